@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AccessibleLines turns section content into plain lines for screen
+// readers: no colors or glyphs, and each line says what it is, e.g.
+// "Checkbox 3 trên 7, chưa xong: ...". Blank lines and rules are dropped.
+func AccessibleLines(content string) []string {
+	lines := strings.Split(content, "\n")
+	total := 0
+	for _, line := range lines {
+		if isCheckbox(line) {
+			total++
+		}
+	}
+
+	var out []string
+	n := 0
+	inCode := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			if inCode {
+				out = append(out, "Hết code block.")
+			} else {
+				out = append(out, strings.TrimSpace("Code block "+strings.TrimLeft(trimmed, "`~"))+":")
+			}
+			inCode = !inCode
+		case inCode:
+			out = append(out, line)
+		case trimmed == "" || trimmed == "---":
+		case isCheckbox(line):
+			n++
+			state := "chưa xong"
+			switch checkboxState(line) {
+			case stateDone:
+				state = "đã xong"
+			case stateInProgress:
+				state = "đang làm"
+			case stateWontDo:
+				state = "không làm"
+			}
+			out = append(out, fmt.Sprintf("Checkbox %d trên %d, %s: %s", n, total, state, speechText(checkboxText(line))))
+		case strings.HasPrefix(trimmed, "#"):
+			out = append(out, "Tiêu đề: "+speechText(strings.TrimLeft(trimmed, "# ")))
+		case strings.HasPrefix(trimmed, ">"):
+			out = append(out, "Trích dẫn: "+speechText(strings.TrimLeft(trimmed, "> ")))
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			out = append(out, "Mục: "+speechText(trimmed[2:]))
+		case strings.HasPrefix(trimmed, "|"):
+			if strings.Contains(trimmed, "---") {
+				continue
+			}
+			var cells []string
+			for _, cell := range strings.Split(strings.Trim(trimmed, "|"), "|") {
+				cells = append(cells, speechText(cell))
+			}
+			out = append(out, "Hàng bảng: "+strings.Join(cells, "; "))
+		default:
+			out = append(out, speechText(trimmed))
+		}
+	}
+	return out
+}
+
+// accessibleSection describes a section in one line: position, level,
+// title and checkbox progress.
+func (a *App) accessibleSection(idx int) string {
+	sec := &a.Sections[idx]
+	line := fmt.Sprintf("Section %d trên %d, cấp %d: %s.", idx+1, len(a.Sections), sec.Level, sec.Title)
+	if checked, total := a.GetProgress(idx); total > 0 {
+		line += fmt.Sprintf(" %d trên %d checkbox đã xong.", checked, total)
+	}
+	if a.IsSkipped(idx) {
+		line += " Đã bỏ qua."
+	}
+	if a.Bookmarks[sec.Title] {
+		line += " Có bookmark."
+	}
+	return line
+}
+
+// renderAccessible prints the current section as linear text, without
+// clearing the screen, so screen readers read each view as new output.
+func (r *Renderer) renderAccessible(sec *Section) {
+	a := r.App
+	fmt.Println()
+	fmt.Println(a.accessibleSection(a.CurrentIdx))
+
+	if ancestors := a.Ancestors(a.CurrentIdx); len(ancestors) > 0 {
+		titles := make([]string, len(ancestors))
+		for i, idx := range ancestors {
+			titles[i] = a.Sections[idx].Title
+		}
+		fmt.Printf("Thuộc: %s.\n", strings.Join(titles, ", "))
+	}
+	for _, c := range a.Reviews.CommentsFor(sec.Title) {
+		fmt.Printf("Nhận xét của %s (%s): %s\n", c.Author, c.Created.Format("2006-01-02"), c.Text)
+	}
+
+	lines := append(AccessibleLines(sec.Content()), a.accessibleChildSummary(a.CurrentIdx)...)
+	start := min(r.ScrollOffset, max(0, len(lines)-1))
+	end := min(start+r.PageSize, len(lines))
+	for _, line := range lines[start:end] {
+		fmt.Println(line)
+	}
+	if len(lines) > r.PageSize {
+		fmt.Printf("Dòng %d đến %d trên %d.\n", start+1, end, len(lines))
+	}
+
+	if msg, ok := a.Status.Current(time.Now()); ok {
+		fmt.Printf("Thông báo: %s\n", msg.Text)
+	}
+	fmt.Println("Phím: j, k cuộn; n, p section; t mục lục; x tick; a ghi chú; ? trợ giúp; Q thoát.")
+}
+
+// handleAccessibleTOC is the table of contents in accessible mode: each
+// key press prints the selected entry as one line.
+func handleAccessibleTOC() {
+	idx := app.CurrentIdx
+	fmt.Println("\nMục lục. j, k: di chuyển; Enter: chọn; q: đóng.")
+	for {
+		fmt.Println(app.accessibleSection(idx))
+
+		switch readKey() {
+		case "j", "Down":
+			idx = min(idx+1, len(app.Sections)-1)
+		case "k", "Up":
+			idx = max(idx-1, 0)
+		case "g":
+			idx = 0
+		case "G":
+			idx = len(app.Sections) - 1
+		case "Enter":
+			app.GotoSection(idx)
+			return
+		case "q", "Q", "Esc":
+			return
+		}
+	}
+}
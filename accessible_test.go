@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAccessibleLines(t *testing.T) {
+	content := "Intro with **bold**.\n\n- [ ] First\n- [x] Second `done`\n- Bullet\n\n---\n> **Ghi chú [2024-01-02 15:04]:** hi\n| A | B |\n|---|---|\n| 1 | 2 |\n```bash\nls -la\n```\n"
+	want := []string{
+		"Intro with bold.",
+		"Checkbox 1 trên 2, chưa xong: First",
+		"Checkbox 2 trên 2, đã xong: Second done",
+		"Mục: Bullet",
+		"Trích dẫn: Ghi chú [2024-01-02 15:04]: hi",
+		"Hàng bảng: A; B",
+		"Hàng bảng: 1; 2",
+		"Code block bash:",
+		"ls -la",
+		"Hết code block.",
+	}
+	if got := AccessibleLines(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("AccessibleLines =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestAccessibleSection(t *testing.T) {
+	app := createTestApp()
+	app.Bookmarks = map[string]bool{"Chapter 1: Basics": true}
+
+	want := "Section 3 trên 6, cấp 3: Chapter 1: Basics. 1 trên 3 checkbox đã xong. Có bookmark."
+	if got := app.accessibleSection(2); got != want {
+		t.Errorf("accessibleSection = %q, want %q", got, want)
+	}
+}
+
+func TestAccessibleScrollBounds(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 2
+	r := NewRenderer(app)
+	r.Accessible = true
+	r.PageSize = 2
+
+	// 3 checkboxes and 1 text line
+	if got := r.maxScrollOffset(); got != 2 {
+		t.Errorf("maxScrollOffset = %d, want 2", got)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultAIBaseURL is used when only OPENAI_API_KEY is set.
+const defaultAIBaseURL = "https://api.openai.com/v1"
+
+// defaultAIModel is used when OPENAI_MODEL is not set.
+const defaultAIModel = "gpt-4o-mini"
+
+// errAINotConfigured is returned when no AI endpoint is configured.
+var errAINotConfigured = errors.New("AI chưa được cấu hình (đặt OPENAI_API_KEY, hoặc OPENAI_BASE_URL cho server tương thích OpenAI)")
+
+// AIClient calls an OpenAI-compatible chat completions endpoint.
+type AIClient struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	HTTP    *http.Client
+}
+
+// newAIClient reads OPENAI_BASE_URL, OPENAI_API_KEY and OPENAI_MODEL.
+// Local servers (Ollama, llama.cpp) need only the base URL.
+func newAIClient() (*AIClient, error) {
+	c := &AIClient{
+		BaseURL: strings.TrimSuffix(os.Getenv("OPENAI_BASE_URL"), "/"),
+		APIKey:  os.Getenv("OPENAI_API_KEY"),
+		Model:   os.Getenv("OPENAI_MODEL"),
+		HTTP:    &http.Client{Timeout: 2 * time.Minute},
+	}
+	if c.BaseURL == "" && c.APIKey == "" {
+		return nil, errAINotConfigured
+	}
+	if c.BaseURL == "" {
+		c.BaseURL = defaultAIBaseURL
+	}
+	if c.Model == "" {
+		c.Model = defaultAIModel
+	}
+	return c, nil
+}
+
+// Host returns the host content is sent to, for the confirmation prompt.
+func (c *AIClient) Host() string {
+	if u, err := url.Parse(c.BaseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return c.BaseURL
+}
+
+// Complete sends a system and a user message and returns the reply.
+func (c *AIClient) Complete(system, user string) (string, error) {
+	body := map[string]any{
+		"model": c.Model,
+		"messages": []map[string]string{
+			{"role": "system", "content": system},
+			{"role": "user", "content": user},
+		},
+	}
+	headers := map[string]string{}
+	if c.APIKey != "" {
+		headers["Authorization"] = "Bearer " + c.APIKey
+	}
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	logger.Debug("ai request", "host", c.Host(), "model", c.Model)
+	if err := postJSON(c.HTTP, c.BaseURL+"/chat/completions", headers, body, &resp); err != nil {
+		logger.Error("ai request", "host", c.Host(), "err", err)
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("%s: empty response", c.BaseURL)
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+// summarizePrompt asks for a study summary of a section.
+const summarizePrompt = `You summarize a section of an SRE learning path for the learner's notes.
+Answer in the language of the section, in markdown: 3-6 short bullet points with the key ideas, commands and pitfalls. No introduction.`
+
+// questionsPrompt asks for practice questions as JSON.
+const questionsPrompt = `You write practice questions about a section of an SRE learning path.
+Answer in the language of the section with only a JSON array of 3-5 objects: [{"question": "...", "answer": "..."}].
+Questions test understanding and hands-on skills; answers are 1-3 sentences.`
+
+// aiSectionText is the text of a section sent to the model.
+func aiSectionText(sec *Section) string {
+	return sectionMarkdown(sec)
+}
+
+// Summarize returns a markdown summary of a section.
+func (c *AIClient) Summarize(sec *Section) (string, error) {
+	return c.Complete(summarizePrompt, aiSectionText(sec))
+}
+
+// GenerateQuestions returns practice questions about a section.
+func (c *AIClient) GenerateQuestions(sec *Section) ([]QuizQuestion, error) {
+	reply, err := c.Complete(questionsPrompt, aiSectionText(sec))
+	if err != nil {
+		return nil, err
+	}
+	return parseQuestions(reply)
+}
+
+// parseQuestions reads the JSON array of questions from a model reply,
+// ignoring text or code fences around it.
+func parseQuestions(reply string) ([]QuizQuestion, error) {
+	start, end := strings.Index(reply, "["), strings.LastIndex(reply, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON array in the reply: %s", truncateEllipsis(reply, 80))
+	}
+	var raw []struct {
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("invalid questions: %w", err)
+	}
+	var questions []QuizQuestion
+	for _, q := range raw {
+		if strings.TrimSpace(q.Question) != "" {
+			questions = append(questions, QuizQuestion{Question: strings.TrimSpace(q.Question), Answer: strings.TrimSpace(q.Answer)})
+		}
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("the reply has no questions")
+	}
+	return questions, nil
+}
+
+// confirmSend asks before the section leaves the machine.
+func confirmSend(reader *bufio.Reader, c *AIClient, sec *Section) bool {
+	fmt.Printf("\n%sGửi section \"%s\" (%d ký tự) tới %s (%s)? (y/N): %s",
+		Yellow, sec.Title, len([]rune(aiSectionText(sec))), c.Host(), c.Model, Reset)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// handleAI shows the AI actions for the current section. Nothing is sent
+// until an action is chosen and confirmed.
+func handleAI() {
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	reader := bufio.NewReader(keyboard)
+	sec := app.GetCurrentSection()
+
+	ClearScreen()
+	fmt.Printf("%s🤖 AI - %s%s\n", Bold+Cyan, sec.Title, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	fmt.Println()
+	if !app.ReadOnly {
+		fmt.Printf("  %ss%s - Tóm tắt section thành ghi chú\n", Cyan, Reset)
+	}
+	fmt.Printf("  %sg%s - Tạo câu hỏi ôn tập (thêm vào quiz)\n", Cyan, Reset)
+	fmt.Printf("  %sr%s - Làm quiz section này (không cần AI)\n", Cyan, Reset)
+	fmt.Printf("  %sq%s - Quay lại\n", Cyan, Reset)
+	fmt.Printf("\nLựa chọn: ")
+	choice, _ := reader.ReadString('\n')
+	choice = strings.TrimSpace(strings.ToLower(choice))
+
+	if choice == "r" {
+		terminal.SetRawMode(true)
+		runQuiz(app.CurrentIdx)
+		return
+	}
+	if (choice != "s" || app.ReadOnly) && choice != "g" {
+		return
+	}
+
+	client, err := newAIClient()
+	if err != nil {
+		notify(SeverityWarning, "%v", err)
+		return
+	}
+	if !confirmSend(reader, client, sec) {
+		return
+	}
+	fmt.Printf("%sĐang chờ %s...%s\n", Dim, client.Host(), Reset)
+
+	switch choice {
+	case "s":
+		summary, err := client.Summarize(sec)
+		if err != nil {
+			notify(SeverityError, "Lỗi AI: %v", err)
+			return
+		}
+		saveNote("🤖 Tóm tắt (" + client.Model + ")\n\n" + summary)
+	case "g":
+		questions, err := client.GenerateQuestions(sec)
+		if err != nil {
+			notify(SeverityError, "Lỗi AI: %v", err)
+			return
+		}
+		if err := app.AddQuizQuestions(app.CurrentIdx, questions); err != nil {
+			notify(SeverityError, "Lỗi lưu quiz: %v", err)
+			return
+		}
+		notify(SeveritySuccess, "Đã thêm %d câu hỏi vào quiz (r trong menu AI để làm)", len(questions))
+	}
+}
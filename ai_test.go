@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewAIClientNotConfigured(t *testing.T) {
+	t.Setenv("OPENAI_BASE_URL", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	if _, err := newAIClient(); err != errAINotConfigured {
+		t.Errorf("err = %v, want errAINotConfigured", err)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	c, err := newAIClient()
+	if err != nil || c.BaseURL != defaultAIBaseURL || c.Model != defaultAIModel || c.Host() != "api.openai.com" {
+		t.Errorf("newAIClient = %+v, %v", c, err)
+	}
+}
+
+func TestAIGenerateQuestions(t *testing.T) {
+	var request struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		reply := "Đây là câu hỏi:\n```json\n[{\"question\": \"SLO là gì?\", \"answer\": \"Mục tiêu cho SLI.\"}, {\"question\": \" \"}]\n```"
+		json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": map[string]string{"content": reply}}}})
+	}))
+	defer srv.Close()
+
+	// A local server needs no API key
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("OPENAI_BASE_URL", srv.URL+"/v1/")
+	t.Setenv("OPENAI_MODEL", "llama3")
+	client, err := newAIClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := createTestApp()
+	questions, err := client.GenerateQuestions(&app.Sections[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(questions) != 1 || questions[0].Question != "SLO là gì?" || questions[0].Answer != "Mục tiêu cho SLI." {
+		t.Errorf("questions = %+v", questions)
+	}
+	if request.Model != "llama3" || len(request.Messages) != 2 || !strings.Contains(request.Messages[1].Content, "### Chapter 1: Basics\n") {
+		t.Errorf("request = %+v", request)
+	}
+}
+
+func TestParseQuestionsInvalid(t *testing.T) {
+	for _, reply := range []string{"Xin lỗi, tôi không thể.", "[]", `[{"question": 1}]`} {
+		if _, err := parseQuestions(reply); err == nil {
+			t.Errorf("parseQuestions(%q) should fail", reply)
+		}
+	}
+}
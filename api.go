@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APISection is a section as returned by the JSON API.
+type APISection struct {
+	Number  int    `json:"number"`
+	ID      string `json:"id,omitempty"`
+	Title   string `json:"title"`
+	Level   int    `json:"level"`
+	Checked int    `json:"checked"`
+	Total   int    `json:"total"`
+	Percent int    `json:"percent"`
+	Skipped bool   `json:"skipped"`
+	// Version must be sent back with edits (optimistic locking)
+	Version string    `json:"version"`
+	Items   []APIItem `json:"items,omitempty"`
+	Notes   []string  `json:"notes,omitempty"`
+}
+
+// APIItem is a checklist item of a section.
+type APIItem struct {
+	// Line is the content line, used to toggle the item
+	Line    int    `json:"line"`
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+	// State is "todo", "in_progress", "done" or "wont_do"
+	State string `json:"state"`
+}
+
+// APIProgress is the overall progress of the document.
+type APIProgress struct {
+	Checked       int        `json:"checked"`
+	Total         int        `json:"total"`
+	Percent       int        `json:"percent"`
+	Sections      int        `json:"sections"`
+	CurrentTitle  string     `json:"current_section"`
+	LastCompleted *time.Time `json:"last_completed,omitempty"`
+}
+
+// percent returns checked/total as a whole percentage.
+func percent(checked, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return checked * 100 / total
+}
+
+// apiSection describes section idx, with its items and notes if detail
+// is set.
+func (a *App) apiSection(idx int, detail bool) APISection {
+	sec := &a.Sections[idx]
+	checked, total := a.GetProgress(idx)
+	s := APISection{
+		Number:  idx + 1,
+		ID:      sec.ID,
+		Title:   sec.Title,
+		Level:   sec.Level,
+		Checked: checked,
+		Total:   total,
+		Percent: percent(checked, total),
+		Skipped: a.IsSkipped(idx),
+		Version: sectionVersion(sec),
+	}
+	if detail {
+		for _, l := range webLines(sec.Content()) {
+			if l.Kind == "checkbox" {
+				s.Items = append(s.Items, APIItem{Line: l.Index, Text: l.Text, Checked: l.Checked, State: l.State})
+			}
+		}
+		s.Notes = extractNotes(sec.Content())
+	}
+	return s
+}
+
+// apiProgress summarizes the progress of the document.
+func (a *App) apiProgress() APIProgress {
+	checked, total := a.GetTotalProgress()
+	p := APIProgress{Checked: checked, Total: total, Percent: percent(checked, total), Sections: len(a.Sections)}
+	if sec := a.GetCurrentSection(); sec != nil {
+		p.CurrentTitle = sec.Title
+	}
+	for i := len(a.History) - 1; i >= 0; i-- {
+		if a.History[i].Checked {
+			p.LastCompleted = &a.History[i].Time
+			break
+		}
+	}
+	return p
+}
+
+// apiSectionIndex resolves a section reference: its number or anchor ID.
+func (a *App) apiSectionIndex(ref string) int {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n >= 1 && n <= len(a.Sections) {
+			return n - 1
+		}
+		return -1
+	}
+	for i, sec := range a.Sections {
+		if sec.ID != "" && sec.ID == ref {
+			return i
+		}
+	}
+	return -1
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// writeJSONError writes {"error": msg}.
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// handleAPIProgress serves GET /api/progress.
+func (s *Server) handleAPIProgress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	s.app.LoadState()
+	writeJSON(w, http.StatusOK, s.app.apiProgress())
+}
+
+// handleAPISections serves
+//
+//	GET  /api/sections                  all sections (without items)
+//	GET  /api/sections/{ref}            one section with items and notes
+//	POST /api/sections/{ref}/toggle     {"line": 4, "version": "..."}
+//	POST /api/sections/{ref}/notes      {"text": "...", "version": "..."}
+//
+// where ref is the section number or its anchor ID. The version is
+// optional; when given, the edit is refused with 409 if the section
+// changed since it was read.
+func (s *Server) handleAPISections(w http.ResponseWriter, r *http.Request) {
+	s.app.LoadState()
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/sections"), "/")
+	if rest == "" {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		sections := make([]APISection, len(s.app.Sections))
+		for i := range s.app.Sections {
+			sections[i] = s.app.apiSection(i, false)
+		}
+		writeJSON(w, http.StatusOK, sections)
+		return
+	}
+
+	ref, action, _ := strings.Cut(rest, "/")
+	idx := s.app.apiSectionIndex(ref)
+	if idx < 0 {
+		writeJSONError(w, http.StatusNotFound, "section not found: "+ref)
+		return
+	}
+
+	if action == "" && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, s.app.apiSection(idx, true))
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var body struct {
+		Line    *int   `json:"line"`
+		Text    string `json:"text"`
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&body); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if body.Version == "" {
+		body.Version = sectionVersion(&s.app.Sections[idx])
+	}
+
+	var edit func() error
+	switch action {
+	case "toggle":
+		if body.Line == nil {
+			writeJSONError(w, http.StatusBadRequest, `missing "line"`)
+			return
+		}
+		edit = func() error { return s.app.toggleLine(idx, *body.Line) }
+	case "notes":
+		edit = func() error { return s.app.addNote(idx, body.Text) }
+	default:
+		writeJSONError(w, http.StatusNotFound, "unknown action: "+action)
+		return
+	}
+
+	if err := s.editSection(idx, body.Version, edit); err != nil {
+		writeJSONError(w, editStatus(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.app.apiSection(idx, true))
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// serveJSON performs a JSON request against s and decodes the response.
+func serveJSON(t *testing.T, s *Server, method, target, body string, v any) int {
+	t.Helper()
+	req := newLocalRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s %s: invalid JSON %q: %v", method, target, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+// ============================================================================
+// JSON API Tests
+// ============================================================================
+
+func TestAPIProgressAndSections(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	var progress APIProgress
+	if code := serveJSON(t, s, http.MethodGet, "/api/progress", "", &progress); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if progress.Checked != 3 || progress.Total != 6 || progress.Percent != 50 {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+
+	var sections []APISection
+	serveJSON(t, s, http.MethodGet, "/api/sections", "", &sections)
+	if len(sections) != 6 || sections[2].Title != "Chapter 1: Basics" || sections[2].Items != nil {
+		t.Errorf("Unexpected section list %+v", sections)
+	}
+
+	var sec APISection
+	serveJSON(t, s, http.MethodGet, "/api/sections/3", "", &sec)
+	if len(sec.Items) != 3 || sec.Items[1].Text != "Task two completed" || !sec.Items[1].Checked {
+		t.Errorf("Unexpected section detail %+v", sec)
+	}
+
+	var apiErr map[string]string
+	if code := serveJSON(t, s, http.MethodGet, "/api/sections/99", "", &apiErr); code != http.StatusNotFound || apiErr["error"] == "" {
+		t.Errorf("Expected 404 with an error, got %d %v", code, apiErr)
+	}
+}
+
+func TestAPIToggleAndNotes(t *testing.T) {
+	s, a := newTestServer(t)
+
+	var sec APISection
+	serveJSON(t, s, http.MethodGet, "/api/sections/3", "", &sec)
+	line := sec.Items[0].Line
+
+	var updated APISection
+	body := `{"line": ` + strconv.Itoa(line) + `, "version": "` + sec.Version + `"}`
+	if code := serveJSON(t, s, http.MethodPost, "/api/sections/3/toggle", body, &updated); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if !updated.Items[0].Checked || updated.Version == sec.Version {
+		t.Errorf("Expected item checked with a new version, got %+v", updated)
+	}
+
+	// The old version is stale now
+	var apiErr map[string]string
+	if code := serveJSON(t, s, http.MethodPost, "/api/sections/3/notes", `{"text": "x", "version": "`+sec.Version+`"}`, &apiErr); code != http.StatusConflict {
+		t.Errorf("Expected 409 for a stale version, got %d", code)
+	}
+
+	if code := serveJSON(t, s, http.MethodPost, "/api/sections/3/notes", `{"text": "đã xong lab"}`, &updated); code != http.StatusOK {
+		t.Fatalf("Expected 200 for a note without version, got %d", code)
+	}
+	if len(updated.Notes) != 1 || !strings.Contains(updated.Notes[0], "đã xong lab") {
+		t.Errorf("Expected the note returned, got %v", updated.Notes)
+	}
+	data, _ := os.ReadFile(a.FilePath)
+	if !strings.Contains(string(data), "- [x] Task one") || !strings.Contains(string(data), "đã xong lab") {
+		t.Error("Expected edits saved to the file")
+	}
+
+	if code := serveJSON(t, s, http.MethodPost, "/api/sections/3/toggle", `{"line": 0}`, &apiErr); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a line without checkbox, got %d", code)
+	}
+}
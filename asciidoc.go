@@ -0,0 +1,191 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// asciiDocHeaderRegex matches an AsciiDoc section title: "= Title"
+// through "====== Title".
+var asciiDocHeaderRegex = regexp.MustCompile(`^(={1,6})\s+(.+?)\s*$`)
+
+// asciiDocAttributeRegex matches a block attribute line such as
+// "[source,bash]", "[NOTE]" or an "[[anchor]]".
+var asciiDocAttributeRegex = regexp.MustCompile(`^\[.*\]$`)
+
+// asciiDocSourceRegex extracts the language of a "[source,lang]" line.
+var asciiDocSourceRegex = regexp.MustCompile(`^\[source(?:,\s*([\w+#.-]+))?`)
+
+// asciiDocAdmonitionRegex matches a paragraph admonition: "NOTE: text".
+var asciiDocAdmonitionRegex = regexp.MustCompile(`^(NOTE|TIP|IMPORTANT|WARNING|CAUTION):\s+(.*)$`)
+
+// asciiDocListRegex matches nested list markers: "** item", ".. step".
+var asciiDocListRegex = regexp.MustCompile(`^(\*{2,5}|\.{1,5}) (.*)$`)
+
+// asciiDocBoldRegex matches constrained *bold* text, which markdown
+// would read as italic.
+var asciiDocBoldRegex = regexp.MustCompile(`(^|[^\w*])\*([^*\s](?:[^*]*[^*\s])?)\*($|[^\w*])`)
+
+// asciiDocURLRegex matches "https://url[text]" and "link:url[text]".
+var asciiDocURLRegex = regexp.MustCompile(`(?:link:)?(https?://[^\s\[]+|link:[^\s\[]+)\[([^\]]*)\]`)
+
+// asciiDocXrefRegex matches a cross reference: "<<id>>" or "<<id,text>>".
+var asciiDocXrefRegex = regexp.MustCompile(`<<([\w.:-]+)(?:,\s*([^>]+))?>>`)
+
+// isAsciiDocPath reports whether a file is read as AsciiDoc, by extension.
+func isAsciiDocPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".adoc", ".asciidoc", ".asc":
+		return true
+	}
+	return false
+}
+
+// IsAsciiDoc reports whether the loaded document is AsciiDoc.
+func (a *App) IsAsciiDoc() bool {
+	return isAsciiDocPath(a.FilePath)
+}
+
+// scanLines classifies lines with the scanner of the document's format.
+func (a *App) scanLines(lines []string) []MarkdownLine {
+	if a.IsAsciiDoc() {
+		return ScanAsciiDocLines(lines)
+	}
+	return ScanLines(lines)
+}
+
+// contentLines splits section content into the lines to render: AsciiDoc
+// is converted to the equivalent markdown line by line, then the content
+// transformer plugins are applied.
+func (a *App) contentLines(content string) []string {
+	lines := strings.Split(content, "\n")
+	if a.IsAsciiDoc() {
+		lines = asciiDocDisplay(lines)
+	}
+	return transformLines(plugins, lines)
+}
+
+// asciiDocDelimiter reports whether a trimmed line delimits a listing
+// (----) or literal (....) block.
+func asciiDocDelimiter(trimmed string) bool {
+	if len(trimmed) < 4 || (trimmed[0] != '-' && trimmed[0] != '.') {
+		return false
+	}
+	return strings.Count(trimmed, trimmed[:1]) == len(trimmed)
+}
+
+// ScanAsciiDocLines classifies AsciiDoc lines into the same kinds as
+// ScanLines: "== Title" is a level-2 header and listing or literal blocks
+// are fenced code, so "----" never reads as a setext underline and
+// "== x" inside a block never starts a section. The info string of a
+// block comes from a "[source,lang]" line right above it.
+func ScanAsciiDocLines(lines []string) []MarkdownLine {
+	result := make([]MarkdownLine, len(lines))
+	fence := ""
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if trimmed == fence {
+				result[i] = MarkdownLine{Kind: LineFenceClose}
+				fence = ""
+			} else {
+				result[i] = MarkdownLine{Kind: LineCode}
+			}
+			continue
+		}
+
+		switch {
+		case asciiDocDelimiter(trimmed):
+			fence = trimmed
+			lang := ""
+			if i > 0 {
+				if m := asciiDocSourceRegex.FindStringSubmatch(strings.TrimSpace(lines[i-1])); m != nil {
+					lang = m[1]
+				}
+			}
+			result[i] = MarkdownLine{Kind: LineFenceOpen, Text: lang}
+		case trimmed == "":
+			result[i] = MarkdownLine{Kind: LineBlank}
+		default:
+			if m := asciiDocHeaderRegex.FindStringSubmatch(line); m != nil {
+				result[i] = MarkdownLine{Kind: LineHeader, Level: len(m[1]), Text: m[2]}
+			} else {
+				result[i] = MarkdownLine{Kind: LineText}
+			}
+		}
+	}
+	return result
+}
+
+// asciiDocDisplay converts AsciiDoc lines to markdown for display, one
+// output line per input line so line numbers (and change markers) stay
+// aligned: blocks become fences, block attributes blank lines, and the
+// inline syntax that differs (bold, links, cross references, nested
+// lists, admonitions and block titles) its markdown equivalent.
+func asciiDocDisplay(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, ml := range ScanAsciiDocLines(lines) {
+		line := lines[i]
+		switch ml.Kind {
+		case LineFenceOpen:
+			out[i] = "```" + ml.Text
+		case LineFenceClose:
+			out[i] = "```"
+		case LineHeader:
+			out[i] = strings.Repeat("#", ml.Level) + " " + asciiDocInline(ml.Text)
+		case LineText:
+			out[i] = asciiDocText(line)
+		default:
+			out[i] = line
+		}
+	}
+	return out
+}
+
+// asciiDocText converts one AsciiDoc text line to markdown.
+func asciiDocText(line string) string {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(trimmed, "//"):
+		return ""
+	case asciiDocAttributeRegex.MatchString(trimmed):
+		return ""
+	case strings.HasPrefix(trimmed, ".") && len(trimmed) > 1 && trimmed[1] != '.' && trimmed[1] != ' ':
+		return "**" + asciiDocInline(trimmed[1:]) + "**"
+	}
+	if m := asciiDocAdmonitionRegex.FindStringSubmatch(trimmed); m != nil {
+		return "> **" + m[1] + ":** " + asciiDocInline(m[2])
+	}
+	if m := asciiDocListRegex.FindStringSubmatch(trimmed); m != nil {
+		marker := "* "
+		if m[1][0] == '.' {
+			marker = "1. "
+		}
+		return strings.Repeat("  ", len(m[1])-1) + marker + asciiDocInline(m[2])
+	}
+	return asciiDocInline(line)
+}
+
+// asciiDocInline converts the inline AsciiDoc syntax that reads
+// differently in markdown.
+func asciiDocInline(text string) string {
+	text = asciiDocURLRegex.ReplaceAllStringFunc(text, func(s string) string {
+		m := asciiDocURLRegex.FindStringSubmatch(s)
+		url := strings.TrimPrefix(m[1], "link:")
+		if m[2] == "" {
+			return url
+		}
+		return "[" + m[2] + "](" + url + ")"
+	})
+	text = asciiDocXrefRegex.ReplaceAllStringFunc(text, func(s string) string {
+		m := asciiDocXrefRegex.FindStringSubmatch(s)
+		if m[2] == "" {
+			return "[" + m[1] + "](#" + m[1] + ")"
+		}
+		return "[" + m[2] + "](#" + m[1] + ")"
+	})
+	return asciiDocBoldRegex.ReplaceAllString(text, "$1**$2**$3")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleAsciiDoc = `= SRE Path
+
+== Linux
+
+* [ ] Learn *systemd* units
+** [x] Read link:https://systemd.io[the docs]
+* [*] Write a unit
+
+[source,bash]
+----
+== not a header
+systemctl status
+----
+
+NOTE: See <<networking,Networking>>.
+
+== Networking
+
+.Checklist
+* [ ] TCP handshake`
+
+func TestScanAsciiDocLines(t *testing.T) {
+	lines := strings.Split(sampleAsciiDoc, "\n")
+	scanned := ScanAsciiDocLines(lines)
+	var headers []string
+	for _, ml := range scanned {
+		if ml.Kind == LineHeader {
+			headers = append(headers, strings.Repeat("=", ml.Level)+" "+ml.Text)
+		}
+	}
+	if strings.Join(headers, "|") != "= SRE Path|== Linux|== Networking" {
+		t.Errorf("headers = %q", headers)
+	}
+	if scanned[9].Kind != LineFenceOpen || scanned[9].Text != "bash" || scanned[12].Kind != LineFenceClose {
+		t.Errorf("listing block = %+v", scanned[8:13])
+	}
+}
+
+func TestAsciiDocSections(t *testing.T) {
+	app := NewApp()
+	app.FilePath = "path.adoc"
+	app.FileLines = strings.Split(sampleAsciiDoc, "\n")
+	app.ParseSections()
+
+	if len(app.Sections) != 3 || app.Sections[1].Title != "Linux" || app.Sections[1].Level != 2 {
+		t.Fatalf("Sections = %+v", app.Sections)
+	}
+	if counts := app.CountCheckboxes(1); counts.Done != 2 || counts.Open != 1 {
+		t.Errorf("CountCheckboxes = %+v", counts)
+	}
+
+	// Toggling writes the section back with its AsciiDoc title
+	app.CurrentIdx = 1
+	app.ToggleCheckbox(1)
+	app.UpdateFileSection(1)
+	if got := app.FileLines[2]; got != "== Linux" {
+		t.Errorf("header = %q", got)
+	}
+	if got := app.FileLines[4]; got != "* [x] Learn *systemd* units" {
+		t.Errorf("item = %q", got)
+	}
+
+	if err := app.InsertSection("Storage", 2); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(strings.Join(app.FileLines, "\n"), "\n== Storage\n") {
+		t.Errorf("FileLines = %q", app.FileLines)
+	}
+}
+
+func TestAsciiDocDisplay(t *testing.T) {
+	lines := asciiDocDisplay(strings.Split(sampleAsciiDoc, "\n"))
+	for i, want := range map[int]string{
+		4:  "* [ ] Learn **systemd** units",
+		5:  "  * [x] Read [the docs](https://systemd.io)",
+		8:  "",
+		9:  "```bash",
+		10: "== not a header",
+		12: "```",
+		14: "> **NOTE:** See [Networking](#networking).",
+		18: "**Checklist**",
+	} {
+		if lines[i] != want {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want)
+		}
+	}
+	if len(lines) != len(strings.Split(sampleAsciiDoc, "\n")) {
+		t.Error("Expected one display line per source line")
+	}
+}
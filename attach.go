@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// attachmentsDirName is the directory next to the document that holds
+// the files attached to notes.
+const attachmentsDirName = "attachments"
+
+// attachmentLinkRegex matches the relative links to attached files:
+// "[plan.txt](attachments/plan.txt)".
+var attachmentLinkRegex = regexp.MustCompile(`\[([^\]]*)\]\((` + attachmentsDirName + `/[^)\s]+)\)`)
+
+// attachmentOpeners are the commands that open a file with its default
+// application, in order of preference.
+var attachmentOpeners = []string{"xdg-open", "open"}
+
+// cleanAttachmentPath turns a path typed or dropped into the terminal into
+// a file path: quotes and shell escapes are removed and ~ is expanded.
+func cleanAttachmentPath(input string) string {
+	path := strings.TrimSpace(input)
+	if len(path) >= 2 && (path[0] == '\'' || path[0] == '"') && path[len(path)-1] == path[0] {
+		path = path[1 : len(path)-1]
+	} else {
+		path = strings.ReplaceAll(path, `\ `, " ")
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+// newAttachment returns a free path for a new attachment named name and
+// its link relative to the document. Spaces in the name are replaced so
+// the markdown link stays valid, and an existing file of the same name
+// gets a numbered suffix instead of being overwritten.
+func (a *App) newAttachment(name string) (path, link string, err error) {
+	dir := filepath.Join(filepath.Dir(a.FilePath), attachmentsDirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", err
+	}
+	name = strings.Join(strings.Fields(name), "-")
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, name)); os.IsNotExist(err) {
+			break
+		}
+		name = fmt.Sprintf("%s-%d%s", stem, i, ext)
+	}
+	return filepath.Join(dir, name), attachmentsDirName + "/" + name, nil
+}
+
+// AttachFile copies src into the attachments directory next to the
+// document and returns its path relative to the document.
+func (a *App) AttachFile(src string) (string, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", src)
+	}
+
+	path, link, err := a.newAttachment(filepath.Base(src))
+	if err != nil {
+		return "", err
+	}
+	if vault != nil {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return "", err
+		}
+		return link, writeSecure(path, data, 0o600)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	return link, nil
+}
+
+// attachmentLink returns the markdown line linking an attached file.
+func attachmentLink(link string) string {
+	return "📎 [" + filepath.Base(link) + "](" + link + ")"
+}
+
+// attachToNote returns note with a link to the attached file added as its
+// last line.
+func attachToNote(note, link string) string {
+	return note + "\n>\n> " + attachmentLink(link)
+}
+
+// noteAttachments returns the relative paths of the files attached to a
+// note.
+func noteAttachments(note string) []string {
+	var paths []string
+	for _, m := range attachmentLinkRegex.FindAllStringSubmatch(note, -1) {
+		paths = append(paths, m[2])
+	}
+	return paths
+}
+
+// replaceNote replaces a note in the content of the current section,
+// keeping its position and timestamp.
+func (a *App) replaceNote(oldNote, newNote string) bool {
+	sec := &a.Sections[a.CurrentIdx]
+	if !strings.Contains(sec.Content(), oldNote) {
+		return false
+	}
+	sec.SetContent(strings.Replace(sec.Content(), oldNote, newNote, 1))
+	return true
+}
+
+// openAttachment opens an attached file with the desktop's default
+// application.
+func (a *App) openAttachment(link string) error {
+	path, err := openSecureAttachment(filepath.Join(filepath.Dir(a.FilePath), filepath.FromSlash(link)))
+	if err != nil {
+		return err
+	}
+	for _, opener := range attachmentOpeners {
+		if _, err := exec.LookPath(opener); err != nil {
+			continue
+		}
+		// The opener returns once the application is started
+		return exec.Command(opener, path).Run()
+	}
+	return fmt.Errorf("không tìm thấy %s", strings.Join(attachmentOpeners, "/"))
+}
+
+// attachFileToNote asks for a note and a file, copies the file into the
+// attachments directory and links it from the note.
+func attachFileToNote(reader *bufio.Reader, notes []string) bool {
+	ClearScreen()
+	fmt.Printf("%s📎 ĐÍNH KÈM FILE%s\n", Bold+Cyan, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	fmt.Println()
+
+	for i, note := range notes {
+		text := strings.ReplaceAll(noteBody(note), "\n", " ")
+		fmt.Printf("  %s%d%s. %s\n", Cyan, i+1, Reset, truncateEllipsis(text, 100))
+	}
+
+	fmt.Printf("\nĐính kèm vào ghi chú số (1-%d) hoặc Enter để hủy: ", len(notes))
+	input, _ := reader.ReadString('\n')
+	idx, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || idx < 1 || idx > len(notes) {
+		return false
+	}
+
+	input, _ = readPrompt(Prompt{Label: "Đường dẫn file (Tab: gợi ý, có thể kéo thả vào terminal): ", History: promptHistories["path"], Complete: completePath})
+	rememberAnswer("path", input)
+	src := cleanAttachmentPath(input)
+	if src == "" {
+		return false
+	}
+
+	link, err := app.AttachFile(src)
+	if err != nil {
+		notify(SeverityError, "Lỗi đính kèm: %v", err)
+		return false
+	}
+	if !app.replaceNote(notes[idx-1], attachToNote(notes[idx-1], link)) {
+		os.Remove(filepath.Join(filepath.Dir(app.FilePath), filepath.FromSlash(link)))
+		notify(SeverityError, "Không tìm thấy ghi chú trong section")
+		return false
+	}
+	app.UpdateFileSection(app.CurrentIdx)
+	if !saveReviewed(reader) {
+		return false
+	}
+	notify(SeveritySuccess, "Đã đính kèm %s", link)
+	return true
+}
+
+// showNoteAttachments lists the files attached to a note below its text
+// and opens the chosen ones until Enter is pressed.
+func showNoteAttachments(note string, reader *bufio.Reader) {
+	links := noteAttachments(note)
+	if len(links) == 0 {
+		fmt.Printf("%s[Enter để quay lại]%s", Dim, Reset)
+		reader.ReadString('\n')
+		return
+	}
+
+	fmt.Printf("%s📎 File đính kèm:%s\n", Yellow, Reset)
+	for i, link := range links {
+		fmt.Printf("  %s%d%s. %s\n", Cyan, i+1, Reset, link)
+	}
+	for {
+		fmt.Printf("\nNhập số để mở file (1-%d) hoặc Enter để quay lại: ", len(links))
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return
+		}
+		n, err := strconv.Atoi(input)
+		if err != nil || n < 1 || n > len(links) {
+			continue
+		}
+		if err := app.openAttachment(links[n-1]); err != nil {
+			fmt.Printf("%s❌ Không mở được %s: %v%s\n", Red, links[n-1], err, Reset)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAttachFile(t *testing.T) {
+	dir := t.TempDir()
+	app := createTestApp()
+	app.FilePath = filepath.Join(dir, "path.md")
+
+	src := filepath.Join(t.TempDir(), "terraform plan.txt")
+	if err := os.WriteFile(src, []byte("+ aws_instance.web"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	link, err := app.AttachFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link != "attachments/terraform-plan.txt" {
+		t.Errorf("link = %q", link)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "attachments", "terraform-plan.txt"))
+	if err != nil || string(data) != "+ aws_instance.web" {
+		t.Errorf("copied file = %q, %v", data, err)
+	}
+
+	// Attaching a file of the same name keeps the first one
+	second, err := app.AttachFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second != "attachments/terraform-plan-2.txt" {
+		t.Errorf("second link = %q", second)
+	}
+
+	if _, err := app.AttachFile(dir); err == nil {
+		t.Error("Expected an error attaching a directory")
+	}
+}
+
+func TestNoteAttachmentsRoundTrip(t *testing.T) {
+	app := createTestApp()
+	app.AddNote("plan của lab 3")
+	note := extractNotes(app.GetCurrentSection().Content())[0]
+
+	if !app.replaceNote(note, attachToNote(note, "attachments/plan.txt")) {
+		t.Fatal("replaceNote did not find the note")
+	}
+	notes := extractNotes(app.GetCurrentSection().Content())
+	if len(notes) != 1 {
+		t.Fatalf("Expected 1 note, got %d: %q", len(notes), notes)
+	}
+	if got := noteAttachments(notes[0]); len(got) != 1 || got[0] != "attachments/plan.txt" {
+		t.Errorf("noteAttachments = %q", got)
+	}
+	if !strings.HasPrefix(noteBody(notes[0]), "plan của lab 3") {
+		t.Errorf("note text lost: %q", noteBody(notes[0]))
+	}
+}
+
+func TestCleanAttachmentPath(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	tests := map[string]string{
+		"  /tmp/a.png\n":         "/tmp/a.png",
+		"'/tmp/my shot.png'":     "/tmp/my shot.png",
+		`/tmp/my\ shot.png`:      "/tmp/my shot.png",
+		"~/Pictures/lab.png":     filepath.Join(home, "Pictures/lab.png"),
+		`"/tmp/quoted path.txt"`: "/tmp/quoted path.txt",
+	}
+	for in, want := range tests {
+		if got := cleanAttachmentPath(in); got != want {
+			t.Errorf("cleanAttachmentPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
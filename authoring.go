@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxHeaderLevel is the deepest markdown header level (######).
+const maxHeaderLevel = 6
+
+// sectionDepth is the deepest header level that starts a section
+// (section_depth); deeper headers render inline in their section.
+func sectionDepth() int {
+	if config.SectionDepth > 0 {
+		return config.SectionDepth
+	}
+	return maxHeaderLevel
+}
+
+// subtreeEnd returns the index of the first section after idx that is
+// not one of its descendants (len(Sections) if the subtree runs to the end).
+func (a *App) subtreeEnd(idx int) int {
+	level := a.Sections[idx].Level
+	end := idx + 1
+	for end < len(a.Sections) && a.Sections[end].Level > level {
+		end++
+	}
+	return end
+}
+
+// sectionStartLine returns the file line where section idx starts, or the
+// end of the file for idx == len(Sections).
+func (a *App) sectionStartLine(idx int) int {
+	if idx >= len(a.Sections) {
+		return len(a.FileLines)
+	}
+	return a.Sections[idx].Line
+}
+
+// replaceLines replaces FileLines[start:end] with lines and re-parses.
+func (a *App) replaceLines(start, end int, lines []string) {
+	newLines := make([]string, 0, len(a.FileLines)-(end-start)+len(lines))
+	newLines = append(newLines, a.FileLines[:start]...)
+	newLines = append(newLines, lines...)
+	newLines = append(newLines, a.FileLines[end:]...)
+	a.FileLines = newLines
+	a.FileContent = strings.Join(a.FileLines, "\n")
+	a.ParseSections()
+}
+
+// sectionAtLine returns the index of the section starting at file line, or -1.
+func (a *App) sectionAtLine(line int) int {
+	for i, sec := range a.Sections {
+		if sec.Line == line {
+			return i
+		}
+	}
+	return -1
+}
+
+// InsertSection adds an empty section after the current one and makes it
+// current. A section at the same or a higher level goes after the current
+// section's children; a deeper one becomes its first child.
+func (a *App) InsertSection(title string, level int) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return fmt.Errorf("title is empty")
+	}
+	if level < 1 || level > sectionDepth() {
+		return fmt.Errorf("level must be between 1 and %d", sectionDepth())
+	}
+
+	at := len(a.FileLines)
+	if sec := a.GetCurrentSection(); sec != nil {
+		next := a.CurrentIdx + 1
+		if level <= sec.Level {
+			next = a.subtreeEnd(a.CurrentIdx)
+		}
+		at = a.sectionStartLine(next)
+	}
+
+	header := Section{Title: title, Level: level, AsciiDoc: a.IsAsciiDoc()}.HeaderLines()
+	a.replaceLines(at, at, append(header, ""))
+	a.CurrentIdx = max(0, a.sectionAtLine(at))
+	return nil
+}
+
+// SplitSection splits section idx at content line contentLine: the lines
+// from there on move into a new section with the same level and the given
+// title, placed right after the first part.
+func (a *App) SplitSection(idx, contentLine int, title string) error {
+	title = strings.TrimSpace(title)
+	if idx < 0 || idx >= len(a.Sections) {
+		return fmt.Errorf("section %d out of range", idx)
+	}
+	if title == "" {
+		return fmt.Errorf("title is empty")
+	}
+
+	sec := a.Sections[idx]
+	lines := strings.Split(sec.Content(), "\n")
+	if contentLine <= 0 || contentLine >= len(lines) {
+		return fmt.Errorf("line must be between 1 and %d", len(lines)-1)
+	}
+	if hasUnclosedFence(strings.Join(lines[:contentLine], "\n")) {
+		return fmt.Errorf("cannot split inside a code block")
+	}
+
+	at := sec.Line + len(sec.HeaderLines()) + contentLine
+	header := Section{Title: title, Level: sec.Level, AsciiDoc: sec.AsciiDoc}.HeaderLines()
+	a.replaceLines(at, at, header)
+	a.CurrentIdx = idx
+	return nil
+}
+
+// MoveSection swaps section idx (with its children) with its previous
+// (delta < 0) or next (delta > 0) sibling under the same parent.
+// Returns false if there is no such sibling.
+func (a *App) MoveSection(idx, delta int) bool {
+	if idx < 0 || idx >= len(a.Sections) || delta == 0 {
+		return false
+	}
+	level := a.Sections[idx].Level
+
+	first, second := idx, -1
+	if delta > 0 {
+		second = a.subtreeEnd(idx)
+		if second >= len(a.Sections) || a.Sections[second].Level != level {
+			return false
+		}
+	} else {
+		prev := idx - 1
+		for prev >= 0 && a.Sections[prev].Level > level {
+			prev--
+		}
+		if prev < 0 || a.Sections[prev].Level != level {
+			return false
+		}
+		first, second = prev, idx
+	}
+
+	start := a.Sections[first].Line
+	mid := a.Sections[second].Line
+	end := a.sectionStartLine(a.subtreeEnd(second))
+
+	swapped := append(append([]string{}, a.FileLines[mid:end]...), a.FileLines[start:mid]...)
+	movedLine := a.Sections[idx].Line
+	a.replaceLines(start, end, swapped)
+
+	// Follow the moved section to its new position
+	if delta > 0 {
+		movedLine += end - mid
+	} else {
+		movedLine -= mid - start
+	}
+	a.CurrentIdx = max(0, a.sectionAtLine(movedLine))
+	return true
+}
+
+// DeleteSection removes section idx together with its children.
+// Returns the number of sections removed.
+func (a *App) DeleteSection(idx int) int {
+	if idx < 0 || idx >= len(a.Sections) {
+		return 0
+	}
+
+	end := a.subtreeEnd(idx)
+	removed := end - idx
+	for _, sec := range a.Sections[idx:end] {
+		delete(a.Skipped, sec.Title)
+	}
+
+	a.replaceLines(a.Sections[idx].Line, a.sectionStartLine(end), nil)
+	a.CurrentIdx = max(0, min(idx, len(a.Sections)-1))
+	return removed
+}
+
+// handleStructure shows the document-authoring menu for the current section.
+func handleStructure() {
+	sec := app.GetCurrentSection()
+	if sec == nil {
+		return
+	}
+
+	ClearScreen()
+	fmt.Printf("%s🧱 CẤU TRÚC - %s%s\n", Bold+Cyan, sec.Title, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	fmt.Printf("\n  %sn%s - Thêm section mới sau section này\n", Cyan, Reset)
+	fmt.Printf("  %ss%s - Tách section tại dòng đang xem\n", Cyan, Reset)
+	fmt.Printf("  %sk%s - Chuyển lên trên (cùng cấp)\n", Cyan, Reset)
+	fmt.Printf("  %sj%s - Chuyển xuống dưới (cùng cấp)\n", Cyan, Reset)
+	fmt.Printf("  %sd%s - Xóa section (kèm section con)\n", Cyan, Reset)
+	if sec.Implicit {
+		fmt.Printf("  %sa%s - Tự chia section theo đoạn văn (tài liệu chưa có header)\n", Cyan, Reset)
+	}
+	fmt.Printf("  %sq%s - Quay lại\n", Cyan, Reset)
+
+	var err error
+	changed := false
+	switch readKey() {
+	case "n":
+		changed, err = promptInsertSection(sec)
+	case "s":
+		changed, err = promptSplitSection(sec)
+	case "k":
+		changed = app.MoveSection(app.CurrentIdx, -1)
+	case "j":
+		changed = app.MoveSection(app.CurrentIdx, 1)
+	case "d":
+		// Saved by the transaction, or rolled back
+		if promptDeleteSection(sec) {
+			notify(SeveritySuccess, "Đã xóa section (z u để hoàn tác)")
+		}
+		return
+	case "a":
+		if !sec.Implicit {
+			return
+		}
+		terminal.SetRawMode(false)
+		defer terminal.SetRawMode(true)
+		if runChange(bufio.NewReader(keyboard), "Tự chia section", "", app.AutoStructure) {
+			renderer.ResetScroll()
+			notify(SeveritySuccess, "Đã chia thành %d section (z u để hoàn tác)", len(app.Sections))
+		}
+		return
+	default:
+		return
+	}
+
+	switch {
+	case err != nil:
+		notify(SeverityError, "%v", err)
+	case !changed:
+		notify(SeverityInfo, "Không có thay đổi")
+	default:
+		if saveDocument() {
+			notify(SeveritySuccess, "Đã cập nhật cấu trúc")
+		}
+		app.SaveState(renderer.PageSize)
+	}
+}
+
+// promptInsertSection asks for the title and level of a new section.
+func promptInsertSection(sec *Section) (bool, error) {
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	inputReader := bufio.NewReader(keyboard)
+
+	fmt.Printf("\n%sTiêu đề (Enter để hủy):%s ", Bold, Reset)
+	title, _ := inputReader.ReadString('\n')
+	if strings.TrimSpace(title) == "" {
+		return false, nil
+	}
+
+	fmt.Printf("%sCấp 1-%d (Enter = %d):%s ", Bold, sectionDepth(), sec.Level, Reset)
+	input, _ := inputReader.ReadString('\n')
+	level := sec.Level
+	if input = strings.TrimSpace(input); input != "" {
+		var err error
+		if level, err = strconv.Atoi(input); err != nil {
+			return false, fmt.Errorf("cấp không hợp lệ: %s", input)
+		}
+	}
+
+	return true, app.InsertSection(title, level)
+}
+
+// promptSplitSection asks for the title of the second part and splits the
+// section at the first visible content line.
+func promptSplitSection(sec *Section) (bool, error) {
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+
+	line := renderer.ScrollOffset
+	lines := strings.Split(sec.Content(), "\n")
+	if line <= 0 || line >= len(lines) {
+		return false, fmt.Errorf("cuộn tới dòng muốn tách (đang ở đầu section)")
+	}
+
+	fmt.Printf("\n%sTách từ dòng:%s %s\n", Dim, Reset, truncateVisible(lines[line], 60))
+	fmt.Printf("%sTiêu đề phần sau (Enter để hủy):%s ", Bold, Reset)
+	title, _ := bufio.NewReader(keyboard).ReadString('\n')
+	if strings.TrimSpace(title) == "" {
+		return false, nil
+	}
+
+	if err := app.SplitSection(app.CurrentIdx, line, title); err != nil {
+		return false, err
+	}
+	renderer.ResetScroll()
+	return true, nil
+}
+
+// continuesBlock reports whether a line opening a block of lines carries
+// on the block above it rather than starting a topic: a list item, a
+// quote, a table row or indented text.
+func continuesBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, prefix := range []string{"- ", "* ", "+ ", ">", "|"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return trimmed != line || numberedListRegex.MatchString(line)
+}
+
+// AutoStructure gives a document without headers sections: a level-1
+// header named after the file, then a level-2 header before every block
+// of lines that follows a blank line, titled after its first line. Lists,
+// quotes and tables stay in the section of the paragraph above them and
+// code blocks are never split.
+func (a *App) AutoStructure() error {
+	if len(a.Sections) != 1 || !a.Sections[0].Implicit {
+		return fmt.Errorf("the document already has headers")
+	}
+	asciiDoc := a.IsAsciiDoc()
+	lines := append(Section{Title: a.Sections[0].Title, Level: 1, AsciiDoc: asciiDoc}.HeaderLines(), "")
+	blockStart := true
+	for i, ml := range a.scanLines(a.FileLines) {
+		line := a.FileLines[i]
+		if ml.Kind == LineBlank {
+			blockStart = true
+			lines = append(lines, line)
+			continue
+		}
+		if blockStart && ml.Kind == LineText && !continuesBlock(line) {
+			title := truncateEllipsis(strings.Trim(strings.TrimSpace(line), "*_`#=:"), 60)
+			lines = append(lines, Section{Title: title, Level: 2, AsciiDoc: asciiDoc}.HeaderLines()...)
+			lines = append(lines, "")
+		}
+		blockStart = false
+		lines = append(lines, line)
+	}
+	a.replaceLines(0, len(a.FileLines), lines)
+	a.CurrentIdx = 0
+	return nil
+}
+
+// promptDeleteSection deletes the section with its children after
+// previewing and confirming it, and saves the file.
+func promptDeleteSection(sec *Section) bool {
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+
+	children := app.subtreeEnd(app.CurrentIdx) - app.CurrentIdx - 1
+	if !runChange(bufio.NewReader(keyboard), fmt.Sprintf("Xóa \"%s\" và %d section con", sec.Title, children), "", func() error {
+		app.DeleteSection(app.CurrentIdx)
+		return nil
+	}) {
+		return false
+	}
+	renderer.ResetScroll()
+	return true
+}
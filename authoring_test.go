@@ -0,0 +1,216 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Section Authoring Tests
+// ============================================================================
+
+// sectionTitles returns the titles of all sections in order.
+func sectionTitles(app *App) []string {
+	titles := make([]string, len(app.Sections))
+	for i, sec := range app.Sections {
+		titles[i] = sec.Title
+	}
+	return titles
+}
+
+func TestInsertSectionSameLevel(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 1 // Giai đoạn 1, with two chapters
+
+	if err := app.InsertSection("Giai đoạn 1.5: Review", 2); err != nil {
+		t.Fatalf("InsertSection failed: %v", err)
+	}
+
+	got := strings.Join(sectionTitles(app), "|")
+	want := "Main Title|Giai đoạn 1: Learning|Chapter 1: Basics|Chapter 2: Advanced|Giai đoạn 1.5: Review|Giai đoạn 2: Practice|Exercise 1"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	if app.CurrentIdx != 4 {
+		t.Errorf("Expected new section to be current, got %d", app.CurrentIdx)
+	}
+}
+
+func TestInsertSectionChild(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 1
+
+	if err := app.InsertSection("Chapter 0", 3); err != nil {
+		t.Fatalf("InsertSection failed: %v", err)
+	}
+
+	if app.CurrentIdx != 2 || app.Sections[2].Title != "Chapter 0" || app.Sections[3].Title != "Chapter 1: Basics" {
+		t.Errorf("Expected child inserted before first chapter, got %v", sectionTitles(app))
+	}
+
+	if err := app.InsertSection("", 2); err == nil {
+		t.Error("Expected error for empty title")
+	}
+
+	if err := app.InsertSection("Too deep", 7); err == nil {
+		t.Error("Expected error for invalid level")
+	}
+}
+
+func TestSplitSection(t *testing.T) {
+	app := createTestApp()
+	lines := strings.Split(app.Sections[2].Content(), "\n")
+	at := 0
+	for i, line := range lines {
+		if strings.Contains(line, "Task three") {
+			at = i
+		}
+	}
+
+	if err := app.SplitSection(2, at, "Chapter 1b"); err != nil {
+		t.Fatalf("SplitSection failed: %v", err)
+	}
+
+	if app.Sections[3].Title != "Chapter 1b" || app.Sections[3].Level != 3 {
+		t.Fatalf("Expected new level-3 section, got %+v", app.Sections[3])
+	}
+
+	if _, total := app.GetProgress(2); total != 2 {
+		t.Errorf("Expected 2 checkboxes left in first part, got %d", total)
+	}
+
+	if !strings.Contains(app.Sections[3].Content(), "Task three") {
+		t.Errorf("Expected second part to start with Task three, got %q", app.Sections[3].Content())
+	}
+
+	if err := app.SplitSection(2, 0, "x"); err == nil {
+		t.Error("Expected error when splitting at line 0")
+	}
+}
+
+func TestSplitSectionInsideFence(t *testing.T) {
+	app := NewApp()
+	app.FileLines = strings.Split("# A\n```\ncode\nmore\n```\n", "\n")
+	app.ParseSections()
+
+	if err := app.SplitSection(0, 2, "B"); err == nil {
+		t.Error("Expected error when splitting inside a code block")
+	}
+}
+
+func TestMoveSection(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 4 // Giai đoạn 2
+
+	if !app.MoveSection(4, -1) {
+		t.Fatal("Expected move up to succeed")
+	}
+
+	got := strings.Join(sectionTitles(app), "|")
+	want := "Main Title|Giai đoạn 2: Practice|Exercise 1|Giai đoạn 1: Learning|Chapter 1: Basics|Chapter 2: Advanced"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	if app.CurrentIdx != 1 {
+		t.Errorf("Expected moved section to stay current, got %d", app.CurrentIdx)
+	}
+
+	if !app.MoveSection(1, 1) || app.CurrentIdx != 4 || app.Sections[4].Title != "Giai đoạn 2: Practice" {
+		t.Errorf("Expected move down to restore order, got %v", sectionTitles(app))
+	}
+
+	// No sibling under the same parent
+	if app.MoveSection(3, 1) || app.MoveSection(2, -1) || app.MoveSection(0, 1) {
+		t.Error("Expected moves without a sibling to fail")
+	}
+}
+
+func TestDeleteSection(t *testing.T) {
+	app := createTestApp()
+	app.ToggleSkip(2)
+
+	if removed := app.DeleteSection(1); removed != 3 {
+		t.Errorf("Expected 3 sections removed, got %d", removed)
+	}
+
+	got := strings.Join(sectionTitles(app), "|")
+	if got != "Main Title|Giai đoạn 2: Practice|Exercise 1" {
+		t.Errorf("Unexpected sections after delete: %s", got)
+	}
+
+	if len(app.Skipped) != 0 {
+		t.Errorf("Expected skipped mark of deleted section removed, got %v", app.Skipped)
+	}
+
+	if checked, total := app.GetTotalProgress(); checked != 2 || total != 2 {
+		t.Errorf("Expected 2/2 after delete, got %d/%d", checked, total)
+	}
+}
+
+// headerlessMarkdown is a document without any header.
+const headerlessMarkdown = `Chuẩn bị môi trường lab trước khi bắt đầu.
+
+- [ ] Cài kubectl
+- [x] Cài kind
+
+Tạo cluster đầu tiên:
+
+` + "```bash\nkind create cluster\n\nkubectl get nodes\n```" + `
+
+> Ghi nhớ: xóa cluster sau khi học xong.`
+
+func TestImplicitSection(t *testing.T) {
+	app := NewApp()
+	app.FilePath = "/tmp/lab-notes.md"
+	app.FileLines = strings.Split(headerlessMarkdown, "\n")
+	app.ParseSections()
+
+	if len(app.Sections) != 1 || !app.Sections[0].Implicit || app.Sections[0].Title != "lab-notes" {
+		t.Fatalf("Expected one implicit section named after the file, got %+v", app.Sections)
+	}
+	if checked, total := app.GetTotalProgress(); checked != 1 || total != 2 {
+		t.Errorf("Expected 1/2, got %d/%d", checked, total)
+	}
+
+	app.AddNote("nhớ cài helm")
+	app.UpdateFileSection(0)
+	if app.FileLines[0] != "Chuẩn bị môi trường lab trước khi bắt đầu." || !strings.Contains(strings.Join(app.FileLines, "\n"), "nhớ cài helm") {
+		t.Errorf("Expected the note appended without a header line, got %q", app.FileLines[:2])
+	}
+	if got := app.SearchSections("helm"); len(got) != 1 {
+		t.Errorf("Expected the note to be searchable, got %v", got)
+	}
+
+	app.FileLines = []string{"", "  "}
+	app.ParseSections()
+	if len(app.Sections) != 0 {
+		t.Errorf("Expected no section for a blank document, got %d", len(app.Sections))
+	}
+}
+
+func TestAutoStructure(t *testing.T) {
+	app := NewApp()
+	app.FilePath = "/tmp/lab-notes.md"
+	app.FileLines = strings.Split(headerlessMarkdown, "\n")
+	app.ParseSections()
+
+	if err := app.AutoStructure(); err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(sectionTitles(app), "|")
+	if got != "lab-notes|Chuẩn bị môi trường lab trước khi bắt đầu.|Tạo cluster đầu tiên" {
+		t.Errorf("Unexpected sections: %s", got)
+	}
+	if checked, total := app.GetTotalProgress(); checked != 1 || total != 2 {
+		t.Errorf("Expected the checkboxes kept, got %d/%d", checked, total)
+	}
+	if !strings.Contains(app.Sections[2].Content(), "kubectl get nodes") || !strings.Contains(app.Sections[2].Content(), "Ghi nhớ") {
+		t.Errorf("Expected the code block and quote kept in their section, got %q", app.Sections[2].Content())
+	}
+
+	if err := app.AutoStructure(); err == nil {
+		t.Error("Expected a structured document to be refused")
+	}
+}
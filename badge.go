@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Badge is a progress badge in the shields.io endpoint schema
+// (https://shields.io/badges/endpoint-badge).
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// progressBadge builds the badge for checked of total items.
+func progressBadge(label string, checked, total int) Badge {
+	pct := 0
+	if total > 0 {
+		pct = checked * 100 / total
+	}
+
+	color := "brightgreen"
+	switch {
+	case pct < 25:
+		color = "red"
+	case pct < 50:
+		color = "orange"
+	case pct < 75:
+		color = "yellow"
+	case pct < 100:
+		color = "yellowgreen"
+	}
+	return Badge{SchemaVersion: 1, Label: label, Message: fmt.Sprintf("%d%%", pct), Color: color}
+}
+
+// badgeColors maps shields.io color names to hex codes for the SVG.
+var badgeColors = map[string]string{
+	"red":         "#e05d44",
+	"orange":      "#fe7d37",
+	"yellow":      "#dfb317",
+	"yellowgreen": "#a4a61d",
+	"brightgreen": "#4c1",
+}
+
+// badgeTextWidth estimates the rendered width of text in the 11px
+// Verdana used by flat badges.
+func badgeTextWidth(text string) int {
+	return visibleWidth(text)*7 + 10
+}
+
+// SVG renders the badge in the shields.io "flat" style, so it can be
+// committed as a static file.
+func (b Badge) SVG() string {
+	lw, mw := badgeTextWidth(b.Label), badgeTextWidth(b.Message)
+	label, message := html.EscapeString(b.Label), html.EscapeString(b.Message)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+  <title>%[4]s: %[5]s</title>
+  <linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+  <clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+  <g clip-path="url(#r)">
+    <rect width="%[2]d" height="20" fill="#555"/>
+    <rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>
+    <rect width="%[1]d" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="%[7]d" y="14">%[4]s</text>
+    <text x="%[8]d" y="14">%[5]s</text>
+  </g>
+</svg>
+`, lw+mw, lw, mw, label, message, badgeColors[b.Color], lw/2, lw+mw/2)
+}
+
+// shieldsEscape escapes a static badge path segment: shields.io uses
+// "-" as the separator and "_" for spaces, so both are doubled.
+func shieldsEscape(s string) string {
+	s = strings.NewReplacer("-", "--", "_", "__").Replace(s)
+	return url.PathEscape(s)
+}
+
+// Markdown returns an image link to the equivalent static shields.io badge.
+func (b Badge) Markdown() string {
+	return fmt.Sprintf("![%s](https://img.shields.io/badge/%s-%s-%s)",
+		b.Label, shieldsEscape(b.Label), shieldsEscape(b.Message), b.Color)
+}
+
+// runBadge prints (or writes with -o) a progress badge for a profile
+// README: SVG by default, shields.io endpoint JSON with --json or a
+// static shields.io image link with --markdown.
+func runBadge(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("badge", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print shields.io endpoint JSON")
+	asMarkdown := fs.Bool("markdown", false, "print a Markdown image link to a static shields.io badge")
+	label := fs.String("label", "SRE Path", "badge label")
+	output := fs.String("o", "", "write the badge to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *asJSON && *asMarkdown {
+		return fmt.Errorf("--json and --markdown are mutually exclusive")
+	}
+
+	app.LoadState() // skipped sections do not count
+	checked, total := app.GetTotalProgress()
+	badge := progressBadge(*label, checked, total)
+
+	content := badge.SVG()
+	switch {
+	case *asJSON:
+		data, err := json.MarshalIndent(badge, "", "  ")
+		if err != nil {
+			return err
+		}
+		content = string(data) + "\n"
+	case *asMarkdown:
+		content = badge.Markdown() + "\n"
+	}
+
+	if *output != "" {
+		return os.WriteFile(*output, []byte(content), 0o644)
+	}
+	_, err := io.WriteString(out, content)
+	return err
+}
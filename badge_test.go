@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Badge Tests
+// ============================================================================
+
+func TestProgressBadge(t *testing.T) {
+	tests := []struct {
+		checked, total int
+		message, color string
+	}{
+		{0, 0, "0%", "red"},
+		{1, 4, "25%", "orange"},
+		{63, 100, "63%", "yellow"},
+		{9, 10, "90%", "yellowgreen"},
+		{5, 5, "100%", "brightgreen"},
+	}
+	for _, tt := range tests {
+		b := progressBadge("SRE Path", tt.checked, tt.total)
+		if b.Message != tt.message || b.Color != tt.color || b.SchemaVersion != 1 {
+			t.Errorf("progressBadge(%d, %d) = %+v, want %s %s", tt.checked, tt.total, b, tt.message, tt.color)
+		}
+	}
+}
+
+func TestBadgeMarkdownEscapesShieldsPath(t *testing.T) {
+	b := Badge{Label: "SRE-Path 2_0", Message: "63%", Color: "yellow"}
+	want := "![SRE-Path 2_0](https://img.shields.io/badge/SRE--Path%202__0-63%25-yellow)"
+	if got := b.Markdown(); got != want {
+		t.Errorf("Markdown() = %s, want %s", got, want)
+	}
+}
+
+func TestBadgeSVG(t *testing.T) {
+	svg := Badge{Label: "A&B", Message: "50%", Color: "yellow"}.SVG()
+	for _, want := range []string{"<svg", "A&amp;B: 50%", "#dfb317", "</svg>"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Expected SVG to contain %q:\n%s", want, svg)
+		}
+	}
+}
+
+func TestRunBadgeJSON(t *testing.T) {
+	withTestApp(t)
+
+	var out bytes.Buffer
+	if err := runBadge([]string{"--json", "--label", "Progress"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	var b Badge
+	if err := json.Unmarshal(out.Bytes(), &b); err != nil {
+		t.Fatalf("Expected endpoint JSON, got %s: %v", out.String(), err)
+	}
+	checked, total := app.GetTotalProgress()
+	if want := progressBadge("Progress", checked, total); b != want {
+		t.Errorf("Expected %+v, got %+v", want, b)
+	}
+
+	path := filepath.Join(t.TempDir(), "badge.svg")
+	if err := runBadge([]string{"-o", path}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(path); err != nil || !strings.HasPrefix(string(data), "<svg") {
+		t.Errorf("Expected SVG written to file, got %q (%v)", data, err)
+	}
+}
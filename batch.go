@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// BatchResult is the outcome of one batch operation, printed as one JSON
+// line.
+type BatchResult struct {
+	Line    int    `json:"line"`
+	Op      string `json:"op"`
+	OK      bool   `json:"ok"`
+	Section string `json:"section,omitempty"`
+	Item    string `json:"item,omitempty"`
+	State   string `json:"state,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// findItem returns the content line of the current section's checkbox
+// whose text is the given one, or else the only one containing it (both
+// case-insensitively).
+func (a *App) findItem(text string) (int, error) {
+	sec := a.GetCurrentSection()
+	if sec == nil {
+		return -1, fmt.Errorf("no section")
+	}
+	want := strings.ToLower(strings.TrimSpace(text))
+	var partial []int
+	for i, line := range strings.Split(sec.Content(), "\n") {
+		if !isCheckbox(line) {
+			continue
+		}
+		item := strings.ToLower(checkboxText(line))
+		if item == want {
+			return i, nil
+		}
+		if strings.Contains(item, want) {
+			partial = append(partial, i)
+		}
+	}
+	switch len(partial) {
+	case 0:
+		return -1, fmt.Errorf("no item %q in %q", text, sec.Title)
+	case 1:
+		return partial[0], nil
+	}
+	return -1, fmt.Errorf("%d items in %q match %q", len(partial), sec.Title, text)
+}
+
+// RunBatchOp applies one batch operation to the current section:
+//
+//	goto <section>            number, #id or title (see FindSection)
+//	toggle|check|uncheck <item text>
+//	note <text>               "\n" starts a new line
+//	save                      write the file and the progress
+//
+// Changes are applied to the file lines right away but only written by
+// "save".
+func (a *App) RunBatchOp(op, arg string, pageSize int) BatchResult {
+	res := BatchResult{Op: op}
+	fail := func(err error) BatchResult {
+		res.Error = err.Error()
+		return res
+	}
+
+	switch op {
+	case "goto":
+		idx := a.FindSection(arg)
+		if idx < 0 {
+			return fail(fmt.Errorf("section %q not found", arg))
+		}
+		a.GotoSection(idx)
+	case "toggle", "check", "uncheck":
+		idx, err := a.findItem(arg)
+		if err != nil {
+			return fail(err)
+		}
+		switch op {
+		case "toggle":
+			a.ToggleCheckbox(idx)
+		case "check":
+			a.SetCheckboxState(idx, stateDone)
+		case "uncheck":
+			a.SetCheckboxState(idx, stateOpen)
+		}
+		a.UpdateFileSection(a.CurrentIdx)
+		line := strings.Split(a.Sections[a.CurrentIdx].Content(), "\n")[idx]
+		res.Item = checkboxText(line)
+		res.State = checkboxStateNames[checkboxState(line)]
+	case "note":
+		text := strings.TrimSpace(strings.ReplaceAll(arg, `\n`, "\n"))
+		if text == "" {
+			return fail(fmt.Errorf("empty note"))
+		}
+		a.AddNote(text)
+		a.UpdateFileSection(a.CurrentIdx)
+	case "save":
+		if err := a.SaveFile(); err != nil {
+			return fail(err)
+		}
+		if err := a.SaveState(pageSize); err != nil {
+			return fail(err)
+		}
+	default:
+		return fail(fmt.Errorf("unknown operation %q (goto, toggle, check, uncheck, note, save)", op))
+	}
+
+	if sec := a.GetCurrentSection(); sec != nil {
+		res.Section = sec.Title
+	}
+	res.OK = true
+	return res
+}
+
+// RunBatch applies the operations read from in, one per line ("#" starts
+// a comment), and writes a BatchResult per operation to out. Failed
+// operations are reported and skipped; their number is returned.
+func (a *App) RunBatch(in io.Reader, out io.Writer, pageSize int) (int, error) {
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	failed := 0
+	scanner := bufio.NewScanner(in)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		op, arg, _ := strings.Cut(line, " ")
+		res := a.RunBatchOp(op, strings.TrimSpace(arg), pageSize)
+		res.Line = n
+		if !res.OK {
+			failed++
+		}
+		if err := enc.Encode(res); err != nil {
+			return failed, err
+		}
+	}
+	return failed, scanner.Err()
+}
+
+// runBatch implements --batch: it opens the document and applies the
+// operations of path ("-" for stdin) without the TUI.
+func runBatch(path, profile string, out io.Writer) error {
+	in := io.Reader(os.Stdin)
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	if err := openDocument(profile); err != nil {
+		return err
+	}
+	pageSize, _ := app.LoadState()
+	app.CurrentIdx = 0
+
+	failed, err := app.RunBatch(in, out, pageSize)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d operation(s) failed", failed)
+	}
+	return nil
+}
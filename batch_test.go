@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunBatch(t *testing.T) {
+	app := withTestApp(t)
+	dir := t.TempDir()
+	app.FilePath = filepath.Join(dir, "path.md")
+	app.StateFile = filepath.Join(dir, "state")
+	if err := os.WriteFile(app.FilePath, []byte(app.FileContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ops := `# maintenance
+goto Chapter 1
+check task one
+toggle Task two
+uncheck task
+note Đã ôn lại\ncả phần 1
+goto Nowhere
+save
+`
+	var out bytes.Buffer
+	failed, err := app.RunBatch(strings.NewReader(ops), &out, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed != 2 {
+		t.Errorf("Expected 2 failed operations, got %d", failed)
+	}
+
+	var results []BatchResult
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var res BatchResult
+		if err := json.Unmarshal([]byte(line), &res); err != nil {
+			t.Fatalf("%q: %v", line, err)
+		}
+		results = append(results, res)
+	}
+	if len(results) != 7 {
+		t.Fatalf("Expected a result per operation, got %+v", results)
+	}
+	if r := results[1]; !r.OK || r.Line != 3 || r.Item != "Task one" || r.State != "done" || r.Section != "Chapter 1: Basics" {
+		t.Errorf("check = %+v", r)
+	}
+	if r := results[2]; !r.OK || r.State != "todo" {
+		t.Errorf("toggle = %+v", r)
+	}
+	if r := results[3]; r.OK || !strings.Contains(r.Error, "3 items") {
+		t.Errorf("Expected an ambiguous item to fail, got %+v", r)
+	}
+	if r := results[5]; r.OK || r.Op != "goto" {
+		t.Errorf("goto = %+v", r)
+	}
+
+	data, _ := os.ReadFile(app.FilePath)
+	for _, want := range []string{"- [x] Task one", "- [ ] Task two completed", ":** Đã ôn lại\n> cả phần 1"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %q in the saved file", want)
+		}
+	}
+	if !fileExists(app.StateFile) {
+		t.Error("Expected the progress to be saved")
+	}
+}
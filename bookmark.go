@@ -0,0 +1,35 @@
+package main
+
+// ToggleBookmark bookmarks or unbookmarks a section.
+// Returns the new bookmarked state of the section.
+func (a *App) ToggleBookmark(idx int) bool {
+	if idx < 0 || idx >= len(a.Sections) {
+		return false
+	}
+	if a.Bookmarks == nil {
+		a.Bookmarks = make(map[string]bool)
+	}
+
+	title := a.Sections[idx].Title
+	if a.Bookmarks[title] {
+		delete(a.Bookmarks, title)
+		return false
+	}
+	a.Bookmarks[title] = true
+	return true
+}
+
+// handleBookmark toggles the bookmark of the current section.
+func handleBookmark() {
+	sec := app.GetCurrentSection()
+	if sec == nil {
+		return
+	}
+
+	if app.ToggleBookmark(app.CurrentIdx) {
+		notify(SeveritySuccess, "Đã đánh dấu: %s", sec.Title)
+	} else {
+		notify(SeverityInfo, "Đã bỏ đánh dấu: %s", sec.Title)
+	}
+	app.SaveState(renderer.PageSize)
+}
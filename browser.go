@@ -0,0 +1,280 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxRecentDocuments is how many recently opened documents the file
+// browser lists above the directory.
+const maxRecentDocuments = 5
+
+// BrowserEntry is one row of the file browser.
+type BrowserEntry struct {
+	Name string
+	Path string
+	Dir  bool
+	// Recent marks a recently opened document (listed from the state
+	// directory, wherever it is)
+	Recent bool
+}
+
+// isDocumentPath reports whether the viewer can open the file at path.
+func isDocumentPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return isAsciiDocPath(path)
+}
+
+// RecentDocuments returns the documents that have a state file, most
+// recently saved first. Documents no longer on disk, state files that
+// cannot be read (e.g. encrypted without the key) and relative paths
+// recorded from another directory are skipped.
+func RecentDocuments(limit int) []string {
+	base, err := stateHome()
+	if err != nil {
+		return nil
+	}
+	paths, _ := filepath.Glob(filepath.Join(base, "documents", "*.json"))
+
+	type recent struct {
+		path  string
+		saved int64
+	}
+	var found []recent
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		data, err := readSecure(p)
+		if err != nil {
+			continue
+		}
+		s, err := DecodeState(data)
+		if err != nil || s.FilePath == "" || !fileExists(s.FilePath) {
+			continue
+		}
+		// The state file is keyed by the absolute path
+		if own, err := documentStateFile(s.FilePath); err == nil && own == p {
+			found = append(found, recent{absPath(s.FilePath), info.ModTime().UnixNano()})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].saved > found[j].saved })
+
+	var docs []string
+	for _, r := range found {
+		if len(docs) == limit {
+			break
+		}
+		docs = append(docs, r.path)
+	}
+	return docs
+}
+
+// ListBrowserEntries returns the parent directory, the subdirectories and
+// the documents of dir, sorted by name. Hidden files are skipped.
+func ListBrowserEntries(dir string) ([]BrowserEntry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []BrowserEntry
+	if parent := filepath.Dir(dir); parent != dir {
+		entries = append(entries, BrowserEntry{Name: "..", Path: parent, Dir: true})
+	}
+	var docs []BrowserEntry
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		// Follow symlinks, so linked folders can be entered
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+		case info.IsDir():
+			entries = append(entries, BrowserEntry{Name: f.Name() + "/", Path: path, Dir: true})
+		case isDocumentPath(f.Name()):
+			docs = append(docs, BrowserEntry{Name: f.Name(), Path: path})
+		}
+	}
+	return append(entries, docs...), nil
+}
+
+// filterEntries returns the entries whose name contains filter
+// (case-insensitive).
+func filterEntries(entries []BrowserEntry, filter string) []BrowserEntry {
+	if filter == "" {
+		return entries
+	}
+	filter = strings.ToLower(filter)
+	var matched []BrowserEntry
+	for _, e := range entries {
+		if strings.Contains(strings.ToLower(e.Name), filter) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// browseFiles lets the user pick a document, starting in dir, with the
+// recently opened ones listed first. j/k (or ↑/↓) move, Enter opens a
+// directory or picks a document, typing filters the list (once a filter
+// is typed, j and k are part of it), Backspace edits the filter or goes
+// up a directory, and Esc clears the filter or cancels. It returns ""
+// when cancelled and expects the terminal in raw mode.
+func browseFiles(dir string) string {
+	dir = absPath(dir)
+	var recent []BrowserEntry
+	for _, path := range recentDocuments(maxRecentDocuments) {
+		recent = append(recent, BrowserEntry{Name: path, Path: path, Recent: true})
+	}
+
+	filter, selected, offset := "", 0, 0
+	entries, err := ListBrowserEntries(dir)
+	for {
+		items := filterEntries(append(append([]BrowserEntry{}, recent...), entries...), filter)
+		rows := max(1, app.TermHeight-7)
+		selected = max(0, min(selected, len(items)-1))
+		if selected < offset {
+			offset = selected
+		}
+		if selected >= offset+rows {
+			offset = selected - rows + 1
+		}
+
+		ClearScreen()
+		fmt.Printf("%s\n", barLine(BgMagenta+White+Bold, " 📂 MỞ FILE  (j/k: di chuyển, Enter: mở, Esc: đóng)", app.TermWidth))
+		fmt.Printf("%s%s%s\n", Dim, dir, Reset)
+		if filter != "" {
+			fmt.Printf("%s🔎 %s%s\n\n", Yellow, filter, Reset)
+		} else {
+			fmt.Printf("%sGõ để lọc...%s\n\n", Dim, Reset)
+		}
+		if err != nil {
+			fmt.Printf("%s%v%s\n", Red, err, Reset)
+		}
+
+		end := min(offset+rows, len(items))
+		for i := offset; i < end; i++ {
+			item := items[i]
+			selector := "  "
+			if i == selected {
+				selector = Green + glyphs.Selector + " " + Reset
+			}
+			name := item.Name
+			switch {
+			case item.Recent:
+				name = "🕘 " + Cyan + name + Reset
+			case item.Dir:
+				name = Bold + Blue + name + Reset
+			}
+			if item.Path == absPath(app.FilePath) {
+				name += Cyan + " (hiện tại)" + Reset
+			}
+			fmt.Println(truncateVisible(selector+name, app.TermWidth))
+		}
+		if len(items) == 0 {
+			fmt.Printf("%sKhông có file nào khớp.%s\n", Dim, Reset)
+		}
+
+		switch key := readKey(); {
+		case key == "Down", key == "j" && filter == "":
+			selected++
+		case key == "Up", key == "k" && filter == "":
+			selected--
+		case key == "Enter":
+			if len(items) == 0 {
+				continue
+			}
+			item := items[selected]
+			if !item.Dir {
+				return item.Path
+			}
+			dir = item.Path
+			entries, err = ListBrowserEntries(dir)
+			filter, selected, offset = "", 0, 0
+		case key == "Backspace":
+			if filter != "" {
+				_, size := utf8.DecodeLastRuneInString(filter)
+				filter = filter[:len(filter)-size]
+				selected = 0
+			} else if parent := filepath.Dir(dir); parent != dir {
+				dir = parent
+				entries, err = ListBrowserEntries(dir)
+				selected, offset = 0, 0
+			}
+		case key == "Esc":
+			if filter == "" {
+				return ""
+			}
+			filter, selected = "", 0
+		case key == "q" && filter == "", key == "Ctrl+c":
+			return ""
+		case utf8.RuneCountInString(key) == 1:
+			filter += key
+			selected = 0
+		case key == "Paste":
+			filter += pasteLine(keyboard.Pasted())
+			selected = 0
+		}
+	}
+}
+
+// switchDocument saves the current session and state, then opens the
+// document at path in place of the current one, with the given state
+// file and profile ("" for the document's own state). If it cannot be
+// opened, the previous document is opened again.
+func switchDocument(path, stateFile, profile string) error {
+	if err := saveSession(); err != nil {
+		return err
+	}
+	app.SaveState(renderer.PageSize)
+
+	prev := CaptureSession(app.Session, app, renderer)
+	open := func(filePath, stateFile, profile string) error {
+		app.FilePath, app.StateFile, app.Profile = filePath, stateFile, profile
+		app.ApplyState(State{})
+		if err := openDocument(""); err != nil {
+			return err
+		}
+		terminal.SetRawMode(false)
+		defer terminal.SetRawMode(true)
+		return lockDocument(reader)
+	}
+	if err := open(absPath(path), stateFile, profile); err != nil {
+		if prevErr := open(prev.FilePath, prev.StateFile, prev.Profile); prevErr == nil {
+			app.LoadState()
+			ApplySession(prev, app, renderer)
+		}
+		return err
+	}
+
+	if _, err := app.LoadState(); err == nil && app.CurrentIdx >= len(app.Sections) {
+		app.CurrentIdx = 0
+	}
+	renderer.RestoreScroll()
+	rememberDocument()
+	return nil
+}
+
+// handleOpenFile opens another document picked in the file browser.
+func handleOpenFile() {
+	path := browseFiles(filepath.Dir(absPath(app.FilePath)))
+	if path == "" || path == absPath(app.FilePath) {
+		return
+	}
+	if err := switchDocument(path, "", ""); err != nil {
+		notify(SeverityError, "Không mở được %s: %v", path, err)
+		return
+	}
+	notify(SeveritySuccess, "Đã mở %s", filepath.Base(app.FilePath))
+}
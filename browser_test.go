@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListBrowserEntries(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "k8s"), 0o755)
+	os.Mkdir(filepath.Join(dir, ".git"), 0o755)
+	for _, name := range []string{"sre.md", "notes.txt", "guide.adoc", ".draft.md"} {
+		os.WriteFile(filepath.Join(dir, name), []byte("# x\n"), 0o644)
+	}
+
+	entries, err := ListBrowserEntries(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	if want := []string{"..", "k8s/", "guide.adoc", "sre.md"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ListBrowserEntries = %v, want %v", names, want)
+	}
+	if !entries[1].Dir || entries[3].Dir || entries[3].Path != filepath.Join(dir, "sre.md") {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	if got := filterEntries(entries, "SRE"); len(got) != 1 || got[0].Name != "sre.md" {
+		t.Errorf("filterEntries = %+v", got)
+	}
+}
+
+func TestRecentDocuments(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	save := func(name string, saved time.Time) {
+		os.WriteFile(name, []byte("# x\n"), 0o644)
+		a := NewApp()
+		a.FilePath = name
+		a.UseDocumentState()
+		if err := a.SaveState(20); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(a.StateFile, saved, saved)
+	}
+	now := time.Now()
+	save("sre.md", now.Add(-time.Hour))
+	save("k8s.md", now)
+	save("gone.md", now)
+	os.Remove("gone.md")
+
+	want := []string{filepath.Join(dir, "k8s.md"), filepath.Join(dir, "sre.md")}
+	if got := RecentDocuments(5); !reflect.DeepEqual(got, want) {
+		t.Errorf("RecentDocuments = %v, want %v", got, want)
+	}
+	if got := RecentDocuments(1); len(got) != 1 {
+		t.Errorf("Expected the limit to apply, got %v", got)
+	}
+}
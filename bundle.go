@@ -0,0 +1,290 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// bundleExt is the extension of a bundle: a zip archive holding the
+// document with everything that belongs to it.
+const bundleExt = ".srelearn"
+
+// bundleVersion is the version of the bundle layout.
+const bundleVersion = 1
+
+// Bundle layout: manifest.json, the document and its sidecars (review
+// file, attachments/) under document/, and the per-document files of the
+// state directory under state/.
+const (
+	bundleManifest = "manifest.json"
+	bundleDocDir   = "document/"
+	bundleStateDir = "state/"
+)
+
+// BundleManifest describes a bundle.
+type BundleManifest struct {
+	Version  int       `json:"version"`
+	Document string    `json:"document"`
+	Created  time.Time `json:"created"`
+}
+
+// stateSidecars returns the per-document files of the state directory by
+// their name in a bundle.
+func stateSidecars(docPath, stateFile string) map[string]string {
+	files := map[string]string{"state.json": stateFile}
+	if p, err := trashPath(docPath); err == nil {
+		files["trash.json"] = p
+	}
+	if p, err := quizPath(docPath); err == nil {
+		files["quiz.json"] = p
+	}
+	return files
+}
+
+// addBundleFile copies the file at src into the archive as name.
+func addBundleFile(zw *zip.Writer, name, src string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteBundle writes the document, its review sidecar and attachments,
+// and its state, trash and quiz files as a bundle. Missing sidecars are
+// skipped; it returns the number of files written.
+func (a *App) WriteBundle(w io.Writer, now time.Time) (int, error) {
+	zw := zip.NewWriter(w)
+	name := filepath.Base(a.FilePath)
+	manifest, err := json.MarshalIndent(BundleManifest{Version: bundleVersion, Document: name, Created: now}, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	mw, err := zw.Create(bundleManifest)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := mw.Write(manifest); err != nil {
+		return 0, err
+	}
+
+	files := 0
+	add := func(name, src string) error {
+		if !fileExists(src) {
+			return nil
+		}
+		files++
+		return addBundleFile(zw, name, src)
+	}
+	if err := add(bundleDocDir+name, a.FilePath); err != nil {
+		return files, err
+	}
+	if err := add(bundleDocDir+name+".review.json", reviewPath(a.FilePath)); err != nil {
+		return files, err
+	}
+
+	attachments := filepath.Join(filepath.Dir(a.FilePath), attachmentsDirName)
+	err = filepath.WalkDir(attachments, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			return filepath.SkipDir
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(attachments, p)
+		if err != nil {
+			return err
+		}
+		return add(bundleDocDir+attachmentsDirName+"/"+filepath.ToSlash(rel), p)
+	})
+	if err != nil {
+		return files, err
+	}
+
+	for name, src := range stateSidecars(a.FilePath, a.StateFile) {
+		if err := add(bundleStateDir+name, src); err != nil {
+			return files, err
+		}
+	}
+	return files, zw.Close()
+}
+
+// readBundleFile returns the content of an archived file.
+func readBundleFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// Unbundle extracts a bundle into dir: the document and its sidecars go
+// to dir, the state files to the state directory of the extracted
+// document, rewritten to point at its new location. Existing files are
+// only replaced with force. It returns the path of the document.
+func Unbundle(bundlePath, dir string, force bool) (string, error) {
+	zr, err := zip.OpenReader(bundlePath)
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+
+	var manifest BundleManifest
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	mf, ok := files[bundleManifest]
+	if !ok {
+		return "", fmt.Errorf("%s is not a bundle (no %s)", bundlePath, bundleManifest)
+	}
+	data, err := readBundleFile(mf)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", bundleManifest, err)
+	}
+	if manifest.Version > bundleVersion {
+		return "", fmt.Errorf("bundle version %d is newer than this viewer supports (%d)", manifest.Version, bundleVersion)
+	}
+	if manifest.Document == "" || manifest.Document != path.Base(manifest.Document) {
+		return "", fmt.Errorf("invalid document name %q", manifest.Document)
+	}
+
+	docPath := absPath(filepath.Join(dir, manifest.Document))
+	sidecars := stateSidecars(docPath, "")
+	if p, err := documentStateFile(docPath); err == nil {
+		sidecars["state.json"] = p
+	}
+
+	// Map every archived file to its destination before writing anything
+	targets := map[string]string{}
+	for _, f := range zr.File {
+		switch {
+		case f.Name == bundleManifest || strings.HasSuffix(f.Name, "/"):
+		case strings.HasPrefix(f.Name, bundleDocDir):
+			rel := strings.TrimPrefix(f.Name, bundleDocDir)
+			if !filepath.IsLocal(rel) {
+				return "", fmt.Errorf("unsafe path %q in bundle", f.Name)
+			}
+			targets[f.Name] = filepath.Join(dir, filepath.FromSlash(rel))
+		case strings.HasPrefix(f.Name, bundleStateDir):
+			if target := sidecars[strings.TrimPrefix(f.Name, bundleStateDir)]; target != "" {
+				targets[f.Name] = target
+			}
+		}
+	}
+	if _, ok := targets[bundleDocDir+manifest.Document]; !ok {
+		return "", fmt.Errorf("bundle has no %s", manifest.Document)
+	}
+	if !force {
+		for _, target := range targets {
+			if fileExists(target) {
+				return "", fmt.Errorf("%s already exists (use --force to overwrite)", target)
+			}
+		}
+	}
+
+	for name, target := range targets {
+		data, err := readBundleFile(files[name])
+		if err != nil {
+			return "", err
+		}
+		if name == bundleStateDir+"state.json" {
+			// Point the state at the new location, encrypted again if it was
+			encrypted := isEncrypted(data)
+			if data, err = openSecure(data); err != nil {
+				return "", fmt.Errorf("bundled state: %w", err)
+			}
+			s, err := DecodeState(data)
+			if err != nil {
+				return "", fmt.Errorf("bundled state: %w", err)
+			}
+			s.FilePath = docPath
+			if data, err = json.MarshalIndent(s, "", "  "); err != nil {
+				return "", err
+			}
+			data = append(data, '\n')
+			if encrypted {
+				if data, err = vault.Seal(data); err != nil {
+					return "", err
+				}
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			return "", err
+		}
+	}
+	return docPath, nil
+}
+
+// runBundle implements "bundle [-o file.srelearn]".
+func runBundle(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	output := fs.String("o", "", "output file (default: the document name with "+bundleExt+")")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	target := *output
+	if target == "" {
+		target = strings.TrimSuffix(filepath.Base(app.FilePath), filepath.Ext(app.FilePath)) + bundleExt
+	}
+
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	n, err := app.WriteBundle(f, time.Now())
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Wrote %s (%d files)\n", target, n)
+	return nil
+}
+
+// runUnbundle implements "unbundle [--force] <file.srelearn> [dir]".
+func runUnbundle(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("unbundle", flag.ContinueOnError)
+	force := fs.Bool("force", false, "overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		return fmt.Errorf("usage: sre-learn unbundle [--force] <file%s> [dir]", bundleExt)
+	}
+	dir := "."
+	if fs.NArg() == 2 {
+		dir = fs.Arg(1)
+	}
+
+	docPath, err := Unbundle(fs.Arg(0), dir, *force)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Extracted %s\n", docPath)
+	return nil
+}
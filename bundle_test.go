@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	app := withTestApp(t)
+	src := t.TempDir()
+	app.FilePath = filepath.Join(src, "path.md")
+	app.UseDocumentState()
+	os.WriteFile(app.FilePath, []byte(app.FileContent), 0o644)
+	os.WriteFile(reviewPath(app.FilePath), []byte(`{"snapshots": []}`), 0o644)
+	os.MkdirAll(filepath.Join(src, attachmentsDirName, "lab"), 0o755)
+	os.WriteFile(filepath.Join(src, attachmentsDirName, "lab", "diagram.png"), []byte("png"), 0o644)
+	app.CurrentIdx = 3
+	if err := app.SaveState(20); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := app.WriteBundle(&buf, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Errorf("Expected document, review, attachment and state, got %d files", n)
+	}
+	bundle := filepath.Join(t.TempDir(), "path"+bundleExt)
+	os.WriteFile(bundle, buf.Bytes(), 0o644)
+
+	dest := t.TempDir()
+	docPath, err := Unbundle(bundle, dest, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(docPath); string(data) != app.FileContent {
+		t.Error("Expected the document to be extracted")
+	}
+	for _, p := range []string{reviewPath(docPath), filepath.Join(dest, attachmentsDirName, "lab", "diagram.png")} {
+		if !fileExists(p) {
+			t.Errorf("Expected %s to be extracted", p)
+		}
+	}
+
+	moved := NewApp()
+	moved.FilePath = docPath
+	moved.UseDocumentState()
+	if _, err := moved.LoadState(); err != nil {
+		t.Fatal(err)
+	}
+	if moved.CurrentIdx != 3 || moved.FilePath != docPath {
+		t.Errorf("Expected the state to follow the document, got idx %d, %s", moved.CurrentIdx, moved.FilePath)
+	}
+
+	if _, err := Unbundle(bundle, dest, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("Expected existing files to be kept, got %v", err)
+	}
+	if _, err := Unbundle(bundle, dest, true); err != nil {
+		t.Errorf("Expected --force to overwrite, got %v", err)
+	}
+}
+
+func TestUnbundleRejectsOtherArchives(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.zip")
+	os.WriteFile(path, []byte("not a zip"), 0o644)
+	if _, err := Unbundle(path, t.TempDir(), false); err == nil {
+		t.Error("Expected an error for a file that is not a bundle")
+	}
+}
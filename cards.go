@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// summaryMarker starts the summary block of a section, a quote such as
+// "> 📌 Summary: etcd keeps the cluster state" whose following quote
+// lines continue it.
+const summaryMarker = "📌 Summary:"
+
+// SummaryCard is the summary of a section shown as a revision card.
+type SummaryCard struct {
+	Section int
+	Lines   []string
+}
+
+// SectionSummary returns the lines of the section's summary block, or
+// nil when it has none.
+func (a *App) SectionSummary(idx int) []string {
+	if idx < 0 || idx >= len(a.Sections) {
+		return nil
+	}
+	var summary []string
+	inSummary := false
+	for _, line := range strings.Split(a.Sections[idx].Content(), "\n") {
+		trimmed := strings.TrimSpace(line)
+		quoted, isQuote := strings.CutPrefix(trimmed, ">")
+		quoted = strings.TrimSpace(quoted)
+		switch {
+		case !inSummary && isQuote && strings.HasPrefix(quoted, summaryMarker):
+			inSummary = true
+			if text := strings.TrimSpace(strings.TrimPrefix(quoted, summaryMarker)); text != "" {
+				summary = append(summary, text)
+			}
+		case inSummary && isQuote:
+			summary = append(summary, quoted)
+		case inSummary:
+			return summary
+		}
+	}
+	return summary
+}
+
+// SummaryCards returns the cards of the sections in scope (a phase, or -1
+// for the whole document) in document order. Skipped sections are left
+// out, and so are the cards marked as known unless withKnown is set.
+func (a *App) SummaryCards(scope int, withKnown bool) []SummaryCard {
+	var cards []SummaryCard
+	start, end := a.cycleRange(scope)
+	for i := start; i < end; i++ {
+		if a.IsSkipped(i) || (!withKnown && a.KnownSummaries[a.Sections[i].Title]) {
+			continue
+		}
+		if summary := a.SectionSummary(i); len(summary) > 0 {
+			cards = append(cards, SummaryCard{i, summary})
+		}
+	}
+	return cards
+}
+
+// ToggleKnownSummary marks the summary of the section as known, or no
+// longer known, and reports whether it is now known.
+func (a *App) ToggleKnownSummary(idx int) bool {
+	title := a.Sections[idx].Title
+	if a.KnownSummaries[title] {
+		delete(a.KnownSummaries, title)
+		return false
+	}
+	if a.KnownSummaries == nil {
+		a.KnownSummaries = make(map[string]bool)
+	}
+	a.KnownSummaries[title] = true
+	return true
+}
+
+// currentPhase returns the level-2 section containing the current
+// section, or -1.
+func (a *App) currentPhase() int {
+	if sec := a.GetCurrentSection(); sec != nil && sec.Level == 2 {
+		return a.CurrentIdx
+	}
+	for _, idx := range a.Ancestors(a.CurrentIdx) {
+		if a.Sections[idx].Level == 2 {
+			return idx
+		}
+	}
+	return -1
+}
+
+// chooseCardScope asks for the phase whose cards to review: Enter picks
+// the current phase (the whole document outside of one), 0 the whole
+// document. It returns false when cancelled.
+func chooseCardScope() (int, bool) {
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+
+	ClearScreen()
+	fmt.Printf("%s📌 THẺ TÓM TẮT%s\n", Bold+Cyan, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	fmt.Printf("\n  %s0%s - Cả lộ trình (%d thẻ)\n", Cyan, Reset, len(app.SummaryCards(-1, true)))
+	var phases []int
+	for i, sec := range app.Sections {
+		if sec.Level == 2 {
+			phases = append(phases, i)
+			fmt.Printf("  %s%d%s - %s (%d thẻ)\n", Cyan, len(phases), Reset, sec.Title, len(app.SummaryCards(i, true)))
+		}
+	}
+	current := app.currentPhase()
+	fmt.Printf("\nPhạm vi (Enter: giai đoạn hiện tại, 0-%d, q để hủy): ", len(phases))
+	input, _ := bufio.NewReader(keyboard).ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return current, true
+	}
+	n, err := strconv.Atoi(input)
+	if err != nil || n < 0 || n > len(phases) {
+		return 0, false
+	}
+	if n == 0 {
+		return -1, true
+	}
+	return phases[n-1], true
+}
+
+// handleSummaryCards shows the summaries of a phase as a deck of cards,
+// hiding the rest of the content: →/Space and ← move, s shuffles, x marks
+// a card as known (known cards are left out of the deck until a toggles
+// them back in), Enter opens the section and q closes.
+func handleSummaryCards() {
+	if len(app.SummaryCards(-1, true)) == 0 {
+		notify(SeverityInfo, "Chưa có section nào có khối \"> %s\"", summaryMarker)
+		return
+	}
+	scope, ok := chooseCardScope()
+	if !ok {
+		return
+	}
+
+	withKnown := false
+	cards := app.SummaryCards(scope, withKnown)
+	pos := 0
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for {
+		ClearScreen()
+		known := len(app.SummaryCards(scope, true)) - len(app.SummaryCards(scope, false))
+		fmt.Printf("%s\n\n", barLine(BgMagenta+White+Bold, fmt.Sprintf(" 📌 THẺ %d/%d · đã thuộc %d  (→/←: chuyển, s: xáo, x: đã thuộc, a: hiện/ẩn thẻ đã thuộc, Enter: mở, q: đóng)", min(pos+1, len(cards)), len(cards), known), app.TermWidth))
+		if len(cards) == 0 {
+			fmt.Printf("  %sĐã thuộc hết các thẻ. Nhấn a để xem lại.%s\n", Dim, Reset)
+		} else {
+			card := cards[pos]
+			sec := app.Sections[card.Section]
+			fmt.Printf("  %s%s%s", Bold+Cyan, sec.Title, Reset)
+			if app.KnownSummaries[sec.Title] {
+				fmt.Printf(" %s%s đã thuộc%s", Green, glyphs.Done, Reset)
+			}
+			fmt.Printf("\n\n")
+			for _, line := range card.Lines {
+				for _, l := range wrapVisible(renderInline(line), max(20, app.TermWidth-4)) {
+					fmt.Println("  " + l)
+				}
+			}
+		}
+
+		switch readKey() {
+		case "Right", "Space", "n", "l":
+			if len(cards) > 0 {
+				pos = (pos + 1) % len(cards)
+			}
+		case "Left", "p", "h":
+			if len(cards) > 0 {
+				pos = (pos - 1 + len(cards)) % len(cards)
+			}
+		case "s":
+			rng.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+			pos = 0
+		case "x":
+			if len(cards) == 0 {
+				continue
+			}
+			if app.ToggleKnownSummary(cards[pos].Section) && !withKnown {
+				cards = append(cards[:pos], cards[pos+1:]...)
+				if pos >= len(cards) {
+					pos = 0
+				}
+			}
+			app.SaveState(renderer.PageSize)
+		case "a":
+			withKnown = !withKnown
+			cards, pos = app.SummaryCards(scope, withKnown), 0
+		case "Enter":
+			if len(cards) > 0 {
+				app.GotoSection(cards[pos].Section)
+				renderer.ResetScroll()
+			}
+			return
+		case "q", "Q", "Esc":
+			return
+		}
+	}
+}
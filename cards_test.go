@@ -0,0 +1,82 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// withSummaries adds summary blocks to sections 2 and 5 of the test app.
+func withSummaries(a *App) *App {
+	a.Sections[2].SetContent(a.Sections[2].Content() + "\n> 📌 Summary: Basics first\n> then **practice**\n\nAfter.\n")
+	a.Sections[5].SetContent("\n> 📌 Summary:\n> Exercise recap\n" + a.Sections[5].Content())
+	return a
+}
+
+func TestSectionSummary(t *testing.T) {
+	a := withSummaries(createTestApp())
+	if got, want := a.SectionSummary(2), []string{"Basics first", "then **practice**"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got, want := a.SectionSummary(5), []string{"Exercise recap"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := a.SectionSummary(3); got != nil {
+		t.Errorf("Expected no summary, got %q", got)
+	}
+
+	// A plain quote is not a summary
+	a.Sections[3].SetContent("> Summary: not pinned")
+	if got := a.SectionSummary(3); got != nil {
+		t.Errorf("Expected no summary without the marker, got %q", got)
+	}
+}
+
+func TestSummaryCards(t *testing.T) {
+	a := withSummaries(createTestApp())
+
+	cardSections := func(cards []SummaryCard) []int {
+		var idxs []int
+		for _, c := range cards {
+			idxs = append(idxs, c.Section)
+		}
+		return idxs
+	}
+	if got := cardSections(a.SummaryCards(-1, false)); !reflect.DeepEqual(got, []int{2, 5}) {
+		t.Errorf("Expected cards of sections 2 and 5, got %v", got)
+	}
+	if got := cardSections(a.SummaryCards(4, false)); !reflect.DeepEqual(got, []int{5}) {
+		t.Errorf("Expected the second phase to hold section 5, got %v", got)
+	}
+
+	if !a.ToggleKnownSummary(2) {
+		t.Fatal("Expected section 2 to become known")
+	}
+	if got := cardSections(a.SummaryCards(-1, false)); !reflect.DeepEqual(got, []int{5}) {
+		t.Errorf("Expected known cards to be left out, got %v", got)
+	}
+	if got := cardSections(a.SummaryCards(-1, true)); !reflect.DeepEqual(got, []int{2, 5}) {
+		t.Errorf("Expected known cards with withKnown, got %v", got)
+	}
+	if got := a.CaptureState(0).KnownSummaries; !reflect.DeepEqual(got, []string{"Chapter 1: Basics"}) {
+		t.Errorf("Expected the known card in the state, got %v", got)
+	}
+	if a.ToggleKnownSummary(2) || len(a.KnownSummaries) != 0 {
+		t.Error("Expected a second toggle to unmark the card")
+	}
+
+	a.Skipped = map[string]bool{a.Sections[5].Title: true}
+	if got := cardSections(a.SummaryCards(-1, false)); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("Expected skipped sections to be left out, got %v", got)
+	}
+}
+
+func TestCurrentPhase(t *testing.T) {
+	a := createTestApp()
+	for idx, want := range map[int]int{0: -1, 1: 1, 3: 1, 5: 4} {
+		a.CurrentIdx = idx
+		if got := a.currentPhase(); got != want {
+			t.Errorf("Section %d (%s): expected phase %d, got %d", idx, strings.TrimSpace(a.Sections[idx].Title), want, got)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed templates/*.md
+var pathTemplateFS embed.FS
+
+// PathTemplate is a complete learning path bundled with the binary.
+type PathTemplate struct {
+	// Name is the identifier used with init --template
+	Name string
+	// Title is the human readable template name
+	Title string
+	// File is the embedded file holding the template
+	File string
+}
+
+// pathTemplates lists the bundled learning paths; the first is the default.
+var pathTemplates = []PathTemplate{
+	{"sre", "Senior DevOps/SRE", "templates/default.md"},
+	{"devops", "DevOps Engineer", "templates/devops.md"},
+	{"platform", "Platform Engineering", "templates/platform.md"},
+	{"interview", "Chuẩn bị phỏng vấn SRE/DevOps", "templates/interview.md"},
+}
+
+// Content returns the template markdown with the start date filled in.
+func (t PathTemplate) Content() string {
+	data, _ := pathTemplateFS.ReadFile(t.File)
+	return strings.Replace(string(data), "YYYY-MM-DD", time.Now().Format("2006-01-02"), 1)
+}
+
+// findPathTemplate returns the bundled template with the given name, or nil.
+func findPathTemplate(name string) *PathTemplate {
+	for i := range pathTemplates {
+		if pathTemplates[i].Name == name {
+			return &pathTemplates[i]
+		}
+	}
+	return nil
+}
+
+// defaultTemplateIndex is where community templates are listed.
+const defaultTemplateIndex = "https://raw.githubusercontent.com/vanvuvuong/upskills/main/templates/index.json"
+
+// maxTemplateSize bounds downloads from the template index.
+const maxTemplateSize = 5 << 20
+
+// CommunityTemplate is an entry of the community template index.
+// The index is a JSON document: {"templates": [{...}, ...]}.
+type CommunityTemplate struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// URL is where the raw markdown is downloaded from
+	URL string `json:"url"`
+	// SHA256 is the hex checksum the download must match
+	SHA256 string `json:"sha256"`
+}
+
+// templateClient is used for index and template downloads.
+var templateClient = &http.Client{Timeout: 15 * time.Second}
+
+// fetch downloads url, refusing responses larger than maxTemplateSize.
+func fetch(url string) ([]byte, error) {
+	resp, err := templateClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTemplateSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxTemplateSize {
+		return nil, fmt.Errorf("GET %s: response larger than %d bytes", url, maxTemplateSize)
+	}
+	return data, nil
+}
+
+// FetchTemplateIndex downloads and parses the community template index.
+func FetchTemplateIndex(url string) ([]CommunityTemplate, error) {
+	data, err := fetch(url)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch template index: %w", err)
+	}
+
+	var index struct {
+		Templates []CommunityTemplate `json:"templates"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("invalid template index: %w", err)
+	}
+	return index.Templates, nil
+}
+
+// FetchCommunityTemplate downloads a community template and verifies it
+// against the checksum published in the index.
+func FetchCommunityTemplate(t CommunityTemplate) (string, error) {
+	if t.SHA256 == "" {
+		return "", fmt.Errorf("template %s has no checksum", t.Name)
+	}
+
+	data, err := fetch(t.URL)
+	if err != nil {
+		return "", fmt.Errorf("cannot download template %s: %w", t.Name, err)
+	}
+
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, t.SHA256) {
+		return "", fmt.Errorf("template %s: checksum mismatch (got %s, want %s)", t.Name, got, t.SHA256)
+	}
+	return string(data), nil
+}
+
+// resolveTemplate returns the content of a bundled template, falling back
+// to the community index for unknown names.
+func resolveTemplate(name, indexURL string) (string, error) {
+	if t := findPathTemplate(name); t != nil {
+		return t.Content(), nil
+	}
+
+	community, err := FetchTemplateIndex(indexURL)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range community {
+		if t.Name == name {
+			return FetchCommunityTemplate(t)
+		}
+	}
+	return "", fmt.Errorf("unknown template %q", name)
+}
+
+// listTemplates writes bundled and community templates to out.
+// Community index failures are reported without failing the listing.
+func listTemplates(out io.Writer, indexURL string) {
+	fmt.Fprintln(out, "Templates có sẵn:")
+	for _, t := range pathTemplates {
+		fmt.Fprintf(out, "  %-12s %s\n", t.Name, t.Title)
+	}
+	fmt.Fprintln(out, "\nTopics cho --topics:", strings.Join(topicNames(), ", "))
+
+	community, err := FetchTemplateIndex(indexURL)
+	if err != nil {
+		fmt.Fprintf(out, "\nTemplates cộng đồng: %v\n", err)
+		return
+	}
+	fmt.Fprintln(out, "\nTemplates cộng đồng:")
+	if len(community) == 0 {
+		fmt.Fprintln(out, "  (chưa có)")
+	}
+	for _, t := range community {
+		fmt.Fprintf(out, "  %-12s %s - %s\n", t.Name, t.Title, t.Description)
+	}
+}
+
+// chooseTemplate asks which template to create a new file from.
+// Returns the chosen content, or "" if cancelled.
+func chooseTemplate(inputReader *bufio.Reader) string {
+	fmt.Println("\nChọn template:")
+	for i, t := range pathTemplates {
+		fmt.Printf("  %s%d%s. %s\n", Bold+Cyan, i+1, Reset, t.Title)
+	}
+	communityChoice := len(pathTemplates) + 1
+	fmt.Printf("  %s%d%s. Tải template cộng đồng\n", Bold+Cyan, communityChoice, Reset)
+	fmt.Printf("\nLựa chọn (Enter = 1): ")
+
+	input, _ := inputReader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return pathTemplates[0].Content()
+	}
+
+	num, err := strconv.Atoi(input)
+	switch {
+	case err != nil || num < 1 || num > communityChoice:
+		return ""
+	case num <= len(pathTemplates):
+		return pathTemplates[num-1].Content()
+	}
+
+	fmt.Printf("%sĐang tải danh sách...%s\n", Dim, Reset)
+	community, err := FetchTemplateIndex(config.TemplateIndex)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return ""
+	}
+	if len(community) == 0 {
+		fmt.Println("Chưa có template cộng đồng nào.")
+		return ""
+	}
+
+	for i, t := range community {
+		fmt.Printf("  %s%d%s. %s %s- %s%s\n", Bold+Cyan, i+1, Reset, t.Title, Dim, t.Description, Reset)
+	}
+	fmt.Printf("\nLựa chọn: ")
+	input, _ = inputReader.ReadString('\n')
+	num, err = strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || num < 1 || num > len(community) {
+		return ""
+	}
+
+	content, err := FetchCommunityTemplate(community[num-1])
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return ""
+	}
+	return content
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Template Catalog Tests
+// ============================================================================
+
+func TestBundledPathTemplates(t *testing.T) {
+	for _, tmpl := range pathTemplates {
+		content := tmpl.Content()
+		if content == "" {
+			t.Errorf("Template %s is empty", tmpl.Name)
+			continue
+		}
+
+		if strings.Contains(content, "YYYY-MM-DD") {
+			t.Errorf("Template %s: start date placeholder not filled", tmpl.Name)
+		}
+
+		app := NewApp()
+		app.FileLines = strings.Split(content, "\n")
+		app.ParseSections()
+		if _, total := app.GetTotalProgress(); len(app.Sections) < 3 || total == 0 {
+			t.Errorf("Template %s: expected sections with checkboxes", tmpl.Name)
+		}
+	}
+
+	if findPathTemplate("devops") == nil || findPathTemplate("nope") != nil {
+		t.Error("Unexpected findPathTemplate result")
+	}
+}
+
+// newTemplateServer serves a community index with one template whose
+// published checksum is sum.
+func newTemplateServer(t *testing.T, body, sum string) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"templates":[{"name":"k8s-cka","title":"CKA","url":%q,"sha256":%q}]}`, server.URL+"/cka.md", sum)
+	})
+	mux.HandleFunc("/cka.md", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+	return server
+}
+
+func TestFetchCommunityTemplate(t *testing.T) {
+	body := "# CKA\n\n- [ ] Pass the exam\n"
+	sum := sha256.Sum256([]byte(body))
+	server := newTemplateServer(t, body, hex.EncodeToString(sum[:]))
+
+	index, err := FetchTemplateIndex(server.URL + "/index.json")
+	if err != nil || len(index) != 1 || index[0].Name != "k8s-cka" {
+		t.Fatalf("Unexpected index %v, err %v", index, err)
+	}
+
+	content, err := resolveTemplate("k8s-cka", server.URL+"/index.json")
+	if err != nil || content != body {
+		t.Errorf("Expected verified template, got %q, err %v", content, err)
+	}
+
+	if _, err := resolveTemplate("missing", server.URL+"/index.json"); err == nil {
+		t.Error("Expected error for unknown template")
+	}
+}
+
+func TestFetchCommunityTemplateChecksumMismatch(t *testing.T) {
+	server := newTemplateServer(t, "# Tampered\n", strings.Repeat("0", 64))
+
+	if _, err := resolveTemplate("k8s-cka", server.URL+"/index.json"); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("Expected checksum mismatch error, got %v", err)
+	}
+
+	if _, err := FetchCommunityTemplate(CommunityTemplate{Name: "x", URL: server.URL + "/cka.md"}); err == nil {
+		t.Error("Expected error for template without checksum")
+	}
+}
+
+func TestFetchTemplateIndexErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := FetchTemplateIndex(server.URL + "/index.json"); err == nil {
+		t.Error("Expected error for missing index")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// childBarWidth is the width of the progress bars in the child summary.
+const childBarWidth = 10
+
+// ChildSections returns the direct children of section idx: the sections
+// of its subtree that are not nested under another section of it. A
+// child may skip levels ("##" followed by "####").
+func (a *App) ChildSections(idx int) []int {
+	if idx < 0 || idx >= len(a.Sections) {
+		return nil
+	}
+	var children []int
+	level, end := 0, a.subtreeEnd(idx)
+	for i := idx + 1; i < end; i++ {
+		if level == 0 || a.Sections[i].Level <= level {
+			children = append(children, i)
+			level = a.Sections[i].Level
+		}
+	}
+	return children
+}
+
+// SubtreeProgress sums the progress of section idx and its descendants.
+func (a *App) SubtreeProgress(idx int) (checked, total int) {
+	for i, end := idx, a.subtreeEnd(idx); i < end; i++ {
+		c, t := a.GetProgress(i)
+		checked += c
+		total += t
+	}
+	return checked, total
+}
+
+// GotoChild moves to the n-th (1-based) child of the current section.
+// Returns true if such a child exists.
+func (a *App) GotoChild(n int) bool {
+	children := a.ChildSections(a.CurrentIdx)
+	if n < 1 || n > len(children) {
+		return false
+	}
+	return a.GotoSection(children[n-1])
+}
+
+// childSummaryLines renders the children of section idx below its
+// content, numbered for "g d", each with the progress of its subtree, so
+// a phase page whose own content is empty lists what it holds. Nil
+// without children.
+func (a *App) childSummaryLines(idx int) []string {
+	children := a.ChildSections(idx)
+	if len(children) == 0 {
+		return nil
+	}
+	lines := []string{"", fmt.Sprintf("%s📂 %d section con%s %s(g d hoặc số + g d để vào)%s", Bold, len(children), Reset, Dim, Reset)}
+	for n, i := range children {
+		line := fmt.Sprintf("  %s%2d.%s %s", Cyan, n+1, Reset, a.Sections[i].Title)
+		if checked, total := a.SubtreeProgress(i); total > 0 {
+			filled := childBarWidth * checked / total
+			bar := Green + strings.Repeat(glyphs.BarFull, filled) + Dim + strings.Repeat(glyphs.BarEmpty, childBarWidth-filled) + Reset
+			line += fmt.Sprintf("  [%s] %s%d/%d%s", bar, Dim, checked, total, Reset)
+			if checked == total {
+				line += " " + Green + glyphs.Done + Reset
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// accessibleChildSummary is childSummaryLines as plain sentences for
+// accessible mode.
+func (a *App) accessibleChildSummary(idx int) []string {
+	children := a.ChildSections(idx)
+	if len(children) == 0 {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("%d section con, số rồi g d để vào:", len(children))}
+	for n, i := range children {
+		line := fmt.Sprintf("%d. %s.", n+1, a.Sections[i].Title)
+		if checked, total := a.SubtreeProgress(i); total > 0 {
+			line += fmt.Sprintf(" Đã xong %d trên %d.", checked, total)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// handleChildren jumps into a child of the current section, picked from
+// the list when there are several.
+func handleChildren() {
+	children := app.ChildSections(app.CurrentIdx)
+	if len(children) == 0 {
+		notify(SeverityWarning, "Section này không có section con")
+		return
+	}
+	titles := make([]string, len(children))
+	for n, i := range children {
+		titles[n] = app.Sections[i].Title
+	}
+	if n := pickItem("Section con", titles); n >= 0 {
+		resetScrollIf(app.GotoChild(n + 1))
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChildSections(t *testing.T) {
+	app := createTestApp()
+
+	tests := []struct {
+		idx  int
+		want []int
+	}{
+		{0, []int{1, 4}},
+		{1, []int{2, 3}},
+		{2, nil},
+		{4, []int{5}},
+		{-1, nil},
+	}
+	for _, tt := range tests {
+		if got := app.ChildSections(tt.idx); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ChildSections(%d) = %v, want %v", tt.idx, got, tt.want)
+		}
+	}
+}
+
+func TestChildSectionsSkippedLevel(t *testing.T) {
+	app := NewApp()
+	app.FileLines = strings.Split("# Top\n## Phase\n#### Deep\n### Mid\n#### Under mid\n## Next", "\n")
+	app.ParseSections()
+
+	if got, want := app.ChildSections(1), []int{2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ChildSections(1) = %v, want %v", got, want)
+	}
+}
+
+func TestSubtreeProgress(t *testing.T) {
+	app := createTestApp()
+
+	if checked, total := app.SubtreeProgress(1); checked != 1 || total != 4 {
+		t.Errorf("SubtreeProgress(1) = %d/%d, want 1/4", checked, total)
+	}
+	if checked, total := app.SubtreeProgress(0); checked != 3 || total != 6 {
+		t.Errorf("SubtreeProgress(0) = %d/%d, want 3/6", checked, total)
+	}
+}
+
+func TestGotoChild(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 1
+
+	if !app.GotoChild(2) || app.CurrentIdx != 3 {
+		t.Errorf("GotoChild(2) should move to section 3, at %d", app.CurrentIdx)
+	}
+	if app.GotoChild(1) {
+		t.Error("GotoChild should fail on a section without children")
+	}
+}
+
+func TestChildSummaryLines(t *testing.T) {
+	app := createTestApp()
+
+	lines := app.childSummaryLines(4)
+	if len(lines) != 3 {
+		t.Fatalf("Expected blank, header and one child, got %q", lines)
+	}
+	if !strings.Contains(lines[2], "Exercise 1") || !strings.Contains(lines[2], "2/2") || !strings.Contains(lines[2], glyphs.Done) {
+		t.Errorf("Expected the finished child with its progress, got %q", lines[2])
+	}
+	if app.childSummaryLines(5) != nil {
+		t.Error("Expected no summary for a section without children")
+	}
+
+	accessible := app.accessibleChildSummary(1)
+	if len(accessible) != 3 || accessible[1] != "1. Chapter 1: Basics. Đã xong 1 trên 3." {
+		t.Errorf("Unexpected accessible summary %q", accessible)
+	}
+}
+
+func TestVisibleContentListsChildren(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 1
+	r := &Renderer{App: app, PageSize: 50, TermWidth: 80}
+
+	rendered, _, _ := r.visibleContent(app.Sections[1].Content(), 80)
+	if !strings.Contains(strings.Join(rendered, "\n"), "Chapter 2: Advanced") {
+		t.Errorf("Expected the children below the content, got %q", rendered)
+	}
+	if r.maxScrollOffset() != 0 {
+		t.Errorf("Expected everything on one page, max offset %d", r.maxScrollOffset())
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// clipboardTool is an external command that reads text on stdin and
+// places it in the system clipboard.
+type clipboardTool struct {
+	name string
+	args []string
+	// available reports whether the tool can be used in this session
+	available func() bool
+}
+
+// clipboardTools lists the native clipboard commands in order of preference.
+var clipboardTools = []clipboardTool{
+	{"pbcopy", nil, func() bool { return true }},
+	{"wl-copy", nil, func() bool { return os.Getenv("WAYLAND_DISPLAY") != "" }},
+	{"xclip", []string{"-selection", "clipboard"}, func() bool { return os.Getenv("DISPLAY") != "" }},
+	{"xsel", []string{"--clipboard", "--input"}, func() bool { return os.Getenv("DISPLAY") != "" }},
+}
+
+// osc52Sequence builds the OSC 52 escape sequence that asks the terminal
+// to set its clipboard. Inside tmux the sequence is wrapped in a DCS
+// passthrough so it reaches the outer terminal.
+func osc52Sequence(text string) string {
+	seq := "\033]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if os.Getenv("TMUX") != "" {
+		seq = "\033Ptmux;\033" + seq + "\033\\"
+	}
+	return seq
+}
+
+// CopyToClipboard places text in the system clipboard.
+// Over SSH the terminal's clipboard is used via OSC 52, since tools on the
+// remote host cannot reach the local clipboard. Otherwise the first working
+// native tool is used, with OSC 52 as the final fallback.
+// Returns the name of the method used.
+func CopyToClipboard(text string) (string, error) {
+	if os.Getenv("SSH_TTY") == "" {
+		for _, tool := range clipboardTools {
+			if !tool.available() {
+				continue
+			}
+			if _, err := exec.LookPath(tool.name); err != nil {
+				continue
+			}
+			cmd := exec.Command(tool.name, tool.args...)
+			cmd.Stdin = strings.NewReader(text)
+			if err := cmd.Run(); err == nil {
+				return tool.name, nil
+			}
+		}
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return "", fmt.Errorf("no clipboard available: %w", err)
+	}
+	defer tty.Close()
+	if _, err := tty.WriteString(osc52Sequence(text)); err != nil {
+		return "", err
+	}
+	return "OSC 52", nil
+}
+
+// sectionMarkdown returns the raw markdown of a section including its header.
+func sectionMarkdown(sec *Section) string {
+	return strings.Join(sec.HeaderLines(), "\n") + "\n" + sec.Content()
+}
+
+// handleYank lets the user copy the current section, a note or a
+// fenced code block to the clipboard.
+func handleYank() {
+	sec := app.GetCurrentSection()
+	if sec == nil {
+		return
+	}
+	notes := extractNotes(sec.Content())
+	blocks := extractCodeBlocks(sec.Content())
+
+	ClearScreen()
+	fmt.Printf("%s📋 COPY VÀO CLIPBOARD%s\n", Bold+Cyan, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	fmt.Printf("\n  %ss%s - Section hiện tại\n", Cyan, Reset)
+	if len(notes) > 0 {
+		fmt.Printf("  %sn%s - Ghi chú (%d)\n", Cyan, Reset, len(notes))
+	}
+	if len(blocks) > 0 {
+		fmt.Printf("  %sc%s - Code block (%d)\n", Cyan, Reset, len(blocks))
+	}
+	fmt.Printf("  %sl%s - Liên kết đến vị trí này (sre-learn open <link>)\n", Cyan, Reset)
+	fmt.Printf("  %sq%s - Quay lại\n", Cyan, Reset)
+
+	text := ""
+	switch readKey() {
+	case "l":
+		copyDeepLink()
+		return
+	case "s", "y":
+		text = sectionMarkdown(sec)
+	case "n":
+		items := make([]string, len(notes))
+		for i, note := range notes {
+			items[i] = noteBody(note)
+		}
+		if i := pickItem("Ghi chú", items); i >= 0 {
+			text = items[i]
+		}
+	case "c":
+		items := make([]string, len(blocks))
+		for i, block := range blocks {
+			items[i] = block.Code
+		}
+		if i := pickItem("Code block", items); i >= 0 {
+			text = items[i]
+		}
+	}
+
+	if text == "" {
+		return
+	}
+
+	if method, err := CopyToClipboard(text); err != nil {
+		notify(SeverityError, "Lỗi copy: %v", err)
+	} else {
+		notify(SeveritySuccess, "Đã copy %d dòng (%s)", strings.Count(text, "\n")+1, method)
+	}
+}
+
+// pickItem lists items with a one-line preview and returns the index of
+// the one chosen by number, or -1 if cancelled. A single item is chosen
+// directly.
+func pickItem(label string, items []string) int {
+	if len(items) == 0 {
+		return -1
+	}
+	if len(items) == 1 {
+		return 0
+	}
+
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+
+	fmt.Println()
+	for i, item := range items {
+		preview := strings.ReplaceAll(strings.TrimSpace(item), "\n", " ⏎ ")
+		fmt.Printf("  %s%2d.%s %s\n", Cyan, i+1, Reset, truncateVisible(preview, 70))
+	}
+	fmt.Printf("\n%sChọn %s (1-%d) hoặc Enter để hủy:%s ", Bold, strings.ToLower(label), len(items), Reset)
+
+	input, _ := bufio.NewReader(keyboard).ReadString('\n')
+	num, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || num < 1 || num > len(items) {
+		return -1
+	}
+	return num - 1
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Clipboard Tests
+// ============================================================================
+
+func TestOSC52Sequence(t *testing.T) {
+	t.Setenv("TMUX", "")
+
+	seq := osc52Sequence("kubectl get pods")
+	expected := "\033]52;c;" + base64.StdEncoding.EncodeToString([]byte("kubectl get pods")) + "\a"
+
+	if seq != expected {
+		t.Errorf("Expected %q, got %q", expected, seq)
+	}
+}
+
+func TestOSC52SequenceTmux(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+
+	seq := osc52Sequence("x")
+
+	if !strings.HasPrefix(seq, "\033Ptmux;") || !strings.HasSuffix(seq, "\033\\") {
+		t.Errorf("Expected tmux passthrough wrapping, got %q", seq)
+	}
+}
+
+func TestSectionMarkdown(t *testing.T) {
+	sec := &Section{Title: "Chapter", Level: 3, content: "\n- [ ] Task"}
+
+	if got := sectionMarkdown(sec); got != "### Chapter\n\n- [ ] Task" {
+		t.Errorf("Unexpected section markdown: %q", got)
+	}
+}
+
+func TestNoteBody(t *testing.T) {
+	note := "> **Ghi chú [2025-01-01 10:00]:** First line\n> second line"
+
+	if got := noteBody(note); got != "First line\nsecond line" {
+		t.Errorf("Unexpected note body: %q", got)
+	}
+}
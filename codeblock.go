@@ -0,0 +1,54 @@
+package main
+
+import "strings"
+
+// CodeBlock is a fenced code block found in section content.
+type CodeBlock struct {
+	// Lang is the info string after the opening fence (e.g. "bash")
+	Lang string
+	// Code is the block body without the fences
+	Code string
+	// Line is the content line index of the opening fence
+	Line int
+}
+
+// fenceRun returns the leading run of backticks or tildes of a line.
+func fenceRun(line string) string {
+	if line == "" || (line[0] != '`' && line[0] != '~') {
+		return ""
+	}
+	n := 0
+	for n < len(line) && line[n] == line[0] {
+		n++
+	}
+	return line[:n]
+}
+
+// extractCodeBlocks returns all fenced (``` or ~~~) code blocks in content.
+// An unterminated block runs to the end of the content.
+func extractCodeBlocks(content string) []CodeBlock {
+	var blocks []CodeBlock
+	var current *CodeBlock
+	var body []string
+
+	lines := strings.Split(content, "\n")
+	for i, ml := range ScanLines(lines) {
+		switch ml.Kind {
+		case LineFenceOpen:
+			current = &CodeBlock{Lang: ml.Text, Line: i}
+			body = nil
+		case LineCode:
+			body = append(body, lines[i])
+		case LineFenceClose:
+			current.Code = strings.Join(body, "\n")
+			blocks = append(blocks, *current)
+			current = nil
+		}
+	}
+
+	if current != nil {
+		current.Code = strings.Join(body, "\n")
+		blocks = append(blocks, *current)
+	}
+	return blocks
+}
@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+// ============================================================================
+// Code Block Tests
+// ============================================================================
+
+func TestExtractCodeBlocks(t *testing.T) {
+	content := "Intro\n\n```bash\nkubectl get pods\nkubectl get svc\n```\n\nText\n\n~~~\nplain\n~~~"
+
+	blocks := extractCodeBlocks(content)
+
+	if len(blocks) != 2 {
+		t.Fatalf("Expected 2 code blocks, got %d", len(blocks))
+	}
+
+	if blocks[0].Lang != "bash" {
+		t.Errorf("Expected lang 'bash', got '%s'", blocks[0].Lang)
+	}
+
+	if blocks[0].Code != "kubectl get pods\nkubectl get svc" {
+		t.Errorf("Unexpected code: %q", blocks[0].Code)
+	}
+
+	if blocks[0].Line != 2 {
+		t.Errorf("Expected fence at line 2, got %d", blocks[0].Line)
+	}
+
+	if blocks[1].Lang != "" || blocks[1].Code != "plain" {
+		t.Errorf("Unexpected second block: %+v", blocks[1])
+	}
+}
+
+func TestExtractCodeBlocksNestedFenceMarkers(t *testing.T) {
+	content := "````md\n```\nnot a fence end for ~~~\n```\n````"
+
+	blocks := extractCodeBlocks(content)
+
+	if len(blocks) != 1 {
+		t.Fatalf("Expected 1 code block, got %d", len(blocks))
+	}
+}
+
+func TestExtractCodeBlocksUnterminated(t *testing.T) {
+	blocks := extractCodeBlocks("```sh\necho hi")
+
+	if len(blocks) != 1 || blocks[0].Code != "echo hi" {
+		t.Errorf("Expected unterminated block to run to end, got %+v", blocks)
+	}
+}
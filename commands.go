@@ -0,0 +1,183 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Command is a non-interactive subcommand, e.g. "sre-learn cat 3".
+type Command struct {
+	// Name is the word selecting the command on the command line
+	Name string
+	// Usage is the one-line synopsis shown in help output
+	Usage string
+	// NoDocument marks commands that run without loading the document
+	NoDocument bool
+	// Run executes the command with its own arguments
+	Run func(args []string, out io.Writer) error
+}
+
+// commands lists all available subcommands.
+var commands = []Command{
+	{
+		Name:  "cat",
+		Usage: "cat [--plain|--color] <section-number|title>  In một section ra stdout",
+		Run:   runCat,
+	},
+	{
+		Name:  "history",
+		Usage: "history [--day YYYY-MM-DD|today|yesterday|tuesday] [--days N]  Các mục đã hoàn thành",
+		Run:   runHistory,
+	},
+	{
+		Name:  "badge",
+		Usage: "badge [--json|--markdown] [--label text] [-o file]  Badge tiến độ (SVG, shields.io endpoint)",
+		Run:   runBadge,
+	},
+	{
+		Name:  "github",
+		Usage: "github [--repo owner/name] push <section> | sync  Tạo issue cho mục chưa xong, đồng bộ issue đã đóng (GITHUB_TOKEN)",
+		Run:   runGitHub,
+	},
+	{
+		Name:  "note",
+		Usage: `note "text"  Ghi nhanh vào section Inbox (tạo nếu chưa có), không mở TUI; không có text thì đọc stdin`,
+		Run:   runNote,
+	},
+	{
+		Name:  "export",
+		Usage: "export --to jira|linear [--dry-run] <section> | export pdf|epub [-o file]  Đẩy mục chưa xong thành ticket, xuất PDF/EPUB",
+		Run:   runExport,
+	},
+	{
+		Name:  "serve",
+		Usage: "serve [--addr host:port]  Giao diện web, JSON API (/api/...) và Prometheus /metrics, dùng chung file với TUI",
+		Run:   runServe,
+	},
+	{
+		Name:  "doctor",
+		Usage: "doctor [--fix]  Kiểm tra markdown (checkbox sai, tiêu đề trùng, code block chưa đóng, ghi chú hỏng, header nhảy cấp) và sửa tự động",
+		Run:   runDoctor,
+	},
+	{
+		Name:       "import",
+		Usage:      "import --from obsidian|notion [--title text] [--force] <folder> [file]  Chuyển vault Obsidian / export Notion thành một lộ trình",
+		NoDocument: true,
+		Run:        runImport,
+	},
+	{
+		Name:  "bundle",
+		Usage: "bundle [-o file.srelearn]  Đóng gói file, ghi chú, file đính kèm và tiến độ thành một file để chuyển máy",
+		Run:   runBundle,
+	},
+	{
+		Name:       "unbundle",
+		Usage:      "unbundle [--force] <file.srelearn> [dir]  Giải nén bundle: file vào dir, tiến độ vào thư mục trạng thái",
+		NoDocument: true,
+		Run:        runUnbundle,
+	},
+	{
+		Name:       "plugins",
+		Usage:      "plugins  Liệt kê plugin (phím, transformer, định dạng export) trong ~/.config/sre-learn/plugins",
+		NoDocument: true,
+		Run:        runPlugins,
+	},
+	{
+		Name:  "update",
+		Usage: "update --from <url|path> [--dry-run] [--yes]  Gộp phiên bản mới của lộ trình, giữ tiến độ và ghi chú (bản cũ: <file>.bak)",
+		Run:   runUpdate,
+	},
+	{
+		Name:       "init",
+		Usage:      "init [--template name|--topics k8s,linux,...] [--weeks N] [--force|--list] [file]  Tạo lộ trình học mới",
+		NoDocument: true,
+		Run:        runInit,
+	},
+}
+
+// findCommand returns the subcommand with the given name, or nil.
+func findCommand(name string) *Command {
+	for i := range commands {
+		if commands[i].Name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// runCommand dispatches args[0] to its subcommand, loading the document
+// first unless the command does not need it.
+func runCommand(args []string, profile string, out io.Writer) error {
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		return fmt.Errorf("unknown command %q (see --help)", args[0])
+	}
+
+	if !cmd.NoDocument {
+		if err := openDocument(profile); err != nil {
+			return err
+		}
+	}
+	return cmd.Run(args[1:], out)
+}
+
+// printUsage prints flags and subcommands for --help.
+func printUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: sre-learn [flags] [command]\n\nFlags:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(out, "\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %s\n", cmd.Usage)
+	}
+	fmt.Fprintf(out, "  %s\n", openUsage)
+}
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// runCat prints one section. Output is styled with ANSI colors when
+// stdout is a terminal (or --color is given) and raw markdown otherwise,
+// so it can be piped into less -R, grep or pbcopy.
+func runCat(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("cat", flag.ContinueOnError)
+	plain := fs.Bool("plain", false, "print raw markdown without ANSI colors")
+	color := fs.Bool("color", false, "force ANSI colors even when piped")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: sre-learn cat [--plain|--color] <section-number|title>")
+	}
+
+	query := strings.Join(fs.Args(), " ")
+	idx := app.FindSection(query)
+	if idx < 0 {
+		return fmt.Errorf("section %q not found", query)
+	}
+
+	useColor := *color || (!*plain && isTerminal(os.Stdout))
+	writeSection(out, app.Sections[idx], useColor)
+	return nil
+}
+
+// writeSection writes a section header and its content to out,
+// rendered with ANSI styling or as plain markdown.
+func writeSection(out io.Writer, sec Section, useColor bool) {
+	if !useColor {
+		fmt.Fprintln(out, sectionMarkdown(&sec))
+		return
+	}
+
+	header := strings.Repeat("#", sec.Level) + " " + sec.Title
+	fmt.Fprintln(out, Bold+Cyan+header+Reset)
+	for _, line := range RenderLines(app.contentLines(sec.Content()), app.TermWidth) {
+		fmt.Fprintln(out, line)
+	}
+}
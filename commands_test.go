@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// withTestApp points the global app at the sample document for the
+// duration of a test.
+func withTestApp(t *testing.T) *App {
+	t.Helper()
+	saved := app
+	app = createTestApp()
+	t.Cleanup(func() { app = saved })
+	return app
+}
+
+// ============================================================================
+// Command Tests
+// ============================================================================
+
+func TestFindCommand(t *testing.T) {
+	if findCommand("cat") == nil {
+		t.Error("Expected 'cat' command to be registered")
+	}
+
+	if findCommand("nonexistent") != nil {
+		t.Error("Expected nil for unknown command")
+	}
+}
+
+func TestRunCommandUnknown(t *testing.T) {
+	var out bytes.Buffer
+
+	if err := runCommand([]string{"nonexistent"}, "", &out); err == nil {
+		t.Error("Expected error for unknown command")
+	}
+}
+
+func TestRunCatPlain(t *testing.T) {
+	withTestApp(t)
+	var out bytes.Buffer
+
+	if err := runCat([]string{"--plain", "Chapter 1"}, &out); err != nil {
+		t.Fatalf("runCat failed: %v", err)
+	}
+
+	result := out.String()
+	if !strings.HasPrefix(result, "### Chapter 1: Basics\n") {
+		t.Errorf("Expected markdown header, got %q", result)
+	}
+
+	if !strings.Contains(result, "- [ ] Task one") {
+		t.Error("Expected raw checkbox markdown in plain output")
+	}
+
+	if strings.Contains(result, "\033[") {
+		t.Error("Expected no ANSI codes in plain output")
+	}
+}
+
+func TestRunCatColor(t *testing.T) {
+	withTestApp(t)
+	var out bytes.Buffer
+
+	if err := runCat([]string{"--color", "3"}, &out); err != nil {
+		t.Fatalf("runCat failed: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "☐") {
+		t.Error("Expected rendered checkbox in color output")
+	}
+}
+
+func TestRunCatNotFound(t *testing.T) {
+	withTestApp(t)
+	var out bytes.Buffer
+
+	if err := runCat([]string{"no such section"}, &out); err == nil {
+		t.Error("Expected error for unknown section")
+	}
+
+	if err := runCat(nil, &out); err == nil {
+		t.Error("Expected usage error without arguments")
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds user preferences loaded from the config file.
+// The file uses the same key=value format as the state file.
+type Config struct {
+	// ScrollStep is the number of lines j/k scroll at a time
+	ScrollStep int
+	// BookMode starts the viewer with continuous scrolling across sections
+	BookMode bool
+	// Accessible starts the viewer in screen reader mode (--accessible)
+	Accessible bool
+	// PreviewWrites shows the diff of note edits before they are written
+	PreviewWrites bool
+	// GlossaryUnderline underlines the terms defined in the glossary
+	GlossaryUnderline bool
+	// InlineEditor writes notes in the built-in editor even when $EDITOR
+	// is set
+	InlineEditor bool
+	// SectionDepth is the deepest header level that starts a section
+	// (section_depth, 1-6); deeper headers render inline
+	SectionDepth int
+	// LargeFileSize is the document size in bytes from which the
+	// large-file mode is used (large_file_mb, 0 never uses it)
+	LargeFileSize int64
+	// TrashRetention is how long deleted notes are kept for restoring
+	// (trash_retention_days, 0 keeps them forever)
+	TrashRetention time.Duration
+	// Glyphs selects the symbol set: "auto" (detected), "unicode" or "ascii"
+	Glyphs string
+	// Theme is the name of the color theme
+	Theme string
+	// Colors overrides theme colors (color.<name>=<color>)
+	Colors map[string]string
+	// ColorDepth is "auto" (detected), "8", "256" or "truecolor"
+	ColorDepth string
+	// RunTimeout limits how long an executed code block may run
+	RunTimeout time.Duration
+	// TemplateIndex is the URL of the community template index
+	TemplateIndex string
+	// Keys remaps key binding actions to key names (key.<action>=<keys>)
+	Keys map[string][]string
+	// ExportFields maps ticket fields to templates per tracker
+	// (export.<tracker>.<field>=<template>, e.g. export.jira.labels={phase})
+	ExportFields map[string]map[string]string
+	// TTSEngine is the speech synthesizer used by read-aloud ("auto" picks
+	// the first installed one)
+	TTSEngine string
+	// TTSURL is an HTTP TTS API: the text is posted and the audio played
+	TTSURL string
+	// LookupURL is an HTTP API for term lookups; {term} is replaced by
+	// the term
+	LookupURL string
+	// Encryption encrypts notes, state, trash, quizzes and attachments
+	Encryption bool
+	// EncryptionKeyFile holds the encryption secret instead of a
+	// passphrase typed at startup
+	EncryptionKeyFile string
+	// ReadingWPM is the reading speed for reading time estimates, in
+	// words per minute
+	ReadingWPM int
+	// Hooks maps events to shell commands (hook.<event>=<command>, e.g.
+	// hook.on-save=git commit -qam progress)
+	Hooks map[string]string
+}
+
+// DefaultConfig returns the built-in preferences.
+func DefaultConfig() Config {
+	return Config{
+		ScrollStep:     3,
+		ReadingWPM:     defaultReadingWPM,
+		RunTimeout:     60 * time.Second,
+		TemplateIndex:  defaultTemplateIndex,
+		TTSEngine:      "auto",
+		Glyphs:         "auto",
+		TrashRetention: 30 * 24 * time.Hour,
+		LargeFileSize:  defaultLargeFileSize,
+		SectionDepth:   maxHeaderLevel,
+		Theme:          "default",
+		ColorDepth:     "auto",
+	}
+}
+
+// configPath returns the config file location, honoring XDG_CONFIG_HOME
+// on Linux via os.UserConfigDir.
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine config directory: %w", err)
+	}
+	return filepath.Join(dir, "sre-learn", "config"), nil
+}
+
+// LoadConfig reads preferences from path on top of the defaults.
+// A missing file yields the defaults; invalid values are reported.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("cannot read config %s: %w", path, err)
+	}
+
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return cfg, fmt.Errorf("%s:%d: expected key=value", path, n+1)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "scroll_step":
+			step, err := strconv.Atoi(value)
+			if err != nil || step < 1 {
+				return cfg, fmt.Errorf("%s:%d: scroll_step must be a positive number", path, n+1)
+			}
+			cfg.ScrollStep = step
+		case "reading_wpm":
+			wpm, err := strconv.Atoi(value)
+			if err != nil || wpm < 1 {
+				return cfg, fmt.Errorf("%s:%d: reading_wpm must be a positive number of words per minute", path, n+1)
+			}
+			cfg.ReadingWPM = wpm
+		case "book_mode":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return cfg, fmt.Errorf("%s:%d: book_mode must be true or false", path, n+1)
+			}
+			cfg.BookMode = enabled
+		case "trash_retention_days":
+			days, err := strconv.Atoi(value)
+			if err != nil || days < 0 {
+				return cfg, fmt.Errorf("%s:%d: trash_retention_days must be a number of days (0 keeps notes forever)", path, n+1)
+			}
+			cfg.TrashRetention = time.Duration(days) * 24 * time.Hour
+		case "large_file_mb":
+			mb, err := strconv.Atoi(value)
+			if err != nil || mb < 0 {
+				return cfg, fmt.Errorf("%s:%d: large_file_mb must be a size in megabytes (0 turns the large-file mode off)", path, n+1)
+			}
+			cfg.LargeFileSize = int64(mb) << 20
+		case "section_depth":
+			depth, err := strconv.Atoi(value)
+			if err != nil || depth < 1 || depth > maxHeaderLevel {
+				return cfg, fmt.Errorf("%s:%d: section_depth must be a header level from 1 to %d", path, n+1, maxHeaderLevel)
+			}
+			cfg.SectionDepth = depth
+		case "preview_writes":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return cfg, fmt.Errorf("%s:%d: preview_writes must be true or false", path, n+1)
+			}
+			cfg.PreviewWrites = enabled
+		case "glossary_underline":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return cfg, fmt.Errorf("%s:%d: glossary_underline must be true or false", path, n+1)
+			}
+			cfg.GlossaryUnderline = enabled
+		case "inline_editor":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return cfg, fmt.Errorf("%s:%d: inline_editor must be true or false", path, n+1)
+			}
+			cfg.InlineEditor = enabled
+		case "accessible":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return cfg, fmt.Errorf("%s:%d: accessible must be true or false", path, n+1)
+			}
+			cfg.Accessible = enabled
+		case "run_timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return cfg, fmt.Errorf("%s:%d: run_timeout must be a duration like 30s", path, n+1)
+			}
+			cfg.RunTimeout = timeout
+		case "template_index":
+			if !strings.HasPrefix(value, "https://") && !strings.HasPrefix(value, "http://") {
+				return cfg, fmt.Errorf("%s:%d: template_index must be an http(s) URL", path, n+1)
+			}
+			cfg.TemplateIndex = value
+		case "glyphs":
+			if value != "auto" && value != "unicode" && value != "ascii" {
+				return cfg, fmt.Errorf("%s:%d: glyphs must be auto, unicode or ascii", path, n+1)
+			}
+			cfg.Glyphs = value
+		case "theme":
+			if _, ok := themes[value]; !ok {
+				return cfg, fmt.Errorf("%s:%d: theme must be one of %s", path, n+1, strings.Join(themeNames(), ", "))
+			}
+			cfg.Theme = value
+		case "color_depth":
+			if value != "auto" && value != "8" && value != "256" && value != "truecolor" {
+				return cfg, fmt.Errorf("%s:%d: color_depth must be auto, 8, 256 or truecolor", path, n+1)
+			}
+			cfg.ColorDepth = value
+		case "tts_engine":
+			known := false
+			for _, name := range ttsEngineNames() {
+				known = known || name == value
+			}
+			if !known {
+				return cfg, fmt.Errorf("%s:%d: tts_engine must be one of %s", path, n+1, strings.Join(ttsEngineNames(), ", "))
+			}
+			cfg.TTSEngine = value
+		case "tts_url":
+			if !strings.HasPrefix(value, "https://") && !strings.HasPrefix(value, "http://") {
+				return cfg, fmt.Errorf("%s:%d: tts_url must be an http(s) URL", path, n+1)
+			}
+			cfg.TTSURL = value
+		case "encryption":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return cfg, fmt.Errorf("%s:%d: encryption must be true or false", path, n+1)
+			}
+			cfg.Encryption = enabled
+		case "encryption_keyfile":
+			cfg.EncryptionKeyFile = cleanAttachmentPath(value)
+		case "lookup_url":
+			if !strings.HasPrefix(value, "https://") && !strings.HasPrefix(value, "http://") {
+				return cfg, fmt.Errorf("%s:%d: lookup_url must be an http(s) URL", path, n+1)
+			}
+			cfg.LookupURL = value
+		default:
+			if action, ok := strings.CutPrefix(key, "key."); ok {
+				var keys []string
+				for _, spec := range strings.Fields(value) {
+					keys = append(keys, parseKeySpec(spec))
+				}
+				if len(keys) == 0 {
+					return cfg, fmt.Errorf("%s:%d: %s needs at least one key", path, n+1, key)
+				}
+				if cfg.Keys == nil {
+					cfg.Keys = map[string][]string{}
+				}
+				cfg.Keys[action] = keys
+			} else if name, ok := strings.CutPrefix(key, "color."); ok {
+				if _, known := paletteVars[name]; !known {
+					return cfg, fmt.Errorf("%s:%d: %s must be color.<%s>", path, n+1, key, strings.Join(colorNames, "|"))
+				}
+				if _, err := parseColor(value); err != nil {
+					return cfg, fmt.Errorf("%s:%d: %s: %w", path, n+1, key, err)
+				}
+				if cfg.Colors == nil {
+					cfg.Colors = map[string]string{}
+				}
+				cfg.Colors[name] = value
+			} else if event, ok := strings.CutPrefix(key, "hook."); ok {
+				known := false
+				for _, name := range hookEvents {
+					known = known || name == event
+				}
+				if !known || value == "" {
+					return cfg, fmt.Errorf("%s:%d: %s must be hook.<%s>=<command>", path, n+1, key, strings.Join(hookEvents, "|"))
+				}
+				if cfg.Hooks == nil {
+					cfg.Hooks = map[string]string{}
+				}
+				cfg.Hooks[event] = value
+			} else if rest, ok := strings.CutPrefix(key, "export."); ok {
+				tracker, field, ok := strings.Cut(rest, ".")
+				if _, known := taskExporters[tracker]; !ok || !known || field == "" {
+					return cfg, fmt.Errorf("%s:%d: %s must be export.<%s>.<field>", path, n+1, key, strings.Join(exporterNames(), "|"))
+				}
+				if cfg.ExportFields == nil {
+					cfg.ExportFields = map[string]map[string]string{}
+				}
+				if cfg.ExportFields[tracker] == nil {
+					cfg.ExportFields[tracker] = map[string]string{}
+				}
+				cfg.ExportFields[tracker][field] = value
+			}
+		}
+	}
+
+	if _, err := cfg.Keymap(); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Keymap returns the default key bindings with the configured overrides.
+func (c Config) Keymap() ([]KeyBinding, error) {
+	return applyKeyOverrides(defaultKeyBindings(), c.Keys)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// ============================================================================
+// Config Tests
+// ============================================================================
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "config"))
+	if err != nil {
+		t.Fatalf("Expected no error for missing config, got %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Errorf("Expected default config, got %+v", cfg)
+	}
+}
+
+func TestLoadConfigScrollStep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "# preferences\nscroll_step = 7\nreading_wpm=250\nunknown_key=1\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if cfg.ScrollStep != 7 {
+		t.Errorf("Expected ScrollStep 7, got %d", cfg.ScrollStep)
+	}
+	if cfg.ReadingWPM != 250 {
+		t.Errorf("Expected ReadingWPM 250, got %d", cfg.ReadingWPM)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	for _, content := range []string{"scroll_step=0\n", "scroll_step=abc\n", "reading_wpm=0\n", "book_mode=maybe\n", "inline_editor=maybe\n", "large_file_mb=-1\n", "section_depth=7\n", "section_depth=0\n", "template_index=ftp://x\n", "tts_engine=sam\n", "tts_url=localhost\n", "no equals sign\n"} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("Expected error for config %q", content)
+		}
+	}
+}
+
+func TestLoadConfigKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("key.next_section = l Right\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if keys := cfg.Keys["next_section"]; len(keys) != 2 || keys[0] != "l" || keys[1] != "Right" {
+		t.Errorf("Expected keys [l Right], got %v", keys)
+	}
+
+	for _, content := range []string{"key.nope=x\n", "key.next_section=j\n", "key.quit=F13\n", "key.quit=\n"} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("Expected error for %q", content)
+		}
+	}
+}
+
+func TestLoadConfigExportFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	os.WriteFile(path, []byte("export.jira.labels=sre-learn,{phase}\n"), 0o644)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ExportFields["jira"]["labels"] != "sre-learn,{phase}" {
+		t.Errorf("Unexpected export fields %v", cfg.ExportFields)
+	}
+
+	os.WriteFile(path, []byte("export.trello.name={title}\n"), 0o644)
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected error for an unknown tracker")
+	}
+}
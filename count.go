@@ -0,0 +1,123 @@
+package main
+
+import "fmt"
+
+// maxCount caps the count typed before a key.
+const maxCount = 9999
+
+// isCountDigit reports whether key continues the count typed so far: 1-9
+// start a count, 0 only continues one.
+func isCountDigit(key string, count int) bool {
+	return len(key) == 1 && (key[0] >= '1' && key[0] <= '9' || key[0] == '0' && count > 0)
+}
+
+// showCount draws the count typed so far on the bottom line.
+func showCount(count int) {
+	fmt.Printf("\033[%d;1H", max(1, app.TermHeight))
+	fmt.Print(barLine(BgMagenta+White+Bold, fmt.Sprintf(" %d …  (Esc: hủy)", count), app.TermWidth))
+}
+
+// readCount reads the count typed before a key ("5n", "20j", "3x") and
+// the key after it; count is 0 when none was typed. A digit that is bound
+// on its own (the breadcrumb keys 1-9) runs when no key follows within
+// chordTimeout, like a bound chord prefix. Esc cancels the count.
+func readCount() (count int, key string) {
+	key = readKey()
+	for isCountDigit(key, count) {
+		typed := min(count*10+int(key[0]-'0'), maxCount)
+		showCount(typed)
+		next := ""
+		if count == 0 && findBinding(keymap, key) != nil {
+			var ok bool
+			if next, ok = readKeyTimeout(chordTimeout); !ok {
+				return 0, key
+			}
+		} else {
+			next = readKey()
+		}
+		count, key = typed, next
+	}
+	if count > 0 && key == "Esc" {
+		return 0, ""
+	}
+	return count, key
+}
+
+// repeatMove runs move up to n times, stopping when it fails, and reports
+// whether it moved at all.
+func repeatMove(n int, move func() bool) bool {
+	moved := false
+	for i := 0; i < n && move(); i++ {
+		moved = true
+	}
+	return moved
+}
+
+// runCounted runs the binding with the count typed before its key:
+// scrolling goes count lines (or pages), section movements count steps,
+// gg/G go to section count, g d to child count, : to line count and x cycles the count-th checkbox. Other
+// actions, and any action without a count, run once.
+func runCounted(kb *KeyBinding, key string, count int) {
+	if count == 0 {
+		kb.Run(key)
+		return
+	}
+	switch kb.Action {
+	case "scroll_down":
+		renderer.Scroll(count)
+	case "scroll_up":
+		renderer.Scroll(-count)
+	case "half_page_down":
+		renderer.Scroll(count * renderer.PageSize / 2)
+	case "half_page_up":
+		renderer.Scroll(-count * renderer.PageSize / 2)
+	case "page_down":
+		renderer.Scroll(count * renderer.PageSize)
+	case "page_up":
+		renderer.Scroll(-count * renderer.PageSize)
+	case "next_section":
+		if !repeatMove(count, app.NextSection) {
+			notify(SeverityWarning, "Đã ở section cuối")
+			return
+		}
+		renderer.ResetScroll()
+	case "prev_section":
+		if !repeatMove(count, app.PrevSection) {
+			notify(SeverityWarning, "Đã ở section đầu")
+			return
+		}
+		renderer.ResetScroll()
+	case "next_sibling":
+		resetScrollIf(repeatMove(count, app.NextSibling))
+	case "prev_sibling":
+		resetScrollIf(repeatMove(count, app.PrevSibling))
+	case "next_phase":
+		resetScrollIf(repeatMove(count, app.NextPhase))
+	case "prev_phase":
+		resetScrollIf(repeatMove(count, app.PrevPhase))
+	case "parent":
+		resetScrollIf(repeatMove(count, app.GotoParent))
+	case "goto_line":
+		if !renderer.GotoLine(app.CurrentIdx, count-1) {
+			notify(SeverityWarning, "Không có dòng %d", count)
+		}
+	case "children":
+		if !app.GotoChild(count) {
+			notify(SeverityWarning, "Không có section con %d", count)
+			return
+		}
+		renderer.ResetScroll()
+	case "goto_first", "goto_last":
+		if !app.GotoSection(count - 1) {
+			notify(SeverityWarning, "Không có section %d", count)
+			return
+		}
+		renderer.ResetScroll()
+	case "toggle_checkbox":
+		if writable() && !toggleCheckboxItem(count, "") {
+			notify(SeverityWarning, "Không có checkbox %d", count)
+		}
+	default:
+		kb.Run(key)
+	}
+}
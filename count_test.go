@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestIsCountDigit(t *testing.T) {
+	tests := []struct {
+		key   string
+		count int
+		want  bool
+	}{
+		{"5", 0, true},
+		{"0", 0, false},
+		{"0", 2, true},
+		{"j", 3, false},
+		{"Ctrl+d", 0, false},
+	}
+	for _, tt := range tests {
+		if got := isCountDigit(tt.key, tt.count); got != tt.want {
+			t.Errorf("isCountDigit(%q, %d) = %v, expected %v", tt.key, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestRepeatMove(t *testing.T) {
+	calls := 0
+	move := func() bool { calls++; return calls <= 2 }
+	if !repeatMove(5, move) || calls != 3 {
+		t.Errorf("Expected to stop after the failed third move, got %d calls", calls)
+	}
+	if repeatMove(3, func() bool { return false }) {
+		t.Error("Expected no move to be reported")
+	}
+}
+
+func TestRunCounted(t *testing.T) {
+	a := withTestApp(t)
+	saved := renderer
+	renderer = NewRenderer(a)
+	t.Cleanup(func() { renderer = saved })
+
+	bindings := defaultKeyBindings()
+	runCounted(findBinding(bindings, "n"), "n", 3)
+	if a.CurrentIdx != 3 {
+		t.Errorf("3n: expected section 3, got %d", a.CurrentIdx)
+	}
+	runCounted(findBinding(bindings, "n"), "n", 50)
+	if a.CurrentIdx != len(a.Sections)-1 {
+		t.Errorf("50n: expected to stop at the last section, got %d", a.CurrentIdx)
+	}
+	runCounted(findBinding(bindings, "G"), "G", 2)
+	if a.CurrentIdx != 1 {
+		t.Errorf("2G: expected section 1, got %d", a.CurrentIdx)
+	}
+	runCounted(findBinding(bindings, "p"), "p", 0)
+	if a.CurrentIdx != 0 {
+		t.Errorf("p without a count: expected section 0, got %d", a.CurrentIdx)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// internalLinkRegex matches a link to another section at the start of
+// the text: "[see SLOs](#giai-doan-2-slo)".
+var internalLinkRegex = regexp.MustCompile(`^\[([^\]]+)\]\(#([^)\s]+)\)`)
+
+// internalLinksRegex finds all links to sections in a text.
+var internalLinksRegex = regexp.MustCompile(`\[([^\]]+)\]\(#([^)\s]+)\)`)
+
+// vietnameseLetters maps the accented Vietnamese letters to their base
+// letter, so anchors can be typed on any keyboard.
+var vietnameseLetters = func() map[rune]rune {
+	m := make(map[rune]rune)
+	for base, accented := range map[rune]string{
+		'a': "àáảãạăằắẳẵặâầấẩẫậ",
+		'e': "èéẻẽẹêềếểễệ",
+		'i': "ìíỉĩị",
+		'o': "òóỏõọôồốổỗộơờớởỡợ",
+		'u': "ùúủũụưừứửữự",
+		'y': "ỳýỷỹỵ",
+		'd': "đ",
+	} {
+		for _, r := range accented {
+			m[r] = base
+		}
+	}
+	return m
+}()
+
+// Slugify turns a section title into its anchor: lowercase ASCII letters
+// and digits separated by single dashes, e.g. "Giai đoạn 2: SLO" becomes
+// "giai-doan-2-slo".
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if base, ok := vietnameseLetters[r]; ok {
+			r = base
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// Anchor returns the anchor of section idx: its {#id} attribute, or the
+// slug of its title.
+func (a *App) Anchor(idx int) string {
+	if id := a.Sections[idx].ID; id != "" {
+		return id
+	}
+	return Slugify(a.Sections[idx].Title)
+}
+
+// anchorIndex returns the section with the given anchor, or -1. An {#id}
+// attribute wins over a title slug.
+func (a *App) anchorIndex(anchor string) int {
+	for i, sec := range a.Sections {
+		if sec.ID == anchor {
+			return i
+		}
+	}
+	for i, sec := range a.Sections {
+		if Slugify(sec.Title) == anchor {
+			return i
+		}
+	}
+	return -1
+}
+
+// SectionLink is an internal link found in a section.
+type SectionLink struct {
+	Text   string
+	Anchor string
+	// Target is the linked section, or -1 if the anchor is unknown
+	Target int
+}
+
+// SectionLinks returns the internal links of section idx in order.
+func (a *App) SectionLinks(idx int) []SectionLink {
+	var links []SectionLink
+	for _, m := range internalLinksRegex.FindAllStringSubmatch(a.Sections[idx].Content(), -1) {
+		links = append(links, SectionLink{Text: m[1], Anchor: m[2], Target: a.anchorIndex(m[2])})
+	}
+	return links
+}
+
+// Backlinks returns the sections linking to section idx.
+func (a *App) Backlinks(idx int) []int {
+	var sections []int
+	for i := range a.Sections {
+		if i == idx {
+			continue
+		}
+		for _, link := range a.SectionLinks(i) {
+			if link.Target == idx {
+				sections = append(sections, i)
+				break
+			}
+		}
+	}
+	return sections
+}
+
+// handleLinks lists the links of the current section and the sections
+// linking to it, and jumps to the chosen one. A single link is followed
+// directly.
+func handleLinks() {
+	links := app.SectionLinks(app.CurrentIdx)
+	backlinks := app.Backlinks(app.CurrentIdx)
+	if len(links) == 1 && len(backlinks) == 0 {
+		followLink(links[0])
+		return
+	}
+	if len(links) == 0 && len(backlinks) == 0 {
+		notify(SeverityInfo, "Section không có liên kết nào (dạng [text](#anchor))")
+		return
+	}
+
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	ClearScreen()
+	fmt.Printf("%s%s LIÊN KẾT - %s%s\n", Bold+Cyan, glyphs.Link, app.Sections[app.CurrentIdx].Title, Reset)
+	fmt.Printf("%s#%s%s\n", Dim, app.Anchor(app.CurrentIdx), Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+
+	n := 0
+	if len(links) > 0 {
+		fmt.Printf("\n%sLiên kết trong section:%s\n", Yellow, Reset)
+		for _, link := range links {
+			n++
+			target := Red + "không tìm thấy #" + link.Anchor + Reset
+			if link.Target >= 0 {
+				target = app.Sections[link.Target].Title
+			}
+			fmt.Printf("  %s%d%s. %s %s%s%s %s\n", Cyan, n, Reset, link.Text, Dim, glyphs.Link, Reset, target)
+		}
+	}
+	if len(backlinks) > 0 {
+		fmt.Printf("\n%sBacklinks (section tham chiếu đến đây):%s\n", Yellow, Reset)
+		for _, idx := range backlinks {
+			n++
+			fmt.Printf("  %s%d%s. %s\n", Cyan, n, Reset, app.Sections[idx].Title)
+		}
+	}
+
+	fmt.Printf("\nNhập số (1-%d) hoặc Enter để quay lại: ", n)
+	input, _ := bufio.NewReader(keyboard).ReadString('\n')
+	num, err := strconv.Atoi(strings.TrimSpace(input))
+	switch {
+	case err != nil || num < 1 || num > n:
+	case num <= len(links):
+		followLink(links[num-1])
+	default:
+		app.GotoSection(backlinks[num-1-len(links)])
+		renderer.ResetScroll()
+	}
+}
+
+// followLink jumps to the section a link points to.
+func followLink(link SectionLink) {
+	if link.Target < 0 {
+		notify(SeverityWarning, "Không tìm thấy section #%s", link.Anchor)
+		return
+	}
+	app.GotoSection(link.Target)
+	renderer.ResetScroll()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Giai đoạn 2: SLO":             "giai-doan-2-slo",
+		"Tuần 1-2: Linux & Networking": "tuan-1-2-linux-networking",
+		"  Kubernetes (K8s)  ":         "kubernetes-k8s",
+		"Đo lường — SLI/SLO":           "do-luong-sli-slo",
+	}
+	for title, want := range tests {
+		if got := Slugify(title); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestSectionLinksAndBacklinks(t *testing.T) {
+	app := createTestApp()
+	app.Sections[2].SetContent(app.Sections[2].Content() + "\nXem [bài tập](#exercise-1) và [cũ](#khong-co).")
+	app.Sections[3].SetContent(app.Sections[3].Content() + "\n[Bài tập](#exercise-1)")
+
+	exercise := app.FindSection("Exercise 1")
+	links := app.SectionLinks(2)
+	if len(links) != 2 || links[0].Target != exercise || links[1].Target != -1 {
+		t.Errorf("SectionLinks = %+v", links)
+	}
+	if got := app.Backlinks(exercise); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("Backlinks = %v", got)
+	}
+	if got := app.FindSection("#giai-doan-2-practice"); got != 4 {
+		t.Errorf("FindSection by slug = %d, want 4", got)
+	}
+}
+
+func TestAnchorPrefersID(t *testing.T) {
+	app := NewApp()
+	app.FileLines = strings.Split("# Intro\n\n## SLO {#slo}\n\n## Slo\n", "\n")
+	app.ParseSections()
+
+	if got := app.Anchor(1); got != "slo" {
+		t.Errorf("Anchor = %q", got)
+	}
+	if got := app.anchorIndex("slo"); got != 1 {
+		t.Errorf("anchorIndex(slo) = %d, want the {#slo} section", got)
+	}
+}
+
+func TestRenderInternalLink(t *testing.T) {
+	got := renderInline("xem [SLOs](#giai-doan-2-slo) nhé")
+	want := "xem " + Underline + Blue + "SLOs" + Reset + Dim + glyphs.Link + Reset + " nhé"
+	if got != want {
+		t.Errorf("renderInline = %q, want %q", got, want)
+	}
+}
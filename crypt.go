@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// With "encryption=true", notes, the state store, the trash, quizzes and
+// attachments are encrypted with AES-256-GCM under a key derived from a
+// passphrase (or a key file) with PBKDF2-HMAC-SHA256. Plaintext is kept
+// in memory only; the rest of the document stays readable markdown.
+
+// encryptedMagic starts every encrypted file, followed by the salt, the
+// nonce and the sealed data.
+const encryptedMagic = "sre-learn:aes-gcm:v1\n"
+
+// sealedNoteMarker follows the banner of an encrypted note; the rest of
+// the line is the base64 of the sealed note.
+const sealedNoteMarker = "🔒 "
+
+const (
+	pbkdf2Iterations = 200_000
+	saltSize         = 16
+	keySize          = 32
+)
+
+// vaultCheckText is sealed in the vault file to verify the passphrase.
+const vaultCheckText = "sre-learn vault"
+
+// errNoVault is returned when reading encrypted data with encryption off.
+var errNoVault = errors.New("file is encrypted: set encryption=true in the config")
+
+// errWrongPassphrase is returned when encrypted data cannot be opened.
+var errWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+
+// vault holds the key while encryption is on; nil keeps files plaintext.
+var vault *Vault
+
+// tempAttachments are decrypted attachment copies, removed on quit.
+var tempAttachments []string
+
+// pbkdf2Key derives a key from a secret with PBKDF2-HMAC-SHA256 (RFC 8018).
+func pbkdf2Key(secret, salt []byte, iterations, size int) []byte {
+	prf := hmac.New(sha256.New, secret)
+	var key []byte
+	for block := uint32(1); len(key) < size; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.Write(prf, binary.BigEndian, block)
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for n := 1; n < iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:size]
+}
+
+// Vault seals and opens data with keys derived from one secret. New data
+// is sealed under the vault's salt; keys of other salts are derived on
+// demand and cached.
+type Vault struct {
+	secret []byte
+	salt   []byte
+	keys   map[string][]byte
+	// sealed remembers the sealed form of notes, so unchanged notes are
+	// written back byte for byte
+	sealed map[string]string
+}
+
+// NewVault returns a vault for secret sealing under salt.
+func NewVault(secret, salt []byte) *Vault {
+	return &Vault{secret: secret, salt: salt, keys: map[string][]byte{}, sealed: map[string]string{}}
+}
+
+// aead returns the cipher for data sealed under salt.
+func (v *Vault) aead(salt []byte) (cipher.AEAD, error) {
+	key, ok := v.keys[string(salt)]
+	if !ok {
+		key = pbkdf2Key(v.secret, salt, pbkdf2Iterations, keySize)
+		v.keys[string(salt)] = key
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal encrypts data: magic, salt, nonce and ciphertext.
+func (v *Vault) Seal(data []byte) ([]byte, error) {
+	gcm, err := v.aead(v.salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(encryptedMagic), v.salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, []byte(encryptedMagic)), nil
+}
+
+// isEncrypted reports whether data was produced by Seal.
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedMagic))
+}
+
+// Open decrypts data produced by Seal.
+func (v *Vault) Open(data []byte) ([]byte, error) {
+	rest, ok := bytes.CutPrefix(data, []byte(encryptedMagic))
+	if !ok || len(rest) < saltSize {
+		return nil, errWrongPassphrase
+	}
+	gcm, err := v.aead(rest[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	rest = rest[saltSize:]
+	if len(rest) < gcm.NonceSize() {
+		return nil, errWrongPassphrase
+	}
+	plain, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], []byte(encryptedMagic))
+	if err != nil {
+		return nil, errWrongPassphrase
+	}
+	return plain, nil
+}
+
+// openSecure decrypts data if it is encrypted.
+func openSecure(data []byte) ([]byte, error) {
+	if !isEncrypted(data) {
+		return data, nil
+	}
+	if vault == nil {
+		return nil, errNoVault
+	}
+	return vault.Open(data)
+}
+
+// readSecure reads a file, decrypting it if it is encrypted. Plaintext
+// files are read as they are, so turning encryption on migrates them on
+// their next write.
+func readSecure(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return openSecure(data)
+}
+
+// writeSecure writes a file, encrypted when encryption is on.
+func writeSecure(path string, data []byte, perm os.FileMode) error {
+	if vault != nil {
+		sealed, err := vault.Seal(data)
+		if err != nil {
+			return err
+		}
+		data = sealed
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// sealFile encrypts a file written by another program in place.
+func sealFile(path string) error {
+	if vault == nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || isEncrypted(data) {
+		return err
+	}
+	return writeSecure(path, data, 0o600)
+}
+
+// noteEnd returns the index after the last line of the note starting at
+// lines[start]: the banner and the quote lines that follow it.
+func noteEnd(lines []string, start int) int {
+	end := start + 1
+	for end < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end]), ">") &&
+		!noteTimestampRegex.MatchString(lines[end]) {
+		end++
+	}
+	return end
+}
+
+// SealNotes returns lines with every note collapsed into its banner
+// followed by the sealed note. Notes outside code blocks are sealed; the
+// rest of the document is left as it is.
+func (v *Vault) SealNotes(lines []string) ([]string, error) {
+	scanned := ScanLines(lines)
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		m := noteTimestampRegex.FindStringIndex(lines[i])
+		if m == nil || scanned[i].Kind != LineText || strings.HasPrefix(lines[i][m[1]:], " "+sealedNoteMarker) {
+			out = append(out, lines[i])
+			continue
+		}
+		end := noteEnd(lines, i)
+		plain := strings.Join(lines[i:end], "\n")
+		line, ok := v.sealed[plain]
+		if !ok {
+			sealed, err := v.Seal([]byte(plain))
+			if err != nil {
+				return nil, err
+			}
+			line = lines[i][:m[1]] + " " + sealedNoteMarker + base64.StdEncoding.EncodeToString(sealed)
+			v.sealed[plain] = line
+		}
+		out = append(out, line)
+		i = end - 1
+	}
+	return out, nil
+}
+
+// OpenNotes returns lines with every sealed note restored.
+func (v *Vault) OpenNotes(lines []string) ([]string, error) {
+	var out []string
+	for n, line := range lines {
+		m := noteTimestampRegex.FindStringIndex(line)
+		if m == nil || !strings.HasPrefix(line[m[1]:], " "+sealedNoteMarker) {
+			out = append(out, line)
+			continue
+		}
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(line[m[1]:], " "+sealedNoteMarker))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid sealed note: %w", n+1, err)
+		}
+		plain, err := v.Open(sealed)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		v.sealed[string(plain)] = line
+		out = append(out, strings.Split(string(plain), "\n")...)
+	}
+	return out, nil
+}
+
+// decodeDocument returns the document text of the file content: sealed
+// notes are opened when encryption is on.
+func decodeDocument(data string) (string, error) {
+	if vault == nil {
+		return data, nil
+	}
+	lines, err := vault.OpenNotes(strings.Split(data, "\n"))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// encodeDocument returns the file content of the document text: notes
+// are sealed when encryption is on.
+func encodeDocument(lines []string) (string, error) {
+	if vault == nil {
+		return strings.Join(lines, "\n"), nil
+	}
+	sealed, err := vault.SealNotes(lines)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(sealed, "\n"), nil
+}
+
+// openSecureAttachment returns a path the opener can read: encrypted
+// attachments are decrypted to a private temporary copy, removed on quit.
+func openSecureAttachment(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !isEncrypted(data) {
+		return path, err
+	}
+	plain, err := openSecure(data)
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp("", "sre-learn-")
+	if err != nil {
+		return "", err
+	}
+	tmp := filepath.Join(dir, filepath.Base(path))
+	if err := os.WriteFile(tmp, plain, 0o600); err != nil {
+		return "", err
+	}
+	tempAttachments = append(tempAttachments, dir)
+	return tmp, nil
+}
+
+// removeTempAttachments deletes the decrypted attachment copies.
+func removeTempAttachments() {
+	for _, dir := range tempAttachments {
+		os.RemoveAll(dir)
+	}
+	tempAttachments = nil
+}
+
+// vaultPath returns the file holding the salt and the passphrase check,
+// next to the config file.
+func vaultPath() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "vault"), nil
+}
+
+// UnlockVault returns the vault for secret. The vault file at path keeps
+// the salt and a sealed check text that verifies the secret; it is created
+// with a new salt the first time.
+func UnlockVault(path string, secret []byte) (*Vault, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		v := NewVault(secret, salt)
+		check, err := v.Seal([]byte(vaultCheckText))
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		return v, os.WriteFile(path, check, 0o600)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !isEncrypted(data) || len(data) < len(encryptedMagic)+saltSize {
+		return nil, fmt.Errorf("invalid vault file %s", path)
+	}
+	v := NewVault(secret, data[len(encryptedMagic):len(encryptedMagic)+saltSize])
+	if check, err := v.Open(data); err != nil || string(check) != vaultCheckText {
+		return nil, errWrongPassphrase
+	}
+	return v, nil
+}
+
+// readPassphrase prompts for the passphrase on the terminal without
+// echoing it.
+func readPassphrase(prompt string) ([]byte, error) {
+	if !isTerminal(os.Stdin) {
+		return nil, fmt.Errorf("encryption needs a passphrase: set SRE_LEARN_PASSPHRASE, encryption_keyfile or run in a terminal")
+	}
+	fmt.Print(prompt)
+	exec.Command("stty", "-F", "/dev/tty", "-echo").Run()
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	exec.Command("stty", "-F", "/dev/tty", "echo").Run()
+	fmt.Println()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return []byte(strings.TrimRight(line, "\r\n")), nil
+}
+
+// encryptionSecret returns the secret: the key file, SRE_LEARN_PASSPHRASE
+// or a passphrase typed at the prompt (twice when the vault is new).
+func encryptionSecret(cfg Config, isNew bool) ([]byte, error) {
+	if cfg.EncryptionKeyFile != "" {
+		data, err := os.ReadFile(cfg.EncryptionKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read key file: %w", err)
+		}
+		return bytes.TrimSpace(data), nil
+	}
+	if secret := os.Getenv("SRE_LEARN_PASSPHRASE"); secret != "" {
+		return []byte(secret), nil
+	}
+	secret, err := readPassphrase("🔑 Mật khẩu mã hóa: ")
+	if err != nil || !isNew {
+		return secret, err
+	}
+	again, err := readPassphrase("🔑 Nhập lại mật khẩu: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(secret, again) {
+		return nil, fmt.Errorf("passphrases do not match")
+	}
+	return secret, nil
+}
+
+// setupEncryption unlocks the vault when encryption is configured.
+func setupEncryption(cfg Config) error {
+	if !cfg.Encryption {
+		return nil
+	}
+	path, err := vaultPath()
+	if err != nil {
+		return err
+	}
+	secret, err := encryptionSecret(cfg, !fileExists(path))
+	if err != nil {
+		return err
+	}
+	if len(secret) == 0 {
+		return fmt.Errorf("empty passphrase")
+	}
+	vault, err = UnlockVault(path, secret)
+	return err
+}
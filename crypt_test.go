@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withVault turns encryption on for a test.
+func withVault(t *testing.T, secret string) *Vault {
+	t.Helper()
+	saved := vault
+	vault = NewVault([]byte(secret), []byte("0123456789abcdef"))
+	t.Cleanup(func() { vault = saved })
+	return vault
+}
+
+func TestPBKDF2Key(t *testing.T) {
+	// RFC 7914 section 11 test vector for PBKDF2-HMAC-SHA256
+	got := hex.EncodeToString(pbkdf2Key([]byte("passwd"), []byte("salt"), 1, 64))
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if got != want {
+		t.Errorf("pbkdf2Key = %s", got)
+	}
+}
+
+func TestVaultSealOpen(t *testing.T) {
+	v := NewVault([]byte("s3cret"), []byte("0123456789abcdef"))
+	sealed, err := v.Seal([]byte("incident details"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted(sealed) || strings.Contains(string(sealed), "incident") {
+		t.Errorf("sealed = %q", sealed)
+	}
+	if plain, err := v.Open(sealed); err != nil || string(plain) != "incident details" {
+		t.Errorf("Open = %q, %v", plain, err)
+	}
+	if _, err := NewVault([]byte("guess"), nil).Open(sealed); !errors.Is(err, errWrongPassphrase) {
+		t.Errorf("Expected a wrong passphrase error, got %v", err)
+	}
+}
+
+func TestSealNotes(t *testing.T) {
+	v := NewVault([]byte("s3cret"), []byte("0123456789abcdef"))
+	lines := strings.Split("## Incident\n\n- [ ] Postmortem\n\n> **Ghi chú [2025-01-31 10:00]:** db-prod-3 failover\n> root cause: disk\n\n```\n> **Ghi chú [2025-01-31 10:00]:** in code\n```", "\n")
+
+	sealed, err := v.SealNotes(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.Join(sealed, "\n")
+	if strings.Contains(text, "db-prod-3") || strings.Contains(text, "root cause") {
+		t.Errorf("Expected the note to be sealed, got %q", text)
+	}
+	if !strings.Contains(text, "> **Ghi chú [2025-01-31 10:00]:** "+sealedNoteMarker) || !strings.Contains(text, "in code") {
+		t.Errorf("sealed = %q", text)
+	}
+
+	opened, err := v.OpenNotes(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(opened, "\n") != strings.Join(lines, "\n") {
+		t.Errorf("OpenNotes = %q", opened)
+	}
+	// Unchanged notes are written back as they were
+	if again, _ := v.SealNotes(opened); strings.Join(again, "\n") != text {
+		t.Error("Expected sealing an unchanged note to be stable")
+	}
+}
+
+func TestUnlockVault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault")
+	if _, err := UnlockVault(path, []byte("s3cret")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnlockVault(path, []byte("s3cret")); err != nil {
+		t.Errorf("Expected the passphrase to unlock the vault, got %v", err)
+	}
+	if _, err := UnlockVault(path, []byte("guess")); !errors.Is(err, errWrongPassphrase) {
+		t.Errorf("Expected a wrong passphrase error, got %v", err)
+	}
+}
+
+func TestEncryptedDocumentAndState(t *testing.T) {
+	app := withTestApp(t)
+	dir := t.TempDir()
+	app.FilePath = filepath.Join(dir, "path.md")
+	app.StateFile = filepath.Join(dir, "state")
+	withVault(t, "s3cret")
+
+	app.CurrentIdx = 2
+	app.AddNote("vpn-gw-01 credentials rotated")
+	app.UpdateFileSection(2)
+	if err := app.SaveFile(); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SaveState(20); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(app.FilePath)
+	if strings.Contains(string(data), "vpn-gw-01") || !strings.Contains(string(data), "- [x] Task two completed") {
+		t.Errorf("Expected only the note to be encrypted, got %q", data)
+	}
+	if state, _ := os.ReadFile(app.StateFile); !isEncrypted(state) {
+		t.Error("Expected the state to be encrypted")
+	}
+	if app.ChangedOnDisk() {
+		t.Error("Expected the saved file to match what is on disk")
+	}
+
+	loaded := NewApp()
+	loaded.FilePath, loaded.StateFile = app.FilePath, app.StateFile
+	if err := loaded.LoadFile(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loaded.LoadState(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(loaded.FileContent, "vpn-gw-01 credentials rotated") || loaded.CurrentIdx != 2 {
+		t.Errorf("Expected the note and position back, got idx %d", loaded.CurrentIdx)
+	}
+
+	vault = nil
+	if _, err := loaded.LoadState(); !errors.Is(err, errNoVault) {
+		t.Errorf("Expected an error without the key, got %v", err)
+	}
+	if !fileExists(app.StateFile) {
+		t.Error("Expected an unreadable state to be left in place")
+	}
+}
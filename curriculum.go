@@ -0,0 +1,237 @@
+package main
+
+import (
+	"embed"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed templates/topics/*.md
+var topicFS embed.FS
+
+// defaultWeeks is the plan length used when --weeks is not given.
+const defaultWeeks = 12
+
+// TopicModule is one unit of a topic template, e.g. "Networking (8h)".
+type TopicModule struct {
+	// Title is the module name without the hour estimate
+	Title string
+	// Hours is the estimated study time
+	Hours int
+	// Body is the module content (starter checklist)
+	Body string
+}
+
+// TopicTemplate is a bundled topic used to generate a learning path.
+type TopicTemplate struct {
+	// Name is the identifier used with --topics (file name without .md)
+	Name string
+	// Title is the human readable topic name
+	Title string
+	// Description is the short text under the topic title
+	Description string
+	// Modules are the topic units in study order
+	Modules []TopicModule
+}
+
+// Hours returns the total estimated hours of the topic.
+func (t TopicTemplate) Hours() int {
+	total := 0
+	for _, m := range t.Modules {
+		total += m.Hours
+	}
+	return total
+}
+
+// moduleHeaderRegex matches "## Module title (8h)".
+var moduleHeaderRegex = regexp.MustCompile(`^##\s+(.+?)\s*\((\d+)h\)\s*$`)
+
+// parseTopicTemplate parses a topic template: a "# Title" line, a short
+// description and "## Module (Nh)" units with their checklists.
+func parseTopicTemplate(name, content string) (TopicTemplate, error) {
+	topic := TopicTemplate{Name: name}
+	var current *TopicModule
+	var desc, body []string
+
+	flush := func() {
+		if current != nil {
+			current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+			topic.Modules = append(topic.Modules, *current)
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case topic.Title == "" && strings.HasPrefix(line, "# "):
+			topic.Title = strings.TrimSpace(line[2:])
+		case strings.HasPrefix(line, "## "):
+			matches := moduleHeaderRegex.FindStringSubmatch(line)
+			if matches == nil {
+				return topic, fmt.Errorf("topic %s: module %q has no hour estimate like (8h)", name, line)
+			}
+			flush()
+			hours, _ := strconv.Atoi(matches[2])
+			current = &TopicModule{Title: matches[1], Hours: hours}
+			body = nil
+		case current != nil:
+			body = append(body, line)
+		default:
+			desc = append(desc, line)
+		}
+	}
+	flush()
+
+	if topic.Title == "" {
+		return topic, fmt.Errorf("topic %s: missing # title", name)
+	}
+	if len(topic.Modules) == 0 {
+		return topic, fmt.Errorf("topic %s: no modules", name)
+	}
+	topic.Description = strings.TrimSpace(strings.Join(desc, "\n"))
+	return topic, nil
+}
+
+// topicNames returns the names of all bundled topic templates.
+func topicNames() []string {
+	entries, _ := topicFS.ReadDir("templates/topics")
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".md"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadTopic loads a bundled topic template by name.
+func loadTopic(name string) (TopicTemplate, error) {
+	data, err := topicFS.ReadFile(path.Join("templates/topics", name+".md"))
+	if err != nil {
+		return TopicTemplate{}, fmt.Errorf("unknown topic %q (available: %s)", name, strings.Join(topicNames(), ", "))
+	}
+	return parseTopicTemplate(name, string(data))
+}
+
+// weekRange formats a week span as "Tuần 3" or "Tuần 3-4".
+func weekRange(from, to int) string {
+	if from == to {
+		return fmt.Sprintf("Tuần %d", from)
+	}
+	return fmt.Sprintf("Tuần %d-%d", from, to)
+}
+
+// GenerateCurriculum builds a learning-path document from topics spread
+// over the given number of weeks. Each topic becomes a phase and each
+// module a weekly section; weeks are allotted in proportion to the
+// estimated hours.
+func GenerateCurriculum(topics []TopicTemplate, weeks int, start time.Time) string {
+	totalHours := 0
+	titles := make([]string, len(topics))
+	for i, t := range topics {
+		totalHours += t.Hours()
+		titles[i] = t.Title
+	}
+	weeks = max(1, weeks)
+
+	// weekAt maps a point in cumulative study hours to a 1-based week
+	weekAt := func(hours int, end bool) int {
+		if totalHours == 0 {
+			return 1
+		}
+		pos := float64(hours) / float64(totalHours) * float64(weeks)
+		w := int(pos) + 1
+		if end && pos == float64(int(pos)) {
+			w = int(pos)
+		}
+		return max(1, min(w, weeks))
+	}
+
+	var intro, phases strings.Builder
+	elapsed := 0
+	for i, t := range topics {
+		phaseStart := weekAt(elapsed, false)
+		var modules strings.Builder
+		for _, m := range t.Modules {
+			from, to := weekAt(elapsed, false), weekAt(elapsed+m.Hours, true)
+			elapsed += m.Hours
+			fmt.Fprintf(&modules, "### %s: %s\n\n_Ước tính: %d giờ_\n\n%s\n\n", weekRange(from, max(from, to)), m.Title, m.Hours, m.Body)
+		}
+		phaseEnd := max(phaseStart, weekAt(elapsed, true))
+
+		fmt.Fprintf(&intro, "- **%s** — %s (%s, ~%d giờ)\n", t.Title, t.Description, weekRange(phaseStart, phaseEnd), t.Hours())
+		fmt.Fprintf(&phases, "## Giai đoạn %d: %s (%s)\n\n", i+1, t.Title, weekRange(phaseStart, phaseEnd))
+		if t.Description != "" {
+			fmt.Fprintf(&phases, "%s\n\n", t.Description)
+		}
+		phases.WriteString(modules.String())
+		phases.WriteString("---\n\n")
+	}
+
+	var doc strings.Builder
+	fmt.Fprintf(&doc, "# LỘ TRÌNH HỌC TẬP - %s\n\n", strings.Join(titles, ", "))
+	fmt.Fprintf(&doc, "_Bắt đầu: %s · %d tuần · ~%d giờ (~%d giờ/tuần)_\n\n---\n\n",
+		start.Format("2006-01-02"), weeks, totalHours, (totalHours+weeks-1)/weeks)
+	fmt.Fprintf(&doc, "## Giới thiệu & Kế hoạch\n\n### Chủ đề\n\n%s\n---\n\n", intro.String())
+	doc.WriteString(phases.String())
+	return strings.TrimRight(doc.String(), "-\n") + "\n"
+}
+
+// runInit writes a new learning-path file, either generated from topic
+// templates (--topics) or copied from a bundled or community template.
+func runInit(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	topicsFlag := fs.String("topics", "", "comma-separated topics (available: "+strings.Join(topicNames(), ", ")+")")
+	templateName := fs.String("template", pathTemplates[0].Name, "bundled or community template to start from")
+	list := fs.Bool("list", false, "list available templates and topics")
+	weeks := fs.Int("weeks", defaultWeeks, "number of weeks to spread the topics over")
+	force := fs.Bool("force", false, "overwrite an existing file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *list {
+		listTemplates(out, config.TemplateIndex)
+		return nil
+	}
+	if *weeks < 1 {
+		return fmt.Errorf("--weeks must be at least 1")
+	}
+
+	target := app.FilePath
+	if fs.NArg() > 0 {
+		target = fs.Arg(0)
+	}
+	if fileExists(target) && !*force {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", target)
+	}
+
+	var content string
+	if *topicsFlag == "" {
+		var err error
+		if content, err = resolveTemplate(*templateName, config.TemplateIndex); err != nil {
+			return err
+		}
+	} else {
+		var topics []TopicTemplate
+		for _, name := range strings.Split(*topicsFlag, ",") {
+			topic, err := loadTopic(strings.TrimSpace(name))
+			if err != nil {
+				return err
+			}
+			topics = append(topics, topic)
+		}
+		content = GenerateCurriculum(topics, *weeks, time.Now())
+	}
+
+	if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✅ Đã tạo %s\n", target)
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================================
+// Curriculum Template Tests
+// ============================================================================
+
+func TestParseTopicTemplate(t *testing.T) {
+	content := "# Demo\n\nShort description.\n\n## First (3h)\n\n- [ ] One\n\n## Second (5h)\n\n- [ ] Two\n"
+
+	topic, err := parseTopicTemplate("demo", content)
+	if err != nil {
+		t.Fatalf("parseTopicTemplate failed: %v", err)
+	}
+
+	if topic.Title != "Demo" || topic.Description != "Short description." {
+		t.Errorf("Unexpected title/description: %q / %q", topic.Title, topic.Description)
+	}
+
+	if len(topic.Modules) != 2 || topic.Modules[1].Title != "Second" || topic.Hours() != 8 {
+		t.Errorf("Unexpected modules: %+v", topic.Modules)
+	}
+
+	if topic.Modules[0].Body != "- [ ] One" {
+		t.Errorf("Expected module body '- [ ] One', got %q", topic.Modules[0].Body)
+	}
+}
+
+func TestParseTopicTemplateErrors(t *testing.T) {
+	if _, err := parseTopicTemplate("x", "# X\n\n## No hours\n"); err == nil {
+		t.Error("Expected error for module without hour estimate")
+	}
+
+	if _, err := parseTopicTemplate("x", "# X\n\nOnly text\n"); err == nil {
+		t.Error("Expected error for topic without modules")
+	}
+}
+
+func TestBundledTopicsParse(t *testing.T) {
+	names := topicNames()
+	if len(names) == 0 {
+		t.Fatal("Expected bundled topic templates")
+	}
+
+	for _, name := range names {
+		if _, err := loadTopic(name); err != nil {
+			t.Errorf("Topic %s: %v", name, err)
+		}
+	}
+
+	if _, err := loadTopic("nope"); err == nil {
+		t.Error("Expected error for unknown topic")
+	}
+}
+
+func TestGenerateCurriculum(t *testing.T) {
+	topics := []TopicTemplate{
+		{Title: "A", Modules: []TopicModule{{Title: "A1", Hours: 5, Body: "- [ ] a1"}, {Title: "A2", Hours: 5, Body: "- [ ] a2"}}},
+		{Title: "B", Modules: []TopicModule{{Title: "B1", Hours: 10, Body: "- [ ] b1"}}},
+	}
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	doc := GenerateCurriculum(topics, 4, start)
+
+	for _, want := range []string{
+		"_Bắt đầu: 2025-01-06",
+		"## Giai đoạn 1: A (Tuần 1-2)",
+		"### Tuần 1: A1",
+		"### Tuần 2: A2",
+		"## Giai đoạn 2: B (Tuần 3-4)",
+		"### Tuần 3-4: B1",
+		"_Ước tính: 10 giờ_",
+		"- [ ] b1",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Expected generated document to contain %q", want)
+		}
+	}
+
+	app := NewApp()
+	app.FileLines = strings.Split(doc, "\n")
+	app.ParseSections()
+	if _, total := app.GetTotalProgress(); total != 3 {
+		t.Errorf("Expected 3 checklist items, got %d", total)
+	}
+}
+
+func TestRunInit(t *testing.T) {
+	withTestApp(t)
+	target := filepath.Join(t.TempDir(), "path.md")
+
+	var out bytes.Buffer
+	if err := runInit([]string{"--topics", "k8s,linux", "--weeks", "6", target}, &out); err != nil {
+		t.Fatalf("runInit failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(target)
+	if !strings.Contains(string(data), "## Giai đoạn 2: Linux") {
+		t.Errorf("Expected Linux phase in generated file")
+	}
+
+	if err := runInit([]string{target}, &out); err == nil {
+		t.Error("Expected error when file exists without --force")
+	}
+
+	if err := runInit([]string{"--force", target}, &out); err != nil {
+		t.Fatalf("runInit --force failed: %v", err)
+	}
+	data, _ = os.ReadFile(target)
+	if string(data) != pathTemplates[0].Content() {
+		t.Error("Expected default template without --topics")
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cycleConfirmWord must be typed to start a new cycle, as it unchecks
+// items in bulk.
+const cycleConfirmWord = "reset"
+
+// Cycle is the archived result of one pass through the path (or through
+// one phase of it), taken when the next cycle was started.
+type Cycle struct {
+	Number int `json:"number"`
+	// Scope is the title of the phase that was reset, "" for the whole
+	// document
+	Scope   string    `json:"scope,omitempty"`
+	Ended   time.Time `json:"ended"`
+	Checked int       `json:"checked"`
+	Total   int       `json:"total"`
+	// Completed are the items that were checked, with when they were
+	// checked if the history knows it
+	Completed []CheckboxEvent `json:"completed,omitempty"`
+}
+
+// cycleRange returns the sections a cycle covers: the subtree of scope,
+// or the whole document for scope -1.
+func (a *App) cycleRange(scope int) (start, end int) {
+	if scope < 0 {
+		return 0, len(a.Sections)
+	}
+	return scope, a.subtreeEnd(scope)
+}
+
+// lastChecked returns when an item was last checked according to the
+// history, or the zero time.
+func (a *App) lastChecked(section, item string) time.Time {
+	for i := len(a.History) - 1; i >= 0; i-- {
+		if ev := a.History[i]; ev.Checked && ev.Section == section && ev.Item == item {
+			return ev.Time
+		}
+	}
+	return time.Time{}
+}
+
+// StartCycle archives the completion state of scope (a phase, or -1 for
+// the whole document) as a new Cycle and unchecks its items. Personal
+// tasks are left alone. The caller saves the file and state.
+func (a *App) StartCycle(scope int, now time.Time) Cycle {
+	c := Cycle{Number: len(a.Cycles) + 1, Ended: now}
+	if scope >= 0 {
+		c.Scope = a.Sections[scope].Title
+	}
+	start, end := a.cycleRange(scope)
+
+	// From the last section back, so the line numbers of the earlier
+	// sections stay valid while the file is rewritten
+	for i := end - 1; i >= start; i-- {
+		sec := &a.Sections[i]
+		checked, total := a.GetProgress(i)
+		c.Checked += checked
+		c.Total += total
+		if checked == 0 {
+			continue
+		}
+		var completed []CheckboxEvent
+		lines := strings.Split(sec.Content(), "\n")
+		for j, line := range lines {
+			if !isChecked(line) || isPersonalTask(line) {
+				continue
+			}
+			item := checkboxText(line)
+			completed = append(completed, CheckboxEvent{Time: a.lastChecked(sec.Title, item), Section: sec.Title, Item: item, Checked: true})
+			lines[j] = setCheckbox(line, false)
+		}
+		c.Completed = append(completed, c.Completed...)
+		sec.SetContent(strings.Join(lines, "\n"))
+		a.UpdateFileSection(i)
+	}
+	a.Cycles = append(a.Cycles, c)
+	return c
+}
+
+// cycleLines describes the archived cycles, newest first.
+func (a *App) cycleLines() []string {
+	var lines []string
+	for i := len(a.Cycles) - 1; i >= 0; i-- {
+		c := a.Cycles[i]
+		scope := "cả lộ trình"
+		if c.Scope != "" {
+			scope = c.Scope
+		}
+		lines = append(lines, fmt.Sprintf("%sVòng %d%s (%s): %d/%d mục (%d%%), kết thúc %s",
+			Bold, c.Number, Reset, scope, c.Checked, c.Total, percent(c.Checked, c.Total), c.Ended.Format("2006-01-02")))
+	}
+	return lines
+}
+
+// handleNewCycle archives the current progress and unchecks the items of
+// the whole path or of a chosen phase, after typing cycleConfirmWord.
+func handleNewCycle() {
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	reader := bufio.NewReader(keyboard)
+
+	ClearScreen()
+	fmt.Printf("%s🔁 BẮT ĐẦU VÒNG HỌC MỚI%s\n", Bold+Cyan, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	if past := app.cycleLines(); len(past) > 0 {
+		fmt.Println()
+		for _, line := range past {
+			fmt.Println("  " + line)
+		}
+	}
+
+	checked, total := app.GetTotalProgress()
+	fmt.Printf("\nKết quả hiện tại được lưu thành vòng %d rồi các mục được bỏ đánh dấu.\n\n", len(app.Cycles)+1)
+	fmt.Printf("  %sEnter%s - Cả lộ trình (%d/%d mục)\n", Cyan, Reset, checked, total)
+	var phases []int
+	for i, sec := range app.Sections {
+		if sec.Level == 2 {
+			phases = append(phases, i)
+			c, t := 0, 0
+			start, end := app.cycleRange(i)
+			for j := start; j < end; j++ {
+				cj, tj := app.GetProgress(j)
+				c, t = c+cj, t+tj
+			}
+			fmt.Printf("  %s%d%s - %s (%d/%d mục)\n", Cyan, len(phases), Reset, sec.Title, c, t)
+		}
+	}
+	fmt.Printf("\nPhạm vi (Enter hoặc 1-%d, q để hủy): ", len(phases))
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	scope := -1
+	if input != "" {
+		n, err := strconv.Atoi(input)
+		if err != nil || n < 1 || n > len(phases) {
+			return
+		}
+		scope = phases[n-1]
+	}
+
+	var c Cycle
+	if !runChange(reader, fmt.Sprintf("Lưu vòng %d và bỏ đánh dấu các mục", len(app.Cycles)+1), cycleConfirmWord, func() error {
+		c = app.StartCycle(scope, time.Now())
+		return nil
+	}) {
+		return
+	}
+	notify(SeveritySuccess, "Đã lưu vòng %d (%d/%d mục) và bắt đầu vòng mới (z u để hoàn tác)", c.Number, c.Checked, c.Total)
+}
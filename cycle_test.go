@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartCycleWholeDocument(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 2
+	app.AddPersonalTask("đọc thêm")
+	app.UpdateFileSection(2)
+	app.ParseSections()
+	checkedAt := time.Date(2025, 2, 1, 8, 0, 0, 0, time.UTC)
+	app.recordCheckbox("Exercise 1", "- [x] Done", true, checkedAt)
+	for i, line := range strings.Split(app.Sections[2].Content(), "\n") {
+		if isPersonalTask(line) {
+			lines := strings.Split(app.Sections[2].Content(), "\n")
+			lines[i] = strings.Replace(line, "- [ ]", "- [x]", 1)
+			app.Sections[2].SetContent(strings.Join(lines, "\n"))
+		}
+	}
+	app.UpdateFileSection(2)
+	app.ParseSections()
+
+	now := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	c := app.StartCycle(-1, now)
+
+	if c.Number != 1 || c.Scope != "" || c.Checked != 3 || c.Total != 6 || !c.Ended.Equal(now) {
+		t.Errorf("cycle = %+v", c)
+	}
+	if len(c.Completed) != 3 || c.Completed[0].Item != "Task two completed" || c.Completed[1].Item != "Done" || !c.Completed[1].Time.Equal(checkedAt) {
+		t.Errorf("completed = %+v", c.Completed)
+	}
+	if checked, _ := app.GetTotalProgress(); checked != 0 {
+		t.Errorf("Expected everything unchecked, %d still checked", checked)
+	}
+	if !strings.Contains(app.FileContent, "- [x] "+personalTaskMarker) {
+		t.Error("personal tasks must keep their state")
+	}
+	if len(app.Cycles) != 1 {
+		t.Errorf("Cycles = %+v", app.Cycles)
+	}
+}
+
+func TestStartCyclePhase(t *testing.T) {
+	app := createTestApp()
+	phase := app.FindSection("Giai đoạn 2")
+	c := app.StartCycle(phase, time.Now())
+
+	if c.Scope != app.Sections[phase].Title || c.Checked != 2 || c.Total != 2 {
+		t.Errorf("cycle = %+v", c)
+	}
+	// The first phase is untouched
+	if checked, _ := app.GetProgress(2); checked != 1 {
+		t.Errorf("Chapter 1 checked = %d, want 1", checked)
+	}
+	if checked, _ := app.GetProgress(5); checked != 0 {
+		t.Errorf("Exercise 1 checked = %d, want 0", checked)
+	}
+
+	restored := createTestApp()
+	restored.ApplyState(app.CaptureState(20))
+	if len(restored.cycleLines()) != 1 {
+		t.Errorf("cycles not restored: %+v", restored.Cycles)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// debugMemInterval is how often the main loop logs memory stats in debug
+// mode.
+const debugMemInterval = 30 * time.Second
+
+// DebugLog records timings and memory stats for --debug, one line per
+// event: "<time> <event> <duration> <detail>". It is nil when debugging
+// is off, and every method is a no-op on nil.
+type DebugLog struct {
+	mu sync.Mutex
+	w  io.Writer
+	// inputAt is the last key read whose latency was logged
+	inputAt time.Time
+}
+
+// debugLog is the debug log of the session (--debug), nil when off.
+var debugLog *DebugLog
+
+// cacheHome returns the base cache directory for sre-learn. It honors
+// XDG_CACHE_HOME and falls back to ~/.cache.
+func cacheHome() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "sre-learn"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".cache", "sre-learn"), nil
+}
+
+// debugLogPath returns the file --debug writes to.
+func debugLogPath() (string, error) {
+	dir, err := cacheHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "debug.log"), nil
+}
+
+// OpenDebugLog appends the debug log to the file at path.
+func OpenDebugLog(path string) (*DebugLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &DebugLog{w: f}, nil
+}
+
+// Logf writes an event line.
+func (d *DebugLog) Logf(event string, elapsed time.Duration, format string, args ...any) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fmt.Fprintf(d.w, "%s %s %s %s\n", time.Now().Format("15:04:05.000"), event, elapsed, fmt.Sprintf(format, args...))
+}
+
+// Time returns a function logging the time elapsed since Time was
+// called, for timing a block with defer.
+func (d *DebugLog) Time(event, format string, args ...any) func() {
+	if d == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { d.Logf(event, time.Since(start), format, args...) }
+}
+
+// Input logs the latency of the key read at readAt, from reading it to
+// the screen reflecting it; each key is logged once.
+func (d *DebugLog) Input(readAt time.Time) {
+	if d == nil || readAt.IsZero() || !readAt.After(d.inputAt) {
+		return
+	}
+	d.inputAt = readAt
+	d.Logf("input", time.Since(readAt), "")
+}
+
+// Mem logs the memory stats of the process.
+func (d *DebugLog) Mem() {
+	if d == nil {
+		return
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	d.Logf("mem", 0, "heap=%dKiB sys=%dKiB objects=%d gc=%d goroutines=%d", m.HeapAlloc>>10, m.Sys>>10, m.HeapObjects, m.NumGC, runtime.NumGoroutine())
+}
+
+// pprofMux serves the runtime profiles under /debug/pprof/ (serve mode
+// with --debug).
+func pprofMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
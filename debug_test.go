@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDebugLogNil(t *testing.T) {
+	var d *DebugLog
+	d.Time("render", "")()
+	d.Input(time.Now())
+	d.Mem()
+}
+
+func TestDebugLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "debug.log")
+	d, err := OpenDebugLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Time("parse", "lines=%d", 42)()
+	readAt := time.Now()
+	d.Input(readAt)
+	d.Input(readAt)
+	d.Mem()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+	if !strings.Contains(log, " parse ") || !strings.Contains(log, "lines=42") {
+		t.Errorf("Expected the parse timing, got %q", log)
+	}
+	if n := strings.Count(log, " input "); n != 1 {
+		t.Errorf("Expected a key's latency logged once, got %d times", n)
+	}
+	if !strings.Contains(log, " mem ") || !strings.Contains(log, "heap=") {
+		t.Errorf("Expected memory stats, got %q", log)
+	}
+}
+
+func TestServePprof(t *testing.T) {
+	s, _ := newTestServer(t)
+	if rec := serve(s, http.MethodGet, "/debug/pprof/", nil); strings.Contains(rec.Body.String(), "goroutine") {
+		t.Error("Expected no profiles without --debug")
+	}
+
+	d, err := OpenDebugLog(filepath.Join(t.TempDir(), "debug.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	debugLog = d
+	t.Cleanup(func() { debugLog = nil })
+	s, _ = newTestServer(t)
+	if rec := serve(s, http.MethodGet, "/debug/pprof/", nil); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "goroutine") {
+		t.Errorf("Expected the pprof index with --debug, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// deepLinkScheme prefixes a deep link so it reads as a URI.
+const deepLinkScheme = "sre-learn://"
+
+// openUsage is the synopsis of "sre-learn open", which starts the viewer
+// instead of running as a subcommand.
+const openUsage = "open <link>  Mở tài liệu tại liên kết sâu (sre-learn://file.md#anchor:dòng hoặc file.md#anchor:dòng)"
+
+// DeepLink points at a line of a section of a document, for sharing a
+// place with someone else who has the same curriculum:
+// "sre-learn://learning-path-full.md#giai-doan-2-slo:120". The file is
+// its base name, since the other user keeps it in another directory, and
+// the section is its anchor, which survives sections being added or
+// moved.
+type DeepLink struct {
+	File   string
+	Anchor string
+	// Line is the 0-based line of the section content, 0 for its top
+	Line int
+}
+
+// String formats the link with the 1-based line shown in the gutter.
+func (l DeepLink) String() string {
+	s := deepLinkScheme + url.PathEscape(l.File) + "#" + l.Anchor
+	if l.Line > 0 {
+		s += ":" + strconv.Itoa(l.Line+1)
+	}
+	return s
+}
+
+// ParseDeepLink parses a link with or without the sre-learn:// prefix:
+// "file.md#anchor", "file.md#anchor:120" or "#anchor:120" for the open
+// document.
+func ParseDeepLink(s string) (DeepLink, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), deepLinkScheme)
+	file, fragment, found := strings.Cut(s, "#")
+	if !found || fragment == "" {
+		return DeepLink{}, fmt.Errorf("invalid link %q: expected file.md#anchor[:line]", s)
+	}
+	file, err := url.PathUnescape(file)
+	if err != nil {
+		return DeepLink{}, fmt.Errorf("invalid link %q: %w", s, err)
+	}
+
+	link := DeepLink{File: file, Anchor: fragment}
+	// Anchors may hold colons too ({#a:b}), so only digits after the last
+	// one are a line
+	if i := strings.LastIndex(fragment, ":"); i > 0 {
+		if n, err := strconv.Atoi(fragment[i+1:]); err == nil {
+			if n < 1 {
+				return DeepLink{}, fmt.Errorf("invalid line %d in link %q", n, s)
+			}
+			link.Anchor, link.Line = fragment[:i], n-1
+		}
+	}
+	return link, nil
+}
+
+// DeepLink returns the link to the current section at the top line shown.
+func (r *Renderer) DeepLink() DeepLink {
+	return DeepLink{
+		File:   filepath.Base(r.App.FilePath),
+		Anchor: r.App.Anchor(r.App.CurrentIdx),
+		Line:   r.ScrollOffset,
+	}
+}
+
+// resolveDeepLinkFile finds the document a link names: the path itself
+// when it exists, else a recent document with the same name, else the
+// path as given.
+func resolveDeepLinkFile(file string) string {
+	if fileExists(file) {
+		return file
+	}
+	for _, path := range recentDocuments(maxRecentFiles) {
+		if filepath.Base(path) == filepath.Base(file) {
+			return path
+		}
+	}
+	return file
+}
+
+// OpenDeepLink shows the section and line of link in the open document.
+func (r *Renderer) OpenDeepLink(link DeepLink) error {
+	idx := r.App.anchorIndex(link.Anchor)
+	if idx < 0 {
+		return fmt.Errorf("section #%s not found", link.Anchor)
+	}
+	if !r.GotoLine(idx, link.Line) {
+		r.App.GotoSection(idx)
+		r.ResetScroll()
+		return fmt.Errorf("section #%s has no line %d", link.Anchor, link.Line+1)
+	}
+	return nil
+}
+
+// parseOpenArgs parses the arguments of "sre-learn open".
+func parseOpenArgs(args []string) (DeepLink, error) {
+	if len(args) != 1 {
+		return DeepLink{}, fmt.Errorf("usage: sre-learn open <link>")
+	}
+	return ParseDeepLink(args[0])
+}
+
+// copyDeepLink copies the link to the current position to the clipboard.
+func copyDeepLink() {
+	link := renderer.DeepLink().String()
+	if method, err := CopyToClipboard(link); err != nil {
+		notify(SeverityError, "Lỗi copy: %v", err)
+	} else {
+		notify(SeveritySuccess, "Đã copy liên kết %s (%s), mở bằng: sre-learn open <link>", link, method)
+	}
+}
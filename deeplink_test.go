@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeepLinkRoundTrip(t *testing.T) {
+	tests := []struct {
+		link DeepLink
+		want string
+	}{
+		{DeepLink{"learning-path-full.md", "giai-doan-2-slo", 119}, "sre-learn://learning-path-full.md#giai-doan-2-slo:120"},
+		{DeepLink{"my path.md", "intro", 0}, "sre-learn://my%20path.md#intro"},
+		{DeepLink{"a.md", "k8s:pods", 4}, "sre-learn://a.md#k8s:pods:5"},
+	}
+	for _, tt := range tests {
+		if got := tt.link.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+		parsed, err := ParseDeepLink(tt.want)
+		if err != nil || parsed != tt.link {
+			t.Errorf("ParseDeepLink(%q) = %+v, %v", tt.want, parsed, err)
+		}
+	}
+}
+
+func TestParseDeepLink(t *testing.T) {
+	link, err := ParseDeepLink("notes.md#setup:12")
+	if err != nil || link != (DeepLink{"notes.md", "setup", 11}) {
+		t.Errorf("Expected a link without the scheme to parse, got %+v, %v", link, err)
+	}
+	link, err = ParseDeepLink("#k8s:pods")
+	if err != nil || link != (DeepLink{"", "k8s:pods", 0}) {
+		t.Errorf("Expected an anchor with a colon and no line, got %+v, %v", link, err)
+	}
+	for _, bad := range []string{"notes.md", "notes.md#", "notes.md#setup:0"} {
+		if _, err := ParseDeepLink(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestOpenDeepLink(t *testing.T) {
+	app := createTestApp()
+	r := &Renderer{App: app, PageSize: 2, TermWidth: 80}
+
+	if err := r.OpenDeepLink(DeepLink{Anchor: "chapter-1-basics", Line: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if app.CurrentIdx != 2 || r.ScrollOffset != 2 {
+		t.Errorf("Expected section 2 at line 2, got section %d offset %d", app.CurrentIdx, r.ScrollOffset)
+	}
+	if got := r.DeepLink(); got.Anchor != "chapter-1-basics" || got.Line != 2 || got.File != "learning-path-full.md" {
+		t.Errorf("Expected the link back to the same place, got %+v", got)
+	}
+
+	if err := r.OpenDeepLink(DeepLink{Anchor: "exercise-1", Line: 99}); err == nil || app.CurrentIdx != 5 || r.ScrollOffset != 0 {
+		t.Errorf("Expected a missing line to open the section top with an error, got %v at %d", err, app.CurrentIdx)
+	}
+	if err := r.OpenDeepLink(DeepLink{Anchor: "nope"}); err == nil {
+		t.Error("Expected an unknown anchor to fail")
+	}
+}
+
+func TestResolveDeepLinkFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "here.md")
+	if err := os.WriteFile(path, []byte("# A\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolveDeepLinkFile(path); got != path {
+		t.Errorf("Expected an existing path as is, got %q", got)
+	}
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_STATE_HOME", dir)
+	if got := resolveDeepLinkFile("missing.md"); got != "missing.md" {
+		t.Errorf("Expected an unknown file as given, got %q", got)
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around a change.
+const diffContext = 3
+
+// maxDiffCells bounds the LCS table; larger changes are shown as one
+// replacement instead.
+const maxDiffCells = 4 << 20
+
+// diffOp is one line of a line diff: kept (' '), removed ('-') or
+// added ('+').
+type diffOp struct {
+	Kind byte
+	Text string
+}
+
+// diffLines returns the edit script turning a into b. Common leading and
+// trailing lines are matched first, so local edits stay cheap.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// diffMiddle diffs the changed part with a longest common subsequence.
+func diffMiddle(a, b []string) []diffOp {
+	var ops []diffOp
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	return ops
+}
+
+// Hunk is a group of nearby changes with their context lines.
+type Hunk struct {
+	// OldStart and NewStart are 1-based line numbers, as in unified diffs
+	OldStart, OldLines int
+	NewStart, NewLines int
+	// Start and End delimit the hunk's ops, context included
+	Start, End int
+}
+
+// diffHunks groups the changes of ops into hunks with context lines.
+func diffHunks(ops []diffOp, context int) []Hunk {
+	var hunks []Hunk
+	for i := 0; i < len(ops); i++ {
+		if ops[i].Kind == ' ' {
+			continue
+		}
+		// Extend over changes separated by at most 2*context kept lines
+		end := i
+		for k := i; k < len(ops); k++ {
+			if ops[k].Kind != ' ' {
+				end = k
+			} else if k-end > 2*context {
+				break
+			}
+		}
+		h := Hunk{Start: max(0, i-context), End: min(len(ops), end+1+context)}
+		if len(hunks) > 0 && h.Start <= hunks[len(hunks)-1].End {
+			h.Start = hunks[len(hunks)-1].End
+		}
+		hunks = append(hunks, h)
+		i = end
+	}
+
+	// Line numbers
+	oldLine, newLine, op := 1, 1, 0
+	for n := range hunks {
+		h := &hunks[n]
+		for ; op < h.Start; op++ {
+			oldLine, newLine = oldLine+btoi(ops[op].Kind != '+'), newLine+btoi(ops[op].Kind != '-')
+		}
+		h.OldStart, h.NewStart = oldLine, newLine
+		for ; op < h.End; op++ {
+			h.OldLines += btoi(ops[op].Kind != '+')
+			h.NewLines += btoi(ops[op].Kind != '-')
+			oldLine, newLine = oldLine+btoi(ops[op].Kind != '+'), newLine+btoi(ops[op].Kind != '-')
+		}
+	}
+	return hunks
+}
+
+// btoi returns 1 for true and 0 for false.
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// applyHunks returns the new lines of ops, with the changes of the
+// discarded hunks reverted to the old lines.
+func applyHunks(ops []diffOp, hunks []Hunk, discard map[int]bool) []string {
+	discarded := make([]bool, len(ops))
+	for n, h := range hunks {
+		if discard[n] {
+			for i := h.Start; i < h.End; i++ {
+				discarded[i] = true
+			}
+		}
+	}
+
+	var lines []string
+	for i, op := range ops {
+		switch {
+		case op.Kind == ' ',
+			op.Kind == '+' && !discarded[i],
+			op.Kind == '-' && discarded[i]:
+			lines = append(lines, op.Text)
+		}
+	}
+	return lines
+}
+
+// writeDiff writes the hunks as a unified diff, numbered so they can be
+// picked; discarded hunks are marked.
+func writeDiff(out io.Writer, ops []diffOp, hunks []Hunk, discard map[int]bool, useColor bool) {
+	style := func(code, s string) string {
+		if useColor {
+			return code + s + Reset
+		}
+		return s
+	}
+	for n, h := range hunks {
+		mark := ""
+		if discard[n] {
+			mark = style(Yellow, "  [bỏ]")
+		}
+		fmt.Fprintf(out, "%s %s%s\n", style(Bold, fmt.Sprintf("#%d", n+1)),
+			style(Cyan, fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)), mark)
+		for _, op := range ops[h.Start:h.End] {
+			line := string(op.Kind) + op.Text
+			switch {
+			case discard[n] && op.Kind != ' ':
+				line = style(Dim, line)
+			case op.Kind == '-':
+				line = style(Red, line)
+			case op.Kind == '+':
+				line = style(Green, line)
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+}
+
+// PendingDiff diffs the file on disk against the in-memory lines.
+func (a *App) PendingDiff() ([]diffOp, []Hunk) {
+	disk := a.diskContent
+	if data, err := os.ReadFile(a.FilePath); err == nil {
+		disk = string(data)
+	}
+	if content, err := decodeDocument(disk); err == nil {
+		disk = content
+	}
+	ops := diffLines(strings.Split(disk, "\n"), a.FileLines)
+	return ops, diffHunks(ops, diffContext)
+}
+
+// reviewChanges shows the unsaved changes and lets the user discard
+// hunks. Returns true if the remaining changes should be written, with
+// the discarded hunks already reverted in memory.
+func reviewChanges(reader *bufio.Reader) bool {
+	ops, hunks := app.PendingDiff()
+	if len(hunks) == 0 {
+		return true
+	}
+
+	discard := map[int]bool{}
+	for {
+		ClearScreen()
+		fmt.Printf("%s± THAY ĐỔI CHƯA LƯU - %s%s\n", Bold+Cyan, app.FilePath, Reset)
+		fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+		writeDiff(os.Stdout, ops, hunks, discard, true)
+
+		fmt.Printf("\n%sSố hunk (1-%d) để bỏ/giữ · w: ghi · q: để sau:%s ", Bold, len(hunks), Reset)
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		switch input {
+		case "w", "W", "":
+			if len(discard) > 0 {
+				app.FileLines = applyHunks(ops, hunks, discard)
+				app.FileContent = strings.Join(app.FileLines, "\n")
+				app.ParseSections()
+				app.CurrentIdx = min(app.CurrentIdx, max(0, len(app.Sections)-1))
+			}
+			return true
+		case "q", "Q":
+			return false
+		}
+		if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(hunks) {
+			if discard[n-1] {
+				delete(discard, n-1)
+			} else {
+				discard[n-1] = true
+			}
+		}
+	}
+}
+
+// saveReviewed writes the file after showing the pending diff when
+// preview_writes is set. Returns false if the user postponed the write
+// or saving failed.
+func saveReviewed(reader *bufio.Reader) bool {
+	if config.PreviewWrites && !reviewChanges(reader) {
+		notify(SeverityWarning, "Chưa lưu thay đổi (D để xem lại)")
+		return false
+	}
+	return saveDocument()
+}
+
+// handleDiff shows the changes not yet written to disk, lets the user
+// discard hunks and writes the rest.
+func handleDiff() {
+	if _, hunks := app.PendingDiff(); len(hunks) == 0 {
+		notify(SeverityInfo, "Không có thay đổi chưa lưu")
+		return
+	}
+	terminal.SetRawMode(false)
+	exec.Command("stty", "sane").Run()
+	defer terminal.SetRawMode(true)
+
+	if !reviewChanges(bufio.NewReader(keyboard)) {
+		return
+	}
+	if app.ReadOnly {
+		notify(SeverityWarning, "Chế độ chỉ đọc - không ghi file")
+		return
+	}
+	if saveDocument() {
+		notify(SeveritySuccess, "Đã lưu")
+	}
+}
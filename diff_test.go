@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffHunks(t *testing.T) {
+	old := strings.Split("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm", "\n")
+	new := strings.Split("a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn", "\n")
+
+	ops := diffLines(old, new)
+	hunks := diffHunks(ops, diffContext)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(hunks))
+	}
+	if h := hunks[0]; h.OldStart != 1 || h.OldLines != 5 || h.NewStart != 1 || h.NewLines != 5 {
+		t.Errorf("hunk 1 = %+v", h)
+	}
+	if h := hunks[1]; h.OldStart != 11 || h.OldLines != 3 || h.NewStart != 11 || h.NewLines != 4 {
+		t.Errorf("hunk 2 = %+v", h)
+	}
+
+	var out bytes.Buffer
+	writeDiff(&out, ops, hunks, map[int]bool{1: true}, false)
+	want := "#1 @@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n#2 @@ -11,3 +11,4 @@  [bỏ]\n k\n l\n m\n+n\n"
+	if out.String() != want {
+		t.Errorf("writeDiff =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestApplyHunksDiscard(t *testing.T) {
+	old := strings.Split("a\nb\nc\nd\ne\nf\ng\nh\ni\nj", "\n")
+	new := strings.Split("x\na\nb\nc\nd\ne\nf\ng\nh\nj", "\n")
+	ops := diffLines(old, new)
+	hunks := diffHunks(ops, 1)
+
+	if got := applyHunks(ops, hunks, nil); !reflect.DeepEqual(got, new) {
+		t.Errorf("keeping all hunks = %q", got)
+	}
+	if got := applyHunks(ops, hunks, map[int]bool{0: true, 1: true}); !reflect.DeepEqual(got, old) {
+		t.Errorf("discarding all hunks = %q", got)
+	}
+	want := strings.Split("a\nb\nc\nd\ne\nf\ng\nh\nj", "\n")
+	if got := applyHunks(ops, hunks, map[int]bool{0: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("discarding hunk 1 = %q, want %q", got, want)
+	}
+}
+
+func TestPendingDiffAfterNote(t *testing.T) {
+	a := createTestApp()
+	dir := t.TempDir()
+	a.FilePath = dir + "/path.md"
+	a.diskContent = sampleMarkdown
+	a.CurrentIdx = 2
+	a.AddNote("xem lại")
+	a.UpdateFileSection(2)
+
+	ops, hunks := a.PendingDiff()
+	if len(hunks) != 1 {
+		t.Fatalf("got %d hunks, want 1", len(hunks))
+	}
+	added := 0
+	for _, op := range ops[hunks[0].Start:hunks[0].End] {
+		if op.Kind == '+' {
+			added++
+		}
+	}
+	if added != 2 {
+		t.Errorf("expected a blank line and the note to be added, got %d lines", added)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// noteHeaderRegex matches the header of a note added with "a":
+// "**Ghi chú [2024-01-02 15:04]:** text".
+var noteHeaderRegex = regexp.MustCompile(`^\*\*Ghi chú \[([^\]]+)\]:\*\*\s*`)
+
+// inlineHTMLRules converts inline markdown on already escaped text.
+var inlineHTMLRules = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile("`([^`]+)`"), "<code>$1</code>"},
+	{regexp.MustCompile(`\*\*([^*]+)\*\*`), "<strong>$1</strong>"},
+	{regexp.MustCompile(`\*([^*]+)\*`), "<em>$1</em>"},
+	{regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`), `<a href="$2">$1</a>`},
+}
+
+// inlineHTML escapes text and converts code spans, bold, italics and
+// links to XHTML, which both browsers and EPUB readers accept.
+func inlineHTML(text string) string {
+	out := html.EscapeString(text)
+	for _, rule := range inlineHTMLRules {
+		out = rule.re.ReplaceAllString(out, rule.repl)
+	}
+	return out
+}
+
+// writeSectionHTML writes the content of a section as XHTML: checkboxes
+// with their state, notes as callouts and code blocks as <pre>.
+func writeSectionHTML(out io.Writer, content string) {
+	var code []string
+	inCode := false
+	for _, l := range webLines(content) {
+		trimmed := strings.TrimSpace(l.Text)
+		if l.Kind == "code" {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				if inCode {
+					fmt.Fprintf(out, "<pre><code>%s</code></pre>\n", html.EscapeString(strings.Join(code, "\n")))
+					code = nil
+				}
+				inCode = !inCode
+				continue
+			}
+			code = append(code, l.Text)
+			continue
+		}
+
+		switch l.Kind {
+		case "heading":
+			fmt.Fprintf(out, "<h5>%s</h5>\n", inlineHTML(l.Text))
+		case "checkbox":
+			class, mark := "todo", "☐"
+			switch l.State {
+			case "done":
+				class, mark = "done", "☑"
+			case "in_progress":
+				class, mark = "doing", "◐"
+			case "wont_do":
+				class, mark = "wontdo", "⊟"
+			}
+			fmt.Fprintf(out, "<p class=\"checkbox %s\">%s %s</p>\n", class, mark, inlineHTML(l.Text))
+		case "note":
+			if m := noteHeaderRegex.FindStringSubmatch(l.Text); m != nil {
+				fmt.Fprintf(out, "<aside class=\"note\"><strong>📝 Ghi chú %s</strong><br/>%s</aside>\n",
+					html.EscapeString(m[1]), inlineHTML(l.Text[len(m[0]):]))
+			} else {
+				fmt.Fprintf(out, "<blockquote>%s</blockquote>\n", inlineHTML(l.Text))
+			}
+		case "text":
+			fmt.Fprintf(out, "<p>%s</p>\n", inlineHTML(strings.TrimSpace(l.Text)))
+		}
+	}
+	if len(code) > 0 {
+		fmt.Fprintf(out, "<pre><code>%s</code></pre>\n", html.EscapeString(strings.Join(code, "\n")))
+	}
+}
+
+// documentCSS styles exported documents for print and e-readers.
+const documentCSS = `body{font-family:serif;line-height:1.5}
+h1,h2,h3,h4{font-family:sans-serif;page-break-after:avoid}
+h2{page-break-before:always}
+.checkbox{margin:.2em 0}.done{color:#2a7a4a}.doing{color:#a06a00}.wontdo{color:#888;text-decoration:line-through}
+aside.note{border-left:3px solid #a5a;background:#f8f0f8;padding:.4em .8em;margin:.6em 0}
+pre{background:#f4f4f4;padding:.5em;white-space:pre-wrap;font-size:.85em}
+.progress{color:#666;font-size:.9em}`
+
+// sectionProgressHTML returns the progress line of a section, if it has
+// checkboxes.
+func (a *App) sectionProgressHTML(idx int) string {
+	checked, total := a.GetProgress(idx)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("<p class=\"progress\">%d/%d hoàn thành</p>\n", checked, total)
+}
+
+// WriteDocumentHTML writes the whole annotated document as one XHTML
+// page. Sections become h1-h4, so converters build the outline from them.
+func (a *App) WriteDocumentHTML(out io.Writer, title string) {
+	fmt.Fprintf(out, "<!DOCTYPE html>\n<html xmlns=\"http://www.w3.org/1999/xhtml\" lang=\"vi\">\n<head>\n<meta charset=\"utf-8\"/>\n<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n",
+		html.EscapeString(title), documentCSS)
+	for i, sec := range a.Sections {
+		level := max(1, min(sec.Level, 4))
+		fmt.Fprintf(out, "<h%d id=\"s%d\">%s</h%d>\n", level, i+1, inlineHTML(sec.Title), level)
+		fmt.Fprint(out, a.sectionProgressHTML(i))
+		writeSectionHTML(out, sec.Content())
+	}
+	fmt.Fprint(out, "</body>\n</html>\n")
+}
+
+// documentTitle returns the title of the document: its first level-1
+// header, or the file name.
+func (a *App) documentTitle() string {
+	for _, sec := range a.Sections {
+		if sec.Level == 1 {
+			return sec.Title
+		}
+	}
+	return strings.TrimSuffix(filepath.Base(a.FilePath), filepath.Ext(a.FilePath))
+}
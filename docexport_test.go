@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteSectionHTML(t *testing.T) {
+	var out bytes.Buffer
+	writeSectionHTML(&out, "- [x] Done <b>\n- [ ] Todo `kubectl`\n> **Ghi chú [2024-01-02 15:04]:** check *logs*\n```\na < b\n```\n")
+	html := out.String()
+
+	for _, want := range []string{
+		`<p class="checkbox done">☑ Done &lt;b&gt;</p>`,
+		`<p class="checkbox todo">☐ Todo <code>kubectl</code></p>`,
+		`<aside class="note"><strong>📝 Ghi chú 2024-01-02 15:04</strong><br/>check <em>logs</em></aside>`,
+		"<pre><code>a &lt; b</code></pre>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %q in:\n%s", want, html)
+		}
+	}
+}
+
+func TestWriteDocumentHTML(t *testing.T) {
+	app := createTestApp()
+	var out bytes.Buffer
+	app.WriteDocumentHTML(&out, app.documentTitle())
+	html := out.String()
+
+	for _, want := range []string{
+		"<title>Main Title</title>",
+		`<h1 id="s1">Main Title</h1>`,
+		`<h2 id="s2">Giai đoạn 1: Learning</h2>`,
+		`<h3 id="s3">Chapter 1: Basics</h3>`,
+		`<p class="progress">1/3 hoàn thành</p>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Problem is something in the markdown the viewer handles badly.
+type Problem struct {
+	// Line is the 1-based line number
+	Line    int
+	Message string
+	// fix rewrites the file lines to solve the problem; nil if it must be
+	// fixed by hand
+	fix func(lines []string) []string
+}
+
+// Fixable reports whether doctor can fix the problem itself.
+func (p Problem) Fixable() bool {
+	return p.fix != nil
+}
+
+// looseCheckboxRegex matches list items that look like checkboxes:
+// indent, marker, spacing, box content and the text after it.
+var looseCheckboxRegex = regexp.MustCompile(`^(\s*)([-*+])(\s*)\[([ xX~-]?)\](.*)$`)
+
+// looseNoteRegex matches note banners, well-formed or not:
+// "> **Ghi chú [2025-01-31 10:00]:** text" with the quote, bold or colon
+// missing.
+var looseNoteRegex = regexp.MustCompile(`^(\s*)>?\s*\**\s*Ghi chú\s*\[([^\]]*)\]\s*:?\s*\**\s*:?\s*(.*)$`)
+
+// noteBannerRegex matches a well-formed note banner.
+var noteBannerRegex = regexp.MustCompile(`^\s*> \*\*Ghi chú \[\d{4}-\d{2}-\d{2} \d{2}:\d{2}\]:\*\*( |$)`)
+
+// noteBannerTimeRegex matches the timestamp of a note banner.
+var noteBannerTimeRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}$`)
+
+// replaceLine returns a fix setting line i to text.
+func replaceLine(i int, text string) func([]string) []string {
+	return func(lines []string) []string {
+		lines[i] = text
+		return lines
+	}
+}
+
+// checkCheckbox reports a list item meant as a checkbox that the viewer
+// does not count (see isCheckbox): "-[ ]", "- []" or no space after the
+// box.
+func checkCheckbox(i int, line string) *Problem {
+	m := looseCheckboxRegex.FindStringSubmatch(line)
+	if m == nil || strings.HasPrefix(m[5], "(") || isCheckbox(line) {
+		return nil // a link, "- [text](url)", or a valid checkbox
+	}
+	indent, marker, state, rest := m[1], m[2], m[4], m[5]
+	box := "[ ]"
+	if state != "" {
+		box = "[" + strings.ToLower(state) + "]"
+	}
+	fixed := indent + marker + " " + box
+	if rest = strings.TrimSpace(rest); rest != "" {
+		fixed += " " + rest
+	}
+	return &Problem{Line: i + 1, Message: fmt.Sprintf("checkbox sai cú pháp %q (đúng: %q)", strings.TrimSpace(line), strings.TrimSpace(fixed)), fix: replaceLine(i, fixed)}
+}
+
+// checkNoteBanner reports a note whose "> **Ghi chú [time]:**" banner is
+// malformed, so it is not shown as a note.
+func checkNoteBanner(i int, line string) *Problem {
+	trimmed := strings.TrimSpace(line)
+	if noteBannerRegex.MatchString(line) || !strings.HasPrefix(trimmed, ">") && !strings.HasPrefix(trimmed, "*") {
+		return nil
+	}
+	m := looseNoteRegex.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	p := &Problem{Line: i + 1, Message: "banner ghi chú hỏng: " + strings.TrimSpace(line)}
+	if stamp := strings.TrimSpace(m[2]); noteBannerTimeRegex.MatchString(stamp) {
+		fixed := m[1] + "> **Ghi chú [" + stamp + "]:**"
+		if m[3] != "" {
+			fixed += " " + m[3]
+		}
+		p.fix = replaceLine(i, fixed)
+	} else {
+		p.Message += " (thời gian phải là YYYY-MM-DD HH:MM)"
+	}
+	return p
+}
+
+// Diagnose checks the lines of a document for malformed checkboxes,
+// broken note banners, duplicate section titles, header-level jumps and
+// unterminated code fences.
+func Diagnose(lines []string) []Problem {
+	var problems []Problem
+	scanned := ScanLines(lines)
+	titles := make(map[string]int)
+	type headerLevel struct{ level, fixed int }
+	var stack []headerLevel
+	openFence := -1
+
+	for i, ml := range scanned {
+		line := lines[i]
+		switch ml.Kind {
+		case LineFenceOpen:
+			openFence = i
+		case LineFenceClose:
+			openFence = -1
+		case LineText:
+			if p := checkCheckbox(i, line); p != nil {
+				problems = append(problems, *p)
+			} else if p := checkNoteBanner(i, line); p != nil {
+				problems = append(problems, *p)
+			}
+		case LineHeader:
+			key := strings.ToLower(ml.Text)
+			if first, ok := titles[key]; ok {
+				problems = append(problems, Problem{Line: i + 1, Message: fmt.Sprintf("tiêu đề %q trùng với dòng %d (link và tìm kiếm chỉ thấy section đầu)", ml.Text, first+1)})
+			} else {
+				titles[key] = i
+			}
+
+			// A jump such as ## -> #### nests the section under a missing
+			// level. Headers are moved up relative to their (fixed) parent,
+			// so a whole subtree moves together.
+			for len(stack) > 0 && stack[len(stack)-1].level >= ml.Level {
+				stack = stack[:len(stack)-1]
+			}
+			level := ml.Level
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				level = min(ml.Level, parent.fixed+1)
+				if level != ml.Level {
+					p := Problem{Line: i + 1, Message: fmt.Sprintf("header nhảy từ cấp %d lên cấp %d: %q", parent.level, ml.Level, ml.Text)}
+					if ml.Level == parent.level+1 {
+						p.Message = fmt.Sprintf("header cấp %d phải lên cấp %d theo header cha: %q", ml.Level, level, ml.Text)
+					}
+					if !ml.Setext {
+						p.fix = replaceLine(i, strings.Repeat("#", level)+strings.TrimLeft(strings.TrimSpace(line), "#"))
+					}
+					problems = append(problems, p)
+				}
+			}
+			stack = append(stack, headerLevel{ml.Level, level})
+		}
+	}
+
+	if openFence >= 0 {
+		fence := fenceRun(strings.TrimSpace(lines[openFence]))
+		problems = append(problems, Problem{Line: openFence + 1, Message: "code block " + fence + " không được đóng (mọi header phía sau bị coi là code)",
+			fix: func(lines []string) []string {
+				if n := len(lines); n > 0 && lines[n-1] == "" {
+					return append(lines[:n-1], fence, "")
+				}
+				return append(lines, fence)
+			}})
+	}
+	return problems
+}
+
+// ApplyFixes applies the fixes of problems to lines and returns the
+// result and the number of problems fixed.
+func ApplyFixes(lines []string, problems []Problem) ([]string, int) {
+	fixed := append([]string(nil), lines...)
+	n := 0
+	for _, p := range problems {
+		if p.fix != nil {
+			fixed = p.fix(fixed)
+			n++
+		}
+	}
+	return fixed, n
+}
+
+// runDoctor implements "doctor [--fix]": it prints the problems found in
+// the document with their line numbers and fixes what it can, after
+// asking when stdin is a terminal. It fails if problems remain, so it
+// can guard a curriculum repository in CI.
+func runDoctor(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fix := fs.Bool("fix", false, "apply the automatic fixes without asking")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	problems := Diagnose(app.FileLines)
+	if len(problems) == 0 {
+		fmt.Fprintf(out, "%s: không có vấn đề\n", app.FilePath)
+		return nil
+	}
+	fixable := 0
+	for _, p := range problems {
+		mark := ""
+		if p.Fixable() {
+			mark = " [sửa được]"
+			fixable++
+		}
+		fmt.Fprintf(out, "%s:%d: %s%s\n", app.FilePath, p.Line, p.Message, mark)
+	}
+
+	if fixable > 0 && !*fix && isTerminal(os.Stdin) {
+		fmt.Fprintf(out, "\nSửa tự động %d/%d vấn đề? (y/N): ", fixable, len(problems))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		*fix = answer == "y" || answer == "yes"
+	}
+	if !*fix || fixable == 0 {
+		return fmt.Errorf("%d problems (%d fixable with --fix)", len(problems), fixable)
+	}
+
+	lines, n := ApplyFixes(app.FileLines, problems)
+	app.FileLines = lines
+	if err := app.SaveFile(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Đã sửa %d vấn đề\n", n)
+	if remaining := len(problems) - n; remaining > 0 {
+		return fmt.Errorf("%d problems must be fixed by hand", remaining)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const brokenMarkdown = `# Path
+
+## Basics
+
+-[ ] No space
+* [x]Star marker
+- [] Empty box
++ [X] Fine
+- [link](https://example.com)
+
+#### Jumped
+##### Child of jumped
+
+> Ghi chú [2025-01-31 10:00]: missing bold
+> **Ghi chú [hôm qua]:** bad time
+> **Ghi chú [2025-01-31 11:00]:** fine
+
+## Basics
+
+` + "```bash\n# not a header\n"
+
+func TestDiagnose(t *testing.T) {
+	problems := Diagnose(strings.Split(brokenMarkdown, "\n"))
+
+	lines := make(map[int]Problem)
+	for _, p := range problems {
+		lines[p.Line] = p
+	}
+	for _, want := range []int{5, 6, 7, 11, 12, 14, 15, 18, 20} {
+		if _, ok := lines[want]; !ok {
+			t.Errorf("Expected a problem on line %d, got %+v", want, problems)
+		}
+	}
+	if len(problems) != 9 {
+		t.Errorf("Expected 9 problems, got %d: %+v", len(problems), problems)
+	}
+	if lines[15].Fixable() || lines[18].Fixable() {
+		t.Error("A bad timestamp or duplicate title cannot be fixed automatically")
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	fixed, n := ApplyFixes(strings.Split(brokenMarkdown, "\n"), Diagnose(strings.Split(brokenMarkdown, "\n")))
+	if n != 7 {
+		t.Errorf("Expected 7 fixes, got %d", n)
+	}
+	for i, want := range map[int]string{
+		4:  "- [ ] No space",
+		5:  "* [x] Star marker",
+		6:  "- [ ] Empty box",
+		10: "### Jumped",
+		11: "#### Child of jumped",
+		13: "> **Ghi chú [2025-01-31 10:00]:** missing bold",
+	} {
+		if fixed[i] != want {
+			t.Errorf("line %d = %q, want %q", i+1, fixed[i], want)
+		}
+	}
+	if !strings.HasSuffix(strings.Join(fixed, "\n"), "# not a header\n```\n") {
+		t.Errorf("Expected the fence to be closed, got %q", fixed[len(fixed)-3:])
+	}
+
+	// Only the problems needing a hand fix remain
+	if remaining := Diagnose(fixed); len(remaining) != 2 {
+		t.Errorf("Expected 2 problems after fixing, got %+v", remaining)
+	}
+}
+
+func TestRunDoctor(t *testing.T) {
+	app := withTestApp(t)
+	app.FilePath = filepath.Join(t.TempDir(), "path.md")
+	if err := os.WriteFile(app.FilePath, []byte(app.FileContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runDoctor(nil, &out); err != nil {
+		t.Errorf("Expected the sample to be clean, got %v: %s", err, out.String())
+	}
+
+	app.FileLines = append(app.FileLines, "*[ ] extra")
+	if err := runDoctor(nil, &out); err == nil {
+		t.Error("Expected an error while problems remain")
+	}
+	if err := runDoctor([]string{"--fix"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(app.FilePath)
+	if !strings.HasSuffix(string(data), "\n* [ ] extra") {
+		t.Errorf("file ends with %q", data[len(data)-20:])
+	}
+}
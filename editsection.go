@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// findEditor returns the user's editor ($EDITOR, $VISUAL) or the first
+// common editor found in PATH, or "" if none is available.
+func findEditor() string {
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	if editor := os.Getenv("VISUAL"); editor != "" {
+		return editor
+	}
+	for _, e := range []string{"nano", "vim", "vi", "notepad"} {
+		if _, err := exec.LookPath(e); err == nil {
+			return e
+		}
+	}
+	return ""
+}
+
+// hasUnclosedFence reports whether content opens a fenced code block
+// that is never closed.
+func hasUnclosedFence(content string) bool {
+	scanned := ScanLines(strings.Split(content, "\n"))
+	last := scanned[len(scanned)-1].Kind
+	return last == LineFenceOpen || last == LineCode
+}
+
+// ReplaceSection replaces a section with edited raw markdown (header
+// plus content, as produced by sectionMarkdown). The header must keep its
+// level and fenced code blocks must be closed; the title, {#id} and header
+// style may change.
+func (a *App) ReplaceSection(idx int, markdown string) error {
+	if idx < 0 || idx >= len(a.Sections) {
+		return fmt.Errorf("section %d out of range", idx)
+	}
+	sec := a.Sections[idx]
+
+	lines := strings.Split(markdown, "\n")
+	header := a.scanLines(lines)[0]
+	if header.Kind != LineHeader || header.Level != sec.Level {
+		marker := strings.Repeat("#", sec.Level)
+		if sec.AsciiDoc {
+			marker = strings.Repeat("=", sec.Level)
+		}
+		return fmt.Errorf("first line must stay a level-%d header (%s ...)", sec.Level, marker)
+	}
+	headerLines := 1
+	if header.Setext {
+		headerLines = 2
+	}
+	content := strings.Join(lines[headerLines:], "\n")
+	if hasUnclosedFence(content) {
+		return fmt.Errorf("unclosed code fence")
+	}
+
+	// Editors add a final newline; don't let it accumulate across edits
+	if strings.HasSuffix(content, "\n") && !strings.HasSuffix(sec.Content(), "\n") {
+		content = strings.TrimSuffix(content, "\n")
+	}
+
+	title := header.Text
+	if title != sec.Title && a.Skipped[sec.Title] {
+		delete(a.Skipped, sec.Title)
+		a.Skipped[title] = true
+	}
+	if title != sec.Title && a.Bookmarks[sec.Title] {
+		delete(a.Bookmarks, sec.Title)
+		a.Bookmarks[title] = true
+	}
+
+	a.Sections[idx].Title = title
+	a.Sections[idx].ID = header.ID
+	a.Sections[idx].Setext = header.Setext
+	a.Sections[idx].SetContent(content)
+	a.UpdateFileSection(idx)
+	return nil
+}
+
+// handleEditSection opens the current section's raw markdown in the
+// user's editor and, once it validates, shows the change and overwrites
+// the section when confirmed.
+func handleEditSection() {
+	sec := app.GetCurrentSection()
+	if sec == nil {
+		return
+	}
+
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	inputReader := bufio.NewReader(keyboard)
+
+	editor := findEditor()
+	if editor == "" {
+		notify(SeverityError, "Không tìm thấy editor (set EDITOR)")
+		return
+	}
+
+	tmpFile, err := os.CreateTemp("", "sre-section-*.md")
+	if err != nil {
+		notify(SeverityError, "Lỗi tạo file tạm: %v", err)
+		return
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	original := sectionMarkdown(sec)
+	entry := app.Snapshot(fmt.Sprintf("Ghi đè section \"%s\"", sec.Title))
+	tmpFile.WriteString(original)
+	tmpFile.Close()
+
+	for {
+		cmd := exec.Command(editor, tmpPath)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			notify(SeverityError, "Lỗi mở editor: %v", err)
+			return
+		}
+
+		edited, err := os.ReadFile(tmpPath)
+		if err != nil {
+			notify(SeverityError, "Lỗi đọc file: %v", err)
+			return
+		}
+		if string(edited) == original {
+			notify(SeverityInfo, "Không có thay đổi")
+			return
+		}
+
+		err = app.ReplaceSection(app.CurrentIdx, string(edited))
+		if err == nil {
+			break
+		}
+
+		ClearScreen()
+		fmt.Printf("%s❌ Nội dung không hợp lệ: %v%s\n", Red, err, Reset)
+		fmt.Printf("\nSửa lại? (Y/n, n = bỏ thay đổi): ")
+		answer, _ := inputReader.ReadString('\n')
+		if strings.TrimSpace(strings.ToLower(answer)) == "n" {
+			return
+		}
+	}
+
+	if confirmChange(inputReader, entry, "") {
+		notify(SeveritySuccess, "Đã cập nhật section (z u để hoàn tác)")
+	}
+}
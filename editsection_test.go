@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Section Editing Tests
+// ============================================================================
+
+func TestHasUnclosedFence(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected bool
+	}{
+		{"text\n```bash\necho hi\n```\n", false},
+		{"```\nopen", true},
+		{"````md\n```\nnested\n```\n````", false},
+		{"~~~\ncode\n```", true},
+		{"no fences", false},
+	}
+
+	for _, tt := range tests {
+		if got := hasUnclosedFence(tt.content); got != tt.expected {
+			t.Errorf("hasUnclosedFence(%q) = %v, expected %v", tt.content, got, tt.expected)
+		}
+	}
+}
+
+func TestReplaceSection(t *testing.T) {
+	app := createTestApp()
+	count := len(app.Sections)
+
+	edited := "### Chapter 1: Fundamentals\n\n- [ ] Task one\n- [ ] New task\n- [ ] Another\n\nExtra paragraph.\n"
+	if err := app.ReplaceSection(2, edited); err != nil {
+		t.Fatalf("ReplaceSection failed: %v", err)
+	}
+
+	if len(app.Sections) != count {
+		t.Fatalf("Expected %d sections, got %d", count, len(app.Sections))
+	}
+
+	if app.Sections[2].Title != "Chapter 1: Fundamentals" {
+		t.Errorf("Expected renamed title, got %q", app.Sections[2].Title)
+	}
+
+	if app.Sections[3].Title != "Chapter 2: Advanced" || !strings.Contains(app.Sections[3].Content(), "- [ ] Advanced task") {
+		t.Errorf("Expected following section intact, got %+v", app.Sections[3])
+	}
+
+	if _, total := app.GetProgress(2); total != 3 {
+		t.Errorf("Expected 3 checkboxes after edit, got %d", total)
+	}
+}
+
+func TestReplaceSectionInvalid(t *testing.T) {
+	app := createTestApp()
+	original := app.FileContent
+
+	invalid := []string{
+		"## Chapter 1: Basics\n\ncontent\n", // level changed
+		"Chapter 1 without header\n",
+		"### Chapter 1: Basics\n\n```bash\necho unclosed\n",
+	}
+
+	for _, markdown := range invalid {
+		if err := app.ReplaceSection(2, markdown); err == nil {
+			t.Errorf("Expected error for %q", markdown)
+		}
+	}
+
+	if app.FileContent != original || app.Sections[2].Title != "Chapter 1: Basics" {
+		t.Error("Expected document unchanged after invalid edits")
+	}
+}
+
+func TestReplaceSectionKeepsSkipped(t *testing.T) {
+	app := createTestApp()
+	app.ToggleSkip(2)
+
+	if err := app.ReplaceSection(2, "### Renamed\n"+app.Sections[2].Content()); err != nil {
+		t.Fatalf("ReplaceSection failed: %v", err)
+	}
+
+	if !app.IsSkipped(2) {
+		t.Error("Expected renamed section to stay skipped")
+	}
+}
+
+func TestReplaceSectionSetext(t *testing.T) {
+	app := NewApp()
+	app.FileLines = strings.Split("Guide {#guide}\n=====\n\nIntro\n\n## Next\n", "\n")
+	app.ParseSections()
+
+	edited := sectionMarkdown(&app.Sections[0]) + "More text\n"
+	if err := app.ReplaceSection(0, edited); err != nil {
+		t.Fatalf("ReplaceSection failed: %v", err)
+	}
+
+	sec := app.Sections[0]
+	if !sec.Setext || sec.ID != "guide" || !strings.Contains(sec.Content(), "More text") {
+		t.Errorf("Expected setext header with id kept, got %+v", sec)
+	}
+
+	if len(app.Sections) != 2 || app.Sections[1].Title != "Next" {
+		t.Errorf("Expected following section intact, got %v", sectionTitles(app))
+	}
+}
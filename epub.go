@@ -0,0 +1,192 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// epubChapter is a level-2 section with its subsections.
+type epubChapter struct {
+	Title    string
+	Sections []int
+}
+
+// epubChapters groups the sections into chapters, one per level-2
+// section. Sections before the first one (title, introduction) form a
+// chapter of their own if they have content.
+func (a *App) epubChapters() []epubChapter {
+	var intro epubChapter
+	var chapters []epubChapter
+	hasIntro := false
+	for i, sec := range a.Sections {
+		switch {
+		case sec.Level <= 2 && (sec.Level == 2 || len(chapters) > 0):
+			chapters = append(chapters, epubChapter{Title: sec.Title, Sections: []int{i}})
+		case len(chapters) > 0:
+			last := &chapters[len(chapters)-1]
+			last.Sections = append(last.Sections, i)
+		default:
+			if intro.Title == "" {
+				intro.Title = sec.Title
+			}
+			intro.Sections = append(intro.Sections, i)
+			hasIntro = hasIntro || strings.TrimSpace(sec.Content()) != ""
+		}
+	}
+	if hasIntro || (len(chapters) == 0 && len(intro.Sections) > 0) {
+		chapters = append([]epubChapter{intro}, chapters...)
+	}
+	return chapters
+}
+
+// epubXHTML writes a complete XHTML page, as required in EPUB files.
+func epubXHTML(out io.Writer, title string, body func(io.Writer)) {
+	fmt.Fprintf(out, "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<!DOCTYPE html>\n<html xmlns=\"http://www.w3.org/1999/xhtml\" xmlns:epub=\"http://www.idpf.org/2007/ops\" lang=\"vi\" xml:lang=\"vi\">\n<head>\n<title>%s</title>\n<link rel=\"stylesheet\" type=\"text/css\" href=\"style.css\"/>\n</head>\n<body>\n",
+		html.EscapeString(title))
+	body(out)
+	fmt.Fprint(out, "</body>\n</html>\n")
+}
+
+// writeChapter writes the sections of a chapter. The chapter's own
+// section becomes h1, so subsections shift one level up.
+func (a *App) writeChapter(out io.Writer, ch epubChapter) {
+	top := a.Sections[ch.Sections[0]].Level
+	for _, i := range ch.Sections {
+		sec := &a.Sections[i]
+		level := max(1, min(sec.Level-top+1, 4))
+		fmt.Fprintf(out, "<h%d id=\"s%d\">%s</h%d>\n", level, i+1, inlineHTML(sec.Title), level)
+		fmt.Fprint(out, a.sectionProgressHTML(i))
+		writeSectionHTML(out, sec.Content())
+	}
+}
+
+// epubContainer points readers at the package document.
+const epubContainer = `<?xml version="1.0" encoding="utf-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// WriteEPUB writes the annotated document as an EPUB 3 book with one
+// chapter per level-2 section. An NCX table of contents is included for
+// older readers.
+func (a *App) WriteEPUB(out io.Writer, title string, modified time.Time) error {
+	chapters := a.epubChapters()
+	id := fmt.Sprintf("urn:sre-learn:%x", sha256.Sum256([]byte(a.FilePath)))
+	z := zip.NewWriter(out)
+
+	// The mimetype must be the first entry, stored uncompressed
+	w, err := z.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	io.WriteString(w, "application/epub+zip")
+
+	files := map[string]string{
+		"META-INF/container.xml": epubContainer,
+		"OEBPS/style.css":        documentCSS + "\nh1{page-break-before:auto}",
+	}
+
+	var manifest, spine, nav, ncx strings.Builder
+	for n, ch := range chapters {
+		name := fmt.Sprintf("chapter%02d.xhtml", n+1)
+		var page bytes.Buffer
+		epubXHTML(&page, ch.Title, func(out io.Writer) { a.writeChapter(out, ch) })
+		files["OEBPS/"+name] = page.String()
+
+		title := html.EscapeString(ch.Title)
+		fmt.Fprintf(&manifest, "    <item id=\"c%d\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", n+1, name)
+		fmt.Fprintf(&spine, "    <itemref idref=\"c%d\"/>\n", n+1)
+		fmt.Fprintf(&nav, "<li><a href=\"%s\">%s</a></li>\n", name, title)
+		fmt.Fprintf(&ncx, "    <navPoint id=\"c%d\" playOrder=\"%d\"><navLabel><text>%s</text></navLabel><content src=\"%s\"/></navPoint>\n",
+			n+1, n+1, title, name)
+	}
+
+	var navPage bytes.Buffer
+	epubXHTML(&navPage, title, func(out io.Writer) {
+		fmt.Fprintf(out, "<nav epub:type=\"toc\" id=\"toc\">\n<h1>%s</h1>\n<ol>\n%s</ol>\n</nav>\n", html.EscapeString(title), nav.String())
+	})
+	files["OEBPS/nav.xhtml"] = navPage.String()
+
+	files["OEBPS/toc.ncx"] = fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head><meta name="dtb:uid" content="%s"/></head>
+  <docTitle><text>%s</text></docTitle>
+  <navMap>
+%s  </navMap>
+</ncx>
+`, id, html.EscapeString(title), ncx.String())
+
+	files["OEBPS/content.opf"] = fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid" xml:lang="vi">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="uid">%s</dc:identifier>
+    <dc:title>%s</dc:title>
+    <dc:language>vi</dc:language>
+    <meta property="dcterms:modified">%s</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+    <item id="css" href="style.css" media-type="text/css"/>
+%s  </manifest>
+  <spine toc="ncx">
+%s  </spine>
+</package>
+`, id, html.EscapeString(title), modified.UTC().Format("2006-01-02T15:04:05Z"), manifest.String(), spine.String())
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		w, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, files[name]); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+// runExportEPUB implements "export epub [-o file]".
+func runExportEPUB(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("export epub", flag.ContinueOnError)
+	output := fs.String("o", "", "output file (default: the document name with .epub)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	target := *output
+	if target == "" {
+		target = strings.TrimSuffix(filepath.Base(app.FilePath), filepath.Ext(app.FilePath)) + ".epub"
+	}
+
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if err := app.WriteEPUB(f, app.documentTitle(), time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Wrote %s (%d chapters)\n", target, len(app.epubChapters()))
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEPUBChapters(t *testing.T) {
+	app := createTestApp()
+	chapters := app.epubChapters()
+
+	want := []string{"Main Title", "Giai đoạn 1: Learning", "Giai đoạn 2: Practice"}
+	if len(chapters) != len(want) {
+		t.Fatalf("got %d chapters, want %d", len(chapters), len(want))
+	}
+	for i, ch := range chapters {
+		if ch.Title != want[i] {
+			t.Errorf("chapter %d = %q, want %q", i, ch.Title, want[i])
+		}
+	}
+	if len(chapters[1].Sections) != 3 {
+		t.Errorf("phase 1 should include its 2 subsections, got %v", chapters[1].Sections)
+	}
+}
+
+func TestWriteEPUB(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 2
+	app.AddNote("Đọc lại *probe*")
+	app.UpdateFileSection(2)
+	app.ParseSections()
+
+	var buf bytes.Buffer
+	if err := app.WriteEPUB(&buf, "Main Title", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first := z.File[0]; first.Name != "mimetype" || first.Method != zip.Store {
+		t.Errorf("first entry = %s (method %d), want stored mimetype", first.Name, first.Method)
+	}
+
+	files := map[string]string{}
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(r)
+		r.Close()
+		files[f.Name] = string(data)
+
+		// Readers reject pages that are not well-formed XML
+		if strings.HasSuffix(f.Name, ".xhtml") || strings.HasSuffix(f.Name, ".opf") || strings.HasSuffix(f.Name, ".ncx") {
+			d := xml.NewDecoder(bytes.NewReader(data))
+			for {
+				if _, err := d.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Errorf("%s: %v", f.Name, err)
+					break
+				}
+			}
+		}
+	}
+
+	if !strings.Contains(files["OEBPS/content.opf"], `<itemref idref="c3"/>`) {
+		t.Error("spine should list 3 chapters")
+	}
+	chapter := files["OEBPS/chapter02.xhtml"]
+	for _, want := range []string{`<h1 id="s2">Giai đoạn 1: Learning</h1>`, `<h2 id="s3">Chapter 1: Basics</h2>`, `<aside class="note">`, "<em>probe</em>"} {
+		if !strings.Contains(chapter, want) {
+			t.Errorf("chapter 2 missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Ticket is an issue created in an external tracker.
+type Ticket struct {
+	// ID is the tracker's key for the ticket, e.g. "SRE-42"
+	ID  string
+	URL string
+}
+
+// TaskExporter pushes checklist items to an issue tracker. Fields are
+// the ticket fields after the field mapping has been applied.
+type TaskExporter interface {
+	// Payload returns the request the exporter would send, so dry runs
+	// show exactly what Export does
+	Payload(fields map[string]string) any
+	// Export creates the ticket
+	Export(fields map[string]string) (Ticket, error)
+}
+
+// exporterFactory creates an exporter from the environment.
+type exporterFactory struct {
+	// Fields is the default field mapping: ticket field -> template
+	Fields map[string]string
+	// New connects the exporter; dryRun skips credential checks
+	New func(dryRun bool) (TaskExporter, error)
+}
+
+// taskExporters lists the available trackers by name. New trackers only
+// need to be added here.
+var taskExporters = map[string]exporterFactory{
+	"jira": {
+		Fields: map[string]string{"summary": "{title}", "description": "{context}", "labels": "sre-learn"},
+		New:    newJiraExporter,
+	},
+	"linear": {
+		Fields: map[string]string{"title": "{title}", "description": "{context}"},
+		New:    newLinearExporter,
+	},
+}
+
+// exporterNames returns the names of the available exporters, sorted.
+func exporterNames() []string {
+	names := make([]string, 0, len(taskExporters))
+	for name := range taskExporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// itemValues returns the placeholders available to field templates.
+func itemValues(item ChecklistItem, filePath string) map[string]string {
+	path := strings.Join(item.Path, " › ")
+	phase := item.Path[0]
+	if len(item.Path) > 1 {
+		phase = item.Path[1]
+	}
+	return map[string]string{
+		"title":   item.Text,
+		"section": item.Path[len(item.Path)-1],
+		"phase":   phase,
+		"path":    path,
+		"file":    filePath,
+		"context": fmt.Sprintf("Section: %s\n\nExported by sre-learn from %s.", path, filePath),
+	}
+}
+
+// exportFields applies a field mapping to an item. The configured
+// mapping is layered over the exporter's defaults; an empty template
+// drops the field.
+func exportFields(item ChecklistItem, filePath string, defaults, overrides map[string]string) map[string]string {
+	values := itemValues(item, filePath)
+	replacements := make([]string, 0, 2*len(values))
+	for name, value := range values {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	replacer := strings.NewReplacer(replacements...)
+
+	fields := make(map[string]string)
+	for field, tmpl := range defaults {
+		fields[field] = tmpl
+	}
+	for field, tmpl := range overrides {
+		fields[field] = tmpl
+	}
+	for field, tmpl := range fields {
+		if tmpl == "" {
+			delete(fields, field)
+			continue
+		}
+		fields[field] = replacer.Replace(tmpl)
+	}
+	return fields
+}
+
+// postJSON sends body as JSON and decodes the JSON response into result.
+func postJSON(client *http.Client, url string, headers map[string]string, body, result any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s: %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// requireEnv reports the named environment variables that are not set.
+func requireEnv(names ...string) error {
+	var missing []string
+	for _, name := range names {
+		if os.Getenv(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing environment variables: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// JiraExporter creates issues with the Jira REST API v2.
+type JiraExporter struct {
+	BaseURL   string
+	Email     string
+	Token     string
+	Project   string
+	IssueType string
+	HTTP      *http.Client
+}
+
+// newJiraExporter reads JIRA_URL, JIRA_EMAIL, JIRA_API_TOKEN and
+// JIRA_PROJECT (and optionally JIRA_ISSUE_TYPE).
+func newJiraExporter(dryRun bool) (TaskExporter, error) {
+	j := &JiraExporter{
+		BaseURL:   strings.TrimSuffix(os.Getenv("JIRA_URL"), "/"),
+		Email:     os.Getenv("JIRA_EMAIL"),
+		Token:     os.Getenv("JIRA_API_TOKEN"),
+		Project:   os.Getenv("JIRA_PROJECT"),
+		IssueType: os.Getenv("JIRA_ISSUE_TYPE"),
+		HTTP:      &http.Client{Timeout: 15 * time.Second},
+	}
+	if j.IssueType == "" {
+		j.IssueType = "Task"
+	}
+	if !dryRun {
+		if err := requireEnv("JIRA_URL", "JIRA_EMAIL", "JIRA_API_TOKEN", "JIRA_PROJECT"); err != nil {
+			return nil, err
+		}
+	}
+	return j, nil
+}
+
+// Payload builds the create-issue request. "labels" is split on commas,
+// as Jira expects a list.
+func (j *JiraExporter) Payload(fields map[string]string) any {
+	issue := map[string]any{
+		"project":   map[string]string{"key": j.Project},
+		"issuetype": map[string]string{"name": j.IssueType},
+	}
+	for field, value := range fields {
+		if field == "labels" {
+			var labels []string
+			for _, label := range strings.Split(value, ",") {
+				// Jira labels cannot contain spaces
+				if label = strings.Join(strings.Fields(label), "-"); label != "" {
+					labels = append(labels, label)
+				}
+			}
+			issue[field] = labels
+			continue
+		}
+		issue[field] = value
+	}
+	return map[string]any{"fields": issue}
+}
+
+// Export creates the issue.
+func (j *JiraExporter) Export(fields map[string]string) (Ticket, error) {
+	var created struct {
+		Key string `json:"key"`
+	}
+	headers := map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(j.Email+":"+j.Token))}
+	if err := postJSON(j.HTTP, j.BaseURL+"/rest/api/2/issue", headers, j.Payload(fields), &created); err != nil {
+		return Ticket{}, err
+	}
+	return Ticket{ID: created.Key, URL: j.BaseURL + "/browse/" + created.Key}, nil
+}
+
+// linearAPI is the Linear GraphQL endpoint.
+const linearAPI = "https://api.linear.app/graphql"
+
+// linearCreateIssue is the mutation creating one issue.
+const linearCreateIssue = `mutation IssueCreate($input: IssueCreateInput!) {
+  issueCreate(input: $input) { success issue { identifier url } }
+}`
+
+// LinearExporter creates issues with the Linear GraphQL API.
+type LinearExporter struct {
+	URL    string
+	APIKey string
+	TeamID string
+	HTTP   *http.Client
+}
+
+// newLinearExporter reads LINEAR_API_KEY and LINEAR_TEAM_ID.
+func newLinearExporter(dryRun bool) (TaskExporter, error) {
+	l := &LinearExporter{
+		URL:    linearAPI,
+		APIKey: os.Getenv("LINEAR_API_KEY"),
+		TeamID: os.Getenv("LINEAR_TEAM_ID"),
+		HTTP:   &http.Client{Timeout: 15 * time.Second},
+	}
+	if !dryRun {
+		if err := requireEnv("LINEAR_API_KEY", "LINEAR_TEAM_ID"); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Payload builds the GraphQL request; mapped fields become fields of
+// IssueCreateInput.
+func (l *LinearExporter) Payload(fields map[string]string) any {
+	input := map[string]any{"teamId": l.TeamID}
+	for field, value := range fields {
+		input[field] = value
+	}
+	return map[string]any{
+		"query":     linearCreateIssue,
+		"variables": map[string]any{"input": input},
+	}
+}
+
+// Export creates the issue.
+func (l *LinearExporter) Export(fields map[string]string) (Ticket, error) {
+	var resp struct {
+		Data struct {
+			IssueCreate struct {
+				Success bool `json:"success"`
+				Issue   struct {
+					Identifier string `json:"identifier"`
+					URL        string `json:"url"`
+				} `json:"issue"`
+			} `json:"issueCreate"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	headers := map[string]string{"Authorization": l.APIKey}
+	if err := postJSON(l.HTTP, l.URL, headers, l.Payload(fields), &resp); err != nil {
+		return Ticket{}, err
+	}
+	if len(resp.Errors) > 0 {
+		return Ticket{}, fmt.Errorf("linear: %s", resp.Errors[0].Message)
+	}
+	if !resp.Data.IssueCreate.Success {
+		return Ticket{}, fmt.Errorf("linear: issue was not created")
+	}
+	issue := resp.Data.IssueCreate.Issue
+	return Ticket{ID: issue.Identifier, URL: issue.URL}, nil
+}
+
+// exportKey identifies an item exported to a tracker in the state.
+func exportKey(exporter, itemKey string) string {
+	return exporter + ":" + itemKey
+}
+
+// ExportSection exports the unchecked items of a section that were not
+// exported to the same tracker before. With dryRun the payloads are
+// printed instead of sent.
+func (a *App) ExportSection(name string, exporter TaskExporter, overrides map[string]string, idx int, dryRun bool, out io.Writer) error {
+	for _, item := range a.SectionItems(idx) {
+		key := exportKey(name, item.Key)
+		if item.Checked || a.Exported[key] != "" {
+			continue
+		}
+		fields := exportFields(item, a.FilePath, taskExporters[name].Fields, overrides)
+
+		if dryRun {
+			data, err := json.MarshalIndent(exporter.Payload(fields), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "# %s\n%s\n", item.Text, data)
+			continue
+		}
+
+		ticket, err := exporter.Export(fields)
+		if err != nil {
+			return fmt.Errorf("cannot export %q: %w", item.Text, err)
+		}
+		if a.Exported == nil {
+			a.Exported = make(map[string]string)
+		}
+		a.Exported[key] = ticket.ID
+		fmt.Fprintf(out, "%s %s %s\n", ticket.ID, item.Text, ticket.URL)
+	}
+	return nil
+}
+
+// documentExporters export the whole document to a file format, as
+// "export <format> [flags]".
+var documentExporters = map[string]func(args []string, out io.Writer) error{
+	"pdf":  runExportPDF,
+	"epub": runExportEPUB,
+}
+
+// runExport implements "export --to <tracker> [--dry-run] <section>" and
+// the document formats ("export pdf", "export epub", and those added by
+// plugins).
+func runExport(args []string, out io.Writer) error {
+	if len(args) > 0 && documentExporters[args[0]] != nil {
+		return documentExporters[args[0]](args[1:], out)
+	}
+	if len(args) > 0 {
+		if p := findPluginExporter(plugins, args[0]); p != nil {
+			return runPluginExport(*p, args[0], args[1:], out)
+		}
+	}
+
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	to := fs.String("to", "", "tracker to export to ("+strings.Join(exporterNames(), ", ")+")")
+	dryRun := fs.Bool("dry-run", false, "print the requests instead of sending them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	factory, ok := taskExporters[*to]
+	if !ok || fs.NArg() == 0 {
+		return fmt.Errorf("usage: sre-learn export --to %s [--dry-run] <section-number|title>", strings.Join(exporterNames(), "|"))
+	}
+
+	query := strings.Join(fs.Args(), " ")
+	idx := app.FindSection(query)
+	if idx < 0 {
+		return fmt.Errorf("section %q not found", query)
+	}
+
+	exporter, err := factory.New(*dryRun)
+	if err != nil {
+		return err
+	}
+	pageSize, _ := app.LoadState()
+	err = app.ExportSection(*to, exporter, config.ExportFields[*to], idx, *dryRun, out)
+	if !*dryRun {
+		// Keep the tickets created so far even if one failed
+		if saveErr := app.SaveState(pageSize); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+	return err
+}
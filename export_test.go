@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Task Export Tests
+// ============================================================================
+
+func TestExportFieldsMapping(t *testing.T) {
+	item := ChecklistItem{Text: "Task one", Path: []string{"Main", "Giai đoạn 1", "Chapter 1"}}
+	fields := exportFields(item, "path.md",
+		map[string]string{"summary": "{title}", "labels": "sre-learn"},
+		map[string]string{"labels": "sre-learn,{phase}", "customfield_1": "{section}", "summary": "[{phase}] {title}"})
+
+	want := map[string]string{
+		"summary":       "[Giai đoạn 1] Task one",
+		"labels":        "sre-learn,Giai đoạn 1",
+		"customfield_1": "Chapter 1",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Expected %v, got %v", want, fields)
+	}
+
+	dropped := exportFields(item, "path.md", map[string]string{"labels": "x"}, map[string]string{"labels": ""})
+	if len(dropped) != 0 {
+		t.Errorf("Expected an empty template to drop the field, got %v", dropped)
+	}
+}
+
+func TestJiraPayload(t *testing.T) {
+	j := &JiraExporter{Project: "SRE", IssueType: "Task"}
+	payload, _ := json.Marshal(j.Payload(map[string]string{"summary": "Task one", "labels": "sre-learn, Giai đoạn 1"}))
+
+	for _, want := range []string{`"project":{"key":"SRE"}`, `"summary":"Task one"`, `"labels":["sre-learn","Giai-đoạn-1"]`} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("Expected payload to contain %s, got %s", want, payload)
+		}
+	}
+}
+
+func TestExportSectionDryRunSendsNothing(t *testing.T) {
+	app := createTestApp()
+	exporter := &JiraExporter{BaseURL: "http://127.0.0.1:1", Project: "SRE", HTTP: http.DefaultClient}
+
+	var out bytes.Buffer
+	if err := app.ExportSection("jira", exporter, nil, 2, true, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "# Task one") || !strings.Contains(out.String(), `"summary": "Task three"`) {
+		t.Errorf("Expected payloads of unchecked items, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "Task two") || len(app.Exported) != 0 {
+		t.Error("Expected checked items skipped and nothing recorded in a dry run")
+	}
+}
+
+func TestLinearExport(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "lin_key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var body map[string]any
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+		requests = append(requests, body)
+		w.Write([]byte(`{"data":{"issueCreate":{"success":true,"issue":{"identifier":"SRE-7","url":"https://linear.app/t/SRE-7"}}}}`))
+	}))
+	defer server.Close()
+
+	app := createTestApp()
+	exporter := &LinearExporter{URL: server.URL, APIKey: "lin_key", TeamID: "team-1", HTTP: server.Client()}
+
+	var out bytes.Buffer
+	if err := app.ExportSection("linear", exporter, nil, 2, false, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 issues created, got %d", len(requests))
+	}
+	input := requests[0]["variables"].(map[string]any)["input"].(map[string]any)
+	if input["teamId"] != "team-1" || input["title"] != "Task one" || !strings.Contains(input["description"].(string), "Chapter 1: Basics") {
+		t.Errorf("Unexpected input %v", input)
+	}
+
+	// Exported items are not exported again
+	if err := app.ExportSection("linear", exporter, nil, 2, false, &out); err != nil || len(requests) != 2 {
+		t.Errorf("Expected no duplicate tickets, got %d requests (%v)", len(requests), err)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Recall ratings of the flash review.
+const (
+	RecallForgot = 1 + iota
+	RecallVague
+	RecallGood
+	RecallEasy
+)
+
+// recallLabels are the rating names shown in the flash review.
+var recallLabels = map[int]string{
+	RecallForgot: "Quên",
+	RecallVague:  "Mang máng",
+	RecallGood:   "Nhớ",
+	RecallEasy:   "Nhớ rõ",
+}
+
+// maxRepetitionDays drops a section from the queue once it is recalled
+// well at longer intervals than this.
+const maxRepetitionDays = 60
+
+// Repetition schedules the next re-study of a section.
+type Repetition struct {
+	// Interval is the current spacing in days
+	Interval int       `json:"interval_days"`
+	Due      time.Time `json:"due"`
+}
+
+// Rate records the recall of an item of the section with the given
+// title. A forgotten or vague item queues the section for re-study now;
+// a good recall of a queued section spaces it out (twice the interval,
+// four times if easy) until it leaves the queue.
+func (a *App) Rate(title string, rating int, now time.Time) {
+	if rating <= RecallVague {
+		if a.Repetition == nil {
+			a.Repetition = make(map[string]Repetition)
+		}
+		a.Repetition[title] = Repetition{Interval: 1, Due: now}
+		return
+	}
+	r, queued := a.Repetition[title]
+	if !queued || now.Before(r.Due) {
+		return
+	}
+	factor := 2
+	if rating == RecallEasy {
+		factor = 4
+	}
+	r.Interval *= factor
+	if r.Interval > maxRepetitionDays {
+		delete(a.Repetition, title)
+		return
+	}
+	r.Due = now.AddDate(0, 0, r.Interval)
+	a.Repetition[title] = r
+}
+
+// DueSections returns the queued sections due for re-study at now, in
+// document order.
+func (a *App) DueSections(now time.Time) []int {
+	var due []int
+	for i, sec := range a.Sections {
+		if r, ok := a.Repetition[sec.Title]; ok && !now.Before(r.Due) {
+			due = append(due, i)
+		}
+	}
+	return due
+}
+
+// flashItem is a checked item with the section it belongs to.
+type flashItem struct {
+	ChecklistItem
+	Section int
+}
+
+// flashItems returns the checked items of the sections that are not
+// skipped, shuffled with rng.
+func (a *App) flashItems(rng *rand.Rand) []flashItem {
+	var items []flashItem
+	for i := range a.Sections {
+		if a.IsSkipped(i) {
+			continue
+		}
+		for _, item := range a.SectionItems(i) {
+			if item.Checked {
+				items = append(items, flashItem{item, i})
+			}
+		}
+	}
+	rng.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+	return items
+}
+
+// handleFlashReview walks through the checked items in random order and
+// asks for a recall rating of each; forgotten items queue their section
+// for re-study. The sections due are listed at the end.
+func handleFlashReview() {
+	items := app.flashItems(rand.New(rand.NewSource(time.Now().UnixNano())))
+	if len(items) == 0 {
+		notify(SeverityInfo, "Chưa có mục nào đã hoàn thành để ôn")
+		return
+	}
+
+	rated, low := 0, 0
+review:
+	for i, item := range items {
+		ClearScreen()
+		fmt.Printf("%s\n\n", barLine(BgMagenta+White+Bold, fmt.Sprintf(" 🧠 ÔN TẬP %d/%d", i+1, len(items)), app.TermWidth))
+		fmt.Printf("  %s%s%s\n\n", Dim, strings.Join(item.Path, " "+glyphs.Crumb+" "), Reset)
+		for _, l := range wrapVisible(renderInline(item.Text), max(20, app.TermWidth-4)) {
+			fmt.Println("  " + Bold + l + Reset)
+		}
+		fmt.Printf("\n  Bạn còn nhớ mục này đến đâu?\n\n")
+		for rating := RecallForgot; rating <= RecallEasy; rating++ {
+			fmt.Printf("  %s%d%s %s\n", Cyan, rating, Reset, recallLabels[rating])
+		}
+		fmt.Printf("\n%s[1-4 đánh giá, Space bỏ qua, q dừng]%s", Dim, Reset)
+
+		for {
+			key := readKey()
+			if key == "q" || key == "Q" || key == "Esc" {
+				break review
+			}
+			if key == "Space" || key == "n" {
+				break
+			}
+			if len(key) == 1 && key[0] >= '1' && key[0] <= '4' {
+				rating := int(key[0] - '0')
+				app.Rate(app.Sections[item.Section].Title, rating, time.Now())
+				rated++
+				if rating <= RecallVague {
+					low++
+				}
+				break
+			}
+		}
+	}
+	app.SaveState(renderer.PageSize)
+
+	lines := []string{fmt.Sprintf("Đã đánh giá %d mục, %d mục cần ôn lại.", rated, low), ""}
+	due := app.DueSections(time.Now())
+	if len(due) > 0 {
+		lines = append(lines, Yellow+"Section cần học lại:"+Reset)
+		for _, idx := range due {
+			lines = append(lines, fmt.Sprintf("  %s%3d.%s %s", Cyan, idx+1, Reset, app.Sections[idx].Title))
+		}
+	}
+	showOverlay("🧠 KẾT QUẢ ÔN TẬP", lines)
+}
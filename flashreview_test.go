@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRateQueuesAndSpacesSections(t *testing.T) {
+	app := createTestApp()
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	title := app.Sections[2].Title
+
+	// A good recall of a section that is not queued changes nothing
+	app.Rate(title, RecallGood, now)
+	if len(app.Repetition) != 0 {
+		t.Fatalf("Repetition = %v", app.Repetition)
+	}
+
+	app.Rate(title, RecallForgot, now)
+	if due := app.DueSections(now); len(due) != 1 || due[0] != 2 {
+		t.Fatalf("DueSections = %v", due)
+	}
+
+	app.Rate(title, RecallGood, now)
+	if r := app.Repetition[title]; r.Interval != 2 || !r.Due.Equal(now.AddDate(0, 0, 2)) {
+		t.Errorf("after a good recall: %+v", r)
+	}
+	if due := app.DueSections(now); len(due) != 0 {
+		t.Errorf("Expected nothing due before the interval, got %v", due)
+	}
+
+	// Ratings before the section is due again do not space it further
+	app.Rate(title, RecallEasy, now.Add(time.Hour))
+	if r := app.Repetition[title]; r.Interval != 2 {
+		t.Errorf("early rating changed the interval: %+v", r)
+	}
+
+	later := now.AddDate(0, 0, 2)
+	for _, want := range []int{8, 32} {
+		app.Rate(title, RecallEasy, later)
+		if r := app.Repetition[title]; r.Interval != want {
+			t.Fatalf("interval = %d, want %d", r.Interval, want)
+		}
+		later = app.Repetition[title].Due
+	}
+	app.Rate(title, RecallEasy, later)
+	if _, ok := app.Repetition[title]; ok {
+		t.Error("Expected the section to leave the queue")
+	}
+}
+
+func TestFlashItems(t *testing.T) {
+	app := createTestApp()
+	app.ToggleSkip(5) // Exercise 1: both of its items are checked
+
+	items := app.flashItems(rand.New(rand.NewSource(1)))
+	if len(items) != 1 || items[0].Section != 2 || items[0].Text != "Task two completed" {
+		t.Errorf("flashItems = %+v", items)
+	}
+}
+
+func TestRepetitionStateRoundTrip(t *testing.T) {
+	app := createTestApp()
+	app.Rate(app.Sections[3].Title, RecallVague, time.Now())
+
+	restored := createTestApp()
+	restored.ApplyState(app.CaptureState(20))
+	if len(restored.DueSections(time.Now())) != 1 {
+		t.Errorf("Repetition not restored: %v", restored.Repetition)
+	}
+}
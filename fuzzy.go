@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyMatch reports whether the runes of query appear in text in order
+// (case-insensitive, spaces in the query ignored), like fzf. The score
+// favors consecutive runes and runes at the start of words; positions
+// are the rune indexes of the best match in text.
+func fuzzyMatch(query, text string) (score int, positions []int, ok bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	t := []rune(strings.ToLower(text))
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+
+	best := -1
+	for start, r := range t {
+		if r != q[0] {
+			continue
+		}
+		s, pos, found := fuzzyMatchFrom(q, t, start)
+		if found && s > best {
+			best, positions = s, pos
+		}
+	}
+	return best, positions, best >= 0
+}
+
+// fuzzyMatchFrom matches q greedily in t with the first rune at start.
+func fuzzyMatchFrom(q, t []rune, start int) (int, []int, bool) {
+	score := 0
+	positions := make([]int, 0, len(q))
+	i := start
+	for _, r := range q {
+		for i < len(t) && t[i] != r {
+			i++
+		}
+		if i == len(t) {
+			return 0, nil, false
+		}
+
+		score++
+		if n := len(positions); n > 0 {
+			if gap := i - positions[n-1] - 1; gap == 0 {
+				score += 5
+			} else {
+				score -= min(gap, 3)
+			}
+		}
+		if i == 0 || (!unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1])) {
+			score += 3
+		}
+		positions = append(positions, i)
+		i++
+	}
+	return score, positions, true
+}
+
+// highlightPositions styles the runes of text at the given indexes with
+// hl, returning to base after each of them.
+func highlightPositions(text string, positions []int, hl, base string) string {
+	if len(positions) == 0 {
+		return text
+	}
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p] = true
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(text) {
+		if marked[i] {
+			b.WriteString(hl + string(r) + Reset + base)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
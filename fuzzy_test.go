@@ -0,0 +1,58 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	if _, _, ok := fuzzyMatch("", "Anything"); !ok {
+		t.Error("empty query should match")
+	}
+	if _, _, ok := fuzzyMatch("xyz", "Chapter 1: Basics"); ok {
+		t.Error("non-subsequence should not match")
+	}
+	if _, _, ok := fuzzyMatch("scb", "Chapter 1: Basics"); ok {
+		t.Error("runes out of order should not match")
+	}
+
+	_, positions, ok := fuzzyMatch("K8S Dpl", "k8s Deployment")
+	if !ok {
+		t.Fatal("expected a case-insensitive match ignoring spaces")
+	}
+	if want := []int{0, 1, 2, 4, 6, 7}; !reflect.DeepEqual(positions, want) {
+		t.Errorf("positions = %v, want %v", positions, want)
+	}
+
+	// Consecutive runes and word starts rank first
+	adv, _, _ := fuzzyMatch("adv", "Chapter 2: Advanced")
+	scattered, _, _ := fuzzyMatch("adv", "Main Title And Overview")
+	if adv <= scattered {
+		t.Errorf("consecutive match scored %d, scattered %d", adv, scattered)
+	}
+	start, _, _ := fuzzyMatch("ba", "Chapter 1: Basics")
+	inner, _, _ := fuzzyMatch("ba", "Database")
+	if start <= inner {
+		t.Errorf("word start scored %d, inner %d", start, inner)
+	}
+}
+
+func TestFuzzyMatchUnicode(t *testing.T) {
+	_, positions, ok := fuzzyMatch("gđ2", "Giai đoạn 2")
+	if !ok {
+		t.Fatal("expected a match on Vietnamese title")
+	}
+	if want := []int{0, 5, 10}; !reflect.DeepEqual(positions, want) {
+		t.Errorf("positions = %v, want %v", positions, want)
+	}
+}
+
+func TestHighlightPositions(t *testing.T) {
+	got := highlightPositions("đoạn", []int{0, 2}, "<", ">")
+	if want := "<đ" + Reset + ">o<ạ" + Reset + ">n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := highlightPositions("plain", nil, "<", ">"); got != "plain" {
+		t.Errorf("got %q, want unchanged", got)
+	}
+}
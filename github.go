@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ChecklistItem is one checkbox of the document with its context.
+type ChecklistItem struct {
+	// Key identifies the item across edits (see checkboxItemKey)
+	Key string
+	// Path is the section hierarchy, outermost first, ending with the
+	// item's own section
+	Path    []string
+	Text    string
+	Checked bool
+}
+
+// SectionItems returns the checklist items of a section, leaving out
+// personal tasks.
+func (a *App) SectionItems(idx int) []ChecklistItem {
+	if idx < 0 || idx >= len(a.Sections) {
+		return nil
+	}
+	sec := a.Sections[idx]
+	var path []string
+	for _, anc := range a.Ancestors(idx) {
+		path = append(path, a.Sections[anc].Title)
+	}
+	path = append(path, sec.Title)
+
+	var items []ChecklistItem
+	for _, line := range strings.Split(sec.Content(), "\n") {
+		if !isCheckbox(line) || isPersonalTask(line) {
+			continue
+		}
+		items = append(items, ChecklistItem{
+			Key:     checkboxItemKey(sec.Title, line),
+			Path:    path,
+			Text:    checkboxText(line),
+			Checked: isChecked(line),
+		})
+	}
+	return items
+}
+
+// CheckItem checks the unchecked item identified by key, recording it in
+// the history. The file lines are updated but not saved. Returns false if
+// no unchecked item has that key.
+func (a *App) CheckItem(key string, at time.Time) bool {
+	for idx, sec := range a.Sections {
+		lines := strings.Split(sec.Content(), "\n")
+		for i, line := range lines {
+			if !isCheckbox(line) || isChecked(line) || checkboxItemKey(sec.Title, line) != key {
+				continue
+			}
+			lines[i] = setCheckbox(line, true)
+			a.recordCheckbox(sec.Title, line, true, at)
+			a.Sections[idx].SetContent(strings.Join(lines, "\n"))
+			a.UpdateFileSection(idx)
+			return true
+		}
+	}
+	return false
+}
+
+// githubAPI is the GitHub REST API base URL.
+const githubAPI = "https://api.github.com"
+
+// GitHubClient talks to the issues API of one repository.
+type GitHubClient struct {
+	BaseURL string
+	// Repo is "owner/name"
+	Repo  string
+	Token string
+	HTTP  *http.Client
+}
+
+// NewGitHubClient creates a client for repo authenticated with the
+// personal access token in GITHUB_TOKEN.
+func NewGitHubClient(repo string) (*GitHubClient, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITHUB_TOKEN is not set (a personal access token with issues access)")
+	}
+	if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" {
+		return nil, fmt.Errorf("invalid repository %q (want owner/name)", repo)
+	}
+	return &GitHubClient{
+		BaseURL: githubAPI,
+		Repo:    repo,
+		Token:   token,
+		HTTP:    &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// do sends a request with a JSON body (if any) and decodes the JSON
+// response into result.
+func (c *GitHubClient) do(method, path string, body, result any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	url := c.BaseURL + "/repos/" + c.Repo + path
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		logger.Error("github request", "method", method, "url", url, "err", err)
+		return err
+	}
+	defer resp.Body.Close()
+	logger.Debug("github request", "method", method, "url", url, "status", resp.StatusCode)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// GitHubIssue is the part of an issue sre-learn uses.
+type GitHubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	URL    string `json:"html_url"`
+}
+
+// CreateIssue opens an issue and returns it.
+func (c *GitHubClient) CreateIssue(title, body string, labels []string) (GitHubIssue, error) {
+	var issue GitHubIssue
+	err := c.do(http.MethodPost, "/issues", map[string]any{
+		"title":  title,
+		"body":   body,
+		"labels": labels,
+	}, &issue)
+	return issue, err
+}
+
+// Issue fetches an issue by number.
+func (c *GitHubClient) Issue(number int) (GitHubIssue, error) {
+	var issue GitHubIssue
+	err := c.do(http.MethodGet, fmt.Sprintf("/issues/%d", number), nil, &issue)
+	return issue, err
+}
+
+// maxLabelLength is GitHub's limit on label names.
+const maxLabelLength = 50
+
+// issueLabels turns a section hierarchy into issue labels.
+func issueLabels(path []string) []string {
+	labels := make([]string, 0, len(path))
+	for _, title := range path {
+		if r := []rune(title); len(r) > maxLabelLength {
+			title = string(r[:maxLabelLength-1]) + "…"
+		}
+		labels = append(labels, title)
+	}
+	return labels
+}
+
+// issueBody describes where an item comes from.
+func issueBody(item ChecklistItem, filePath string) string {
+	return fmt.Sprintf("**Section:** %s\n\n- [ ] %s\n\n_Tạo bởi sre-learn từ %s. Đóng issue để đánh dấu hoàn thành (sre-learn github sync)._",
+		strings.Join(item.Path, " › "), item.Text, filePath)
+}
+
+// PushSection creates an issue for every unchecked item of a section that
+// has none yet, remembering the issue numbers in the state.
+func (a *App) PushSection(c *GitHubClient, idx int, out io.Writer) error {
+	if a.GitHubIssues == nil {
+		a.GitHubIssues = make(map[string]int)
+	}
+	for _, item := range a.SectionItems(idx) {
+		if item.Checked || a.GitHubIssues[item.Key] != 0 {
+			continue
+		}
+		issue, err := c.CreateIssue(item.Text, issueBody(item, a.FilePath), issueLabels(item.Path))
+		if err != nil {
+			return err
+		}
+		a.GitHubIssues[item.Key] = issue.Number
+		fmt.Fprintf(out, "#%d %s\n", issue.Number, item.Text)
+	}
+	return nil
+}
+
+// SyncIssues checks the items whose issues were closed. Returns the
+// number of items checked.
+func (a *App) SyncIssues(c *GitHubClient, out io.Writer) (int, error) {
+	synced := 0
+	for key, number := range a.GitHubIssues {
+		issue, err := c.Issue(number)
+		if err != nil {
+			return synced, err
+		}
+		if issue.State != "closed" {
+			continue
+		}
+		if a.CheckItem(key, time.Now()) {
+			logger.Info("checked item of closed issue", "issue", number, "item", key)
+			synced++
+			fmt.Fprintf(out, "☑ #%d %s\n", number, issue.Title)
+		}
+		delete(a.GitHubIssues, key)
+	}
+	return synced, nil
+}
+
+// runGitHub implements "github push <section>" and "github sync".
+func runGitHub(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("github", flag.ContinueOnError)
+	repo := fs.String("repo", os.Getenv("GITHUB_REPOSITORY"), "repository as owner/name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	usage := fmt.Errorf("usage: sre-learn github [--repo owner/name] push <section-number|title> | sync")
+	if fs.NArg() == 0 {
+		return usage
+	}
+
+	client, err := NewGitHubClient(*repo)
+	if err != nil {
+		return err
+	}
+	pageSize, _ := app.LoadState()
+
+	switch fs.Arg(0) {
+	case "push":
+		query := strings.Join(fs.Args()[1:], " ")
+		idx := app.FindSection(query)
+		if query == "" || idx < 0 {
+			return fmt.Errorf("section %q not found", query)
+		}
+		err = app.PushSection(client, idx, out)
+	case "sync":
+		var synced int
+		if synced, err = app.SyncIssues(client, out); synced > 0 {
+			if saveErr := app.SaveFile(); saveErr != nil && err == nil {
+				err = saveErr
+			}
+		}
+		fmt.Fprintf(out, "%d item(s) checked\n", synced)
+	default:
+		return usage
+	}
+
+	// Keep the issue numbers created so far even if a request failed
+	if saveErr := app.SaveState(pageSize); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return err
+}
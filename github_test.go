@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================================
+// GitHub Sync Tests
+// ============================================================================
+
+func TestSectionItems(t *testing.T) {
+	app := createTestApp()
+	items := app.SectionItems(2) // Chapter 1: Basics
+
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+	want := []string{"Main Title", "Giai đoạn 1: Learning", "Chapter 1: Basics"}
+	if !reflect.DeepEqual(items[0].Path, want) {
+		t.Errorf("Expected path %v, got %v", want, items[0].Path)
+	}
+	if items[0].Text != "Task one" || items[0].Checked || !items[1].Checked {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+}
+
+func TestCheckItem(t *testing.T) {
+	app := createTestApp()
+	key := app.SectionItems(2)[2].Key // Task three
+
+	if !app.CheckItem(key, time.Now()) {
+		t.Fatal("Expected item to be checked")
+	}
+	if !strings.Contains(strings.Join(app.FileLines, "\n"), "- [x] Task three") {
+		t.Error("Expected file lines to be updated")
+	}
+	if len(app.History) != 1 || app.History[0].Item != "Task three" {
+		t.Errorf("Expected the completion in the history, got %+v", app.History)
+	}
+	if app.CheckItem(key, time.Now()) {
+		t.Error("Expected an already checked item not to be checked again")
+	}
+}
+
+func TestIssueLabelsTruncated(t *testing.T) {
+	labels := issueLabels([]string{"Phase 1", strings.Repeat("x", 60)})
+	if labels[0] != "Phase 1" || len([]rune(labels[1])) != maxLabelLength {
+		t.Errorf("Unexpected labels %q", labels)
+	}
+}
+
+// fakeGitHub serves the issues API, remembering created issues; issues
+// listed in closed are reported as closed.
+func fakeGitHub(t *testing.T, closed map[int]bool) (*GitHubClient, *[]map[string]any) {
+	var created []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/team/path/issues":
+			var body map[string]any
+			data, _ := io.ReadAll(r.Body)
+			json.Unmarshal(data, &body)
+			created = append(created, body)
+			fmt.Fprintf(w, `{"number": %d, "title": %q, "state": "open"}`, len(created), body["title"])
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/repos/team/path/issues/"):
+			var number int
+			fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/repos/team/path/issues/"), "%d", &number)
+			state := "open"
+			if closed[number] {
+				state = "closed"
+			}
+			fmt.Fprintf(w, `{"number": %d, "title": "issue %d", "state": %q}`, number, number, state)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := &GitHubClient{BaseURL: server.URL, Repo: "team/path", Token: "test-token", HTTP: server.Client()}
+	return client, &created
+}
+
+func TestPushSectionAndSync(t *testing.T) {
+	app := createTestApp()
+	client, created := fakeGitHub(t, map[int]bool{2: true})
+
+	var out bytes.Buffer
+	if err := app.PushSection(client, 2, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(*created) != 2 {
+		t.Fatalf("Expected an issue per unchecked item, got %d", len(*created))
+	}
+	labels := (*created)[0]["labels"].([]any)
+	if len(labels) != 3 || labels[2] != "Chapter 1: Basics" {
+		t.Errorf("Expected labels from the section hierarchy, got %v", labels)
+	}
+
+	// Pushing again does not duplicate issues
+	if err := app.PushSection(client, 2, &out); err != nil || len(*created) != 2 {
+		t.Errorf("Expected no new issues, got %d (%v)", len(*created), err)
+	}
+
+	synced, err := app.SyncIssues(client, &out)
+	if err != nil || synced != 1 {
+		t.Fatalf("Expected 1 item synced, got %d (%v)", synced, err)
+	}
+	if !strings.Contains(app.Sections[2].Content(), "- [x] Task three") || len(app.GitHubIssues) != 1 {
+		t.Errorf("Expected Task three checked and its issue forgotten, got %v", app.GitHubIssues)
+	}
+}
+
+func TestNewGitHubClientRequiresToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	if _, err := NewGitHubClient("team/path"); err == nil {
+		t.Error("Expected error without GITHUB_TOKEN")
+	}
+	t.Setenv("GITHUB_TOKEN", "x")
+	if _, err := NewGitHubClient("team"); err == nil {
+		t.Error("Expected error for a repository without owner")
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// glossaryTitles are the section titles that hold the glossary.
+var glossaryTitles = []string{"Glossary", "Thuật ngữ"}
+
+// GlossaryTerm is one "term: definition" line of the glossary.
+type GlossaryTerm struct {
+	Term       string
+	Definition string
+}
+
+// ParseGlossary reads the "term: definition" lines of a glossary
+// section. List markers and bold around the term are allowed:
+// "- **SLO**: Service Level Objective".
+func ParseGlossary(content string) []GlossaryTerm {
+	var terms []GlossaryTerm
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		for _, marker := range []string{"- ", "* ", "+ "} {
+			line = strings.TrimPrefix(line, marker)
+		}
+		// ": " rather than ":" so URLs are not split; "**SLO:** text"
+		// has the colon inside the bold
+		term, def, ok := strings.Cut(line, ":** ")
+		if !ok {
+			term, def, ok = strings.Cut(line, ": ")
+		}
+		if !ok || strings.HasPrefix(line, "[") || strings.HasPrefix(line, ">") {
+			continue
+		}
+		term = strings.Trim(term, "*` ")
+		def = strings.TrimSpace(def)
+		if term == "" || def == "" || len(term) > 60 {
+			continue
+		}
+		terms = append(terms, GlossaryTerm{Term: term, Definition: def})
+	}
+	return terms
+}
+
+// glossaryIndex returns the glossary section, or -1.
+func (a *App) glossaryIndex() int {
+	for i, sec := range a.Sections {
+		for _, title := range glossaryTitles {
+			if strings.EqualFold(sec.Title, title) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Glossary returns the terms defined in the glossary section.
+func (a *App) Glossary() []GlossaryTerm {
+	idx := a.glossaryIndex()
+	if idx < 0 {
+		return nil
+	}
+	return ParseGlossary(a.Sections[idx].Content())
+}
+
+// LookupTerm returns the terms matching query: the exact term
+// (case-insensitive) if it is defined, otherwise the terms containing it.
+func LookupTerm(terms []GlossaryTerm, query string) []GlossaryTerm {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var partial []GlossaryTerm
+	for _, t := range terms {
+		term := strings.ToLower(t.Term)
+		if term == query {
+			return []GlossaryTerm{t}
+		}
+		if strings.Contains(term, query) {
+			partial = append(partial, t)
+		}
+	}
+	return partial
+}
+
+// glossaryTerms matches the glossary terms in rendered lines when
+// glossary_underline is set; nil disables underlining.
+var glossaryTerms *regexp.Regexp
+
+// glossarySource is the glossary content glossaryTerms was built from.
+var glossarySource string
+
+// termsRegex builds a regexp matching any of the terms as whole words,
+// longest first so "SLO burn rate" wins over "SLO".
+func termsRegex(terms []GlossaryTerm) *regexp.Regexp {
+	if len(terms) == 0 {
+		return nil
+	}
+	words := make([]string, len(terms))
+	for i, t := range terms {
+		words[i] = regexp.QuoteMeta(t.Term)
+	}
+	sort.Slice(words, func(i, j int) bool { return len(words[i]) > len(words[j]) })
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(words, "|") + `)\b`)
+}
+
+// refreshGlossaryTerms rebuilds glossaryTerms when the glossary changed.
+func (a *App) refreshGlossaryTerms() {
+	idx := a.glossaryIndex()
+	if idx < 0 {
+		glossaryTerms, glossarySource = nil, ""
+		return
+	}
+	if content := a.Sections[idx].Content(); content != glossarySource || glossaryTerms == nil {
+		glossaryTerms, glossarySource = termsRegex(ParseGlossary(content)), content
+	}
+}
+
+// underlineTerms underlines the glossary terms of a markdown line,
+// outside code spans. Underline is switched off with SGR 24 rather than
+// a reset, so the surrounding styles are kept.
+func underlineTerms(line string) string {
+	if glossaryTerms == nil {
+		return line
+	}
+	parts := strings.Split(line, "`")
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = glossaryTerms.ReplaceAllString(parts[i], Underline+"$0\033[24m")
+	}
+	return strings.Join(parts, "`")
+}
+
+// glossaryLines formats terms for the lookup overlay.
+func glossaryLines(terms []GlossaryTerm, width int) []string {
+	var lines []string
+	for _, t := range terms {
+		lines = append(lines, Bold+Cyan+t.Term+Reset)
+		for _, l := range wrapVisible(renderInline(t.Definition), max(20, width-4)) {
+			lines = append(lines, "    "+l)
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// handleGlossary prompts for a term and shows its definition; an empty
+// query lists the whole glossary.
+func handleGlossary() {
+	terms := app.Glossary()
+	if len(terms) == 0 {
+		notify(SeverityInfo, "Chưa có glossary: thêm section \"## Glossary\" với các dòng \"thuật ngữ: định nghĩa\"")
+		return
+	}
+
+	terminal.SetRawMode(false)
+	fmt.Printf("\n%s📖 Tra thuật ngữ (Enter để xem tất cả):%s ", Bold, Reset)
+	query, _ := bufio.NewReader(keyboard).ReadString('\n')
+	terminal.SetRawMode(true)
+
+	query = strings.TrimSpace(query)
+	matches := terms
+	if query != "" {
+		matches = LookupTerm(terms, query)
+	}
+	if len(matches) == 0 {
+		notify(SeverityWarning, "Không có %q trong glossary", query)
+		return
+	}
+	title := fmt.Sprintf("📖 GLOSSARY (%d)", len(matches))
+	if query != "" {
+		title = fmt.Sprintf("📖 %s", query)
+	}
+	showOverlay(title, glossaryLines(matches, app.TermWidth))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const glossaryMarkdown = `# Path
+
+## Tuần 1
+
+Đặt SLO trước, rồi tính error budget.
+
+## Glossary
+
+Các thuật ngữ:
+
+- **SLO**: Service Level Objective, mục tiêu cho một SLI
+- **Error budget:** phần lỗi được phép, ` + "`1 - SLO`" + `
+SLI: Service Level Indicator
+- Tài liệu: https://sre.google/sre-book/
+- [ ] không phải: thuật ngữ
+`
+
+func glossaryApp() *App {
+	app := NewApp()
+	app.FileLines = strings.Split(glossaryMarkdown, "\n")
+	app.ParseSections()
+	return app
+}
+
+func TestParseGlossary(t *testing.T) {
+	terms := glossaryApp().Glossary()
+	want := []GlossaryTerm{
+		{"SLO", "Service Level Objective, mục tiêu cho một SLI"},
+		{"Error budget", "phần lỗi được phép, `1 - SLO`"},
+		{"SLI", "Service Level Indicator"},
+		{"Tài liệu", "https://sre.google/sre-book/"},
+	}
+	if len(terms) != len(want) {
+		t.Fatalf("Glossary = %+v", terms)
+	}
+	for i := range want {
+		if terms[i] != want[i] {
+			t.Errorf("term %d = %+v, want %+v", i, terms[i], want[i])
+		}
+	}
+}
+
+func TestLookupTerm(t *testing.T) {
+	terms := glossaryApp().Glossary()
+	if got := LookupTerm(terms, "slo"); len(got) != 1 || got[0].Term != "SLO" {
+		t.Errorf("exact lookup = %+v", got)
+	}
+	if got := LookupTerm(terms, "budget"); len(got) != 1 || got[0].Term != "Error budget" {
+		t.Errorf("partial lookup = %+v", got)
+	}
+	if got := LookupTerm(terms, "kubernetes"); len(got) != 0 {
+		t.Errorf("unknown lookup = %+v", got)
+	}
+}
+
+func TestUnderlineTerms(t *testing.T) {
+	app := glossaryApp()
+	app.refreshGlossaryTerms()
+	t.Cleanup(func() { glossaryTerms, glossarySource = nil, "" })
+
+	got := underlineTerms("Đặt SLO trước, rồi tính error budget. `SLO` trong code, SLOs thì không")
+	want := "Đặt " + Underline + "SLO\033[24m trước, rồi tính " + Underline + "error budget\033[24m. `SLO` trong code, SLOs thì không"
+	if got != want {
+		t.Errorf("underlineTerms = %q, want %q", got, want)
+	}
+
+	glossaryTerms = nil
+	if got := underlineTerms("SLO"); got != "SLO" {
+		t.Errorf("Expected no underline when disabled, got %q", got)
+	}
+}
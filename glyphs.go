@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// Glyphs are the symbols drawn by the viewer, so terminals or fonts that
+// cannot render Unicode symbols get ASCII equivalents.
+type Glyphs struct {
+	Unchecked, Checked string // checkbox states
+	InProgress, WontDo string // "- [~]" and "- [-]" checkboxes
+	BarFull, BarEmpty  string // progress bars and block letters
+	Rule               string // horizontal rules
+	Vertical           string // quote bars and the split view separator
+	Bullet             string // list bullets
+	Selector           string // selected entry, running commands
+	Done, Todo         string // section progress markers in the TOC
+	Skipped            string // skipped sections
+	Up, Down           string // scroll indicators
+	Crumb              string // breadcrumb separator
+	Link               string // internal links to other sections
+}
+
+// unicodeGlyphs are the default symbols.
+var unicodeGlyphs = Glyphs{
+	Unchecked: "☐", Checked: "☑", InProgress: "◐", WontDo: "⊟",
+	BarFull: "█", BarEmpty: "░",
+	Rule: "─", Vertical: "│", Bullet: "•", Selector: "▶",
+	Done: "✓", Todo: "○", Skipped: "⊘",
+	Up: "↑", Down: "↓", Crumb: "›", Link: "↪",
+}
+
+// asciiGlyphs render on any terminal.
+var asciiGlyphs = Glyphs{
+	Unchecked: "[ ]", Checked: "[x]", InProgress: "[~]", WontDo: "[-]",
+	BarFull: "#", BarEmpty: ".",
+	Rule: "-", Vertical: "|", Bullet: "*", Selector: ">",
+	Done: "+", Todo: "o", Skipped: "/",
+	Up: "^", Down: "v", Crumb: ">", Link: "->",
+}
+
+// glyphs is the active glyph set.
+var glyphs = unicodeGlyphs
+
+// asciiTerminals cannot draw the Unicode symbols, or only with fonts
+// that usually lack them.
+var asciiTerminals = map[string]bool{"dumb": true, "linux": true, "vt100": true, "vt102": true, "vt220": true}
+
+// detectGlyphs picks the glyph set from the config (glyphs=unicode|ascii),
+// or for "auto" from the environment: ASCII when TERM is a basic
+// terminal or the locale does not use UTF-8.
+func detectGlyphs(setting string, getenv func(string) string) Glyphs {
+	switch setting {
+	case "unicode":
+		return unicodeGlyphs
+	case "ascii":
+		return asciiGlyphs
+	}
+
+	if asciiTerminals[getenv("TERM")] {
+		return asciiGlyphs
+	}
+	// The first set of LC_ALL, LC_CTYPE and LANG decides the charset
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := strings.ToLower(getenv(name))
+		if locale == "" {
+			continue
+		}
+		if !strings.Contains(locale, "utf-8") && !strings.Contains(locale, "utf8") {
+			return asciiGlyphs
+		}
+		break
+	}
+	return unicodeGlyphs
+}
+
+// useDetectedGlyphs activates the glyph set for this session.
+func useDetectedGlyphs(setting string) {
+	glyphs = detectGlyphs(setting, os.Getenv)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectGlyphs(t *testing.T) {
+	tests := []struct {
+		setting string
+		env     map[string]string
+		want    Glyphs
+	}{
+		{"auto", map[string]string{"TERM": "xterm-256color", "LANG": "en_US.UTF-8"}, unicodeGlyphs},
+		{"auto", map[string]string{"TERM": "xterm", "LANG": "vi_VN.utf8"}, unicodeGlyphs},
+		{"auto", map[string]string{"TERM": "xterm"}, unicodeGlyphs},
+		{"auto", map[string]string{"TERM": "vt100", "LANG": "en_US.UTF-8"}, asciiGlyphs},
+		{"auto", map[string]string{"TERM": "xterm", "LC_ALL": "C", "LANG": "en_US.UTF-8"}, asciiGlyphs},
+		{"auto", map[string]string{"TERM": "xterm", "LC_CTYPE": "en_US.ISO-8859-1"}, asciiGlyphs},
+		{"unicode", map[string]string{"TERM": "dumb"}, unicodeGlyphs},
+		{"ascii", map[string]string{"LANG": "en_US.UTF-8"}, asciiGlyphs},
+	}
+	for _, tt := range tests {
+		got := detectGlyphs(tt.setting, func(name string) string { return tt.env[name] })
+		if got != tt.want {
+			t.Errorf("detectGlyphs(%q, %v) = %+v", tt.setting, tt.env, got)
+		}
+	}
+}
+
+func TestRenderLineASCII(t *testing.T) {
+	saved := glyphs
+	glyphs = asciiGlyphs
+	defer func() { glyphs = saved }()
+
+	for line, want := range map[string]string{
+		"- [ ] Task": "[ ]",
+		"- [x] Task": "[x]",
+		"- Bullet":   "* ",
+		"> quote":    "| quote",
+		"---":        "-----",
+	} {
+		got := RenderLine(line, 20)
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderLine(%q) = %q, want %q", line, got, want)
+		}
+		for _, r := range got {
+			if r > 127 {
+				t.Errorf("RenderLine(%q) = %q contains non-ASCII %q", line, got, r)
+				break
+			}
+		}
+	}
+}
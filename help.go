@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// helpEntry is one row of the help overlay.
+type helpEntry struct {
+	Category string
+	Keys     string
+	Desc     string
+}
+
+// modeHelp lists the keys of the sub-views, which are not remappable.
+func modeHelp() []helpEntry {
+	entries := []helpEntry{
+		{"Trong TOC", "j / k", "Di chuyển lên/xuống"},
+		{"Trong TOC", "gõ chữ", "Tìm section theo tên (fuzzy), Backspace để xóa"},
+		{"Trong TOC", "Enter", "Chọn section (kết quả đầu tiên khi đang tìm)"},
+		{"Trong TOC", "Tab", "Lọc: tất cả / chưa xong / bookmark / có ghi chú"},
+		{"Trong TOC", "Ctrl+r", "Sắp xếp: thứ tự / tiến độ / hoạt động gần nhất"},
+		{"Trong TOC", "q / Esc", "Đóng TOC (Esc xóa từ khóa trước)"},
+		{"Nhảy đến (Ctrl+p)", "gõ chữ", "Tìm fuzzy trong tên section, checkbox và ghi chú"},
+		{"Nhảy đến (Ctrl+p)", "↑ / ↓", "Di chuyển"},
+		{"Nhảy đến (Ctrl+p)", "Enter", "Nhảy đến dòng của kết quả"},
+		{"Nhảy đến (Ctrl+p)", "Esc", "Xóa từ khóa / đóng"},
+		{"Thẻ tóm tắt (nhấn c)", "→ / ←", "Thẻ kế tiếp / trước"},
+		{"Thẻ tóm tắt (nhấn c)", "s", "Xáo trộn thẻ"},
+		{"Thẻ tóm tắt (nhấn c)", "x", "Đánh dấu đã thuộc (ẩn khỏi bộ thẻ)"},
+		{"Thẻ tóm tắt (nhấn c)", "a", "Hiện/ẩn thẻ đã thuộc"},
+		{"Thẻ tóm tắt (nhấn c)", "Enter", "Mở section của thẻ"},
+		{"Chọn dòng (nhấn V)", "j / k", "Mở rộng vùng chọn"},
+		{"Chọn dòng (nhấn V)", "Space", "Bắt đầu chọn lại từ dòng con trỏ"},
+		{"Chọn dòng (nhấn V)", "Enter", "Highlight vùng chọn, nhập ghi chú (tùy chọn)"},
+		{"Highlight (nhấn h)", "Enter", "Mở section tại dòng được highlight"},
+		{"Highlight (nhấn h)", "d", "Xóa highlight"},
+		{"Mở file (nhấn O)", "j / k", "Di chuyển (🕘 là file mở gần đây)"},
+		{"Mở file (nhấn O)", "Enter", "Mở thư mục hoặc file"},
+		{"Mở file (nhấn O)", "gõ chữ", "Lọc theo tên"},
+		{"Mở file (nhấn O)", "Backspace", "Xóa lọc / lên thư mục cha"},
+		{"Mở file (nhấn O)", "Esc", "Xóa lọc / đóng"},
+		{"Presentation (nhấn P)", "Space / →", "Slide tiếp theo"},
+		{"Presentation (nhấn P)", "← / p", "Slide trước"},
+		{"Presentation (nhấn P)", "↑ / ↓", "Cuộn slide dài"},
+		{"Presentation (nhấn P)", "q / Esc", "Thoát presentation"},
+		{"Ghi chú (nhấn a)", "a", "Thêm mới (editor có sẵn, hoặc $EDITOR nếu đã đặt)"},
+		{"Ghi chú (nhấn a)", "v", "Xem chi tiết, mở file đính kèm"},
+		{"Ghi chú (nhấn a)", "e", "Sửa ghi chú"},
+		{"Ghi chú (nhấn a)", "d", "Xóa (vào thùng rác)"},
+		{"Ghi chú (nhấn a)", "r", "Khôi phục ghi chú đã xóa"},
+		{"Ghi chú (nhấn a)", "f", "Đính kèm file (lưu trong attachments/)"},
+		{"Ghi chú (nhấn a)", "g", "Ghi âm (arecord/sox/ffmpeg), Enter để dừng"},
+		{"Ghi chú (nhấn a)", "m", "Chuyển ghi chú sang section khác (dọn Inbox)"},
+	}
+	if app.ReviewMode {
+		entries = append(entries,
+			helpEntry{"Review mode (--review)", "a", "Thêm nhận xét cho mentee"},
+			helpEntry{"Review mode (--review)", "▌", "Mục thay đổi kể từ lần review trước"},
+		)
+	}
+	return entries
+}
+
+// helpEntries returns the help rows: the active keymap (so remapped keys
+// show as configured) followed by the sub-view keys.
+func helpEntries(bindings []KeyBinding) []helpEntry {
+	var entries []helpEntry
+	for _, kb := range bindings {
+		if len(kb.Keys) == 0 {
+			continue
+		}
+		entries = append(entries, helpEntry{kb.Category, keysLabel(kb.Keys), kb.Desc})
+	}
+	return append(entries, modeHelp()...)
+}
+
+// helpLines renders the entries matching filter (case-insensitive, on
+// keys, description or category), grouped by category.
+func helpLines(entries []helpEntry, filter string) []string {
+	filter = strings.ToLower(filter)
+
+	var lines []string
+	category := ""
+	for _, e := range entries {
+		haystack := strings.ToLower(e.Keys + " " + e.Desc + " " + e.Category)
+		if filter != "" && !strings.Contains(haystack, filter) {
+			continue
+		}
+		if e.Category != category {
+			if category != "" {
+				lines = append(lines, "")
+			}
+			lines = append(lines, Bold+Magenta+e.Category+Reset)
+			category = e.Category
+		}
+		lines = append(lines, "  "+Bold+Cyan+padVisible(e.Keys, 10)+Reset+" "+e.Desc)
+	}
+	return lines
+}
+
+// handleHelp shows the keyboard shortcuts in a scrollable overlay.
+// Typing filters the list, Backspace edits the filter, ↑/↓ and Space
+// scroll, Esc clears the filter (or closes) and q closes.
+func handleHelp() {
+	entries := helpEntries(keymap)
+	filter := ""
+	offset := 0
+
+	for {
+		ClearScreen()
+		rows := max(1, app.TermHeight-6)
+		lines := helpLines(entries, filter)
+		offset = max(0, min(offset, len(lines)-rows))
+
+		fmt.Printf("%s\n", barLine(BgCyan+Black+Bold, " ❓ KEYBOARD SHORTCUTS", app.TermWidth))
+		if filter != "" {
+			fmt.Printf("%s🔎 %s%s\n\n", Yellow, filter, Reset)
+		} else {
+			fmt.Printf("%sGõ để lọc...%s\n\n", Dim, Reset)
+		}
+
+		end := min(offset+rows, len(lines))
+		for _, line := range lines[offset:end] {
+			fmt.Println(truncateVisible(line, app.TermWidth))
+		}
+		if len(lines) == 0 {
+			fmt.Printf("%sKhông có phím nào khớp.%s\n", Dim, Reset)
+		}
+
+		fmt.Printf("\n%s[%d-%d/%d] ↑/↓ cuộn · Backspace xóa lọc · %d dòng/trang (+/-) · q/Esc đóng%s",
+			Dim, min(offset+1, len(lines)), end, len(lines), renderer.PageSize, Reset)
+
+		switch key := readKey(); {
+		case key == "Down":
+			offset++
+		case key == "Up":
+			offset--
+		case key == "Space":
+			offset += rows
+		case key == "Backspace":
+			if filter != "" {
+				_, size := utf8.DecodeLastRuneInString(filter)
+				filter = filter[:len(filter)-size]
+				offset = 0
+			}
+		case key == "Esc":
+			if filter == "" {
+				return
+			}
+			filter, offset = "", 0
+		case key == "Enter", key == "q" && filter == "", key == "Ctrl+c":
+			return
+		case utf8.RuneCountInString(key) == 1:
+			filter += key
+			offset = 0
+		case key == "Paste":
+			filter += pasteLine(keyboard.Pasted())
+			offset = 0
+		}
+	}
+}
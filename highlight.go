@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Highlight is a range of lines of a section the user marked, with an
+// optional comment. It is kept in the state file, so the document itself
+// is not changed.
+type Highlight struct {
+	Section string `json:"section"`
+	// Line and End are the first and last highlighted content lines
+	Line int `json:"line"`
+	End  int `json:"end"`
+	// Quote is the first highlighted line, used to find the range again
+	// when lines were added or removed above it
+	Quote   string    `json:"quote"`
+	Comment string    `json:"comment,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// AddHighlight highlights the content lines start to end (in any order)
+// of the section at idx.
+func (a *App) AddHighlight(idx, start, end int, comment string) (Highlight, bool) {
+	if idx < 0 || idx >= len(a.Sections) {
+		return Highlight{}, false
+	}
+	lines := strings.Split(a.Sections[idx].Content(), "\n")
+	if start > end {
+		start, end = end, start
+	}
+	if start < 0 || end >= len(lines) {
+		return Highlight{}, false
+	}
+	h := Highlight{
+		Section: a.Sections[idx].Title,
+		Line:    start,
+		End:     end,
+		Quote:   strings.TrimSpace(lines[start]),
+		Comment: strings.TrimSpace(comment),
+		Created: time.Now(),
+	}
+	a.Highlights = append(a.Highlights, h)
+	return h, true
+}
+
+// HighlightRange locates a highlight in the current document: its section
+// and first and last content lines. When its first line moved, the
+// nearest line with the same text is used; ok is false when the section
+// or the line is gone.
+func (a *App) HighlightRange(h Highlight) (idx, start, end int, ok bool) {
+	idx = a.sectionIndex(h.Section)
+	if idx < 0 {
+		return -1, 0, 0, false
+	}
+	lines := strings.Split(a.Sections[idx].Content(), "\n")
+	found := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) != h.Quote {
+			continue
+		}
+		if found < 0 || abs(i-h.Line) < abs(found-h.Line) {
+			found = i
+		}
+	}
+	if found < 0 {
+		return idx, 0, 0, false
+	}
+	return idx, found, min(found+h.End-h.Line, len(lines)-1), true
+}
+
+// HighlightedLines maps the highlighted content lines of the section at
+// idx to the comment shown after the last line of their highlight ("" on
+// the other lines).
+func (a *App) HighlightedLines(idx int) map[int]string {
+	var lines map[int]string
+	for _, h := range a.Highlights {
+		hIdx, start, end, ok := a.HighlightRange(h)
+		if !ok || hIdx != idx {
+			continue
+		}
+		if lines == nil {
+			lines = make(map[int]string)
+		}
+		for i := start; i <= end; i++ {
+			if _, seen := lines[i]; !seen {
+				lines[i] = ""
+			}
+		}
+		if h.Comment != "" {
+			lines[end] = h.Comment
+		}
+	}
+	return lines
+}
+
+// withBackground renders a styled line on a background color, restoring
+// it after every reset inside the line.
+func withBackground(line, bg string) string {
+	return bg + strings.ReplaceAll(line, Reset, Reset+bg) + Reset
+}
+
+// highlightLine renders a highlighted content line on a blue background,
+// followed by the comment of its highlight on the last line.
+func highlightLine(line, comment string) string {
+	line = withBackground(line, BgBlue)
+	if comment != "" {
+		line += Dim + " 💬 " + comment + Reset
+	}
+	return line
+}
+
+// sortedHighlights returns the indexes of the highlights in document
+// order; highlights that were not found come last.
+func (a *App) sortedHighlights() []int {
+	type position struct{ idx, line int }
+	pos := make([]position, len(a.Highlights))
+	order := make([]int, len(a.Highlights))
+	for i, h := range a.Highlights {
+		order[i] = i
+		pos[i] = position{len(a.Sections), 0}
+		if idx, start, _, ok := a.HighlightRange(h); ok {
+			pos[i] = position{idx, start}
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		pi, pj := pos[order[i]], pos[order[j]]
+		return pi.idx < pj.idx || (pi.idx == pj.idx && pi.line < pj.line)
+	})
+	return order
+}
+
+// handleSelectHighlight is the line selection mode: j/k move the cursor
+// from the top visible line, extending the selection from where it
+// started (Space restarts it at the cursor), and Enter highlights the
+// selected lines with an optional comment.
+func handleSelectHighlight() {
+	sec := app.GetCurrentSection()
+	if sec == nil {
+		return
+	}
+	rendered := RenderLines(app.contentLines(sec.Content()), app.TermWidth)
+	if len(rendered) == 0 {
+		return
+	}
+	cursor := min(renderer.ScrollOffset, len(rendered)-1)
+	anchor := cursor
+	offset := renderer.ScrollOffset
+	rows := max(1, app.TermHeight-3)
+
+	for {
+		ClearScreen()
+		fmt.Printf("%s\n", barLine(BgMagenta+White+Bold, " 🖍  CHỌN DÒNG  (j/k: mở rộng, Space: chọn lại từ đây, Enter: highlight, Esc: hủy)", app.TermWidth))
+		if cursor < offset {
+			offset = cursor
+		}
+		if cursor >= offset+rows {
+			offset = cursor - rows + 1
+		}
+		first, last := min(anchor, cursor), max(anchor, cursor)
+		for i := offset; i < min(offset+rows, len(rendered)); i++ {
+			line := truncateVisible(rendered[i], app.TermWidth-2)
+			marker := "  "
+			if i == cursor {
+				marker = Green + glyphs.Selector + " " + Reset
+			}
+			if i >= first && i <= last {
+				line = withBackground(line, BgMagenta)
+			}
+			fmt.Println(marker + line)
+		}
+		fmt.Printf("%s%d dòng đã chọn%s", Dim, last-first+1, Reset)
+
+		switch readKey() {
+		case "j", "Down":
+			cursor = min(cursor+1, len(rendered)-1)
+		case "k", "Up":
+			cursor = max(cursor-1, 0)
+		case "Space":
+			anchor = cursor
+		case "Enter":
+			fmt.Println()
+			comment, _ := readPrompt(Prompt{Label: Bold + "Ghi chú cho highlight (Enter để bỏ qua):" + Reset + " ", History: promptHistories["note"]})
+			rememberAnswer("note", comment)
+			if _, ok := app.AddHighlight(app.CurrentIdx, first, last, comment); ok {
+				app.SaveState(renderer.PageSize)
+				notify(SeveritySuccess, "Đã highlight %d dòng", last-first+1)
+			}
+			return
+		case "Esc", "q", "Ctrl+c":
+			return
+		}
+	}
+}
+
+// handleHighlights lists the highlights of the document for revision:
+// j/k move, Enter jumps to the highlight, d deletes it, q/Esc closes.
+func handleHighlights() {
+	if len(app.Highlights) == 0 {
+		notify(SeverityInfo, "Chưa có highlight nào (V để chọn dòng và highlight)")
+		return
+	}
+	selected, offset := 0, 0
+	for {
+		order := app.sortedHighlights()
+		if len(order) == 0 {
+			return
+		}
+		selected = min(selected, len(order)-1)
+		rows := max(1, (app.TermHeight-3)/2)
+		if selected < offset {
+			offset = selected
+		}
+		if selected >= offset+rows {
+			offset = selected - rows + 1
+		}
+
+		ClearScreen()
+		fmt.Printf("%s\n\n", barLine(BgMagenta+White+Bold, fmt.Sprintf(" 🖍  HIGHLIGHT (%d)  (j/k: di chuyển, Enter: mở, d: xóa, q: đóng)", len(order)), app.TermWidth))
+		for i := offset; i < min(offset+rows, len(order)); i++ {
+			h := app.Highlights[order[i]]
+			selector := "  "
+			if i == selected {
+				selector = Green + glyphs.Selector + " " + Reset
+			}
+			where := h.Section
+			if _, start, end, ok := app.HighlightRange(h); ok {
+				where += fmt.Sprintf(" · dòng %d-%d", start+1, end+1)
+			} else {
+				where += " · không còn tìm thấy"
+			}
+			fmt.Println(truncateVisible(selector+BgBlue+renderInline(h.Quote)+Reset, app.TermWidth))
+			detail := Dim + where + Reset
+			if h.Comment != "" {
+				detail = "💬 " + h.Comment + "  " + detail
+			}
+			fmt.Println(truncateVisible("    "+detail, app.TermWidth))
+		}
+
+		switch readKey() {
+		case "j", "Down":
+			selected = min(selected+1, len(order)-1)
+		case "k", "Up":
+			selected = max(selected-1, 0)
+		case "Enter":
+			h := app.Highlights[order[selected]]
+			if idx, start, _, ok := app.HighlightRange(h); ok {
+				app.JumpTo(JumpTarget{Section: idx, Line: start})
+			} else if idx >= 0 {
+				app.JumpTo(JumpTarget{Section: idx, Line: -1})
+			}
+			return
+		case "d":
+			i := order[selected]
+			app.Highlights = append(app.Highlights[:i], app.Highlights[i+1:]...)
+			app.SaveState(renderer.PageSize)
+			if len(app.Highlights) == 0 {
+				return
+			}
+		case "q", "Q", "Esc", "h":
+			return
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAddHighlight(t *testing.T) {
+	a := createTestApp()
+	h, ok := a.AddHighlight(2, 3, 1, " check later ")
+	if !ok {
+		t.Fatal("AddHighlight failed")
+	}
+	if h.Section != "Chapter 1: Basics" || h.Line != 1 || h.End != 3 || h.Quote != "- [ ] Task one" || h.Comment != "check later" {
+		t.Errorf("Unexpected highlight %+v", h)
+	}
+	if _, ok := a.AddHighlight(2, 0, 99, ""); ok {
+		t.Error("Expected a range past the section to be rejected")
+	}
+	if len(a.Highlights) != 1 {
+		t.Errorf("Expected 1 highlight, got %d", len(a.Highlights))
+	}
+}
+
+func TestHighlightRangeFollowsMovedLines(t *testing.T) {
+	a := createTestApp()
+	h, _ := a.AddHighlight(2, 1, 2, "")
+
+	a.Sections[2].SetContent("\nNew intro.\n" + a.Sections[2].Content())
+	idx, start, end, ok := a.HighlightRange(h)
+	if !ok || idx != 2 || start != 3 || end != 4 {
+		t.Errorf("Expected lines 3-4 of section 2, got %d %d-%d %v", idx, start, end, ok)
+	}
+
+	a.Sections[2].SetContent(strings.Replace(a.Sections[2].Content(), "Task one", "Task 1", 1))
+	if _, _, _, ok := a.HighlightRange(h); ok {
+		t.Error("Expected a highlight whose line is gone not to be found")
+	}
+	h.Section = "Removed"
+	if idx, _, _, ok := a.HighlightRange(h); ok || idx != -1 {
+		t.Error("Expected a highlight of a removed section not to be found")
+	}
+}
+
+func TestHighlightedLines(t *testing.T) {
+	a := createTestApp()
+	a.AddHighlight(2, 1, 2, "note")
+	a.AddHighlight(2, 2, 3, "")
+	a.AddHighlight(5, 1, 1, "other section")
+
+	lines := a.HighlightedLines(2)
+	if len(lines) != 3 || lines[1] != "" || lines[2] != "note" || lines[3] != "" {
+		t.Errorf("Unexpected highlighted lines %v", lines)
+	}
+	if lines := a.HighlightedLines(3); lines != nil {
+		t.Errorf("Expected no highlighted lines, got %v", lines)
+	}
+}
+
+func TestSortedHighlights(t *testing.T) {
+	a := createTestApp()
+	a.AddHighlight(5, 1, 1, "")
+	a.AddHighlight(2, 3, 3, "")
+	a.AddHighlight(2, 1, 1, "")
+	a.Highlights = append(a.Highlights, Highlight{Section: "Removed"})
+
+	order := a.sortedHighlights()
+	if want := []int{2, 1, 0, 3}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected %v, got %v", want, order)
+	}
+}
+
+func TestWithBackground(t *testing.T) {
+	got := withBackground(Bold+"a"+Reset+" b", BgBlue)
+	if want := BgBlue + Bold + "a" + Reset + BgBlue + " b" + Reset; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
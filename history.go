@@ -0,0 +1,195 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// CheckboxEvent records one checkbox transition. Events are only ever
+// appended, so the log answers "what did I finish on Tuesday?" even after
+// an item was unchecked again.
+type CheckboxEvent struct {
+	Time    time.Time `json:"time"`
+	Section string    `json:"section"`
+	Item    string    `json:"item"`
+	Checked bool      `json:"checked"`
+}
+
+// checkboxText returns the text of a checkbox line without its marker.
+func checkboxText(line string) string {
+	if m := checkboxRegex.FindStringIndex(line); m != nil {
+		line = line[m[1]:]
+	}
+	return strings.TrimSpace(line)
+}
+
+// recordCheckbox appends a transition of item in section to the history.
+func (a *App) recordCheckbox(section, line string, checked bool, at time.Time) {
+	a.History = append(a.History, CheckboxEvent{
+		Time:    at,
+		Section: section,
+		Item:    checkboxText(line),
+		Checked: checked,
+	})
+	a.scheduleRecurrence(section, line, checked, at)
+}
+
+// LastCompleted returns when a checkbox of the section was last checked.
+func (a *App) LastCompleted(idx int) (time.Time, bool) {
+	if idx < 0 || idx >= len(a.Sections) {
+		return time.Time{}, false
+	}
+	title := a.Sections[idx].Title
+	for i := len(a.History) - 1; i >= 0; i-- {
+		if ev := a.History[i]; ev.Checked && ev.Section == title {
+			return ev.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// checkedBefore returns the state of item in section as of since, taken
+// from the history: the last transition before since, or the opposite of
+// the first one after it. ok is false if the item has not changed since.
+func (a *App) checkedBefore(section, item string, since time.Time) (checked, ok bool) {
+	for i := len(a.History) - 1; i >= 0; i-- {
+		ev := a.History[i]
+		if ev.Section != section || ev.Item != item {
+			continue
+		}
+		if ev.Time.Before(since) {
+			return ev.Checked, ok
+		}
+		checked, ok = !ev.Checked, true
+	}
+	return checked, ok
+}
+
+// CompletedBetween returns the items checked in [from, to), oldest first.
+func (a *App) CompletedBetween(from, to time.Time) []CheckboxEvent {
+	var events []CheckboxEvent
+	for _, ev := range a.History {
+		if ev.Checked && !ev.Time.Before(from) && ev.Time.Before(to) {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// startOfDay returns midnight of t's day in t's location.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// weekdays maps English and Vietnamese day names to weekdays.
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday,
+	"wednesday": time.Wednesday, "thursday": time.Thursday, "friday": time.Friday,
+	"saturday": time.Saturday,
+	"cn":       time.Sunday, "t2": time.Monday, "t3": time.Tuesday, "t4": time.Wednesday,
+	"t5": time.Thursday, "t6": time.Friday, "t7": time.Saturday,
+}
+
+// parseDay resolves a day relative to now: YYYY-MM-DD, "today",
+// "yesterday" or a weekday name meaning its most recent past occurrence.
+func parseDay(s string, now time.Time) (time.Time, error) {
+	today := startOfDay(now)
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "today", "hôm nay":
+		return today, nil
+	case "yesterday", "hôm qua":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if wd, ok := weekdays[s]; ok {
+		back := (int(today.Weekday()) - int(wd) + 7) % 7
+		if back == 0 {
+			back = 7
+		}
+		return today.AddDate(0, 0, -back), nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid day %q (YYYY-MM-DD, today, yesterday or a weekday)", s)
+	}
+	return day, nil
+}
+
+// writeHistory writes events grouped by day, newest day first.
+func writeHistory(out io.Writer, events []CheckboxEvent, useColor bool) {
+	style := func(code, text string) string {
+		if useColor {
+			return code + text + Reset
+		}
+		return text
+	}
+
+	day := ""
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		if d := ev.Time.Format("2006-01-02 (Mon)"); d != day {
+			if day != "" {
+				fmt.Fprintln(out)
+			}
+			day = d
+			fmt.Fprintln(out, style(Bold+Cyan, day))
+		}
+		mark := style(Green, glyphs.Checked)
+		if !ev.Checked {
+			mark = style(Red, glyphs.Unchecked)
+		}
+		fmt.Fprintf(out, "  %s %s %s %s\n", style(Dim, ev.Time.Format("15:04")), mark, ev.Item, style(Dim, "· "+ev.Section))
+	}
+}
+
+// runHistory prints the checkbox history, by default the items completed
+// in the last 7 days.
+func runHistory(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	dayFlag := fs.String("day", "", "only items completed on this day (YYYY-MM-DD, today, yesterday, tuesday, ...)")
+	days := fs.Int("days", 7, "number of days to show, ending today")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *days < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+	app.LoadState()
+
+	now := time.Now()
+	from := startOfDay(now).AddDate(0, 0, 1-*days)
+	to := now.Add(time.Second)
+	if *dayFlag != "" {
+		day, err := parseDay(*dayFlag, now)
+		if err != nil {
+			return err
+		}
+		from, to = day, day.AddDate(0, 0, 1)
+	}
+
+	events := app.CompletedBetween(from, to)
+	if len(events) == 0 {
+		fmt.Fprintf(out, "No items completed between %s and %s.\n", from.Format("2006-01-02"), to.Add(-time.Second).Format("2006-01-02"))
+		return nil
+	}
+	writeHistory(out, events, isTerminal(os.Stdout))
+	return nil
+}
+
+// handleHistory shows every checkbox transition, newest first.
+func handleHistory() {
+	var b strings.Builder
+	writeHistory(&b, app.History, true)
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(app.History) == 0 {
+		lines = []string{Dim + "Chưa có lịch sử. Đánh dấu checkbox bằng x để bắt đầu." + Reset}
+	}
+	if cycles := app.cycleLines(); len(cycles) > 0 {
+		lines = append(append(cycles, ""), lines...)
+	}
+	showOverlay(fmt.Sprintf("🕘 LỊCH SỬ HOÀN THÀNH (%d)", len(app.CompletedBetween(time.Time{}, time.Now().Add(time.Second)))), lines)
+}
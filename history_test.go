@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================================
+// Checkbox History Tests
+// ============================================================================
+
+func TestToggleCheckboxRecordsHistory(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 2
+	lines := app.GetCheckboxLines()
+	if len(lines) == 0 {
+		t.Fatal("Expected checkboxes in test section")
+	}
+
+	app.ToggleCheckbox(lines[0])
+	app.ToggleCheckbox(lines[0])
+
+	if len(app.History) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(app.History))
+	}
+	first, second := app.History[0], app.History[1]
+	if first.Checked == second.Checked || first.Section != app.Sections[2].Title || first.Item == "" {
+		t.Errorf("Unexpected events: %+v %+v", first, second)
+	}
+	if strings.Contains(first.Item, "[") {
+		t.Errorf("Expected item text without checkbox marker, got %q", first.Item)
+	}
+}
+
+func TestLastCompletedAndCompletedBetween(t *testing.T) {
+	app := createTestApp()
+	title := app.Sections[2].Title
+	monday := time.Date(2026, 10, 12, 9, 0, 0, 0, time.Local)
+	app.recordCheckbox(title, "- [ ] đọc SLO", true, monday)
+	app.recordCheckbox(title, "- [ ] viết postmortem", true, monday.AddDate(0, 0, 1))
+	app.recordCheckbox(title, "- [x] viết postmortem", false, monday.AddDate(0, 0, 2))
+
+	last, ok := app.LastCompleted(2)
+	if !ok || !last.Equal(monday.AddDate(0, 0, 1)) {
+		t.Errorf("Expected last completion on Tuesday, got %v %v", last, ok)
+	}
+	if _, ok := app.LastCompleted(3); ok {
+		t.Error("Expected no completion for a section without history")
+	}
+
+	tuesday := startOfDay(monday.AddDate(0, 0, 1))
+	got := app.CompletedBetween(tuesday, tuesday.AddDate(0, 0, 1))
+	if len(got) != 1 || got[0].Item != "viết postmortem" {
+		t.Errorf("Expected the postmortem on Tuesday, got %+v", got)
+	}
+}
+
+func TestParseDay(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 30, 0, 0, time.Local) // Friday
+	tests := map[string]string{
+		"today":      "2026-10-16",
+		"yesterday":  "2026-10-15",
+		"tuesday":    "2026-10-13",
+		"Friday":     "2026-10-09",
+		"t3":         "2026-10-13",
+		"2026-09-01": "2026-09-01",
+	}
+	for input, want := range tests {
+		got, err := parseDay(input, now)
+		if err != nil || got.Format("2006-01-02") != want {
+			t.Errorf("parseDay(%q) = %v, %v; want %s", input, got, err, want)
+		}
+	}
+	if _, err := parseDay("someday", now); err == nil {
+		t.Error("Expected error for an unknown day")
+	}
+}
+
+func TestRunHistoryPrintsDay(t *testing.T) {
+	app := withTestApp(t)
+	app.StateFile = filepath.Join(t.TempDir(), "state")
+	yesterday := time.Now().AddDate(0, 0, -1)
+	app.recordCheckbox("Tuần 1", "- [ ] cài Prometheus", true, yesterday)
+	app.recordCheckbox("Tuần 1", "- [ ] cài Grafana", true, time.Now())
+	if err := app.SaveState(20); err != nil {
+		t.Fatal(err)
+	}
+	app.History = nil
+
+	var out bytes.Buffer
+	if err := runHistory([]string{"--day", "yesterday"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "cài Prometheus · Tuần 1") || strings.Contains(out.String(), "Grafana") {
+		t.Errorf("Expected only yesterday's item, got:\n%s", out.String())
+	}
+}
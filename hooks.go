@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hook events, configured as "hook.<event>=<command>".
+const (
+	hookSave            = "on-save"
+	hookSectionComplete = "on-section-complete"
+	hookNoteAdded       = "on-note-added"
+	hookQuit            = "on-quit"
+)
+
+// hookEvents lists the events hooks can be configured for.
+var hookEvents = []string{hookSave, hookSectionComplete, hookNoteAdded, hookQuit}
+
+// hookTimeout bounds how long a hook may run; long jobs (git push) should
+// background themselves with "&".
+const hookTimeout = 30 * time.Second
+
+// HookEvent is the context of a fired hook, written as JSON to the hook's
+// stdin and exported as SRE_LEARN_* variables.
+type HookEvent struct {
+	Event   string    `json:"event"`
+	File    string    `json:"file"`
+	Section string    `json:"section,omitempty"`
+	Note    string    `json:"note,omitempty"`
+	Done    int       `json:"done"`
+	Total   int       `json:"total"`
+	Time    time.Time `json:"time"`
+}
+
+// env returns the event as environment variables.
+func (ev HookEvent) env() []string {
+	return []string{
+		"SRE_LEARN_EVENT=" + ev.Event,
+		"SRE_LEARN_FILE=" + ev.File,
+		"SRE_LEARN_SECTION=" + ev.Section,
+		"SRE_LEARN_NOTE=" + ev.Note,
+		"SRE_LEARN_DONE=" + strconv.Itoa(ev.Done),
+		"SRE_LEARN_TOTAL=" + strconv.Itoa(ev.Total),
+	}
+}
+
+// runHook runs a hook command with the shell. Its output is discarded so
+// it does not garble the screen; stderr is reported when it fails.
+func runHook(command string, ev HookEvent) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), ev.env()...)
+	cmd.Stdin = bytes.NewReader(append(payload, '\n'))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// Don't wait for the output of commands left running in the background
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("hook %s: %s", ev.Event, msg)
+		}
+		return fmt.Errorf("hook %s: %w", ev.Event, err)
+	}
+	return nil
+}
+
+// fireHook runs the configured hook of an event, if any, with the
+// document's overall progress. A failing hook is reported on the status
+// line and does not stop the action that fired it.
+func (a *App) fireHook(event, section, note string) {
+	command := config.Hooks[event]
+	if command == "" {
+		return
+	}
+	total := a.TotalCheckboxes()
+	ev := HookEvent{
+		Event:   event,
+		File:    a.FilePath,
+		Section: section,
+		Note:    note,
+		Done:    total.Done,
+		Total:   total.Open + total.InProgress + total.Done,
+		Time:    time.Now(),
+	}
+	logger.Debug("run hook", "event", event, "command", command)
+	if err := runHook(command, ev); err != nil {
+		logger.Warn("hook failed", "event", event, "err", err)
+		a.Status.Push(SeverityWarning, err.Error())
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigHooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	os.WriteFile(path, []byte("hook.on-save=git commit -qam progress\n"), 0o644)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Hooks[hookSave] != "git commit -qam progress" {
+		t.Errorf("Unexpected hooks %v", cfg.Hooks)
+	}
+
+	os.WriteFile(path, []byte("hook.on-boot=echo hi\n"), 0o644)
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected error for an unknown event")
+	}
+}
+
+// withHooks configures hooks that append their stdin (and one variable)
+// to a log file, which is returned.
+func withHooks(t *testing.T, events ...string) string {
+	t.Helper()
+	log := filepath.Join(t.TempDir(), "hooks.log")
+	saved := config
+	config = DefaultConfig()
+	config.Hooks = map[string]string{}
+	for _, event := range events {
+		config.Hooks[event] = `cat >> "` + log + `"; echo "section=$SRE_LEARN_SECTION" >> "` + log + `"`
+	}
+	t.Cleanup(func() { config = saved })
+	return log
+}
+
+func TestHookEvents(t *testing.T) {
+	log := withHooks(t, hookNoteAdded, hookSectionComplete)
+	app := NewApp()
+	app.FileLines = strings.Split("## Basics\n- [ ] One\n- [x] Two\n- [ ] Three", "\n")
+	app.ParseSections()
+
+	app.AddNote("read the man page")
+	app.ToggleCheckbox(0)
+	app.ToggleCheckbox(2) // completes the section
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected two hooks to run, got %q", lines)
+	}
+	var note, complete HookEvent
+	json.Unmarshal([]byte(lines[0]), &note)
+	json.Unmarshal([]byte(lines[2]), &complete)
+	if note.Event != hookNoteAdded || note.Note != "read the man page" || lines[1] != "section=Basics" {
+		t.Errorf("note hook = %+v, %q", note, lines[1])
+	}
+	if complete.Event != hookSectionComplete || complete.Done != 3 || complete.Total != 3 {
+		t.Errorf("complete hook = %+v", complete)
+	}
+}
+
+func TestHookFailureIsReported(t *testing.T) {
+	saved := config
+	config = DefaultConfig()
+	config.Hooks = map[string]string{hookQuit: "echo nope >&2; exit 3"}
+	t.Cleanup(func() { config = saved })
+
+	app := NewApp()
+	app.fireHook(hookQuit, "", "")
+	if msg, ok := app.Status.Current(time.Now()); !ok || !strings.Contains(msg.Text, "hook on-quit: nope") {
+		t.Errorf("status = %+v", msg)
+	}
+}
@@ -0,0 +1,419 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Import flavors.
+const (
+	flavorObsidian = "obsidian"
+	flavorNotion   = "notion"
+)
+
+// notionIDRegex matches the page ID Notion appends to exported file and
+// folder names: "Kubernetes 1a2b...(32 hex)".
+var notionIDRegex = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+
+// wikilinkRegex matches Obsidian links and embeds:
+// [[Note]], [[Note#Heading|alias]], ![[diagram.png]].
+var wikilinkRegex = regexp.MustCompile(`(!?)\[\[([^\]|#]*)(?:#([^\]|]*))?(?:\|([^\]]*))?\]\]`)
+
+// calloutRegex matches the first line of an Obsidian callout:
+// "> [!tip]- Title".
+var calloutRegex = regexp.MustCompile(`^(\s*>\s*)\[!(\w+)\][+-]?\s*(.*)$`)
+
+// markdownLinkRegex matches [text](target) links and ![alt](src) images.
+var markdownLinkRegex = regexp.MustCompile(`(!?)\[([^\]]*)\]\(([^)\s]+)\)`)
+
+// summaryRegex matches the title of a Notion toggle block.
+var summaryRegex = regexp.MustCompile(`<summary>(.*?)</summary>`)
+
+// importPage is a markdown file of the export, or a folder without one.
+type importPage struct {
+	Title string
+	// Level is the header level of the page title
+	Level int
+	// Path is the markdown file, empty for a folder without a page
+	Path     string
+	Children []*importPage
+}
+
+// pageTitle returns the title of an exported file or folder name.
+func pageTitle(name string) string {
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.TrimSpace(notionIDRegex.ReplaceAllString(name, ""))
+}
+
+// loadImportTree builds the page hierarchy of the folder dir. A folder
+// holding the subpages of a page is nested under it: Notion writes
+// "Page.md" next to a "Page/" folder, Obsidian folder notes are
+// "Folder/Folder.md".
+func loadImportTree(dir string, level int) ([]*importPage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var pages []*importPage
+	byTitle := make(map[string]*importPage)
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".md") {
+			continue
+		}
+		p := &importPage{Title: pageTitle(e.Name()), Level: level, Path: filepath.Join(dir, e.Name())}
+		pages = append(pages, p)
+		byTitle[p.Title] = p
+	}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		title := pageTitle(e.Name())
+		parent, ok := byTitle[title]
+		if !ok {
+			parent = &importPage{Title: title, Level: level}
+		}
+		children, err := loadImportTree(filepath.Join(dir, e.Name()), level+1)
+		if err != nil {
+			return nil, err
+		}
+		// Obsidian folder note: Folder/Folder.md is the folder's page
+		for i, c := range children {
+			if parent.Path == "" && c.Title == title && c.Path != "" {
+				parent.Path = c.Path
+				children = append(children[:i], children[i+1:]...)
+				break
+			}
+		}
+		if len(children) == 0 && parent.Path == "" {
+			continue // only attachments
+		}
+		parent.Children = append(parent.Children, children...)
+		if !ok {
+			pages = append(pages, parent)
+		}
+	}
+	return pages, nil
+}
+
+// importer converts the pages of an export into one document.
+type importer struct {
+	// outDir is the directory of the output file; links to attachments
+	// are made relative to it
+	outDir string
+	// titles maps lowercase page names to their titles, to resolve links
+	titles map[string]string
+	// files maps the base names of all files to their paths, to resolve
+	// Obsidian embeds
+	files map[string]string
+}
+
+// index records the pages and files of the export for link resolution.
+func (im *importer) index(root string, pages []*importPage) error {
+	var walk func([]*importPage)
+	walk = func(pages []*importPage) {
+		for _, p := range pages {
+			im.titles[strings.ToLower(p.Title)] = p.Title
+			walk(p.Children)
+		}
+	}
+	walk(pages)
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if _, seen := im.files[d.Name()]; !seen {
+				im.files[d.Name()] = path
+			}
+		}
+		return err
+	})
+}
+
+// sectionAnchor returns the link target of a page or heading title.
+func sectionAnchor(title string) string {
+	return "#" + Slugify(title)
+}
+
+// relativeTo makes path relative to the output directory, with spaces
+// escaped so it stays a valid link target.
+func (im *importer) relativeTo(path string) string {
+	if rel, err := filepath.Rel(im.outDir, path); err == nil {
+		path = rel
+	}
+	return strings.ReplaceAll(filepath.ToSlash(path), " ", "%20")
+}
+
+// convertWikilinks turns Obsidian links into section links and embeds
+// into images or links to the attachment.
+func (im *importer) convertWikilinks(line string) string {
+	return wikilinkRegex.ReplaceAllStringFunc(line, func(m string) string {
+		parts := wikilinkRegex.FindStringSubmatch(m)
+		embed, target, heading, alias := parts[1] == "!", strings.TrimSpace(parts[2]), strings.TrimSpace(parts[3]), strings.TrimSpace(parts[4])
+		if embed {
+			if path, ok := im.files[filepath.Base(target)]; ok {
+				return fmt.Sprintf("![%s](%s)", filepath.Base(target), im.relativeTo(path))
+			}
+		}
+		title, ok := im.titles[strings.ToLower(pageTitle(target))]
+		if !ok {
+			title = target
+		}
+		text := alias
+		if text == "" {
+			text = strings.TrimSpace(strings.Trim(target+" › "+heading, " ›"))
+		}
+		if heading != "" {
+			return fmt.Sprintf("[%s](%s)", text, sectionAnchor(heading))
+		}
+		if !ok {
+			return text // a note that was not exported
+		}
+		return fmt.Sprintf("[%s](%s)", text, sectionAnchor(title))
+	})
+}
+
+// convertLinks points links to other exported pages at their sections
+// and links to attachments at their files, relative to the output.
+func (im *importer) convertLinks(line, pageDir string) string {
+	return markdownLinkRegex.ReplaceAllStringFunc(line, func(m string) string {
+		parts := markdownLinkRegex.FindStringSubmatch(m)
+		target := parts[3]
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "mailto:") {
+			return m
+		}
+		if decoded, err := url.PathUnescape(target); err == nil {
+			target = decoded
+		}
+		target, _, _ = strings.Cut(target, "#")
+		if strings.EqualFold(filepath.Ext(target), ".md") {
+			if title, ok := im.titles[strings.ToLower(pageTitle(filepath.Base(target)))]; ok && parts[1] == "" {
+				return fmt.Sprintf("[%s](%s)", parts[2], sectionAnchor(title))
+			}
+		}
+		path := filepath.Join(pageDir, target)
+		if !fileExists(path) {
+			return m
+		}
+		return fmt.Sprintf("%s[%s](%s)", parts[1], parts[2], im.relativeTo(path))
+	})
+}
+
+// convertBlocks rewrites the flavor's block syntax: callouts become
+// quotes with a bold label, Notion toggle blocks a bold title followed
+// by their content, and Notion asides quotes.
+func convertBlocks(lines []string) []string {
+	var out []string
+	inAside := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "<details>" || trimmed == "</details>" || trimmed == "<details open>":
+			continue
+		case summaryRegex.MatchString(trimmed):
+			out = append(out, "**"+strings.TrimSpace(summaryRegex.FindStringSubmatch(trimmed)[1])+"**")
+			continue
+		case trimmed == "<aside>":
+			inAside = true
+			continue
+		case trimmed == "</aside>":
+			inAside = false
+			continue
+		}
+		if m := calloutRegex.FindStringSubmatch(line); m != nil {
+			label := strings.ToUpper(m[2][:1]) + strings.ToLower(m[2][1:])
+			line = strings.TrimRight(fmt.Sprintf("%s**%s:** %s", m[1], label, m[3]), " ")
+		}
+		if inAside {
+			line = strings.TrimRight("> "+line, " ")
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// stripFrontMatter removes a leading YAML front matter block.
+func stripFrontMatter(lines []string) []string {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return lines
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return lines[i+1:]
+		}
+	}
+	return lines
+}
+
+// convertPage returns the markdown of a page: its title header at the
+// page's level, then its content with its headers moved below it.
+func (im *importer) convertPage(p *importPage) ([]string, error) {
+	out := []string{strings.Repeat("#", min(p.Level, 6)) + " " + p.Title, ""}
+	if p.Path == "" {
+		return out, nil
+	}
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	lines := stripFrontMatter(strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"))
+
+	// Notion starts each page with its title as "# Title"
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "# "+p.Title {
+		lines = lines[1:]
+		for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+			lines = lines[1:]
+		}
+	}
+
+	lines = convertBlocks(lines)
+	scanned := ScanLines(lines)
+	// The page's top headers go one level below its title, whether it
+	// starts at # or ##
+	top := 0
+	for _, ml := range scanned {
+		if ml.Kind == LineHeader && (top == 0 || ml.Level < top) {
+			top = ml.Level
+		}
+	}
+	for i, line := range lines {
+		switch scanned[i].Kind {
+		case LineHeader:
+			line = strings.Repeat("#", min(scanned[i].Level-top+p.Level+1, 6)) + " " + im.convertWikilinks(scanned[i].Text)
+			if id := scanned[i].ID; id != "" {
+				line += " {#" + id + "}"
+			}
+		case LineSetextUnderline:
+			continue
+		case LineText:
+			line = im.convertLinks(im.convertWikilinks(line), filepath.Dir(p.Path))
+		}
+		out = append(out, line)
+	}
+	for len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+		out = out[:len(out)-1]
+	}
+	return append(out, ""), nil
+}
+
+// ImportExport converts an Obsidian vault or a Notion markdown export in
+// dir into one learning path document with the given title. Folders and
+// pages become nested sections; links between pages become section
+// links. outDir is where the document will be written.
+func ImportExport(dir, flavor, title, outDir string) (string, error) {
+	if flavor != flavorObsidian && flavor != flavorNotion {
+		return "", fmt.Errorf("unknown import format %q (obsidian, notion)", flavor)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	pages, err := loadImportTree(dir, 2)
+	if err != nil {
+		return "", err
+	}
+	if len(pages) == 0 {
+		return "", fmt.Errorf("%s has no markdown files", dir)
+	}
+	// A single top-level page (Notion exports one root page) is the title
+	if len(pages) == 1 && flavor == flavorNotion {
+		var shift func([]*importPage)
+		shift = func(ps []*importPage) {
+			for _, p := range ps {
+				p.Level--
+				shift(p.Children)
+			}
+		}
+		shift(pages)
+	}
+
+	im := &importer{outDir: outDir, titles: make(map[string]string), files: make(map[string]string)}
+	if err := im.index(dir, pages); err != nil {
+		return "", err
+	}
+
+	var out []string
+	if pages[0].Level == 2 {
+		out = append(out, "# "+title, "")
+	}
+	var write func([]*importPage) error
+	write = func(ps []*importPage) error {
+		for _, p := range ps {
+			lines, err := im.convertPage(p)
+			if err != nil {
+				return err
+			}
+			out = append(out, lines...)
+			if err := write(p.Children); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := write(pages); err != nil {
+		return "", err
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// runImport implements "import --from obsidian|notion [--force] <folder> [file]".
+func runImport(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	from := fs.String("from", flavorObsidian, "export format: obsidian or notion")
+	title := fs.String("title", "", "document title (default: the folder name)")
+	force := fs.Bool("force", false, "overwrite an existing file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: sre-learn import --from obsidian|notion [--title text] [--force] <folder> [file]")
+	}
+	dir := fs.Arg(0)
+	target := app.FilePath
+	if fs.NArg() > 1 {
+		target = fs.Arg(1)
+	}
+	if fileExists(target) && !*force {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", target)
+	}
+	if *title == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		*title = pageTitle(filepath.Base(abs))
+	}
+	outDir, err := filepath.Abs(filepath.Dir(target))
+	if err != nil {
+		return err
+	}
+
+	content, err := ImportExport(dir, *from, *title, outDir)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+		return err
+	}
+	checked, total := 0, 0
+	for _, line := range strings.Split(content, "\n") {
+		if isCheckbox(line) {
+			total++
+			if isChecked(line) {
+				checked++
+			}
+		}
+	}
+	fmt.Fprintf(out, "✅ Đã nhập %s vào %s (%d/%d checkbox đã xong)\n", dir, target, checked, total)
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTree creates files (path -> content) under dir.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestImportObsidian(t *testing.T) {
+	dir := t.TempDir()
+	vault := filepath.Join(dir, "vault")
+	writeTree(t, vault, map[string]string{
+		"Start.md":             "---\ntags: [sre]\n---\n# Intro\n\nSee [[Pods|the pods page]] and [[Pods#Probes]], not [[Missing]].\n\n> [!tip]- Remember\n> kubectl explain\n\n- [x] setup\n",
+		"K8s/K8s.md":           "Folder note\n",
+		"K8s/Pods.md":          "## Probes\n\n* [ ] liveness\n    - [x] readiness\n![[diagram.png]]\n",
+		"K8s/img/diagram.png":  "png",
+		".obsidian/app.json":   "{}",
+		"Empty/attachment.pdf": "pdf",
+	})
+
+	content, err := ImportExport(vault, flavorObsidian, "SRE vault", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# SRE vault\n\n## Start\n\n### Intro\n",
+		"See [the pods page](#pods) and [Pods › Probes](#probes), not Missing.",
+		"> **Tip:** Remember\n> kubectl explain",
+		"## K8s\n\nFolder note\n\n### Pods\n\n#### Probes\n\n* [ ] liveness\n    - [x] readiness\n![diagram.png](vault/K8s/img/diagram.png)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q in:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "tags:") || strings.Contains(content, "Empty") {
+		t.Errorf("Unexpected front matter or attachment folder in:\n%s", content)
+	}
+
+	// Links resolve to the sections of the imported document
+	a := parseMarkdown(content)
+	if idx := a.anchorIndex("probes"); idx < 0 || a.Sections[idx].Title != "Probes" {
+		t.Errorf("anchor #probes = %d", idx)
+	}
+	if checked, total := a.GetProgress(a.sectionIndex("Probes")); checked != 1 || total != 2 {
+		t.Errorf("Probes progress = %d/%d", checked, total)
+	}
+}
+
+func TestImportNotion(t *testing.T) {
+	dir := t.TempDir()
+	id := "0123456789abcdef0123456789abcdef"
+	writeTree(t, dir, map[string]string{
+		"Export/SRE Path " + id + ".md":                        "# SRE Path\n\nStart with [Linux](SRE%20Path%20" + id + "/Linux%20" + id + ".md).\n",
+		"Export/SRE Path " + id + "/Linux " + id + ".md":       "# Linux\n\n<details>\n<summary>Commands</summary>\n\n- [ ] ps\n- [x] top\n</details>\n\n<aside>\nUse man pages\n</aside>\n",
+		"Export/SRE Path " + id + "/Linux " + id + "/shot.png": "png",
+		"Export/SRE Path " + id + "/Linux " + id + "/Tools.md": "See ![screen](shot.png)\n",
+	})
+
+	content, err := ImportExport(filepath.Join(dir, "Export"), flavorNotion, "unused", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# SRE Path\n\nStart with [Linux](#linux).",
+		"## Linux\n\n**Commands**\n\n- [ ] ps\n- [x] top\n\n> Use man pages",
+		"### Tools\n\nSee ![screen](Export/SRE%20Path%20" + id + "/Linux%20" + id + "/shot.png)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q in:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "<details>") || strings.Contains(content, id+".md") {
+		t.Errorf("Unconverted Notion syntax in:\n%s", content)
+	}
+}
+
+func TestRunImport(t *testing.T) {
+	withTestApp(t)
+	dir := t.TempDir()
+	writeTree(t, filepath.Join(dir, "notes"), map[string]string{"A.md": "- [ ] one\n"})
+	target := filepath.Join(dir, "path.md")
+
+	var out bytes.Buffer
+	if err := runImport([]string{"--from", "obsidian", filepath.Join(dir, "notes"), target}, &out); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(target)
+	if string(data) != "# notes\n\n## A\n\n- [ ] one\n" {
+		t.Errorf("file = %q", data)
+	}
+	if err := runImport([]string{filepath.Join(dir, "notes"), target}, &out); err == nil {
+		t.Error("Expected an error for an existing file without --force")
+	}
+	if err := runImport([]string{"--from", "roam", "--force", filepath.Join(dir, "notes"), target}, &out); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// inboxTitle is the section collecting notes captured with "sre-learn note".
+const inboxTitle = "Inbox"
+
+// inboxIndex returns the index of the Inbox section, appending it as a
+// level-2 section at the end of the document if it is missing.
+func (a *App) inboxIndex() int {
+	for i, sec := range a.Sections {
+		if strings.EqualFold(sec.Title, inboxTitle) {
+			return i
+		}
+	}
+	for len(a.FileLines) > 0 && strings.TrimSpace(a.FileLines[len(a.FileLines)-1]) == "" {
+		a.FileLines = a.FileLines[:len(a.FileLines)-1]
+	}
+	a.FileLines = append(a.FileLines, "", "## "+inboxTitle, "")
+	a.FileContent = strings.Join(a.FileLines, "\n")
+	a.ParseSections()
+	return len(a.Sections) - 1
+}
+
+// AddInboxNote appends a timestamped note to the Inbox section. The caller
+// saves the file.
+func (a *App) AddInboxNote(text string) {
+	current := a.CurrentIdx
+	a.CurrentIdx = a.inboxIndex()
+	a.AddNote(text)
+	a.UpdateFileSection(a.CurrentIdx)
+	a.CurrentIdx = current
+}
+
+// MoveNote moves a note of section from to the end of section to,
+// keeping its timestamp. The caller saves the file.
+func (a *App) MoveNote(note string, from, to int) {
+	src := &a.Sections[from]
+	src.SetContent(removeNoteFromContent(src.Content(), note))
+	dst := &a.Sections[to]
+	dst.SetContent(strings.TrimRight(dst.Content(), "\n") + "\n\n" + note)
+
+	// Rewrite the later section first, so the line numbers of the earlier
+	// one stay valid
+	logger.Info("moved note", "from", src.Title, "to", dst.Title)
+	a.UpdateFileSection(max(from, to))
+	a.UpdateFileSection(min(from, to))
+}
+
+// runNote implements `sre-learn note "text"`: the note is added to the
+// Inbox section without opening the viewer. Without arguments the note
+// is read from stdin, e.g. `pbpaste | sre-learn note`.
+func runNote(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("note", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	text := strings.Join(fs.Args(), " ")
+	if fs.NArg() == 0 || text == "-" {
+		if fs.NArg() == 0 && isTerminal(os.Stdin) {
+			return fmt.Errorf(`usage: sre-learn note "text" (or pipe the note on stdin)`)
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		text = string(data)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("empty note")
+	}
+
+	app.AddInboxNote(text)
+	if err := app.SaveFile(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Đã thêm vào %s (%s)\n", inboxTitle, app.FilePath)
+	return nil
+}
+
+// moveNoteToSection lets the user pick a note and the section to move it
+// to, for triaging the Inbox.
+func moveNoteToSection(reader *bufio.Reader, notes []string) bool {
+	ClearScreen()
+	fmt.Printf("%s📥 CHUYỂN GHI CHÚ%s\n", Bold+Cyan, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	fmt.Println()
+
+	for i, note := range notes {
+		text := strings.ReplaceAll(noteBody(note), "\n", " ")
+		fmt.Printf("  %s%d%s. %s\n", Cyan, i+1, Reset, truncateEllipsis(text, 100))
+	}
+
+	fmt.Printf("\nChuyển ghi chú số (1-%d) hoặc Enter để hủy: ", len(notes))
+	input, _ := reader.ReadString('\n')
+	idx, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || idx < 1 || idx > len(notes) {
+		return false
+	}
+
+	input, _ = readPrompt(Prompt{Label: "Đến section (số hoặc tên, Tab: gợi ý): ", History: promptHistories["goto"], Complete: app.completeTitle})
+	target := app.FindSection(input)
+	if target < 0 || target == app.CurrentIdx {
+		notify(SeverityWarning, "Không tìm thấy section %q", strings.TrimSpace(input))
+		return false
+	}
+
+	app.MoveNote(notes[idx-1], app.CurrentIdx, target)
+	if !saveReviewed(reader) {
+		return false
+	}
+	notify(SeveritySuccess, "Đã chuyển ghi chú sang %s", app.Sections[target].Title)
+	return true
+}
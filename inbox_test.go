@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunNoteCreatesInbox(t *testing.T) {
+	app := withTestApp(t)
+	app.FilePath = filepath.Join(t.TempDir(), "path.md")
+	app.CurrentIdx = 2
+
+	var out bytes.Buffer
+	if err := runNote([]string{"xem", "lại", "PDB"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(app.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), "## Inbox\n\n\n> **Ghi chú ["+noteTimestamp(t, string(data))+"]:** xem lại PDB") {
+		t.Errorf("file = %q", data)
+	}
+	if app.CurrentIdx != 2 {
+		t.Errorf("CurrentIdx = %d, want 2", app.CurrentIdx)
+	}
+
+	// The second note goes to the same Inbox
+	if err := runNote([]string{"ghi chú 2"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	inbox := app.FindSection(inboxTitle)
+	if n := len(extractNotes(app.Sections[inbox].Content())); n != 2 || inbox != len(app.Sections)-1 || len(app.Sections) != 7 {
+		t.Errorf("Expected one Inbox with 2 notes, got %d notes in section %d of %d", n, inbox, len(app.Sections))
+	}
+}
+
+// noteTimestamp returns the timestamp of the first note in content.
+func noteTimestamp(t *testing.T, content string) string {
+	t.Helper()
+	m := noteTimestampRegex.FindStringSubmatch(content)
+	if m == nil {
+		t.Fatalf("no note in %q", content)
+	}
+	return m[1]
+}
+
+func TestMoveNote(t *testing.T) {
+	app := createTestApp()
+	app.AddInboxNote("dời sang Chapter 2")
+	inbox := app.FindSection(inboxTitle)
+	note := extractNotes(app.Sections[inbox].Content())[0]
+
+	target := app.FindSection("Chapter 2")
+	app.MoveNote(note, inbox, target)
+
+	if notes := extractNotes(app.Sections[inbox].Content()); len(notes) != 0 {
+		t.Errorf("Inbox still has %q", notes)
+	}
+	if notes := extractNotes(app.Sections[target].Content()); len(notes) != 1 || notes[0] != note {
+		t.Errorf("Chapter 2 notes = %q, want %q", notes, note)
+	}
+	// The sections around them are intact
+	if !strings.HasPrefix(app.Sections[target+1].Title, "Giai đoạn 2") || !strings.Contains(app.Sections[2].Content(), "Task one") {
+		t.Errorf("unexpected sections after move: %+v", app.Sections)
+	}
+}
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JournalEntry is one edit of the document lines: the lines from Start
+// up to End are replaced by Lines.
+type JournalEntry struct {
+	Time  time.Time `json:"time"`
+	Start int       `json:"start"`
+	End   int       `json:"end"`
+	Lines []string  `json:"lines"`
+}
+
+// journalHeader starts a journal: the hash of the file content the
+// entries apply to.
+type journalHeader struct {
+	Base    string    `json:"base"`
+	Started time.Time `json:"started"`
+}
+
+// journalBase is the part of the file content a save overwrites in
+// place: the content from Offset on, as it was before. With the bytes
+// before Offset, which the save leaves alone, it gives back the content
+// the journal entries apply to when the save is cut short.
+type journalBase struct {
+	Offset int    `json:"offset"`
+	Tail   string `json:"tail"`
+}
+
+// Journal is the append-only log of the edits not yet saved to the
+// document, so they survive a crash, a dropped SSH session or a power
+// loss. Every edit is synced to disk as it is recorded; a save empties
+// the journal.
+type Journal struct {
+	path string
+	base string
+	// lines are the document lines as of the last entry, and seen the
+	// slice of the document they were last brought up to date with
+	lines []string
+	seen  []string
+	// pending are the entries not yet written
+	pending []JournalEntry
+	entries int
+}
+
+// journalPath returns the journal file of a document.
+func journalPath(docPath string) (string, error) {
+	path, err := documentStateFile(docPath)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(path, ".json") + ".journal", nil
+}
+
+// journalBasePath returns the file keeping the journalBase of a save.
+func journalBasePath(path string) string {
+	return path + ".base"
+}
+
+// contentHash identifies a file content in the journal header.
+func contentHash(content string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+}
+
+// NewJournal returns a journal at path for the edits of lines, read from
+// a file whose content hashes to base. No file is written before the
+// first edit.
+func NewJournal(path, base string, lines []string) *Journal {
+	return &Journal{path: path, base: base, lines: append([]string(nil), lines...), seen: lines}
+}
+
+// sameLines reports whether a and b are the same slice, not just equal
+// lines.
+func sameLines(a, b []string) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// lineSplice returns the edit turning old into new: the lines from start
+// up to end of old replaced by lines. ok is false when they are equal.
+func lineSplice(old, new []string) (start, end int, lines []string, ok bool) {
+	for start < len(old) && start < len(new) && old[start] == new[start] {
+		start++
+	}
+	if start == len(old) && start == len(new) {
+		return 0, 0, nil, false
+	}
+	end, newEnd := len(old), len(new)
+	for end > start && newEnd > start && old[end-1] == new[newEnd-1] {
+		end--
+		newEnd--
+	}
+	return start, end, append([]string(nil), new[start:newEnd]...), true
+}
+
+// sealJournalLine encodes a journal record as one line, encrypted when
+// encryption is on.
+func sealJournalLine(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if vault != nil {
+		sealed, err := vault.Seal(data)
+		if err != nil {
+			return nil, err
+		}
+		data = []byte(base64.StdEncoding.EncodeToString(sealed))
+	}
+	return append(data, '\n'), nil
+}
+
+// openJournalLine decodes a line written by sealJournalLine into v.
+func openJournalLine(line string, v any) error {
+	data := []byte(line)
+	if !strings.HasPrefix(line, "{") {
+		sealed, err := base64.StdEncoding.DecodeString(line)
+		if err != nil {
+			return err
+		}
+		if data, err = openSecure(sealed); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(data, v)
+}
+
+// observe queues the edit from the last recorded lines to lines when
+// the document was replaced by another slice since; the lines are only
+// compared then, so observing an unchanged document costs nothing.
+func (j *Journal) observe(lines []string) {
+	if sameLines(j.seen, lines) {
+		return
+	}
+	if start, end, changed, ok := lineSplice(j.lines, lines); ok {
+		j.pending = append(j.pending, JournalEntry{Time: time.Now(), Start: start, End: end, Lines: changed})
+	}
+	j.lines = append(j.lines[:0], lines...)
+	j.seen = lines
+}
+
+// Splice queues the edit of one section: the lines from start up to end
+// replaced by lines, leaving the document doc. Only the section's lines
+// are copied; the document is not compared.
+func (j *Journal) Splice(start, end int, lines, doc []string) {
+	changed := append([]string(nil), lines...)
+	j.pending = append(j.pending, JournalEntry{Time: time.Now(), Start: start, End: end, Lines: changed})
+	j.lines = slices.Replace(j.lines, start, end, changed...)
+	j.seen = doc
+}
+
+// Record appends the edits queued since the last call and the one from
+// the last recorded lines to lines, if any, and syncs them to disk.
+func (j *Journal) Record(lines []string) error {
+	j.observe(lines)
+	if len(j.pending) == 0 {
+		return nil
+	}
+	var out []byte
+	if j.entries == 0 {
+		header, err := sealJournalLine(journalHeader{Base: j.base, Started: time.Now()})
+		if err != nil {
+			return err
+		}
+		out = header
+	}
+	for _, e := range j.pending {
+		entry, err := sealJournalLine(e)
+		if err != nil {
+			return err
+		}
+		out = append(out, entry...)
+	}
+
+	flags := os.O_APPEND
+	if j.entries == 0 {
+		flags = os.O_TRUNC
+	}
+	if err := writeSynced(j.path, out, flags); err != nil {
+		return err
+	}
+	j.entries += len(j.pending)
+	j.pending = nil
+	return nil
+}
+
+// writeSynced writes data to the file at path, opened with flags added
+// to O_WRONLY|O_CREATE, and syncs it to disk.
+func writeSynced(path string, data []byte, flags int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|flags, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Protect keeps the part of the file content old that saving data in
+// place overwrites, before the save, so a save cut short by a crash can
+// be undone and the journal replayed (see restoreJournalBase).
+func (j *Journal) Protect(old, data string) error {
+	start, oldEnd, newEnd := changedRange(old, data)
+	if start == oldEnd && start == newEnd {
+		return nil
+	}
+	record, err := sealJournalLine(journalBase{Offset: start, Tail: old[start:]})
+	if err != nil {
+		return err
+	}
+	return writeSynced(journalBasePath(j.path), record, os.O_TRUNC)
+}
+
+// restoreJournalBase returns the document text the journal at path
+// applies to when disk is a save of it cut short: disk up to the offset
+// Protect kept, followed by the content that was there. ok is false
+// when nothing was kept or it does not give the content hashing to base.
+func restoreJournalBase(path, disk, base string) (text string, ok bool) {
+	data, err := os.ReadFile(journalBasePath(path))
+	if err != nil {
+		return "", false
+	}
+	var kept journalBase
+	if err := openJournalLine(strings.TrimSuffix(string(data), "\n"), &kept); err != nil || kept.Offset < 0 || kept.Offset > len(disk) {
+		return "", false
+	}
+	content := disk[:kept.Offset] + kept.Tail
+	if contentHash(content) != base {
+		return "", false
+	}
+	text, err = decodeDocument(content)
+	return text, err == nil
+}
+
+// Reset empties the journal after the document was saved or read again
+// with the given content and lines.
+func (j *Journal) Reset(base string, lines []string) error {
+	j.base = base
+	j.lines = append([]string(nil), lines...)
+	j.seen = lines
+	j.pending = nil
+	j.entries = 0
+	for _, path := range []string{j.path, journalBasePath(j.path)} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadJournal reads the journal at path: the hash of the content its
+// entries apply to, and the entries. A missing journal has no entries; a
+// line cut short by a crash ends the journal.
+func LoadJournal(path string) (base string, entries []JournalEntry, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	scanner.Buffer(nil, len(data)+1)
+	if !scanner.Scan() {
+		return "", nil, nil
+	}
+	var header journalHeader
+	if err := openJournalLine(scanner.Text(), &header); err != nil {
+		return "", nil, fmt.Errorf("journal %s is corrupt: %w", path, err)
+	}
+	for scanner.Scan() {
+		var e JournalEntry
+		if err := openJournalLine(scanner.Text(), &e); err != nil {
+			break
+		}
+		entries = append(entries, e)
+	}
+	return header.Base, entries, nil
+}
+
+// ReplayJournal applies the entries to lines in order.
+func ReplayJournal(lines []string, entries []JournalEntry) ([]string, error) {
+	lines = append([]string(nil), lines...)
+	for i, e := range entries {
+		if e.Start < 0 || e.Start > e.End || e.End > len(lines) {
+			return nil, fmt.Errorf("journal entry %d is out of range", i+1)
+		}
+		replaced := append(append(append([]string(nil), lines[:e.Start]...), e.Lines...), lines[e.End:]...)
+		lines = replaced
+	}
+	return lines, nil
+}
+
+// StartJournal begins journaling the edits of the document.
+func (a *App) StartJournal() error {
+	path, err := journalPath(a.FilePath)
+	if err != nil {
+		return err
+	}
+	a.journal = NewJournal(path, contentHash(a.diskContent), a.FileLines)
+	return nil
+}
+
+// syncJournal records the edits made since the last call, warning when
+// the journal cannot be written.
+func (a *App) syncJournal() {
+	if a.journal == nil {
+		return
+	}
+	if err := a.journal.Record(a.FileLines); err != nil {
+		logger.Error("record journal", "path", a.journal.path, "err", err)
+		notify(SeverityWarning, "Không ghi được nhật ký chỉnh sửa: %v", err)
+	}
+}
+
+// resetJournal empties the journal once the document matches the file.
+func (a *App) resetJournal() {
+	if a.journal == nil {
+		return
+	}
+	if err := a.journal.Reset(contentHash(a.diskContent), a.FileLines); err != nil {
+		notify(SeverityWarning, "Không xóa được nhật ký chỉnh sửa: %v", err)
+	}
+}
+
+// journalDocument recovers the unsaved edits of a crashed session, then
+// journals the edits of the document just opened.
+func journalDocument(reader *bufio.Reader) {
+	recoverJournal(reader)
+	if err := app.StartJournal(); err != nil {
+		notify(SeverityWarning, "Không ghi được nhật ký chỉnh sửa: %v", err)
+	}
+}
+
+// recoverJournal offers to replay the edits a previous session journaled
+// but never saved. A save cut short by a crash leaves the document torn
+// between its old and new content; the journal is then replayed onto the
+// old content, restored from what Protect kept. A journal older than the
+// document, or written for a different content of it, is moved aside to
+// <journal>.old instead.
+func recoverJournal(reader *bufio.Reader) {
+	path, err := journalPath(app.FilePath)
+	if err != nil {
+		return
+	}
+	base, entries, err := LoadJournal(path)
+	if err != nil {
+		notify(SeverityWarning, "%v", err)
+		return
+	}
+	if len(entries) == 0 {
+		os.Remove(path)
+		os.Remove(journalBasePath(path))
+		return
+	}
+
+	baseLines, torn := app.FileLines, false
+	if base != contentHash(app.diskContent) {
+		if text, ok := restoreJournalBase(path, app.diskContent, base); ok {
+			baseLines, torn = strings.Split(text, "\n"), true
+			logger.Warn("torn save", "path", app.FilePath, "entries", len(entries))
+		}
+	}
+	journalInfo, jerr := os.Stat(path)
+	docInfo, derr := os.Stat(app.FilePath)
+	stale := jerr == nil && derr == nil && journalInfo.ModTime().Before(docInfo.ModTime())
+	if !torn && (stale || base != contentHash(app.diskContent)) {
+		logger.Warn("stale journal", "path", path, "entries", len(entries))
+		if err := os.Rename(path, path+".old"); err == nil {
+			notify(SeverityWarning, "Nhật ký chỉnh sửa không khớp với file (đã đổi sau đó), giữ lại ở %s", path+".old")
+		}
+		return
+	}
+
+	lines, err := ReplayJournal(baseLines, entries)
+	if err != nil {
+		notify(SeverityWarning, "Không khôi phục được nhật ký chỉnh sửa: %v", err)
+		return
+	}
+	if torn && slices.Equal(lines, app.FileLines) {
+		// The save was written in full, only the journal was left
+		os.Remove(path)
+		os.Remove(journalBasePath(path))
+		return
+	}
+	if torn {
+		fmt.Printf("%s⚠️  Lần lưu trước bị ngắt giữa chừng, file chỉ được ghi một phần.%s\n", Yellow, Reset)
+	}
+	fmt.Printf("%s⚠️  Phiên trước kết thúc với %d thay đổi chưa lưu (lần cuối %s).%s\n", Yellow, len(entries), entries[len(entries)-1].Time.Format("02/01 15:04"), Reset)
+	fmt.Printf("Khôi phục các thay đổi? (Y/n): ")
+	answer, _ := reader.ReadString('\n')
+	replay := strings.TrimSpace(strings.ToLower(answer)) != "n"
+	if !replay {
+		logger.Info("discarded journal", "path", path, "entries", len(entries))
+		if !torn {
+			os.Remove(path)
+			return
+		}
+		// The torn file is put back as it was before the save
+		lines = baseLines
+	}
+
+	app.FileLines = lines
+	app.FileContent = strings.Join(lines, "\n")
+	app.ParseSections()
+	if err := app.SaveFile(); err != nil {
+		notify(SeverityError, "Lỗi lưu: %v (nhật ký được giữ lại)", err)
+		return
+	}
+	os.Remove(path)
+	os.Remove(journalBasePath(path))
+	if !replay {
+		notify(SeveritySuccess, "Đã khôi phục file như trước lần lưu bị ngắt")
+		return
+	}
+	logger.Info("replayed journal", "path", path, "entries", len(entries))
+	notify(SeveritySuccess, "Đã khôi phục %d thay đổi chưa lưu", len(entries))
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineSplice(t *testing.T) {
+	tests := []struct {
+		old, new   string
+		start, end int
+		lines      []string
+	}{
+		{"a\nb\nc", "a\nB\nc", 1, 2, []string{"B"}},
+		{"a\nb\nc", "a\nb\nx\nc", 2, 2, []string{"x"}},
+		{"a\nb\nc", "a\nc", 1, 2, nil},
+		{"a\nb", "a\nb\nc\nd", 2, 2, []string{"c", "d"}},
+		{"a\na\na", "a\na", 2, 3, nil},
+	}
+	for _, tt := range tests {
+		old, new := strings.Split(tt.old, "\n"), strings.Split(tt.new, "\n")
+		start, end, lines, ok := lineSplice(old, new)
+		if !ok || start != tt.start || end != tt.end || len(lines) != len(tt.lines) || len(lines) > 0 && !reflect.DeepEqual(lines, tt.lines) {
+			t.Errorf("lineSplice(%q, %q) = %d, %d, %q, %v", tt.old, tt.new, start, end, lines, ok)
+		}
+		got, err := ReplayJournal(old, []JournalEntry{{Start: start, End: end, Lines: lines}})
+		if err != nil || !reflect.DeepEqual(got, new) {
+			t.Errorf("replaying %q onto %q = %q, %v", tt.new, tt.old, got, err)
+		}
+	}
+	if _, _, _, ok := lineSplice([]string{"a"}, []string{"a"}); ok {
+		t.Error("Expected no edit between equal lines")
+	}
+}
+
+func TestJournalRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.journal")
+	base := []string{"# Doc", "- [ ] one", "- [ ] two"}
+	j := NewJournal(path, contentHash("doc"), base)
+
+	if err := j.Record(base); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("Expected no journal before the first edit")
+	}
+
+	edits := [][]string{
+		{"# Doc", "- [x] one", "- [ ] two"},
+		{"# Doc", "- [x] one", "", "> **Ghi chú [1]:** hi", "- [ ] two"},
+	}
+	for _, lines := range edits {
+		if err := j.Record(lines); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gotBase, entries, err := LoadJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotBase != contentHash("doc") || len(entries) != 2 {
+		t.Fatalf("LoadJournal = %q, %d entries", gotBase, len(entries))
+	}
+	lines, err := ReplayJournal(base, entries)
+	if err != nil || !reflect.DeepEqual(lines, edits[1]) {
+		t.Errorf("ReplayJournal = %q, %v", lines, err)
+	}
+
+	if err := j.Reset(contentHash("saved"), edits[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, entries, _ := LoadJournal(path); len(entries) != 0 {
+		t.Errorf("Expected an empty journal after a save, got %d entries", len(entries))
+	}
+}
+
+func TestJournalSplice(t *testing.T) {
+	for _, large := range []bool{false, true} {
+		a := createTestApp()
+		a.LargeFile = large
+		base := append([]string(nil), a.FileLines...)
+		path := filepath.Join(t.TempDir(), "doc.journal")
+		a.journal = NewJournal(path, "base", a.FileLines)
+
+		start, end := a.Sections[2].Line, a.Sections[3].Line
+		a.CurrentIdx = 2
+		a.ToggleCheckbox(1)
+		a.UpdateFileSection(2)
+		a.Sections[2].SetContent(a.Sections[2].Content() + "\n- [ ] Task four")
+		a.UpdateFileSection(2)
+		a.syncJournal()
+
+		_, entries, err := LoadJournal(path)
+		if err != nil || len(entries) != 2 {
+			t.Fatalf("large=%v: LoadJournal = %d entries, %v", large, len(entries), err)
+		}
+		for _, e := range entries {
+			if e.Start != start || e.End != end {
+				t.Errorf("large=%v: Expected an entry of section 2's lines, got %d-%d", large, e.Start, e.End)
+			}
+		}
+
+		// A document replaced wholesale is still compared with the journal
+		a.FileLines = append(append([]string(nil), a.FileLines...), "tail")
+		a.syncJournal()
+		_, entries, _ = LoadJournal(path)
+		lines, err := ReplayJournal(base, entries)
+		if err != nil || !reflect.DeepEqual(lines, a.FileLines) {
+			t.Errorf("large=%v: ReplayJournal = %q, %v, want %q", large, lines, err, a.FileLines)
+		}
+	}
+}
+
+func TestRecoverTornSave(t *testing.T) {
+	for _, answer := range []string{"\n", "n\n"} {
+		t.Setenv("XDG_STATE_HOME", t.TempDir())
+		path := filepath.Join(t.TempDir(), "doc.md")
+		if err := os.WriteFile(path, []byte(sampleMarkdown), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		a := withTestApp(t)
+		a.FilePath = path
+		if err := a.LoadFile(); err != nil {
+			t.Fatal(err)
+		}
+		a.ParseSections()
+		if err := a.StartJournal(); err != nil {
+			t.Fatal(err)
+		}
+		a.Sections[1].SetContent("Grown by a line\n" + a.Sections[1].Content())
+		a.UpdateFileSection(1)
+		a.syncJournal()
+
+		// The save is cut short halfway through the part it rewrites
+		old, saved := a.diskContent, strings.Join(a.FileLines, "\n")
+		if err := a.journal.Protect(old, saved); err != nil {
+			t.Fatal(err)
+		}
+		start, _, _ := changedRange(old, saved)
+		cut := start + (len(saved)-start)/2
+		if err := os.WriteFile(path, []byte(saved[:cut]+old[cut:]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		a.journal = nil
+		if err := a.Reload(); err != nil {
+			t.Fatal(err)
+		}
+		recoverJournal(bufio.NewReader(strings.NewReader(answer)))
+
+		want := saved
+		if answer == "n\n" {
+			want = old
+		}
+		if data, _ := os.ReadFile(path); string(data) != want {
+			t.Errorf("answer %q: Expected the file to be restored, got %q", answer, data)
+		}
+		journal, _ := journalPath(path)
+		for _, p := range []string{journal, journalBasePath(journal)} {
+			if _, err := os.Stat(p); !os.IsNotExist(err) {
+				t.Errorf("answer %q: Expected %s to be removed", answer, p)
+			}
+		}
+	}
+}
+
+func TestLoadJournalTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.journal")
+	j := NewJournal(path, "base", []string{"a"})
+	if err := j.Record([]string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2024-01-01T00:00:00Z","start":0,"en`)
+	f.Close()
+
+	_, entries, err := LoadJournal(path)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("Expected the complete entry only, got %d, %v", len(entries), err)
+	}
+}
+
+func TestReplayJournalOutOfRange(t *testing.T) {
+	if _, err := ReplayJournal([]string{"a"}, []JournalEntry{{Start: 0, End: 3}}); err == nil {
+		t.Error("Expected an error for an entry past the end")
+	}
+}
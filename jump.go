@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JumpTarget is a place the jump-anywhere overlay (Ctrl+p) can go to: a
+// section title, a checkbox item or a line of a note.
+type JumpTarget struct {
+	Section int
+	// Line is the line of the section content, -1 for the title
+	Line int
+	Kind string
+	Text string
+}
+
+// Kinds of jump targets
+const (
+	jumpTitle = "title"
+	jumpItem  = "item"
+	jumpNote  = "note"
+)
+
+// JumpTargets lists the section titles, checkbox items and note lines of
+// the document, in document order.
+func (a *App) JumpTargets() []JumpTarget {
+	return a.SearchIndex().JumpTargets()
+}
+
+// sectionJumpTargets lists the title, checkbox items and note lines of
+// one section; Section is left for the caller.
+func sectionJumpTargets(title, content string) []JumpTarget {
+	targets := []JumpTarget{{0, -1, jumpTitle, title}}
+	inNote := false
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "> **Ghi chú ["):
+			inNote = true
+			if _, text, ok := strings.Cut(trimmed, ":**"); ok && strings.TrimSpace(text) != "" {
+				targets = append(targets, JumpTarget{0, i, jumpNote, strings.TrimSpace(text)})
+			}
+		case inNote && strings.HasPrefix(trimmed, ">"):
+			if text := strings.TrimSpace(strings.TrimLeft(trimmed, ">")); text != "" {
+				targets = append(targets, JumpTarget{0, i, jumpNote, text})
+			}
+		case isCheckbox(line):
+			inNote = false
+			targets = append(targets, JumpTarget{0, i, jumpItem, checkboxText(line)})
+		default:
+			inNote = false
+		}
+	}
+	return targets
+}
+
+// jumpMatch is a jump target matching the typed query.
+type jumpMatch struct {
+	JumpTarget
+	positions []int
+	score     int
+}
+
+// MatchJumpTargets fuzzy-matches query against the targets, best matches
+// first and ties in document order.
+func MatchJumpTargets(targets []JumpTarget, query string) []jumpMatch {
+	var matches []jumpMatch
+	for _, t := range targets {
+		if score, positions, ok := fuzzyMatch(query, t.Text); ok {
+			matches = append(matches, jumpMatch{t, positions, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	return matches
+}
+
+// narrowJumpTargets returns the targets whose text holds the runes of
+// query in order, the targets fuzzyMatch can match. A longer query only
+// holds a subset of them, so typing narrows the previous targets instead
+// of matching every target again.
+func narrowJumpTargets(targets []JumpTarget, query string) []JumpTarget {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	var narrowed []JumpTarget
+	for _, t := range targets {
+		i := 0
+		for _, r := range strings.ToLower(t.Text) {
+			if i < len(q) && r == q[i] {
+				i++
+			}
+		}
+		if i == len(q) {
+			narrowed = append(narrowed, t)
+		}
+	}
+	return narrowed
+}
+
+// JumpTo opens the target's section scrolled to its line.
+func (a *App) JumpTo(t JumpTarget) bool {
+	if !a.GotoSection(t.Section) {
+		return false
+	}
+	renderer.ResetScroll()
+	if t.Line > 0 {
+		renderer.ScrollToLine(t.Line)
+	}
+	return true
+}
+
+// jumpKindIcon marks the kind of a target in the overlay.
+func jumpKindIcon(kind string) string {
+	switch kind {
+	case jumpItem:
+		return glyphs.Unchecked
+	case jumpNote:
+		return "📝"
+	}
+	return "📑"
+}
+
+// handleJumpAnywhere shows the jump-anywhere overlay: typing fuzzy-filters
+// every title, checkbox item and note line, Enter jumps to the selected
+// one (the best match unless moved with ↑/↓).
+func handleJumpAnywhere() {
+	targets := app.JumpTargets()
+	if len(targets) == 0 {
+		return
+	}
+
+	query := ""
+	candidates := targets
+	matches := MatchJumpTargets(candidates, query)
+	selected, scrollOffset := 0, 0
+	maxVisible := max(1, app.TermHeight-6)
+
+	for {
+		ClearScreen()
+		fmt.Printf("%s\n", barLine(BgMagenta+White+Bold, " 🧭 NHẢY ĐẾN  (gõ để tìm section, checkbox, ghi chú · ↑/↓ · Enter: nhảy · Esc: đóng)", app.TermWidth))
+		fmt.Printf("🔎 %s%s%s %s(%d/%d)%s\n\n", Bold, query, Reset, Dim, len(matches), len(targets), Reset)
+		if len(matches) == 0 {
+			fmt.Printf("%sKhông có kết quả.%s\n", Dim, Reset)
+		}
+
+		if selected < scrollOffset {
+			scrollOffset = selected
+		}
+		if selected >= scrollOffset+maxVisible {
+			scrollOffset = selected - maxVisible + 1
+		}
+		end := min(scrollOffset+maxVisible, len(matches))
+		for i := scrollOffset; i < end; i++ {
+			m := matches[i]
+			selector := "  "
+			if i == selected {
+				selector = Green + glyphs.Selector + " " + Reset
+			}
+			text := highlightPositions(truncateEllipsis(m.Text, 60), m.positions, Bold+Yellow, "")
+			where := ""
+			if m.Kind != jumpTitle {
+				where = Dim + " — " + app.Sections[m.Section].Title + Reset
+			}
+			fmt.Println(truncateVisible(fmt.Sprintf("%s%s %s%s", selector, jumpKindIcon(m.Kind), text, where), app.TermWidth))
+		}
+
+		switch key := readKey(); key {
+		case "Down", "Ctrl+n":
+			if selected < len(matches)-1 {
+				selected++
+			}
+		case "Up", "Ctrl+p":
+			if selected > 0 {
+				selected--
+			}
+		case "Enter":
+			if len(matches) > 0 {
+				app.JumpTo(matches[selected].JumpTarget)
+			}
+			return
+		case "Backspace":
+			if r := []rune(query); len(r) > 0 {
+				query = string(r[:len(r)-1])
+				candidates = narrowJumpTargets(targets, query)
+				matches, selected, scrollOffset = MatchJumpTargets(candidates, query), 0, 0
+			}
+		case "Esc":
+			if query == "" {
+				return
+			}
+			query, candidates = "", targets
+			matches, selected, scrollOffset = MatchJumpTargets(candidates, query), 0, 0
+		case "Ctrl+c":
+			return
+		case "Paste":
+			query += pasteLine(keyboard.Pasted())
+			candidates = narrowJumpTargets(candidates, query)
+			matches, selected, scrollOffset = MatchJumpTargets(candidates, query), 0, 0
+		default:
+			if r, ok := typedRune(key); ok {
+				query += string(r)
+				candidates = narrowJumpTargets(candidates, query)
+				matches, selected, scrollOffset = MatchJumpTargets(candidates, query), 0, 0
+			}
+		}
+	}
+}
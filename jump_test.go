@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJumpTargets(t *testing.T) {
+	a := createTestApp()
+	a.Sections[3].SetContent(a.Sections[3].Content() + "\n> **Ghi chú [2024-05-01 10:00]:** etcd defrag\n> compact trước rồi defrag từng member\n")
+
+	var items, notes []JumpTarget
+	titles := 0
+	for _, target := range a.JumpTargets() {
+		switch target.Kind {
+		case jumpTitle:
+			titles++
+		case jumpItem:
+			items = append(items, target)
+		case jumpNote:
+			notes = append(notes, target)
+		}
+	}
+	if titles != len(a.Sections) {
+		t.Errorf("titles = %d, want %d", titles, len(a.Sections))
+	}
+	if len(items) != 6 || items[0].Text != "Task one" || items[0].Section != 2 {
+		t.Errorf("items = %+v", items)
+	}
+	if len(notes) != 2 || notes[0].Text != "etcd defrag" || notes[1].Text != "compact trước rồi defrag từng member" {
+		t.Fatalf("notes = %+v", notes)
+	}
+	lines := strings.Split(a.Sections[3].Content(), "\n")
+	if !strings.Contains(lines[notes[0].Line], "etcd defrag") {
+		t.Errorf("note line %d = %q", notes[0].Line, lines[notes[0].Line])
+	}
+}
+
+func TestMatchJumpTargets(t *testing.T) {
+	targets := []JumpTarget{
+		{0, -1, jumpTitle, "Debugging etcd"},
+		{1, 4, jumpNote, "etcd defrag"},
+		{2, 2, jumpItem, "Deploy the app"},
+	}
+	matches := MatchJumpTargets(targets, "etcd defrag")
+	if len(matches) != 1 || matches[0].Section != 1 {
+		t.Fatalf("matches = %+v", matches)
+	}
+	if got := MatchJumpTargets(targets, ""); len(got) != 3 || got[0].Section != 0 {
+		t.Errorf("empty query should list every target in order, got %+v", got)
+	}
+	if got := MatchJumpTargets(targets, "dep"); got[0].Section != 2 {
+		t.Errorf("best match for dep = %+v", got[0])
+	}
+}
+
+func TestJumpTo(t *testing.T) {
+	a := withTestApp(t)
+	saved := renderer
+	renderer = NewRenderer(a)
+	t.Cleanup(func() { renderer = saved })
+	renderer.PageSize = 2
+
+	if !a.JumpTo(JumpTarget{2, 3, jumpItem, "Task three"}) {
+		t.Fatal("JumpTo failed")
+	}
+	if a.CurrentIdx != 2 || renderer.ScrollOffset != 3 {
+		t.Errorf("section %d offset %d, want 2 and 3", a.CurrentIdx, renderer.ScrollOffset)
+	}
+	if a.JumpTo(JumpTarget{Section: 99}) {
+		t.Error("JumpTo out of range should fail")
+	}
+}
@@ -0,0 +1,568 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Key binding categories, in the order the help overlay lists them.
+const (
+	categoryScroll   = "Cuộn nội dung"
+	categoryNavigate = "Điều hướng section"
+	categoryEdit     = "Chỉnh sửa & tính năng"
+	categoryDisplay  = "Hiển thị"
+	categorySystem   = "Hệ thống"
+)
+
+// KeyBinding is one entry of the main-view keymap.
+type KeyBinding struct {
+	// Action is the stable name used to remap the binding in the config
+	// file, e.g. "key.next_section=l Right"
+	Action string
+	// Keys are the key names that trigger the action (see keyName)
+	Keys []string
+	// Category groups the binding in the help overlay
+	Category string
+	// Desc is the help text
+	Desc string
+	// Run performs the action; key is the name of the pressed key
+	Run func(key string)
+}
+
+// keymap is the active keymap: the defaults with config overrides applied.
+var keymap []KeyBinding
+
+// resetScrollIf resets the content scroll when a navigation succeeded.
+func resetScrollIf(moved bool) {
+	if moved {
+		renderer.ResetScroll()
+	}
+}
+
+// writable reports whether the document can be edited, telling the user
+// why not when it is read-only.
+func writable() bool {
+	if app.ReadOnly {
+		notify(SeverityWarning, "🔒 Chỉ đọc (%s) - phím sửa bị tắt", app.ReadOnlyReason)
+		return false
+	}
+	return true
+}
+
+// defaultKeyBindings returns the built-in keymap of the main view.
+func defaultKeyBindings() []KeyBinding {
+	return []KeyBinding{
+		// Content scrolling within section
+		{"scroll_down", []string{"j", "Down"}, categoryScroll, "Scroll xuống trong section", func(string) { renderer.ScrollDown() }},
+		{"scroll_up", []string{"k", "Up"}, categoryScroll, "Scroll lên trong section", func(string) { renderer.ScrollUp() }},
+		{"half_page_down", []string{"Ctrl+d"}, categoryScroll, "Scroll nửa trang xuống", func(string) { renderer.Scroll(renderer.PageSize / 2) }},
+		{"half_page_up", []string{"Ctrl+u"}, categoryScroll, "Scroll nửa trang lên", func(string) { renderer.Scroll(-renderer.PageSize / 2) }},
+		{"page_down", []string{"Ctrl+f"}, categoryScroll, "Scroll cả trang xuống", func(string) { renderer.Scroll(renderer.PageSize) }},
+		{"page_up", []string{"Ctrl+b"}, categoryScroll, "Scroll cả trang lên", func(string) { renderer.Scroll(-renderer.PageSize) }},
+		{"top", []string{"<", "Home"}, categoryScroll, "Đầu section", func(string) { renderer.ScrollToTop() }},
+		{"bottom", []string{">", "End"}, categoryScroll, "Cuối section", func(string) { renderer.ScrollToBottom() }},
+
+		// Section navigation
+		{"next_section", []string{"n", "Enter"}, categoryNavigate, "Section tiếp theo (next)", func(string) {
+			if app.NextSection() {
+				renderer.ResetScroll()
+			} else {
+				notify(SeverityWarning, "Đã ở section cuối")
+			}
+		}},
+		{"prev_section", []string{"p"}, categoryNavigate, "Section trước (previous)", func(string) {
+			if app.PrevSection() {
+				renderer.ResetScroll()
+			} else {
+				notify(SeverityWarning, "Đã ở section đầu")
+			}
+		}},
+		{"toc", []string{"t", "T"}, categoryNavigate, "Mở Table of Contents", func(string) {
+			handleTOC()
+			renderer.ResetScroll()
+		}},
+		{"goto_first", []string{"g g"}, categoryNavigate, "Về section đầu tiên", func(string) {
+			app.GotoSection(0)
+			renderer.ResetScroll()
+		}},
+		{"goto", []string{"g n"}, categoryNavigate, "Goto - nhảy đến section theo số", func(string) {
+			handleGoto()
+			renderer.ResetScroll()
+		}},
+		{"goto_last", []string{"G", "g e"}, categoryNavigate, "Goto section cuối", func(string) {
+			app.GotoSection(len(app.Sections) - 1)
+			renderer.ResetScroll()
+		}},
+		{"search", []string{"/"}, categoryNavigate, "Tìm kiếm section", func(string) { handleSearch() }},
+		{"jump_anywhere", []string{"Ctrl+p", "g j"}, categoryNavigate, "Nhảy đến section, checkbox hoặc ghi chú bất kỳ (fuzzy)", func(string) { handleJumpAnywhere() }},
+		{"breadcrumb", []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, categoryNavigate, "Nhảy đến section cha (breadcrumb)", func(key string) {
+			resetScrollIf(app.GotoAncestor(int(key[0] - '0')))
+		}},
+		{"parent", []string{"u", "g u"}, categoryNavigate, "Lên section cha", func(string) { resetScrollIf(app.GotoParent()) }},
+		{"children", []string{"g d"}, categoryNavigate, "Vào section con (số + g d: section con thứ số)", func(string) { handleChildren() }},
+		{"next_sibling", []string{"]"}, categoryNavigate, "Section kế cùng cha", func(string) { resetScrollIf(app.NextSibling()) }},
+		{"prev_sibling", []string{"["}, categoryNavigate, "Section trước cùng cha", func(string) { resetScrollIf(app.PrevSibling()) }},
+		{"next_phase", []string{"}"}, categoryNavigate, "Giai đoạn (##) kế tiếp", func(string) { resetScrollIf(app.NextPhase()) }},
+		{"prev_phase", []string{"{"}, categoryNavigate, "Giai đoạn (##) trước", func(string) { resetScrollIf(app.PrevPhase()) }},
+		{"goto_line", []string{":"}, categoryNavigate, "Đến dòng trong section (120, hoặc 43:120 cho section 43)", func(string) { handleGotoLine() }},
+		{"outline", []string{"g o"}, categoryNavigate, "Dàn ý của section (tiêu đề con, dòng in đậm, danh sách dài, code block)", func(string) { handleOutline() }},
+		{"links", []string{"f", "g f"}, categoryNavigate, "Theo liên kết [text](#anchor), xem backlinks", func(string) { handleLinks() }},
+
+		// Features
+		{"toggle_checkbox", []string{"x", "X"}, categoryEdit, "Toggle checkbox ([ ] → [~] đang làm → [x]; số kèm - = không làm)", func(string) {
+			if writable() {
+				handleToggle()
+			}
+		}},
+		{"note", []string{"a", "A"}, categoryEdit, "Ghi chú (thêm/xem/sửa/xóa)", func(string) {
+			if app.ReviewMode {
+				handleReviewComment()
+			} else if writable() {
+				handleNote()
+			}
+		}},
+		{"edit_section", []string{"e"}, categoryEdit, "Sửa section bằng $EDITOR", func(string) {
+			if writable() {
+				handleEditSection()
+				renderer.ResetScroll()
+			}
+		}},
+		{"structure", []string{"E"}, categoryEdit, "Thêm/tách/di chuyển/xóa section", func(string) {
+			if writable() {
+				handleStructure()
+			}
+		}},
+		{"yank", []string{"y", "Y"}, categoryEdit, "Copy section/ghi chú/code block vào clipboard", func(string) { handleYank() }},
+		{"run_block", []string{"!"}, categoryEdit, "Chạy code block bash/sh (thư mục tạm, có timeout)", func(string) { handleRunBlock() }},
+		{"add_task", []string{"m"}, categoryEdit, "Thêm việc cá nhân (không tính tiến độ)", func(string) {
+			if writable() {
+				handleAddTask()
+			}
+		}},
+		{"task_list", []string{"M"}, categoryEdit, "Danh sách việc cá nhân", func(string) {
+			handleTaskList()
+			renderer.ResetScroll()
+		}},
+		{"agenda", []string{"v"}, categoryEdit, "Agenda việc lặp lại (@every(7d)), tự bỏ đánh dấu khi đến hạn", func(string) { handleAgenda() }},
+		{"summary_cards", []string{"c"}, categoryEdit, "Thẻ tóm tắt (> 📌 Summary:) của một giai đoạn để ôn nhanh", func(string) { handleSummaryCards() }},
+		{"highlight", []string{"V"}, categoryEdit, "Chọn dòng để highlight kèm ghi chú", func(string) { handleSelectHighlight() }},
+		{"highlights", []string{"h"}, categoryEdit, "Danh sách highlight để ôn lại", func(string) { handleHighlights() }},
+		{"bookmark", []string{"B"}, categoryEdit, "Đánh dấu/bỏ đánh dấu section (🔖 trong mục lục)", func(string) { handleBookmark() }},
+		{"glossary", []string{"i"}, categoryEdit, "Tra thuật ngữ trong section Glossary", func(string) { handleGlossary() }},
+		{"lookup", []string{"I"}, categoryEdit, "Tra cứu tldr/man/API, lưu kết quả thành ghi chú", func(string) { handleLookup() }},
+		{"ai", []string{"C"}, categoryEdit, "AI: tóm tắt section, tạo câu hỏi ôn tập, làm quiz", func(string) { handleAI() }},
+		{"flash_review", []string{"F"}, categoryEdit, "Ôn tập ngẫu nhiên các mục đã xong, tự đánh giá", func(string) { handleFlashReview() }},
+		{"history", []string{"H"}, categoryEdit, "Lịch sử hoàn thành checkbox", func(string) { handleHistory() }},
+		{"new_cycle", []string{"N"}, categoryEdit, "Bắt đầu vòng học mới (lưu kết quả, bỏ đánh dấu)", func(string) {
+			if writable() {
+				handleNewCycle()
+			}
+		}},
+		{"undo", []string{"z u"}, categoryEdit, "Hoàn tác thay đổi lớn gần nhất (xóa ghi chú/section, vòng mới, ghi đè section)", func(string) {
+			if writable() {
+				handleUndo()
+			}
+		}},
+		{"diff", []string{"D"}, categoryEdit, "Xem thay đổi chưa lưu (diff), bỏ từng hunk", func(string) { handleDiff() }},
+		{"skip", []string{"z z"}, categoryEdit, "Bỏ qua/học lại section (không tính tiến độ)", func(string) {
+			if !app.ReviewMode {
+				handleSkip()
+			}
+		}},
+		{"skipped_list", []string{"Z", "z l"}, categoryEdit, "Danh sách section đã bỏ qua", func(string) {
+			if !app.ReviewMode {
+				handleSkippedList()
+			}
+		}},
+
+		// Display settings
+		{"presentation", []string{"P"}, categoryDisplay, "Presentation mode (mỗi section một slide)", func(string) {
+			handlePresentation()
+			renderer.ResetScroll()
+		}},
+		{"read_aloud", []string{"R"}, categoryDisplay, "Đọc to section (TTS), tự chuyển section kế tiếp", func(string) { handleReadAloud() }},
+		{"book_mode", []string{"b"}, categoryDisplay, "Bật/tắt book mode (cuộn liền mạch qua section)", func(string) { renderer.BookMode = !renderer.BookMode }},
+		{"line_numbers", []string{"z n"}, categoryDisplay, "Bật/tắt số dòng", func(string) { renderer.ToggleLineNumbers() }},
+		{"split_view", []string{"|"}, categoryDisplay, "Bật/tắt split view (TOC bên trái)", func(string) { renderer.ToggleSplitView() }},
+		{"sidebar_down", []string{"J"}, categoryDisplay, "Cuộn TOC sidebar xuống", func(string) { renderer.ScrollSidebar(3) }},
+		{"sidebar_up", []string{"K"}, categoryDisplay, "Cuộn TOC sidebar lên", func(string) { renderer.ScrollSidebar(-3) }},
+		{"more_lines", []string{"+", "="}, categoryDisplay, "Tăng 10 dòng hiển thị", func(string) { renderer.AdjustPageSize(10) }},
+		{"fewer_lines", []string{"-", "_"}, categoryDisplay, "Giảm 10 dòng hiển thị", func(string) { renderer.AdjustPageSize(-10) }},
+
+		// System
+		{"save", []string{"s", "S"}, categorySystem, "Lưu file & tiến độ", func(string) {
+			if !app.ReadOnly && !saveDocument() {
+				return
+			}
+			app.SaveState(renderer.PageSize)
+			if err := saveSession(); err != nil {
+				notify(SeverityError, "Lỗi lưu phiên: %v", err)
+				return
+			}
+			if app.ReadOnly {
+				notify(SeverityInfo, "🔒 Chỉ đọc: đã lưu vị trí đọc, file không đổi")
+				return
+			}
+			notify(SeveritySuccess, "Đã lưu")
+		}},
+		{"open_file", []string{"O"}, categorySystem, "Mở file khác (duyệt thư mục, file gần đây)", func(string) { handleOpenFile() }},
+		{"recent_files", []string{"o"}, categorySystem, "File gần đây (chuyển nhanh, kèm tiến độ)", func(string) { handleRecentFiles() }},
+		{"sessions", []string{"W"}, categorySystem, "Phiên làm việc (xem/chuyển/lưu)", func(string) { handleSessions() }},
+		{"messages", []string{"L"}, categorySystem, "Lịch sử thông báo", func(string) { handleMessages() }},
+		{"help", []string{"?"}, categorySystem, "Hiển thị help này", func(string) { handleHelp() }},
+		{"macro_record", []string{"q"}, categorySystem, "q<a-z> ghi macro, q dừng ghi (qq = thoát)", handleMacroRecord},
+		{"macro_replay", []string{"@"}, categorySystem, "@<a-z> chạy macro, @@ chạy lại macro vừa dùng", handleMacroReplay},
+		{"quit", []string{"Q", "Ctrl+c"}, categorySystem, "Thoát", func(string) { quit() }},
+	}
+}
+
+// keyName names the first key of b (see decodeKey).
+func keyName(b []byte) string {
+	name, _ := decodeKey(b)
+	return name
+}
+
+// csiKeys name the keys sent as "ESC [ <n> ~".
+var csiKeys = map[string]string{
+	"1": "Home", "2": "Insert", "3": "Delete", "4": "End", "5": "PageUp",
+	"6": "PageDown", "7": "Home", "8": "End", "15": "F5", "17": "F6",
+	"18": "F7", "19": "F8", "20": "F9", "21": "F10", "23": "F11", "24": "F12",
+}
+
+// csiFinals name the keys sent as "ESC [ <final>" or "ESC O <final>",
+// optionally with a modifier parameter ("ESC [ 1 ; 5 A" is Ctrl+Up).
+var csiFinals = map[byte]string{
+	'A': "Up", 'B': "Down", 'C': "Right", 'D': "Left", 'H': "Home", 'F': "End",
+	'P': "F1", 'Q': "F2", 'R': "F3", 'S': "F4", 'Z': "Shift+Tab",
+}
+
+// keyModifiers returns the name prefix of the xterm modifier parameter m:
+// one plus the sum of 1 (Shift), 2 (Alt) and 4 (Ctrl).
+func keyModifiers(m int) string {
+	m--
+	prefix := ""
+	if m&4 != 0 {
+		prefix += "Ctrl+"
+	}
+	if m&2 != 0 {
+		prefix += "Alt+"
+	}
+	if m&1 != 0 {
+		prefix += "Shift+"
+	}
+	return prefix
+}
+
+// decodeCSI decodes the escape sequence "ESC [ ..." at the start of b.
+func decodeCSI(b []byte) (string, int) {
+	end := 2
+	for end < len(b) && b[end] >= 0x30 && b[end] <= 0x3f {
+		end++
+	}
+	if end == len(b) || b[end] < 0x40 || b[end] > 0x7e {
+		// An incomplete sequence: the rest of the input is dropped
+		return "", len(b)
+	}
+	params := strings.Split(string(b[2:end]), ";")
+	name := csiFinals[b[end]]
+	if b[end] == '~' {
+		name = csiKeys[params[0]]
+	}
+	if name == "" {
+		return "", end + 1
+	}
+	if m, err := strconv.Atoi(params[len(params)-1]); err == nil && len(params) == 2 && m > 1 {
+		name = keyModifiers(m) + name
+	}
+	return name, end + 1
+}
+
+// decodeKey decodes the first key of the terminal input b and returns its
+// name and length in bytes: a printable character as itself, otherwise
+// "Enter", "Esc", "Up", "PageDown", "F5", "Ctrl+d", "Ctrl+Up", "Alt+x"
+// and so on. Escape sequences with their modifiers and UTF-8 characters
+// are decoded whole; unknown input is named "".
+func decodeKey(b []byte) (string, int) {
+	if len(b) == 0 {
+		return "", 0
+	}
+	if b[0] == 27 {
+		switch {
+		case len(b) == 1 || b[1] == 0 || b[1] == 27:
+			return "Esc", 1
+		case b[1] == '[':
+			return decodeCSI(b)
+		case b[1] == 'O' && len(b) >= 3:
+			return csiFinals[b[2]], 3
+		}
+		// Alt sends ESC before the key
+		name, size := decodeKey(b[1:])
+		if name == "" {
+			return "", size + 1
+		}
+		return "Alt+" + name, size + 1
+	}
+
+	switch c := b[0]; {
+	case c == 13 || c == 10:
+		return "Enter", 1
+	case c == 9:
+		return "Tab", 1
+	case c == 127 || c == 8:
+		return "Backspace", 1
+	case c == ' ':
+		return "Space", 1
+	case c >= 1 && c <= 26:
+		return "Ctrl+" + string(rune('a'+c-1)), 1
+	case c < 32:
+		return "", 1
+	}
+
+	r, size := utf8.DecodeRune(b)
+	if r == utf8.RuneError {
+		return "", size
+	}
+	return string(r), size
+}
+
+// typedRune returns the character a key types, for the views where
+// typing filters or edits text.
+func typedRune(key string) (rune, bool) {
+	if key == "Space" {
+		return ' ', true
+	}
+	r, size := utf8.DecodeRuneInString(key)
+	return r, size == len(key) && r != utf8.RuneError && r >= ' '
+}
+
+// namedKeys are the key names of more than one character decodeKey
+// produces without modifiers.
+var namedKeys = map[string]bool{
+	"Up": true, "Down": true, "Left": true, "Right": true, "Home": true,
+	"End": true, "Esc": true, "Enter": true, "Tab": true, "Backspace": true,
+	"Space": true, "Insert": true, "Delete": true, "PageUp": true,
+	"PageDown": true, "Shift+Tab": true,
+	"F1": true, "F2": true, "F3": true, "F4": true, "F5": true, "F6": true,
+	"F7": true, "F8": true, "F9": true, "F10": true, "F11": true, "F12": true,
+}
+
+// validKeyName reports whether name is a key name decodeKey can produce:
+// a character, a named key, Ctrl with a letter, or modifiers with a named
+// key ("Ctrl+Up", "Alt+x").
+func validKeyName(name string) bool {
+	if namedKeys[name] || utf8.RuneCountInString(name) == 1 {
+		return true
+	}
+	if letter, ok := strings.CutPrefix(name, "Ctrl+"); ok && len(letter) == 1 {
+		return letter[0] >= 'a' && letter[0] <= 'z'
+	}
+	if key, ok := strings.CutPrefix(name, "Alt+"); ok {
+		return validKeyName(key)
+	}
+	base := strings.TrimPrefix(strings.TrimPrefix(name, "Ctrl+"), "Shift+")
+	return base != name && namedKeys[base]
+}
+
+// A chord is a sequence of keys bound to one action, written as the key
+// names separated by spaces ("g g"). Its first keys are a prefix: pressing
+// them shows the keys that can follow (see handleInput). A prefix may be
+// bound on its own too; it runs when no key follows within chordTimeout.
+const chordSep = " "
+
+// validKey reports whether key is a key name or a chord of key names.
+func validKey(key string) bool {
+	for _, k := range strings.Split(key, chordSep) {
+		if !validKeyName(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseKeySpec converts a key as written in the config to a binding key:
+// a key name as is, anything else as a chord of the characters written
+// together ("gg", "zl"). A capital followed by a lowercase letter or a
+// digit, or a "+", is a key name ("PageUp", "F13"), kept for validation
+// to reject when it is not one.
+func parseKeySpec(spec string) string {
+	if validKeyName(spec) || strings.Contains(spec, "+") {
+		return spec
+	}
+	if len(spec) > 1 && spec[0] >= 'A' && spec[0] <= 'Z' && (spec[1] >= 'a' && spec[1] <= 'z' || spec[1] >= '0' && spec[1] <= '9') {
+		return spec
+	}
+	return strings.Join(strings.Split(spec, ""), chordSep)
+}
+
+// isChordPrefix reports whether prefix is the start of the chord key.
+func isChordPrefix(prefix, key string) bool {
+	return strings.HasPrefix(key, prefix+chordSep)
+}
+
+// chordTimeout is how long a key that is bound on its own and also starts
+// chords waits for the next key before running its own action.
+const chordTimeout = time.Second
+
+// keyNode is a node of the binding trie: the binding completed by the
+// keys leading to it and the keys that can follow them.
+type keyNode struct {
+	binding *KeyBinding
+	next    map[string]*keyNode
+	// order lists the keys of next in keymap order
+	order []string
+}
+
+// buildKeyTrie returns the root of the trie of the bindings' keys.
+func buildKeyTrie(bindings []KeyBinding) *keyNode {
+	root := &keyNode{}
+	for i := range bindings {
+		for _, key := range bindings[i].Keys {
+			node := root
+			for _, k := range strings.Split(key, chordSep) {
+				child := node.next[k]
+				if child == nil {
+					if node.next == nil {
+						node.next = map[string]*keyNode{}
+					}
+					child = &keyNode{}
+					node.next[k] = child
+					node.order = append(node.order, k)
+				}
+				node = child
+			}
+			node.binding = &bindings[i]
+		}
+	}
+	return root
+}
+
+// find returns the node reached by the keys of a key or chord, or nil.
+func (n *keyNode) find(key string) *keyNode {
+	for _, k := range strings.Split(key, chordSep) {
+		if n = n.next[k]; n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// chordEntry is a key that can follow a prefix, with what it does.
+type chordEntry struct {
+	Key  string
+	Desc string
+}
+
+// entries lists the keys that can follow the node, in keymap order. A
+// key starting longer chords is described by the keys that follow it.
+func (n *keyNode) entries() []chordEntry {
+	var entries []chordEntry
+	for _, k := range n.order {
+		child := n.next[k]
+		desc := ""
+		switch {
+		case len(child.order) == 0:
+			desc = child.binding.Desc
+		case child.binding != nil:
+			desc = child.binding.Desc + " (+" + strings.Join(child.order, "/") + " …)"
+		default:
+			desc = "+" + strings.Join(child.order, "/") + " …"
+		}
+		entries = append(entries, chordEntry{k, desc})
+	}
+	return entries
+}
+
+// chordContinuations returns the keys that can follow prefix, in keymap
+// order.
+func chordContinuations(bindings []KeyBinding, prefix string) []chordEntry {
+	if node := buildKeyTrie(bindings).find(prefix); node != nil {
+		return node.entries()
+	}
+	return nil
+}
+
+// applyKeyOverrides returns bindings with the keys of the overridden
+// actions replaced. It fails on unknown actions or key names and when two
+// actions end up sharing a key.
+func applyKeyOverrides(bindings []KeyBinding, overrides map[string][]string) ([]KeyBinding, error) {
+	result := make([]KeyBinding, len(bindings))
+	copy(result, bindings)
+
+	for action, keys := range overrides {
+		found := false
+		for i := range result {
+			if result[i].Action == action {
+				result[i].Keys = keys
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown key action %q", action)
+		}
+		for _, key := range keys {
+			if !validKey(key) {
+				return nil, fmt.Errorf("key.%s: invalid key %q", action, key)
+			}
+		}
+	}
+
+	owner := map[string]string{}
+	for _, kb := range result {
+		for _, key := range kb.Keys {
+			if other, ok := owner[key]; ok {
+				return nil, fmt.Errorf("key %q is bound to both %s and %s", key, other, kb.Action)
+			}
+			owner[key] = kb.Action
+		}
+	}
+	return result, nil
+}
+
+// keyTaken reports whether key is bound, or starts or is started by a
+// bound chord.
+func keyTaken(bindings []KeyBinding, key string) bool {
+	for _, kb := range bindings {
+		for _, k := range kb.Keys {
+			if k == key || isChordPrefix(k, key) || isChordPrefix(key, k) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findBinding returns the binding triggered by key, or nil.
+func findBinding(bindings []KeyBinding, key string) *KeyBinding {
+	for i := range bindings {
+		for _, k := range bindings[i].Keys {
+			if k == key {
+				return &bindings[i]
+			}
+		}
+	}
+	return nil
+}
+
+// keysLabel formats binding keys for display: "j / Down", "1-9" for a run
+// of digits, and upper-case aliases of listed letters left out.
+func keysLabel(keys []string) string {
+	if len(keys) > 3 {
+		return keys[0] + "-" + keys[len(keys)-1]
+	}
+
+	listed := map[string]bool{}
+	for _, k := range keys {
+		listed[k] = true
+	}
+	var shown []string
+	for _, k := range keys {
+		if lower := strings.ToLower(k); lower != k && len(k) == 1 && listed[lower] {
+			continue
+		}
+		shown = append(shown, k)
+	}
+	return strings.Join(shown, " / ")
+}
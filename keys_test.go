@@ -0,0 +1,176 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Key Binding Tests
+// ============================================================================
+
+func TestKeyName(t *testing.T) {
+	tests := []struct {
+		input    []byte
+		expected string
+	}{
+		{[]byte("j"), "j"},
+		{[]byte{27, '[', 'B'}, "Down"},
+		{[]byte{27, '[', 'H'}, "Home"},
+		{[]byte{27}, "Esc"},
+		{[]byte{27, 0, 0}, "Esc"},
+		{[]byte{13}, "Enter"},
+		{[]byte{4}, "Ctrl+d"},
+		{[]byte{3}, "Ctrl+c"},
+		{[]byte{127}, "Backspace"},
+		{[]byte(" "), "Space"},
+		{[]byte("ệ"), "ệ"},
+		{[]byte("\x1b[1;5A"), "Ctrl+Up"},
+		{[]byte("\x1b[1;2C"), "Shift+Right"},
+		{[]byte("\x1b[5~"), "PageUp"},
+		{[]byte("\x1b[3;3~"), "Alt+Delete"},
+		{[]byte("\x1b[15~"), "F5"},
+		{[]byte("\x1bOP"), "F1"},
+		{[]byte("\x1bOA"), "Up"},
+		{[]byte("\x1b[Z"), "Shift+Tab"},
+		{[]byte("\x1bx"), "Alt+x"},
+		{[]byte{27, 4}, "Alt+Ctrl+d"},
+	}
+
+	for _, tt := range tests {
+		if got := keyName(tt.input); got != tt.expected {
+			t.Errorf("keyName(%v) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestKeyboardReadKey(t *testing.T) {
+	kb := &Keyboard{src: strings.NewReader("jệ\x1b[B\x1b[1;5Cq")}
+	for _, want := range []string{"j", "ệ", "Down", "Ctrl+Right", "q"} {
+		if got, err := kb.ReadKey(); err != nil || got != want {
+			t.Fatalf("ReadKey() = %q, %v, expected %q", got, err, want)
+		}
+	}
+	if kb.Waiting() {
+		t.Error("Expected nothing left to read")
+	}
+}
+
+func TestTypedRune(t *testing.T) {
+	for key, want := range map[string]rune{"a": 'a', "ệ": 'ệ', "Space": ' '} {
+		if r, ok := typedRune(key); !ok || r != want {
+			t.Errorf("typedRune(%q) = %q, %v, expected %q", key, r, ok, want)
+		}
+	}
+	for _, key := range []string{"Enter", "Ctrl+a", "Alt+x", "PageUp"} {
+		if _, ok := typedRune(key); ok {
+			t.Errorf("Expected %q not to be typed text", key)
+		}
+	}
+}
+
+func TestValidKeyName(t *testing.T) {
+	for _, name := range []string{"j", "PageDown", "F12", "Ctrl+Up", "Shift+Tab", "Alt+x", "Alt+Ctrl+d"} {
+		if !validKeyName(name) {
+			t.Errorf("Expected %q to be a valid key", name)
+		}
+	}
+	for _, name := range []string{"F13", "Ctrl+", "Ctrl+1", "Hyper+x", "jj"} {
+		if validKeyName(name) {
+			t.Errorf("Expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestKeyTrie(t *testing.T) {
+	bindings := []KeyBinding{
+		{Action: "top", Keys: []string{"g", "g g"}},
+		{Action: "down", Keys: []string{"j"}},
+	}
+	root := buildKeyTrie(bindings)
+	if node := root.find("g"); node == nil || node.binding.Action != "top" || node.find("g") == nil {
+		t.Errorf("Expected g to be bound and to start g g, got %+v", node)
+	}
+	if node := root.find("j"); node == nil || len(node.next) != 0 {
+		t.Errorf("Expected j to be a leaf, got %+v", node)
+	}
+	if root.find("x") != nil {
+		t.Error("Expected no node for an unbound key")
+	}
+}
+
+func TestDefaultKeyBindingsUnique(t *testing.T) {
+	if _, err := applyKeyOverrides(defaultKeyBindings(), nil); err != nil {
+		t.Fatalf("Default keymap has a conflict: %v", err)
+	}
+
+	for _, kb := range defaultKeyBindings() {
+		if kb.Action == "" || kb.Category == "" || kb.Desc == "" || kb.Run == nil {
+			t.Errorf("Incomplete binding: %+v", kb)
+		}
+		for _, key := range kb.Keys {
+			if !validKey(key) {
+				t.Errorf("%s: invalid key name %q", kb.Action, key)
+			}
+		}
+	}
+}
+
+func TestApplyKeyOverrides(t *testing.T) {
+	bindings, err := applyKeyOverrides(defaultKeyBindings(), map[string][]string{"next_section": {"l", "Right"}})
+	if err != nil {
+		t.Fatalf("applyKeyOverrides failed: %v", err)
+	}
+
+	if kb := findBinding(bindings, "l"); kb == nil || kb.Action != "next_section" {
+		t.Errorf("Expected l bound to next_section, got %+v", kb)
+	}
+	if kb := findBinding(bindings, "n"); kb != nil {
+		t.Errorf("Expected n unbound after remap, got %s", kb.Action)
+	}
+
+	// Defaults are not modified
+	if kb := findBinding(defaultKeyBindings(), "n"); kb == nil {
+		t.Error("Expected default keymap unchanged")
+	}
+
+	if _, err := applyKeyOverrides(defaultKeyBindings(), map[string][]string{"quit": {"j"}}); err == nil {
+		t.Error("Expected conflict error")
+	}
+}
+
+func TestKeysLabel(t *testing.T) {
+	tests := []struct {
+		keys     []string
+		expected string
+	}{
+		{[]string{"j", "Down"}, "j / Down"},
+		{[]string{"x", "X"}, "x"},
+		{[]string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, "1-9"},
+		{[]string{"E"}, "E"},
+	}
+
+	for _, tt := range tests {
+		if got := keysLabel(tt.keys); got != tt.expected {
+			t.Errorf("keysLabel(%v) = %q, expected %q", tt.keys, got, tt.expected)
+		}
+	}
+}
+
+func TestHelpLinesFromKeymap(t *testing.T) {
+	app = NewApp()
+	bindings, _ := applyKeyOverrides(defaultKeyBindings(), map[string][]string{"next_section": {"l"}})
+
+	lines := helpLines(helpEntries(bindings), "section tiếp")
+	text := stripANSI(strings.Join(lines, "\n"))
+	if !strings.Contains(text, categoryNavigate) || !strings.Contains(text, "l          Section tiếp theo") {
+		t.Errorf("Expected remapped key in help, got:\n%s", text)
+	}
+	if strings.Contains(text, "Thoát") {
+		t.Errorf("Expected filter to hide unrelated entries, got:\n%s", text)
+	}
+
+	if lines := helpLines(helpEntries(bindings), "zzz-no-match"); len(lines) != 0 {
+		t.Errorf("Expected no lines, got %v", lines)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gutterWidth returns the width of the line-number gutter for content
+// of n lines: the digits of n and the separator.
+func gutterWidth(n int) int {
+	return len(strconv.Itoa(max(n, 1))) + 3
+}
+
+// addGutter prefixes the first numbered lines of rendered with their
+// 1-based line number; the lines after them (footnotes, the child
+// summary) get an empty gutter.
+func addGutter(rendered []string, numbered int) []string {
+	digits := gutterWidth(numbered) - 3
+	for i := range rendered {
+		number := strings.Repeat(" ", digits)
+		if i < numbered {
+			number = fmt.Sprintf("%*d", digits, i+1)
+		}
+		rendered[i] = Dim + number + " " + glyphs.Vertical + Reset + " " + rendered[i]
+	}
+	return rendered
+}
+
+// ToggleLineNumbers shows or hides the line-number gutter.
+func (r *Renderer) ToggleLineNumbers() {
+	r.LineNumbers = !r.LineNumbers
+}
+
+// parseLineRef parses a go-to-line answer: "120" is line 120 of the
+// current section and "43:120" line 120 of section 43. Both are 1-based
+// as shown in the gutter; the result is 0-based.
+func parseLineRef(input string, current int) (section, line int, err error) {
+	section = current
+	sectionPart, linePart, found := strings.Cut(strings.TrimSpace(input), ":")
+	if !found {
+		linePart = sectionPart
+	} else {
+		n, err := strconv.Atoi(strings.TrimSpace(sectionPart))
+		if err != nil || n < 1 {
+			return 0, 0, fmt.Errorf("invalid section number %q", sectionPart)
+		}
+		section = n - 1
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(linePart))
+	if err != nil || n < 1 {
+		return 0, 0, fmt.Errorf("invalid line number %q", linePart)
+	}
+	return section, n - 1, nil
+}
+
+// GotoLine opens section idx scrolled to line of its content. Returns
+// false if either does not exist.
+func (r *Renderer) GotoLine(idx, line int) bool {
+	if idx < 0 || idx >= len(r.App.Sections) || line >= len(r.App.contentLines(r.App.Sections[idx].Content())) {
+		return false
+	}
+	if idx != r.App.CurrentIdx {
+		r.App.GotoSection(idx)
+	}
+	r.ScrollToLine(line)
+	return true
+}
+
+// handleGotoLine asks for a line of the current section ("120") or of
+// another one ("43:120") and scrolls to it.
+func handleGotoLine() {
+	fmt.Println()
+	input, ok := readPrompt(Prompt{Label: "Đến dòng (số dòng, hoặc section:dòng): ", History: promptHistories["line"]})
+	if !ok || input == "" {
+		return
+	}
+	section, line, err := parseLineRef(input, app.CurrentIdx)
+	if err != nil {
+		notify(SeverityWarning, "Không hiểu %q: nhập số dòng, ví dụ 120 hoặc 43:120", input)
+		return
+	}
+	if !renderer.GotoLine(section, line) {
+		notify(SeverityWarning, "Không có dòng %s", input)
+		return
+	}
+	rememberAnswer("line", input)
+}
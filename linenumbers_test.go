@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddGutter(t *testing.T) {
+	rendered := addGutter(make([]string, 12), 10)
+
+	if got := stripANSI(rendered[0]); got != " 1 "+glyphs.Vertical+" " {
+		t.Errorf("Expected a padded number, got %q", got)
+	}
+	if got := stripANSI(rendered[9]); !strings.HasPrefix(got, "10 ") {
+		t.Errorf("Expected line 10 numbered, got %q", got)
+	}
+	if got := stripANSI(rendered[11]); !strings.HasPrefix(got, "   "+glyphs.Vertical) {
+		t.Errorf("Expected an empty gutter after the numbered lines, got %q", got)
+	}
+}
+
+func TestParseLineRef(t *testing.T) {
+	tests := []struct {
+		input         string
+		section, line int
+		ok            bool
+	}{
+		{"120", 4, 119, true},
+		{"43:120", 42, 119, true},
+		{" 2 : 3 ", 1, 2, true},
+		{"0", 0, 0, false},
+		{"x:3", 0, 0, false},
+		{"3:", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		section, line, err := parseLineRef(tt.input, 4)
+		if (err == nil) != tt.ok || (tt.ok && (section != tt.section || line != tt.line)) {
+			t.Errorf("parseLineRef(%q) = %d, %d, %v", tt.input, section, line, err)
+		}
+	}
+}
+
+func TestGotoLine(t *testing.T) {
+	app := createTestApp()
+	r := &Renderer{App: app, PageSize: 2, TermWidth: 80}
+
+	if !r.GotoLine(2, 3) || app.CurrentIdx != 2 || r.ScrollOffset != 3 {
+		t.Errorf("Expected section 2 at line 3, got section %d offset %d", app.CurrentIdx, r.ScrollOffset)
+	}
+	if r.GotoLine(2, 100) || r.GotoLine(9, 0) {
+		t.Error("Expected lines past the content and missing sections to fail")
+	}
+}
+
+func TestVisibleContentLineNumbers(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 2
+	r := &Renderer{App: app, PageSize: 50, TermWidth: 80, LineNumbers: true}
+
+	rendered, _, _ := r.visibleContent(app.Sections[2].Content(), 80)
+	if got := stripANSI(rendered[1]); !strings.HasPrefix(got, "2 "+glyphs.Vertical) || !strings.Contains(got, "Task one") {
+		t.Errorf("Expected line 2 numbered, got %q", got)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// LockInfo identifies the instance holding a document's lock file.
+type LockInfo struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Started time.Time `json:"started"`
+}
+
+// String describes the holder for the warning prompt.
+func (l LockInfo) String() string {
+	if l.PID == 0 {
+		return fmt.Sprintf("một phiên đang mở (từ %s)", l.Started.Format("2006-01-02 15:04"))
+	}
+	return fmt.Sprintf("PID %d trên %s (từ %s)", l.PID, l.Host, l.Started.Format("2006-01-02 15:04"))
+}
+
+// Lock is an advisory lock on a document, held while the viewer runs so
+// a second instance does not silently overwrite the first one's saves.
+type Lock struct {
+	Path string
+	Info LockInfo
+}
+
+// docLock is the lock of the open document, if any.
+var docLock *Lock
+
+// lockPath returns the lock file of a document.
+func lockPath(docPath string) string {
+	return docPath + ".lock"
+}
+
+// processAlive reports whether a process with the given PID runs on this
+// host. A permission error means it exists but belongs to someone else.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// readLock returns the holder recorded in a lock file.
+func readLock(path string) (LockInfo, error) {
+	var info LockInfo
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+	return info, json.Unmarshal(data, &info)
+}
+
+// lockGrace is how long a lock file that cannot be read is taken for
+// one being written by an instance starting right now, rather than for
+// a leftover.
+const lockGrace = 5 * time.Second
+
+// stale reports whether a lock was left behind: its process is gone (on
+// this host), or the file cannot be read.
+func (l LockInfo) stale(host string) bool {
+	return l.PID == 0 || (l.Host == host && !processAlive(l.PID))
+}
+
+// AcquireLock creates the lock file of a document for this process. A
+// stale lock is replaced; a live one is returned as the holder with a nil
+// lock. The lock is written to a temporary file and linked into place,
+// so another instance never reads a lock file still being written.
+func AcquireLock(docPath string, now time.Time) (*Lock, *LockInfo, error) {
+	host, _ := os.Hostname()
+	info := LockInfo{PID: os.Getpid(), Host: host, Started: now}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := lockPath(docPath)
+	tmp := fmt.Sprintf("%s.%d.tmp", path, info.PID)
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return nil, nil, err
+	}
+	defer os.Remove(tmp)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		err := createLock(tmp, path, data)
+		if err == nil {
+			return &Lock{Path: path, Info: info}, nil, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, nil, err
+		}
+
+		holder, err := readLock(path)
+		if err != nil {
+			// Written by an older version that creates the file first,
+			// or left broken: only a fresh one may still be in use
+			if stat, statErr := os.Stat(path); statErr == nil && time.Since(stat.ModTime()) < lockGrace {
+				holder = LockInfo{Started: stat.ModTime()}
+				return nil, &holder, nil
+			}
+		}
+		if !holder.stale(host) {
+			return nil, &holder, nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, nil, err
+		}
+	}
+	return nil, nil, fmt.Errorf("cannot lock %s", docPath)
+}
+
+// createLock links the written lock file tmp to path, failing with
+// os.ErrExist when path exists. On file systems without hard links the
+// lock is created exclusively and written instead.
+func createLock(tmp, path string, data []byte) error {
+	err := os.Link(tmp, path)
+	if err == nil || errors.Is(err, os.ErrExist) {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+// Release removes the lock file if it is still this process's.
+func (l *Lock) Release() {
+	if l == nil {
+		return
+	}
+	if holder, err := readLock(l.Path); err == nil && holder.PID == l.Info.PID && holder.Host == l.Info.Host {
+		os.Remove(l.Path)
+	}
+}
+
+// lockDocument locks the open document for the viewer, releasing the
+// lock of the previous one, and starts journaling its edits. When another
+// instance holds it, the user chooses between read-only mode, opening it
+// anyway (taking the lock over) or quitting. Review mode, piped documents
+// and documents opened read-only (see detectReadOnly) take no lock.
+func lockDocument(reader *bufio.Reader) error {
+	docLock.Release()
+	docLock = nil
+	app.journal = nil
+	if app.ReviewMode || app.Piped {
+		return nil
+	}
+	app.ReadOnly, app.ReadOnlyReason = false, ""
+	if reason := app.detectReadOnly(); reason != "" {
+		app.SetReadOnly(reason)
+		return nil
+	}
+
+	lock, holder, err := AcquireLock(app.FilePath, time.Now())
+	if err != nil {
+		return err
+	}
+	if holder == nil {
+		docLock = lock
+		journalDocument(reader)
+		return nil
+	}
+
+	fmt.Printf("%s⚠️  %s đang được mở bởi %s.%s\n", Yellow, app.FilePath, holder, Reset)
+	fmt.Println("Hai phiên cùng lưu sẽ ghi đè thay đổi của nhau.")
+	fmt.Printf("[r] Mở chỉ đọc  [o] Vẫn mở để sửa  [q] Thoát: ")
+	choice, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(choice)) {
+	case "o":
+		os.Remove(lockPath(app.FilePath))
+		if docLock, _, err = AcquireLock(app.FilePath, time.Now()); err != nil {
+			return err
+		}
+		journalDocument(reader)
+		return nil
+	case "q":
+		os.Exit(0)
+	}
+	app.SetReadOnly(readOnlyLocked)
+	notify(SeverityWarning, "Chỉ đọc: %s đang được mở ở phiên khác", app.FilePath)
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	doc := filepath.Join(t.TempDir(), "path.md")
+	now := time.Now()
+
+	lock, holder, err := AcquireLock(doc, now)
+	if err != nil || lock == nil || holder != nil {
+		t.Fatalf("AcquireLock = %v, %v, %v", lock, holder, err)
+	}
+	if _, holder, _ := AcquireLock(doc, now); holder == nil || holder.PID != os.Getpid() {
+		t.Errorf("Expected the second instance to see the holder, got %+v", holder)
+	}
+
+	lock.Release()
+	if fileExists(lockPath(doc)) {
+		t.Error("Expected Release to remove the lock file")
+	}
+}
+
+func TestAcquireLockStale(t *testing.T) {
+	doc := filepath.Join(t.TempDir(), "path.md")
+	host, _ := os.Hostname()
+	write := func(info LockInfo) {
+		data, _ := json.Marshal(info)
+		os.WriteFile(lockPath(doc), data, 0o644)
+	}
+
+	// A crashed instance on this host
+	write(LockInfo{PID: 1 << 30, Host: host})
+	if lock, holder, err := AcquireLock(doc, time.Now()); err != nil || lock == nil {
+		t.Errorf("Expected a stale lock to be replaced, got %+v, %v", holder, err)
+	}
+
+	// Another machine sharing the file: its process cannot be checked
+	write(LockInfo{PID: 1 << 30, Host: "other-" + host})
+	if _, holder, _ := AcquireLock(doc, time.Now()); holder == nil {
+		t.Error("Expected a lock from another host to be respected")
+	}
+
+	// A lock taken over by someone else is not removed on release
+	lock := &Lock{Path: lockPath(doc), Info: LockInfo{PID: os.Getpid(), Host: host}}
+	lock.Release()
+	if !fileExists(lockPath(doc)) {
+		t.Error("Expected another instance's lock to be kept")
+	}
+
+	os.WriteFile(lockPath(doc), []byte("garbage"), 0o644)
+	old := time.Now().Add(-2 * lockGrace)
+	os.Chtimes(lockPath(doc), old, old)
+	if lock, _, _ := AcquireLock(doc, time.Now()); lock == nil {
+		t.Error("Expected an old unreadable lock to be replaced")
+	}
+}
+
+func TestAcquireLockEmptyFile(t *testing.T) {
+	doc := filepath.Join(t.TempDir(), "path.md")
+
+	// Another instance created the lock file and has not written it yet
+	os.WriteFile(lockPath(doc), nil, 0o644)
+	lock, holder, err := AcquireLock(doc, time.Now())
+	if err != nil || lock != nil || holder == nil {
+		t.Fatalf("Expected a fresh empty lock to be live, got %v, %+v, %v", lock, holder, err)
+	}
+	if !fileExists(lockPath(doc)) {
+		t.Error("Expected the empty lock to be kept")
+	}
+	if matches, _ := filepath.Glob(lockPath(doc) + ".*.tmp"); len(matches) > 0 {
+		t.Errorf("Expected the temporary lock file to be removed, got %v", matches)
+	}
+
+	old := time.Now().Add(-2 * lockGrace)
+	os.Chtimes(lockPath(doc), old, old)
+	if lock, _, _ := AcquireLock(doc, time.Now()); lock == nil {
+		t.Error("Expected an empty lock left behind to be replaced")
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Log rotation: the log file is rotated once it grows past logMaxSize,
+// keeping logBackups older files (sre-learn.log.1 is the newest).
+const (
+	logMaxSize = 1 << 20
+	logBackups = 3
+)
+
+// logger records what the file IO, state, journal, trash, sync and
+// integration code did, for diagnosing reports like a vanished note. It
+// discards everything until openLog is called.
+var logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// rotatingFile is a log file rotated by size. It is opened on the first
+// write, so a session that logs nothing creates no file.
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	f       *os.File
+	size    int64
+}
+
+// Write appends p to the file, rotating it first when p would take it
+// past maxSize.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// open opens the file for appending.
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// rotate shifts the backups by one, dropping the oldest, moves the file
+// to the first backup and starts a new one.
+func (r *rotatingFile) rotate() error {
+	r.f.Close()
+	r.f = nil
+	for i := r.backups - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if r.backups > 0 {
+		os.Rename(r.path, r.path+".1")
+	} else {
+		os.Remove(r.path)
+	}
+	return r.open()
+}
+
+// logPath returns the log file in the cache dir.
+func logPath() (string, error) {
+	dir, err := cacheHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sre-learn.log"), nil
+}
+
+// openLog sends the logger to the rotating log file at path: saves,
+// deletions, recoveries and failures, and the details of every read,
+// write and request with verbose (--verbose).
+func openLog(path string, verbose bool) {
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	w := &rotatingFile{path: path, maxSize: logMaxSize, backups: logBackups}
+	logger = slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
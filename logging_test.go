@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "sre-learn.log")
+	r := &rotatingFile{path: path, maxSize: 10, backups: 2}
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n", "five\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := map[string]string{path: "four\nfive\n", path + ".1": "three\n", path + ".2": "one\ntwo\n"}
+	for file, content := range want {
+		if data, err := os.ReadFile(file); err != nil || string(data) != content {
+			t.Errorf("%s = %q, %v, want %q", filepath.Base(file), data, err, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("Expected the oldest backup to be dropped")
+	}
+}
+
+func TestOpenLogLevels(t *testing.T) {
+	t.Cleanup(func() { logger = slog.New(slog.NewTextHandler(io.Discard, nil)) })
+	for _, verbose := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "sre-learn.log")
+		openLog(path, verbose)
+		logger.Debug("read document", "path", "doc.md")
+		logger.Info("saved document", "path", "doc.md")
+
+		data, _ := os.ReadFile(path)
+		log := string(data)
+		if !strings.Contains(log, "level=INFO") || !strings.Contains(log, `msg="saved document" path=doc.md`) {
+			t.Errorf("Expected the save logged, got %q", log)
+		}
+		if strings.Contains(log, "level=DEBUG") != verbose {
+			t.Errorf("verbose=%v: unexpected debug lines in %q", verbose, log)
+		}
+	}
+}
+
+func TestSaveFileLogs(t *testing.T) {
+	t.Cleanup(func() { logger = slog.New(slog.NewTextHandler(io.Discard, nil)) })
+	path := filepath.Join(t.TempDir(), "sre-learn.log")
+	openLog(path, false)
+
+	a := createTestApp()
+	a.FilePath = filepath.Join(t.TempDir(), "doc.md")
+	if err := a.SaveFile(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), `msg="saved document"`) {
+		t.Errorf("Expected the save in the log, got %q", data)
+	}
+}
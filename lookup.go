@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// lookupTimeout limits how long a lookup may take.
+const lookupTimeout = 10 * time.Second
+
+// lookupNoteLines caps the lookup output saved in a note.
+const lookupNoteLines = 40
+
+// lookupSource looks a term up in an external reference.
+type lookupSource struct {
+	name string
+	run  func(ctx context.Context, term string) (string, error)
+}
+
+// overstrikeRegex matches the backspace bold/underline of man output.
+var overstrikeRegex = regexp.MustCompile(".\b")
+
+// runLookupCommand runs a reference command and returns its output.
+func runLookupCommand(ctx context.Context, name string, args []string, env ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %s", name, msg)
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return string(out), nil
+}
+
+// lookupSources returns the sources in the order they are tried: tldr
+// and man, then the HTTP API if lookup_url is set. Man pages are
+// formatted for the given width.
+func lookupSources(cfg Config, width int) []lookupSource {
+	sources := []lookupSource{
+		{"tldr", func(ctx context.Context, term string) (string, error) {
+			return runLookupCommand(ctx, "tldr", strings.Fields(term))
+		}},
+		{"man", func(ctx context.Context, term string) (string, error) {
+			// "man 5 crontab" selects a section
+			out, err := runLookupCommand(ctx, "man", strings.Fields(term),
+				"MANPAGER=cat", "PAGER=cat", fmt.Sprintf("MANWIDTH=%d", min(max(40, width-2), 100)))
+			return overstrikeRegex.ReplaceAllString(out, ""), err
+		}},
+	}
+	if cfg.LookupURL != "" {
+		sources = append(sources, lookupSource{"http", func(ctx context.Context, term string) (string, error) {
+			return httpLookup(ctx, cfg.LookupURL, term)
+		}})
+	}
+	return sources
+}
+
+// httpLookup fetches the lookup URL with {term} replaced. JSON responses
+// are indented for reading.
+func httpLookup(ctx context.Context, template, term string) (string, error) {
+	target := strings.ReplaceAll(template, "{term}", url.QueryEscape(term))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: %s", target, resp.Status)
+	}
+	var indented bytes.Buffer
+	if json.Indent(&indented, body, "", "  ") == nil {
+		return indented.String(), nil
+	}
+	return string(body), nil
+}
+
+// Lookup tries the sources in order and returns the first result with
+// the name of its source. The errors of the sources are returned if none
+// has an entry for the term.
+func Lookup(ctx context.Context, sources []lookupSource, term string) (string, string, error) {
+	var errs []string
+	for _, s := range sources {
+		out, err := s.run(ctx, term)
+		if err == nil && strings.TrimSpace(out) != "" {
+			return s.name, strings.TrimRight(out, "\n"), nil
+		}
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) == 0 {
+		return "", "", fmt.Errorf("không có kết quả cho %q", term)
+	}
+	return "", "", fmt.Errorf("%s", strings.Join(errs, "; "))
+}
+
+// lookupNote returns the note saving a lookup: the output in a code
+// block, cut to lookupNoteLines lines.
+func lookupNote(source, term, output string) string {
+	lines := strings.Split(stripANSI(output), "\n")
+	if len(lines) > lookupNoteLines {
+		lines = append(lines[:lookupNoteLines], "...")
+	}
+	return fmt.Sprintf("🔎 %s `%s`\n\n```\n%s\n```", source, term, strings.Join(lines, "\n"))
+}
+
+// handleLookup prompts for a term, shows its man page, tldr page or HTTP
+// API entry in an overlay and offers to save it as a note.
+func handleLookup() {
+	sources := lookupSources(config, app.TermWidth)
+	names := make([]string, len(sources))
+	for i, s := range sources {
+		names[i] = s.name
+	}
+	terminal.SetRawMode(false)
+	fmt.Printf("\n%s🔎 Tra cứu (%s):%s ", Bold, strings.Join(names, "/"), Reset)
+	input := bufio.NewReader(keyboard)
+	term, _ := input.ReadString('\n')
+	term = strings.TrimSpace(term)
+	if term == "" {
+		terminal.SetRawMode(true)
+		return
+	}
+	fmt.Printf("%sĐang tra %s...%s", Dim, term, Reset)
+	terminal.SetRawMode(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	source, output, err := Lookup(ctx, sources, term)
+	if err != nil {
+		notify(SeverityError, "Tra cứu %q thất bại: %v", term, err)
+		return
+	}
+	showOverlay(fmt.Sprintf("🔎 %s: %s", source, term), strings.Split(output, "\n"))
+
+	if app.ReadOnly {
+		return
+	}
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	ClearScreen()
+	fmt.Printf("Lưu kết quả %s %q thành ghi chú? (y/N): ", source, term)
+	answer, _ := input.ReadString('\n')
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer == "y" || answer == "yes" {
+		saveNote(lookupNote(source, term, output))
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLookupFallsBackToMan(t *testing.T) {
+	// tldr has no page; man prints with backspace overstrike
+	dir := fakeTool(t, "tldr", "echo 'Page not found' >&2\nexit 1\n")
+	script := "printf 'N\\bNA\\bAM\\bME\\bE\\n    kill - send a signal %s\\n' \"$*\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "man"), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	source, out, err := Lookup(context.Background(), lookupSources(DefaultConfig(), 80), "1 kill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source != "man" || out != "NAME\n    kill - send a signal 1 kill" {
+		t.Errorf("Lookup = %q, %q", source, out)
+	}
+}
+
+func TestLookupHTTP(t *testing.T) {
+	fakeTool(t, "none", "")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "error budget" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"term":"error budget","definition":"1 - SLO"}`))
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.LookupURL = srv.URL + "/define?q={term}"
+	source, out, err := Lookup(context.Background(), lookupSources(cfg, 80), "error budget")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source != "http" || !strings.Contains(out, "\n  \"definition\": \"1 - SLO\"") {
+		t.Errorf("Lookup = %q, %q", source, out)
+	}
+
+	if _, _, err := Lookup(context.Background(), lookupSources(cfg, 80), "unknown"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected the 404 in the error, got %v", err)
+	}
+}
+
+func TestLookupNote(t *testing.T) {
+	output := strings.Repeat("line\n", lookupNoteLines+5)
+	note := lookupNote("tldr", "tar", Bold+output+Reset)
+	if !strings.HasPrefix(note, "🔎 tldr `tar`\n\n```\nline\n") || !strings.HasSuffix(note, "line\n...\n```") {
+		t.Errorf("note = %q", note)
+	}
+	if strings.Contains(note, "\033[") {
+		t.Error("Expected ANSI codes to be stripped from the note")
+	}
+}
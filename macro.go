@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+// maxReplayKeys bounds the keys one replay may expand to, so a macro that
+// replays itself stops instead of looping forever.
+const maxReplayKeys = 10000
+
+// Keyboard is the source of all interactive input. It serves queued macro
+// keys before reading the terminal and records what the user types while
+// a macro is being recorded.
+type Keyboard struct {
+	src     io.Reader
+	pending []byte
+	// buffered is terminal input read ahead by ReadKey: a read can return
+	// several keys (fast typing, a paste), which are served one by one
+	buffered []byte
+	// pasted is the text of the last paste ReadKey returned as "Paste"
+	pasted string
+	// replayed counts the keys queued since the terminal was last read
+	replayed int
+	// recording is the register being recorded, "" if none
+	recording string
+	recorded  []byte
+	// readAt is when the terminal was last read, for the input latency
+	// of --debug
+	readAt time.Time
+}
+
+// keyboard is the input used by the viewer and all of its prompts.
+var keyboard = &Keyboard{src: os.Stdin}
+
+// Read serves one queued key at a time, so key reads and line prompts
+// consume replayed input exactly like typed input, then the input read
+// ahead and finally the terminal. Pastes are served as one line of text.
+func (k *Keyboard) Read(p []byte) (int, error) {
+	if len(k.pending) > 0 {
+		k.pending = flattenPastes(k.pending)
+		_, size := decodeKey(k.pending)
+		n := copy(p, k.pending[:max(1, size)])
+		k.pending = k.pending[n:]
+		return n, nil
+	}
+	if len(k.buffered) == 0 {
+		data, err := k.readTerminal()
+		if len(data) == 0 {
+			return 0, err
+		}
+		k.buffered = data
+	}
+	k.buffered = flattenPastes(k.buffered)
+	n := copy(p, k.buffered)
+	k.buffered = k.buffered[n:]
+	return n, nil
+}
+
+// readTerminal reads the terminal, reading on until the end of a paste
+// that arrives in several reads, and records the input while a macro is
+// being recorded.
+func (k *Keyboard) readTerminal() ([]byte, error) {
+	k.replayed = 0
+	chunk := make([]byte, 256)
+	n, err := k.src.Read(chunk)
+	data := append([]byte(nil), chunk[:n]...)
+	for err == nil && n > 0 && incompletePaste(data) {
+		n, err = k.src.Read(chunk)
+		data = append(data, chunk[:n]...)
+	}
+	if k.recording != "" {
+		k.recorded = append(k.recorded, data...)
+	}
+	if n > 0 {
+		k.readAt = time.Now()
+	}
+	return data, err
+}
+
+// ReadKey reads the next key and returns its name (see decodeKey). A
+// paste is returned whole as the key "Paste", its text kept for Pasted.
+// A timed out terminal read returns "".
+func (k *Keyboard) ReadKey() (string, error) {
+	if len(k.pending) > 0 {
+		return k.takeKey(&k.pending), nil
+	}
+	if len(k.buffered) == 0 {
+		data, err := k.readTerminal()
+		if len(data) == 0 {
+			return "", err
+		}
+		k.buffered = data
+	}
+	return k.takeKey(&k.buffered), nil
+}
+
+// takeKey removes the first key, or paste, from the input in buf.
+func (k *Keyboard) takeKey(buf *[]byte) string {
+	if text, rest, ok := takePaste(*buf); ok {
+		k.pasted, *buf = text, rest
+		return "Paste"
+	}
+	name, size := decodeKey(*buf)
+	*buf = (*buf)[max(1, size):]
+	return name
+}
+
+// Pasted returns the text of the last "Paste" key.
+func (k *Keyboard) Pasted() string {
+	return k.pasted
+}
+
+// Waiting reports whether keys are queued or read ahead, so reading the
+// next one does not wait for the terminal.
+func (k *Keyboard) Waiting() bool {
+	return len(k.pending) > 0 || len(k.buffered) > 0
+}
+
+// Replaying reports whether queued macro keys are waiting to be read.
+func (k *Keyboard) Replaying() bool {
+	return len(k.pending) > 0
+}
+
+// Replay queues keys to be read before the rest of the pending input, so
+// a macro called from another macro runs at the point it was called.
+func (k *Keyboard) Replay(keys string) error {
+	k.replayed += len(keys)
+	if k.replayed > maxReplayKeys {
+		k.pending = nil
+		return fmt.Errorf("macro dừng sau %d phím (macro gọi lại chính nó?)", maxReplayKeys)
+	}
+	k.pending = append([]byte(keys), k.pending...)
+	return nil
+}
+
+// Recording returns the register being recorded, or "".
+func (k *Keyboard) Recording() string {
+	return k.recording
+}
+
+// StartRecording starts recording typed keys into register.
+func (k *Keyboard) StartRecording(register string) {
+	k.recording = register
+	k.recorded = nil
+}
+
+// StopRecording ends the recording and returns its register and keys,
+// leaving out the last trim bytes (the key that stopped it).
+func (k *Keyboard) StopRecording(trim int) (register, keys string) {
+	register = k.recording
+	keys = string(k.recorded[:max(0, len(k.recorded)-trim)])
+	k.recording = ""
+	k.recorded = nil
+	return register, keys
+}
+
+// validRegister reports whether key names a macro register (a-z).
+func validRegister(key string) bool {
+	return len(key) == 1 && key[0] >= 'a' && key[0] <= 'z'
+}
+
+// readKey reads a single key from the keyboard.
+func readKey() string {
+	key, _ := keyboard.ReadKey()
+	return key
+}
+
+// readKeyTimeout reads a key like readKey, giving up after d when none is
+// typed (ok is false).
+func readKeyTimeout(d time.Duration) (key string, ok bool) {
+	if keyboard.Waiting() {
+		return readKey(), true
+	}
+	terminal.SetReadTimeout(true)
+	defer terminal.SetReadTimeout(false)
+	for deadline := time.Now().Add(d); time.Now().Before(deadline); {
+		key, err := keyboard.ReadKey()
+		if key != "" {
+			return key, true
+		}
+		if err != nil {
+			break
+		}
+	}
+	return "", false
+}
+
+// handleMacroRecord implements q: while recording it stops and stores the
+// macro; otherwise it reads a register (a-z) and starts recording into
+// it. "qq" quits, as q alone did before macros.
+func handleMacroRecord(key string) {
+	if keyboard.Recording() != "" {
+		register, keys := keyboard.StopRecording(len(key))
+		if app.Macros == nil {
+			app.Macros = make(map[string]string)
+		}
+		app.Macros[register] = keys
+		app.SaveState(renderer.PageSize)
+		notify(SeveritySuccess, "Đã lưu macro @%s (%d phím)", register, utf8.RuneCountInString(keys))
+		return
+	}
+
+	notify(SeverityInfo, "q… chọn register a-z để ghi macro, q để thoát")
+	renderer.Render()
+	switch register := readKey(); {
+	case register == key:
+		quit()
+	case validRegister(register):
+		keyboard.StartRecording(register)
+		notify(SeverityInfo, "Đang ghi macro @%s (nhấn %s để dừng)", register, key)
+	default:
+		notify(SeverityInfo, "Đã hủy")
+	}
+}
+
+// lastMacro is the register replayed last, used by @@.
+var lastMacro string
+
+// handleMacroReplay implements @<reg>: it queues the keys of the macro in
+// register (or of the last replayed one for @@).
+func handleMacroReplay(key string) {
+	register := readKey()
+	if register == key {
+		register = lastMacro
+	}
+
+	keys, ok := app.Macros[register]
+	if !validRegister(register) || !ok {
+		notify(SeverityWarning, "Không có macro @%s", register)
+		return
+	}
+	lastMacro = register
+	if err := keyboard.Replay(keys); err != nil {
+		notify(SeverityError, "%v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Macro Tests
+// ============================================================================
+
+func TestKeyboardReplaysOneKeyAtATime(t *testing.T) {
+	kb := &Keyboard{src: strings.NewReader("typed")}
+	kb.Replay("j\x1b[Bx")
+
+	var keys []string
+	for kb.Replaying() {
+		b := make([]byte, 3)
+		n, _ := kb.Read(b)
+		keys = append(keys, keyName(b[:n]))
+	}
+	if strings.Join(keys, ",") != "j,Down,x" {
+		t.Errorf("Expected j,Down,x, got %v", keys)
+	}
+
+	// Then the terminal is read again
+	b := make([]byte, 8)
+	n, _ := kb.Read(b)
+	if string(b[:n]) != "typed" {
+		t.Errorf("Expected terminal input after replay, got %q", b[:n])
+	}
+}
+
+func TestKeyboardReplayFeedsLinePrompts(t *testing.T) {
+	kb := &Keyboard{src: strings.NewReader("")}
+	kb.Replay("ghi chú\nx")
+
+	line, _ := bufio.NewReader(kb).ReadString('\n')
+	if line != "ghi chú\n" {
+		t.Errorf("Expected prompt line, got %q", line)
+	}
+	if !kb.Replaying() {
+		t.Error("Expected the prompt not to consume keys after the newline")
+	}
+}
+
+func TestKeyboardRecording(t *testing.T) {
+	kb := &Keyboard{src: strings.NewReader("xnq")}
+	kb.StartRecording("a")
+	if kb.Recording() != "a" {
+		t.Fatalf("Expected recording into a, got %q", kb.Recording())
+	}
+
+	for i := 0; i < 3; i++ {
+		kb.Read(make([]byte, 1))
+	}
+
+	register, keys := kb.StopRecording(1)
+	if register != "a" || keys != "xn" {
+		t.Errorf("Expected @a = \"xn\", got @%s = %q", register, keys)
+	}
+	if kb.Recording() != "" {
+		t.Error("Expected recording to stop")
+	}
+}
+
+func TestKeyboardReplayNotRecorded(t *testing.T) {
+	kb := &Keyboard{src: strings.NewReader("k")}
+	kb.StartRecording("b")
+	kb.Replay("j")
+	kb.Read(make([]byte, 3))
+	kb.Read(make([]byte, 3))
+
+	if _, keys := kb.StopRecording(0); keys != "k" {
+		t.Errorf("Expected only typed keys recorded, got %q", keys)
+	}
+}
+
+func TestKeyboardReplayRecursionStops(t *testing.T) {
+	kb := &Keyboard{src: strings.NewReader("")}
+
+	var err error
+	for i := 0; i < maxReplayKeys && err == nil; i++ {
+		err = kb.Replay("@a")
+	}
+	if err == nil {
+		t.Fatal("Expected self-replaying macro to stop")
+	}
+	if kb.Replaying() {
+		t.Error("Expected pending keys to be dropped")
+	}
+}
+
+func TestMacrosPersistInState(t *testing.T) {
+	app := NewApp()
+	app.StateFile = filepath.Join(t.TempDir(), "state")
+	app.Macros = map[string]string{"a": "x\rn", "b": "a\x1b[Bghi chú\n"}
+
+	if err := app.SaveState(20); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewApp()
+	loaded.StateFile = app.StateFile
+	if _, err := loaded.LoadState(); err != nil {
+		t.Fatal(err)
+	}
+	for register, keys := range app.Macros {
+		if loaded.Macros[register] != keys {
+			t.Errorf("Macro @%s: expected %q, got %q", register, keys, loaded.Macros[register])
+		}
+	}
+}
@@ -8,53 +8,331 @@
 //
 //	go build -o sre-learn .
 //	./sre-learn
+//	./sre-learn --profile alice
+//	./sre-learn --profile alice --review --since 2025-01-31
+//	./sre-learn --session interview-prep
+//	./sre-learn open sre-learn://learning-path-full.md#giai-doan-2-slo:120
+//	./sre-learn cat 12 | less -R
+//	./sre-learn note "xem lại PodDisruptionBudget"
+//	./sre-learn init --topics k8s,linux,observability --weeks 12
+//	./sre-learn update --from https://example.com/learning-path-full.md
+//	./sre-learn doctor --fix
+//	./sre-learn import --from obsidian ~/vaults/sre my-path.md
+//	./sre-learn plugins
+//	./sre-learn --batch ops.txt
+//	./sre-learn bundle -o sre.srelearn && ./sre-learn unbundle sre.srelearn ~/sre
+//	curl -s https://example.com/runbook.md | ./sre-learn -
+//	GIT_PAGER="./sre-learn --pager" git show HEAD:README.md
 //
 // The tool expects a file named "learning-path-full.md" in the current directory.
+// If it is missing, a file browser picks another document (directories,
+// markdown and AsciiDoc files, and the recently opened documents); "O"
+// opens the same browser to switch documents at runtime. Opened documents
+// are kept in ~/.config/sre-learn/recent.json, and "o" lists them with
+// their overall progress for a quick switch ("o Enter" goes back to the
+// previous one).
+// With --profile, progress and notes are kept in an isolated copy under
+// ~/.local/share/sre-learn/profiles/<name>, so several learners can share
+// the same curriculum file. Each curriculum gets its own copy there, also
+// when two of them in different directories have the same file name.
+//
+// With --session <name>, the open file, section, scroll position and pane
+// layout are saved under ~/.local/share/sre-learn/sessions and restored
+// the next time that session is used; "W" lists the sessions and switches
+// between them at runtime.
+//
+// "init" creates a new learning path: the default SRE curriculum, or with
+// --topics a plan generated from bundled topic templates (phases, weekly
+// sections with estimated hours and starter checklists). "init --template"
+// picks one of the bundled paths (sre, devops, platform, interview) or a
+// community template from the index at "template_index", whose SHA-256
+// checksum is verified after download; "init --list" shows them all.
+//
+// "update --from <url|path>" merges a newer version of the curriculum into
+// the annotated copy: new sections and items are added, check states,
+// personal tasks and notes are kept, and items that moved or were removed
+// upstream are asked about one by one (--yes keeps them all). It refuses
+// to run while a viewer has the document open.
+//
+// "import --from obsidian|notion <folder>" converts an Obsidian vault or an
+// unzipped Notion markdown export into one document: folders and pages
+// become nested sections, wikilinks and links between pages become
+// section links, callouts and toggle blocks become quotes and bold titles,
+// and checklists are kept.
+//
+// "--batch <file|->" applies operations without the TUI, one per line:
+// "goto <section>", "toggle|check|uncheck <item text>", "note <text>" and
+// "save". Each prints a JSON result line ({"line", "op", "ok", "section",
+// "item", "state", "error"}); it exits non-zero if any failed.
+//
+// "bundle" packs the document with its review sidecar, attachments and its
+// state, trash and quiz files into one .srelearn zip archive; "unbundle"
+// extracts it on another machine, pointing the state at the new location.
+//
+// With "-" the document is read from stdin and keys from the terminal, so
+// the viewer can end a shell pipeline. The piped document is read-only and
+// keeps no state; with --out <file> it is written to that (new) file and
+// opened for editing instead.
+//
+// "--pager [file]" only renders markdown (a file, or stdin) with less-style
+// keys: j/k, Space/b, d/u, g/G, / and ? to search, n/N and q. Nothing is
+// saved, so it can serve as GIT_PAGER or MANPAGER for markdown; when
+// stdout is not a terminal the input is copied unchanged.
+//
+// "doctor" lints the document: malformed checkboxes, duplicate titles,
+// unterminated code fences, broken note banners and header-level jumps
+// are listed with their line numbers, and "doctor --fix" repairs those
+// that can be repaired. It exits non-zero while problems remain.
+//
+// Files ending in .adoc (or .asciidoc, .asc) are read as AsciiDoc: "=="
+// titles are sections, "* [ ]" checklists (nested with "**", checked with
+// "[x]" or "[*]") toggle and count as in markdown, and listing blocks,
+// admonitions and links are shown like their markdown counterparts.
+//
+// Plugins are executables in ~/.config/sre-learn/plugins that read one
+// JSON request on stdin and answer with one JSON response on stdout
+// (see PluginRequest and PluginResponse). Asked to "describe" themselves,
+// they register key commands (listed under "Plugin" in the help), a
+// transformer of section content before rendering, and formats for
+// "export <format>"; "plugins" lists them.
+//
+// Preferences are read from ~/.config/sre-learn/config (key=value lines),
+// e.g. "scroll_step=5" to scroll five lines per j/k press or "book_mode=true"
+// to start in book mode. "run_timeout=2m" limits how long code blocks run.
+// Keys are remapped per action with "key.<action>=<keys>", e.g.
+// "key.next_section=l Right"; the help overlay (?) shows the active keys.
+// Characters written together are a chord ("key.goto=gn"): pressing the
+// first key of chords pops up the keys that can follow it (which-key); a
+// key that starts chords and is bound on its own runs when no key follows
+// within a second. Keys are named as in the help: "PageUp", "F1"-"F12",
+// "Shift+Tab" and modifiers such as "Ctrl+Up" or "Alt+x".
+// Prompts (goto, search, note comments, file paths) edit like readline:
+// ←/→, Home/End, Ctrl+w/Ctrl+u/Ctrl+k, ↑/↓ through the answers given
+// before and Tab to complete section titles or file paths.
+// Pasting is read whole (bracketed paste): a pasted block goes into the
+// prompt it is pasted in as one line, and pasted newlines are never run
+// as keys.
+// "reading_wpm=250" sets the reading speed behind the "~8 phút đọc"
+// estimates in the section header and TOC (default 200); the TOC footer
+// sums the time left for sections not opened yet.
+// "glyphs=ascii" draws [ ]/[x], # and - instead of ☐/☑, █ and ─ (by
+// default ASCII is used when TERM or the locale cannot show them).
+// Destructive changes (deleting notes or sections, a new cycle,
+// overwriting a section from $EDITOR) show their diff and are saved only
+// once confirmed; "z u" undoes the last of them, and again redoes it.
+// Undo reverts only the lines the change made, keeping edits made since;
+// if those lines were edited too, it is refused.
+// Edits are journaled next to the state until they are saved; after a
+// crash or a dropped connection the next start offers to replay them,
+// onto the content before the save when the crash cut a save short.
+// Deleted notes go to a per-document trash next to the state and can be
+// restored from the note menu for "trash_retention_days" (default 30).
+// "lookup_url=https://api.example.com/define?q={term}" adds an HTTP API
+// to the tldr/man lookup (I).
+// "glossary_underline=true" underlines the terms defined in the glossary.
+// Notes are written in a built-in editor (Ctrl+s saves, Esc cancels)
+// unless $EDITOR or $VISUAL is set; "inline_editor=true" uses the
+// built-in one anyway.
+// Documents from "large_file_mb" (default 4, 0 turns it off) open in a
+// large-file mode: an edit that keeps a section's line count changes the
+// lines in place instead of copying and re-joining the whole document.
+// Saving rewrites only the changed bytes when the size is kept (otherwise
+// from the first changed byte on), and the file is re-read to detect
+// outside changes only when its size or time changed. Parsing indexes
+// the headers with the byte offsets of their bodies, and a body is cut
+// from the document only when the section is read; the sections after
+// an edit keep their offsets. The document is held in memory once: its
+// lines and section bodies are slices of the loaded (or saved) content,
+// so bodies are cut from memory rather than read from disk again.
+// Every header level (# to ######) starts a section; "section_depth=4"
+// keeps ##### and ###### inline in their parent section instead.
+// "preview_writes=true" shows the diff of a note edit, deletion or "clean
+// all notes" before it is written.
+// "theme=gruvbox" (also solarized, nord) and "color.<name>=#rrggbb" or a
+// 256-color index recolor the palette; colors are downgraded to what the
+// terminal supports (COLORTERM/TERM, or "color_depth=8|256|truecolor").
+// "tts_engine=espeak-ng" picks the synthesizer used by read-aloud (R), or
+// "tts_url=<url>" posts the text to an HTTP TTS API and plays the audio.
+// "hook.<event>=<command>" runs a shell command on on-save,
+// on-section-complete, on-note-added or on-quit, with the event as JSON on
+// stdin and in SRE_LEARN_EVENT, SRE_LEARN_FILE, SRE_LEARN_SECTION,
+// SRE_LEARN_NOTE, SRE_LEARN_DONE and SRE_LEARN_TOTAL.
+// "encryption=true" encrypts notes (inline, after their banner), the
+// state, trash, quizzes and attachments with AES-256-GCM; the key comes
+// from "encryption_keyfile", SRE_LEARN_PASSPHRASE or a passphrase asked
+// at startup and checked against ~/.config/sre-learn/vault. Plaintext is
+// only kept in memory (attachments are opened from a temporary copy that
+// is removed on quit).
+//
+// While a document is open its <file>.lock holds the PID, host and start
+// time of the viewer. A second instance on the same document warns and
+// offers read-only mode, opening it anyway, or quitting; a lock whose
+// process is gone on this host is stale and replaced.
+//
+// The AI actions (C) use an OpenAI-compatible API configured with
+// OPENAI_API_KEY, OPENAI_BASE_URL (e.g. a local Ollama server) and
+// OPENAI_MODEL; nothing is sent unless an action is chosen and confirmed.
+//
+// With --accessible (or "accessible=true" in the config), the viewer prints
+// plain linear text for screen readers: no colors, glyphs or screen
+// clearing, and labelled lines such as "Checkbox 3 trên 7, chưa xong: ...".
+//
+// With --read-only, documents open read-only: toggling, notes, authoring
+// and saving the file are disabled and the header shows 🔒 CHỈ ĐỌC. A file
+// the user cannot write, a document piped on stdin (without --out) and one
+// another instance has open are read-only in the same way. Saving writes
+// the file in place, keeping its mode, owner and links; when the file
+// stops being writable mid-session, the edits stay in the journal and the
+// document turns read-only until the file can be written again.
+//
+// A document without headers opens as one section named after the file,
+// so it can still be read, searched and annotated; "a" in the structure
+// menu (E) inserts headers at its blank-line boundaries.
+//
+// A save that fails opens an error screen instead of a status message:
+// retry (e.g. after fixing the permissions), save a copy elsewhere, copy
+// the document to the clipboard, or Esc to go on with the edits kept in
+// the journal. A crash in a key action shows the same screen and the
+// session continues.
+//
+// What the file IO, state, journal, trash, GitHub sync and integrations
+// do is logged to ~/.cache/sre-learn/sre-learn.log (rotated at 1 MB,
+// three old files kept): saves, note deletions, recoveries and failures,
+// and with --verbose every read, write and request too.
+//
+// With --debug, render and parse times, the latency from a key read to the
+// screen showing it and memory stats are logged to
+// ~/.cache/sre-learn/debug.log; serve mode then also serves the runtime
+// profiles under /debug/pprof/.
+//
+// Search and the jump-anywhere overlay use an in-memory trigram index of
+// the section titles, contents and notes, built in the background when a
+// document opens and updated for the sections an edit changed. It holds
+// no lowercased copy of the document: matches are verified by lowercasing
+// the lines of the candidate sections.
+//
+// After an edit, the sections that follow it are shifted by the number of
+// lines added or removed rather than the whole document parsed again; an
+// edit that adds a header or opens a code block still parses it all. With
+// --debug-index, every such update is checked against a full parse and a
+// mismatch is reported (and repaired).
+//
+// With --review, a mentor opens the document read-only: checkboxes and notes
+// changed since the given date (default: the previous review) are highlighted
+// and "a" attaches review comments, stored in a separate <file>.review.json
+// sidecar and shown to the mentee in the matching sections.
 //
 // Keyboard shortcuts:
 //
 // Content navigation:
 //   - j/↓: Scroll down within section
 //   - k/↑: Scroll up within section
+//   - Ctrl+d/Ctrl+u: Scroll half a page down/up
+//   - Ctrl+f/Ctrl+b: Scroll a full page down/up
+//   - Home/End or </>: Jump to top/bottom of the section
 //
 // Section navigation:
 //   - n: Next section
 //   - p: Previous section
 //   - Enter: Next section
-//   - t: Open interactive TOC
-//   - g: Go to section by number
-//   - G: Go to last section
-//   - /: Search sections
+//   - t: Open interactive TOC (type to fuzzy-filter, Tab filters, Ctrl+r sorts)
+//   - gg: Go to the first section
+//   - gn: Go to section by number or title (Tab completes titles)
+//   - G/ge: Go to last section
+//   - /: Search sections, with a snippet of the first match; picking a
+//     result scrolls to the matching line. ↑/↓ recall past queries, Tab
+//     the saved searches; results can be saved by name and pinned to the
+//     TOC footer with their live counts (~/.config/sre-learn/searches.json)
+//   - Ctrl+p: Jump anywhere: fuzzy-find a section title, checkbox item or
+//     note line and jump to its line
+//   - 1-9: Jump to a breadcrumb ancestor (when no key follows within a
+//     second, since digits also start a count)
+//   - <count><key>: Repeat counts, e.g. 5n five sections ahead, 20j twenty
+//     lines down, 3x cycles the third checkbox, 12G goes to section 12
+//   - u/gu: Go to parent section
+//   - gd: Go into a child section; <count>gd goes to the count-th child.
+//     A section with children lists them below its content with their
+//     progress
+//   - ]/[: Next/previous sibling (section with the same parent; stops at
+//     the parent's last child instead of entering the next phase)
+//   - }/{: Next/previous phase (level-2 section)
+//   - :: Go to a line of the current section (120, or 43:120 for line 120
+//     of section 43); <count>: goes to line count. zn toggles the line
+//     numbers
+//   - go: Outline of the current section (headers inside the content,
+//     bold lines, long lists, code blocks), pick an entry to scroll to it
+//   - f/gf: Follow a link to another section ([text](#anchor), anchors
+//     are {#id} or the title slug) or jump to a section linking here.
+//     Enter stays "next section": reading straight through is the common
+//     case, and a page often shows several links to choose from
 //
 // Features:
-//   - x: Toggle checkbox
-//   - a: Add note
+//   - x: Toggle checkbox ([ ] -> [~] in progress -> [x]; "3-" marks item 3 won't do)
+//   - a: Add note (attach files or record voice memos into attachments/)
+//   - e: Edit the section's markdown in $EDITOR
+//   - E: New/split/move/delete section
+//   - y: Copy section, note or code block to clipboard, or with l a
+//     deep link to the current position (sre-learn://file#anchor:line)
+//     that "sre-learn open <link>" opens at the same section and line
+//   - !: Run a bash/sh code block of the section (output logged to notes)
+//   - m: Add a personal TODO (not counted in progress)
+//   - M: List all personal TODOs
+//   - v: Agenda of recurring items ("- [ ] chaos drill @every(7d)"): once
+//     checked they come back unchecked after the interval
+//   - D: Diff of unsaved changes; discard hunks before writing
+//   - i: Look up a term in the "## Glossary" section (term: definition lines)
+//   - I: Look up a command or term with tldr, man or the lookup_url API
+//   - C: AI summary of the section as a note, practice questions and the
+//     section quiz (OpenAI-compatible API, only sent after confirmation)
+//   - F: Flash review of the checked items in random order; items rated
+//     as forgotten queue their section for spaced re-study (🧠 in the TOC)
+//   - H: Completion history (with the results of earlier cycles)
+//   - N: Start a new study cycle: archive the progress, uncheck the path
+//     or one phase (when each checkbox was checked)
+//   - zz: Skip the section as not relevant (excluded from total progress)
+//   - Z/zl: List skipped sections and unskip them
+//   - B: Bookmark the section (🔖 in the TOC)
+//   - c: Summary cards: the "> 📌 Summary:" blocks of a phase as a deck of
+//     revision cards (shuffle, mark as known)
+//   - V: Select lines (j/k) and highlight them with an optional comment,
+//     kept in the state file; h lists the highlights for revision
 //   - s: Save file
 //
 // Display:
+//   - P: Presentation mode (one section per slide)
+//   - R: Read the section aloud (TTS), continuing with the next sections
+//   - b: Toggle book mode (scrolling flows across sections)
+//   - |: Toggle split view (TOC sidebar + content)
+//   - J/K: Scroll the TOC sidebar in split view
 //   - +: Increase visible lines
 //   - -: Decrease visible lines
-//   - ?: Show help
-//   - q: Quit
+//   - ?: Show help (type to filter)
+//   - L: Show the status message history
+//   - O: Open another document from the file browser
+//   - o: Switch to a recently opened document
+//   - W: List, switch or save named sessions
+//   - q<a-z>: Record a macro into a register (q again stops recording)
+//   - @<a-z>: Replay a macro (@@ repeats the last one)
+//   - qq/Q: Quit
 package main
 
 import (
 	"bufio"
-	_ "embed"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-//go:embed templates/default.md
-var defaultTemplate string
-
 // ANSI escape codes for terminal styling.
-// These constants provide color and formatting for terminal output.
+// These constants provide formatting for terminal output.
 const (
 	// Text formatting
 	Reset     = "\033[0m"
@@ -62,7 +340,13 @@ const (
 	Dim       = "\033[2m"
 	Italic    = "\033[3m"
 	Underline = "\033[4m"
+	Strike    = "\033[9m"
+)
 
+// ANSI colors. They default to the basic colors and are replaced by the
+// theme (see applyTheme), with 256-color or RGB values when the terminal
+// supports them.
+var (
 	// Foreground colors
 	Black   = "\033[30m"
 	Red     = "\033[31m"
@@ -85,16 +369,77 @@ const (
 )
 
 // Section represents a markdown section parsed from the document.
-// Each section corresponds to a header (# through ####) and its content.
+// Each section corresponds to a header (# through ######, see
+// section_depth) and its content.
 type Section struct {
 	// Title is the text after the # symbols
 	Title string
-	// Content is all text until the next header
-	Content string
+	// content is the body once set; until then the body is cut on demand
+	// from text, the document it was parsed from, between the byte
+	// offsets start and end (see Content)
+	content    string
+	text       string
+	start, end int
 	// Level indicates header depth (1 = #, 2 = ##, etc.)
 	Level int
 	// Line is the line number in the source file (0-indexed)
 	Line int
+	// ID is the header's {#custom-id} attribute, if any
+	ID string
+	// Setext marks a header written as an underlined (=== / ---) title
+	Setext bool
+	// AsciiDoc marks a section of an AsciiDoc document ("== Title")
+	AsciiDoc bool
+	// Implicit marks the section standing for a document without headers:
+	// titled after the file, with the whole file as its content and no
+	// header line (see AutoStructure)
+	Implicit bool
+}
+
+// Content returns the body of the section: all text until the next
+// header.
+func (s Section) Content() string {
+	if s.text != "" {
+		return s.text[s.start:s.end]
+	}
+	return s.content
+}
+
+// SetContent replaces the body of the section.
+func (s *Section) SetContent(content string) {
+	s.content, s.text, s.start, s.end = content, "", 0, 0
+}
+
+// cut makes the body the part of text from byte start up to end, sliced
+// only when the content is read, so parsing a document indexes its
+// headers without building the bodies.
+func (s *Section) cut(text string, start, end int) {
+	start = min(start, len(text))
+	s.content, s.text, s.start, s.end = "", text, start, max(start, min(end, len(text)))
+}
+
+// HeaderLines returns the markdown header of the section as written in
+// the file: "## Title {#id}", or the title and its underline for setext
+// headers. AsciiDoc sections are written "== Title".
+func (s Section) HeaderLines() []string {
+	if s.Implicit {
+		return nil
+	}
+	if s.AsciiDoc {
+		return []string{strings.Repeat("=", s.Level) + " " + s.Title}
+	}
+	title := s.Title
+	if s.ID != "" {
+		title += " {#" + s.ID + "}"
+	}
+	if s.Setext && s.Level <= 2 {
+		underline := "="
+		if s.Level == 2 {
+			underline = "-"
+		}
+		return []string{title, strings.Repeat(underline, max(3, visibleWidth(title)))}
+	}
+	return []string{strings.Repeat("#", s.Level) + " " + title}
 }
 
 // App holds the application state.
@@ -106,7 +451,9 @@ type App struct {
 	CurrentIdx int
 	// FilePath is the path to the markdown file
 	FilePath string
-	// FileContent is the raw file content
+	// FileContent is the raw file content; in the large-file mode it is
+	// the content as last read or written, FileLines holding the edits.
+	// Lines and section bodies parsed from it are slices of it, not copies
 	FileContent string
 	// FileLines is the file split by newlines
 	FileLines []string
@@ -114,112 +461,322 @@ type App struct {
 	TermWidth int
 	// TermHeight is the terminal height in rows
 	TermHeight int
-	// StateFile is the path to save/load state
+	// StateFile is the path to save/load state; when empty it is
+	// resolved per document by UseDocumentState
 	StateFile string
+	// stateBase is the state as last loaded or saved, which SaveState
+	// merges changes written by others since then against
+	stateBase *State
+	// Profile is the active profile name (empty for the default profile)
+	Profile string
+	// ReadOnly disables all edits to the document
+	ReadOnly bool
+	// ReadOnlyReason says why the document is read-only
+	ReadOnlyReason string
+	// ForceReadOnly opens every document read-only (--read-only)
+	ForceReadOnly bool
+	// Piped is set for a document read from stdin (see LoadPiped)
+	Piped bool
+	// ReviewMode enables mentor review (change highlighting, comments)
+	ReviewMode bool
+	// ReviewSince is the point in time changes are highlighted from
+	ReviewSince time.Time
+	// Reviews holds mentor review comments and snapshots
+	Reviews *ReviewStore
+	// Skipped holds the titles of sections marked as not relevant
+	Skipped map[string]bool
+	// Status holds the notifications shown on the status line
+	Status StatusLine
+	// Macros maps registers (a-z) to recorded key sequences
+	Macros map[string]string
+	// Session is the name of the active named session ("" for none)
+	Session string
+	// Bookmarks holds the titles of bookmarked sections
+	Bookmarks map[string]bool
+	// History is the append-only log of checkbox transitions
+	History []CheckboxEvent
+	// GitHubIssues maps checklist item keys to the issues created for them
+	GitHubIssues map[string]int
+	// Exported maps tracker-qualified item keys to the tickets created
+	// for them (see exportKey)
+	Exported map[string]string
+	// StudyTime is the time spent reading each section, in seconds
+	StudyTime map[string]float64
+	// Repetition is the spaced-repetition queue of sections to re-study,
+	// by title (see Rate)
+	Repetition map[string]Repetition
+	// Cycles are the archived results of earlier passes through the path
+	Cycles []Cycle
+	// Recurring maps the item keys of checked recurring items (@every) to
+	// when they revert to unchecked
+	Recurring map[string]time.Time
+	// SectionScroll remembers the scroll offset of each section by title
+	SectionScroll map[string]int
+	// Highlights are the line ranges marked with V, with their comments
+	Highlights []Highlight
+	// KnownSummaries holds the titles of the sections whose summary card
+	// was marked as known
+	KnownSummaries map[string]bool
+	// Undo holds the confirmed destructive changes that can be undone,
+	// most recent last (see runChange)
+	Undo []UndoEntry
+	// journal logs the edits not yet saved (nil when not journaling)
+	journal *Journal
+	// LargeFile is the performance mode of huge documents (see
+	// large_file_mb): edits splice FileLines in place and the content is
+	// joined only when saved
+	LargeFile bool
+	// diskContent is the file content as last read or written, used to
+	// detect edits made by another program (a second viewer, serve mode)
+	diskContent string
+	// diskStamp is the size and time of the file as last read or written
+	diskStamp diskStamp
+	// searchIndex answers SearchSections and the jump overlay; searchBuilt
+	// delivers it while it is built in the background
+	searchIndex *SearchIndex
+	searchBuilt chan *SearchIndex
 }
 
+// errReadOnly is returned when saving a document opened read-only.
+var errReadOnly = errors.New("document is read-only")
+
+// errModified is returned when saving a document another program changed.
+var errModified = errors.New("file was changed by another program")
+
 // NewApp creates a new App instance with default values.
 // It initializes terminal dimensions and sets the default file path.
 func NewApp() *App {
 	return &App{
 		FilePath:   "learning-path-full.md",
-		StateFile:  ".sre-learn-state",
 		TermWidth:  80,
 		TermHeight: 24,
+		Reviews:    &ReviewStore{},
 	}
 }
 
-// SaveState saves current reading position and settings to state file.
-func (a *App) SaveState(pageSize int) error {
-	content := fmt.Sprintf("current_section=%d\npage_size=%d\nfile_path=%s\n",
-		a.CurrentIdx, pageSize, a.FilePath)
-	return os.WriteFile(a.StateFile, []byte(content), 0o644)
-}
-
-// LoadState restores reading position and settings from state file.
-// Returns (pageSize, error). If file doesn't exist, returns defaults.
-func (a *App) LoadState() (int, error) {
-	data, err := os.ReadFile(a.StateFile)
-	if err != nil {
-		return 0, err // File doesn't exist, use defaults
-	}
-
-	pageSize := 0
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
-		key, value := parts[0], parts[1]
-		switch key {
-		case "current_section":
-			if idx, err := strconv.Atoi(value); err == nil {
-				a.CurrentIdx = idx
-			}
-		case "page_size":
-			if ps, err := strconv.Atoi(value); err == nil {
-				pageSize = ps
-			}
-		case "file_path":
-			// Only use saved file_path if current one is default
-			if a.FilePath == "learning-path-full.md" && value != "" {
-				a.FilePath = value
-			}
-		}
-	}
-
-	return pageSize, nil
-}
-
 // LoadFile reads the markdown file into memory.
 // It populates FileContent and FileLines fields.
 // Returns an error if the file cannot be read.
 func (a *App) LoadFile() error {
 	data, err := os.ReadFile(a.FilePath)
 	if err != nil {
+		logger.Error("read document", "path", a.FilePath, "err", err)
 		return fmt.Errorf("cannot read file %s: %w", a.FilePath, err)
 	}
-	a.FileContent = string(data)
+	// Without encryption the content is the disk content itself, so
+	// the document is held once
+	disk := string(data)
+	content, err := decodeDocument(disk)
+	if err != nil {
+		logger.Error("decrypt document", "path", a.FilePath, "err", err)
+		return fmt.Errorf("cannot decrypt notes of %s: %w", a.FilePath, err)
+	}
+	logger.Debug("read document", "path", a.FilePath, "bytes", len(data))
+	a.FileContent = content
 	a.FileLines = strings.Split(a.FileContent, "\n")
+	a.diskContent = disk
+	a.diskStamp, _ = stampFile(a.FilePath)
+	a.LargeFile = isLargeFile(len(data))
+	a.resetJournal()
+	return nil
+}
+
+// ChangedOnDisk reports whether the file was modified by another program
+// since it was last read or written. The file is read only when its size
+// or modification time changed.
+func (a *App) ChangedOnDisk() bool {
+	if a.diskContent == "" {
+		return false
+	}
+	stamp, err := stampFile(a.FilePath)
+	if err != nil || stamp.size == a.diskStamp.size && stamp.modTime.Equal(a.diskStamp.modTime) {
+		return false
+	}
+	data, err := os.ReadFile(a.FilePath)
+	if err != nil {
+		return false
+	}
+	if string(data) == a.diskContent {
+		// Touched, not changed
+		a.diskStamp = stamp
+		return false
+	}
+	return true
+}
+
+// Reload reads the file again, staying on the current section if it
+// still exists.
+func (a *App) Reload() error {
+	title := ""
+	if sec := a.GetCurrentSection(); sec != nil {
+		title = sec.Title
+	}
+	if err := a.LoadFile(); err != nil {
+		return err
+	}
+	a.ParseSections()
+	if idx := a.sectionIndex(title); idx >= 0 {
+		a.CurrentIdx = idx
+	}
+	a.CurrentIdx = max(0, min(a.CurrentIdx, len(a.Sections)-1))
 	return nil
 }
 
 // ParseSections extracts sections from the loaded markdown content.
-// A section starts with a header (# to ####) and includes all content
-// until the next header of any level.
+// A section starts with a header (# to ######, or down to the
+// section_depth level) and includes all content until the next header of
+// any level; deeper headers stay in the content. A document without headers is
+// one implicit section. Lines inside fenced code blocks
+// are never treated as headers (see ScanLines). AsciiDoc documents are
+// scanned with ScanAsciiDocLines.
 func (a *App) ParseSections() {
+	defer debugLog.Time("parse", "lines=%d", len(a.FileLines))()
 	a.Sections = []Section{}
 	var currentSection *Section
-	var contentLines []string
-
-	headerRegex := regexp.MustCompile(`^(#{1,4})\s+(.+)$`)
-
-	for i, line := range a.FileLines {
-		if matches := headerRegex.FindStringSubmatch(line); matches != nil {
+	// The headers are indexed with the byte offset of their body in the
+	// text, which is cut into bodies only when they are read
+	text := a.documentText()
+	contentOff, off := 0, 0
+
+	asciiDoc := a.IsAsciiDoc()
+	scanned := a.scanLines(a.FileLines)
+	depth := sectionDepth()
+	for i, ml := range scanned {
+		lineOff := off
+		off += len(a.FileLines[i]) + 1
+		if ml.Kind == LineSetextUnderline && i > 0 && scanned[i-1].Level <= depth {
+			continue // part of the header line above
+		}
+		if ml.Kind == LineHeader && ml.Level <= depth {
 			// Save previous section
 			if currentSection != nil {
-				currentSection.Content = strings.Join(contentLines, "\n")
+				currentSection.cut(text, contentOff, lineOff-1)
 				a.Sections = append(a.Sections, *currentSection)
 			}
 
 			// Start new section
 			currentSection = &Section{
-				Title: matches[2],
-				Level: len(matches[1]),
-				Line:  i,
+				Title:    ml.Text,
+				Level:    ml.Level,
+				Line:     i,
+				ID:       ml.ID,
+				Setext:   ml.Setext,
+				AsciiDoc: asciiDoc,
+			}
+			contentOff = off
+			if ml.Setext && i+1 < len(a.FileLines) {
+				contentOff += len(a.FileLines[i+1]) + 1 // after the underline
 			}
-			contentLines = []string{}
-		} else if currentSection != nil {
-			contentLines = append(contentLines, line)
 		}
 	}
 
 	// Save last section
 	if currentSection != nil {
-		currentSection.Content = strings.Join(contentLines, "\n")
+		currentSection.cut(text, contentOff, len(text))
 		a.Sections = append(a.Sections, *currentSection)
 	}
+
+	// Without headers the document is one implicit section, so it can
+	// still be read, searched and annotated
+	if len(a.Sections) == 0 {
+		for _, ml := range scanned {
+			if ml.Kind != LineBlank {
+				a.Sections = []Section{{Title: a.documentName(), Level: 1, AsciiDoc: asciiDoc, Implicit: true}}
+				a.Sections[0].cut(text, 0, len(text))
+				break
+			}
+		}
+	}
+}
+
+// documentText returns the text the section bodies are cut from:
+// FileContent while FileLines are still its lines (as loaded, or saved
+// in the large-file mode), so the bodies share the document's memory,
+// else FileLines joined.
+func (a *App) documentText() string {
+	off := 0
+	for i, line := range a.FileLines {
+		if i > 0 {
+			if off >= len(a.FileContent) || a.FileContent[off] != '\n' {
+				return strings.Join(a.FileLines, "\n")
+			}
+			off++
+		}
+		if !strings.HasPrefix(a.FileContent[off:], line) {
+			return strings.Join(a.FileLines, "\n")
+		}
+		off += len(line)
+	}
+	if off != len(a.FileContent) {
+		return strings.Join(a.FileLines, "\n")
+	}
+	return a.FileContent
+}
+
+// resliceDocument points FileLines and the section bodies into the
+// saved FileContent, so a large document is held once again instead of
+// the lines kept from the previous read, and the bodies set by edits,
+// pinning copies of it.
+func (a *App) resliceDocument() {
+	a.FileLines = strings.Split(a.FileContent, "\n")
+	line, off := 0, 0
+	offset := func(n int) int {
+		for ; line < n; line++ {
+			off += len(a.FileLines[line]) + 1
+		}
+		return off
+	}
+	for i := range a.Sections {
+		sec := &a.Sections[i]
+		end := len(a.FileLines)
+		if i+1 < len(a.Sections) {
+			end = a.Sections[i+1].Line
+		}
+		start := offset(sec.Line + len(sec.HeaderLines()))
+		sec.cut(a.FileContent, start, offset(end)-1)
+	}
+}
+
+// documentName is the title of the implicit section of a document
+// without headers: its file name without the extension.
+func (a *App) documentName() string {
+	name := strings.TrimSuffix(filepath.Base(a.FilePath), filepath.Ext(a.FilePath))
+	if a.FilePath == "" || a.Piped || name == "" {
+		return "Tài liệu"
+	}
+	return name
+}
+
+// FindSection resolves a section by 1-based number, by "#id" (the
+// header's {#id} attribute) or by title.
+// Titles match case-insensitively, preferring an exact match over the
+// first partial match. Returns -1 if nothing matches.
+func (a *App) FindSection(query string) int {
+	query = strings.TrimSpace(query)
+	if num, err := strconv.Atoi(query); err == nil {
+		if num >= 1 && num <= len(a.Sections) {
+			return num - 1
+		}
+		return -1
+	}
+	if id, ok := strings.CutPrefix(query, "#"); ok && id != "" {
+		if i := a.anchorIndex(id); i >= 0 {
+			return i
+		}
+	}
+
+	lower := strings.ToLower(query)
+	partial := -1
+	for i, sec := range a.Sections {
+		title := strings.ToLower(sec.Title)
+		if title == lower {
+			return i
+		}
+		if partial == -1 && lower != "" && strings.Contains(title, lower) {
+			partial = i
+		}
+	}
+	return partial
 }
 
 // GetCurrentSection returns the currently selected section.
@@ -261,36 +818,219 @@ func (a *App) GotoSection(idx int) bool {
 	return false
 }
 
+// Ancestors returns the indices of the parent chain of a section,
+// from the outermost (lowest level) ancestor down to the direct parent.
+func (a *App) Ancestors(idx int) []int {
+	if idx < 0 || idx >= len(a.Sections) {
+		return nil
+	}
+
+	chain := []int{}
+	level := a.Sections[idx].Level
+	for i := idx - 1; i >= 0 && level > 1; i-- {
+		if a.Sections[i].Level < level {
+			chain = append([]int{i}, chain...)
+			level = a.Sections[i].Level
+		}
+	}
+	return chain
+}
+
+// GotoAncestor jumps to the n-th (1-based) breadcrumb of the current section.
+// Returns true if such an ancestor exists.
+func (a *App) GotoAncestor(n int) bool {
+	ancestors := a.Ancestors(a.CurrentIdx)
+	if n < 1 || n > len(ancestors) {
+		return false
+	}
+	return a.GotoSection(ancestors[n-1])
+}
+
+// GotoParent moves to the direct parent of the current section.
+// Returns true if the section has a parent.
+func (a *App) GotoParent() bool {
+	ancestors := a.Ancestors(a.CurrentIdx)
+	if len(ancestors) == 0 {
+		return false
+	}
+	return a.GotoSection(ancestors[len(ancestors)-1])
+}
+
+// Siblings returns the sections sharing the parent of section idx,
+// idx included, in order: the parent's children, or the top-level
+// sections when idx has no parent.
+func (a *App) Siblings(idx int) []int {
+	if idx < 0 || idx >= len(a.Sections) {
+		return nil
+	}
+	if ancestors := a.Ancestors(idx); len(ancestors) > 0 {
+		return a.ChildSections(ancestors[len(ancestors)-1])
+	}
+	var roots []int
+	for i := 0; i < len(a.Sections); i = a.subtreeEnd(i) {
+		roots = append(roots, i)
+	}
+	return roots
+}
+
+// NextSibling moves to the next section with the same parent as the
+// current one, without leaving the parent's subtree.
+// Returns true if such a section exists.
+func (a *App) NextSibling() bool {
+	return a.gotoSibling(1)
+}
+
+// PrevSibling moves to the previous section with the same parent as the
+// current one. Returns true if such a section exists.
+func (a *App) PrevSibling() bool {
+	return a.gotoSibling(-1)
+}
+
+// gotoSibling moves step (+1 or -1) places among the siblings of the
+// current section.
+func (a *App) gotoSibling(step int) bool {
+	siblings := a.Siblings(a.CurrentIdx)
+	for i, idx := range siblings {
+		if idx == a.CurrentIdx && i+step >= 0 && i+step < len(siblings) {
+			return a.GotoSection(siblings[i+step])
+		}
+	}
+	return false
+}
+
+// NextPhase moves to the next level-2 section (learning phase).
+// Returns true if such a section exists.
+func (a *App) NextPhase() bool {
+	return a.gotoMatching(1, func(s Section) bool { return s.Level == 2 })
+}
+
+// PrevPhase moves to the previous level-2 section (learning phase).
+// Returns true if such a section exists.
+func (a *App) PrevPhase() bool {
+	return a.gotoMatching(-1, func(s Section) bool { return s.Level == 2 })
+}
+
+// gotoMatching walks from the current section in the given direction
+// (+1 or -1) and moves to the first section satisfying match.
+func (a *App) gotoMatching(step int, match func(Section) bool) bool {
+	for i := a.CurrentIdx + step; i >= 0 && i < len(a.Sections); i += step {
+		if match(a.Sections[i]) {
+			a.CurrentIdx = i
+			return true
+		}
+	}
+	return false
+}
+
 // SearchSections finds all sections matching the query string.
 // The search is case-insensitive and matches both title and content.
-// Returns a slice of indices for matching sections.
+// Returns a slice of indices for matching sections (see SearchIndex).
 func (a *App) SearchSections(query string) []int {
-	query = strings.ToLower(query)
-	matches := []int{}
+	return a.SearchIndex().Search(query)
+}
 
-	for i, sec := range a.Sections {
-		if strings.Contains(strings.ToLower(sec.Title), query) ||
-			strings.Contains(strings.ToLower(sec.Content), query) {
-			matches = append(matches, i)
-		}
+// Checkbox states, the character between the brackets.
+const (
+	stateOpen       = ' '
+	stateInProgress = '~'
+	stateDone       = 'x'
+	stateWontDo     = '-'
+)
+
+// checkboxRegex matches a task list item: "- [ ]", "- [~]" (in progress),
+// "- [x]" or "- [-]" (won't do), with "*" or "+" as list marker, "[X]"
+// for checked, indented under nested bullets or inside a quote, as
+// exported by Notion and Obsidian. AsciiDoc checklists nest with "**"
+// and may check with "[*]".
+var checkboxRegex = regexp.MustCompile(`^(?:\s*>)*\s*((?:[-+]|\*{1,5}) \[([ xX*~-])\])(?:\s|$)`)
+
+// isCheckbox reports whether line is a task list item.
+func isCheckbox(line string) bool {
+	return checkboxRegex.MatchString(line)
+}
+
+// checkboxState returns the state of a task list item ("[X]" reads as
+// stateDone), or 0 if line is no checkbox.
+func checkboxState(line string) byte {
+	m := checkboxRegex.FindStringSubmatch(line)
+	if m == nil {
+		return 0
 	}
+	if m[2] == "X" || m[2] == "*" {
+		return stateDone
+	}
+	return m[2][0]
+}
+
+// isChecked reports whether line is a checked task list item.
+func isChecked(line string) bool {
+	return checkboxState(line) == stateDone
+}
 
-	return matches
+// setCheckboxState returns line with its box set to state, keeping the
+// list marker. A line that is no checkbox is returned unchanged.
+func setCheckboxState(line string, state byte) string {
+	m := checkboxRegex.FindStringSubmatchIndex(line)
+	if m == nil {
+		return line
+	}
+	return line[:m[4]] + string(state) + line[m[5]:]
+}
+
+// setCheckbox returns line with its box checked or unchecked.
+func setCheckbox(line string, checked bool) string {
+	if checked {
+		return setCheckboxState(line, stateDone)
+	}
+	return setCheckboxState(line, stateOpen)
+}
+
+// nextCheckboxState is the state the toggle key moves to: open, in
+// progress, done and open again. Won't-do items reopen.
+func nextCheckboxState(state byte) byte {
+	switch state {
+	case stateOpen:
+		return stateInProgress
+	case stateInProgress:
+		return stateDone
+	}
+	return stateOpen
+}
+
+// checkboxGlyph returns the colored glyph drawn for a checkbox state.
+func checkboxGlyph(state byte) string {
+	switch state {
+	case stateInProgress:
+		return Yellow + glyphs.InProgress + Reset
+	case stateDone:
+		return Green + glyphs.Checked + Reset
+	case stateWontDo:
+		return Dim + glyphs.WontDo + Reset
+	}
+	return Red + glyphs.Unchecked + Reset
+}
+
+// checkboxStateNames name the states in the JSON API and the web view.
+var checkboxStateNames = map[byte]string{
+	stateOpen:       "todo",
+	stateInProgress: "in_progress",
+	stateDone:       "done",
+	stateWontDo:     "wont_do",
 }
 
 // GetCheckboxLines returns the line indices of all checkboxes in the current section.
-// A checkbox is either "- [ ]" (unchecked) or "- [x]" (checked).
+// A checkbox is a task list item (see isCheckbox), e.g. "- [ ]" or "* [x]".
 func (a *App) GetCheckboxLines() []int {
 	sec := a.GetCurrentSection()
 	if sec == nil {
 		return nil
 	}
 
-	lines := strings.Split(sec.Content, "\n")
+	lines := strings.Split(sec.Content(), "\n")
 	checkboxLines := []int{}
 
 	for i, line := range lines {
-		if strings.Contains(line, "- [ ]") || strings.Contains(line, "- [x]") {
+		if isCheckbox(line) {
 			checkboxLines = append(checkboxLines, i)
 		}
 	}
@@ -298,62 +1038,151 @@ func (a *App) GetCheckboxLines() []int {
 	return checkboxLines
 }
 
-// ToggleCheckbox toggles the checkbox at the given content line index.
+// ToggleCheckbox checks the checkbox at the given content line index, or
+// unchecks it if it is checked, and records the transition in the history.
 // Returns true if a checkbox was toggled, false if the line has no checkbox.
 func (a *App) ToggleCheckbox(contentLineIdx int) bool {
+	return a.changeCheckbox(contentLineIdx, func(state byte) byte {
+		if state == stateDone {
+			return stateOpen
+		}
+		return stateDone
+	})
+}
+
+// CycleCheckbox moves the checkbox at the given content line index to its
+// next state (see nextCheckboxState), as the toggle key does.
+func (a *App) CycleCheckbox(contentLineIdx int) bool {
+	return a.changeCheckbox(contentLineIdx, nextCheckboxState)
+}
+
+// changeCheckbox sets the checkbox at the given content line index to
+// the state next returns for its current state.
+func (a *App) changeCheckbox(contentLineIdx int, next func(byte) byte) bool {
+	sec := a.GetCurrentSection()
+	if sec == nil {
+		return false
+	}
+	lines := strings.Split(sec.Content(), "\n")
+	if contentLineIdx < 0 || contentLineIdx >= len(lines) || !isCheckbox(lines[contentLineIdx]) {
+		return false
+	}
+	return a.SetCheckboxState(contentLineIdx, next(checkboxState(lines[contentLineIdx])))
+}
+
+// SetCheckboxState sets the checkbox at the given content line index of
+// the current section to state, recording it in the history when it gets
+// checked or unchecked. Returns false if the line has no checkbox.
+func (a *App) SetCheckboxState(contentLineIdx int, state byte) bool {
 	sec := a.GetCurrentSection()
 	if sec == nil {
 		return false
 	}
 
-	lines := strings.Split(sec.Content, "\n")
+	lines := strings.Split(sec.Content(), "\n")
 	if contentLineIdx < 0 || contentLineIdx >= len(lines) {
 		return false
 	}
 
 	line := lines[contentLineIdx]
-	if strings.Contains(line, "- [ ]") {
-		lines[contentLineIdx] = strings.Replace(line, "- [ ]", "- [x]", 1)
-	} else if strings.Contains(line, "- [x]") {
-		lines[contentLineIdx] = strings.Replace(line, "- [x]", "- [ ]", 1)
-	} else {
+	if !isCheckbox(line) {
 		return false
 	}
+	lines[contentLineIdx] = setCheckboxState(line, state)
+	if was := isChecked(line); was != (state == stateDone) {
+		a.recordCheckbox(sec.Title, line, !was, time.Now())
+	}
 
-	a.Sections[a.CurrentIdx].Content = strings.Join(lines, "\n")
+	before := a.CountCheckboxes(a.CurrentIdx)
+	a.Sections[a.CurrentIdx].SetContent(strings.Join(lines, "\n"))
+	if after := a.CountCheckboxes(a.CurrentIdx); after.Done > 0 && after.Open+after.InProgress == 0 && before.Open+before.InProgress > 0 {
+		a.fireHook(hookSectionComplete, sec.Title, "")
+	}
 	return true
 }
 
 // AddNote appends a timestamped note to the current section.
-// The note is formatted as a blockquote with the current timestamp.
+// The note is formatted as a blockquote with the current timestamp; every
+// line of a multi-line note is quoted, so it stays one note.
 func (a *App) AddNote(note string) {
 	if note == "" {
 		return
 	}
 
+	lines := strings.Split(note, "\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = strings.TrimRight("> "+lines[i], " ")
+	}
 	timestamp := time.Now().Format("2006-01-02 15:04")
-	noteText := fmt.Sprintf("\n\n> **Ghi chú [%s]:** %s", timestamp, note)
-	a.Sections[a.CurrentIdx].Content += noteText
+	noteText := fmt.Sprintf("\n\n> **Ghi chú [%s]:** %s", timestamp, strings.Join(lines, "\n"))
+	a.Sections[a.CurrentIdx].SetContent(a.Sections[a.CurrentIdx].Content() + noteText)
+	a.fireHook(hookNoteAdded, a.Sections[a.CurrentIdx].Title, note)
+}
+
+// CheckboxCounts counts the checkboxes of a section by state.
+type CheckboxCounts struct {
+	Open, InProgress, Done, WontDo int
+}
+
+// CountCheckboxes counts the checkboxes of a section by state.
+// Personal tasks (see isPersonalTask) are not counted.
+func (a *App) CountCheckboxes(sectionIdx int) CheckboxCounts {
+	var c CheckboxCounts
+	if sectionIdx < 0 || sectionIdx >= len(a.Sections) {
+		return c
+	}
+	for _, line := range strings.Split(a.Sections[sectionIdx].Content(), "\n") {
+		if isPersonalTask(line) {
+			continue
+		}
+		switch checkboxState(line) {
+		case stateOpen:
+			c.Open++
+		case stateInProgress:
+			c.InProgress++
+		case stateDone:
+			c.Done++
+		case stateWontDo:
+			c.WontDo++
+		}
+	}
+	return c
 }
 
 // GetProgress calculates the completion progress for a section.
 // Returns (checked, total) where checked is the number of checked boxes
-// and total is the total number of checkboxes.
+// and total is the total number of checkboxes. Won't-do items ("- [-]")
+// are left out of both and in-progress items count as not checked.
+// Personal tasks (see isPersonalTask) are not counted.
 func (a *App) GetProgress(sectionIdx int) (checked, total int) {
-	if sectionIdx < 0 || sectionIdx >= len(a.Sections) {
-		return 0, 0
-	}
+	c := a.CountCheckboxes(sectionIdx)
+	return c.Done, c.Open + c.InProgress + c.Done
+}
 
-	content := a.Sections[sectionIdx].Content
-	checked = strings.Count(content, "- [x]")
-	total = checked + strings.Count(content, "- [ ]")
-	return
+// TotalCheckboxes counts the checkboxes of all sections except skipped
+// ones by state.
+func (a *App) TotalCheckboxes() CheckboxCounts {
+	var total CheckboxCounts
+	for i := range a.Sections {
+		if a.IsSkipped(i) {
+			continue
+		}
+		c := a.CountCheckboxes(i)
+		total.Open += c.Open
+		total.InProgress += c.InProgress
+		total.Done += c.Done
+		total.WontDo += c.WontDo
+	}
+	return total
 }
 
 // GetTotalProgress calculates the overall progress across all sections.
-// Returns (checked, total) aggregated from all sections.
+// Returns (checked, total) aggregated from all sections except skipped ones.
 func (a *App) GetTotalProgress() (checked, total int) {
 	for i := range a.Sections {
+		if a.IsSkipped(i) {
+			continue
+		}
 		c, t := a.GetProgress(i)
 		checked += c
 		total += t
@@ -362,7 +1191,9 @@ func (a *App) GetTotalProgress() (checked, total int) {
 }
 
 // UpdateFileSection updates the file lines to reflect changes in a section.
-// This syncs the in-memory section changes back to the file lines array.
+// This syncs the in-memory section changes back to the file lines array
+// and keeps the section index in step (see reindexSections), so callers
+// need not parse the document again.
 func (a *App) UpdateFileSection(idx int) {
 	if idx < 0 || idx >= len(a.Sections) {
 		return
@@ -377,69 +1208,123 @@ func (a *App) UpdateFileSection(idx int) {
 		endLine = a.Sections[idx+1].Line
 	}
 
-	// Rebuild section content
-	headerLine := strings.Repeat("#", sec.Level) + " " + sec.Title
-	newLines := []string{headerLine}
-	newLines = append(newLines, strings.Split(sec.Content, "\n")...)
-
-	// Replace in fileLines
-	newFileLines := append(a.FileLines[:startLine], newLines...)
-	newFileLines = append(newFileLines, a.FileLines[endLine:]...)
-	a.FileLines = newFileLines
+	// Rebuild section content, keeping the header style and {#id}
+	newLines := sec.HeaderLines()
+	newLines = append(newLines, strings.Split(sec.Content(), "\n")...)
 
-	// Update file content
-	a.FileContent = strings.Join(a.FileLines, "\n")
+	// A large file keeps its lines when the section's line count did not
+	// change (a toggle, a reworded line) and is joined only when saved
+	oldLines := endLine - startLine
+	fenceWasOpen := a.fenceLeftOpen(a.FileLines[startLine:endLine])
+	if a.journal != nil {
+		// Catch up on edits made elsewhere before this section's lines
+		a.journal.observe(a.FileLines)
+	}
+	switch {
+	case a.LargeFile && len(newLines) == oldLines:
+		copy(a.FileLines[startLine:endLine], newLines)
+	case a.LargeFile:
+		a.FileLines = append(a.FileLines[:startLine], append(newLines, a.FileLines[endLine:]...)...)
+	default:
+		// Replace in fileLines (copy, so a growing section doesn't
+		// overwrite the lines that follow it in the shared backing array)
+		newFileLines := make([]string, 0, len(a.FileLines)-oldLines+len(newLines))
+		newFileLines = append(newFileLines, a.FileLines[:startLine]...)
+		newFileLines = append(newFileLines, newLines...)
+		newFileLines = append(newFileLines, a.FileLines[endLine:]...)
+		a.FileLines = newFileLines
+		a.FileContent = strings.Join(a.FileLines, "\n")
+	}
+	if a.journal != nil {
+		a.journal.Splice(startLine, endLine, newLines, a.FileLines)
+	}
+	a.reindexSections(idx, newLines, len(newLines)-oldLines, fenceWasOpen)
 }
 
 // SaveFile writes the current file content to disk.
-// Returns an error if the file cannot be written.
+// Returns an error if the file cannot be written, is read-only or was
+// changed by another program since it was read (errModified); the
+// caller should Reload and apply its edit again. A file the user lost
+// permission to write (errUnwritable) makes the document read-only, its
+// edits kept in the journal.
 func (a *App) SaveFile() error {
-	a.FileContent = strings.Join(a.FileLines, "\n")
-	return os.WriteFile(a.FilePath, []byte(a.FileContent), 0o644)
+	if a.ReadOnly {
+		return errReadOnly
+	}
+	if a.ChangedOnDisk() {
+		logger.Warn("save refused, document changed on disk", "path", a.FilePath)
+		return errModified
+	}
+	// Journaled first, so a crash while writing loses nothing
+	a.syncJournal()
+	disk, err := encodeDocument(a.FileLines)
+	if err != nil {
+		return err
+	}
+	a.FileContent = disk
+	if vault != nil {
+		a.FileContent = strings.Join(a.FileLines, "\n")
+	}
+	if a.journal != nil {
+		// What the write overwrites is kept, so a torn write can be undone
+		if err := a.journal.Protect(a.diskContent, disk); err != nil {
+			logger.Error("protect document", "path", a.FilePath, "err", err)
+			return err
+		}
+	}
+	if err := writeDocument(a.FilePath, a.diskContent, disk); err != nil {
+		logger.Error("save document", "path", a.FilePath, "err", err)
+		// Keep the edits journaled and stop editing a file we cannot write
+		if errors.Is(err, errUnwritable) {
+			a.SetReadOnly(readOnlyPerm)
+		}
+		return err
+	}
+	logger.Info("saved document", "path", a.FilePath, "bytes", len(disk), "sections", len(a.Sections))
+	a.diskContent = disk
+	a.diskStamp, _ = stampFile(a.FilePath)
+	if a.LargeFile {
+		a.resliceDocument()
+	}
+	a.resetJournal()
+	a.fireHook(hookSave, "", "")
+	return nil
 }
 
+// numberedListRegex matches the "1. " marker of numbered list items.
+var numberedListRegex = regexp.MustCompile(`^(\s*)(\d+)\.\s`)
+
 // RenderLine converts a markdown line to ANSI-styled terminal output.
-// It handles checkboxes, bold, italic, code, bullets, and blockquotes.
+// It handles checkboxes, inline styles (bold, italic, code, ~~strike~~,
+// ==highlight==), bullets, and blockquotes.
+// Use RenderLines for blocks that may contain fenced code.
 func RenderLine(line string, termWidth int) string {
-	// Checkbox: - [ ] or - [x]
-	if strings.Contains(line, "- [ ]") {
-		line = strings.Replace(line, "- [ ]", Red+"☐"+Reset, 1)
-	}
-	if strings.Contains(line, "- [x]") {
-		line = strings.Replace(line, "- [x]", Green+"☑"+Reset, 1)
+	// Checkbox: - [ ], * [x], + [X], - [~], - [-] ...
+	if m := checkboxRegex.FindStringSubmatchIndex(line); m != nil {
+		line = line[:m[2]] + checkboxGlyph(checkboxState(line)) + line[m[3]:]
 	}
 
-	// Bold: **text**
-	boldRegex := regexp.MustCompile(`\*\*([^*]+)\*\*`)
-	line = boldRegex.ReplaceAllString(line, Bold+"$1"+Reset)
-
-	// Italic: *text* (but not **)
-	italicRegex := regexp.MustCompile(`(?:^|[^*])\*([^*]+)\*(?:[^*]|$)`)
-	line = italicRegex.ReplaceAllString(line, Italic+"$1"+Reset)
-
-	// Inline code: `code`
-	codeRegex := regexp.MustCompile("`([^`]+)`")
-	line = codeRegex.ReplaceAllString(line, BgBlack+Cyan+"$1"+Reset)
+	// Inline code, bold and italic (may nest), glossary terms underlined
+	line = renderInline(underlineTerms(renderRecurrence(line)))
 
 	// Bullet points (but not checkboxes)
 	if strings.HasPrefix(strings.TrimSpace(line), "- ") &&
-		!strings.Contains(line, "☐") &&
-		!strings.Contains(line, "☑") {
-		line = strings.Replace(line, "- ", Yellow+"• "+Reset, 1)
+		!strings.Contains(line, glyphs.Unchecked) &&
+		!strings.Contains(line, glyphs.Checked) {
+		line = strings.Replace(line, "- ", Yellow+glyphs.Bullet+" "+Reset, 1)
 	}
 
 	// Numbered lists
-	numRegex := regexp.MustCompile(`^(\s*)(\d+)\.\s`)
-	line = numRegex.ReplaceAllString(line, "$1"+Cyan+"$2."+Reset+" ")
+	line = numberedListRegex.ReplaceAllString(line, "$1"+Cyan+"$2."+Reset+" ")
 
 	// Quote blocks: > text
 	if strings.HasPrefix(strings.TrimSpace(line), ">") {
-		line = Dim + "│ " + strings.TrimPrefix(strings.TrimSpace(line), "> ") + Reset
+		line = Dim + glyphs.Vertical + " " + strings.TrimPrefix(strings.TrimSpace(line), "> ") + Reset
 	}
 
 	// Horizontal rule
 	if strings.TrimSpace(line) == "---" {
-		line = Dim + strings.Repeat("─", termWidth-4) + Reset
+		line = Dim + strings.Repeat(glyphs.Rule, termWidth-4) + Reset
 	}
 
 	// Table separator
@@ -455,8 +1340,16 @@ type Renderer struct {
 	App          *App
 	TermWidth    int
 	TermHeight   int
-	ScrollOffset int // Track scroll within section content
-	PageSize     int // Number of lines per page (user adjustable)
+	ScrollOffset int  // Track scroll within section content
+	PageSize     int  // Number of lines per page (user adjustable)
+	ScrollStep   int  // Lines scrolled per j/k press (configurable)
+	BookMode     bool // Scrolling flows across section boundaries
+	Accessible   bool // Plain linear output for screen readers
+	LineNumbers  bool // Show the line-number gutter in the content
+
+	SplitView        bool // Show the TOC sidebar next to the content
+	SidebarOffset    int  // First sidebar row; -1 re-centers on the current section
+	sidebarFollowIdx int  // Section the sidebar was last centered on
 }
 
 // NewRenderer creates a new Renderer for the given App.
@@ -467,11 +1360,13 @@ func NewRenderer(app *App) *Renderer {
 		pageSize = 15
 	}
 	return &Renderer{
-		App:          app,
-		TermWidth:    app.TermWidth,
-		TermHeight:   app.TermHeight,
-		ScrollOffset: 0,
-		PageSize:     pageSize,
+		App:           app,
+		TermWidth:     app.TermWidth,
+		TermHeight:    app.TermHeight,
+		ScrollOffset:  0,
+		PageSize:      pageSize,
+		ScrollStep:    DefaultConfig().ScrollStep,
+		SidebarOffset: -1,
 	}
 }
 
@@ -480,34 +1375,97 @@ func (r *Renderer) ResetScroll() {
 	r.ScrollOffset = 0
 }
 
-// ScrollDown scrolls content down.
+// RestoreScroll scrolls back to where the current section was left.
+func (r *Renderer) RestoreScroll() {
+	if sec := r.App.GetCurrentSection(); sec != nil {
+		r.ScrollOffset = max(0, min(r.App.SectionScroll[sec.Title], r.maxScrollOffset()))
+	}
+}
+
+// rememberScroll records the scroll offset of the current section.
+func (r *Renderer) rememberScroll(title string) {
+	if r.ScrollOffset == 0 {
+		delete(r.App.SectionScroll, title)
+		return
+	}
+	if r.App.SectionScroll == nil {
+		r.App.SectionScroll = make(map[string]int)
+	}
+	r.App.SectionScroll[title] = r.ScrollOffset
+}
+
+// ScrollDown scrolls content down by ScrollStep lines.
 // Returns true if scrolled, false if already at bottom.
 func (r *Renderer) ScrollDown() bool {
-	sec := r.App.GetCurrentSection()
-	if sec == nil {
+	return r.Scroll(r.ScrollStep)
+}
+
+// ScrollUp scrolls content up by ScrollStep lines.
+// Returns true if scrolled, false if already at top.
+func (r *Renderer) ScrollUp() bool {
+	return r.Scroll(-r.ScrollStep)
+}
+
+// Scroll scrolls content by delta lines. In book mode, scrolling past the
+// end of a section continues at the top of the next one, and scrolling up
+// past the top continues at the bottom of the previous one.
+// Returns true if the view changed.
+func (r *Renderer) Scroll(delta int) bool {
+	if r.ScrollBy(delta) {
+		return true
+	}
+	if !r.BookMode {
 		return false
 	}
 
-	lines := strings.Split(sec.Content, "\n")
-
-	if r.ScrollOffset+r.PageSize < len(lines) {
-		r.ScrollOffset += 3 // Scroll by 3 lines for smoother navigation
+	if delta > 0 && r.App.NextSection() {
+		r.ScrollToTop()
+		return true
+	}
+	if delta < 0 && r.App.PrevSection() {
+		r.ScrollToBottom()
 		return true
 	}
 	return false
 }
 
-// ScrollUp scrolls content up.
-// Returns true if scrolled, false if already at top.
-func (r *Renderer) ScrollUp() bool {
-	if r.ScrollOffset > 0 {
-		r.ScrollOffset -= 3 // Scroll by 3 lines
-		if r.ScrollOffset < 0 {
-			r.ScrollOffset = 0
-		}
-		return true
+// ScrollBy scrolls content by delta lines (negative scrolls up),
+// clamped to the bounds of the current section.
+// Returns true if the scroll position changed.
+func (r *Renderer) ScrollBy(delta int) bool {
+	old := r.ScrollOffset
+	r.ScrollOffset = max(0, min(r.ScrollOffset+delta, r.maxScrollOffset()))
+	return r.ScrollOffset != old
+}
+
+// ScrollToTop jumps to the first line of the current section.
+func (r *Renderer) ScrollToTop() {
+	r.ScrollOffset = 0
+}
+
+// ScrollToBottom jumps so the last page of the current section is shown.
+func (r *Renderer) ScrollToBottom() {
+	r.ScrollOffset = r.maxScrollOffset()
+}
+
+// ScrollToLine scrolls so line of the current section's content is at
+// the top, or as near as the last page allows.
+func (r *Renderer) ScrollToLine(line int) {
+	r.ScrollOffset = max(0, min(line, r.maxScrollOffset()))
+}
+
+// maxScrollOffset returns the largest useful scroll offset for the
+// current section, i.e. the one showing its last page.
+func (r *Renderer) maxScrollOffset() int {
+	sec := r.App.GetCurrentSection()
+	if sec == nil {
+		return 0
 	}
-	return false
+	lines := len(RenderLines(r.App.contentLines(sec.Content()), r.TermWidth)) + len(r.App.childSummaryLines(r.App.CurrentIdx))
+	if r.Accessible {
+		lines = len(AccessibleLines(sec.Content())) + len(r.App.accessibleChildSummary(r.App.CurrentIdx))
+	}
+	return max(0, lines-r.PageSize)
 }
 
 // AdjustPageSize changes the number of visible lines.
@@ -527,7 +1485,10 @@ func ClearScreen() {
 
 // Render displays the current section with header and footer.
 func (r *Renderer) Render() {
-	ClearScreen()
+	defer debugLog.Time("render", "section=%d", r.App.CurrentIdx+1)()
+	if !r.Accessible {
+		ClearScreen()
+	}
 
 	if len(r.App.Sections) == 0 {
 		fmt.Println("Không có sections.")
@@ -539,8 +1500,22 @@ func (r *Renderer) Render() {
 		return
 	}
 
+	if !r.BookMode {
+		r.rememberScroll(sec.Title)
+	}
+	if config.GlossaryUnderline {
+		r.App.refreshGlossaryTerms()
+	}
+	if r.Accessible {
+		r.renderAccessible(sec)
+		return
+	}
 	r.printHeader(sec)
-	r.printContent(sec.Content)
+	if r.splitActive() {
+		r.printSplitContent(sec.Content())
+	} else {
+		r.printContent(sec.Content())
+	}
 	r.printFooter()
 }
 
@@ -550,70 +1525,144 @@ func (r *Renderer) printHeader(sec *Section) {
 	progress := float64(r.App.CurrentIdx+1) / float64(len(r.App.Sections)) * 100
 	barWidth := 20
 	filled := int(float64(barWidth) * float64(r.App.CurrentIdx+1) / float64(len(r.App.Sections)))
-	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+	bar := strings.Repeat(glyphs.BarFull, filled) + strings.Repeat(glyphs.BarEmpty, barWidth-filled)
 
-	fmt.Printf("%s%s", BgBlue+White+Bold, strings.Repeat(" ", r.TermWidth))
-	fmt.Print("\r")
-	fmt.Printf(" 📖 SRE Learning Path  [%s] %.0f%%  (%d/%d)", bar, progress, r.App.CurrentIdx+1, len(r.App.Sections))
-	fmt.Printf("%s\n", Reset)
+	header := fmt.Sprintf(" 📖 SRE Learning Path  [%s] %.0f%%  (%d/%d)", bar, progress, r.App.CurrentIdx+1, len(r.App.Sections))
+	if r.App.Profile != "" {
+		header += fmt.Sprintf("  👤 %s", r.App.Profile)
+	}
+	if r.App.ReviewMode {
+		header += "  🔍 REVIEW"
+	} else if r.App.ReadOnly {
+		header += "  🔒 CHỈ ĐỌC (" + r.App.ReadOnlyReason + ")"
+	}
+	if r.BookMode {
+		header += "  📜 book"
+	}
+	if register := keyboard.Recording(); register != "" {
+		header += "  ⏺ REC @" + register
+	}
+	fmt.Println(barLine(BgBlue+White+Bold, header, r.TermWidth))
 
 	// Section title
 	levelColors := []string{White, Cyan, Yellow, Green}
 	levelColor := levelColors[min(sec.Level-1, 3)]
 	prefix := strings.Repeat("  ", sec.Level-1)
-	fmt.Printf("\n%s%s%s %s%s\n", prefix, Bold+levelColor, strings.Repeat("#", sec.Level), sec.Title, Reset)
-	fmt.Println(Dim + strings.Repeat("─", r.TermWidth-4) + Reset)
+	fmt.Println()
+	if crumbs := r.breadcrumb(); crumbs != "" {
+		fmt.Println(crumbs)
+	}
+	title := fmt.Sprintf("%s%s%s %s%s", prefix, Bold+levelColor, strings.Repeat("#", sec.Level), sec.Title, Reset)
+	if stats := r.App.SectionStats(r.App.CurrentIdx, readingWPM()); stats.Words > 0 {
+		title += Dim + "  " + statsLabel(stats) + Reset
+	}
+	fmt.Println(truncateVisible(title, r.TermWidth))
+
+	// Mentor review comments
+	for _, c := range r.App.Reviews.CommentsFor(sec.Title) {
+		fmt.Printf("%s💬 %s (%s):%s %s\n", Magenta+Bold, c.Author, c.Created.Format("2006-01-02"), Reset, c.Text)
+	}
+
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, r.TermWidth-4) + Reset)
+}
+
+// breadcrumb renders the ancestor chain of the current section,
+// numbered so each crumb can be reached with the matching digit key.
+func (r *Renderer) breadcrumb() string {
+	ancestors := r.App.Ancestors(r.App.CurrentIdx)
+	if len(ancestors) == 0 {
+		return ""
+	}
+
+	crumbs := make([]string, len(ancestors))
+	for i, idx := range ancestors {
+		crumbs[i] = fmt.Sprintf("%s%d%s %s", Cyan, i+1, Reset+Dim, r.App.Sections[idx].Title)
+	}
+	line := Dim + strings.Join(crumbs, " "+glyphs.Crumb+" ") + " " + glyphs.Crumb + Reset
+	return truncateVisible(line, r.TermWidth)
 }
 
 // printContent renders the section content with markdown styling.
 func (r *Renderer) printContent(content string) {
-	lines := strings.Split(content, "\n")
+	rendered, startIdx, endIdx := r.visibleContent(content, r.TermWidth)
 
-	rendered := make([]string, len(lines))
-	for i, line := range lines {
-		rendered[i] = RenderLine(line, r.TermWidth)
+	for _, line := range rendered[startIdx:endIdx] {
+		fmt.Println(line)
+	}
+
+	r.printPosition(len(rendered), startIdx, endIdx)
+}
+
+// visibleContent renders all content lines and returns them together with
+// the [startIdx, endIdx) window selected by the scroll offset and page size.
+func (r *Renderer) visibleContent(content string, width int) (rendered []string, startIdx, endIdx int) {
+	lines := r.App.contentLines(content)
+
+	// Highlight changes since the review date
+	var changed map[int]bool
+	if r.App.ReviewMode {
+		baseline := r.App.Reviews.BaselineAt(r.App.ReviewSince)
+		changed = r.App.ChangedLines(r.App.CurrentIdx, r.App.ReviewSince, baseline)
+	}
+
+	highlighted := r.App.HighlightedLines(r.App.CurrentIdx)
+
+	if r.LineNumbers {
+		width -= gutterWidth(len(lines))
+	}
+	rendered = RenderLines(lines, width)
+	for i := range rendered {
+		if comment, ok := highlighted[i]; ok {
+			rendered[i] = highlightLine(rendered[i], comment)
+		}
+		if changed[i] {
+			rendered[i] = Yellow + Bold + glyphs.Vertical + " " + Reset + rendered[i]
+		}
+	}
+	rendered = append(rendered, r.App.childSummaryLines(r.App.CurrentIdx)...)
+	if r.LineNumbers {
+		rendered = addGutter(rendered, len(lines))
 	}
 
 	// Apply scroll offset
-	startIdx := r.ScrollOffset
+	startIdx = r.ScrollOffset
 	if startIdx >= len(rendered) {
 		startIdx = 0
 		r.ScrollOffset = 0
 	}
 
-	endIdx := min(startIdx+r.PageSize, len(rendered))
-	displayLines := rendered[startIdx:endIdx]
+	endIdx = min(startIdx+r.PageSize, len(rendered))
+	return rendered, startIdx, endIdx
+}
 
-	for _, line := range displayLines {
-		fmt.Println(line)
+// printPosition shows the scroll position indicator for long content.
+func (r *Renderer) printPosition(total, startIdx, endIdx int) {
+	if total <= r.PageSize {
+		return
 	}
 
-	// Show position indicator
-	if len(rendered) > r.PageSize {
-		above := startIdx
-		below := len(rendered) - endIdx
-
-		posInfo := fmt.Sprintf("[%d-%d/%d]", startIdx+1, endIdx, len(rendered))
-		scrollHint := ""
+	above := startIdx
+	below := total - endIdx
 
-		if above > 0 && below > 0 {
-			scrollHint = fmt.Sprintf("↑%d ↓%d", above, below)
-		} else if above > 0 {
-			scrollHint = fmt.Sprintf("↑%d (k lên đầu)", above)
-		} else if below > 0 {
-			scrollHint = fmt.Sprintf("↓%d (j xem tiếp)", below)
-		}
+	posInfo := fmt.Sprintf("[%d-%d/%d]", startIdx+1, endIdx, total)
+	scrollHint := ""
 
-		fmt.Printf("\n%s%s %s  [%d dòng/trang, +/- chỉnh]%s", Dim, posInfo, scrollHint, r.PageSize, Reset)
+	if above > 0 && below > 0 {
+		scrollHint = fmt.Sprintf("%s%d %s%d", glyphs.Up, above, glyphs.Down, below)
+	} else if above > 0 {
+		scrollHint = fmt.Sprintf("%s%d (k lên đầu)", glyphs.Up, above)
+	} else if below > 0 {
+		scrollHint = fmt.Sprintf("%s%d (j xem tiếp)", glyphs.Down, below)
 	}
+
+	fmt.Printf("\n%s%s %s  [%d dòng/trang, +/- chỉnh]%s", Dim, posInfo, scrollHint, r.PageSize, Reset)
 }
 
-// printFooter renders the bottom navigation bar.
+// printFooter renders the status line and the bottom navigation bar.
 func (r *Renderer) printFooter() {
 	fmt.Println()
-	fmt.Printf("%s%s", BgBlack+White, strings.Repeat(" ", r.TermWidth))
-	fmt.Print("\r")
-	fmt.Printf(" %sj%s/%sk%s scroll %sn%s/%sp%s section %st%s toc %sx%s tick %sa%s note %s?%s help %sq%s quit",
+	printStatusLine()
+	footer := fmt.Sprintf(" %sj%s/%sk%s scroll %sn%s/%sp%s section %st%s toc %sx%s tick %sa%s note %s?%s help %sq%s quit",
 		Bold+Cyan, Reset+BgBlack+White,
 		Bold+Cyan, Reset+BgBlack+White,
 		Bold+Cyan, Reset+BgBlack+White,
@@ -623,11 +1672,14 @@ func (r *Renderer) printFooter() {
 		Bold+Cyan, Reset+BgBlack+White,
 		Bold+Cyan, Reset+BgBlack+White,
 		Bold+Cyan, Reset+BgBlack+White)
-	fmt.Printf("%s\n", Reset)
+	fmt.Println(barLine(BgBlack+White, footer, r.TermWidth))
 }
 
 // Terminal provides terminal manipulation utilities.
-type Terminal struct{}
+type Terminal struct {
+	// raw is whether raw mode is enabled
+	raw bool
+}
 
 // GetSize returns the terminal dimensions (width, height).
 // Falls back to 80x24 if unable to determine.
@@ -644,14 +1696,40 @@ func (t *Terminal) GetSize() (width, height int) {
 
 // SetRawMode enables or disables raw terminal mode.
 // In raw mode, input is read character by character without echo.
+// Entering raw mode turns bracketed paste on again, since the programs
+// run in between (the $EDITOR) may have turned it off.
 func (t *Terminal) SetRawMode(enable bool) {
+	t.raw = enable
 	if enable {
 		exec.Command("stty", "-F", "/dev/tty", "cbreak", "min", "1", "-echo").Run()
+		t.SetBracketedPaste(true)
 	} else {
 		exec.Command("stty", "-F", "/dev/tty", "-cbreak", "echo").Run()
 	}
 }
 
+// SetBracketedPaste makes the terminal mark pasted text (see pasteStart),
+// so a paste is read as a whole: typed into a prompt rather than run as
+// keys.
+func (t *Terminal) SetBracketedPaste(enable bool) {
+	if enable {
+		fmt.Print("\033[?2004h")
+	} else {
+		fmt.Print("\033[?2004l")
+	}
+}
+
+// SetReadTimeout makes key reads return after 0.2s without input when
+// enabled, so a mode can poll the keyboard while work runs in the
+// background.
+func (t *Terminal) SetReadTimeout(enable bool) {
+	if enable {
+		exec.Command("stty", "-F", "/dev/tty", "min", "0", "time", "2").Run()
+	} else {
+		exec.Command("stty", "-F", "/dev/tty", "min", "1", "time", "0").Run()
+	}
+}
+
 // min returns the smaller of two integers.
 func min(a, b int) int {
 	if a < b {
@@ -662,6 +1740,7 @@ func min(a, b int) int {
 
 // Global instances for main program
 var (
+	config   Config
 	app      *App
 	renderer *Renderer
 	terminal *Terminal
@@ -669,27 +1748,193 @@ var (
 )
 
 func main() {
+	profile := flag.String("profile", "", "use an isolated profile for progress and notes")
+	reviewMode := flag.Bool("review", false, "open the document read-only for mentor review")
+	readOnly := flag.Bool("read-only", false, "open documents read-only: no checkbox, note or file changes")
+	since := flag.String("since", "", "highlight changes since this date (YYYY-MM-DD); requires --review")
+	sessionName := flag.String("session", "", "resume (or start) a named session: file, position and layout")
+	batch := flag.String("batch", "", "apply operations from a file (- for stdin) without the TUI, printing a JSON result per operation")
+	accessible := flag.Bool("accessible", false, "plain linear output for screen readers (no colors, glyphs or screen redraws)")
+	out := flag.String("out", "", "with -, write the piped document to this file and open it for editing")
+	flag.BoolVar(&debugIndex, "debug-index", false, "check the section index against a full parse after every edit")
+	verbose := flag.Bool("verbose", false, "log every file read, write and request to the log file in the cache dir, not only changes and failures")
+	debug := flag.Bool("debug", false, "log render, parse and input timings and memory stats to the cache dir; serve mode adds /debug/pprof/")
+	pager := flag.Bool("pager", false, "view a markdown file (or stdin) like less, without state or progress; usable as GIT_PAGER")
+	flag.Usage = printUsage
+	flag.Parse()
+	if *since != "" && !*reviewMode {
+		fmt.Fprintln(os.Stderr, "❌ Lỗi: --since requires --review")
+		os.Exit(1)
+	}
+
+	if path, err := logPath(); err == nil {
+		openLog(path, *verbose)
+	}
+
+	if *debug {
+		path, err := debugLogPath()
+		if err == nil {
+			debugLog, err = OpenDebugLog(path)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+		debugLog.Mem()
+	}
+
+	// "sre-learn -" views a document piped on stdin; keys come from the tty
+	var piped *string
+	if !*pager && flag.NArg() == 1 && flag.Arg(0) == stdinPath {
+		content, err := readStdinDocument(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+		if *sessionName != "" || (*out == "" && *profile != "") {
+			fmt.Fprintln(os.Stderr, "❌ Lỗi: - cannot be used with --session, or with --profile without --out")
+			os.Exit(1)
+		}
+		piped = &content
+	}
+
+	config = DefaultConfig()
+	if path, err := configPath(); err == nil {
+		if config, err = LoadConfig(path); err != nil {
+			fmt.Printf("❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	keymap, _ = config.Keymap()
+	useDetectedGlyphs(config.Glyphs)
+	applyTheme(themes[config.Theme], config.Colors, detectColorDepth(config.ColorDepth, os.Getenv))
+
 	app = NewApp()
+	app.ForceReadOnly = *readOnly
 	terminal = &Terminal{}
 
 	// Get terminal size
 	app.TermWidth, app.TermHeight = terminal.GetSize()
 
-	// Check if file exists, prompt if not
-	if !fileExists(app.FilePath) {
-		handleFileNotFound()
+	// The pager only renders: no state, progress, notes or plugins
+	if *pager {
+		if err := runPager(flag.Args(), os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	// Load file
-	if err := app.LoadFile(); err != nil {
+	// Encrypted notes and state need the key before anything is read
+	if err := setupEncryption(config); err != nil {
 		fmt.Printf("❌ Lỗi: %v\n", err)
 		os.Exit(1)
 	}
-	app.ParseSections()
+
+	// Plugins add key commands, content transformers and export formats
+	for _, err := range loadPlugins() {
+		notify(SeverityWarning, "%v", err)
+	}
+	keymap = append(keymap, pluginKeyBindings(plugins, keymap)...)
+
+	// "sre-learn open <link>" starts the viewer at a shared deep link
+	var openLink *DeepLink
+	if flag.NArg() > 0 && flag.Arg(0) == "open" && piped == nil {
+		link, err := parseOpenArgs(flag.Args()[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+		if link.File != "" {
+			app.FilePath = resolveDeepLinkFile(link.File)
+		}
+		openLink = &link
+	}
+
+	// Non-interactive subcommands (e.g. "sre-learn cat 3")
+	if flag.NArg() > 0 && piped == nil && openLink == nil {
+		if err := runCommand(flag.Args(), *profile, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *batch != "" {
+		if err := runBatch(*batch, *profile, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// A saved session decides which document (and profile copy) to open
+	var session *Session
+	if *sessionName != "" {
+		s, err := LoadSession(*sessionName)
+		switch {
+		case err == nil:
+			session = &s
+			app.FilePath, app.StateFile, app.Profile = s.FilePath, s.StateFile, s.Profile
+			*profile = ""
+		case !errors.Is(err, os.ErrNotExist):
+			fmt.Printf("❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if piped != nil && *out != "" {
+		if err := writePiped(*out, *piped); err != nil {
+			fmt.Printf("❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+		app.FilePath, piped = *out, nil
+	}
+
+	if piped != nil {
+		if err := app.LoadPiped(*piped); err != nil {
+			fmt.Printf("❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		// Check if file exists, prompt if not
+		if !fileExists(app.FilePath) {
+			handleFileNotFound()
+		}
+
+		if err := openDocument(*profile); err != nil {
+			fmt.Printf("❌ Lỗi: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *reviewMode {
+		app.ReviewMode = true
+		app.SetReadOnly(readOnlyReview)
+		if *since != "" {
+			t, err := time.ParseInLocation("2006-01-02", *since, time.Local)
+			if err != nil {
+				fmt.Printf("❌ Ngày không hợp lệ: %s\n", *since)
+				os.Exit(1)
+			}
+			app.ReviewSince = t
+		} else if last := app.Reviews.BaselineAt(time.Now()); last != nil {
+			app.ReviewSince = last.Taken
+		}
+	}
 
 	// Create renderer with default settings
 	renderer = NewRenderer(app)
-	reader = bufio.NewReader(os.Stdin)
+	renderer.ScrollStep = config.ScrollStep
+	renderer.BookMode = config.BookMode
+	renderer.Accessible = *accessible || config.Accessible
+	reader = bufio.NewReader(keyboard)
+
+	// Warn when another instance already has the document open
+	if err := lockDocument(reader); err != nil {
+		fmt.Printf("❌ Lỗi: %v\n", err)
+		os.Exit(1)
+	}
+	rememberDocument()
 
 	// Load saved state (position, page size)
 	if savedPageSize, err := app.LoadState(); err == nil {
@@ -700,21 +1945,108 @@ func main() {
 		if app.CurrentIdx >= len(app.Sections) {
 			app.CurrentIdx = 0
 		}
+		renderer.RestoreScroll()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		notify(SeverityWarning, "Không đọc được trạng thái, dùng mặc định: %v", err)
+	}
+	if len(app.Sections) == 1 && app.Sections[0].Implicit && !app.ReadOnly {
+		notify(SeverityInfo, "Tài liệu chưa có header: E rồi a để tự chia section")
+	}
+	// Recurring items whose interval has passed come back unchecked
+	if !app.ReadOnly {
+		if n := app.ReopenRecurring(time.Now()); n > 0 {
+			if saveDocument() {
+				notify(SeverityInfo, "%d việc lặp lại đến hạn (v để xem agenda)", n)
+			}
+		}
+	}
+	if session != nil {
+		ApplySession(*session, app, renderer)
+	} else if *sessionName != "" {
+		app.Session = *sessionName
+		if err := saveSession(); err != nil {
+			notify(SeverityWarning, "Không lưu được phiên %s: %v", *sessionName, err)
+		}
+	}
+	if openLink != nil {
+		if err := renderer.OpenDeepLink(*openLink); err != nil {
+			notify(SeverityWarning, "Liên kết %s: %v", openLink, err)
+		}
 	}
 
 	// Enable raw mode for keyboard input
 	terminal.SetRawMode(true)
 	defer func() {
 		terminal.SetRawMode(false)
+		terminal.SetBracketedPaste(false)
 		// Save state on exit
 		app.SaveState(renderer.PageSize)
+		saveSession()
+		docLock.Release()
+		debugLog.Mem()
 	}()
 
-	// Main loop
+	// Main loop; replayed macro keys run without redrawing in between
+	lastSave, lastMem := time.Now(), time.Now()
 	for {
-		renderer.Render()
-		handleInput()
+		if !keyboard.Replaying() {
+			// Pick up edits made from serve mode or another viewer
+			if app.ChangedOnDisk() {
+				if err := app.Reload(); err == nil {
+					logger.Info("reloaded document changed on disk", "path", app.FilePath)
+					notify(SeverityInfo, "File đã thay đổi bên ngoài, đã tải lại")
+					// The edit also went into the state (history): merge it in
+					app.SaveState(renderer.PageSize)
+				}
+			}
+			renderer.Render()
+			debugLog.Input(keyboard.readAt)
+		}
+		if debugLog != nil && time.Since(lastMem) > debugMemInterval {
+			debugLog.Mem()
+			lastMem = time.Now()
+		}
+
+		start, title := time.Now(), ""
+		if sec := app.GetCurrentSection(); sec != nil {
+			title = sec.Title
+		}
+		handleInputRecovering()
+		app.syncJournal()
+		app.AddStudyTime(title, time.Since(start))
+		if time.Since(lastSave) > stateSaveInterval {
+			app.SaveState(renderer.PageSize)
+			lastSave = time.Now()
+		}
+	}
+}
+
+// openDocument loads the document (the profile's copy if a profile is
+// given), parses its sections and loads the mentor review comments.
+func openDocument(profile string) error {
+	app.Piped = false
+	// Switch to the profile's own copy of the curriculum
+	if profile != "" {
+		if err := app.UseProfile(profile); err != nil {
+			return err
+		}
+	}
+	if app.StateFile == "" {
+		app.UseDocumentState()
+	}
+
+	if err := app.LoadFile(); err != nil {
+		return err
+	}
+	app.ParseSections()
+	app.IndexInBackground()
+
+	reviews, err := LoadReviewStore(reviewPath(app.FilePath))
+	if err != nil {
+		return err
 	}
+	app.Reviews = reviews
+	return nil
 }
 
 // fileExists checks if a file exists.
@@ -728,119 +2060,111 @@ func handleFileNotFound() {
 	fmt.Printf("%s📚 SRE Learning Path CLI%s\n\n", Bold+Cyan, Reset)
 	fmt.Printf("File %s%s%s không tồn tại.\n\n", Yellow, app.FilePath, Reset)
 	fmt.Println("Chọn:")
-	fmt.Printf("  %s1%s. Tạo file mới với template mặc định\n", Bold+Cyan, Reset)
-	fmt.Printf("  %s2%s. Nhập đường dẫn file khác\n", Bold+Cyan, Reset)
+	fmt.Printf("  %s1%s. Tạo file mới từ template\n", Bold+Cyan, Reset)
+	fmt.Printf("  %s2%s. Chọn file khác (duyệt thư mục, file gần đây)\n", Bold+Cyan, Reset)
 	fmt.Printf("  %s3%s. Thoát\n", Bold+Cyan, Reset)
 	fmt.Printf("\nLựa chọn (1/2/3): ")
 
-	inputReader := bufio.NewReader(os.Stdin)
+	inputReader := bufio.NewReader(keyboard)
 	input, _ := inputReader.ReadString('\n')
 	input = strings.TrimSpace(input)
 
 	switch input {
 	case "1":
-		createDefaultFile()
+		content := chooseTemplate(inputReader)
+		if content == "" {
+			fmt.Println("Thoát.")
+			os.Exit(0)
+		}
+		createFileFromTemplate(content)
 	case "2":
-		fmt.Printf("Nhập đường dẫn file: ")
-		path, _ := inputReader.ReadString('\n')
-		path = strings.TrimSpace(path)
+		terminal.SetRawMode(true)
+		path := browseFiles(".")
+		terminal.SetRawMode(false)
+		terminal.SetBracketedPaste(false)
+		ClearScreen()
 		if path == "" {
-			fmt.Println("Đường dẫn trống. Thoát.")
-			os.Exit(1)
+			fmt.Println("Thoát.")
+			os.Exit(0)
 		}
 		app.FilePath = path
-		if !fileExists(app.FilePath) {
-			fmt.Printf("File %s không tồn tại. Thoát.\n", app.FilePath)
-			os.Exit(1)
-		}
 	default:
 		fmt.Println("Thoát.")
 		os.Exit(0)
 	}
 }
 
-// createDefaultFile creates a new markdown file with default template.
-func createDefaultFile() {
-	if err := os.WriteFile(app.FilePath, []byte(defaultTemplate), 0o644); err != nil {
+// createFileFromTemplate creates the markdown file with the given template content.
+func createFileFromTemplate(content string) {
+	if err := os.WriteFile(app.FilePath, []byte(content), 0o644); err != nil {
 		fmt.Printf("❌ Không thể tạo file: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Printf("%s✅ Đã tạo file %s%s\n", Green, app.FilePath, Reset)
-	time.Sleep(time.Second)
+	notify(SeveritySuccess, "Đã tạo file %s", app.FilePath)
 }
 
-// handleInput reads and processes a single keyboard input.
+// handleInput reads a key, after the count typed before it (see
+// readCount), and runs the action bound to it. A key that
+// starts chords shows the keys that can follow it (which-key) and reads
+// the next one, walking the binding trie until a binding is complete or
+// Esc cancels. A prefix bound on its own runs when no key follows within
+// chordTimeout.
 func handleInput() {
-	b := make([]byte, 3)
-	os.Stdin.Read(b)
-
-	switch {
-	// Content scrolling within section
-	case b[0] == 'j' || (b[0] == 27 && b[1] == 91 && b[2] == 66): // j or down arrow
-		renderer.ScrollDown()
-	case b[0] == 'k' || (b[0] == 27 && b[1] == 91 && b[2] == 65): // k or up arrow
-		renderer.ScrollUp()
-
-	// Section navigation
-	case b[0] == 'n': // next section
-		if app.NextSection() {
-			renderer.ResetScroll()
-		}
-	case b[0] == 'p': // previous section
-		if app.PrevSection() {
-			renderer.ResetScroll()
-		}
-	case b[0] == 13 || b[0] == 10: // Enter - next section
-		if app.NextSection() {
-			renderer.ResetScroll()
-		}
-
-	// Features
-	case b[0] == 't' || b[0] == 'T': // TOC
-		handleTOC()
-		renderer.ResetScroll()
-	case b[0] == 'x' || b[0] == 'X': // toggle checkbox (x = check)
-		handleToggle()
-	case b[0] == 'g': // goto section
-		handleGoto()
-		renderer.ResetScroll()
-	case b[0] == 'G': // goto last section
-		app.GotoSection(len(app.Sections) - 1)
-		renderer.ResetScroll()
-	case b[0] == '/': // search
-		handleSearch()
-		renderer.ResetScroll()
-	case b[0] == 'a' || b[0] == 'A': // add note
-		handleNote()
-
-	// Display settings
-	case b[0] == '+' || b[0] == '=': // increase visible lines
-		renderer.AdjustPageSize(10)
-	case b[0] == '-' || b[0] == '_': // decrease visible lines
-		renderer.AdjustPageSize(-10)
-
-	// System
-	case b[0] == 's' || b[0] == 'S': // save
-		app.SaveFile()
-		app.SaveState(renderer.PageSize)
-	case b[0] == 'q' || b[0] == 'Q' || b[0] == 3: // quit or Ctrl+C
-		terminal.SetRawMode(false)
-		app.SaveState(renderer.PageSize)
-		ClearScreen()
-		fmt.Println("👋 Tạm biệt! Tiến độ đã lưu.")
-		os.Exit(0)
-	case b[0] == '?': // help
-		handleHelp()
+	count, key := readCount()
+	node := buildKeyTrie(keymap).find(key)
+	for node != nil {
+		if len(node.order) == 0 {
+			runCounted(node.binding, key, count)
+			return
+		}
+		showWhichKey(key, node.entries())
+		next := ""
+		if node.binding != nil {
+			var ok bool
+			if next, ok = readKeyTimeout(chordTimeout); !ok {
+				runCounted(node.binding, key, count)
+				return
+			}
+		} else {
+			next = readKey()
+		}
+		if next == "Esc" {
+			return
+		}
+		key += chordSep + next
+		node = node.next[next]
 	}
 }
 
+// handleInputRecovering runs handleInput, showing a panic on the error
+// screen instead of ending the session (see recoverPanic).
+func handleInputRecovering() {
+	defer recoverPanic()
+	handleInput()
+}
+
+// quit saves progress, restores the terminal and exits.
+func quit() {
+	terminal.SetRawMode(false)
+	terminal.SetBracketedPaste(false)
+	app.SaveState(renderer.PageSize)
+	saveSession()
+	finishReview()
+	app.fireHook(hookQuit, "", "")
+	removeTempAttachments()
+	docLock.Release()
+	ClearScreen()
+	fmt.Println("👋 Tạm biệt! Tiến độ đã lưu.")
+	os.Exit(0)
+}
+
 // handleGoto displays section list and jumps to selected section.
 func handleGoto() {
 	terminal.SetRawMode(false)
 	ClearScreen()
 
 	fmt.Println(Bold + "📑 DANH SÁCH SECTIONS" + Reset)
-	fmt.Println(Dim + strings.Repeat("─", 60) + Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
 
 	for i, sec := range app.Sections {
 		prefix := strings.Repeat("  ", sec.Level-1)
@@ -853,63 +2177,117 @@ func handleGoto() {
 		progress := ""
 		if total > 0 {
 			progress = fmt.Sprintf(" %s[%d/%d]%s", Dim, checked, total, Reset)
+			if n := app.CountCheckboxes(i).InProgress; n > 0 {
+				progress += fmt.Sprintf(" %s%s%d%s", Yellow, glyphs.InProgress, n, Reset)
+			}
 		}
 
 		fmt.Printf("%s%3d. %s%s%s%s\n", Cyan, i+1, Reset, prefix, sec.Title, progress+marker)
 	}
 
-	fmt.Printf("\n%sNhập số (1-%d) hoặc Enter để hủy:%s ", Bold, len(app.Sections), Reset)
-
-	inputReader := bufio.NewReader(os.Stdin)
-	input, _ := inputReader.ReadString('\n')
-	input = strings.TrimSpace(input)
+	fmt.Printf("\n%sSố (1-%d) hoặc tên section (Tab: gợi ý tên, ↑/↓: lịch sử), Enter để hủy:%s\n", Bold, len(app.Sections), Reset)
 
-	if num, err := strconv.Atoi(input); err == nil {
-		app.GotoSection(num - 1)
+	input, ok := readPrompt(Prompt{Label: "> ", History: promptHistories["goto"], Complete: app.completeTitle})
+	if idx := app.FindSection(input); ok && input != "" {
+		if idx < 0 {
+			notify(SeverityWarning, "Không tìm thấy section %q", input)
+		} else {
+			rememberAnswer("goto", input)
+			app.GotoSection(idx)
+		}
 	}
 
 	terminal.SetRawMode(true)
 }
 
 // handleSearch prompts for search query and shows matching sections.
+// Queries are kept in the search history; the results can be saved as a
+// named search and pinned to the TOC footer.
 func handleSearch() {
-	terminal.SetRawMode(false)
 	ClearScreen()
 
-	fmt.Printf("%s🔍 Tìm kiếm:%s ", Bold, Reset)
-
-	inputReader := bufio.NewReader(os.Stdin)
-	query, _ := inputReader.ReadString('\n')
-	query = strings.TrimSpace(query)
+	store, storePath := loadSearchStore()
+	if len(store.Saved) > 0 {
+		var saved []string
+		for _, s := range store.Saved {
+			label := fmt.Sprintf("%s (%d)", s.Name, len(app.SearchSections(s.Query)))
+			if s.Pinned {
+				label = "📌 " + label
+			}
+			saved = append(saved, label)
+		}
+		fmt.Printf("%sĐã lưu (Tab để chọn): %s%s\n", Dim, strings.Join(saved, " · "), Reset)
+	}
+	fmt.Printf("%s↑/↓: lịch sử · Esc: hủy%s\n\n", Dim, Reset)
 
-	if query == "" {
-		terminal.SetRawMode(true)
+	query, ok := readSearchQuery(Bold+"🔍 Tìm kiếm:"+Reset+" ", store)
+	if !ok || query == "" {
 		return
 	}
+	store.AddHistory(query)
+	saveSearchStore(storePath, store)
 
 	matches := app.SearchSections(query)
 
 	if len(matches) == 0 {
-		fmt.Println(Red + "Không tìm thấy." + Reset)
-		time.Sleep(time.Second)
-		terminal.SetRawMode(true)
+		notify(SeverityWarning, "Không tìm thấy: %s", query)
 		return
 	}
 
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+
 	fmt.Printf("\n%sTìm thấy %d kết quả:%s\n\n", Green, len(matches), Reset)
 	for j, i := range matches {
-		fmt.Printf("%s%2d.%s %s\n", Cyan, j+1, Reset, app.Sections[i].Title)
+		fmt.Printf("%s%2d.%s %s\n", Cyan, j+1, Reset, highlightMatches(app.Sections[i].Title, query))
+		for _, line := range app.SearchSnippet(i, query) {
+			fmt.Printf("    %s%s%s\n", Dim, snippetLine(line, query, app.TermWidth-4), Reset)
+		}
 	}
 
-	fmt.Printf("\n%sChọn số hoặc Enter để hủy:%s ", Bold, Reset)
+	fmt.Printf("\n%sChọn số, s <tên>: lưu tìm kiếm, p <tên>: ghim/bỏ ghim, d <tên>: xóa tìm kiếm đã lưu, Enter để hủy:%s ", Bold, Reset)
+	inputReader := bufio.NewReader(keyboard)
 	input, _ := inputReader.ReadString('\n')
 	input = strings.TrimSpace(input)
 
+	// Jump to the matching line rather than the section top
 	if num, err := strconv.Atoi(input); err == nil && num >= 1 && num <= len(matches) {
-		app.GotoSection(matches[num-1])
+		idx := matches[num-1]
+		app.JumpTo(JumpTarget{Section: idx, Line: app.MatchLine(idx, query)})
+		return
 	}
 
-	terminal.SetRawMode(true)
+	cmd, name, _ := strings.Cut(input, " ")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = query
+	}
+	switch cmd {
+	case "s":
+		store.Save(name, query)
+		notify(SeverityInfo, "Đã lưu tìm kiếm %q", name)
+	case "p":
+		saved := store.Find(name)
+		if saved == nil {
+			store.Save(name, query)
+			saved = store.Find(name)
+		}
+		saved.Pinned = !saved.Pinned
+		if saved.Pinned {
+			notify(SeverityInfo, "Đã ghim %q vào mục lục", saved.Name)
+		} else {
+			notify(SeverityInfo, "Đã bỏ ghim %q", saved.Name)
+		}
+	case "d":
+		if !store.Remove(name) {
+			notify(SeverityWarning, "Không có tìm kiếm đã lưu %q", name)
+			return
+		}
+		notify(SeverityInfo, "Đã xóa tìm kiếm %q", name)
+	default:
+		return
+	}
+	saveSearchStore(storePath, store)
 }
 
 // handleToggle displays checkboxes and toggles the selected one.
@@ -923,43 +2301,55 @@ func handleToggle() {
 	ClearScreen()
 
 	sec := app.GetCurrentSection()
-	lines := strings.Split(sec.Content, "\n")
+	lines := strings.Split(sec.Content(), "\n")
 
-	fmt.Printf("%s☑ TOGGLE CHECKBOX%s\n", Bold, Reset)
-	fmt.Println(Dim + strings.Repeat("─", 60) + Reset)
+	fmt.Printf("%s%s TOGGLE CHECKBOX%s\n", Bold, glyphs.Checked, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
 
 	for j, lineIdx := range checkboxLines {
 		line := lines[lineIdx]
-		status := Red + "☐" + Reset
-		if strings.Contains(line, "- [x]") {
-			status = Green + "☑" + Reset
-		}
-		text := strings.TrimSpace(line)
-		text = strings.TrimPrefix(text, "- [ ]")
-		text = strings.TrimPrefix(text, "- [x]")
-		text = strings.TrimSpace(text)
-
-		fmt.Printf("%s%2d.%s %s %s\n", Cyan, j+1, Reset, status, text)
+		fmt.Printf("%s%2d.%s %s %s\n", Cyan, j+1, Reset, checkboxGlyph(checkboxState(line)), checkboxText(line))
 	}
 
-	fmt.Printf("\n%sNhập số để toggle (hoặc Enter để hủy):%s ", Bold, Reset)
+	fmt.Printf("\n%sNhập số để chuyển trạng thái %s → %s → %s, thêm ' ' ~ x - để đặt trạng thái (vd. 3-), Enter để hủy:%s ",
+		Bold, glyphs.Unchecked, glyphs.InProgress, glyphs.Checked, Reset)
 
-	inputReader := bufio.NewReader(os.Stdin)
+	inputReader := bufio.NewReader(keyboard)
 	input, _ := inputReader.ReadString('\n')
-	input = strings.TrimSpace(input)
+	input = strings.TrimRight(input, "\r\n")
+	input = strings.TrimLeft(input, " ")
 
-	if num, err := strconv.Atoi(input); err == nil && num >= 1 && num <= len(checkboxLines) {
-		lineIdx := checkboxLines[num-1]
-		if app.ToggleCheckbox(lineIdx) {
-			app.UpdateFileSection(app.CurrentIdx)
-			app.ParseSections() // Re-parse to update line numbers
-			app.SaveFile()
-		}
+	// "3" cycles item 3, "3~" / "3x" / "3-" / "3 " set its state
+	digits := strings.TrimRight(input, " ~xX-")
+	if num, err := strconv.Atoi(digits); err == nil {
+		toggleCheckboxItem(num, input[len(digits):])
 	}
 
 	terminal.SetRawMode(true)
 }
 
+// toggleCheckboxItem cycles the num-th checkbox of the current section, or
+// sets its state when suffix is one of " ~x-", and saves the file.
+func toggleCheckboxItem(num int, suffix string) bool {
+	checkboxLines := app.GetCheckboxLines()
+	if num < 1 || num > len(checkboxLines) {
+		return false
+	}
+	lineIdx := checkboxLines[num-1]
+	toggled := false
+	if suffix == "" {
+		toggled = app.CycleCheckbox(lineIdx)
+	} else {
+		toggled = app.SetCheckboxState(lineIdx, checkboxState("- ["+suffix[:1]+"]"))
+	}
+	if toggled {
+		app.UpdateFileSection(app.CurrentIdx)
+		saveDocument()
+		app.SaveState(renderer.PageSize)
+	}
+	return toggled
+}
+
 // handleNote provides a menu for note management.
 func handleNote() {
 	terminal.SetRawMode(false)
@@ -967,12 +2357,12 @@ func handleNote() {
 	exec.Command("stty", "sane").Run()
 
 	sec := app.GetCurrentSection()
-	existingNotes := extractNotes(sec.Content)
+	existingNotes := extractNotes(sec.Content())
 
 	for {
 		ClearScreen()
 		fmt.Printf("%s📝 GHI CHÚ - %s%s\n", Bold+Cyan, sec.Title, Reset)
-		fmt.Println(Dim + strings.Repeat("─", 60) + Reset)
+		fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
 
 		if len(existingNotes) > 0 {
 			fmt.Printf("\n%sGhi chú hiện có (%d):%s\n\n", Yellow, len(existingNotes), Reset)
@@ -991,6 +2381,7 @@ func handleNote() {
 		}
 
 		fmt.Println()
+		printStatusLine()
 		fmt.Printf("%sChọn:%s\n", Bold, Reset)
 		fmt.Printf("  %sa%s - Thêm ghi chú mới\n", Cyan, Reset)
 		if len(existingNotes) > 0 {
@@ -999,10 +2390,16 @@ func handleNote() {
 			fmt.Printf("  %sd%s - Xóa ghi chú\n", Cyan, Reset)
 			fmt.Printf("  %sc%s - Xóa TẤT CẢ ghi chú (clean)\n", Cyan, Reset)
 		}
+		fmt.Printf("  %sr%s - Khôi phục ghi chú đã xóa\n", Cyan, Reset)
+		fmt.Printf("  %sg%s - Ghi âm (voice memo)\n", Cyan, Reset)
+		if len(existingNotes) > 0 {
+			fmt.Printf("  %sf%s - Đính kèm file vào ghi chú\n", Cyan, Reset)
+			fmt.Printf("  %sm%s - Chuyển ghi chú sang section khác\n", Cyan, Reset)
+		}
 		fmt.Printf("  %sq%s - Quay lại\n", Cyan, Reset)
 		fmt.Printf("\nLựa chọn: ")
 
-		reader := bufio.NewReader(os.Stdin)
+		reader := bufio.NewReader(keyboard)
 		choice, _ := reader.ReadString('\n')
 		choice = strings.TrimSpace(strings.ToLower(choice))
 
@@ -1011,7 +2408,7 @@ func handleNote() {
 			addNewNote(reader)
 			// Refresh notes list
 			sec = app.GetCurrentSection()
-			existingNotes = extractNotes(sec.Content)
+			existingNotes = extractNotes(sec.Content())
 		case "v":
 			if len(existingNotes) > 0 {
 				viewNoteDetail(existingNotes, reader)
@@ -1021,7 +2418,7 @@ func handleNote() {
 				if editNote(reader, existingNotes) {
 					// Refresh after edit
 					sec = app.GetCurrentSection()
-					existingNotes = extractNotes(sec.Content)
+					existingNotes = extractNotes(sec.Content())
 				}
 			}
 		case "d":
@@ -1029,7 +2426,7 @@ func handleNote() {
 				if deleteNote(reader, existingNotes) {
 					// Refresh after delete
 					sec = app.GetCurrentSection()
-					existingNotes = extractNotes(sec.Content)
+					existingNotes = extractNotes(sec.Content())
 				}
 			}
 		case "c":
@@ -1037,9 +2434,29 @@ func handleNote() {
 				if cleanAllNotes(reader) {
 					// Refresh after clean
 					sec = app.GetCurrentSection()
-					existingNotes = extractNotes(sec.Content)
+					existingNotes = extractNotes(sec.Content())
 				}
 			}
+		case "g":
+			if recordVoiceMemo(reader) {
+				sec = app.GetCurrentSection()
+				existingNotes = extractNotes(sec.Content())
+			}
+		case "m":
+			if len(existingNotes) > 0 && moveNoteToSection(reader, existingNotes) {
+				sec = app.GetCurrentSection()
+				existingNotes = extractNotes(sec.Content())
+			}
+		case "f":
+			if len(existingNotes) > 0 && attachFileToNote(reader, existingNotes) {
+				sec = app.GetCurrentSection()
+				existingNotes = extractNotes(sec.Content())
+			}
+		case "r":
+			if restoreNote(reader) {
+				sec = app.GetCurrentSection()
+				existingNotes = extractNotes(sec.Content())
+			}
 		case "q", "":
 			terminal.SetRawMode(true)
 			return
@@ -1047,67 +2464,35 @@ func handleNote() {
 	}
 }
 
-// addNewNote handles adding a new note using an external editor.
-// This ensures proper UTF-8 support and cursor navigation.
+// addNewNote handles adding a new note in the built-in editor or an
+// external one (see noteEditor).
 func addNewNote(reader *bufio.Reader) {
+	editor := noteEditor()
+	if editor == "" {
+		note, ok := editText("📝 THÊM GHI CHÚ MỚI", "")
+		if note = strings.TrimSpace(note); ok && note != "" {
+			saveNote(note)
+		} else if ok {
+			notify(SeverityWarning, "Ghi chú trống - đã hủy")
+		}
+		return
+	}
+
 	ClearScreen()
 	fmt.Printf("%s📝 THÊM GHI CHÚ MỚI%s\n", Bold+Cyan, Reset)
-	fmt.Println(Dim + strings.Repeat("─", 60) + Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
 	fmt.Println()
 
 	// Create temp file for editing
 	tmpFile, err := os.CreateTemp("", "sre-note-*.txt")
 	if err != nil {
-		fmt.Printf("%s❌ Lỗi tạo file tạm: %v%s\n", Red, err, Reset)
-		fmt.Printf("\n%s[Enter để quay lại]%s", Dim, Reset)
-		reader.ReadString('\n')
+		showError("Lỗi tạo file tạm", err, nil)
 		return
 	}
 	tmpPath := tmpFile.Name()
 	defer os.Remove(tmpPath)
 	tmpFile.Close()
 
-	// Find editor
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = os.Getenv("VISUAL")
-	}
-	if editor == "" {
-		// Try common editors
-		for _, e := range []string{"nano", "vim", "vi", "notepad"} {
-			if _, err := exec.LookPath(e); err == nil {
-				editor = e
-				break
-			}
-		}
-	}
-
-	if editor == "" {
-		// Fallback to simple stdin input
-		fmt.Println("Không tìm thấy editor (nano/vim). Dùng input đơn giản:")
-		fmt.Println("(Nhập ghi chú, dòng trống để kết thúc)")
-		fmt.Println()
-
-		var lines []string
-		for {
-			line, err := reader.ReadString('\n')
-			if err != nil {
-				break
-			}
-			line = strings.TrimRight(line, "\r\n")
-			if line == "" {
-				break
-			}
-			lines = append(lines, line)
-		}
-
-		note := strings.TrimSpace(strings.Join(lines, "\n"))
-		if note != "" {
-			saveNote(note)
-		}
-		return
-	}
-
 	fmt.Printf("Mở %s%s%s để soạn ghi chú...\n", Bold+Cyan, editor, Reset)
 	fmt.Printf("%s(Lưu và thoát editor để hoàn thành)%s\n", Dim, Reset)
 	time.Sleep(500 * time.Millisecond)
@@ -1136,8 +2521,7 @@ func addNewNote(reader *bufio.Reader) {
 
 	note := strings.TrimSpace(string(content))
 	if note == "" {
-		fmt.Printf("\n%sGhi chú trống - đã hủy.%s\n", Yellow, Reset)
-		time.Sleep(time.Second)
+		notify(SeverityWarning, "Ghi chú trống - đã hủy")
 		return
 	}
 
@@ -1148,20 +2532,16 @@ func addNewNote(reader *bufio.Reader) {
 func saveNote(note string) {
 	app.AddNote(note)
 	app.UpdateFileSection(app.CurrentIdx)
-	app.ParseSections()
-	if err := app.SaveFile(); err != nil {
-		fmt.Printf("\n%s❌ Lỗi lưu: %v%s\n", Red, err, Reset)
-	} else {
-		fmt.Printf("\n%s✅ Đã lưu ghi chú!%s\n", Green, Reset)
+	if saveDocument() {
+		notify(SeveritySuccess, "Đã lưu ghi chú")
 	}
-	time.Sleep(time.Second)
 }
 
 // viewNoteDetail shows full content of a specific note.
 func viewNoteDetail(notes []string, reader *bufio.Reader) {
 	ClearScreen()
 	fmt.Printf("%s📖 XEM GHI CHÚ%s\n", Bold+Cyan, Reset)
-	fmt.Println(Dim + strings.Repeat("─", 60) + Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
 	fmt.Println()
 
 	for i := range notes {
@@ -1184,19 +2564,20 @@ func viewNoteDetail(notes []string, reader *bufio.Reader) {
 	// Show full note
 	ClearScreen()
 	fmt.Printf("%s📖 GHI CHÚ #%d%s\n", Bold+Cyan, idx, Reset)
-	fmt.Println(Dim + strings.Repeat("─", 60) + Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
 	fmt.Println()
-	fmt.Println(notes[idx-1])
+	for _, line := range renderNote(notes[idx-1], app.TermWidth) {
+		fmt.Println(line)
+	}
 	fmt.Println()
-	fmt.Printf("%s[Enter để quay lại]%s", Dim, Reset)
-	reader.ReadString('\n')
+	showNoteAttachments(notes[idx-1], reader)
 }
 
 // editNote opens an editor to modify an existing note.
 func editNote(reader *bufio.Reader, notes []string) bool {
 	ClearScreen()
 	fmt.Printf("%s✏️ SỬA GHI CHÚ%s\n", Bold+Cyan, Reset)
-	fmt.Println(Dim + strings.Repeat("─", 60) + Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
 	fmt.Println()
 
 	for i, note := range notes {
@@ -1222,28 +2603,21 @@ func editNote(reader *bufio.Reader, notes []string) bool {
 	}
 
 	oldNote := notes[idx-1]
+	noteContent := noteBody(oldNote)
 
-	// Extract just the note content (remove timestamp prefix)
-	noteContent := oldNote
-	if strings.HasPrefix(noteContent, "> **Ghi chú [") {
-		// Find the end of timestamp
-		if endIdx := strings.Index(noteContent, ":**"); endIdx != -1 {
-			noteContent = strings.TrimSpace(noteContent[endIdx+3:])
+	editor := noteEditor()
+	if editor == "" {
+		edited, ok := editText("✏️ SỬA GHI CHÚ", noteContent)
+		if !ok {
+			return false
 		}
+		return replaceNote(reader, oldNote, edited)
 	}
-	// Remove leading > from subsequent lines
-	lines := strings.Split(noteContent, "\n")
-	for i, line := range lines {
-		lines[i] = strings.TrimPrefix(strings.TrimPrefix(line, "> "), ">")
-	}
-	noteContent = strings.Join(lines, "\n")
 
 	// Create temp file with existing content
 	tmpFile, err := os.CreateTemp("", "sre-note-edit-*.txt")
 	if err != nil {
-		fmt.Printf("%s❌ Lỗi tạo file tạm: %v%s\n", Red, err, Reset)
-		fmt.Printf("\n%s[Enter để quay lại]%s", Dim, Reset)
-		reader.ReadString('\n')
+		showError("Lỗi tạo file tạm", err, nil)
 		return false
 	}
 	tmpPath := tmpFile.Name()
@@ -1252,27 +2626,6 @@ func editNote(reader *bufio.Reader, notes []string) bool {
 	tmpFile.WriteString(noteContent)
 	tmpFile.Close()
 
-	// Find editor
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = os.Getenv("VISUAL")
-	}
-	if editor == "" {
-		for _, e := range []string{"nano", "vim", "vi"} {
-			if _, err := exec.LookPath(e); err == nil {
-				editor = e
-				break
-			}
-		}
-	}
-
-	if editor == "" {
-		fmt.Printf("%s❌ Không tìm thấy editor%s\n", Red, Reset)
-		fmt.Printf("\n%s[Enter để quay lại]%s", Dim, Reset)
-		reader.ReadString('\n')
-		return false
-	}
-
 	fmt.Printf("\nMở %s%s%s để sửa...\n", Bold+Cyan, editor, Reset)
 	time.Sleep(500 * time.Millisecond)
 
@@ -1297,32 +2650,32 @@ func editNote(reader *bufio.Reader, notes []string) bool {
 		reader.ReadString('\n')
 		return false
 	}
+	return replaceNote(reader, oldNote, string(content))
+}
 
-	newNote := strings.TrimSpace(string(content))
+// replaceNote replaces oldNote of the current section with the edited
+// text and saves the file; an empty text cancels.
+func replaceNote(reader *bufio.Reader, oldNote, edited string) bool {
+	newNote := strings.TrimSpace(edited)
 	if newNote == "" {
-		fmt.Printf("\n%sGhi chú trống - đã hủy.%s\n", Yellow, Reset)
-		time.Sleep(time.Second)
+		notify(SeverityWarning, "Ghi chú trống - đã hủy")
 		return false
 	}
 
 	// Replace old note with new one
 	sec := app.GetCurrentSection()
-	newContent := removeNoteFromContent(sec.Content, oldNote)
-	app.Sections[app.CurrentIdx].Content = newContent
+	newContent := removeNoteFromContent(sec.Content(), oldNote)
+	app.Sections[app.CurrentIdx].SetContent(newContent)
 
 	// Add the edited note
 	app.AddNote(newNote)
 	app.UpdateFileSection(app.CurrentIdx)
-	app.ParseSections()
 
-	if err := app.SaveFile(); err != nil {
-		fmt.Printf("\n%s❌ Lỗi lưu: %v%s\n", Red, err, Reset)
-		time.Sleep(time.Second)
+	if !saveReviewed(reader) {
 		return false
 	}
 
-	fmt.Printf("\n%s✅ Đã cập nhật ghi chú!%s\n", Green, Reset)
-	time.Sleep(time.Second)
+	notify(SeveritySuccess, "Đã cập nhật ghi chú")
 	return true
 }
 
@@ -1330,7 +2683,7 @@ func editNote(reader *bufio.Reader, notes []string) bool {
 func deleteNote(reader *bufio.Reader, notes []string) bool {
 	ClearScreen()
 	fmt.Printf("%s🗑️ XÓA GHI CHÚ%s\n", Bold+Red, Reset)
-	fmt.Println(Dim + strings.Repeat("─", 60) + Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
 	fmt.Println()
 
 	for i, note := range notes {
@@ -1355,31 +2708,22 @@ func deleteNote(reader *bufio.Reader, notes []string) bool {
 		return false
 	}
 
-	// Confirm delete
-	fmt.Printf("\n%sXác nhận xóa ghi chú #%d? (y/N): %s", Yellow, idx, Reset)
-	confirm, _ := reader.ReadString('\n')
-	confirm = strings.TrimSpace(strings.ToLower(confirm))
-
-	if confirm != "y" && confirm != "yes" {
-		return false
-	}
-
-	// Remove note from content
+	// Remove note from content, after previewing and confirming it
 	noteToDelete := notes[idx-1]
-	sec := app.GetCurrentSection()
-	newContent := removeNoteFromContent(sec.Content, noteToDelete)
-	app.Sections[app.CurrentIdx].Content = newContent
-
-	app.UpdateFileSection(app.CurrentIdx)
-	app.ParseSections()
-	if err := app.SaveFile(); err != nil {
-		fmt.Printf("\n%s❌ Lỗi: %v%s\n", Red, err, Reset)
-		time.Sleep(time.Second)
+	if !runChange(reader, fmt.Sprintf("Xóa ghi chú #%d", idx), "", func() error {
+		sec := app.GetCurrentSection()
+		app.Sections[app.CurrentIdx].SetContent(removeNoteFromContent(sec.Content(), noteToDelete))
+		app.UpdateFileSection(app.CurrentIdx)
+		return nil
+	}) {
 		return false
 	}
+	if err := app.TrashNotes(app.CurrentIdx, []string{noteToDelete}); err != nil {
+		notify(SeverityWarning, "Đã xóa ghi chú nhưng không lưu được vào thùng rác: %v", err)
+		return true
+	}
 
-	fmt.Printf("\n%s✅ Đã xóa ghi chú!%s\n", Green, Reset)
-	time.Sleep(time.Second)
+	notify(SeveritySuccess, "Đã xóa ghi chú (z u để hoàn tác, r trong menu ghi chú để khôi phục)")
 	return true
 }
 
@@ -1426,17 +2770,23 @@ func removeNoteFromContent(content, noteToRemove string) string {
 
 // cleanAllNotes removes all notes from current section.
 func cleanAllNotes(reader *bufio.Reader) bool {
-	fmt.Printf("\n%s⚠️ Xác nhận xóa TẤT CẢ ghi chú trong section này? (y/N): %s", Yellow, Reset)
-	confirm, _ := reader.ReadString('\n')
-	confirm = strings.TrimSpace(strings.ToLower(confirm))
-
-	if confirm != "y" && confirm != "yes" {
+	deleted := extractNotes(app.GetCurrentSection().Content())
+	if !runChange(reader, fmt.Sprintf("Xóa TẤT CẢ %d ghi chú trong section này", len(deleted)), "", removeAllNotes) {
 		return false
 	}
+	if err := app.TrashNotes(app.CurrentIdx, deleted); err != nil {
+		notify(SeverityWarning, "Đã xóa ghi chú nhưng không lưu được vào thùng rác: %v", err)
+		return true
+	}
+
+	notify(SeveritySuccess, "Đã xóa tất cả ghi chú (z u để hoàn tác, r trong menu ghi chú để khôi phục)")
+	return true
+}
 
-	// Remove all notes from content
+// removeAllNotes removes every note of the current section in memory.
+func removeAllNotes() error {
 	sec := app.GetCurrentSection()
-	lines := strings.Split(sec.Content, "\n")
+	lines := strings.Split(sec.Content(), "\n")
 	var result []string
 	inNote := false
 
@@ -1462,19 +2812,37 @@ func cleanAllNotes(reader *bufio.Reader) bool {
 		result = append(result, line)
 	}
 
-	app.Sections[app.CurrentIdx].Content = strings.TrimSpace(strings.Join(result, "\n"))
+	app.Sections[app.CurrentIdx].SetContent(strings.TrimSpace(strings.Join(result, "\n")))
 	app.UpdateFileSection(app.CurrentIdx)
-	app.ParseSections()
+	return nil
+}
 
-	if err := app.SaveFile(); err != nil {
-		fmt.Printf("\n%s❌ Lỗi: %v%s\n", Red, err, Reset)
-		time.Sleep(time.Second)
-		return false
+// renderNote renders a note for the detail view: its timestamp, then
+// its body through the markdown renderer.
+func renderNote(note string, width int) []string {
+	var out []string
+	if m := noteTimestampRegex.FindStringSubmatch(note); m != nil {
+		out = append(out, Dim+"🕒 "+m[1]+Reset, "")
 	}
+	return append(out, RenderLines(strings.Split(noteBody(note), "\n"), width)...)
+}
 
-	fmt.Printf("\n%s✅ Đã xóa tất cả ghi chú!%s\n", Green, Reset)
-	time.Sleep(time.Second)
-	return true
+// noteBody returns the text of a note without its timestamp banner
+// and blockquote markers.
+func noteBody(note string) string {
+	// Extract just the note content (remove timestamp prefix)
+	if strings.HasPrefix(note, "> **Ghi chú [") {
+		// Find the end of timestamp
+		if endIdx := strings.Index(note, ":**"); endIdx != -1 {
+			note = strings.TrimSpace(note[endIdx+3:])
+		}
+	}
+	// Remove leading > from subsequent lines
+	lines := strings.Split(note, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(strings.TrimPrefix(line, "> "), ">")
+	}
+	return strings.Join(lines, "\n")
 }
 
 // extractNotes extracts existing notes from section content.
@@ -1524,110 +2892,79 @@ func extractNotes(content string) []string {
 	return notes
 }
 
-// handleHelp displays all keyboard shortcuts.
-func handleHelp() {
-	ClearScreen()
-
-	fmt.Printf("%s%s", BgCyan+Black+Bold, strings.Repeat(" ", app.TermWidth))
-	fmt.Print("\r")
-	fmt.Printf(" ❓ KEYBOARD SHORTCUTS")
-	fmt.Printf("%s\n\n", Reset)
-
-	helpItems := []struct {
-		key  string
-		desc string
-	}{
-		{"j / ↓", "Scroll xuống trong section"},
-		{"k / ↑", "Scroll lên trong section"},
-		{"n", "Section tiếp theo (next)"},
-		{"p", "Section trước (previous)"},
-		{"Enter", "Section tiếp theo"},
-		{"", ""},
-		{"t", "Mở Table of Contents"},
-		{"g", "Goto - nhảy đến section"},
-		{"G", "Goto section cuối"},
-		{"/", "Tìm kiếm section"},
-		{"", ""},
-		{"x", "Toggle checkbox (tick/untick)"},
-		{"a", "Ghi chú (thêm/xem/sửa/xóa)"},
-		{"s", "Lưu file & tiến độ"},
-		{"", ""},
-		{"+", "Tăng 10 dòng hiển thị"},
-		{"-", "Giảm 10 dòng hiển thị"},
-		{"", ""},
-		{"?", "Hiển thị help này"},
-		{"q", "Thoát"},
-	}
-
-	for _, item := range helpItems {
-		if item.key == "" {
-			fmt.Println()
-		} else {
-			fmt.Printf("  %s%-10s%s %s\n", Bold+Cyan, item.key, Reset, item.desc)
-		}
-	}
-
-	fmt.Printf("\n%sTrong TOC:%s\n", Bold+Magenta, Reset)
-	fmt.Printf("  %s%-10s%s %s\n", Bold+Cyan, "j/k", Reset, "Di chuyển lên/xuống")
-	fmt.Printf("  %s%-10s%s %s\n", Bold+Cyan, "Enter", Reset, "Chọn section")
-	fmt.Printf("  %s%-10s%s %s\n", Bold+Cyan, "q/Esc", Reset, "Đóng TOC")
-
-	fmt.Printf("\n%sGhi chú (nhấn a):%s\n", Bold+Magenta, Reset)
-	fmt.Printf("  %s%-10s%s %s\n", Bold+Cyan, "a", Reset, "Thêm mới (mở editor)")
-	fmt.Printf("  %s%-10s%s %s\n", Bold+Cyan, "v", Reset, "Xem chi tiết")
-	fmt.Printf("  %s%-10s%s %s\n", Bold+Cyan, "e", Reset, "Sửa ghi chú")
-	fmt.Printf("  %s%-10s%s %s\n", Bold+Cyan, "d", Reset, "Xóa")
-	fmt.Printf("  %sDùng nano/vim, set EDITOR env để đổi editor%s\n", Dim, Reset)
-
-	fmt.Printf("\n%sHiện tại: %d dòng/trang (nhấn +/- để chỉnh, không giới hạn)%s\n", Dim, renderer.PageSize, Reset)
-
-	fmt.Printf("\n%s[Nhấn phím bất kỳ để quay lại]%s", Dim, Reset)
-
-	// Wait for any key
-	b := make([]byte, 1)
-	os.Stdin.Read(b)
-}
-
 // handleTOC displays an interactive table of contents.
-// Supports j/k navigation, Enter to select, q to quit.
+// Supports j/k navigation, Enter to select, q to quit, Tab to cycle the
+// filter (incomplete, bookmarked, with notes) and Ctrl+r the order.
 func handleTOC() {
-	// Build list of navigable sections (skip phase headers)
+	if renderer.Accessible {
+		handleAccessibleTOC()
+		return
+	}
+	if len(app.Sections) == 0 {
+		return
+	}
+
 	type tocItem struct {
 		idx   int
 		title string
 		level int
+		// positions are the title runes matching the query
+		positions []int
+		score     int
 	}
 
+	// Build the list for the filter, order and typed query, keeping the
+	// selected section selected when it is still listed. With a query the
+	// best fuzzy matches come first.
+	filter, order := tocAll, tocByPosition
+	query := ""
 	items := []tocItem{}
-	for i, sec := range app.Sections {
-		items = append(items, tocItem{i, sec.Title, sec.Level})
-	}
-
-	if len(items) == 0 {
-		return
-	}
-
-	// Find current position in TOC
 	tocIdx := 0
-	for i, item := range items {
-		if item.idx == app.CurrentIdx {
-			tocIdx = i
-			break
+	build := func(selected int) {
+		items = items[:0]
+		for _, i := range app.TOCSections(filter, order) {
+			score, positions, ok := fuzzyMatch(query, app.Sections[i].Title)
+			if ok {
+				items = append(items, tocItem{i, app.Sections[i].Title, app.Sections[i].Level, positions, score})
+			}
+		}
+		if query != "" {
+			sort.SliceStable(items, func(i, j int) bool { return items[i].score > items[j].score })
+		}
+		tocIdx = 0
+		for i, item := range items {
+			if item.idx == selected {
+				tocIdx = i
+			}
 		}
 	}
+	build(app.CurrentIdx)
+
+	// Pinned saved searches with their live result counts
+	store, _ := loadSearchStore()
+	pinned := app.pinnedSearchesLabel(store)
 
 	// Scrolling state
 	scrollOffset := 0
-	maxVisible := app.TermHeight - 6
+	maxVisible := app.TermHeight - 8
+	if pinned != "" {
+		maxVisible--
+	}
 
 	for {
 		ClearScreen()
 
 		// Header
-		fmt.Printf("%s%s", BgMagenta+White+Bold, strings.Repeat(" ", app.TermWidth))
-		fmt.Print("\r")
-		fmt.Printf(" 📚 MỤC LỤC  (j/k: di chuyển, Enter: chọn, q: đóng)")
-		fmt.Printf("%s\n\n", Reset)
+		fmt.Printf("%s\n", barLine(BgMagenta+White+Bold, " 📚 MỤC LỤC  (↑/↓: di chuyển, Enter: chọn, gõ chữ: tìm, Tab: lọc, Ctrl+r: sắp xếp, Esc: đóng)", app.TermWidth))
+		fmt.Printf("%sLọc: %s · Sắp xếp: %s · %d/%d section%s\n", Dim, tocFilterNames[filter], tocOrderNames[order], len(items), len(app.Sections), Reset)
+		if query != "" {
+			fmt.Printf("🔎 %s%s%s\n\n", Bold, query, Reset)
+		} else {
+			fmt.Printf("%s🔎 Gõ để tìm section (j/k, g/G, q khi chưa gõ gì)%s\n\n", Dim, Reset)
+		}
+		if len(items) == 0 {
+			fmt.Printf("%sKhông có section nào khớp bộ lọc.%s\n", Dim, Reset)
+		}
 
 		// Adjust scroll to keep selection visible
 		if tocIdx < scrollOffset {
@@ -1645,7 +2982,7 @@ func handleTOC() {
 			// Selection indicator
 			selector := "  "
 			if i == tocIdx {
-				selector = Green + "▶ " + Reset
+				selector = Green + glyphs.Selector + " " + Reset
 			}
 
 			// Indentation based on level
@@ -1657,14 +2994,44 @@ func handleTOC() {
 			if total > 0 {
 				pct := float64(checked) / float64(total) * 100
 				if pct == 100 {
-					progress = Green + " ✓" + Reset
+					progress = Green + " " + glyphs.Done + Reset
 				} else if pct > 0 {
 					progress = fmt.Sprintf(" %s%.0f%%%s", Yellow, pct, Reset)
 				} else {
-					progress = Dim + " ○" + Reset
+					progress = Dim + " " + glyphs.Todo + Reset
 				}
 			}
 
+			// Skipped sections are dimmed
+			skipped := app.IsSkipped(item.idx)
+			if skipped {
+				progress = Dim + " " + glyphs.Skipped + Reset
+			}
+
+			if app.Bookmarks[item.title] {
+				progress += " 🔖"
+			}
+
+			// Due for re-study after a flash review
+			if r, ok := app.Repetition[item.title]; ok && !time.Now().Before(r.Due) {
+				progress += " 🧠"
+			}
+
+			// Estimated reading time
+			if stats := app.SectionStats(item.idx, readingWPM()); stats.Words > 0 {
+				progress += Dim + " · " + readingTimeLabel(stats.ReadingTime) + Reset
+			}
+
+			// When the section last had an item completed
+			if last, ok := app.LastCompleted(item.idx); ok {
+				progress += Dim + " · " + last.Format("02/01") + Reset
+			}
+
+			// Mentor review comments marker
+			if len(app.Reviews.CommentsFor(item.title)) > 0 {
+				progress += Magenta + " 💬" + Reset
+			}
+
 			// Current section marker
 			current := ""
 			if item.idx == app.CurrentIdx {
@@ -1672,10 +3039,7 @@ func handleTOC() {
 			}
 
 			// Title styling based on level
-			title := item.title
-			if len(title) > 50 {
-				title = title[:47] + "..."
-			}
+			title := truncateEllipsis(item.title, 50)
 
 			titleStyle := ""
 			switch item.level {
@@ -1688,20 +3052,25 @@ func handleTOC() {
 			default:
 				titleStyle = Dim
 			}
+			if skipped {
+				titleStyle = Dim
+			}
 
 			// Print row
-			fmt.Printf("%s%s%s%s%s%s%s\n", selector, indent, titleStyle, title, Reset, progress, current)
+			title = highlightPositions(title, item.positions, Bold+Yellow, titleStyle)
+			row := fmt.Sprintf("%s%s%s%s%s%s%s", selector, indent, titleStyle, title, Reset, progress, current)
+			fmt.Println(truncateVisible(row, app.TermWidth))
 		}
 
 		// Scroll indicators
 		if scrollOffset > 0 {
-			fmt.Printf("\n%s  ↑ còn %d mục phía trên%s", Dim, scrollOffset, Reset)
+			fmt.Printf("\n%s  %s còn %d mục phía trên%s", Dim, glyphs.Up, scrollOffset, Reset)
 		}
 		if endIdx < len(items) {
 			if scrollOffset == 0 {
 				fmt.Println()
 			}
-			fmt.Printf("\n%s  ↓ còn %d mục phía dưới%s", Dim, len(items)-endIdx, Reset)
+			fmt.Printf("\n%s  %s còn %d mục phía dưới%s", Dim, glyphs.Down, len(items)-endIdx, Reset)
 		}
 
 		// Footer with total progress
@@ -1711,35 +3080,87 @@ func handleTOC() {
 			pct := float64(checked) / float64(total) * 100
 			barWidth := 20
 			filled := int(float64(barWidth) * pct / 100)
-			bar := Green + strings.Repeat("█", filled) + Dim + strings.Repeat("░", barWidth-filled) + Reset
-			fmt.Printf("\n  Tiến độ: [%s] %d/%d (%.0f%%)\n", bar, checked, total, pct)
+			bar := Green + strings.Repeat(glyphs.BarFull, filled) + Dim + strings.Repeat(glyphs.BarEmpty, barWidth-filled) + Reset
+			fmt.Printf("\n  Tiến độ: [%s] %d/%d (%.0f%%)", bar, checked, total, pct)
+			if n := app.TotalCheckboxes().InProgress; n > 0 {
+				fmt.Printf(" %s%s %d đang làm%s", Yellow, glyphs.InProgress, n, Reset)
+			}
+			fmt.Println()
+		}
+		if remaining, unread := app.RemainingReadingTime(readingWPM()); unread > 0 {
+			fmt.Printf("  %sCòn %s đọc (%d section chưa đọc)%s\n", Dim, readingTimeLabel(remaining), unread, Reset)
+		}
+		if pinned != "" {
+			fmt.Printf("  %s\n", pinned)
 		}
 
-		// Read input
-		b := make([]byte, 3)
-		os.Stdin.Read(b)
+		// Read input: typing filters, the letter keys are commands only
+		// while nothing is typed
+		key := readKey()
+		typed, isTyped := typedRune(key)
+		if query != "" && isTyped && key != "Space" {
+			key = "type"
+		}
 
-		switch {
-		case b[0] == 'j' || (b[0] == 27 && b[1] == 91 && b[2] == 66): // j or down
+		switch key {
+		case "j", "Down":
 			if tocIdx < len(items)-1 {
 				tocIdx++
 			}
-		case b[0] == 'k' || (b[0] == 27 && b[1] == 91 && b[2] == 65): // k or up
+		case "k", "Up":
 			if tocIdx > 0 {
 				tocIdx--
 			}
-		case b[0] == 'g': // go to top
+		case "g", "Home": // go to top
 			tocIdx = 0
 			scrollOffset = 0
-		case b[0] == 'G': // go to bottom
-			tocIdx = len(items) - 1
-		case b[0] == 13 || b[0] == 10: // Enter - select
-			app.GotoSection(items[tocIdx].idx)
-			return
-		case b[0] == 'q' || b[0] == 'Q' || b[0] == 27: // q or Escape - close
+		case "G", "End": // go to bottom
+			tocIdx = max(0, len(items)-1)
+		case "Enter": // select, the top match after typing
+			if len(items) > 0 {
+				app.GotoSection(items[tocIdx].idx)
+				return
+			}
+		case "Tab", "Ctrl+r": // next filter, next order
+			selected := -1
+			if len(items) > 0 {
+				selected = items[tocIdx].idx
+			}
+			if key == "Tab" {
+				filter = (filter + 1) % TOCFilter(len(tocFilterNames))
+			} else {
+				order = (order + 1) % TOCOrder(len(tocOrderNames))
+			}
+			build(selected)
+			scrollOffset = 0
+		case "Backspace":
+			if query != "" {
+				r := []rune(query)
+				query = string(r[:len(r)-1])
+				build(-1)
+				scrollOffset = 0
+			}
+		case "Esc": // clear the query, or close
+			if query == "" {
+				return
+			}
+			query = ""
+			build(-1)
+			scrollOffset = 0
+		case "q", "Q", "Ctrl+c":
 			return
-		case b[0] == ' ': // Space - page down
-			tocIdx = min(tocIdx+maxVisible, len(items)-1)
+		case "Space": // page down
+			tocIdx = max(0, min(tocIdx+maxVisible, len(items)-1))
+		case "Paste":
+			query += pasteLine(keyboard.Pasted())
+			build(-1)
+			scrollOffset = 0
+		default:
+			if isTyped {
+				query += string(typed)
+				build(-1)
+				scrollOffset = 0
+			}
 		}
 	}
 }
@@ -64,8 +64,8 @@ func TestNewApp(t *testing.T) {
 		t.Errorf("Expected default FilePath 'learning-path-full.md', got '%s'", app.FilePath)
 	}
 
-	if app.StateFile != ".sre-learn-state" {
-		t.Errorf("Expected default StateFile '.sre-learn-state', got '%s'", app.StateFile)
+	if app.StateFile != "" {
+		t.Errorf("Expected StateFile to be resolved per document, got '%s'", app.StateFile)
 	}
 
 	if app.TermWidth != 80 {
@@ -118,6 +118,30 @@ func TestParseSectionsLevels(t *testing.T) {
 	}
 }
 
+func TestParseSectionsDepth(t *testing.T) {
+	doc := "## Phase\n\n#### Topic\n\n##### Lab\n\n- [ ] a\n\n###### Step\n\n- [x] b\n- [ ] c"
+	app := NewApp()
+	app.FileLines = strings.Split(doc, "\n")
+	app.ParseSections()
+	if len(app.Sections) != 4 || app.Sections[2].Level != 5 || app.Sections[3].Level != 6 {
+		t.Fatalf("Expected all six levels as sections, got %v", sectionTitles(app))
+	}
+	if checked, total := app.GetProgress(2); checked != 0 || total != 1 {
+		t.Errorf("Expected the level-5 section to count its own checkboxes, got %d/%d", checked, total)
+	}
+
+	saved := config
+	t.Cleanup(func() { config = saved })
+	config.SectionDepth = 4
+	app.ParseSections()
+	if len(app.Sections) != 2 || !strings.Contains(app.Sections[1].Content(), "###### Step") {
+		t.Fatalf("Expected deeper headers inline with section_depth=4, got %v", sectionTitles(app))
+	}
+	if checked, total := app.GetProgress(1); checked != 1 || total != 3 {
+		t.Errorf("Expected the inline sections' checkboxes in their parent, got %d/%d", checked, total)
+	}
+}
+
 // ============================================================================
 // Navigation Tests
 // ============================================================================
@@ -205,6 +229,133 @@ func TestGetCurrentSection(t *testing.T) {
 	}
 }
 
+func TestFindSection(t *testing.T) {
+	app := createTestApp()
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"1", 0},
+		{"3", 2},
+		{"99", -1},
+		{"0", -1},
+		{"exercise 1", 5},
+		{"Chapter", 2},
+		{"giai đoạn 2", 4},
+		{"missing", -1},
+		{"", -1},
+	}
+
+	for _, tt := range tests {
+		if got := app.FindSection(tt.query); got != tt.expected {
+			t.Errorf("FindSection(%q) = %d, expected %d", tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	app := createTestApp()
+
+	// "Chapter 2: Advanced" -> Giai đoạn 1 -> Main Title
+	ancestors := app.Ancestors(3)
+
+	if len(ancestors) != 2 {
+		t.Fatalf("Expected 2 ancestors, got %d", len(ancestors))
+	}
+
+	if app.Sections[ancestors[0]].Title != "Main Title" {
+		t.Errorf("Expected outermost ancestor 'Main Title', got '%s'", app.Sections[ancestors[0]].Title)
+	}
+
+	if app.Sections[ancestors[1]].Title != "Giai đoạn 1: Learning" {
+		t.Errorf("Expected parent 'Giai đoạn 1: Learning', got '%s'", app.Sections[ancestors[1]].Title)
+	}
+
+	if len(app.Ancestors(0)) != 0 {
+		t.Error("Expected no ancestors for top-level section")
+	}
+}
+
+func TestGotoAncestor(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 5 // Exercise 1
+
+	if !app.GotoAncestor(2) {
+		t.Fatal("Expected GotoAncestor(2) to succeed")
+	}
+
+	if app.Sections[app.CurrentIdx].Title != "Giai đoạn 2: Practice" {
+		t.Errorf("Expected 'Giai đoạn 2: Practice', got '%s'", app.Sections[app.CurrentIdx].Title)
+	}
+
+	if app.GotoAncestor(9) {
+		t.Error("Expected GotoAncestor to fail for missing ancestor")
+	}
+}
+
+func TestGotoParent(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 3 // Chapter 2
+
+	if !app.GotoParent() || app.CurrentIdx != 1 {
+		t.Errorf("Expected parent index 1, got %d", app.CurrentIdx)
+	}
+
+	app.CurrentIdx = 0
+	if app.GotoParent() {
+		t.Error("Expected GotoParent to fail at top level")
+	}
+}
+
+func TestSiblingNavigation(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 2 // Chapter 1 (level 3)
+
+	if !app.NextSibling() || app.CurrentIdx != 3 {
+		t.Errorf("Expected next sibling index 3, got %d", app.CurrentIdx)
+	}
+
+	// The last child of a phase does not go into the next phase
+	if app.NextSibling() || app.CurrentIdx != 3 {
+		t.Errorf("Expected NextSibling to stop at the last child, got %d", app.CurrentIdx)
+	}
+
+	if !app.PrevSibling() || app.CurrentIdx != 2 {
+		t.Errorf("Expected previous sibling index 2, got %d", app.CurrentIdx)
+	}
+	if app.PrevSibling() {
+		t.Error("Expected PrevSibling to fail at the first child")
+	}
+
+	app.CurrentIdx = 5 // Exercise 1, the only child of phase 2
+	if app.PrevSibling() || app.NextSibling() {
+		t.Error("Expected an only child to have no siblings")
+	}
+
+	app.CurrentIdx = 1 // Giai đoạn 1
+	if !app.NextSibling() || app.CurrentIdx != 4 {
+		t.Errorf("Expected the next phase as sibling, got %d", app.CurrentIdx)
+	}
+}
+
+func TestPhaseNavigation(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 2
+
+	if !app.NextPhase() || app.Sections[app.CurrentIdx].Title != "Giai đoạn 2: Practice" {
+		t.Errorf("Expected next phase 'Giai đoạn 2: Practice', got '%s'", app.Sections[app.CurrentIdx].Title)
+	}
+
+	if !app.PrevPhase() || app.Sections[app.CurrentIdx].Title != "Giai đoạn 1: Learning" {
+		t.Errorf("Expected previous phase 'Giai đoạn 1: Learning', got '%s'", app.Sections[app.CurrentIdx].Title)
+	}
+
+	if app.PrevPhase() {
+		t.Error("Expected PrevPhase to fail before first phase")
+	}
+}
+
 // ============================================================================
 // Search Tests
 // ============================================================================
@@ -256,7 +407,7 @@ func TestToggleCheckbox(t *testing.T) {
 
 	// Find a section with checkboxes
 	for i, sec := range app.Sections {
-		if strings.Contains(sec.Content, "- [ ]") {
+		if strings.Contains(sec.Content(), "- [ ]") {
 			app.CurrentIdx = i
 			break
 		}
@@ -269,13 +420,13 @@ func TestToggleCheckbox(t *testing.T) {
 	}
 
 	sec := app.GetCurrentSection()
-	initialUnchecked := strings.Count(sec.Content, "- [ ]")
+	initialUnchecked := strings.Count(sec.Content(), "- [ ]")
 
 	// Toggle the first actual checkbox line
 	app.ToggleCheckbox(checkboxLines[0])
 
 	sec = app.GetCurrentSection()
-	newUnchecked := strings.Count(sec.Content, "- [ ]")
+	newUnchecked := strings.Count(sec.Content(), "- [ ]")
 
 	if newUnchecked >= initialUnchecked {
 		t.Error("Expected checkbox to be toggled from unchecked to checked")
@@ -287,7 +438,7 @@ func TestGetCheckboxLines(t *testing.T) {
 
 	// Find a section with checkboxes
 	for i, sec := range app.Sections {
-		if strings.Contains(sec.Content, "- [ ]") || strings.Contains(sec.Content, "- [x]") {
+		if strings.Contains(sec.Content(), "- [ ]") || strings.Contains(sec.Content(), "- [x]") {
 			app.CurrentIdx = i
 			break
 		}
@@ -300,6 +451,74 @@ func TestGetCheckboxLines(t *testing.T) {
 	}
 }
 
+func TestAlternativeCheckboxSyntaxes(t *testing.T) {
+	app := NewApp()
+	app.FileLines = strings.Split("## Notion\n\n* [ ] star\n+ [X] plus upper\n- parent\n    - [x] nested\n> - [ ] quoted\n- [link](https://x)\n-[ ] no space", "\n")
+	app.ParseSections()
+
+	if lines := app.GetCheckboxLines(); len(lines) != 4 {
+		t.Errorf("Expected 4 checkbox lines, got %v", lines)
+	}
+	if checked, total := app.GetProgress(0); checked != 2 || total != 4 {
+		t.Errorf("GetProgress = %d/%d, want 2/4", checked, total)
+	}
+
+	// Toggling keeps the list marker and indentation
+	app.ToggleCheckbox(1)
+	app.ToggleCheckbox(2)
+	app.ToggleCheckbox(4)
+	content := app.Sections[0].Content()
+	for _, want := range []string{"* [x] star", "+ [ ] plus upper", "    - [ ] nested"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q in %q", want, content)
+		}
+	}
+	if got := checkboxText("    * [X] nested item"); got != "nested item" {
+		t.Errorf("checkboxText = %q", got)
+	}
+
+	result := RenderLine("  + [X] done", 80)
+	if !strings.HasPrefix(result, "  "+Green+glyphs.Checked) || strings.Contains(result, "[X]") {
+		t.Errorf("RenderLine = %q", result)
+	}
+}
+
+func TestCheckboxStates(t *testing.T) {
+	app := NewApp()
+	app.FileLines = strings.Split("## Lab\n- [ ] multi-day lab\n- [-] skipped tool\n- [~] reading\n- [x] done", "\n")
+	app.ParseSections()
+
+	if c := app.CountCheckboxes(0); c != (CheckboxCounts{Open: 1, InProgress: 1, Done: 1, WontDo: 1}) {
+		t.Errorf("CountCheckboxes = %+v", c)
+	}
+	// Won't-do items leave the total, in-progress ones are not checked
+	if checked, total := app.GetProgress(0); checked != 1 || total != 3 {
+		t.Errorf("GetProgress = %d/%d, want 1/3", checked, total)
+	}
+
+	// The toggle key cycles open -> in progress -> done -> open
+	for _, want := range []string{"- [~] multi-day lab", "- [x] multi-day lab", "- [ ] multi-day lab"} {
+		app.CycleCheckbox(0)
+		if line := strings.Split(app.Sections[0].Content(), "\n")[0]; line != want {
+			t.Errorf("line = %q, want %q", line, want)
+		}
+	}
+	// Only reaching and leaving done is recorded
+	if len(app.History) != 2 || !app.History[0].Checked || app.History[1].Checked {
+		t.Errorf("History = %+v", app.History)
+	}
+
+	app.SetCheckboxState(1, stateOpen)
+	app.ToggleCheckbox(2)
+	if content := app.Sections[0].Content(); !strings.Contains(content, "- [ ] skipped tool") || !strings.Contains(content, "- [x] reading") {
+		t.Errorf("content = %q", content)
+	}
+
+	if result := RenderLine("- [~] reading", 80); !strings.Contains(result, Yellow+glyphs.InProgress) {
+		t.Errorf("RenderLine = %q", result)
+	}
+}
+
 // ============================================================================
 // Note Tests
 // ============================================================================
@@ -309,21 +528,21 @@ func TestAddNote(t *testing.T) {
 	app.CurrentIdx = 0
 
 	sec := app.GetCurrentSection()
-	initialContent := sec.Content
+	initialContent := sec.Content()
 
 	app.AddNote("Test note content")
 
 	sec = app.GetCurrentSection()
 
-	if !strings.Contains(sec.Content, "Test note content") {
+	if !strings.Contains(sec.Content(), "Test note content") {
 		t.Error("Expected note to be added to content")
 	}
 
-	if !strings.Contains(sec.Content, "**Ghi chú [") {
+	if !strings.Contains(sec.Content(), "**Ghi chú [") {
 		t.Error("Expected note to have timestamp header")
 	}
 
-	if len(sec.Content) <= len(initialContent) {
+	if len(sec.Content()) <= len(initialContent) {
 		t.Error("Expected content to be longer after adding note")
 	}
 }
@@ -332,16 +551,36 @@ func TestAddNoteEmpty(t *testing.T) {
 	app := createTestApp()
 
 	sec := app.GetCurrentSection()
-	initialContent := sec.Content
+	initialContent := sec.Content()
 
 	app.AddNote("")
 
 	newSec := app.GetCurrentSection()
-	if newSec.Content != initialContent {
+	if newSec.Content() != initialContent {
 		t.Error("Expected no change for empty note")
 	}
 }
 
+func TestMultiLineNoteRoundTrip(t *testing.T) {
+	app := createTestApp()
+	note := "Lệnh hay dùng:\n\n```bash\nkubectl get pods -A\n  # indented\n```\n- **một** mục"
+
+	app.AddNote(note)
+	notes := extractNotes(app.GetCurrentSection().Content())
+	if len(notes) != 1 {
+		t.Fatalf("Expected 1 note, got %d: %q", len(notes), notes)
+	}
+	if got := noteBody(notes[0]); got != note {
+		t.Errorf("noteBody = %q, want %q", got, note)
+	}
+
+	// Editing removes the whole note, including its blank and code lines
+	content := removeNoteFromContent(app.GetCurrentSection().Content(), notes[0])
+	if strings.Contains(content, "kubectl") {
+		t.Errorf("Expected note to be removed, got %q", content)
+	}
+}
+
 func TestExtractNotes(t *testing.T) {
 	content := `Some content here.
 
@@ -410,7 +649,7 @@ func TestGetProgress(t *testing.T) {
 
 	// Find section with checkboxes
 	for i, sec := range app.Sections {
-		if strings.Contains(sec.Content, "- [x]") {
+		if strings.Contains(sec.Content(), "- [x]") {
 			app.CurrentIdx = i
 			checked, total := app.GetProgress(i)
 
@@ -430,6 +669,23 @@ func TestGetProgress(t *testing.T) {
 	}
 }
 
+func TestUpdateFileSectionGrowing(t *testing.T) {
+	app := createTestApp()
+	count := len(app.Sections)
+
+	app.Sections[2].SetContent(app.Sections[2].Content() + "\nextra 1\nextra 2\nextra 3")
+	app.UpdateFileSection(2)
+	app.ParseSections()
+
+	if len(app.Sections) != count {
+		t.Fatalf("Expected %d sections after growing one, got %d", count, len(app.Sections))
+	}
+
+	if app.Sections[3].Title != "Chapter 2: Advanced" {
+		t.Errorf("Expected next section header intact, got %q", app.Sections[3].Title)
+	}
+}
+
 func TestGetTotalProgress(t *testing.T) {
 	app := createTestApp()
 
@@ -631,6 +887,102 @@ func TestRendererScrollUpAtTop(t *testing.T) {
 	}
 }
 
+// createLongApp creates an App with a single section of n content lines.
+func createLongApp(n int) *App {
+	app := NewApp()
+	var content strings.Builder
+	content.WriteString("# Test")
+	for i := 0; i < n; i++ {
+		content.WriteString("\nLine " + strconv.Itoa(i))
+	}
+	app.FileContent = content.String()
+	app.FileLines = strings.Split(app.FileContent, "\n")
+	app.ParseSections()
+	return app
+}
+
+func TestRendererScrollStep(t *testing.T) {
+	renderer := NewRenderer(createLongApp(100))
+	renderer.PageSize = 10
+	renderer.ScrollStep = 7
+
+	renderer.ScrollDown()
+
+	if renderer.ScrollOffset != 7 {
+		t.Errorf("Expected ScrollOffset 7 with ScrollStep 7, got %d", renderer.ScrollOffset)
+	}
+}
+
+func TestRendererScrollByClamps(t *testing.T) {
+	renderer := NewRenderer(createLongApp(100))
+	renderer.PageSize = 10
+
+	renderer.ScrollBy(1000)
+	if renderer.ScrollOffset != 90 {
+		t.Errorf("Expected ScrollOffset clamped to 90, got %d", renderer.ScrollOffset)
+	}
+
+	if renderer.ScrollBy(5) {
+		t.Error("Expected ScrollBy to report no change at bottom")
+	}
+
+	renderer.ScrollBy(-1000)
+	if renderer.ScrollOffset != 0 {
+		t.Errorf("Expected ScrollOffset clamped to 0, got %d", renderer.ScrollOffset)
+	}
+}
+
+func TestRendererScrollToBottomAndTop(t *testing.T) {
+	renderer := NewRenderer(createLongApp(50))
+	renderer.PageSize = 20
+
+	renderer.ScrollToBottom()
+	if renderer.ScrollOffset != 30 {
+		t.Errorf("Expected ScrollOffset 30 at bottom, got %d", renderer.ScrollOffset)
+	}
+
+	renderer.ScrollToTop()
+	if renderer.ScrollOffset != 0 {
+		t.Errorf("Expected ScrollOffset 0 at top, got %d", renderer.ScrollOffset)
+	}
+}
+
+func TestRendererBookModeFlowsAcrossSections(t *testing.T) {
+	app := createTestApp()
+	renderer := NewRenderer(app)
+	renderer.PageSize = 5
+	renderer.BookMode = true
+
+	// Scroll down until the first section is exhausted
+	for app.CurrentIdx == 0 {
+		if !renderer.ScrollDown() {
+			t.Fatal("Expected book mode to flow into the next section")
+		}
+	}
+
+	if app.CurrentIdx != 1 || renderer.ScrollOffset != 0 {
+		t.Errorf("Expected top of section 1, got section %d offset %d", app.CurrentIdx, renderer.ScrollOffset)
+	}
+
+	// Scrolling up flows back to the bottom of the previous section
+	renderer.ScrollUp()
+	if app.CurrentIdx != 0 || renderer.ScrollOffset != renderer.maxScrollOffset() {
+		t.Errorf("Expected bottom of section 0, got section %d offset %d", app.CurrentIdx, renderer.ScrollOffset)
+	}
+}
+
+func TestRendererWithoutBookModeStaysInSection(t *testing.T) {
+	app := createTestApp()
+	renderer := NewRenderer(app)
+
+	renderer.ScrollDown()
+	renderer.ScrollDown()
+
+	if app.CurrentIdx != 0 {
+		t.Errorf("Expected to stay in section 0 without book mode, got %d", app.CurrentIdx)
+	}
+}
+
 func TestRendererResetScroll(t *testing.T) {
 	app := createTestApp()
 	renderer := NewRenderer(app)
@@ -722,7 +1074,7 @@ func TestCheckboxWorkflow(t *testing.T) {
 
 	// Find section with unchecked items
 	for i, sec := range app.Sections {
-		if strings.Contains(sec.Content, "- [ ]") {
+		if strings.Contains(sec.Content(), "- [ ]") {
 			app.CurrentIdx = i
 
 			// Get actual checkbox line indices
@@ -793,11 +1145,11 @@ func TestSpecialCharactersInContent(t *testing.T) {
 		t.Fatal("Expected section to be parsed")
 	}
 
-	if !strings.Contains(app.Sections[0].Content, "🎉") {
+	if !strings.Contains(app.Sections[0].Content(), "🎉") {
 		t.Error("Expected emoji to be preserved")
 	}
 
-	if !strings.Contains(app.Sections[0].Content, "Việt Nam") {
+	if !strings.Contains(app.Sections[0].Content(), "Việt Nam") {
 		t.Error("Expected Vietnamese characters to be preserved")
 	}
 }
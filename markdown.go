@@ -0,0 +1,453 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineKind classifies a markdown line at block level.
+type LineKind int
+
+const (
+	// LineText is any line outside code blocks that is not a header or blank
+	LineText LineKind = iota
+	// LineBlank is an empty or whitespace-only line outside code blocks
+	LineBlank
+	// LineHeader is an ATX header (# to ######)
+	LineHeader
+	// LineFenceOpen opens a fenced code block (``` or ~~~)
+	LineFenceOpen
+	// LineFenceClose closes a fenced code block
+	LineFenceClose
+	// LineCode is a line inside a fenced code block
+	LineCode
+	// LineSetextUnderline is the === or --- line under a setext header
+	LineSetextUnderline
+	// LineMathFence opens or closes a $$ display math block
+	LineMathFence
+	// LineMath is a line inside a $$ display math block
+	LineMath
+)
+
+// MarkdownLine is the block-level classification of one source line.
+type MarkdownLine struct {
+	Kind LineKind
+	// Level is the header depth for LineHeader
+	Level int
+	// Text is the header title for LineHeader and the info string
+	// (e.g. "bash") for LineFenceOpen
+	Text string
+	// ID is the {#id} attribute of a LineHeader, if any
+	ID string
+	// Setext marks a LineHeader underlined on the next line
+	Setext bool
+}
+
+// atxHeaderRegex matches "# Title" through "###### Title".
+var atxHeaderRegex = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*$`)
+
+// setextUnderlineRegex matches a setext underline: === (level 1) or --- (level 2).
+var setextUnderlineRegex = regexp.MustCompile(`^ {0,3}(={3,}|-{3,})\s*$`)
+
+// headerIDRegex matches a trailing {#custom-id} header attribute.
+var headerIDRegex = regexp.MustCompile(`\s*\{#([A-Za-z0-9][\w.:-]*)\}$`)
+
+// splitHeaderID separates a trailing {#id} attribute from a header title.
+func splitHeaderID(text string) (title, id string) {
+	if m := headerIDRegex.FindStringSubmatchIndex(text); m != nil {
+		return text[:m[0]], text[m[2]:m[3]]
+	}
+	return text, ""
+}
+
+// setextCandidate reports whether a text line may be turned into a setext
+// header by the following underline: a single-line paragraph that is not a
+// list item, quote or table row (so "---" under those stays a rule).
+func setextCandidate(scanned []MarkdownLine, lines []string, i int) bool {
+	if i < 0 || scanned[i].Kind != LineText {
+		return false
+	}
+	if i > 0 {
+		switch scanned[i-1].Kind {
+		case LineText, LineCode, LineFenceOpen:
+			return false
+		}
+	}
+	trimmed := strings.TrimSpace(lines[i])
+	for _, prefix := range []string{"- ", "* ", "+ ", ">", "|"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return false
+		}
+	}
+	return !numberedListRegex.MatchString(lines[i])
+}
+
+// ScanLines classifies lines with a small state machine. Lines inside
+// fenced code blocks are never headers, so "# comment" in a shell snippet
+// does not start a section. A closing fence uses the same character as
+// the opening one, at least as many times, and nothing else; an
+// unterminated block runs to the end. A "$$" line opens or closes a
+// display math block, whose lines are likewise never headers.
+func ScanLines(lines []string) []MarkdownLine {
+	result := make([]MarkdownLine, len(lines))
+	fence := ""
+	inMath := false
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		run := fenceRun(trimmed)
+
+		if fence != "" {
+			if run != "" && run[0] == fence[0] && len(run) >= len(fence) && run == trimmed {
+				result[i] = MarkdownLine{Kind: LineFenceClose}
+				fence = ""
+			} else {
+				result[i] = MarkdownLine{Kind: LineCode}
+			}
+			continue
+		}
+
+		if trimmed == "$$" {
+			result[i] = MarkdownLine{Kind: LineMathFence}
+			inMath = !inMath
+			continue
+		}
+		if inMath {
+			result[i] = MarkdownLine{Kind: LineMath}
+			continue
+		}
+
+		switch {
+		case len(run) >= 3:
+			fence = run
+			result[i] = MarkdownLine{Kind: LineFenceOpen, Text: strings.TrimSpace(trimmed[len(run):])}
+		case trimmed == "":
+			result[i] = MarkdownLine{Kind: LineBlank}
+		case setextUnderlineRegex.MatchString(line) && setextCandidate(result, lines, i-1):
+			level := 1
+			if trimmed[0] == '-' {
+				level = 2
+			}
+			title, id := splitHeaderID(strings.TrimSpace(lines[i-1]))
+			result[i-1] = MarkdownLine{Kind: LineHeader, Level: level, Text: title, ID: id, Setext: true}
+			result[i] = MarkdownLine{Kind: LineSetextUnderline}
+		default:
+			if m := atxHeaderRegex.FindStringSubmatch(line); m != nil {
+				title, id := splitHeaderID(m[2])
+				result[i] = MarkdownLine{Kind: LineHeader, Level: len(m[1]), Text: title, ID: id}
+			} else {
+				result[i] = MarkdownLine{Kind: LineText}
+			}
+		}
+	}
+	return result
+}
+
+// footnoteDefRegex matches a footnote definition line "[^label]: text".
+var footnoteDefRegex = regexp.MustCompile(`^\[\^([^\]\s]+)\]:\s*(.*)$`)
+
+// footnoteRefRegex matches a footnote reference "[^label]".
+var footnoteRefRegex = regexp.MustCompile(`\[\^([^\]\s]+)\]`)
+
+// footnoteMarker renders footnote number n as a superscript marker.
+func footnoteMarker(n int) string {
+	marker, _ := scriptRunes(strconv.Itoa(n), superscripts)
+	return Cyan + marker + Reset
+}
+
+// RenderLines renders a block of markdown lines, one output line per input
+// line. Code block lines are shown verbatim in code style instead of being
+// interpreted as markdown; headers inside content are shown in bold and
+// display math is converted to Unicode.
+//
+// Footnote references ([^label]) are numbered in order of appearance.
+// Definition lines render empty in place, keeping line indexes stable,
+// and the footnote bodies are appended after the last line.
+func RenderLines(lines []string, termWidth int) []string {
+	scanned := ScanLines(lines)
+
+	// Number footnotes by first reference, then unreferenced definitions
+	numbers := map[string]int{}
+	defs := map[string]string{}
+	var order, defLabels []string
+	number := func(label string) {
+		if _, ok := numbers[label]; !ok {
+			order = append(order, label)
+			numbers[label] = len(order)
+		}
+	}
+	for i, ml := range scanned {
+		if ml.Kind != LineText {
+			continue
+		}
+		if m := footnoteDefRegex.FindStringSubmatch(lines[i]); m != nil {
+			defs[m[1]] = m[2]
+			defLabels = append(defLabels, m[1])
+			continue
+		}
+		for _, m := range footnoteRefRegex.FindAllStringSubmatch(lines[i], -1) {
+			number(m[1])
+		}
+	}
+	for _, label := range defLabels {
+		number(label)
+	}
+
+	rendered := make([]string, len(lines))
+	for i, ml := range scanned {
+		switch ml.Kind {
+		case LineFenceOpen, LineFenceClose, LineSetextUnderline, LineMathFence:
+			rendered[i] = Dim + lines[i] + Reset
+		case LineMath:
+			rendered[i] = "    " + renderMath(lines[i])
+		case LineCode:
+			rendered[i] = Cyan + lines[i] + Reset
+		case LineHeader:
+			rendered[i] = Bold + renderInline(ml.Text) + Reset
+		default:
+			if footnoteDefRegex.MatchString(lines[i]) {
+				continue
+			}
+			line := footnoteRefRegex.ReplaceAllStringFunc(lines[i], func(ref string) string {
+				return footnoteMarker(numbers[ref[2:len(ref)-1]])
+			})
+			rendered[i] = RenderLine(line, termWidth)
+		}
+	}
+
+	renderQuotes(scanned, lines, rendered, termWidth)
+
+	if len(defs) > 0 {
+		rendered = append(rendered, "", Dim+strings.Repeat(glyphs.Rule, 20)+Reset)
+		for _, label := range order {
+			if text, ok := defs[label]; ok {
+				rendered = append(rendered, footnoteMarker(numbers[label])+" "+renderInline(text))
+			}
+		}
+	}
+	return rendered
+}
+
+// renderQuotes re-renders each run of blockquote lines (notes included)
+// as markdown of its own, so bold, code spans, lists and fenced code
+// inside a quote are styled like in the section, behind a quote bar.
+func renderQuotes(scanned []MarkdownLine, lines, rendered []string, termWidth int) {
+	for i := 0; i < len(lines); i++ {
+		if scanned[i].Kind != LineText || !strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+			continue
+		}
+		start := i
+		var inner []string
+		for ; i < len(lines) && scanned[i].Kind == LineText && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+			text := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+			inner = append(inner, strings.TrimPrefix(text, " "))
+		}
+		for k, line := range RenderLines(inner, termWidth-2)[:len(inner)] {
+			rendered[start+k] = Dim + glyphs.Vertical + Reset + " " + line
+		}
+	}
+}
+
+// inlineStyle returns the ANSI style of an emphasis delimiter. Colors
+// are looked up on each call, as the theme may change them.
+func inlineStyle(delim string) string {
+	switch delim {
+	case "**":
+		return Bold
+	case "*":
+		return Italic
+	case "~~":
+		return Strike
+	case "==":
+		return BgYellow + Black
+	}
+	return ""
+}
+
+// renderInline styles inline markdown: `code`, **bold**, *italic*,
+// ~~strikethrough~~, ==highlight== and $math$ (or $$math$$).
+// Emphasis may nest (e.g. **bold *and italic***); when an inner style is
+// closed, the outer ones are re-applied. Delimiters without a matching
+// closer, code span and math contents and backslash-escaped characters
+// stay literal to the emphasis rules.
+func renderInline(text string) string {
+	var out strings.Builder
+	var open []string // delimiters of the currently open styles
+
+	// After a Reset the outer styles are re-applied lazily, right before
+	// the next visible character, so consecutive closers stay compact
+	dirty := false
+	write := func(s string) {
+		if dirty {
+			for _, d := range open {
+				out.WriteString(inlineStyle(d))
+			}
+			dirty = false
+		}
+		out.WriteString(s)
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("*`_$\\", text[i+1]) >= 0:
+			write(text[i+1 : i+2])
+			i += 2
+
+		case c == '`':
+			run := backtickRun(text[i:])
+			end := strings.Index(text[i+len(run):], run)
+			if end < 0 {
+				write(run)
+				i += len(run)
+				continue
+			}
+			write(BgBlack + Cyan + text[i+len(run):i+len(run)+end] + Reset)
+			dirty = true
+			i += len(run) + end + len(run)
+
+		case c == '[' && internalLinkRegex.MatchString(text[i:]):
+			m := internalLinkRegex.FindStringSubmatch(text[i:])
+			write(Underline + Blue + m[1] + Reset + Dim + glyphs.Link + Reset)
+			dirty = true
+			i += len(m[0])
+
+		case c == '$':
+			if strings.HasPrefix(text[i:], "$$") {
+				if end := strings.Index(text[i+2:], "$$"); end > 0 {
+					write(renderMath(text[i+2 : i+2+end]))
+					dirty = true
+					i += 2 + end + 2
+					continue
+				}
+			} else if end := inlineMathEnd(text, i); end > 0 {
+				write(renderMath(text[i+1 : end]))
+				dirty = true
+				i = end + 1
+				continue
+			}
+			write("$")
+			i++
+
+		case c == '*':
+			delim := "*"
+			if strings.HasPrefix(text[i:], "**") {
+				delim = "**"
+			}
+			// A "***" closer that ends an inner "*" is read as "*" then "**"
+			if strings.HasPrefix(text[i:], "***") && len(open) > 0 && open[len(open)-1] == "*" {
+				delim = "*"
+			}
+
+			if n := len(open); n > 0 && open[n-1] == delim {
+				open = open[:n-1]
+				if !dirty {
+					out.WriteString(Reset)
+				}
+				dirty = true
+			} else if canOpenEmphasis(text, i, delim) {
+				write(inlineStyle(delim))
+				open = append(open, delim)
+			} else {
+				write(delim)
+			}
+			i += len(delim)
+
+		case (c == '~' || c == '=') && strings.HasPrefix(text[i+1:], string(c)):
+			delim := text[i : i+2]
+			if n := len(open); n > 0 && open[n-1] == delim {
+				open = open[:n-1]
+				if !dirty {
+					out.WriteString(Reset)
+				}
+				dirty = true
+			} else if canOpenPair(text, i, delim) {
+				write(inlineStyle(delim))
+				open = append(open, delim)
+			} else {
+				write(delim)
+			}
+			i += len(delim)
+
+		default:
+			write(text[i : i+1])
+			i++
+		}
+	}
+
+	if len(open) > 0 && !dirty {
+		out.WriteString(Reset)
+	}
+	return out.String()
+}
+
+// backtickRun returns the leading run of backticks of s.
+func backtickRun(s string) string {
+	n := 0
+	for n < len(s) && s[n] == '`' {
+		n++
+	}
+	return s[:n]
+}
+
+// canOpenPair reports whether a ~~ or == delimiter at text[i] opens a
+// span: it must be followed by a non-space character and closed later by
+// the same delimiter preceded by a non-space character.
+func canOpenPair(text string, i int, delim string) bool {
+	start := i + len(delim)
+	if start >= len(text) || text[start] == ' ' || text[start] == '\t' || text[start] == delim[0] {
+		return false
+	}
+	for j := start + 1; j+len(delim) <= len(text); j++ {
+		if text[j:j+len(delim)] == delim && text[j-1] != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+// canOpenEmphasis reports whether delim at text[i] starts emphasis: it must
+// be followed by a non-space character and have a matching closer later on
+// the line (outside code and math spans).
+func canOpenEmphasis(text string, i int, delim string) bool {
+	start := i + len(delim)
+	if start >= len(text) || text[start] == ' ' || text[start] == '\t' {
+		return false
+	}
+
+	for j := start; j < len(text); {
+		switch {
+		case text[j] == '\\':
+			j += 2
+		case text[j] == '`':
+			run := backtickRun(text[j:])
+			end := strings.Index(text[j+len(run):], run)
+			if end < 0 {
+				j += len(run)
+			} else {
+				j += len(run) + end + len(run)
+			}
+		case text[j] == '$':
+			if end := inlineMathEnd(text, j); end > 0 {
+				j = end + 1
+			} else {
+				j++
+			}
+		case text[j] == '*':
+			n := 0
+			for j+n < len(text) && text[j+n] == '*' {
+				n++
+			}
+			// A closer is not preceded by a space
+			if text[j-1] != ' ' && (n == len(delim) || n >= 3) {
+				return true
+			}
+			j += n
+		default:
+			j++
+		}
+	}
+	return false
+}
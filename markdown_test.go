@@ -0,0 +1,243 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite markdown fixture golden files")
+
+// ============================================================================
+// Markdown Scanner Tests
+// ============================================================================
+
+func TestScanLines(t *testing.T) {
+	lines := []string{"# Title", "", "```bash", "# comment", "```", "text", "#nospace"}
+	kinds := []LineKind{LineHeader, LineBlank, LineFenceOpen, LineCode, LineFenceClose, LineText, LineText}
+
+	scanned := ScanLines(lines)
+	for i, ml := range scanned {
+		if ml.Kind != kinds[i] {
+			t.Errorf("Line %d %q: expected kind %d, got %d", i, lines[i], kinds[i], ml.Kind)
+		}
+	}
+
+	if scanned[0].Level != 1 || scanned[0].Text != "Title" {
+		t.Errorf("Unexpected header: %+v", scanned[0])
+	}
+
+	if scanned[2].Text != "bash" {
+		t.Errorf("Expected fence info 'bash', got %q", scanned[2].Text)
+	}
+}
+
+func TestParseSectionsIgnoresFencedHeaders(t *testing.T) {
+	app := NewApp()
+	app.FileLines = strings.Split("# A\n```bash\n# comment\n```\n## B\n", "\n")
+	app.ParseSections()
+
+	if len(app.Sections) != 2 || app.Sections[1].Title != "B" {
+		t.Errorf("Expected sections A and B, got %v", sectionTitles(app))
+	}
+}
+
+func TestRenderInline(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"**b**", Bold + "b" + Reset},
+		{"*i*", Italic + "i" + Reset},
+		{"**b *i* b**", Bold + "b " + Italic + "i" + Reset + Bold + " b" + Reset},
+		{"*i **b** i*", Italic + "i " + Bold + "b" + Reset + Italic + " i" + Reset},
+		{"`**x**`", BgBlack + Cyan + "**x**" + Reset},
+		{"5 * 3 * 2", "5 * 3 * 2"},
+		{`\*x\*`, "*x*"},
+		{"**open", "**open"},
+		{"~~old~~", Strike + "old" + Reset},
+		{"==key==", BgYellow + Black + "key" + Reset},
+		{"a == b and c ~~ d", "a == b and c ~~ d"},
+	}
+
+	for _, tt := range tests {
+		if got := renderInline(tt.input); got != tt.expected {
+			t.Errorf("renderInline(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestRenderLinesCodeBlock(t *testing.T) {
+	rendered := RenderLines([]string{"```", "- [ ] not a checkbox", "```"}, 80)
+
+	if strings.Contains(rendered[1], "☐") {
+		t.Error("Expected code block line rendered verbatim")
+	}
+}
+
+func TestHeaderIDAndSetextPreserved(t *testing.T) {
+	app := NewApp()
+	app.FileLines = strings.Split("Guide {#guide}\n=====\n\nIntro\n\n## Week 1 {#week-1}\n\n- [ ] Task\n", "\n")
+	app.ParseSections()
+
+	if app.Sections[0].ID != "guide" || !app.Sections[0].Setext || app.Sections[0].Title != "Guide" {
+		t.Fatalf("Unexpected setext section: %+v", app.Sections[0])
+	}
+
+	app.CurrentIdx = 0
+	app.AddNote("note")
+	app.UpdateFileSection(0)
+	app.CurrentIdx = 1
+	app.ToggleCheckbox(app.GetCheckboxLines()[0])
+	app.UpdateFileSection(1)
+	app.ParseSections()
+
+	if app.FileLines[0] != "Guide {#guide}" || !strings.HasPrefix(app.FileLines[1], "===") {
+		t.Errorf("Expected setext header with id preserved, got %q / %q", app.FileLines[0], app.FileLines[1])
+	}
+
+	if !strings.Contains(app.FileContent, "## Week 1 {#week-1}") {
+		t.Error("Expected ATX header id preserved")
+	}
+
+	if app.FindSection("#week-1") != 1 || app.FindSection("#missing") != -1 {
+		t.Error("Expected FindSection to resolve header ids")
+	}
+}
+
+func TestRenderLinesFootnotes(t *testing.T) {
+	lines := []string{"See[^b] and[^a].", "[^a]: Alpha", "[^b]: Beta"}
+	rendered := RenderLines(lines, 80)
+
+	if !strings.Contains(rendered[0], "¹") || !strings.Contains(rendered[0], "²") {
+		t.Errorf("Expected numbered references, got %q", rendered[0])
+	}
+
+	if rendered[1] != "" || rendered[2] != "" {
+		t.Error("Expected definition lines rendered empty in place")
+	}
+
+	if len(rendered) != 7 || !strings.Contains(rendered[5], "Beta") || !strings.Contains(rendered[6], "Alpha") {
+		t.Errorf("Expected footnotes appended in reference order, got %q", rendered[3:])
+	}
+}
+
+// ============================================================================
+// Markdown Fixture Tests
+// ============================================================================
+
+// ansiTags makes rendered output readable in golden files.
+var ansiTags = strings.NewReplacer(
+	Reset, "</>", Bold, "<b>", Dim, "<dim>", Italic, "<i>", Strike, "<s>",
+	BgYellow+Black, "<mark>",
+	Red, "<red>", Green, "<green>", Yellow, "<yellow>", Cyan, "<cyan>",
+	Magenta, "<magenta>", BgBlack, "<bg>",
+)
+
+// fixtureDepths are the section_depth settings of the fixtures parsed
+// with one; the others use the default. mixed.md keeps its ##### heading
+// inline in the section content.
+var fixtureDepths = map[string]int{"mixed.md": 4}
+
+// fixtureOutput describes how a fixture document is parsed and rendered
+// with the given section_depth (0 for the default).
+func fixtureOutput(content string, depth int) (sections, rendered string) {
+	saved := config
+	config.SectionDepth = depth
+	defer func() { config = saved }()
+
+	app := NewApp()
+	app.FileLines = strings.Split(content, "\n")
+	app.ParseSections()
+
+	var s, r strings.Builder
+	for i, sec := range app.Sections {
+		checked, total := app.GetProgress(i)
+		fmt.Fprintf(&s, "L%d %s [%d/%d]", sec.Level, sec.Title, checked, total)
+		if sec.ID != "" {
+			fmt.Fprintf(&s, " #%s", sec.ID)
+		}
+		if sec.Setext {
+			s.WriteString(" (setext)")
+		}
+		s.WriteString("\n")
+
+		fmt.Fprintf(&r, "== %s\n", sec.Title)
+		for _, line := range RenderLines(strings.Split(sec.Content(), "\n"), 40) {
+			r.WriteString(ansiTags.Replace(line) + "\n")
+		}
+	}
+	return s.String(), r.String()
+}
+
+// checkGolden compares got with the golden file, rewriting it with -update.
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Missing golden file (run go test -update): %v", err)
+	}
+	if string(want) != got {
+		t.Errorf("%s mismatch:\n--- want\n%s\n--- got\n%s", path, want, got)
+	}
+}
+
+func TestMarkdownFixtures(t *testing.T) {
+	fixtures, _ := filepath.Glob(filepath.Join("testdata", "markdown", "*.md"))
+	if len(fixtures) == 0 {
+		t.Fatal("Expected markdown fixtures")
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			data, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sections, rendered := fixtureOutput(string(data), fixtureDepths[filepath.Base(fixture)])
+			base := strings.TrimSuffix(fixture, ".md")
+			checkGolden(t, base+".sections", sections)
+			checkGolden(t, base+".render", rendered)
+		})
+	}
+}
+
+func TestRenderLinesQuotedMarkdown(t *testing.T) {
+	lines := []string{
+		"> **Ghi chú [2025-01-01 10:00]:** dùng `kubectl`",
+		">",
+		"> ```bash",
+		"> # not a header",
+		"> ```",
+		"> - mục",
+	}
+	rendered := RenderLines(lines, 80)
+	if len(rendered) != len(lines) {
+		t.Fatalf("Expected %d lines, got %d", len(lines), len(rendered))
+	}
+	for i, line := range rendered {
+		if !strings.HasPrefix(line, Dim+glyphs.Vertical+Reset+" ") {
+			t.Errorf("line %d missing quote bar: %q", i, line)
+		}
+	}
+	if !strings.Contains(rendered[0], Bold+"Ghi chú") || !strings.Contains(rendered[0], BgBlack+Cyan+"kubectl") {
+		t.Errorf("inline markdown not rendered: %q", rendered[0])
+	}
+	if !strings.Contains(rendered[3], Cyan+"# not a header") {
+		t.Errorf("code inside the quote should render as code: %q", rendered[3])
+	}
+	if !strings.Contains(rendered[5], glyphs.Bullet) {
+		t.Errorf("list inside the quote should render as a bullet: %q", rendered[5])
+	}
+}
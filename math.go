@@ -0,0 +1,221 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// mathSymbols maps LaTeX commands to Unicode.
+var mathSymbols = map[string]string{
+	// Greek letters
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε",
+	"varepsilon": "ε", "zeta": "ζ", "eta": "η", "theta": "θ", "iota": "ι",
+	"kappa": "κ", "lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "pi": "π",
+	"rho": "ρ", "sigma": "σ", "tau": "τ", "upsilon": "υ", "phi": "φ",
+	"varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ",
+	"Pi": "Π", "Sigma": "Σ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+
+	// Operators and relations
+	"times": "×", "cdot": "·", "div": "÷", "pm": "±", "mp": "∓",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠",
+	"approx": "≈", "sim": "∼", "equiv": "≡", "propto": "∝",
+	"infty": "∞", "sum": "∑", "prod": "∏", "int": "∫", "partial": "∂",
+	"in": "∈", "notin": "∉", "subset": "⊂", "cup": "∪", "cap": "∩",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "Rightarrow": "⇒",
+	"forall": "∀", "exists": "∃", "neg": "¬", "land": "∧", "lor": "∨",
+	"ldots": "…", "cdots": "⋯", "dots": "…", "log": "log", "ln": "ln",
+	"min": "min", "max": "max", "exp": "exp",
+
+	// Spacing
+	"quad": "  ", "qquad": "    ",
+}
+
+// superscripts and subscripts map characters to their Unicode forms.
+var (
+	superscripts = map[rune]rune{
+		'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶',
+		'7': '⁷', '8': '⁸', '9': '⁹', '+': '⁺', '-': '⁻', '=': '⁼', '(': '⁽',
+		')': '⁾', 'n': 'ⁿ', 'i': 'ⁱ', 'x': 'ˣ', 'k': 'ᵏ', 'T': 'ᵀ',
+	}
+	subscripts = map[rune]rune{
+		'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆',
+		'7': '₇', '8': '₈', '9': '₉', '+': '₊', '-': '₋', '=': '₌', '(': '₍',
+		')': '₎', 'i': 'ᵢ', 'j': 'ⱼ', 'k': 'ₖ', 'n': 'ₙ', 'x': 'ₓ', 'e': 'ₑ',
+		'a': 'ₐ', 'o': 'ₒ', 't': 'ₜ', 'm': 'ₘ', 'p': 'ₚ', 's': 'ₛ',
+	}
+)
+
+// vulgarFractions maps simple fractions to single characters.
+var vulgarFractions = map[string]string{
+	"1/2": "½", "1/3": "⅓", "2/3": "⅔", "1/4": "¼", "3/4": "¾",
+	"1/5": "⅕", "1/8": "⅛", "1/10": "⅒",
+}
+
+// scriptRunes converts s with table, reporting false if any rune has no
+// script form.
+func scriptRunes(s string, table map[rune]rune) (string, bool) {
+	var out strings.Builder
+	for _, r := range s {
+		mapped, ok := table[r]
+		if !ok {
+			return "", false
+		}
+		out.WriteRune(mapped)
+	}
+	return out.String(), true
+}
+
+// mathGroup reads a {…} group or a single character at expr[i:].
+// Returns the group content and the index after it.
+func mathGroup(expr string, i int) (string, int) {
+	for i < len(expr) && expr[i] == ' ' {
+		i++
+	}
+	if i >= len(expr) {
+		return "", i
+	}
+	if expr[i] != '{' {
+		if expr[i] == '\\' {
+			j := i + 1
+			for j < len(expr) && unicode.IsLetter(rune(expr[j])) {
+				j++
+			}
+			return expr[i:max(j, i+2)], max(j, i+2)
+		}
+		return expr[i : i+1], i + 1
+	}
+
+	depth := 0
+	for j := i; j < len(expr); j++ {
+		switch expr[j] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return expr[i+1 : j], j + 1
+			}
+		}
+	}
+	return expr[i+1:], len(expr)
+}
+
+// parenthesize wraps converted math in parentheses unless it is a single
+// term (no spaces or operators).
+func parenthesize(s string) string {
+	if strings.ContainsAny(s, " +-−×·/=") && len([]rune(s)) > 1 {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// latexToUnicode converts a simple LaTeX math expression to Unicode text:
+// Greek letters and operators become symbols, ^ and _ become super- and
+// subscripts where Unicode has them, \frac becomes a/b (or ½ etc.) and
+// \sqrt becomes √. Unknown commands are kept without the backslash.
+func latexToUnicode(expr string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == '\\' && i+1 < len(expr) && unicode.IsLetter(rune(expr[i+1])):
+			j := i + 1
+			for j < len(expr) && unicode.IsLetter(rune(expr[j])) {
+				j++
+			}
+			name := expr[i+1 : j]
+			i = j
+
+			switch name {
+			case "frac", "dfrac", "tfrac":
+				var num, den string
+				num, i = mathGroup(expr, i)
+				den, i = mathGroup(expr, i)
+				num, den = latexToUnicode(num), latexToUnicode(den)
+				if frac, ok := vulgarFractions[num+"/"+den]; ok {
+					out.WriteString(frac)
+				} else {
+					out.WriteString(parenthesize(num) + "/" + parenthesize(den))
+				}
+			case "sqrt":
+				var arg string
+				arg, i = mathGroup(expr, i)
+				out.WriteString("√" + parenthesize(latexToUnicode(arg)))
+			case "text", "mathrm", "mathbf", "mathit", "textbf", "operatorname":
+				var arg string
+				arg, i = mathGroup(expr, i)
+				out.WriteString(latexToUnicode(arg))
+			case "left", "right":
+				// Sizing only; the delimiter itself follows
+			default:
+				if sym, ok := mathSymbols[name]; ok {
+					out.WriteString(sym)
+				} else {
+					out.WriteString(name)
+				}
+			}
+
+		case c == '\\' && i+1 < len(expr):
+			switch expr[i+1] {
+			case ',', ';', ':', ' ':
+				out.WriteByte(' ')
+			case '!':
+			default:
+				out.WriteByte(expr[i+1])
+			}
+			i += 2
+
+		case c == '^' || c == '_':
+			var arg string
+			arg, i = mathGroup(expr, i+1)
+			arg = latexToUnicode(arg)
+			table := superscripts
+			if c == '_' {
+				table = subscripts
+			}
+			if script, ok := scriptRunes(arg, table); ok {
+				out.WriteString(script)
+			} else {
+				out.WriteString(string(c) + parenthesize(arg))
+			}
+
+		case c == '{' || c == '}':
+			i++
+
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String()
+}
+
+// renderMath styles a converted math expression.
+func renderMath(expr string) string {
+	return Magenta + latexToUnicode(strings.TrimSpace(expr)) + Reset
+}
+
+// inlineMathEnd returns the index of the $ closing inline math opened at
+// text[i], or -1. Like pandoc, the opening $ must be followed by a
+// non-space and the closing one preceded by a non-space and not followed
+// by a digit, so prices such as "$5 and $10" stay literal. Math never
+// spans into a code span.
+func inlineMathEnd(text string, i int) int {
+	start := i + 1
+	if start >= len(text) || text[start] == ' ' || text[start] == '$' {
+		return -1
+	}
+	for j := start + 1; j < len(text); j++ {
+		switch {
+		case text[j] == '\\':
+			j++
+		case text[j] == '`':
+			return -1
+		case text[j] == '$' && text[j-1] != ' ' && (j+1 >= len(text) || text[j+1] < '0' || text[j+1] > '9'):
+			return j
+		}
+	}
+	return -1
+}
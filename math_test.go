@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Math Rendering Tests
+// ============================================================================
+
+func TestLatexToUnicode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`\alpha + \beta`, "α + β"},
+		{`x^2 + y^{10}`, "x² + y¹⁰"},
+		{`a_i + a_{n+1}`, "aᵢ + aₙ₊₁"},
+		{`\frac{1}{2}`, "½"},
+		{`\frac{a+b}{c}`, "(a+b)/c"},
+		{`\sqrt{2}`, "√2"},
+		{`\sqrt{x + 1}`, "√(x + 1)"},
+		{`e^{-\lambda t}`, "e^(-λ t)"},
+		{`1 - \frac{\text{errors}}{\text{requests}} \geq 99.9\%`, "1 - errors/requests ≥ 99.9%"},
+		{`\sum_{i=1}^{n} x_i`, "∑ᵢ₌₁ⁿ xᵢ"},
+		{`\unknown{x}`, "unknownx"},
+	}
+
+	for _, tt := range tests {
+		if got := latexToUnicode(tt.input); got != tt.expected {
+			t.Errorf("latexToUnicode(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestRenderInlineMath(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`SLO $\geq 99.9\%$`, "SLO " + Magenta + "≥ 99.9%" + Reset},
+		{`$$x^2$$`, Magenta + "x²" + Reset},
+		{`$a*b*c$`, Magenta + "a*b*c" + Reset},
+		{"costs $5 and $10", "costs $5 and $10"},
+		{"$ not math $", "$ not math $"},
+		{`\$x$`, "$x$"},
+		{"**$x$**", Bold + Magenta + "x" + Reset},
+		{"$5 or `$x$`", "$5 or " + BgBlack + Cyan + "$x$" + Reset},
+	}
+
+	for _, tt := range tests {
+		if got := renderInline(tt.input); got != tt.expected {
+			t.Errorf("renderInline(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestDisplayMathBlock(t *testing.T) {
+	lines := []string{"$$", "# not a header", `\alpha^2`, "$$", "# Header"}
+	kinds := []LineKind{LineMathFence, LineMath, LineMath, LineMathFence, LineHeader}
+	for i, ml := range ScanLines(lines) {
+		if ml.Kind != kinds[i] {
+			t.Errorf("Line %d %q: expected kind %d, got %d", i, lines[i], kinds[i], ml.Kind)
+		}
+	}
+
+	rendered := RenderLines(lines, 80)
+	if !strings.Contains(rendered[2], "α²") {
+		t.Errorf("Expected converted display math, got %q", rendered[2])
+	}
+}
+
+func TestMathSurvivesSave(t *testing.T) {
+	content := "# SLO\n\nBudget: $1 - \\frac{1}{2}$\n\n$$\nE = 1 - SLO\n$$\n- [ ] Task"
+	app := NewApp()
+	app.FileLines = strings.Split(content, "\n")
+	app.ParseSections()
+
+	if !app.ToggleCheckbox(6) {
+		t.Fatal("Expected checkbox on content line 6")
+	}
+	app.UpdateFileSection(0)
+	if !strings.Contains(strings.Join(app.FileLines, "\n"), "Budget: $1 - \\frac{1}{2}$\n\n$$\nE = 1 - SLO\n$$") {
+		t.Errorf("Math source changed on save:\n%s", strings.Join(app.FileLines, "\n"))
+	}
+}
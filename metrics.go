@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxStudyGap caps the time counted between two key presses, so leaving
+// the viewer open over lunch does not count as studying.
+const maxStudyGap = 5 * time.Minute
+
+// stateSaveInterval is how often the viewer saves its state while
+// running, so serve mode sees fresh study time.
+const stateSaveInterval = time.Minute
+
+// AddStudyTime adds time spent reading a section, capped at maxStudyGap.
+func (a *App) AddStudyTime(title string, d time.Duration) {
+	if title == "" || d <= 0 {
+		return
+	}
+	if a.StudyTime == nil {
+		a.StudyTime = make(map[string]float64)
+	}
+	if d > maxStudyGap {
+		d = maxStudyGap
+	}
+	a.StudyTime[title] += d.Seconds()
+}
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// writeMetric writes one metric family in the Prometheus text format.
+func writeMetric(out io.Writer, name, help string, samples map[string]float64) {
+	fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	labels := make([]string, 0, len(samples))
+	for label := range samples {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		fmt.Fprintf(out, "%s%s %g\n", name, label, samples[label])
+	}
+}
+
+// WriteMetrics writes the learning progress and study time of the
+// document in the Prometheus text exposition format.
+func (a *App) WriteMetrics(out io.Writer) {
+	checked, total := a.GetTotalProgress()
+	writeMetric(out, "learningpath_items_total", "Checklist items, excluding skipped sections.", map[string]float64{"": float64(total)})
+	writeMetric(out, "learningpath_items_completed", "Checked checklist items, excluding skipped sections.", map[string]float64{"": float64(checked)})
+	counts := a.TotalCheckboxes()
+	writeMetric(out, "learningpath_items_in_progress", "Checklist items marked in progress ([~]), excluding skipped sections.", map[string]float64{"": float64(counts.InProgress)})
+	writeMetric(out, "learningpath_items_wont_do", "Checklist items marked won't do ([-]), not counted in the total.", map[string]float64{"": float64(counts.WontDo)})
+	writeMetric(out, "learningpath_sections_total", "Sections in the document.", map[string]float64{"": float64(len(a.Sections))})
+
+	sectionTotal := map[string]float64{}
+	sectionDone := map[string]float64{}
+	for i, sec := range a.Sections {
+		c, t := a.GetProgress(i)
+		if t == 0 || a.IsSkipped(i) {
+			continue
+		}
+		label := fmt.Sprintf(`{section="%s"}`, escapeLabel(sec.Title))
+		sectionTotal[label] += float64(t)
+		sectionDone[label] += float64(c)
+	}
+	writeMetric(out, "learningpath_section_items_total", "Checklist items per section.", sectionTotal)
+	writeMetric(out, "learningpath_section_items_completed", "Checked checklist items per section.", sectionDone)
+
+	spent := map[string]float64{}
+	for title, seconds := range a.StudyTime {
+		spent[fmt.Sprintf(`{section="%s"}`, escapeLabel(title))] = seconds
+	}
+	writeMetric(out, "learningpath_seconds_spent", "Time spent reading each section in the viewer.", spent)
+
+	last := map[string]float64{}
+	for i := len(a.History) - 1; i >= 0; i-- {
+		if a.History[i].Checked {
+			last[""] = float64(a.History[i].Time.Unix())
+			break
+		}
+	}
+	writeMetric(out, "learningpath_last_completion_timestamp_seconds", "Unix time the last item was checked, for alerts on stalled progress.", last)
+}
+
+// handleMetrics serves GET /metrics.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.app.LoadState()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.app.WriteMetrics(w)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================================
+// Metrics Tests
+// ============================================================================
+
+func TestAddStudyTimeCapsIdleGaps(t *testing.T) {
+	app := NewApp()
+	app.AddStudyTime("Tuần 1", 30*time.Second)
+	app.AddStudyTime("Tuần 1", 2*time.Hour)
+	app.AddStudyTime("", time.Minute)
+
+	want := (30*time.Second + maxStudyGap).Seconds()
+	if app.StudyTime["Tuần 1"] != want || len(app.StudyTime) != 1 {
+		t.Errorf("Expected %v seconds for one section, got %v", want, app.StudyTime)
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	app := createTestApp()
+	app.StudyTime = map[string]float64{`Chapter "1"`: 90}
+	app.recordCheckbox("Chapter 1: Basics", "- [ ] Task one", true, time.Unix(1700000000, 0))
+
+	var out bytes.Buffer
+	app.WriteMetrics(&out)
+	metrics := out.String()
+	for _, want := range []string{
+		"# TYPE learningpath_items_total gauge\nlearningpath_items_total 6\n",
+		"learningpath_items_completed 3\n",
+		`learningpath_section_items_completed{section="Chapter 1: Basics"} 1` + "\n",
+		`learningpath_seconds_spent{section="Chapter \"1\""} 90` + "\n",
+		"learningpath_last_completion_timestamp_seconds 1.7e+09\n",
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("Expected metrics to contain %q:\n%s", want, metrics)
+		}
+	}
+}
+
+func TestServeMetrics(t *testing.T) {
+	s, _ := newTestServer(t)
+	rec := serve(s, http.MethodGet, "/metrics", nil)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("Unexpected response %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "learningpath_items_completed 3") {
+		t.Errorf("Expected progress metrics, got:\n%s", rec.Body.String())
+	}
+}
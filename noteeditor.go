@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// TextEditor is the text and cursor of the built-in note editor. Lines
+// hold runes, so the cursor moves by character rather than by byte.
+type TextEditor struct {
+	Lines [][]rune
+	Row   int
+	Col   int
+}
+
+// NewTextEditor returns an editor holding text with the cursor at its end.
+func NewTextEditor(text string) *TextEditor {
+	e := &TextEditor{}
+	for _, line := range strings.Split(text, "\n") {
+		e.Lines = append(e.Lines, []rune(line))
+	}
+	e.Row = len(e.Lines) - 1
+	e.Col = len(e.Lines[e.Row])
+	return e
+}
+
+// Text returns the edited text.
+func (e *TextEditor) Text() string {
+	lines := make([]string, len(e.Lines))
+	for i, line := range e.Lines {
+		lines[i] = string(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Insert types r at the cursor.
+func (e *TextEditor) Insert(r rune) {
+	line := e.Lines[e.Row]
+	line = append(line[:e.Col], append([]rune{r}, line[e.Col:]...)...)
+	e.Lines[e.Row] = line
+	e.Col++
+}
+
+// InsertText types text at the cursor, splitting lines at its newlines.
+func (e *TextEditor) InsertText(text string) {
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			e.Newline()
+		}
+		for _, r := range line {
+			e.Insert(r)
+		}
+	}
+}
+
+// Newline splits the line at the cursor.
+func (e *TextEditor) Newline() {
+	line := e.Lines[e.Row]
+	rest := append([]rune(nil), line[e.Col:]...)
+	e.Lines[e.Row] = line[:e.Col]
+	e.Lines = append(e.Lines[:e.Row+1], append([][]rune{rest}, e.Lines[e.Row+1:]...)...)
+	e.Row, e.Col = e.Row+1, 0
+}
+
+// Backspace deletes the character before the cursor, joining the line
+// with the previous one at its start.
+func (e *TextEditor) Backspace() {
+	if e.Col > 0 {
+		line := e.Lines[e.Row]
+		e.Lines[e.Row] = append(line[:e.Col-1], line[e.Col:]...)
+		e.Col--
+		return
+	}
+	if e.Row > 0 {
+		e.Row--
+		e.Col = len(e.Lines[e.Row])
+		e.joinNext()
+	}
+}
+
+// Delete deletes the character under the cursor, joining the next line
+// at the end of the line.
+func (e *TextEditor) Delete() {
+	line := e.Lines[e.Row]
+	if e.Col < len(line) {
+		e.Lines[e.Row] = append(line[:e.Col], line[e.Col+1:]...)
+		return
+	}
+	e.joinNext()
+}
+
+// joinNext appends the next line to the cursor line.
+func (e *TextEditor) joinNext() {
+	if e.Row+1 < len(e.Lines) {
+		e.Lines[e.Row] = append(e.Lines[e.Row], e.Lines[e.Row+1]...)
+		e.Lines = append(e.Lines[:e.Row+1], e.Lines[e.Row+2:]...)
+	}
+}
+
+// Move moves the cursor by rows lines and cols characters. Moving left of
+// the start of a line goes to the end of the previous one and right of
+// its end to the start of the next; moving up or down keeps the column
+// where the line is long enough.
+func (e *TextEditor) Move(rows, cols int) {
+	e.Row = max(0, min(e.Row+rows, len(e.Lines)-1))
+	e.Col = min(e.Col, len(e.Lines[e.Row]))
+	switch {
+	case cols < 0 && e.Col == 0 && e.Row > 0:
+		e.Row--
+		e.Col = len(e.Lines[e.Row])
+	case cols > 0 && e.Col == len(e.Lines[e.Row]) && e.Row+1 < len(e.Lines):
+		e.Row, e.Col = e.Row+1, 0
+	default:
+		e.Col = max(0, min(e.Col+cols, len(e.Lines[e.Row])))
+	}
+}
+
+// viewLine renders the part of line starting at column left that fits
+// width columns, showing the cursor in reverse video at cursor (-1 for
+// none).
+func viewLine(line []rune, left, width, cursor int) string {
+	var b strings.Builder
+	used := 0
+	for i := left; i <= len(line); i++ {
+		ch := " "
+		if i < len(line) {
+			ch = string(line[i])
+		} else if i != cursor {
+			break
+		}
+		w := max(1, visibleWidth(ch))
+		if used+w > width {
+			break
+		}
+		if i == cursor {
+			ch = "\033[7m" + ch + Reset
+		}
+		b.WriteString(ch)
+		used += w
+	}
+	return b.String()
+}
+
+// noteEditor returns the external editor notes are written in, or "" for
+// the built-in one: when inline_editor is set or neither $EDITOR nor
+// $VISUAL is.
+func noteEditor() string {
+	if config.InlineEditor || os.Getenv("EDITOR") == "" && os.Getenv("VISUAL") == "" {
+		return ""
+	}
+	return findEditor()
+}
+
+// editText edits text in the built-in editor overlay and returns the
+// result; ok is false when cancelled. Arrows, Home/End (Ctrl+a/Ctrl+e),
+// Enter, Backspace and Delete edit as usual, pastes keep their lines,
+// Ctrl+s saves and Esc cancels.
+func editText(title, text string) (string, bool) {
+	terminal.SetRawMode(true)
+	defer terminal.SetRawMode(false)
+	// Ctrl+s would otherwise stop the terminal output (XOFF)
+	exec.Command("stty", "-F", "/dev/tty", "-ixon").Run()
+	defer exec.Command("stty", "-F", "/dev/tty", "ixon").Run()
+
+	e := NewTextEditor(text)
+	top, left := 0, 0
+	for {
+		rows := max(1, app.TermHeight-3)
+		width := max(10, app.TermWidth-1)
+		if e.Row < top {
+			top = e.Row
+		}
+		if e.Row >= top+rows {
+			top = e.Row - rows + 1
+		}
+		if e.Col < left {
+			left = e.Col
+		}
+		if e.Col >= left+width {
+			left = e.Col - width + 1
+		}
+
+		ClearScreen()
+		fmt.Println(barLine(BgMagenta+White+Bold, " "+title+"  (Ctrl+s: lưu, Esc: hủy)", app.TermWidth))
+		for i := top; i < min(top+rows, len(e.Lines)); i++ {
+			cursor := -1
+			if i == e.Row {
+				cursor = e.Col
+			}
+			fmt.Println(viewLine(e.Lines[i], left, width, cursor))
+		}
+		fmt.Printf("\033[%d;1H%sdòng %d/%d, cột %d%s", max(1, app.TermHeight), Dim, e.Row+1, len(e.Lines), e.Col+1, Reset)
+
+		switch key := readKey(); key {
+		case "Ctrl+s":
+			return e.Text(), true
+		case "Esc", "Ctrl+c":
+			return text, false
+		case "Enter":
+			e.Newline()
+		case "Backspace":
+			e.Backspace()
+		case "Delete", "Ctrl+d":
+			e.Delete()
+		case "Left":
+			e.Move(0, -1)
+		case "Right":
+			e.Move(0, 1)
+		case "Up":
+			e.Move(-1, 0)
+		case "Down":
+			e.Move(1, 0)
+		case "Home", "Ctrl+a":
+			e.Col = 0
+		case "End", "Ctrl+e":
+			e.Col = len(e.Lines[e.Row])
+		case "PageUp":
+			e.Move(-rows, 0)
+		case "PageDown":
+			e.Move(rows, 0)
+		case "Tab":
+			e.InsertText("  ")
+		case "Paste":
+			e.InsertText(keyboard.Pasted())
+		default:
+			if r, ok := typedRune(key); ok {
+				e.Insert(r)
+			}
+		}
+	}
+}
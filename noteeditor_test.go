@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestTextEditorEditing(t *testing.T) {
+	e := NewTextEditor("Pod lỗi")
+	if e.Row != 0 || e.Col != 7 {
+		t.Fatalf("Expected the cursor at the end, got %d:%d", e.Row, e.Col)
+	}
+	e.Move(0, -4)
+	e.Insert('x')
+	if got := e.Text(); got != "Podx lỗi" {
+		t.Errorf("Insert: got %q", got)
+	}
+	e.Newline()
+	e.InsertText("a\nb")
+	if got := e.Text(); got != "Podx\na\nb lỗi" || e.Row != 2 || e.Col != 1 {
+		t.Errorf("InsertText: got %q at %d:%d", got, e.Row, e.Col)
+	}
+	e.Col = 0
+	e.Backspace()
+	if got := e.Text(); got != "Podx\nab lỗi" || e.Row != 1 || e.Col != 1 {
+		t.Errorf("Backspace at line start: got %q at %d:%d", got, e.Row, e.Col)
+	}
+	e.Col = len(e.Lines[1])
+	e.Delete()
+	e.Backspace()
+	if got := e.Text(); got != "Podx\nab lỗ" {
+		t.Errorf("Delete/Backspace: got %q", got)
+	}
+}
+
+func TestTextEditorMove(t *testing.T) {
+	e := NewTextEditor("long line\nab")
+	e.Move(-1, 0)
+	if e.Row != 0 || e.Col != 2 {
+		t.Errorf("Up: expected 0:2, got %d:%d", e.Row, e.Col)
+	}
+	e.Col = 0
+	e.Move(0, -1)
+	if e.Row != 0 || e.Col != 0 {
+		t.Errorf("Left at the start: expected 0:0, got %d:%d", e.Row, e.Col)
+	}
+	e.Col = len(e.Lines[0])
+	e.Move(0, 1)
+	if e.Row != 1 || e.Col != 0 {
+		t.Errorf("Right at the end of a line: expected 1:0, got %d:%d", e.Row, e.Col)
+	}
+	e.Move(5, 0)
+	if e.Row != 1 {
+		t.Errorf("Down past the end: expected row 1, got %d", e.Row)
+	}
+}
+
+func TestViewLine(t *testing.T) {
+	if got := viewLine([]rune("abcdef"), 2, 3, -1); got != "cde" {
+		t.Errorf("viewLine = %q", got)
+	}
+	if got := viewLine([]rune("ab"), 0, 5, 2); got != "ab\033[7m "+Reset {
+		t.Errorf("Expected the cursor after the text, got %q", got)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// outlineMinListItems is the number of items from which a list is
+// listed in the section outline.
+const outlineMinListItems = 5
+
+// OutlineEntry is a landmark inside a section's content: a header below
+// section_depth, a bold pseudo-header, a long list or a code block.
+type OutlineEntry struct {
+	// Line is the line of the section content
+	Line int
+	// Depth is the nesting under the headers inside the content
+	Depth int
+	Kind  string
+	Text  string
+}
+
+// Kinds of outline entries
+const (
+	outlineHeader = "header"
+	outlineBold   = "bold"
+	outlineList   = "list"
+	outlineCode   = "code"
+)
+
+// boldLineRegex matches a line that is only bold text, optionally
+// followed by a colon: "**Setup**" or "__Setup:__".
+var boldLineRegex = regexp.MustCompile(`^(?:\*\*|__)([^*_].*?)(?:\*\*|__):?$`)
+
+// listItemRegex matches a bullet or numbered list item.
+var listItemRegex = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(.*)$`)
+
+// codeTitleRegex matches a title="..." attribute of a fence info string.
+var codeTitleRegex = regexp.MustCompile(`title="([^"]*)"`)
+
+// SectionOutline lists the landmarks of section idx's content, in order,
+// for scanning a long section. Lines are those of the rendered content,
+// so an entry's line is also its scroll offset.
+func (a *App) SectionOutline(idx int) []OutlineEntry {
+	if idx < 0 || idx >= len(a.Sections) {
+		return nil
+	}
+	lines := a.contentLines(a.Sections[idx].Content())
+	scanned := ScanLines(lines)
+
+	var entries []OutlineEntry
+	headerLevel, depth := 0, 0
+	listStart, listIndent, listItems := -1, 0, 0
+	endList := func() {
+		if listStart >= 0 && listItems >= outlineMinListItems {
+			first := listItemRegex.FindStringSubmatch(lines[listStart])[2]
+			if isCheckbox(lines[listStart]) {
+				first = checkboxText(lines[listStart])
+			}
+			text := fmt.Sprintf("%s (%d mục)", renderPlain(first), listItems)
+			entries = append(entries, OutlineEntry{listStart, depth, outlineList, text})
+		}
+		listStart = -1
+	}
+
+	for i, ml := range scanned {
+		trimmed := strings.TrimSpace(lines[i])
+		if ml.Kind == LineText {
+			if m := listItemRegex.FindStringSubmatch(lines[i]); m != nil {
+				switch {
+				case listStart < 0:
+					listStart, listIndent, listItems = i, len(m[1]), 1
+				case len(m[1]) <= listIndent:
+					listIndent = len(m[1])
+					listItems++
+				}
+				continue
+			}
+			if listStart >= 0 && lines[i] != trimmed {
+				continue // continuation of an item
+			}
+		}
+		if ml.Kind == LineBlank {
+			continue
+		}
+		endList()
+
+		switch ml.Kind {
+		case LineHeader:
+			if headerLevel == 0 || ml.Level < headerLevel {
+				headerLevel = ml.Level
+			}
+			depth = ml.Level - headerLevel
+			entries = append(entries, OutlineEntry{i, depth, outlineHeader, renderPlain(ml.Text)})
+			depth++
+		case LineFenceOpen:
+			entries = append(entries, OutlineEntry{i, depth, outlineCode, codeBlockTitle(ml.Text)})
+		case LineText:
+			if m := boldLineRegex.FindStringSubmatch(trimmed); m != nil {
+				entries = append(entries, OutlineEntry{i, depth, outlineBold, strings.TrimSuffix(m[1], ":")})
+			}
+		}
+	}
+	endList()
+	return entries
+}
+
+// renderPlain renders the inline markdown of s without styling.
+func renderPlain(s string) string {
+	return stripANSI(renderInline(s))
+}
+
+// codeBlockTitle names a code block by its fence info string: the
+// title="..." attribute, else the words after the language ("bash
+// deploy.sh"), else the language.
+func codeBlockTitle(info string) string {
+	if m := codeTitleRegex.FindStringSubmatch(info); m != nil {
+		return m[1]
+	}
+	fields := strings.Fields(info)
+	switch len(fields) {
+	case 0:
+		return "code"
+	case 1:
+		return "code " + fields[0]
+	}
+	return strings.Join(fields[1:], " ") + " (" + fields[0] + ")"
+}
+
+// outlineKindIcon marks the kind of an outline entry.
+func outlineKindIcon(kind string) string {
+	switch kind {
+	case outlineHeader:
+		return "§"
+	case outlineList:
+		return glyphs.Bullet
+	case outlineCode:
+		return "⌨"
+	}
+	return "▪"
+}
+
+// handleOutline shows the outline of the current section and scrolls to
+// the entry chosen by number.
+func handleOutline() {
+	entries := app.SectionOutline(app.CurrentIdx)
+	if len(entries) == 0 {
+		notify(SeverityInfo, "Section không có tiêu đề con, dòng in đậm, danh sách dài hay code block")
+		return
+	}
+
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	ClearScreen()
+	fmt.Printf("%s🗂  DÀN Ý - %s%s\n", Bold+Cyan, app.Sections[app.CurrentIdx].Title, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	for n, e := range entries {
+		fmt.Printf("  %s%2d.%s %s%s %s %s(dòng %d)%s\n", Cyan, n+1, Reset, strings.Repeat("  ", e.Depth), outlineKindIcon(e.Kind), truncateVisible(e.Text, 60), Dim, e.Line+1, Reset)
+	}
+
+	fmt.Printf("\nNhập số (1-%d) hoặc Enter để quay lại: ", len(entries))
+	input, _ := bufio.NewReader(keyboard).ReadString('\n')
+	num, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || num < 1 || num > len(entries) {
+		return
+	}
+	renderer.ScrollToLine(entries[num-1].Line)
+}
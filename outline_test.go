@@ -0,0 +1,70 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSectionOutline(t *testing.T) {
+	app := NewApp()
+	app.FileLines = strings.Split(`# Long section
+
+**Setup:**
+
+Install things.
+
+- one
+- two
+  continued
+- three
+  - nested
+- four
+
+- five
+
+Short list:
+
+- a
+- b
+
+###### Deep *header*
+
+`+"```bash deploy.sh"+`
+echo **not bold**
+`+"```"+`
+
+`+"```yaml title=\"values.yaml\""+`
+`+"```"+`
+`, "\n")
+	saved := config
+	config = Config{SectionDepth: 5}
+	defer func() { config = saved }()
+	app.ParseSections()
+
+	got := app.SectionOutline(0)
+	want := []OutlineEntry{
+		{1, 0, outlineBold, "Setup"},
+		{5, 0, outlineList, "one (5 mục)"},
+		{19, 0, outlineHeader, "Deep header"},
+		{21, 1, outlineCode, "deploy.sh (bash)"},
+		{25, 1, outlineCode, "values.yaml"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SectionOutline =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCodeBlockTitle(t *testing.T) {
+	tests := map[string]string{
+		"":                      "code",
+		"bash":                  "code bash",
+		"bash deploy.sh":        "deploy.sh (bash)",
+		`yaml title="k8s.yaml"`: "k8s.yaml",
+	}
+	for info, want := range tests {
+		if got := codeBlockTitle(info); got != want {
+			t.Errorf("codeBlockTitle(%q) = %q, want %q", info, got, want)
+		}
+	}
+}
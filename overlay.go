@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+)
+
+// showOverlay displays lines in a full-screen scrollable pane.
+// j/k/↑/↓ scroll, Space pages down, q/Esc/Enter close.
+func showOverlay(title string, lines []string) {
+	offset := 0
+	for {
+		ClearScreen()
+		rows := max(1, app.TermHeight-5)
+		offset = max(0, min(offset, len(lines)-rows))
+
+		fmt.Printf("%s\n\n", barLine(BgMagenta+White+Bold, " "+title, app.TermWidth))
+
+		end := min(offset+rows, len(lines))
+		for _, line := range lines[offset:end] {
+			fmt.Println(truncateVisible(line, app.TermWidth))
+		}
+
+		fmt.Printf("\n%s[%d-%d/%d] j/k cuộn, q đóng%s", Dim, min(offset+1, len(lines)), end, len(lines), Reset)
+
+		switch readKey() {
+		case "j", "Down":
+			offset++
+		case "k", "Up":
+			offset--
+		case "Space", "PageDown":
+			offset += rows
+		case "PageUp":
+			offset -= rows
+		case "q", "Q", "Enter", "Esc":
+			return
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Pager shows a rendered markdown document like less: scrolling and
+// search only, with no sections, state, progress or notes.
+type Pager struct {
+	Lines []string
+	// Rows is the number of document lines on the screen
+	Rows   int
+	Offset int
+	// Query is the last search; Matches are the lines containing it and
+	// Backward is its direction (? instead of /)
+	Query    string
+	Matches  []int
+	Backward bool
+}
+
+// PagerLines renders a markdown document for the pager: headers keep
+// their # marks and are colored by level, long lines are wrapped.
+func PagerLines(content string, width int) []string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	rendered := RenderLines(lines, width)
+	for i, ml := range ScanLines(lines) {
+		if ml.Kind != LineHeader {
+			continue
+		}
+		style := Dim
+		switch ml.Level {
+		case 1:
+			style = Bold + White
+		case 2:
+			style = Bold + Magenta
+		case 3:
+			style = Bold + Cyan
+		case 4:
+			style = Cyan
+		}
+		rendered[i] = style + strings.Repeat("#", ml.Level) + " " + renderInline(ml.Text) + Reset
+	}
+
+	var wrapped []string
+	for _, line := range rendered {
+		wrapped = append(wrapped, wrapVisible(line, width)...)
+	}
+	return wrapped
+}
+
+// maxOffset is the offset showing the last screen of the document.
+func (p *Pager) maxOffset() int {
+	return max(0, len(p.Lines)-p.Rows)
+}
+
+// Scroll moves the view by n lines, staying within the document.
+func (p *Pager) Scroll(n int) {
+	p.Offset = max(0, min(p.Offset+n, p.maxOffset()))
+}
+
+// jump scrolls to the first match at or after line from (the last one at
+// or before it when back is set) and reports whether there was one.
+func (p *Pager) jump(from int, back bool) bool {
+	if back {
+		for i := len(p.Matches) - 1; i >= 0; i-- {
+			if p.Matches[i] <= from {
+				p.Offset = min(p.Matches[i], p.maxOffset())
+				return true
+			}
+		}
+		return false
+	}
+	for _, m := range p.Matches {
+		if m >= from {
+			p.Offset = min(m, p.maxOffset())
+			return true
+		}
+	}
+	return false
+}
+
+// Search finds the lines containing query (case-insensitive) and scrolls
+// to the first one from the top of the screen, in the given direction.
+func (p *Pager) Search(query string, backward bool) bool {
+	p.Query, p.Backward, p.Matches = query, backward, nil
+	q := strings.ToLower(query)
+	for i, line := range p.Lines {
+		if strings.Contains(strings.ToLower(stripANSI(line)), q) {
+			p.Matches = append(p.Matches, i)
+		}
+	}
+	return p.jump(p.Offset, backward)
+}
+
+// Next scrolls to the next match of the last search (n), or the previous
+// one with reverse (N).
+func (p *Pager) Next(reverse bool) bool {
+	if p.Backward != reverse {
+		return p.jump(p.Offset-1, true)
+	}
+	return p.jump(p.Offset+1, false)
+}
+
+// highlightMatches shows a line in plain text with the occurrences of
+// query highlighted, or unchanged when it does not contain it.
+func highlightMatches(line, query string) string {
+	plain := stripANSI(line)
+	lower := strings.ToLower(plain)
+	q := strings.ToLower(query)
+	if q == "" || len(lower) != len(plain) || !strings.Contains(lower, q) {
+		return line
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, q)
+		if i < 0 {
+			b.WriteString(plain)
+			return b.String()
+		}
+		b.WriteString(plain[:i] + BgYellow + Black + plain[i:i+len(q)] + Reset)
+		plain, lower = plain[i+len(q):], lower[i+len(q):]
+	}
+}
+
+// View returns the lines on the screen, with search matches highlighted.
+func (p *Pager) View() []string {
+	end := min(p.Offset+p.Rows, len(p.Lines))
+	view := make([]string, 0, end-p.Offset)
+	for _, line := range p.Lines[p.Offset:end] {
+		view = append(view, highlightMatches(line, p.Query))
+	}
+	return view
+}
+
+// pagerPrompt reads a search query on the bottom line.
+func pagerPrompt(prompt string) string {
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	fmt.Printf("\r\033[K%s", prompt)
+	query, _ := bufio.NewReader(keyboard).ReadString('\n')
+	return strings.TrimRight(query, "\r\n")
+}
+
+// Run shows the pager until q is pressed. The keys follow less: j/k
+// (Enter, ↑/↓) scroll a line, Space/f and b a page, d/u half a page,
+// g/< and G/> jump to the top and bottom, / and ? search forward and
+// backward, n/N repeat the search.
+func (p *Pager) Run(name string) {
+	status := ""
+	for {
+		ClearScreen()
+		for _, line := range p.View() {
+			fmt.Println(truncateVisible(line, app.TermWidth))
+		}
+		for i := len(p.View()); i < p.Rows; i++ {
+			fmt.Println(Dim + "~" + Reset)
+		}
+		end := min(p.Offset+p.Rows, len(p.Lines))
+		if status == "" {
+			status = fmt.Sprintf("%s [%d-%d/%d]", name, min(p.Offset+1, len(p.Lines)), end, len(p.Lines))
+			if end == len(p.Lines) {
+				status += " (END)"
+			}
+		}
+		fmt.Print(Dim + truncateVisible(status+"  /: tìm · n/N: kết quả · q: thoát", app.TermWidth) + Reset)
+		status = ""
+
+		key := readKey()
+		half := max(1, p.Rows/2)
+		switch key {
+		case "j", "Down", "Enter", "e", "Ctrl+e", "Ctrl+n":
+			p.Scroll(1)
+		case "k", "Up", "y", "Ctrl+y", "Ctrl+p":
+			p.Scroll(-1)
+		case "Space", "f", "Ctrl+f", "Ctrl+v":
+			p.Scroll(p.Rows)
+		case "b", "Ctrl+b":
+			p.Scroll(-p.Rows)
+		case "d", "Ctrl+d":
+			p.Scroll(half)
+		case "u", "Ctrl+u":
+			p.Scroll(-half)
+		case "g", "<", "Home":
+			p.Offset = 0
+		case "G", ">", "End":
+			p.Offset = p.maxOffset()
+		case "/", "?":
+			if query := pagerPrompt(key); query != "" && !p.Search(query, key == "?") {
+				status = "Không tìm thấy: " + query
+			}
+		case "n", "N":
+			if p.Query != "" && !p.Next(key == "N") {
+				status = "Không còn kết quả: " + p.Query
+			}
+		case "q", "Q", "Ctrl+c":
+			return
+		}
+	}
+}
+
+// runPager implements --pager [file|-]: the document (stdin without a
+// file) is shown in the pager, or copied unchanged when stdout is not a
+// terminal, like less does.
+func runPager(args []string, out *os.File) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: sre-learn --pager [file|-]")
+	}
+	name := "stdin"
+	var content string
+	switch {
+	case len(args) == 1 && args[0] != stdinPath:
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		content, name = string(data), filepath.Base(args[0])
+	case !isTerminal(out):
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		content = string(data)
+	case isTerminal(os.Stdin) && len(args) == 0:
+		return fmt.Errorf("usage: sre-learn --pager [file|-] (or pipe a document in)")
+	default:
+		var err error
+		if content, err = readStdinDocument(os.Stdin); err != nil {
+			return err
+		}
+	}
+
+	if !isTerminal(out) {
+		_, err := io.WriteString(out, content)
+		return err
+	}
+
+	app.TermWidth, app.TermHeight = terminal.GetSize()
+	p := &Pager{Lines: PagerLines(content, app.TermWidth), Rows: max(1, app.TermHeight-1)}
+	terminal.SetRawMode(true)
+	defer terminal.SetRawMode(false)
+	p.Run(name)
+	ClearScreen()
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPagerLines(t *testing.T) {
+	lines := PagerLines("# Runbook\n\nStep one is **important** and rather long\n", 20)
+	if got := stripANSI(lines[0]); got != "# Runbook" {
+		t.Errorf("header = %q", got)
+	}
+	if len(lines) != 6 || stripANSI(lines[2]) != "Step one is" {
+		t.Errorf("Expected long lines to wrap, got %q", lines)
+	}
+}
+
+func TestPagerSearch(t *testing.T) {
+	p := &Pager{Rows: 2}
+	for i := 0; i < 10; i++ {
+		p.Lines = append(p.Lines, "line")
+	}
+	p.Lines[1], p.Lines[4], p.Lines[6] = "etcd defrag", Bold+"ETCD"+Reset+" backup", "etcd restore"
+
+	p.Scroll(-5)
+	if p.Offset != 0 {
+		t.Errorf("Expected scrolling to stop at the top, got %d", p.Offset)
+	}
+	p.Scroll(50)
+	if p.Offset != 8 {
+		t.Errorf("Expected scrolling to stop at the last screen, got %d", p.Offset)
+	}
+
+	p.Offset = 2
+	if !p.Search("etcd", false) || p.Offset != 4 || len(p.Matches) != 3 {
+		t.Fatalf("Search = offset %d, matches %v", p.Offset, p.Matches)
+	}
+	if !p.Next(false) || p.Offset != 6 {
+		t.Errorf("Expected n to go to line 6, got %d", p.Offset)
+	}
+	if p.Next(false) {
+		t.Error("Expected no match after the last one")
+	}
+	if !p.Next(true) || p.Offset != 4 {
+		t.Errorf("Expected N to go back to line 4, got %d", p.Offset)
+	}
+	if !p.Search("etcd", true) || p.Offset != 4 || !p.Next(false) || p.Offset != 1 {
+		t.Errorf("Expected ? to search backward, got %d", p.Offset)
+	}
+	if p.Search("kafka", false) {
+		t.Error("Expected no match")
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	got := highlightMatches(Bold+"etcd"+Reset+" and ETCD", "etcd")
+	if want := BgYellow + Black + "etcd" + Reset + " and " + BgYellow + Black + "ETCD" + Reset; got != want {
+		t.Errorf("highlightMatches = %q, want %q", got, want)
+	}
+	if got := highlightMatches(Bold+"kafka"+Reset, "etcd"); got != Bold+"kafka"+Reset {
+		t.Errorf("Expected a line without the query unchanged, got %q", got)
+	}
+}
+
+func TestRunPagerNotTerminal(t *testing.T) {
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "runbook.md")
+	os.WriteFile(doc, []byte("# Runbook\n- [ ] step\n"), 0o644)
+	out, err := os.Create(filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	if err := runPager([]string{doc}, out); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(out.Name()); string(data) != "# Runbook\n- [ ] step\n" {
+		t.Errorf("Expected the document copied unchanged, got %q", data)
+	}
+	if err := runPager([]string{doc, doc}, out); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("Expected a usage error, got %v", err)
+	}
+}
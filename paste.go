@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+)
+
+// With bracketed paste enabled (see Terminal.SetBracketedPaste) the
+// terminal wraps pasted text in these sequences, so a paste is read as one
+// piece of input instead of as keys.
+var (
+	pasteStart = []byte("\x1b[200~")
+	pasteEnd   = []byte("\x1b[201~")
+)
+
+// incompletePaste reports whether b ends inside a paste, whose end is
+// still to be read.
+func incompletePaste(b []byte) bool {
+	i := bytes.LastIndex(b, pasteStart)
+	return i >= 0 && !bytes.Contains(b[i:], pasteEnd)
+}
+
+// takePaste splits the paste at the start of b into its text, with "\n"
+// line endings, and the input after it. ok is false when b does not start
+// with a paste.
+func takePaste(b []byte) (text string, rest []byte, ok bool) {
+	body, ok := bytes.CutPrefix(b, pasteStart)
+	if !ok {
+		return "", b, false
+	}
+	body, rest, _ = bytes.Cut(body, pasteEnd)
+	text = strings.ReplaceAll(string(body), "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n"), rest, true
+}
+
+// pasteLine joins the lines of pasted text with spaces, for the prompts
+// that read a single line.
+func pasteLine(text string) string {
+	return strings.Join(strings.Split(strings.TrimRight(text, "\n"), "\n"), " ")
+}
+
+// flattenPastes replaces the pastes in b with their text on one line, so
+// a line prompt gets a pasted block as the answer it is typed into rather
+// than as one answer per line.
+func flattenPastes(b []byte) []byte {
+	i := bytes.Index(b, pasteStart)
+	if i < 0 {
+		return b
+	}
+	text, rest, _ := takePaste(b[i:])
+	out := append(append([]byte(nil), b[:i]...), pasteLine(text)...)
+	return append(out, flattenPastes(rest)...)
+}
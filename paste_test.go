@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestTakePaste(t *testing.T) {
+	text, rest, ok := takePaste([]byte("\x1b[200~kubectl get pods\r\n-n kube-system\x1b[201~j"))
+	if !ok || text != "kubectl get pods\n-n kube-system" || string(rest) != "j" {
+		t.Errorf("takePaste = %q, %q, %v", text, rest, ok)
+	}
+	if _, _, ok := takePaste([]byte("j")); ok {
+		t.Error("Expected no paste")
+	}
+}
+
+func TestFlattenPastes(t *testing.T) {
+	got := string(flattenPastes([]byte("a\x1b[200~one\ntwo\n\x1b[201~\nb")))
+	if got != "aone two\nb" {
+		t.Errorf("flattenPastes = %q", got)
+	}
+	if !incompletePaste([]byte("x\x1b[200~one\n")) || incompletePaste([]byte("\x1b[200~one\x1b[201~")) {
+		t.Error("Unexpected incompletePaste result")
+	}
+}
+
+// chunkedReader returns one chunk per read, like a terminal.
+type chunkedReader struct{ chunks []string }
+
+func (r *chunkedReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, nil
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
+
+func TestKeyboardPaste(t *testing.T) {
+	kb := &Keyboard{src: &chunkedReader{[]string{"j\x1b[200~line one\n", "line two\x1b[201~k"}}}
+	for _, want := range []string{"j", "Paste", "k"} {
+		if got, _ := kb.ReadKey(); got != want {
+			t.Fatalf("ReadKey() = %q, expected %q", got, want)
+		}
+		if want == "Paste" && kb.Pasted() != "line one\nline two" {
+			t.Errorf("Pasted() = %q", kb.Pasted())
+		}
+	}
+
+	kb = &Keyboard{src: &chunkedReader{[]string{"\x1b[200~a\n", "b\x1b[201~\n"}}}
+	if line, _ := bufio.NewReader(kb).ReadString('\n'); strings.TrimSpace(line) != "a b" {
+		t.Errorf("Expected the paste as one line, got %q", line)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// pdfDriver is an external converter turning the exported HTML into PDF.
+type pdfDriver struct {
+	name string
+	// args returns the command line converting in (HTML) to out (PDF)
+	args func(in, out string) []string
+}
+
+// pdfDrivers lists the converters in order of preference. All of them
+// build the PDF outline (bookmarks) from the section headers.
+var pdfDrivers = []pdfDriver{
+	{"wkhtmltopdf", func(in, out string) []string {
+		return []string{"--quiet", "--encoding", "utf-8", "--outline", "--outline-depth", "4", in, out}
+	}},
+	{"pandoc", func(in, out string) []string {
+		return []string{in, "--from", "html", "--pdf-engine", "xelatex", "-V", "mainfont=DejaVu Serif", "-o", out}
+	}},
+	{"chromium", func(in, out string) []string {
+		return []string{"--headless", "--disable-gpu", "--no-pdf-header-footer", "--generate-pdf-document-outline", "--print-to-pdf=" + out, in}
+	}},
+	{"google-chrome", func(in, out string) []string {
+		return []string{"--headless", "--disable-gpu", "--no-pdf-header-footer", "--generate-pdf-document-outline", "--print-to-pdf=" + out, in}
+	}},
+}
+
+// errNoPDFDriver is returned when no converter is installed.
+var errNoPDFDriver = errors.New("no PDF converter found")
+
+// findPDFDriver returns the named driver, or the first installed one for
+// "auto".
+func findPDFDriver(name string) (*pdfDriver, error) {
+	for i, d := range pdfDrivers {
+		if name != "auto" && d.name != name {
+			continue
+		}
+		if _, err := exec.LookPath(d.name); err == nil {
+			return &pdfDrivers[i], nil
+		}
+		if name != "auto" {
+			return nil, fmt.Errorf("%s is not installed", name)
+		}
+	}
+	if name != "auto" {
+		return nil, fmt.Errorf("unknown PDF engine %q", name)
+	}
+	return nil, errNoPDFDriver
+}
+
+// driverNames returns the names of the PDF drivers.
+func driverNames() []string {
+	names := make([]string, len(pdfDrivers))
+	for i, d := range pdfDrivers {
+		names[i] = d.name
+	}
+	return names
+}
+
+// runExportPDF implements "export pdf [-o file] [--engine name]". The
+// document is rendered to HTML and converted by an external engine; if
+// none is installed the HTML is kept so it can be printed from a browser.
+func runExportPDF(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("export pdf", flag.ContinueOnError)
+	output := fs.String("o", "", "output file (default: the document name with .pdf)")
+	engine := fs.String("engine", "auto", "converter: auto, "+strings.Join(driverNames(), ", "))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	target := *output
+	if target == "" {
+		target = strings.TrimSuffix(filepath.Base(app.FilePath), filepath.Ext(app.FilePath)) + ".pdf"
+	}
+
+	htmlPath := strings.TrimSuffix(target, filepath.Ext(target)) + ".html"
+	f, err := os.Create(htmlPath)
+	if err != nil {
+		return err
+	}
+	app.WriteDocumentHTML(f, app.documentTitle())
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	driver, err := findPDFDriver(*engine)
+	if errors.Is(err, errNoPDFDriver) {
+		return fmt.Errorf("%w (install one of %s); the document was written to %s, print it to PDF from a browser",
+			err, strings.Join(driverNames(), ", "), htmlPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(driver.name, driver.args(htmlPath, target)...)
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w\n%s(the HTML is kept at %s)", driver.name, err, msg, htmlPath)
+	}
+	os.Remove(htmlPath)
+	fmt.Fprintf(out, "Wrote %s (%s)\n", target, driver.name)
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportPDFWithDriver(t *testing.T) {
+	withTestApp(t)
+	bin := t.TempDir()
+	// Fake wkhtmltopdf copying its input (second to last argument) to its output
+	script := "#!/bin/sh\nfor a; do in=$out; out=$a; done\nwhile IFS= read -r l; do echo \"$l\"; done < \"$in\" > \"$out\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "wkhtmltopdf"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	target := filepath.Join(t.TempDir(), "path.pdf")
+	var out bytes.Buffer
+	if err := runExport([]string{"pdf", "-o", target}, &out); err != nil {
+		t.Fatalf("export pdf: %v", err)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `<h2 id="s2">`) {
+		t.Errorf("converter did not get the document HTML")
+	}
+	if _, err := os.Stat(strings.TrimSuffix(target, ".pdf") + ".html"); !os.IsNotExist(err) {
+		t.Errorf("intermediate HTML should be removed")
+	}
+	if !strings.Contains(out.String(), "wkhtmltopdf") {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestExportPDFFallsBackToHTML(t *testing.T) {
+	withTestApp(t)
+	t.Setenv("PATH", t.TempDir())
+
+	target := filepath.Join(t.TempDir(), "path.pdf")
+	err := runExport([]string{"pdf", "-o", target}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "print it to PDF") {
+		t.Fatalf("expected fallback error, got %v", err)
+	}
+	if _, err := os.Stat(strings.TrimSuffix(target, ".pdf") + ".html"); err != nil {
+		t.Errorf("HTML fallback not written: %v", err)
+	}
+}
+
+func TestFindPDFDriverUnknown(t *testing.T) {
+	if _, err := findPDFDriver("acrobat"); err == nil {
+		t.Error("expected error for unknown engine")
+	}
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Plugins are executables in the plugin directory that speak JSON over
+// stdio: each call writes one PluginRequest to the plugin's stdin and
+// reads one PluginResponse from its stdout. On discovery a plugin is asked
+// to "describe" itself; the manifest it returns registers key commands,
+// a content transformer and document exporters.
+
+// pluginTimeout bounds one plugin call.
+const pluginTimeout = 10 * time.Second
+
+// categoryPlugin groups the plugin keys in the help overlay.
+const categoryPlugin = "Plugin"
+
+// PluginKey is a key command registered by a plugin.
+type PluginKey struct {
+	// Key is the key name (see keyName), e.g. "U" or "Ctrl+k"
+	Key string `json:"key"`
+	// Action is sent back to the plugin when the key is pressed
+	Action string `json:"action"`
+	// Desc is the help text
+	Desc string `json:"desc"`
+}
+
+// PluginManifest is a plugin's answer to the "describe" request.
+type PluginManifest struct {
+	Name string      `json:"name"`
+	Keys []PluginKey `json:"keys,omitempty"`
+	// Transform asks for section content before it is rendered
+	Transform bool `json:"transform,omitempty"`
+	// Exporters are formats added to "export <format>"
+	Exporters []string `json:"exporters,omitempty"`
+}
+
+// Plugin is a discovered plugin.
+type Plugin struct {
+	Path string
+	PluginManifest
+}
+
+// PluginSection is a section as sent to plugins.
+type PluginSection struct {
+	Title   string `json:"title"`
+	Level   int    `json:"level"`
+	ID      string `json:"id,omitempty"`
+	Content string `json:"content"`
+}
+
+// PluginRequest is written to a plugin's stdin. Type is "describe",
+// "key" (with Action and Section), "transform" (with Lines and Section)
+// or "export" (with Format and Sections).
+type PluginRequest struct {
+	Type     string          `json:"type"`
+	File     string          `json:"file,omitempty"`
+	Action   string          `json:"action,omitempty"`
+	Format   string          `json:"format,omitempty"`
+	Section  *PluginSection  `json:"section,omitempty"`
+	Sections []PluginSection `json:"sections,omitempty"`
+	Lines    []string        `json:"lines,omitempty"`
+}
+
+// PluginResponse is read from a plugin's stdout. A key command may
+// replace the section content, show lines in an overlay and a message;
+// a transformer returns the lines to render; an exporter the output.
+type PluginResponse struct {
+	Error   string   `json:"error,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Title   string   `json:"title,omitempty"`
+	Lines   []string `json:"lines,omitempty"`
+	Content *string  `json:"content,omitempty"`
+	Output  string   `json:"output,omitempty"`
+}
+
+// pluginDir returns the plugin discovery directory, next to the config
+// file.
+func pluginDir() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "plugins"), nil
+}
+
+// callPlugin runs the plugin executable with one request and decodes its
+// response into v. Stderr is reported when the plugin fails.
+func callPlugin(path string, req PluginRequest, v any) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(append(payload, '\n'))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	name := filepath.Base(path)
+	logger.Debug("call plugin", "plugin", name, "type", req.Type)
+	if err != nil {
+		logger.Warn("plugin failed", "plugin", name, "err", err)
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("plugin %s: %s", name, msg)
+		}
+		return fmt.Errorf("plugin %s: %w", name, err)
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("plugin %s: invalid response: %w", name, err)
+	}
+	return nil
+}
+
+// Call sends a request to the plugin. An "error" in the response is
+// returned as an error.
+func (p Plugin) Call(req PluginRequest) (PluginResponse, error) {
+	var resp PluginResponse
+	if err := callPlugin(p.Path, req, &resp); err != nil {
+		return resp, err
+	}
+	if resp.Error != "" {
+		return resp, fmt.Errorf("plugin %s: %s", p.Name, resp.Error)
+	}
+	return resp, nil
+}
+
+// DiscoverPlugins describes every executable in dir, in name order. A
+// missing directory has no plugins; plugins that fail to describe
+// themselves are reported and skipped.
+func DiscoverPlugins(dir string) ([]Plugin, []error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var found []Plugin
+	var errs []error
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		var manifest PluginManifest
+		if err := callPlugin(path, PluginRequest{Type: "describe"}, &manifest); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if manifest.Name == "" {
+			manifest.Name = strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		}
+		found = append(found, Plugin{Path: path, PluginManifest: manifest})
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	return found, errs
+}
+
+// plugins are the plugins discovered at startup (see loadPlugins).
+var plugins []Plugin
+
+// loadPlugins discovers the plugins of the plugin directory.
+func loadPlugins() []error {
+	dir, err := pluginDir()
+	if err != nil {
+		return []error{err}
+	}
+	var errs []error
+	plugins, errs = DiscoverPlugins(dir)
+	return errs
+}
+
+// pluginSection converts a section for a plugin request.
+func pluginSection(sec *Section) *PluginSection {
+	return &PluginSection{Title: sec.Title, Level: sec.Level, ID: sec.ID, Content: sec.Content()}
+}
+
+// pluginKeyBindings returns the key commands of the plugins as bindings
+// of the "plugin.<name>.<action>" actions. Keys that are already bound
+// or overlap a bound chord are skipped, so a plugin cannot shadow a
+// built-in key.
+func pluginKeyBindings(found []Plugin, bound []KeyBinding) []KeyBinding {
+	var bindings []KeyBinding
+	for _, p := range found {
+		for _, k := range p.Keys {
+			if !validKey(k.Key) || keyTaken(append(bound, bindings...), k.Key) {
+				continue
+			}
+			p, k := p, k
+			desc := k.Desc
+			if desc == "" {
+				desc = k.Action
+			}
+			bindings = append(bindings, KeyBinding{"plugin." + p.Name + "." + k.Action, []string{k.Key},
+				categoryPlugin, desc + " (" + p.Name + ")", func(string) { runPluginKey(p, k) }})
+		}
+	}
+	return bindings
+}
+
+// ApplyPluginKey sends a key command for the current section to the
+// plugin and applies the returned content. The file lines are updated but
+// not saved.
+func (a *App) ApplyPluginKey(p Plugin, k PluginKey) (PluginResponse, error) {
+	sec := a.GetCurrentSection()
+	if sec == nil {
+		return PluginResponse{}, fmt.Errorf("no section")
+	}
+	resp, err := p.Call(PluginRequest{Type: "key", File: a.FilePath, Action: k.Action, Section: pluginSection(sec)})
+	if err != nil {
+		return resp, err
+	}
+	if resp.Content != nil && *resp.Content != sec.Content() {
+		if a.ReadOnly {
+			return resp, fmt.Errorf("read-only: plugin %s cannot change the section", p.Name)
+		}
+		sec.SetContent(*resp.Content)
+		a.UpdateFileSection(a.CurrentIdx)
+	}
+	return resp, nil
+}
+
+// runPluginKey runs a plugin key command from the main view.
+func runPluginKey(p Plugin, k PluginKey) {
+	resp, err := app.ApplyPluginKey(p, k)
+	if err != nil {
+		notify(SeverityError, "%v", err)
+		return
+	}
+	if resp.Content != nil {
+		if !saveDocument() {
+			return
+		}
+	}
+	if len(resp.Lines) > 0 {
+		title := resp.Title
+		if title == "" {
+			title = "🧩 " + p.Name
+		}
+		showOverlay(title, resp.Lines)
+	}
+	if resp.Message != "" {
+		notify(SeverityInfo, "%s", resp.Message)
+	}
+}
+
+// pluginTransforms caches transformed content by plugin and input, since
+// the content is rendered on every key press.
+var pluginTransforms = map[string][]string{}
+
+// transformLines passes content lines through the plugins that transform
+// content, in order. A failing plugin leaves the lines unchanged.
+func transformLines(found []Plugin, lines []string) []string {
+	for _, p := range found {
+		if !p.Transform {
+			continue
+		}
+		key := p.Path + "\x00" + strings.Join(lines, "\n")
+		if cached, ok := pluginTransforms[key]; ok {
+			lines = cached
+			continue
+		}
+		resp, err := p.Call(PluginRequest{Type: "transform", Lines: lines})
+		if err == nil && resp.Lines != nil {
+			pluginTransforms[key] = resp.Lines
+			lines = resp.Lines
+		} else {
+			pluginTransforms[key] = lines
+		}
+	}
+	return lines
+}
+
+// findPluginExporter returns the plugin that exports format.
+func findPluginExporter(found []Plugin, format string) *Plugin {
+	for i, p := range found {
+		for _, name := range p.Exporters {
+			if name == format {
+				return &found[i]
+			}
+		}
+	}
+	return nil
+}
+
+// runPluginExport implements "export <format> [-o file]" for a format
+// added by a plugin: the whole document goes to the plugin and its output
+// to the file or stdout.
+func runPluginExport(p Plugin, format string, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("export "+format, flag.ContinueOnError)
+	output := fs.String("o", "", "output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sections := make([]PluginSection, len(app.Sections))
+	for i := range app.Sections {
+		sections[i] = *pluginSection(&app.Sections[i])
+	}
+	resp, err := p.Call(PluginRequest{Type: "export", File: app.FilePath, Format: format, Sections: sections})
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err := io.WriteString(out, resp.Output)
+		return err
+	}
+	if err := os.WriteFile(*output, []byte(resp.Output), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Wrote %s\n", *output)
+	return nil
+}
+
+// runPlugins implements "plugins": it lists the discovered plugins with
+// what they register, and the plugins that failed.
+func runPlugins(args []string, out io.Writer) error {
+	dir, err := pluginDir()
+	if err != nil {
+		return err
+	}
+	found, errs := DiscoverPlugins(dir)
+	if len(found) == 0 && len(errs) == 0 {
+		fmt.Fprintf(out, "No plugins in %s\n", dir)
+		return nil
+	}
+	for _, p := range found {
+		var parts []string
+		for _, k := range p.Keys {
+			parts = append(parts, "key "+k.Key+" ("+k.Action+")")
+		}
+		if p.Transform {
+			parts = append(parts, "transform")
+		}
+		for _, format := range p.Exporters {
+			parts = append(parts, "export "+format)
+		}
+		fmt.Fprintf(out, "%-16s %s\n", p.Name, strings.Join(parts, ", "))
+	}
+	for _, err := range errs {
+		fmt.Fprintf(out, "error: %v\n", err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d plugin(s) failed", len(errs))
+	}
+	return nil
+}
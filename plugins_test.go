@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPlugin = `#!/bin/sh
+read -r req
+case "$req" in
+*'"type":"describe"'*) echo '{"name":"shout","keys":[{"key":"U","action":"todo","desc":"Thêm việc"},{"key":"j","action":"shadow"}],"transform":true,"exporters":["count"]}' ;;
+*'"type":"transform"'*) echo '{"lines":["transformed"]}' ;;
+*'"type":"key"'*) echo '{"content":"- [ ] added by plugin","message":"done"}' ;;
+*'"type":"export"'*) echo '{"output":"exported"}' ;;
+esac
+`
+
+// writePlugins creates a plugin directory under a temporary config home
+// with a working plugin, a failing one and a non-executable file.
+func writePlugins(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	dir := filepath.Join(home, "sre-learn", "plugins")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, script := range map[string]string{
+		"shout.sh":  testPlugin,
+		"broken.sh": "#!/bin/sh\necho boom >&2\nexit 1\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	dir := writePlugins(t)
+	found, errs := DiscoverPlugins(dir)
+	if len(found) != 1 || found[0].Name != "shout" || !found[0].Transform {
+		t.Fatalf("found = %+v", found)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "boom") {
+		t.Errorf("errs = %v", errs)
+	}
+
+	bindings := pluginKeyBindings(found, defaultKeyBindings())
+	if len(bindings) != 1 || bindings[0].Action != "plugin.shout.todo" || bindings[0].Keys[0] != "U" {
+		t.Errorf("Expected only the free key to be bound, got %+v", bindings)
+	}
+
+	if got := transformLines(found, []string{"a", "b"}); len(got) != 1 || got[0] != "transformed" {
+		t.Errorf("transformLines = %q", got)
+	}
+
+	if found, errs := DiscoverPlugins(filepath.Join(dir, "missing")); found != nil || errs != nil {
+		t.Errorf("Expected no plugins without a directory, got %v %v", found, errs)
+	}
+}
+
+func TestPluginKeyAndExport(t *testing.T) {
+	dir := writePlugins(t)
+	found, _ := DiscoverPlugins(dir)
+	app := withTestApp(t)
+
+	resp, err := app.ApplyPluginKey(found[0], found[0].Keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Message != "done" || app.Sections[0].Content() != "- [ ] added by plugin" {
+		t.Errorf("resp = %+v, content = %q", resp, app.Sections[0].Content())
+	}
+	if !strings.Contains(strings.Join(app.FileLines, "\n"), "- [ ] added by plugin") {
+		t.Error("Expected the file lines to be updated")
+	}
+
+	saved := plugins
+	plugins = found
+	t.Cleanup(func() { plugins = saved })
+	var out bytes.Buffer
+	if err := runExport([]string{"count"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "exported" {
+		t.Errorf("export output = %q", out.String())
+	}
+}
+
+func TestRunPlugins(t *testing.T) {
+	writePlugins(t)
+	var out bytes.Buffer
+	if err := runPlugins(nil, &out); err == nil {
+		t.Error("Expected an error for the failing plugin")
+	}
+	if !strings.Contains(out.String(), "key U (todo), key j (shadow), transform, export count") {
+		t.Errorf("output = %q", out.String())
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// bigFontHeight is the number of rows of each block-letter glyph.
+const bigFontHeight = 5
+
+// bigFont holds figlet-style block letters used for slide titles.
+var bigFont = map[rune][bigFontHeight]string{
+	'A': {" ███ ", "█   █", "█████", "█   █", "█   █"},
+	'B': {"████ ", "█   █", "████ ", "█   █", "████ "},
+	'C': {" ████", "█    ", "█    ", "█    ", " ████"},
+	'D': {"████ ", "█   █", "█   █", "█   █", "████ "},
+	'E': {"█████", "█    ", "████ ", "█    ", "█████"},
+	'F': {"█████", "█    ", "████ ", "█    ", "█    "},
+	'G': {" ████", "█    ", "█  ██", "█   █", " ████"},
+	'H': {"█   █", "█   █", "█████", "█   █", "█   █"},
+	'I': {"███", " █ ", " █ ", " █ ", "███"},
+	'J': {"  ███", "   █ ", "   █ ", "█  █ ", " ██  "},
+	'K': {"█   █", "█  █ ", "███  ", "█  █ ", "█   █"},
+	'L': {"█    ", "█    ", "█    ", "█    ", "█████"},
+	'M': {"█   █", "██ ██", "█ █ █", "█   █", "█   █"},
+	'N': {"█   █", "██  █", "█ █ █", "█  ██", "█   █"},
+	'O': {" ███ ", "█   █", "█   █", "█   █", " ███ "},
+	'P': {"████ ", "█   █", "████ ", "█    ", "█    "},
+	'Q': {" ███ ", "█   █", "█ █ █", "█  █ ", " ██ █"},
+	'R': {"████ ", "█   █", "████ ", "█  █ ", "█   █"},
+	'S': {" ████", "█    ", " ███ ", "    █", "████ "},
+	'T': {"█████", "  █  ", "  █  ", "  █  ", "  █  "},
+	'U': {"█   █", "█   █", "█   █", "█   █", " ███ "},
+	'V': {"█   █", "█   █", "█   █", " █ █ ", "  █  "},
+	'W': {"█   █", "█   █", "█ █ █", "██ ██", "█   █"},
+	'X': {"█   █", " █ █ ", "  █  ", " █ █ ", "█   █"},
+	'Y': {"█   █", " █ █ ", "  █  ", "  █  ", "  █  "},
+	'Z': {"█████", "   █ ", "  █  ", " █   ", "█████"},
+	'0': {" ███ ", "█  ██", "█ █ █", "██  █", " ███ "},
+	'1': {" █ ", "██ ", " █ ", " █ ", "███"},
+	'2': {"████ ", "    █", " ███ ", "█    ", "█████"},
+	'3': {"████ ", "    █", " ███ ", "    █", "████ "},
+	'4': {"█   █", "█   █", "█████", "    █", "    █"},
+	'5': {"█████", "█    ", "████ ", "    █", "████ "},
+	'6': {" ███ ", "█    ", "████ ", "█   █", " ███ "},
+	'7': {"█████", "    █", "   █ ", "  █  ", "  █  "},
+	'8': {" ███ ", "█   █", " ███ ", "█   █", " ███ "},
+	'9': {" ███ ", "█   █", " ████", "    █", " ███ "},
+	' ': {"  ", "  ", "  ", "  ", "  "},
+	'-': {"    ", "    ", "████", "    ", "    "},
+	':': {" ", "█", " ", "█", " "},
+	'.': {" ", " ", " ", " ", "█"},
+	',': {"  ", "  ", "  ", " █", "█ "},
+	'/': {"    █", "   █ ", "  █  ", " █   ", "█    "},
+	'(': {" █", "█ ", "█ ", "█ ", " █"},
+	')': {"█ ", " █", " █", " █", "█ "},
+	'&': {" ██  ", "█  █ ", " ██ █", "█  █ ", " ██ █"},
+	'?': {"████ ", "    █", "  ██ ", "     ", "  █  "},
+	'!': {"█", "█", "█", " ", "█"},
+}
+
+// vietnameseBase maps Vietnamese letters with diacritics to their base
+// Latin letter so titles can be drawn with the block font.
+var vietnameseBase = map[rune]rune{}
+
+func init() {
+	groups := map[rune]string{
+		'a': "àáạảãâầấậẩẫăằắặẳẵ",
+		'e': "èéẹẻẽêềếệểễ",
+		'i': "ìíịỉĩ",
+		'o': "òóọỏõôồốộổỗơờớợởỡ",
+		'u': "ùúụủũưừứựửữ",
+		'y': "ỳýỵỷỹ",
+		'd': "đ",
+	}
+	for base, letters := range groups {
+		for _, r := range letters {
+			vietnameseBase[r] = base
+			vietnameseBase[unicode.ToUpper(r)] = unicode.ToUpper(base)
+		}
+	}
+}
+
+// BigText renders text with the block font.
+// Letters are upper-cased and stripped of diacritics; characters without
+// a glyph are skipped. Returns bigFontHeight rows.
+func BigText(text string) []string {
+	rows := make([]string, bigFontHeight)
+	for _, r := range text {
+		if base, ok := vietnameseBase[r]; ok {
+			r = base
+		}
+		glyph, ok := bigFont[unicode.ToUpper(r)]
+		if !ok {
+			continue
+		}
+		for i := range rows {
+			rows[i] += glyph[i] + " "
+		}
+	}
+	return rows
+}
+
+// centerLines indents a block of lines so that it is horizontally centered
+// in width columns, keeping the lines aligned with each other.
+func centerLines(lines []string, width int) []string {
+	blockWidth := 0
+	for _, line := range lines {
+		blockWidth = max(blockWidth, visibleWidth(line))
+	}
+	pad := strings.Repeat(" ", max(0, (width-blockWidth)/2))
+
+	centered := make([]string, len(lines))
+	for i, line := range lines {
+		centered[i] = pad + line
+	}
+	return centered
+}
+
+// slideTitle returns the title block of a slide: block letters when they
+// fit the terminal, a plain bold title otherwise.
+func slideTitle(title string, width int) []string {
+	big := BigText(title)
+	if visibleWidth(big[0]) > 0 && visibleWidth(big[0]) <= width {
+		for i := range big {
+			big[i] = Bold + Cyan + strings.ReplaceAll(big[i], "█", glyphs.BarFull) + Reset
+		}
+		return big
+	}
+	return []string{Bold + Cyan + strings.ToUpper(title) + Reset}
+}
+
+// renderSlide draws one section as a slide: large title, centered
+// content, no footer, only a small slide counter.
+// Returns the scroll offset clamped to the slide content.
+func renderSlide(sec *Section, slide, total, scroll, width, height int) int {
+	ClearScreen()
+
+	fmt.Println()
+	for _, line := range centerLines(slideTitle(sec.Title, width-4), width) {
+		fmt.Println(line)
+	}
+	fmt.Println()
+
+	// Trim surrounding blank lines so the content sits right under the title
+	content := strings.Trim(sec.Content(), "\n")
+	lines := app.contentLines(content)
+	var rendered []string
+	for _, line := range RenderLines(lines, min(width, 100)) {
+		rendered = append(rendered, wrapVisible(line, min(width, 100))...)
+	}
+
+	rows := max(1, height-bigFontHeight-6)
+	scroll = min(scroll, max(0, len(rendered)-rows))
+	visible := rendered[scroll:min(scroll+rows, len(rendered))]
+	for _, line := range centerLines(visible, width) {
+		fmt.Println(line)
+	}
+
+	counter := fmt.Sprintf("%d/%d", slide+1, total)
+	if scroll+rows < len(rendered) {
+		counter = "↓ " + counter
+	}
+	fmt.Printf("\n%s%s%s%s", strings.Repeat(" ", max(0, width-visibleWidth(counter)-2)), Dim, counter, Reset)
+	return scroll
+}
+
+// handlePresentation runs the presentation mode loop.
+// Space/→/l/n advance, ←/h/p go back, ↑/↓ scroll a long slide, q/Esc exit.
+func handlePresentation() {
+	if len(app.Sections) == 0 {
+		return
+	}
+
+	scroll := 0
+	for {
+		scroll = renderSlide(app.GetCurrentSection(), app.CurrentIdx, len(app.Sections), scroll, app.TermWidth, app.TermHeight)
+
+		switch readKey() {
+		case "Space", "n", "l", "Enter", "Right", "PageDown": // next slide
+			if app.NextSection() {
+				scroll = 0
+			}
+		case "p", "h", "Backspace", "Left", "PageUp": // previous slide
+			if app.PrevSection() {
+				scroll = 0
+			}
+		case "j", "Down": // scroll slide down
+			scroll += 3
+		case "k", "Up": // scroll slide up
+			scroll = max(0, scroll-3)
+		case "q", "Q", "Esc": // q or Escape - exit
+			return
+		}
+	}
+}
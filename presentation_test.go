@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Presentation Tests
+// ============================================================================
+
+func TestBigTextRows(t *testing.T) {
+	rows := BigText("SRE")
+
+	if len(rows) != bigFontHeight {
+		t.Fatalf("Expected %d rows, got %d", bigFontHeight, len(rows))
+	}
+
+	for _, row := range rows {
+		if visibleWidth(row) != visibleWidth(rows[0]) {
+			t.Error("Expected all rows to have the same width")
+		}
+	}
+
+	if !strings.Contains(rows[0], "█") {
+		t.Error("Expected block glyphs in output")
+	}
+}
+
+func TestBigTextVietnamese(t *testing.T) {
+	withDiacritics := BigText("Giai đoạn")
+	plain := BigText("GIAI DOAN")
+
+	for i := range plain {
+		if withDiacritics[i] != plain[i] {
+			t.Errorf("Row %d: expected diacritics to map to base letters", i)
+		}
+	}
+}
+
+func TestSlideTitleFallback(t *testing.T) {
+	title := slideTitle("A very long section title that cannot fit", 20)
+
+	if len(title) != 1 {
+		t.Fatalf("Expected plain single-line title on narrow terminal, got %d rows", len(title))
+	}
+
+	if !strings.Contains(title[0], "A VERY LONG") {
+		t.Errorf("Expected upper-cased title, got '%s'", title[0])
+	}
+}
+
+func TestCenterLines(t *testing.T) {
+	lines := centerLines([]string{"ab", "abcd"}, 10)
+
+	if !strings.HasPrefix(lines[0], "   ab") || !strings.HasPrefix(lines[1], "   abcd") {
+		t.Errorf("Expected block to be centered with shared indent, got %q", lines)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// profileNameRegex restricts profile names to safe directory names.
+var profileNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// dataHome returns the base data directory for sre-learn.
+// It honors XDG_DATA_HOME and falls back to ~/.local/share.
+func dataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "sre-learn"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "sre-learn"), nil
+}
+
+// stateHome returns the base directory for reading state. It honors
+// XDG_STATE_HOME and falls back to ~/.local/state.
+func stateHome() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "sre-learn"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "sre-learn"), nil
+}
+
+// profileDir returns the directory holding state and notes for a profile.
+// Returns an error if the name is not a valid profile name.
+func profileDir(name string) (string, error) {
+	if !profileNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid profile name %q", name)
+	}
+	base, err := dataHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "profiles", name), nil
+}
+
+// UseProfile switches the app to an isolated profile.
+// The shared curriculum at FilePath is copied into the profile directory
+// on first use; afterwards the profile keeps its own copy, so checkbox
+// progress and notes never clobber those of other profiles. The copy and
+// its state are keyed like documentStateFile, by the curriculum's name
+// and a hash of its path, so curricula of the same name in different
+// directories get separate copies. A copy from before that, named after
+// the curriculum alone, is taken over on first use.
+func (a *App) UseProfile(name string) error {
+	dir, err := profileDir(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("cannot create profile directory %s: %w", dir, err)
+	}
+
+	// Reopening the profile's own copy (a recent document, a session)
+	if filepath.Dir(absPath(a.FilePath)) == dir {
+		a.Profile = name
+		a.StateFile = profileStateFile(dir, a.FilePath)
+		if legacy := filepath.Join(dir, "state"); !fileExists(a.StateFile) && fileExists(legacy) {
+			os.Rename(legacy, a.StateFile)
+		}
+		return nil
+	}
+
+	key := documentKey(a.FilePath)
+	profileFile := filepath.Join(dir, key+filepath.Ext(a.FilePath))
+	stateFile := filepath.Join(dir, key+".state")
+	if !fileExists(profileFile) {
+		if err := adoptLegacyProfileCopy(dir, a.FilePath, profileFile, stateFile); err != nil {
+			return err
+		}
+	}
+	if !fileExists(profileFile) {
+		data, err := os.ReadFile(a.FilePath)
+		if err != nil {
+			return fmt.Errorf("cannot read curriculum %s: %w", a.FilePath, err)
+		}
+		if err := os.WriteFile(profileFile, data, 0o644); err != nil {
+			return fmt.Errorf("cannot create profile copy %s: %w", profileFile, err)
+		}
+	}
+
+	a.Profile = name
+	a.FilePath = profileFile
+	a.StateFile = stateFile
+	return nil
+}
+
+// profileStateFile returns the state file of a copy in a profile
+// directory.
+func profileStateFile(dir, profileFile string) string {
+	base := filepath.Base(profileFile)
+	return filepath.Join(dir, strings.TrimSuffix(base, filepath.Ext(base))+".state")
+}
+
+// adoptLegacyProfileCopy renames a profile copy of the curriculum at
+// source named after its base name, with the profile's single state
+// file, to the keyed names.
+func adoptLegacyProfileCopy(dir, source, profileFile, stateFile string) error {
+	legacy := filepath.Join(dir, filepath.Base(source))
+	if !fileExists(legacy) {
+		return nil
+	}
+	if err := os.Rename(legacy, profileFile); err != nil {
+		return fmt.Errorf("cannot move profile copy %s: %w", legacy, err)
+	}
+	if legacyState := filepath.Join(dir, "state"); fileExists(legacyState) {
+		if err := os.Rename(legacyState, stateFile); err != nil {
+			return fmt.Errorf("cannot move profile state %s: %w", legacyState, err)
+		}
+	}
+	return nil
+}
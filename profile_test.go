@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Profile Tests
+// ============================================================================
+
+func TestProfileDir(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/xdg-data")
+
+	dir, err := profileDir("alice")
+	if err != nil {
+		t.Fatalf("profileDir failed: %v", err)
+	}
+
+	expected := filepath.Join("/tmp/xdg-data", "sre-learn", "profiles", "alice")
+	if dir != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, dir)
+	}
+}
+
+func TestProfileDirInvalidName(t *testing.T) {
+	for _, name := range []string{"", "../bob", "a/b", ".hidden"} {
+		if _, err := profileDir(name); err == nil {
+			t.Errorf("Expected error for invalid profile name %q", name)
+		}
+	}
+}
+
+func TestUseProfile(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmp, "data"))
+
+	curriculum := filepath.Join(tmp, "learning-path-full.md")
+	if err := os.WriteFile(curriculum, []byte(sampleMarkdown), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewApp()
+	app.FilePath = curriculum
+
+	if err := app.UseProfile("alice"); err != nil {
+		t.Fatalf("UseProfile failed: %v", err)
+	}
+
+	if app.Profile != "alice" {
+		t.Errorf("Expected Profile 'alice', got '%s'", app.Profile)
+	}
+
+	if !strings.Contains(app.FilePath, filepath.Join("profiles", "alice")) {
+		t.Errorf("Expected FilePath inside profile dir, got '%s'", app.FilePath)
+	}
+
+	if !strings.Contains(app.StateFile, filepath.Join("profiles", "alice")) {
+		t.Errorf("Expected StateFile inside profile dir, got '%s'", app.StateFile)
+	}
+
+	data, err := os.ReadFile(app.FilePath)
+	if err != nil {
+		t.Fatalf("Expected profile copy to exist: %v", err)
+	}
+	if string(data) != sampleMarkdown {
+		t.Error("Expected profile copy to match the curriculum")
+	}
+}
+
+func TestUseProfileKeepsExistingCopy(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmp, "data"))
+
+	curriculum := filepath.Join(tmp, "learning-path-full.md")
+	if err := os.WriteFile(curriculum, []byte(sampleMarkdown), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	first := NewApp()
+	first.FilePath = curriculum
+	if err := first.UseProfile("bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(first.FilePath, []byte("# Bob's progress\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	second := NewApp()
+	second.FilePath = curriculum
+	if err := second.UseProfile("bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(second.FilePath)
+	if string(data) != "# Bob's progress\n" {
+		t.Error("Expected existing profile copy to be preserved")
+	}
+}
+
+func TestUseProfileSeparatesCurriculaOfTheSameName(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmp, "data"))
+
+	var paths, states []string
+	for _, team := range []string{"sre", "platform"} {
+		curriculum := filepath.Join(tmp, team, "learning-path-full.md")
+		os.MkdirAll(filepath.Dir(curriculum), 0o755)
+		os.WriteFile(curriculum, []byte("# "+team+"\n"), 0o644)
+
+		app := NewApp()
+		app.FilePath = curriculum
+		if err := app.UseProfile("alice"); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, app.FilePath)
+		states = append(states, app.StateFile)
+		if data, _ := os.ReadFile(app.FilePath); string(data) != "# "+team+"\n" {
+			t.Errorf("Expected the copy of the %s curriculum, got %q", team, data)
+		}
+	}
+	if paths[0] == paths[1] || states[0] == states[1] {
+		t.Errorf("Expected separate copies and states, got %v and %v", paths, states)
+	}
+
+	// Reopening a copy keeps its state
+	reopened := NewApp()
+	reopened.FilePath = paths[1]
+	if err := reopened.UseProfile("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if reopened.FilePath != paths[1] || reopened.StateFile != states[1] {
+		t.Errorf("Expected %s with %s, got %s with %s", paths[1], states[1], reopened.FilePath, reopened.StateFile)
+	}
+}
+
+func TestUseProfileAdoptsLegacyCopy(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmp, "data"))
+	curriculum := filepath.Join(tmp, "learning-path-full.md")
+	os.WriteFile(curriculum, []byte(sampleMarkdown), 0o644)
+
+	dir, _ := profileDir("carol")
+	os.MkdirAll(dir, 0o755)
+	os.WriteFile(filepath.Join(dir, "learning-path-full.md"), []byte("# Carol's progress\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "state"), []byte(`{"version": 2, "current_section": 3}`), 0o644)
+
+	app := NewApp()
+	app.FilePath = curriculum
+	if err := app.UseProfile("carol"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(app.FilePath); string(data) != "# Carol's progress\n" {
+		t.Errorf("Expected the old copy to be kept, got %q", data)
+	}
+	if data, _ := os.ReadFile(app.StateFile); !strings.Contains(string(data), `"current_section": 3`) {
+		t.Errorf("Expected the old state to be kept, got %q", data)
+	}
+}
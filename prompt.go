@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxPromptHistory is how many past answers a prompt history keeps.
+const maxPromptHistory = 50
+
+// LineEditor is the input of a one-line prompt: the typed runes and the
+// cursor position among them.
+type LineEditor struct {
+	Input []rune
+	Pos   int
+}
+
+// String returns the typed text.
+func (l *LineEditor) String() string {
+	return string(l.Input)
+}
+
+// Set replaces the text, with the cursor at its end.
+func (l *LineEditor) Set(text string) {
+	l.Input = []rune(text)
+	l.Pos = len(l.Input)
+}
+
+// Insert types text at the cursor.
+func (l *LineEditor) Insert(text string) {
+	r := []rune(text)
+	l.Input = append(l.Input[:l.Pos], append(r, l.Input[l.Pos:]...)...)
+	l.Pos += len(r)
+}
+
+// Backspace deletes the character before the cursor.
+func (l *LineEditor) Backspace() {
+	if l.Pos > 0 {
+		l.Input = append(l.Input[:l.Pos-1], l.Input[l.Pos:]...)
+		l.Pos--
+	}
+}
+
+// Delete deletes the character under the cursor.
+func (l *LineEditor) Delete() {
+	if l.Pos < len(l.Input) {
+		l.Input = append(l.Input[:l.Pos], l.Input[l.Pos+1:]...)
+	}
+}
+
+// DeleteWord deletes the word before the cursor and the spaces after it.
+func (l *LineEditor) DeleteWord() {
+	start := l.Pos
+	for start > 0 && l.Input[start-1] == ' ' {
+		start--
+	}
+	for start > 0 && l.Input[start-1] != ' ' {
+		start--
+	}
+	l.Input = append(l.Input[:start], l.Input[l.Pos:]...)
+	l.Pos = start
+}
+
+// Edit applies an editing key: ←/→, Home/End (Ctrl+a/Ctrl+e), Backspace,
+// Delete, Ctrl+w (delete a word), Ctrl+u/Ctrl+k (delete to the start or
+// end), a paste or a typed character. It reports whether key edits.
+func (l *LineEditor) Edit(key string) bool {
+	switch key {
+	case "Left", "Ctrl+b":
+		l.Pos = max(0, l.Pos-1)
+	case "Right", "Ctrl+f":
+		l.Pos = min(len(l.Input), l.Pos+1)
+	case "Home", "Ctrl+a":
+		l.Pos = 0
+	case "End", "Ctrl+e":
+		l.Pos = len(l.Input)
+	case "Backspace":
+		l.Backspace()
+	case "Delete", "Ctrl+d":
+		l.Delete()
+	case "Ctrl+w":
+		l.DeleteWord()
+	case "Ctrl+u":
+		l.Input = l.Input[l.Pos:]
+		l.Pos = 0
+	case "Ctrl+k":
+		l.Input = l.Input[:l.Pos]
+	case "Paste":
+		l.Insert(pasteLine(keyboard.Pasted()))
+	default:
+		r, ok := typedRune(key)
+		if !ok {
+			return false
+		}
+		l.Insert(string(r))
+	}
+	return true
+}
+
+// Prompt describes a one-line prompt read by readPrompt.
+type Prompt struct {
+	// Label is printed before the input
+	Label string
+	// History holds the past answers offered by ↑/↓, most recent first
+	History []string
+	// Complete returns the completions of the input offered by Tab
+	Complete func(input string) []string
+}
+
+// promptHistories keeps the answers of the prompts by kind for the
+// session (the search history is kept in the search store instead).
+var promptHistories = make(map[string][]string)
+
+// pushHistory moves entry to the front of history, dropping an older copy
+// and the oldest entries beyond limit.
+func pushHistory(history []string, entry string, limit int) []string {
+	pushed := []string{entry}
+	for _, old := range history {
+		if old != entry && len(pushed) < limit {
+			pushed = append(pushed, old)
+		}
+	}
+	return pushed
+}
+
+// rememberAnswer adds a non-empty answer to the history of the prompt kind.
+func rememberAnswer(kind, answer string) {
+	if answer = strings.TrimSpace(answer); answer != "" {
+		promptHistories[kind] = pushHistory(promptHistories[kind], answer, maxPromptHistory)
+	}
+}
+
+// commonPrefix returns the longest prefix shared by all of words.
+func commonPrefix(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	prefix := []rune(words[0])
+	for _, w := range words[1:] {
+		r := []rune(w)
+		n := 0
+		for n < len(prefix) && n < len(r) && prefix[n] == r[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
+// readPrompt reads a line on the current terminal line with readline-style
+// editing (see LineEditor.Edit). ↑/↓ go through the history and Tab
+// completes: to the longest common prefix first, then cycling through the
+// completions. Enter returns the trimmed input, Esc and Ctrl+c cancel
+// (ok is false). A cooked terminal is put in raw mode for the prompt.
+func readPrompt(p Prompt) (string, bool) {
+	if !terminal.raw {
+		terminal.SetRawMode(true)
+		defer terminal.SetRawMode(false)
+	}
+
+	var line LineEditor
+	draft := ""
+	histPos := -1
+	var completions []string
+	compPos := -1
+	for {
+		hint := ""
+		if len(completions) > 1 {
+			hint = fmt.Sprintf("  %s(%d/%d, Tab)%s", Dim, compPos+1, len(completions), Reset)
+		}
+		after := string(line.Input[line.Pos:])
+		fmt.Printf("\r\033[K%s%s%s", p.Label, line.String(), hint)
+		if back := visibleWidth(after) + visibleWidth(stripANSI(hint)); back > 0 {
+			fmt.Printf("\033[%dD", back)
+		}
+
+		key := readKey()
+		if key != "Tab" {
+			completions, compPos = nil, -1
+		}
+		switch key {
+		case "Enter":
+			fmt.Println()
+			return strings.TrimSpace(line.String()), true
+		case "Esc", "Ctrl+c":
+			fmt.Println()
+			return "", false
+		case "Up", "Ctrl+p":
+			if histPos+1 < len(p.History) {
+				if histPos == -1 {
+					draft = line.String()
+				}
+				histPos++
+				line.Set(p.History[histPos])
+			}
+		case "Down", "Ctrl+n":
+			if histPos >= 0 {
+				histPos--
+				if histPos == -1 {
+					line.Set(draft)
+				} else {
+					line.Set(p.History[histPos])
+				}
+			}
+		case "Tab":
+			if p.Complete == nil {
+				continue
+			}
+			if completions == nil {
+				completions = p.Complete(line.String())
+				if prefix := commonPrefix(completions); len(completions) == 1 || len([]rune(prefix)) > len(line.Input) {
+					line.Set(prefix)
+					if len(completions) == 1 {
+						completions = nil
+					}
+					continue
+				}
+			}
+			if len(completions) > 0 {
+				compPos = (compPos + 1) % len(completions)
+				line.Set(completions[compPos])
+			}
+		default:
+			line.Edit(key)
+		}
+	}
+}
+
+// completeTitle completes a section title: the titles starting with
+// input (case-insensitive), or containing it when none does.
+func (a *App) completeTitle(input string) []string {
+	lower := strings.ToLower(strings.TrimSpace(input))
+	var prefixed, containing []string
+	for _, sec := range a.Sections {
+		title := strings.ToLower(sec.Title)
+		switch {
+		case strings.HasPrefix(title, lower):
+			prefixed = append(prefixed, sec.Title)
+		case strings.Contains(title, lower):
+			containing = append(containing, sec.Title)
+		}
+	}
+	if len(prefixed) > 0 {
+		return prefixed
+	}
+	return containing
+}
+
+// completePath completes a file path: the entries of its directory
+// starting with its last element in name order, directories with a
+// trailing slash.
+// Hidden entries are offered only when the element starts with a dot; a
+// leading "~/" stands for the home directory.
+func completePath(input string) []string {
+	dir, base := filepath.Split(input)
+	readDir := dir
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(dir, "~/") {
+		readDir = filepath.Join(home, dir[2:])
+	}
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+	var completions []string
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, base) || strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".") {
+			continue
+		}
+		if e.IsDir() {
+			name += string(filepath.Separator)
+		}
+		completions = append(completions, dir+name)
+	}
+	return completions
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLineEditorEdit(t *testing.T) {
+	var l LineEditor
+	for _, key := range []string{"ệ", "t", "c", "d", "Left", "Left", "Backspace", "Home", "Delete", "End", "Space", "x"} {
+		if !l.Edit(key) {
+			t.Fatalf("Expected %q to edit", key)
+		}
+	}
+	if got := l.String(); got != "cd x" || l.Pos != 4 {
+		t.Errorf("Expected \"cd x\" with the cursor at 4, got %q at %d", got, l.Pos)
+	}
+	if l.Edit("Enter") {
+		t.Error("Expected Enter not to edit")
+	}
+
+	l.Set("kubectl get pods")
+	l.Edit("Ctrl+w")
+	if got := l.String(); got != "kubectl get " {
+		t.Errorf("Ctrl+w: got %q", got)
+	}
+	l.Pos = 7
+	l.Edit("Ctrl+k")
+	l.Edit("Ctrl+a")
+	l.Edit("Ctrl+d")
+	if got := l.String(); got != "ubectl" || l.Pos != 0 {
+		t.Errorf("Ctrl+k/Ctrl+d: got %q at %d", got, l.Pos)
+	}
+}
+
+func TestPushHistory(t *testing.T) {
+	history := pushHistory([]string{"b", "a", "c"}, "a", 3)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(history, want) {
+		t.Errorf("Expected %v, got %v", want, history)
+	}
+	if history = pushHistory(history, "d", 3); len(history) != 3 || history[0] != "d" {
+		t.Errorf("Expected the oldest entry dropped, got %v", history)
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	if got := commonPrefix([]string{"Chapter 1: Basics", "Chapter 2: Advanced"}); got != "Chapter " {
+		t.Errorf("commonPrefix = %q", got)
+	}
+	if got := commonPrefix(nil); got != "" {
+		t.Errorf("commonPrefix(nil) = %q", got)
+	}
+}
+
+func TestCompleteTitle(t *testing.T) {
+	a := createTestApp()
+	if got, want := a.completeTitle("chap"), []string{"Chapter 1: Basics", "Chapter 2: Advanced"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got, want := a.completeTitle("advan"), []string{"Chapter 2: Advanced"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected titles containing the input, got %v", got)
+	}
+}
+
+func TestCompletePath(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "notes.md"), nil, 0o644)
+	os.WriteFile(filepath.Join(dir, ".hidden"), nil, 0o644)
+	os.Mkdir(filepath.Join(dir, "notes"), 0o755)
+
+	prefix := dir + string(filepath.Separator)
+	want := []string{prefix + "notes" + string(filepath.Separator), prefix + "notes.md"}
+	if got := completePath(prefix + "no"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := completePath(prefix); len(got) != 2 {
+		t.Errorf("Expected hidden files left out, got %v", got)
+	}
+	if got := completePath(prefix + "."); len(got) != 1 {
+		t.Errorf("Expected hidden files after a dot, got %v", got)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// QuizQuestion is a practice question about a section.
+type QuizQuestion struct {
+	// Section is the title path of the section the question is about
+	Section  []string  `json:"section"`
+	Question string    `json:"question"`
+	Answer   string    `json:"answer"`
+	Created  time.Time `json:"created"`
+}
+
+// Quiz holds the practice questions of a document. Like the trash it is
+// stored next to the document's state, not in the markdown.
+type Quiz struct {
+	Questions []QuizQuestion `json:"questions"`
+}
+
+// quizPath returns the quiz file of a document.
+func quizPath(docPath string) (string, error) {
+	path, err := documentStateFile(docPath)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(path, ".json") + ".quiz.json", nil
+}
+
+// LoadQuiz reads the quiz from path. A missing file yields an empty quiz.
+func LoadQuiz(path string) (*Quiz, error) {
+	q := &Quiz{}
+	data, err := readSecure(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read quiz %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, q); err != nil {
+		return nil, fmt.Errorf("cannot parse quiz %s: %w", path, err)
+	}
+	return q, nil
+}
+
+// Save writes the quiz to path.
+func (q *Quiz) Save(path string) error {
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeSecure(path, data, 0o600)
+}
+
+// ForSection returns the questions about the section with the given
+// title path.
+func (q *Quiz) ForSection(section []string) []QuizQuestion {
+	var questions []QuizQuestion
+	for _, question := range q.Questions {
+		if strings.Join(question.Section, "\x00") == strings.Join(section, "\x00") {
+			questions = append(questions, question)
+		}
+	}
+	return questions
+}
+
+// loadQuiz reads the document's quiz.
+func (a *App) loadQuiz() (*Quiz, string, error) {
+	path, err := quizPath(a.FilePath)
+	if err != nil {
+		return nil, "", err
+	}
+	q, err := LoadQuiz(path)
+	return q, path, err
+}
+
+// AddQuizQuestions adds questions about section idx to the quiz.
+func (a *App) AddQuizQuestions(idx int, questions []QuizQuestion) error {
+	q, path, err := a.loadQuiz()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, question := range questions {
+		question.Section = a.sectionPath(idx)
+		question.Created = now
+		q.Questions = append(q.Questions, question)
+	}
+	return q.Save(path)
+}
+
+// runQuiz asks the questions about section idx one by one: Space or
+// Enter shows the answer, n or Enter goes to the next question, q stops.
+func runQuiz(idx int) {
+	q, _, err := app.loadQuiz()
+	if err != nil {
+		notify(SeverityError, "Lỗi đọc quiz: %v", err)
+		return
+	}
+	questions := q.ForSection(app.sectionPath(idx))
+	if len(questions) == 0 {
+		notify(SeverityInfo, "Section chưa có câu hỏi (g trong menu AI để tạo)")
+		return
+	}
+
+	width := max(20, app.TermWidth-4)
+	for i, revealed := 0, false; i < len(questions); {
+		ClearScreen()
+		fmt.Printf("%s\n\n", barLine(BgMagenta+White+Bold, fmt.Sprintf(" ❓ QUIZ %d/%d - %s", i+1, len(questions), app.Sections[idx].Title), app.TermWidth))
+		for _, l := range wrapVisible(renderInline(questions[i].Question), width) {
+			fmt.Println("  " + Bold + l + Reset)
+		}
+		fmt.Println()
+		if revealed {
+			for _, l := range wrapVisible(renderInline(questions[i].Answer), width) {
+				fmt.Println("  " + Green + l + Reset)
+			}
+			fmt.Printf("\n%s[n/Enter câu tiếp, q thoát]%s", Dim, Reset)
+		} else {
+			fmt.Printf("%s[Space xem đáp án, n bỏ qua, q thoát]%s", Dim, Reset)
+		}
+
+		switch readKey() {
+		case "Space":
+			revealed = true
+		case "Enter":
+			if !revealed {
+				revealed = true
+			} else {
+				i, revealed = i+1, false
+			}
+		case "n", "Right":
+			i, revealed = i+1, false
+		case "q", "Q", "Esc":
+			return
+		}
+	}
+	notify(SeveritySuccess, "Đã xong %d câu hỏi", len(questions))
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestQuizQuestionsPerSection(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	app := createTestApp()
+	app.FilePath = "/tmp/path.md"
+
+	if err := app.AddQuizQuestions(2, []QuizQuestion{{Question: "Q1", Answer: "A1"}, {Question: "Q2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.AddQuizQuestions(3, []QuizQuestion{{Question: "Q3"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	q, _, err := app.loadQuiz()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := q.ForSection(app.sectionPath(2))
+	if len(got) != 2 || got[0].Question != "Q1" || got[0].Answer != "A1" || got[0].Created.IsZero() {
+		t.Errorf("questions of section 2 = %+v", got)
+	}
+	if got := q.ForSection(app.sectionPath(3)); len(got) != 1 || got[0].Question != "Q3" {
+		t.Errorf("questions of section 3 = %+v", got)
+	}
+	if got := q.ForSection(app.sectionPath(5)); len(got) != 0 {
+		t.Errorf("questions of section 5 = %+v", got)
+	}
+}
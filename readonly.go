@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// Reasons a document is read-only, shown in the header and when an edit
+// key is pressed.
+const (
+	readOnlyFlag   = "--read-only"
+	readOnlyPerm   = "không có quyền ghi file"
+	readOnlyPiped  = "đọc từ stdin, dùng --out <file> để sửa"
+	readOnlyReview = "review"
+	readOnlyLocked = "đang mở ở phiên khác"
+)
+
+// SetReadOnly makes the document read-only for the given reason.
+func (a *App) SetReadOnly(reason string) {
+	a.ReadOnly = true
+	a.ReadOnlyReason = reason
+}
+
+// fileWritable reports whether path can be opened for writing; only a
+// permission error or a read-only file system makes it not writable.
+func fileWritable(path string) bool {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return !errors.Is(err, fs.ErrPermission) && !errors.Is(err, syscall.EROFS)
+	}
+	f.Close()
+	return true
+}
+
+// detectReadOnly returns why the document must be opened read-only: the
+// --read-only flag or a file the user cannot write; "" when it can be
+// edited.
+func (a *App) detectReadOnly() string {
+	switch {
+	case a.ForceReadOnly:
+		return readOnlyFlag
+	case !fileWritable(a.FilePath):
+		return readOnlyPerm
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectReadOnly(t *testing.T) {
+	a := createTestApp()
+	a.FilePath = filepath.Join(t.TempDir(), "doc.md")
+	if err := os.WriteFile(a.FilePath, []byte(sampleMarkdown), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if reason := a.detectReadOnly(); reason != "" {
+		t.Errorf("Expected a writable file to be editable, got %q", reason)
+	}
+
+	a.ForceReadOnly = true
+	if reason := a.detectReadOnly(); reason != readOnlyFlag {
+		t.Errorf("Expected --read-only to win, got %q", reason)
+	}
+	a.ForceReadOnly = false
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write any file")
+	}
+	if err := os.Chmod(a.FilePath, 0o444); err != nil {
+		t.Fatal(err)
+	}
+	if reason := a.detectReadOnly(); reason != readOnlyPerm {
+		t.Errorf("Expected a file without write permission to be read-only, got %q", reason)
+	}
+}
+
+func TestWritable(t *testing.T) {
+	a := withTestApp(t)
+	if !writable() {
+		t.Error("Expected the document to be writable")
+	}
+
+	a.SetReadOnly(readOnlyFlag)
+	if writable() {
+		t.Error("Expected a read-only document to refuse edits")
+	}
+	if msg, ok := a.Status.Current(time.Now()); !ok || !strings.Contains(msg.Text, readOnlyFlag) {
+		t.Errorf("Expected the reason in the status line, got %q", msg.Text)
+	}
+}
+
+func TestSaveFileUnwritable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write any file")
+	}
+	a := createTestApp()
+	a.FilePath = filepath.Join(t.TempDir(), "doc.md")
+	if err := os.WriteFile(a.FilePath, []byte(sampleMarkdown), 0o444); err != nil {
+		t.Fatal(err)
+	}
+	a.FileLines[0] += " (sửa)"
+
+	if err := a.SaveFile(); !errors.Is(err, errUnwritable) {
+		t.Fatalf("Expected errUnwritable, got %v", err)
+	}
+	if !a.ReadOnly || a.ReadOnlyReason != readOnlyPerm {
+		t.Errorf("Expected the document to become read-only, got %v %q", a.ReadOnly, a.ReadOnlyReason)
+	}
+}
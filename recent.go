@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRecentFiles is how many documents the recent files list keeps.
+const maxRecentFiles = 20
+
+// RecentFile is a recently opened document. StateFile and Profile are
+// kept so a profile's copy reopens with its own progress.
+type RecentFile struct {
+	Path      string    `json:"path"`
+	StateFile string    `json:"state_file,omitempty"`
+	Profile   string    `json:"profile,omitempty"`
+	Opened    time.Time `json:"opened"`
+}
+
+// recentPath returns the file the recent files list is stored in.
+func recentPath() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "recent.json"), nil
+}
+
+// LoadRecentFiles reads the recent files list, most recent first. A
+// missing file is an empty list.
+func LoadRecentFiles(path string) ([]RecentFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []RecentFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %w", path, err)
+	}
+	return files, nil
+}
+
+// SaveRecentFiles writes the recent files list.
+func SaveRecentFiles(path string, files []RecentFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(files, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// AddRecentFile moves f to the front of files, dropping an older entry
+// for the same document and the oldest ones beyond maxRecentFiles.
+func AddRecentFile(files []RecentFile, f RecentFile) []RecentFile {
+	list := []RecentFile{f}
+	for _, old := range files {
+		if old.Path != f.Path && len(list) < maxRecentFiles {
+			list = append(list, old)
+		}
+	}
+	return list
+}
+
+// rememberDocument records the open document in the recent files list.
+func rememberDocument() {
+	path, err := recentPath()
+	if err != nil || app.Piped {
+		return
+	}
+	files, err := LoadRecentFiles(path)
+	if err != nil {
+		notify(SeverityWarning, "Không đọc được danh sách file gần đây: %v", err)
+	}
+	f := RecentFile{Path: absPath(app.FilePath), StateFile: absPath(app.StateFile), Profile: app.Profile, Opened: time.Now()}
+	if err := SaveRecentFiles(path, AddRecentFile(files, f)); err != nil {
+		notify(SeverityWarning, "Không lưu được danh sách file gần đây: %v", err)
+	}
+}
+
+// recentDocuments returns the paths of the recent files still on disk,
+// falling back to the documents found in the state directory before the
+// list was kept.
+func recentDocuments(limit int) []string {
+	var docs []string
+	if path, err := recentPath(); err == nil {
+		files, _ := LoadRecentFiles(path)
+		for _, f := range files {
+			if len(docs) < limit && fileExists(f.Path) {
+				docs = append(docs, f.Path)
+			}
+		}
+	}
+	if len(docs) == 0 {
+		return RecentDocuments(limit)
+	}
+	return docs
+}
+
+// Progress returns the overall checkbox progress of the document, leaving
+// out the sections skipped in its state.
+func (f RecentFile) Progress() (checked, total int, err error) {
+	a := NewApp()
+	a.FilePath = f.Path
+	if err := a.LoadFile(); err != nil {
+		return 0, 0, err
+	}
+	a.ParseSections()
+	stateFile := f.StateFile
+	if stateFile == "" {
+		stateFile, _ = documentStateFile(f.Path)
+	}
+	if data, err := readSecure(stateFile); err == nil {
+		if s, err := DecodeState(data); err == nil {
+			a.Skipped = titleSet(s.Skipped)
+		}
+	}
+	checked, total = a.GetTotalProgress()
+	return checked, total, nil
+}
+
+// progressLabel renders the progress of a recent file as a short bar.
+func progressLabel(f RecentFile) string {
+	checked, total, err := f.Progress()
+	if err != nil {
+		return Dim + " (không đọc được)" + Reset
+	}
+	pct := 0.0
+	if total > 0 {
+		pct = float64(checked) / float64(total) * 100
+	}
+	barWidth := 10
+	filled := int(float64(barWidth) * pct / 100)
+	bar := Green + strings.Repeat(glyphs.BarFull, filled) + Dim + strings.Repeat(glyphs.BarEmpty, barWidth-filled) + Reset
+	return fmt.Sprintf(" [%s] %d/%d (%.0f%%)", bar, checked, total, pct)
+}
+
+// recentFileLines renders the numbered recent files with their progress
+// labels, marking the open document and the selected row.
+func recentFileLines(files []RecentFile, progress []string, current string, selected int) []string {
+	var lines []string
+	for i, f := range files {
+		selector := "  "
+		if i == selected {
+			selector = Green + glyphs.Selector + " " + Reset
+		}
+		name := Bold + filepath.Base(f.Path) + Reset
+		if f.Profile != "" {
+			name += Magenta + " @" + f.Profile + Reset
+		}
+		if f.Path == current {
+			name += Cyan + " (hiện tại)" + Reset
+		}
+		lines = append(lines, fmt.Sprintf("%s%s%d.%s %s%s", selector, Cyan, i+1, Reset, name, progress[i]))
+		lines = append(lines, fmt.Sprintf("      %s%s · %s%s", Dim, filepath.Dir(f.Path), f.Opened.Format("2006-01-02 15:04"), Reset))
+	}
+	return lines
+}
+
+// handleRecentFiles shows the recent files with their progress for a
+// quick switch: j/k and Enter or a number 1-9 open one, q/Esc closes.
+// The previous document is preselected, so o Enter flips between two.
+func handleRecentFiles() {
+	path, err := recentPath()
+	if err != nil {
+		notify(SeverityError, "%v", err)
+		return
+	}
+	all, err := LoadRecentFiles(path)
+	if err != nil {
+		notify(SeverityError, "%v", err)
+		return
+	}
+	// Two rows per file; the list is cut to what fits the screen
+	var files []RecentFile
+	var progress []string
+	for _, f := range all {
+		if fileExists(f.Path) && len(files) < max(1, (app.TermHeight-4)/2) {
+			files = append(files, f)
+			progress = append(progress, progressLabel(f))
+		}
+	}
+	if len(files) == 0 {
+		notify(SeverityInfo, "Chưa có file nào mở gần đây (O để duyệt file)")
+		return
+	}
+
+	current := absPath(app.FilePath)
+	selected := 0
+	if files[0].Path == current && len(files) > 1 {
+		selected = 1
+	}
+
+	for {
+		ClearScreen()
+		fmt.Printf("%s\n\n", barLine(BgMagenta+White+Bold, " 🕘 FILE GẦN ĐÂY  (j/k: di chuyển, Enter/1-9: mở, q: đóng)", app.TermWidth))
+		for _, line := range recentFileLines(files, progress, current, selected) {
+			fmt.Println(truncateVisible(line, app.TermWidth))
+		}
+
+		choice := -1
+		switch key := readKey(); key {
+		case "j", "Down":
+			selected = min(selected+1, len(files)-1)
+		case "k", "Up":
+			selected = max(selected-1, 0)
+		case "Enter":
+			choice = selected
+		case "q", "Esc", "o":
+			return
+		default:
+			if d, err := strconv.Atoi(key); err == nil && d >= 1 && d <= len(files) {
+				choice = d - 1
+			}
+		}
+		if choice < 0 {
+			continue
+		}
+
+		f := files[choice]
+		if f.Path == current {
+			return
+		}
+		if err := switchDocument(f.Path, f.StateFile, f.Profile); err != nil {
+			notify(SeverityError, "Không mở được %s: %v", f.Path, err)
+			return
+		}
+		notify(SeveritySuccess, "Đã mở %s", filepath.Base(app.FilePath))
+		return
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAddRecentFile(t *testing.T) {
+	var files []RecentFile
+	for i := 0; i < maxRecentFiles+3; i++ {
+		files = AddRecentFile(files, RecentFile{Path: filepath.Join("/docs", string(rune('a'+i))+".md")})
+	}
+	if len(files) != maxRecentFiles || files[0].Path != "/docs/w.md" {
+		t.Fatalf("Expected the newest %d files, got %d starting with %s", maxRecentFiles, len(files), files[0].Path)
+	}
+
+	files = AddRecentFile(files, RecentFile{Path: "/docs/m.md", Profile: "alice"})
+	if files[0].Path != "/docs/m.md" || files[0].Profile != "alice" || len(files) != maxRecentFiles {
+		t.Errorf("Expected a reopened file to move to the front, got %+v", files[:2])
+	}
+	for _, f := range files[1:] {
+		if f.Path == "/docs/m.md" {
+			t.Error("Expected the older entry to be dropped")
+		}
+	}
+}
+
+func TestRecentFilesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sre-learn", "recent.json")
+	if files, err := LoadRecentFiles(path); err != nil || files != nil {
+		t.Fatalf("Expected a missing list to be empty, got %v, %v", files, err)
+	}
+
+	want := []RecentFile{{Path: "/docs/sre.md", StateFile: "/state/sre.json", Opened: time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)}}
+	if err := SaveRecentFiles(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadRecentFiles(path); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadRecentFiles = %+v, %v", got, err)
+	}
+
+	os.WriteFile(path, []byte("{"), 0o644)
+	if _, err := LoadRecentFiles(path); err == nil {
+		t.Error("Expected a corrupt list to be reported")
+	}
+}
+
+func TestRecentFileProgress(t *testing.T) {
+	dir := t.TempDir()
+	f := RecentFile{Path: filepath.Join(dir, "sre.md"), StateFile: filepath.Join(dir, "state.json")}
+	os.WriteFile(f.Path, []byte(sampleMarkdown), 0o644)
+
+	if checked, total, err := f.Progress(); err != nil || checked != 3 || total != 6 {
+		t.Errorf("Progress = %d/%d, %v", checked, total, err)
+	}
+
+	data, _ := json.Marshal(State{Version: stateVersion, Skipped: []string{"Exercise 1"}})
+	os.WriteFile(f.StateFile, data, 0o644)
+	if checked, total, _ := f.Progress(); checked != 1 || total != 4 {
+		t.Errorf("Expected skipped sections to be left out, got %d/%d", checked, total)
+	}
+
+	f.Path = filepath.Join(dir, "missing.md")
+	if _, _, err := f.Progress(); err == nil {
+		t.Error("Expected a missing document to fail")
+	}
+}
+
+func TestRecentDocumentsFromList(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "k8s.md")
+	os.WriteFile(doc, []byte("# x\n"), 0o644)
+
+	path, err := recentPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	SaveRecentFiles(path, []RecentFile{{Path: filepath.Join(dir, "gone.md")}, {Path: doc}})
+	if got := recentDocuments(5); !reflect.DeepEqual(got, []string{doc}) {
+		t.Errorf("recentDocuments = %v", got)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// RecoveryAction is a way out of an error offered on the error screen.
+type RecoveryAction struct {
+	Key   string
+	Label string
+	// Run resolves the error; the error it returns replaces the one shown
+	Run func() error
+}
+
+// showError shows err in an overlay with the actions that can resolve
+// it, until one of them succeeds (true) or the error is ignored with Esc
+// (false). The session goes on either way: unsaved edits stay in memory
+// and in the journal. A cooked terminal is put in raw mode meanwhile.
+func showError(title string, err error, actions []RecoveryAction) bool {
+	logger.Error(title, "err", err)
+	if !terminal.raw {
+		terminal.SetRawMode(true)
+		defer terminal.SetRawMode(false)
+	}
+	for {
+		ClearScreen()
+		fmt.Printf("%s\n\n", barLine(BgRed+White+Bold, " ❌ "+title, app.TermWidth))
+		for _, line := range wrapVisible(err.Error(), max(20, app.TermWidth-4)) {
+			fmt.Printf("  %s\n", line)
+		}
+		fmt.Println()
+		for _, a := range actions {
+			fmt.Printf("  %s[%s]%s %s\n", Bold+Cyan, a.Key, Reset, a.Label)
+		}
+		fmt.Printf("  %s[Esc]%s Bỏ qua, tiếp tục làm việc\n", Bold+Cyan, Reset)
+
+		key := readKey()
+		if key == "Esc" || key == "Ctrl+c" {
+			return false
+		}
+		for _, a := range actions {
+			if key != a.Key {
+				continue
+			}
+			if err = a.Run(); err == nil {
+				return true
+			}
+			logger.Error(title, "action", a.Label, "err", err)
+		}
+	}
+}
+
+// saveDocument saves the file, showing the error screen when that fails:
+// retry (once the file is writable again), save a copy elsewhere or copy
+// the document to the clipboard. Returns whether the error was resolved.
+func saveDocument() bool {
+	err := app.SaveFile()
+	if err == nil {
+		return true
+	}
+	return showError("Lỗi lưu file", err, saveRecoveryActions())
+}
+
+// saveRecoveryActions are the ways out of a failed save.
+func saveRecoveryActions() []RecoveryAction {
+	return []RecoveryAction{
+		{"r", "Thử lại", retrySave},
+		{"w", "Lưu vào file khác", saveElsewhere},
+		{"c", "Chép toàn bộ tài liệu vào clipboard", func() error {
+			method, err := CopyToClipboard(strings.Join(app.FileLines, "\n"))
+			if err == nil {
+				notify(SeveritySuccess, "Đã chép tài liệu vào clipboard (%s), file chưa được lưu", method)
+			}
+			return err
+		}},
+	}
+}
+
+// retrySave saves again, first making the document editable again when
+// it turned read-only because the file could not be written and can be
+// now.
+func retrySave() error {
+	if app.ReadOnlyReason == readOnlyPerm && fileWritable(app.FilePath) {
+		app.ReadOnly, app.ReadOnlyReason = false, ""
+	}
+	return app.SaveFile()
+}
+
+// saveElsewhere writes the document to a path the user types. The file
+// itself stays unsaved, so the edits are kept in the journal too.
+func saveElsewhere() error {
+	fmt.Println()
+	path, ok := readPrompt(Prompt{Label: "Lưu vào (Tab: gợi ý): ", History: promptHistories["path"], Complete: completePath})
+	if !ok || path == "" {
+		return errors.New("no file chosen")
+	}
+	data, err := encodeDocument(app.FileLines)
+	if err != nil {
+		return err
+	}
+	if err := writeDocument(cleanAttachmentPath(path), "", data); err != nil {
+		return err
+	}
+	logger.Info("saved copy of document", "path", app.FilePath, "copy", path)
+	notify(SeveritySuccess, "Đã lưu bản sao vào %s, file gốc chưa được lưu", path)
+	return nil
+}
+
+// recoverPanic turns a panic in a key action into the error screen, so a
+// bug in one feature does not end the session and lose its edits. Use
+// with defer.
+func recoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	logger.Error("panic", "err", r, "stack", string(debug.Stack()))
+	terminal.SetRawMode(true)
+	showError("Lỗi không mong muốn", fmt.Errorf("%v (chi tiết trong log)", r), nil)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withKeys feeds keys to the error screen in a raw terminal.
+func withKeys(t *testing.T, keys string) {
+	t.Helper()
+	savedKeyboard, savedTerminal := keyboard, terminal
+	keyboard = &Keyboard{src: strings.NewReader(keys)}
+	terminal = &Terminal{raw: true}
+	t.Cleanup(func() { keyboard, terminal = savedKeyboard, savedTerminal })
+}
+
+func TestShowErrorRetry(t *testing.T) {
+	withTestApp(t)
+	withKeys(t, "xrr")
+	calls := 0
+	retry := func() error {
+		calls++
+		if calls == 1 {
+			return errors.New("still failing")
+		}
+		return nil
+	}
+	if !showError("Lỗi lưu file", errors.New("permission denied"), []RecoveryAction{{"r", "Thử lại", retry}}) {
+		t.Error("Expected the error to be resolved by the second retry")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 retries, got %d", calls)
+	}
+}
+
+func TestShowErrorIgnore(t *testing.T) {
+	withTestApp(t)
+	withKeys(t, "\x1b")
+	if showError("Lỗi lưu file", errors.New("permission denied"), saveRecoveryActions()) {
+		t.Error("Expected Esc to leave the error unresolved")
+	}
+}
+
+func TestRetrySaveAfterPermissionFixed(t *testing.T) {
+	a := withTestApp(t)
+	a.FilePath = filepath.Join(t.TempDir(), "doc.md")
+	if err := os.WriteFile(a.FilePath, []byte(sampleMarkdown), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a.LoadFile()
+	a.SetReadOnly(readOnlyPerm)
+	a.Sections[2].SetContent(a.Sections[2].Content() + "\n- [ ] New task")
+	a.UpdateFileSection(2)
+
+	if err := retrySave(); err != nil {
+		t.Fatal(err)
+	}
+	if a.ReadOnly {
+		t.Error("Expected the document to be editable again")
+	}
+	if data, _ := os.ReadFile(a.FilePath); !strings.Contains(string(data), "New task") {
+		t.Error("Expected the edit to be saved")
+	}
+}
+
+func TestRecoverPanic(t *testing.T) {
+	withTestApp(t)
+	withKeys(t, "\x1b")
+	func() {
+		defer recoverPanic()
+		var sections []Section
+		_ = sections[3]
+	}()
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recurrenceRegex matches the recurrence annotation of a checklist item:
+// "- [ ] Do a chaos drill @every(7d)", with h, d or w units.
+var recurrenceRegex = regexp.MustCompile(`@every\((\d+)([hdw])\)`)
+
+// parseRecurrence returns the interval of a recurring item, or false if
+// the text has no (valid) @every annotation.
+func parseRecurrence(text string) (time.Duration, bool) {
+	m := recurrenceRegex.FindStringSubmatch(text)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[m[2]]
+	return time.Duration(n) * unit, true
+}
+
+// scheduleRecurrence records when a recurring item checked at the given
+// time reverts to unchecked, or forgets it when the item is unchecked.
+func (a *App) scheduleRecurrence(section, line string, checked bool, at time.Time) {
+	interval, ok := parseRecurrence(line)
+	if !ok {
+		return
+	}
+	key := checkboxItemKey(section, line)
+	if !checked {
+		delete(a.Recurring, key)
+		return
+	}
+	if a.Recurring == nil {
+		a.Recurring = make(map[string]time.Time)
+	}
+	a.Recurring[key] = at.Add(interval)
+}
+
+// recurringDue returns when the checked recurring item reverts: its
+// scheduled time, or the last completion in the history plus the
+// interval for items checked outside the viewer.
+func (a *App) recurringDue(section, line string, interval time.Duration) (time.Time, bool) {
+	if due, ok := a.Recurring[checkboxItemKey(section, line)]; ok {
+		return due, true
+	}
+	if last := a.lastChecked(section, checkboxText(line)); !last.IsZero() {
+		return last.Add(interval), true
+	}
+	return time.Time{}, false
+}
+
+// ReopenRecurring unchecks the recurring items whose interval has passed
+// at now and returns their number. The file lines are updated but not
+// saved; the completions stay in the history.
+func (a *App) ReopenRecurring(now time.Time) int {
+	reopened := 0
+	for i := range a.Sections {
+		sec := &a.Sections[i]
+		lines := strings.Split(sec.Content(), "\n")
+		changed := false
+		for j, line := range lines {
+			interval, ok := parseRecurrence(line)
+			if !ok || !isChecked(line) {
+				continue
+			}
+			if due, ok := a.recurringDue(sec.Title, line, interval); ok && !now.Before(due) {
+				lines[j] = setCheckbox(line, false)
+				delete(a.Recurring, checkboxItemKey(sec.Title, line))
+				changed = true
+				reopened++
+			}
+		}
+		if changed {
+			sec.SetContent(strings.Join(lines, "\n"))
+			a.UpdateFileSection(i)
+		}
+	}
+	if reopened > 0 {
+		a.ParseSections()
+	}
+	return reopened
+}
+
+// AgendaItem is a recurring item in the agenda.
+type AgendaItem struct {
+	SectionIdx int
+	Text       string
+	Interval   time.Duration
+	// Due is when the item reverts to unchecked; zero for items due now
+	Due time.Time
+}
+
+// Agenda returns the recurring items: those due now (unchecked) first,
+// then the checked ones by the time they come back.
+func (a *App) Agenda() []AgendaItem {
+	var items []AgendaItem
+	for i, sec := range a.Sections {
+		for _, line := range strings.Split(sec.Content(), "\n") {
+			interval, ok := parseRecurrence(line)
+			if !ok || !isCheckbox(line) || checkboxState(line) == stateWontDo {
+				continue
+			}
+			item := AgendaItem{SectionIdx: i, Text: checkboxText(line), Interval: interval}
+			if isChecked(line) {
+				due, ok := a.recurringDue(sec.Title, line, interval)
+				if !ok {
+					continue
+				}
+				item.Due = due
+			}
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Due.Before(items[j].Due) })
+	return items
+}
+
+// formatInterval writes an interval as the @every annotation does.
+func formatInterval(d time.Duration) string {
+	switch {
+	case d%(7*24*time.Hour) == 0:
+		return fmt.Sprintf("%dw", d/(7*24*time.Hour))
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return fmt.Sprintf("%dh", d/time.Hour)
+}
+
+// renderRecurrence replaces @every annotations with a dimmed 🔁 marker.
+func renderRecurrence(line string) string {
+	return recurrenceRegex.ReplaceAllString(line, Dim+"🔁 $1$2"+Reset)
+}
+
+// untilText describes the time left until an item comes back.
+func untilText(d time.Duration) string {
+	if days := int(d.Hours() / 24); days > 0 {
+		return fmt.Sprintf("còn %d ngày", days)
+	}
+	return fmt.Sprintf("còn %d giờ", max(0, int(d.Hours())))
+}
+
+// agendaLines formats the agenda for the overlay; sectionTitles are the
+// titles of the document's sections.
+func agendaLines(items []AgendaItem, sectionTitles []string, now time.Time) []string {
+	var lines []string
+	due := true
+	for i, item := range items {
+		if i == 0 || due != item.Due.IsZero() {
+			due = item.Due.IsZero()
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			if due {
+				lines = append(lines, Bold+Yellow+"Cần làm"+Reset)
+			} else {
+				lines = append(lines, Bold+Green+"Đã làm, lặp lại sau"+Reset)
+			}
+		}
+		state, when := byte(stateOpen), ""
+		if !due {
+			state = stateDone
+			when = fmt.Sprintf(" %s(%s, %s)%s", Dim, item.Due.Format("2006-01-02 15:04"), untilText(item.Due.Sub(now)), Reset)
+		}
+		text := strings.TrimSpace(recurrenceRegex.ReplaceAllString(item.Text, ""))
+		lines = append(lines, fmt.Sprintf("  %s %s %s🔁 %s · %s%s%s", checkboxGlyph(state),
+			renderInline(text), Dim, formatInterval(item.Interval), sectionTitles[item.SectionIdx], Reset, when))
+	}
+	return lines
+}
+
+// handleAgenda reopens the recurring items that are due and shows the
+// agenda of recurring practice.
+func handleAgenda() {
+	now := time.Now()
+	if !app.ReadOnly {
+		if n := app.ReopenRecurring(now); n > 0 {
+			saveDocument()
+			app.SaveState(renderer.PageSize)
+		}
+	}
+	items := app.Agenda()
+	if len(items) == 0 {
+		notify(SeverityInfo, "Chưa có việc lặp lại: thêm @every(7d) vào cuối một checkbox")
+		return
+	}
+	titles := make([]string, len(app.Sections))
+	for i, sec := range app.Sections {
+		titles[i] = sec.Title
+	}
+	showOverlay(fmt.Sprintf("🔁 AGENDA (%d)", len(items)), agendaLines(items, titles, now))
+}
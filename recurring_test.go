@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := map[string]time.Duration{
+		"Chaos drill @every(7d)":   7 * 24 * time.Hour,
+		"Review alerts @every(2w)": 14 * 24 * time.Hour,
+		"On-call sync @every(12h)": 12 * time.Hour,
+	}
+	for text, want := range tests {
+		if got, ok := parseRecurrence(text); !ok || got != want {
+			t.Errorf("parseRecurrence(%q) = %v, %v; want %v", text, got, ok, want)
+		}
+	}
+	for _, text := range []string{"no annotation", "@every(0d)", "@every(3m)"} {
+		if _, ok := parseRecurrence(text); ok {
+			t.Errorf("Expected %q not to recur", text)
+		}
+	}
+}
+
+func TestRecurringItemReopens(t *testing.T) {
+	app := NewApp()
+	app.FileLines = strings.Split("## Practice\n- [ ] Chaos drill @every(7d)\n- [ ] One-off lab", "\n")
+	app.ParseSections()
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	app.SetCheckboxState(0, stateDone)
+	key := checkboxItemKey("Practice", "- [ ] Chaos drill @every(7d)")
+	// recordCheckbox uses the wall clock; schedule from a fixed time
+	app.scheduleRecurrence("Practice", "- [x] Chaos drill @every(7d)", true, start)
+	if due := app.Recurring[key]; !due.Equal(start.Add(7 * 24 * time.Hour)) {
+		t.Fatalf("due = %v", due)
+	}
+
+	if n := app.ReopenRecurring(start.Add(6 * 24 * time.Hour)); n != 0 {
+		t.Errorf("Expected nothing to reopen before the interval, got %d", n)
+	}
+	items := app.Agenda()
+	if len(items) != 1 || items[0].Due.IsZero() {
+		t.Errorf("Agenda = %+v", items)
+	}
+
+	if n := app.ReopenRecurring(start.Add(7 * 24 * time.Hour)); n != 1 {
+		t.Fatalf("Expected the drill to reopen, got %d", n)
+	}
+	if !strings.Contains(strings.Join(app.FileLines, "\n"), "- [ ] Chaos drill @every(7d)") {
+		t.Errorf("FileLines = %q", app.FileLines)
+	}
+	if _, ok := app.Recurring[key]; ok {
+		t.Error("Expected the schedule to be cleared")
+	}
+	// The completion stays in the history; the agenda lists it as due
+	if len(app.History) != 1 || !app.History[0].Checked {
+		t.Errorf("History = %+v", app.History)
+	}
+	if items := app.Agenda(); len(items) != 1 || !items[0].Due.IsZero() {
+		t.Errorf("Agenda = %+v", items)
+	}
+}
+
+func TestRenderRecurrence(t *testing.T) {
+	if got := stripANSI(RenderLine("- [ ] Chaos drill @every(7d)", 80)); !strings.Contains(got, "Chaos drill 🔁 7d") {
+		t.Errorf("RenderLine = %q", got)
+	}
+	lines := agendaLines([]AgendaItem{{Text: "Chaos drill @every(1w)", Interval: 7 * 24 * time.Hour}}, []string{"Practice"}, time.Now())
+	if len(lines) != 2 || !strings.Contains(stripANSI(lines[1]), "Chaos drill 🔁 1w · Practice") {
+		t.Errorf("agendaLines = %q", lines)
+	}
+}
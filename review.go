@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// noteTimestampRegex extracts the timestamp from a note banner line.
+var noteTimestampRegex = regexp.MustCompile(`\*\*Ghi chú \[(\d{4}-\d{2}-\d{2} \d{2}:\d{2})\]:\*\*`)
+
+// ReviewComment is a mentor comment attached to a section.
+// Comments are kept apart from regular notes so they never end up in
+// the mentee's markdown file.
+type ReviewComment struct {
+	Section string    `json:"section"`
+	Author  string    `json:"author"`
+	Text    string    `json:"text"`
+	Created time.Time `json:"created"`
+}
+
+// ReviewSnapshot records which checkbox items were checked at a point in time.
+// A snapshot is taken at the end of each review session and serves as
+// the baseline for highlighting changes in the next one.
+type ReviewSnapshot struct {
+	Taken   time.Time `json:"taken"`
+	Checked []string  `json:"checked"`
+}
+
+// ReviewStore holds all review data for a document.
+// It is persisted as JSON next to the document (see reviewPath).
+type ReviewStore struct {
+	Comments  []ReviewComment  `json:"comments"`
+	Snapshots []ReviewSnapshot `json:"snapshots"`
+}
+
+// reviewPath returns the review sidecar path for a document.
+func reviewPath(docPath string) string {
+	return docPath + ".review.json"
+}
+
+// LoadReviewStore reads review data from path.
+// A missing file yields an empty store.
+func LoadReviewStore(path string) (*ReviewStore, error) {
+	store := &ReviewStore{}
+	data, err := readSecure(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read review file %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("cannot parse review file %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// Save writes the review data to path.
+func (s *ReviewStore) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeSecure(path, data, 0o600)
+}
+
+// AddComment attaches a new comment to the section with the given title.
+func (s *ReviewStore) AddComment(section, author, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	s.Comments = append(s.Comments, ReviewComment{
+		Section: section,
+		Author:  author,
+		Text:    text,
+		Created: time.Now(),
+	})
+}
+
+// CommentsFor returns the comments attached to a section title.
+func (s *ReviewStore) CommentsFor(section string) []ReviewComment {
+	if s == nil {
+		return nil
+	}
+	var result []ReviewComment
+	for _, c := range s.Comments {
+		if c.Section == section {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// TakeSnapshot records the currently checked items of the app.
+func (s *ReviewStore) TakeSnapshot(a *App) {
+	checked := []string{}
+	for key, done := range checkedItems(a) {
+		if done {
+			checked = append(checked, key)
+		}
+	}
+	sort.Strings(checked)
+	s.Snapshots = append(s.Snapshots, ReviewSnapshot{Taken: time.Now(), Checked: checked})
+}
+
+// BaselineAt returns the latest snapshot taken at or before since.
+// Returns nil if no such snapshot exists.
+func (s *ReviewStore) BaselineAt(since time.Time) *ReviewSnapshot {
+	var best *ReviewSnapshot
+	for i := range s.Snapshots {
+		snap := &s.Snapshots[i]
+		if snap.Taken.After(since) {
+			continue
+		}
+		if best == nil || snap.Taken.After(best.Taken) {
+			best = snap
+		}
+	}
+	return best
+}
+
+// checkboxItemKey identifies a checkbox item across document versions.
+func checkboxItemKey(sectionTitle, line string) string {
+	return sectionTitle + "\x1f" + checkboxText(line)
+}
+
+// checkedItems maps every checkbox item key to its checked state.
+func checkedItems(a *App) map[string]bool {
+	items := make(map[string]bool)
+	for _, sec := range a.Sections {
+		for _, line := range strings.Split(sec.Content(), "\n") {
+			if isCheckbox(line) {
+				items[checkboxItemKey(sec.Title, line)] = isChecked(line)
+			}
+		}
+	}
+	return items
+}
+
+// ChangedLines returns the content line indices of a section that changed
+// since the given time: checkboxes toggled since then to a different
+// state and notes written after since. Checkboxes are compared against
+// their state in the history, or against the baseline snapshot for items
+// the history has no transitions of since then (edited outside the app
+// or checked before the history was kept).
+func (a *App) ChangedLines(sectionIdx int, since time.Time, baseline *ReviewSnapshot) map[int]bool {
+	changed := make(map[int]bool)
+	if sectionIdx < 0 || sectionIdx >= len(a.Sections) {
+		return changed
+	}
+
+	var wasChecked map[string]bool
+	if baseline != nil {
+		wasChecked = make(map[string]bool, len(baseline.Checked))
+		for _, key := range baseline.Checked {
+			wasChecked[key] = true
+		}
+	}
+
+	sec := a.Sections[sectionIdx]
+	inNewNote := false
+	for i, line := range strings.Split(sec.Content(), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if m := noteTimestampRegex.FindStringSubmatch(trimmed); m != nil {
+			ts, err := time.ParseInLocation("2006-01-02 15:04", m[1], time.Local)
+			inNewNote = err == nil && !ts.Before(since)
+		} else if !strings.HasPrefix(trimmed, ">") {
+			inNewNote = false
+		}
+		if inNewNote {
+			changed[i] = true
+			continue
+		}
+
+		if !isCheckbox(line) {
+			continue
+		}
+		before, ok := a.checkedBefore(sec.Title, checkboxText(line), since)
+		if !ok {
+			if wasChecked == nil {
+				continue
+			}
+			before = wasChecked[checkboxItemKey(sec.Title, line)]
+		}
+		if before != isChecked(line) {
+			changed[i] = true
+		}
+	}
+	return changed
+}
+
+// reviewAuthor returns the name recorded on review comments.
+func reviewAuthor() string {
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return "mentor"
+}
+
+// handleReviewComment prompts the mentor for a comment on the current section.
+func handleReviewComment() {
+	sec := app.GetCurrentSection()
+	if sec == nil {
+		return
+	}
+
+	terminal.SetRawMode(false)
+	ClearScreen()
+
+	fmt.Printf("%s💬 NHẬN XÉT - %s%s\n", Bold+Magenta, sec.Title, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+
+	for _, c := range app.Reviews.CommentsFor(sec.Title) {
+		fmt.Printf("  %s[%s] %s:%s %s\n", Dim, c.Created.Format("2006-01-02"), c.Author, Reset, c.Text)
+	}
+
+	fmt.Printf("\n%sNhận xét mới (Enter để hủy):%s ", Bold, Reset)
+	inputReader := bufio.NewReader(keyboard)
+	text, _ := inputReader.ReadString('\n')
+
+	if strings.TrimSpace(text) != "" {
+		app.Reviews.AddComment(sec.Title, reviewAuthor(), text)
+		if err := app.Reviews.Save(reviewPath(app.FilePath)); err != nil {
+			notify(SeverityError, "Lỗi lưu nhận xét: %v", err)
+		} else {
+			notify(SeveritySuccess, "Đã thêm nhận xét")
+		}
+	}
+
+	terminal.SetRawMode(true)
+}
+
+// finishReview records a snapshot so the next review can diff against it.
+func finishReview() {
+	if !app.ReviewMode {
+		return
+	}
+	app.Reviews.TakeSnapshot(app)
+	app.Reviews.Save(reviewPath(app.FilePath))
+}
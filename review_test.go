@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================================
+// Review Tests
+// ============================================================================
+
+func TestReviewStoreSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.md.review.json")
+
+	store := &ReviewStore{}
+	store.AddComment("Chapter 1: Basics", "mentor", "Good progress")
+	store.AddComment("Chapter 1: Basics", "mentor", "   ")
+
+	if err := store.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadReviewStore(path)
+	if err != nil {
+		t.Fatalf("LoadReviewStore failed: %v", err)
+	}
+
+	comments := loaded.CommentsFor("Chapter 1: Basics")
+	if len(comments) != 1 {
+		t.Fatalf("Expected 1 comment (blank ignored), got %d", len(comments))
+	}
+
+	if comments[0].Text != "Good progress" {
+		t.Errorf("Expected 'Good progress', got '%s'", comments[0].Text)
+	}
+}
+
+func TestReviewStoreSaveIsPrivate(t *testing.T) {
+	withVault(t, "secret")
+	path := filepath.Join(t.TempDir(), "doc.md.review.json")
+
+	store := &ReviewStore{}
+	store.AddComment("Chapter 1: Basics", "mentor", "Needs work on SLOs")
+	if err := store.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected a private review file, got %v (%v)", info.Mode(), err)
+	}
+	if data, err := os.ReadFile(path); err != nil || !isEncrypted(data) {
+		t.Errorf("Expected an encrypted review file (%v)", err)
+	}
+	loaded, err := LoadReviewStore(path)
+	if err != nil || len(loaded.CommentsFor("Chapter 1: Basics")) != 1 {
+		t.Errorf("Expected the comment to load back, got %v (%v)", loaded, err)
+	}
+}
+
+func TestLoadReviewStoreMissingFile(t *testing.T) {
+	store, err := LoadReviewStore(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Expected no error for missing file, got %v", err)
+	}
+
+	if len(store.Comments) != 0 {
+		t.Error("Expected empty store for missing file")
+	}
+}
+
+func TestReviewBaselineAt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	store := &ReviewStore{Snapshots: []ReviewSnapshot{
+		{Taken: day(1)},
+		{Taken: day(10)},
+		{Taken: day(20)},
+	}}
+
+	if got := store.BaselineAt(day(15)); got == nil || !got.Taken.Equal(day(10)) {
+		t.Errorf("Expected baseline from day 10, got %v", got)
+	}
+
+	if got := store.BaselineAt(day(0)); got != nil {
+		t.Errorf("Expected no baseline before first snapshot, got %v", got)
+	}
+}
+
+func TestChangedLinesCheckboxes(t *testing.T) {
+	app := createTestApp()
+	store := &ReviewStore{}
+	store.TakeSnapshot(app)
+	baseline := &store.Snapshots[0]
+
+	// Find the Basics chapter and toggle its first checkbox
+	for i, sec := range app.Sections {
+		if sec.Title == "Chapter 1: Basics" {
+			app.CurrentIdx = i
+		}
+	}
+	lines := app.GetCheckboxLines()
+	app.ToggleCheckbox(lines[0])
+
+	changed := app.ChangedLines(app.CurrentIdx, time.Now(), baseline)
+
+	if len(changed) != 1 || !changed[lines[0]] {
+		t.Errorf("Expected only line %d to be changed, got %v", lines[0], changed)
+	}
+}
+
+func TestChangedLinesNotes(t *testing.T) {
+	app := NewApp()
+	app.FileContent = `# Section
+
+Text.
+
+> **Ghi chú [2025-01-01 10:00]:** Old note
+
+> **Ghi chú [2025-02-01 10:00]:** New note
+> second line`
+	app.FileLines = strings.Split(app.FileContent, "\n")
+	app.ParseSections()
+
+	since := time.Date(2025, 1, 15, 0, 0, 0, 0, time.Local)
+	changed := app.ChangedLines(0, since, nil)
+
+	if len(changed) != 2 {
+		t.Errorf("Expected 2 changed lines for the new note, got %v", changed)
+	}
+}
+
+func TestChangedLinesFromHistory(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 2
+	lines := app.GetCheckboxLines()
+	since := time.Now().Add(-time.Hour)
+
+	// Toggled twice since the review date: back where it was
+	app.ToggleCheckbox(lines[0])
+	app.ToggleCheckbox(lines[0])
+	// Toggled before the review date only
+	app.ToggleCheckbox(lines[1])
+	app.History[len(app.History)-1].Time = since.Add(-time.Hour)
+	// Toggled since the review date
+	app.ToggleCheckbox(lines[2])
+
+	changed := app.ChangedLines(app.CurrentIdx, since, nil)
+
+	if len(changed) != 1 || !changed[lines[2]] {
+		t.Errorf("Expected only line %d to be changed without a snapshot, got %v", lines[2], changed)
+	}
+}
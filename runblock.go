@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// shellLanguages maps code block languages that can be executed to the
+// interpreter used for them.
+var shellLanguages = map[string]string{
+	"bash":  "bash",
+	"sh":    "sh",
+	"shell": "sh",
+}
+
+// shellBlocks returns the code blocks of content that can be executed.
+func shellBlocks(content string) []CodeBlock {
+	var result []CodeBlock
+	for _, block := range extractCodeBlocks(content) {
+		if _, ok := shellLanguages[strings.ToLower(block.Lang)]; ok && strings.TrimSpace(block.Code) != "" {
+			result = append(result, block)
+		}
+	}
+	return result
+}
+
+// RunResult is the outcome of executing a code block.
+type RunResult struct {
+	Output   string
+	ExitCode int
+	Duration time.Duration
+	TimedOut bool
+}
+
+// RunCodeBlock executes a shell code block in a subshell.
+// The shell runs in a fresh temporary directory that is removed afterwards,
+// with no stdin and a timeout, so practice snippets cannot wait for input
+// or litter the working directory.
+func RunCodeBlock(block CodeBlock, timeout time.Duration) (RunResult, error) {
+	shell := shellLanguages[strings.ToLower(block.Lang)]
+	if _, err := exec.LookPath(shell); err != nil {
+		shell = "sh"
+	}
+
+	dir, err := os.MkdirTemp("", "sre-run-*")
+	if err != nil {
+		return RunResult{}, fmt.Errorf("cannot create sandbox directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, shell, "-c", block.Code)
+	cmd.Dir = dir
+	// Don't wait for orphaned children still holding the output pipe
+	cmd.WaitDelay = 500 * time.Millisecond
+
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	result := RunResult{Output: string(output), Duration: time.Since(start)}
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		result.TimedOut = true
+		result.ExitCode = -1
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return result, err
+	}
+	return result, nil
+}
+
+// runLogNote summarizes an executed code block for the section notes.
+func runLogNote(block CodeBlock, result RunResult) string {
+	command := strings.TrimSpace(block.Code)
+	if first, _, multi := strings.Cut(command, "\n"); multi {
+		command = first + " …"
+	}
+
+	status := fmt.Sprintf("exit %d", result.ExitCode)
+	if result.TimedOut {
+		status = "timeout"
+	}
+	return fmt.Sprintf("▶ Đã chạy `%s` (%s, %s)", command, status, result.Duration.Round(time.Millisecond))
+}
+
+// handleRunBlock lists the shell code blocks of the current section,
+// runs the selected one after confirmation and shows its output.
+func handleRunBlock() {
+	sec := app.GetCurrentSection()
+	if sec == nil {
+		return
+	}
+	blocks := shellBlocks(sec.Content())
+	if len(blocks) == 0 {
+		return
+	}
+
+	items := make([]string, len(blocks))
+	for i, block := range blocks {
+		items[i] = block.Code
+	}
+
+	ClearScreen()
+	fmt.Printf("%s%s CHẠY CODE BLOCK%s\n", Bold+Cyan, glyphs.Selector, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+
+	choice := pickItem("Code block", items)
+	if choice < 0 {
+		return
+	}
+	block := blocks[choice]
+
+	terminal.SetRawMode(false)
+	ClearScreen()
+	fmt.Printf("%s%s Sẽ chạy (%s, thư mục tạm, timeout %s):%s\n\n", Bold+Yellow, glyphs.Selector, block.Lang, config.RunTimeout, Reset)
+	fmt.Println(BgBlack + Cyan + block.Code + Reset)
+	fmt.Printf("\n%sXác nhận chạy? (y/N): %s", Yellow, Reset)
+
+	confirm, _ := bufio.NewReader(keyboard).ReadString('\n')
+	confirm = strings.TrimSpace(strings.ToLower(confirm))
+	if confirm != "y" && confirm != "yes" {
+		terminal.SetRawMode(true)
+		return
+	}
+
+	fmt.Printf("\n%sĐang chạy...%s\n", Dim, Reset)
+	result, err := RunCodeBlock(block, config.RunTimeout)
+	terminal.SetRawMode(true)
+
+	if err != nil {
+		showOverlay("❌ Lỗi chạy code block", []string{err.Error()})
+		return
+	}
+
+	// Log the run to the section notes
+	if !app.ReadOnly {
+		app.AddNote(runLogNote(block, result))
+		app.UpdateFileSection(app.CurrentIdx)
+		saveDocument()
+	}
+
+	lines := strings.Split(strings.TrimRight(result.Output, "\n"), "\n")
+	title := fmt.Sprintf("%s Kết quả: exit %d (%s)", glyphs.Selector, result.ExitCode, result.Duration.Round(time.Millisecond))
+	if result.TimedOut {
+		title = fmt.Sprintf("⏱ Hết thời gian sau %s", config.RunTimeout)
+	}
+	showOverlay(title, lines)
+}
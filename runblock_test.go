@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================================
+// Code Block Execution Tests
+// ============================================================================
+
+func TestShellBlocks(t *testing.T) {
+	content := "```bash\necho a\n```\n```go\nfmt.Println()\n```\n```sh\n```\n```shell\nls\n```"
+
+	blocks := shellBlocks(content)
+
+	if len(blocks) != 2 {
+		t.Fatalf("Expected 2 runnable blocks (bash, shell), got %d", len(blocks))
+	}
+}
+
+func TestRunCodeBlock(t *testing.T) {
+	block := CodeBlock{Lang: "sh", Code: "echo hello\npwd\nexit 3"}
+
+	result, err := RunCodeBlock(block, 10*time.Second)
+	if err != nil {
+		t.Fatalf("RunCodeBlock failed: %v", err)
+	}
+
+	if !strings.Contains(result.Output, "hello") {
+		t.Errorf("Expected output to contain 'hello', got %q", result.Output)
+	}
+
+	if !strings.Contains(result.Output, "sre-run-") {
+		t.Errorf("Expected command to run in a temp directory, got %q", result.Output)
+	}
+
+	if result.ExitCode != 3 {
+		t.Errorf("Expected exit code 3, got %d", result.ExitCode)
+	}
+}
+
+func TestRunCodeBlockTimeout(t *testing.T) {
+	block := CodeBlock{Lang: "sh", Code: "sleep 5"}
+
+	result, err := RunCodeBlock(block, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("RunCodeBlock failed: %v", err)
+	}
+
+	if !result.TimedOut {
+		t.Error("Expected run to time out")
+	}
+}
+
+func TestRunLogNote(t *testing.T) {
+	block := CodeBlock{Lang: "bash", Code: "kubectl get pods\nkubectl get svc"}
+
+	note := runLogNote(block, RunResult{ExitCode: 0, Duration: 1500 * time.Millisecond})
+
+	if !strings.Contains(note, "`kubectl get pods …`") {
+		t.Errorf("Expected first command line in note, got %q", note)
+	}
+
+	if !strings.Contains(note, "exit 0") {
+		t.Errorf("Expected exit status in note, got %q", note)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"time"
+)
+
+// defaultLargeFileSize is the document size from which the large-file
+// mode is used (see large_file_mb).
+const defaultLargeFileSize = 4 << 20
+
+// errUnwritable is returned when saving a document the user may no longer
+// write; the edits stay in the journal (see recoverJournal).
+var errUnwritable = errors.New("no permission to write the file, edits are kept in the journal until it can be saved")
+
+// diskStamp is the size and modification time of the document as last
+// read or written, so a change by another program is noticed without
+// reading the whole file on every key.
+type diskStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// stampFile returns the stamp of the file at path.
+func stampFile(path string) (diskStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return diskStamp{}, err
+	}
+	return diskStamp{info.Size(), info.ModTime()}, nil
+}
+
+// changedRange returns the byte range of new that differs from old: new
+// from start up to newEnd replaces old from start up to oldEnd.
+func changedRange(old, new string) (start, oldEnd, newEnd int) {
+	for start < len(old) && start < len(new) && old[start] == new[start] {
+		start++
+	}
+	oldEnd, newEnd = len(old), len(new)
+	for oldEnd > start && newEnd > start && old[oldEnd-1] == new[newEnd-1] {
+		oldEnd--
+		newEnd--
+	}
+	return start, oldEnd, newEnd
+}
+
+// writeDocument writes data to the document at path, which holds old. The
+// file is written in place, so it keeps its mode, owner and links (a
+// rename would replace them, e.g. leave a root-owned file behind under
+// sudo), and only from the first changed byte: a change that keeps the
+// size rewrites just the changed range, otherwise the rest of the file is
+// rewritten and the file truncated. A new file is created 0644. The write
+// is synced to disk; a permission error is errUnwritable.
+func writeDocument(path, old, data string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o644)
+	if errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS) {
+		return fmt.Errorf("%w (%v)", errUnwritable, err)
+	}
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if info.Size() != int64(len(old)) {
+		// Not the content we know: rewrite it all
+		old = ""
+	}
+
+	start, _, newEnd := changedRange(old, data)
+	if len(data) != len(old) {
+		newEnd = len(data)
+	}
+	if _, err := f.WriteAt([]byte(data[start:newEnd]), int64(start)); err != nil {
+		f.Close()
+		return err
+	}
+	if int64(len(data)) < info.Size() {
+		if err := f.Truncate(int64(len(data))); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// isLargeFile reports whether a document of size bytes uses the
+// large-file mode.
+func isLargeFile(size int) bool {
+	return config.LargeFileSize > 0 && int64(size) >= config.LargeFileSize
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestChangedRange(t *testing.T) {
+	tests := []struct {
+		old, new              string
+		start, oldEnd, newEnd int
+	}{
+		{"- [ ] a\n- [ ] b", "- [ ] a\n- [x] b", 11, 12, 12},
+		{"abc", "abXc", 2, 2, 3},
+		{"abc", "ac", 1, 2, 1},
+		{"same", "same", 4, 4, 4},
+		{"", "new", 0, 0, 3},
+	}
+	for _, tt := range tests {
+		start, oldEnd, newEnd := changedRange(tt.old, tt.new)
+		if start != tt.start || oldEnd != tt.oldEnd || newEnd != tt.newEnd {
+			t.Errorf("changedRange(%q, %q) = %d, %d, %d", tt.old, tt.new, start, oldEnd, newEnd)
+		}
+	}
+}
+
+func TestWriteDocumentIncremental(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.md")
+	old := "# Doc\n- [ ] one\n- [ ] two\n"
+	for _, data := range []string{
+		"# Doc\n- [ ] one\n- [x] two\n",          // same size
+		"# Doc\n- [ ] one\n- [x] two\n- [ ] 3\n", // grows
+		"# Doc\n",                                // shrinks
+	} {
+		if err := os.WriteFile(path, []byte(old), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeDocument(path, old, data); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != data {
+			t.Errorf("wrote %q, file holds %q", data, got)
+		}
+	}
+
+	// A file not holding old is rewritten whole
+	if err := os.WriteFile(path, []byte("something else entirely"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeDocument(path, old, "new"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "new" {
+		t.Errorf("Expected a full rewrite, file holds %q", got)
+	}
+}
+
+func TestChangedOnDiskStamp(t *testing.T) {
+	a := createTestApp()
+	a.FilePath = filepath.Join(t.TempDir(), "doc.md")
+	if err := os.WriteFile(a.FilePath, []byte(sampleMarkdown), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.LoadFile(); err != nil {
+		t.Fatal(err)
+	}
+	if a.ChangedOnDisk() {
+		t.Error("Expected the file just read to be unchanged")
+	}
+
+	// Touched without a change
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(a.FilePath, later, later)
+	if a.ChangedOnDisk() {
+		t.Error("Expected a touched file to be unchanged")
+	}
+
+	os.WriteFile(a.FilePath, []byte(sampleMarkdown+"\nmore"), 0o644)
+	if !a.ChangedOnDisk() {
+		t.Error("Expected the rewritten file to be changed")
+	}
+}
+
+func TestLargeFileUpdateSection(t *testing.T) {
+	normal, large := createTestApp(), createTestApp()
+	large.LargeFile = true
+	for _, a := range []*App{normal, large} {
+		// Same line count, then a growing section
+		a.Sections[2].SetContent(strings.Replace(a.Sections[2].Content(), "[ ]", "[x]", 1))
+		a.UpdateFileSection(2)
+		a.ParseSections()
+		a.Sections[3].SetContent(a.Sections[3].Content() + "\n\n> **Ghi chú [1]:** note")
+		a.UpdateFileSection(3)
+		a.ParseSections()
+	}
+	if strings.Join(large.FileLines, "\n") != strings.Join(normal.FileLines, "\n") {
+		t.Errorf("large-file edits differ:\n%s\nwant\n%s", strings.Join(large.FileLines, "\n"), strings.Join(normal.FileLines, "\n"))
+	}
+	if large.FileContent != sampleMarkdown {
+		t.Error("Expected the large-file mode to leave FileContent until saved")
+	}
+}
+func TestWriteDocumentKeepsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.md")
+	if err := os.WriteFile(path, []byte("old"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link.md")
+	if err := os.Link(path, link); err != nil {
+		t.Skip("no hard links:", err)
+	}
+
+	if err := writeDocument(path, "old", "new"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("Expected the mode to be kept, got %v", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(link); string(data) != "new" {
+		t.Errorf("Expected the hard link to see the new content, got %q", data)
+	}
+}
+
+// sectionBody joins the lines of section i's body from FileLines.
+func sectionBody(a *App, i int) string {
+	end := len(a.FileLines)
+	if i+1 < len(a.Sections) {
+		end = a.Sections[i+1].Line
+	}
+	start := min(a.Sections[i].Line+len(a.Sections[i].HeaderLines()), end)
+	return strings.Join(a.FileLines[start:end], "\n")
+}
+
+func TestParseSectionsIndexesHeaders(t *testing.T) {
+	a := createTestApp()
+	for i, sec := range a.Sections {
+		if sec.content != "" || sec.text == "" || unsafe.StringData(sec.text) != unsafe.StringData(a.FileContent) {
+			t.Errorf("Expected %q to be indexed into FileContent, not built", sec.Title)
+		}
+		if got := sec.Content(); got != sectionBody(a, i) {
+			t.Errorf("Section %q body = %q, want %q", sec.Title, got, sectionBody(a, i))
+		}
+	}
+
+	// An edit sets the section's own body; the sections after it keep
+	// their offsets into the text they were parsed from
+	a.LargeFile = true
+	a.Sections[2].SetContent(a.Sections[2].Content() + "\n- [ ] Task four")
+	a.UpdateFileSection(2)
+	for i, sec := range a.Sections {
+		if got := sec.Content(); got != sectionBody(a, i) {
+			t.Errorf("After the edit, section %q body = %q, want %q", sec.Title, got, sectionBody(a, i))
+		}
+	}
+	if a.Sections[3].content != "" || a.Sections[2].text != "" {
+		t.Error("Expected only the edited section to hold its body")
+	}
+
+	// Lines edited since FileContent was read are joined instead
+	a.Sections[2].SetContent(strings.Replace(a.Sections[2].Content(), "[ ]", "[x]", 1))
+	a.UpdateFileSection(2)
+	a.ParseSections()
+	if !strings.Contains(a.Sections[2].Content(), "- [x] Task one") {
+		t.Errorf("Expected the edited lines, got %q", a.Sections[2].Content())
+	}
+}
+
+func TestLargeFileSaveReslices(t *testing.T) {
+	a := createTestApp()
+	a.FilePath = filepath.Join(t.TempDir(), "doc.md")
+	if err := os.WriteFile(a.FilePath, []byte(sampleMarkdown), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.LoadFile(); err != nil {
+		t.Fatal(err)
+	}
+	a.ParseSections()
+	if a.diskContent != a.FileContent || unsafe.StringData(a.diskContent) != unsafe.StringData(a.FileContent) {
+		t.Error("Expected the disk content and the content to be one string")
+	}
+
+	a.LargeFile = true
+	a.Sections[2].SetContent(strings.Replace(a.Sections[2].Content(), "[ ]", "[x]", 1))
+	a.UpdateFileSection(2)
+	if err := a.SaveFile(); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(sampleMarkdown, "- [ ] Task one", "- [x] Task one", 1)
+	if a.FileContent != want || strings.Join(a.FileLines, "\n") != want {
+		t.Fatalf("Unexpected saved document %q", a.FileContent)
+	}
+	for i, sec := range a.Sections {
+		if unsafe.StringData(sec.text) != unsafe.StringData(a.FileContent) || sec.Content() != sectionBody(a, i) {
+			t.Errorf("Expected %q to point into the saved content", sec.Title)
+		}
+	}
+}
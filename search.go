@@ -0,0 +1,58 @@
+package main
+
+import "strings"
+
+// snippetContext is the number of characters kept before a match when a
+// snippet line is too long to show whole.
+const snippetContext = 20
+
+// MatchLine returns the first line of the section content containing
+// query (case-insensitive), or -1 when only the title matches.
+func (a *App) MatchLine(idx int, query string) int {
+	if idx < 0 || idx >= len(a.Sections) || query == "" {
+		return -1
+	}
+	q := strings.ToLower(query)
+	for i, line := range strings.Split(a.Sections[idx].Content(), "\n") {
+		if strings.Contains(strings.ToLower(line), q) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SearchSnippet returns the two lines around the first match of query in
+// the section, the matching line first, or nil when only the title
+// matches. The next line is used as context, or the previous one when
+// the match is the last line.
+func (a *App) SearchSnippet(idx int, query string) []string {
+	line := a.MatchLine(idx, query)
+	if line < 0 {
+		return nil
+	}
+	lines := strings.Split(a.Sections[idx].Content(), "\n")
+	snippet := []string{strings.TrimSpace(lines[line])}
+	for i := line + 1; i < len(lines); i++ {
+		if next := strings.TrimSpace(lines[i]); next != "" {
+			return append(snippet, next)
+		}
+	}
+	for i := line - 1; i >= 0; i-- {
+		if prev := strings.TrimSpace(lines[i]); prev != "" {
+			return append([]string{prev}, snippet...)
+		}
+	}
+	return snippet
+}
+
+// snippetLine fits a snippet line into width columns with query
+// highlighted, cutting the start of long lines so the match stays visible.
+func snippetLine(line, query string, width int) string {
+	lower := strings.ToLower(line)
+	if i := strings.Index(lower, strings.ToLower(query)); i >= 0 && len(lower) == len(line) && visibleWidth(line) > width {
+		if at := len([]rune(line[:i])); at > snippetContext {
+			line = "…" + string([]rune(line)[at-snippetContext:])
+		}
+	}
+	return highlightMatches(truncateEllipsis(line, width), query)
+}
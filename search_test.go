@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchLine(t *testing.T) {
+	a := createTestApp()
+	if got := a.MatchLine(2, "TASK THREE"); got != 3 {
+		t.Errorf("MatchLine = %d, want 3", got)
+	}
+	if got := a.MatchLine(2, "Chapter"); got != -1 {
+		t.Errorf("title-only match = %d, want -1", got)
+	}
+	if got := a.MatchLine(99, "x"); got != -1 {
+		t.Errorf("out of range = %d, want -1", got)
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	a := createTestApp()
+	got := a.SearchSnippet(2, "task two")
+	if len(got) != 2 || got[0] != "- [x] Task two completed" || got[1] != "- [ ] Task three" {
+		t.Errorf("snippet = %q", got)
+	}
+	// The last line takes the previous line as context
+	got = a.SearchSnippet(5, "also done")
+	if len(got) != 2 || got[0] != "- [x] Done" || got[1] != "- [x] Also done" {
+		t.Errorf("snippet at end = %q", got)
+	}
+	if got := a.SearchSnippet(2, "Basics"); got != nil {
+		t.Errorf("title-only snippet = %q", got)
+	}
+}
+
+func TestSnippetLine(t *testing.T) {
+	line := strings.Repeat("word ", 20) + "etcd defrag at the end"
+	got := stripANSI(snippetLine(line, "defrag", 40))
+	if !strings.HasPrefix(got, "…") || !strings.Contains(got, "defrag") {
+		t.Errorf("long line should be cut before the match, got %q", got)
+	}
+	if got := snippetLine("short etcd line", "etcd", 40); !strings.Contains(got, BgYellow) {
+		t.Errorf("match not highlighted: %q", got)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxSearchHistory is how many past queries the search history keeps.
+const maxSearchHistory = 50
+
+// SavedSearch is a named query that can be re-run from the search
+// prompt. Pinned searches are shown with their live result count in the
+// TOC footer.
+type SavedSearch struct {
+	Name   string `json:"name"`
+	Query  string `json:"query"`
+	Pinned bool   `json:"pinned,omitempty"`
+}
+
+// SearchStore holds the search history, most recent first, and the saved
+// searches. It is shared by all documents.
+type SearchStore struct {
+	History []string      `json:"history,omitempty"`
+	Saved   []SavedSearch `json:"saved,omitempty"`
+}
+
+// searchesPath returns the file the search history is stored in.
+func searchesPath() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "searches.json"), nil
+}
+
+// LoadSearches reads the search history and saved searches. A missing
+// file is an empty store.
+func LoadSearches(path string) (SearchStore, error) {
+	var store SearchStore
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return store, err
+	}
+	if err := json.Unmarshal(data, &store); err != nil {
+		return SearchStore{}, fmt.Errorf("%s is corrupt: %w", path, err)
+	}
+	return store, nil
+}
+
+// SaveSearches writes the search history and saved searches.
+func SaveSearches(path string, store SearchStore) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// AddHistory moves query to the front of the history, dropping an older
+// copy and the oldest queries beyond maxSearchHistory.
+func (s *SearchStore) AddHistory(query string) {
+	s.History = pushHistory(s.History, query, maxSearchHistory)
+}
+
+// Find returns the saved search with the given name (case-insensitive).
+func (s *SearchStore) Find(name string) *SavedSearch {
+	for i := range s.Saved {
+		if strings.EqualFold(s.Saved[i].Name, name) {
+			return &s.Saved[i]
+		}
+	}
+	return nil
+}
+
+// Save stores query under name, replacing the query of an existing saved
+// search with that name and keeping its pin.
+func (s *SearchStore) Save(name, query string) {
+	if saved := s.Find(name); saved != nil {
+		saved.Query = query
+		return
+	}
+	s.Saved = append(s.Saved, SavedSearch{Name: name, Query: query})
+}
+
+// Remove deletes the saved search with the given name.
+func (s *SearchStore) Remove(name string) bool {
+	for i := range s.Saved {
+		if strings.EqualFold(s.Saved[i].Name, name) {
+			s.Saved = append(s.Saved[:i], s.Saved[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// pinnedSearchesLabel lists the pinned searches with their live result
+// counts in the document, e.g. "📌 kubectl (5) · interview (2)", or ""
+// when none is pinned.
+func (a *App) pinnedSearchesLabel(store SearchStore) string {
+	var parts []string
+	for _, s := range store.Saved {
+		if s.Pinned {
+			parts = append(parts, fmt.Sprintf("%s (%d)", s.Name, len(a.SearchSections(s.Query))))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "📌 " + strings.Join(parts, " · ")
+}
+
+// loadSearchStore reads the search store of the user, warning when it
+// cannot be read.
+func loadSearchStore() (SearchStore, string) {
+	path, err := searchesPath()
+	if err != nil {
+		return SearchStore{}, ""
+	}
+	store, err := LoadSearches(path)
+	if err != nil {
+		notify(SeverityWarning, "Không đọc được lịch sử tìm kiếm: %v", err)
+	}
+	return store, path
+}
+
+// saveSearchStore writes the search store, warning when it cannot.
+func saveSearchStore(path string, store SearchStore) {
+	if path == "" {
+		return
+	}
+	if err := SaveSearches(path, store); err != nil {
+		notify(SeverityWarning, "Không lưu được lịch sử tìm kiếm: %v", err)
+	}
+}
+
+// readSearchQuery reads a search query with readPrompt: ↑/↓ go through
+// the history and Tab completes the saved searches, by name or query.
+func readSearchQuery(label string, store SearchStore) (string, bool) {
+	return readPrompt(Prompt{
+		Label:   label,
+		History: store.History,
+		Complete: func(input string) []string {
+			var queries []string
+			for _, s := range store.Saved {
+				if strings.HasPrefix(strings.ToLower(s.Name), strings.ToLower(input)) || strings.HasPrefix(s.Query, input) {
+					queries = append(queries, s.Query)
+				}
+			}
+			return queries
+		},
+	})
+}
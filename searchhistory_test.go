@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestAddHistory(t *testing.T) {
+	var store SearchStore
+	for i := 0; i < maxSearchHistory+5; i++ {
+		store.AddHistory("q" + strconv.Itoa(i))
+	}
+	if len(store.History) != maxSearchHistory || store.History[0] != "q54" {
+		t.Fatalf("Expected the newest %d queries, got %d starting with %s", maxSearchHistory, len(store.History), store.History[0])
+	}
+
+	store.AddHistory("q30")
+	if store.History[0] != "q30" || len(store.History) != maxSearchHistory {
+		t.Errorf("Expected a repeated query to move to the front, got %v", store.History[:3])
+	}
+	for _, q := range store.History[1:] {
+		if q == "q30" {
+			t.Error("Expected the older copy to be dropped")
+		}
+	}
+}
+
+func TestSavedSearches(t *testing.T) {
+	var store SearchStore
+	store.Save("kubectl", "kubectl get")
+	store.Save("interview", "interview")
+	store.Find("KUBECTL").Pinned = true
+	store.Save("kubectl", "kubectl")
+
+	if got := store.Find("kubectl"); got == nil || got.Query != "kubectl" || !got.Pinned {
+		t.Errorf("Expected saving an existing name to replace its query and keep the pin, got %+v", got)
+	}
+	if len(store.Saved) != 2 {
+		t.Errorf("Expected 2 saved searches, got %d", len(store.Saved))
+	}
+	if !store.Remove("interview") || store.Remove("interview") || store.Find("interview") != nil {
+		t.Error("Expected interview to be removed once")
+	}
+}
+
+func TestSearchesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sre-learn", "searches.json")
+	if store, err := LoadSearches(path); err != nil || store.History != nil || store.Saved != nil {
+		t.Fatalf("Expected a missing store to be empty, got %+v, %v", store, err)
+	}
+
+	want := SearchStore{History: []string{"etcd", "task"}, Saved: []SavedSearch{{Name: "k8s", Query: "kubectl", Pinned: true}}}
+	if err := SaveSearches(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadSearches(path)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v, %v", want, got, err)
+	}
+
+	os.WriteFile(path, []byte("{"), 0o644)
+	if _, err := LoadSearches(path); err == nil {
+		t.Error("Expected an error for a corrupt store")
+	}
+}
+
+func TestPinnedSearchesLabel(t *testing.T) {
+	a := createTestApp()
+	store := SearchStore{Saved: []SavedSearch{
+		{Name: "tasks", Query: "task", Pinned: true},
+		{Name: "chapters", Query: "chapter"},
+		{Name: "done", Query: "done", Pinned: true},
+	}}
+	if got, want := a.pinnedSearchesLabel(store), "📌 tasks (2) · done (1)"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := a.pinnedSearchesLabel(SearchStore{}); got != "" {
+		t.Errorf("Expected no label without pinned searches, got %q", got)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// trigram is three bytes of lowercased text, the key of the search index.
+type trigram [3]byte
+
+// indexedSection is a section as the search index last saw it. The title
+// and content share the section's memory; they are lowercased a line at
+// a time when indexed and when a match is verified, so the index does not
+// keep a lowercased copy of the document.
+type indexedSection struct {
+	title, content string
+	grams          []trigram
+	// targets are the jump targets of the section, listed on first use
+	targets []JumpTarget
+	listed  bool
+}
+
+// SearchIndex is an inverted index from the trigrams of the section
+// titles and contents (notes included) to the sections holding them, so
+// a search only looks at the sections that can match instead of
+// lowercasing and scanning the whole document. Sync brings it up to date
+// with the sections, indexing again only the ones that changed.
+type SearchIndex struct {
+	docs     []indexedSection
+	postings map[trigram]map[int]struct{}
+}
+
+// NewSearchIndex indexes the sections.
+func NewSearchIndex(sections []Section) *SearchIndex {
+	ix := &SearchIndex{postings: make(map[trigram]map[int]struct{})}
+	ix.Sync(sections)
+	return ix
+}
+
+// trigrams returns the distinct trigrams of the lines of s, lowercased.
+func trigrams(s ...string) []trigram {
+	seen := make(map[trigram]bool)
+	var grams []trigram
+	for _, text := range s {
+		eachLine(text, func(line string) bool {
+			line = strings.ToLower(line)
+			for i := 0; i+3 <= len(line); i++ {
+				g := trigram{line[i], line[i+1], line[i+2]}
+				if !seen[g] {
+					seen[g] = true
+					grams = append(grams, g)
+				}
+			}
+			return true
+		})
+	}
+	return grams
+}
+
+// eachLine calls fn with each line of s until it returns false, without
+// splitting s into a slice.
+func eachLine(s string, fn func(line string) bool) {
+	for {
+		line, rest, more := strings.Cut(s, "\n")
+		if !fn(line) || !more {
+			return
+		}
+		s = rest
+	}
+}
+
+// contains reports whether a line of the title or content of doc holds q,
+// which is lowercase.
+func (doc *indexedSection) contains(q string) bool {
+	found := false
+	for _, text := range []string{doc.title, doc.content} {
+		eachLine(text, func(line string) bool {
+			found = strings.Contains(strings.ToLower(line), q)
+			return !found
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// Sync updates the index to the sections. A section is indexed again
+// only when its title or content changed; comparing an unchanged string
+// is cheap, as it shares its bytes with the indexed one.
+func (ix *SearchIndex) Sync(sections []Section) {
+	for len(ix.docs) > len(sections) {
+		ix.remove(len(ix.docs) - 1)
+		ix.docs = ix.docs[:len(ix.docs)-1]
+	}
+	for i, sec := range sections {
+		if i < len(ix.docs) && ix.docs[i].title == sec.Title && ix.docs[i].content == sec.Content() {
+			continue
+		}
+		if i < len(ix.docs) {
+			ix.remove(i)
+		} else {
+			ix.docs = append(ix.docs, indexedSection{})
+		}
+		ix.docs[i] = indexedSection{title: sec.Title, content: sec.Content(), grams: trigrams(sec.Title, sec.Content())}
+		for _, g := range ix.docs[i].grams {
+			if ix.postings[g] == nil {
+				ix.postings[g] = make(map[int]struct{})
+			}
+			ix.postings[g][i] = struct{}{}
+		}
+	}
+}
+
+// remove drops the postings of section i.
+func (ix *SearchIndex) remove(i int) {
+	for _, g := range ix.docs[i].grams {
+		delete(ix.postings[g], i)
+		if len(ix.postings[g]) == 0 {
+			delete(ix.postings, g)
+		}
+	}
+}
+
+// Search returns the sections whose title or a line of content contains
+// query (case-insensitive), in document order. The candidates are the sections
+// of the query's rarest trigram; a query shorter than a trigram checks
+// every section.
+func (ix *SearchIndex) Search(query string) []int {
+	q := strings.ToLower(query)
+	matches := []int{}
+	if len(q) < 3 {
+		for i := range ix.docs {
+			if ix.docs[i].contains(q) {
+				matches = append(matches, i)
+			}
+		}
+		return matches
+	}
+
+	var candidates map[int]struct{}
+	for _, g := range trigrams(q) {
+		posting := ix.postings[g]
+		if len(posting) == 0 {
+			return matches
+		}
+		if candidates == nil || len(posting) < len(candidates) {
+			candidates = posting
+		}
+	}
+	for i := range candidates {
+		if ix.docs[i].contains(q) {
+			matches = append(matches, i)
+		}
+	}
+	sort.Ints(matches)
+	return matches
+}
+
+// JumpTargets returns the jump targets of every section in document
+// order, listing again only the sections that changed.
+func (ix *SearchIndex) JumpTargets() []JumpTarget {
+	var targets []JumpTarget
+	for i := range ix.docs {
+		doc := &ix.docs[i]
+		if !doc.listed {
+			doc.targets, doc.listed = sectionJumpTargets(doc.title, doc.content), true
+		}
+		for _, t := range doc.targets {
+			t.Section = i
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// IndexInBackground builds the search index of the document on another
+// goroutine, so the first search after opening a large document does not
+// wait for it.
+func (a *App) IndexInBackground() {
+	sections := append([]Section(nil), a.Sections...)
+	built := make(chan *SearchIndex, 1)
+	a.searchIndex, a.searchBuilt = nil, built
+	go func() { built <- NewSearchIndex(sections) }()
+}
+
+// SearchIndex returns the search index, up to date with the sections. It
+// waits for a background build still running, and builds the index when
+// none was started.
+func (a *App) SearchIndex() *SearchIndex {
+	if a.searchBuilt != nil {
+		a.searchIndex, a.searchBuilt = <-a.searchBuilt, nil
+	}
+	if a.searchIndex == nil {
+		a.searchIndex = NewSearchIndex(a.Sections)
+	} else {
+		a.searchIndex.Sync(a.Sections)
+	}
+	return a.searchIndex
+}
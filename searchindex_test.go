@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// linearSearch is the scan the search index replaces.
+func linearSearch(sections []Section, query string) []int {
+	q := strings.ToLower(query)
+	matches := []int{}
+	for i, sec := range sections {
+		if strings.Contains(strings.ToLower(sec.Title), q) || strings.Contains(strings.ToLower(sec.Content()), q) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+func TestSearchIndexMatchesScan(t *testing.T) {
+	a := createTestApp()
+	a.Sections[3].SetContent(a.Sections[3].Content() + "\n> **Ghi chú [2024-05-01 10:00]:** Giải thích etcd defrag\n")
+	queries := []string{"chapter", "CHAPTER", "ch", "a", "", "task one", "giải thích", "ETCD", "Basics", "nonexistent12345", "e 1"}
+	for _, q := range queries {
+		if got, want := a.SearchSections(q), linearSearch(a.Sections, q); !reflect.DeepEqual(got, want) {
+			t.Errorf("SearchSections(%q) = %v, want %v", q, got, want)
+		}
+	}
+}
+
+func TestSearchIndexSync(t *testing.T) {
+	a := createTestApp()
+	if got := a.SearchSections("kubernetes"); len(got) != 0 {
+		t.Fatalf("Expected no match yet, got %v", got)
+	}
+
+	a.Sections[5].SetContent(a.Sections[5].Content() + "\nKubernetes deployment")
+	a.UpdateFileSection(5)
+	if got := a.SearchSections("kubernetes"); !reflect.DeepEqual(got, []int{5}) {
+		t.Errorf("Expected the edited section to match, got %v", got)
+	}
+
+	a.Sections[2].SetContent(a.Sections[2].Content() + "\n## Kubernetes\n")
+	a.UpdateFileSection(2)
+	if got, want := a.SearchSections("kubernetes"), linearSearch(a.Sections, "kubernetes"); !reflect.DeepEqual(got, want) {
+		t.Errorf("After a new section: %v, want %v", got, want)
+	}
+
+	a.Sections = a.Sections[:3]
+	if got := a.SearchSections("kubernetes"); len(got) != 0 {
+		t.Errorf("After removing sections: %v", got)
+	}
+}
+
+func TestIndexInBackground(t *testing.T) {
+	a := createTestApp()
+	a.IndexInBackground()
+	a.Sections[1].Title = "Giai đoạn một"
+	if got := a.SearchSections("đoạn một"); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("Expected the index to catch up with an edit made while it was built, got %v", got)
+	}
+}
+
+func TestNarrowJumpTargets(t *testing.T) {
+	a := createTestApp()
+	targets := a.JumpTargets()
+	candidates := targets
+	for _, q := range []string{"t", "ta", "tas", "task o"} {
+		candidates = narrowJumpTargets(candidates, q)
+		if got, want := MatchJumpTargets(candidates, q), MatchJumpTargets(targets, q); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Narrowed matches for %q = %v, want %v", q, got, want)
+		}
+	}
+	if len(candidates) >= len(targets) {
+		t.Errorf("Expected typing to narrow the targets, %d of %d left", len(candidates), len(targets))
+	}
+}
+
+func BenchmarkSearchSectionsLarge(b *testing.B) {
+	var doc strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&doc, "## Section %d\n\n- [ ] task %d about pods and nodes\n\nSome text on topic %d.\n\n", i, i, i)
+	}
+	a := NewApp()
+	a.FileLines = strings.Split(doc.String(), "\n")
+	a.ParseSections()
+	a.SearchSections("warm")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.SearchSections("topic 4242")
+	}
+}
+
+func TestSearchIndexSharesContent(t *testing.T) {
+	a := createTestApp()
+	ix := a.SearchIndex()
+	for i, doc := range ix.docs {
+		if len(doc.content) > 0 && unsafe.StringData(doc.content) != unsafe.StringData(a.Sections[i].Content()) {
+			t.Errorf("Expected section %d to be indexed without copying its content", i)
+		}
+	}
+	// Verified line by line, case-insensitively
+	if got := ix.Search("TASK TWO COMPLETED"); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("Expected the line to match in section 2, got %v", got)
+	}
+}
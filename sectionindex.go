@@ -0,0 +1,71 @@
+package main
+
+import "fmt"
+
+// debugIndex checks the section index against a full parse after every
+// incremental update (--debug-index).
+var debugIndex bool
+
+// fenceLeftOpen reports whether lines end inside a code block, which
+// changes how the lines after them parse.
+func (a *App) fenceLeftOpen(lines []string) bool {
+	if len(lines) == 0 {
+		return false
+	}
+	last := a.scanLines(lines)[len(lines)-1].Kind
+	return last == LineFenceOpen || last == LineCode
+}
+
+// reindexSections updates the section index after section idx was
+// rewritten as lines, delta lines longer than before: the following
+// sections move by delta instead of the whole document being parsed
+// again. The document is still parsed when the rewrite changes its
+// structure: a header appears in the content, or the section now leaves
+// a code block open (or no longer does).
+func (a *App) reindexSections(idx int, lines []string, delta int, fenceWasOpen bool) {
+	header, depth := len(a.Sections[idx].HeaderLines()), sectionDepth()
+	for i, ml := range a.scanLines(lines) {
+		if i >= header && ml.Kind == LineHeader && ml.Level <= depth {
+			a.ParseSections()
+			return
+		}
+	}
+	if a.fenceLeftOpen(lines) != fenceWasOpen {
+		a.ParseSections()
+		return
+	}
+	for i := idx + 1; i < len(a.Sections); i++ {
+		a.Sections[i].Line += delta
+	}
+	if debugIndex {
+		if diff := a.CheckSectionIndex(); diff != "" {
+			notify(SeverityError, "Chỉ mục section lệch (%s), đã phân tích lại", diff)
+			a.ParseSections()
+		}
+	}
+}
+
+// sameSection reports whether a and b are the same header with the same
+// body, wherever the bodies are cut from.
+func sameSection(a, b Section) bool {
+	return a.Title == b.Title && a.Level == b.Level && a.Line == b.Line && a.ID == b.ID &&
+		a.Setext == b.Setext && a.AsciiDoc == b.AsciiDoc && a.Implicit == b.Implicit &&
+		a.Content() == b.Content()
+}
+
+// CheckSectionIndex compares the section index with a full parse of the
+// document and describes the first difference, or returns "" when they
+// agree.
+func (a *App) CheckSectionIndex() string {
+	full := &App{FilePath: a.FilePath, FileLines: a.FileLines}
+	full.ParseSections()
+	if len(full.Sections) != len(a.Sections) {
+		return fmt.Sprintf("%d sections, parsed %d", len(a.Sections), len(full.Sections))
+	}
+	for i, sec := range a.Sections {
+		if !sameSection(sec, full.Sections[i]) {
+			return fmt.Sprintf("section %d %q at line %d, parsed %q at line %d", i+1, sec.Title, sec.Line+1, full.Sections[i].Title, full.Sections[i].Line+1)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReindexSectionsMatchesFullParse(t *testing.T) {
+	a := createTestApp()
+	edits := []struct {
+		idx     int
+		content string
+	}{
+		{2, "\nmore\nlines\n\n- [ ] new task\n"},
+		{1, ""},
+		{3, "```bash\necho hi\n```\n"},
+		{2, "short"},
+	}
+	for _, e := range edits {
+		a.Sections[e.idx].SetContent(e.content)
+		a.UpdateFileSection(e.idx)
+		if diff := a.CheckSectionIndex(); diff != "" {
+			t.Fatalf("After rewriting section %d: %s", e.idx, diff)
+		}
+	}
+}
+
+func TestReindexSectionsStructureChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"header in the content", "text\n\n## Chapter 1b\n\nbody", 7},
+		{"unclosed fence", "```bash\n# not a header", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := createTestApp()
+			a.Sections[2].SetContent(tt.content)
+			a.UpdateFileSection(2)
+			if diff := a.CheckSectionIndex(); diff != "" {
+				t.Fatal(diff)
+			}
+			if len(a.Sections) != tt.want {
+				titles := make([]string, len(a.Sections))
+				for i, s := range a.Sections {
+					titles[i] = s.Title
+				}
+				t.Errorf("Expected %d sections, got %s", tt.want, strings.Join(titles, ", "))
+			}
+		})
+	}
+}
+
+func TestCheckSectionIndexMismatch(t *testing.T) {
+	a := createTestApp()
+	a.Sections[3].Line++
+	if a.CheckSectionIndex() == "" {
+		t.Error("Expected a shifted section to be reported")
+	}
+}
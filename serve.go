@@ -0,0 +1,420 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server serves the document over HTTP. All requests share one App and
+// are serialized by mu; before every request the document is reloaded if
+// the TUI (or anything else) changed it.
+type Server struct {
+	mu  sync.Mutex
+	app *App
+	mux *http.ServeMux
+	// pprof serves the runtime profiles with --debug, nil otherwise
+	pprof *http.ServeMux
+	// host is the host of the listen address, "" for all interfaces
+	host string
+}
+
+// NewServer creates a server for a loaded document.
+func NewServer(a *App) *Server {
+	s := &Server{app: a, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/", s.handleIndex)
+	s.mux.HandleFunc("/s/", s.handleSection)
+	s.mux.HandleFunc("/api/progress", s.handleAPIProgress)
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
+	s.mux.HandleFunc("/api/sections", s.handleAPISections)
+	s.mux.HandleFunc("/api/sections/", s.handleAPISections)
+	if debugLog != nil {
+		s.pprof = pprofMux()
+	}
+	return s
+}
+
+// ServeHTTP syncs the document with the disk and dispatches the request.
+// Profiles are served without the lock, so a CPU profile taken while
+// requests run sees them.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.allowedHost(r.Host) {
+		logger.Warn("refused request for another host", "host", r.Host, "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "unknown host", http.StatusMisdirectedRequest)
+		return
+	}
+	if s.pprof != nil && strings.HasPrefix(r.URL.Path, "/debug/pprof/") {
+		s.pprof.ServeHTTP(w, r)
+		return
+	}
+	defer debugLog.Time("request", "%s %s", r.Method, r.URL.Path)()
+	if !safeMethod(r.Method) && !sameOrigin(r) {
+		logger.Warn("refused cross-site request", "method", r.Method, "path", r.URL.Path, "origin", r.Header.Get("Origin"), "referer", r.Referer())
+		http.Error(w, "cross-site request refused", http.StatusForbidden)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.app.ChangedOnDisk() {
+		if err := s.app.Reload(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		logger.Info("reloaded document changed on disk", "path", s.app.FilePath)
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// safeMethod reports whether a request method only reads.
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// allowedHost reports whether a Host header names this server: localhost,
+// a loopback address, the host it listens on or, listening on all
+// interfaces, an address of one of them. A page of another site whose
+// name was rebound to 127.0.0.1 would otherwise be same-origin with the
+// server and could read and change the document.
+func (s *Server) allowedHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") || (s.host != "" && strings.EqualFold(host, s.host)) {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	if listen := net.ParseIP(s.host); s.host != "" && (listen == nil || !listen.IsUnspecified()) {
+		return false
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameOrigin reports whether a request comes from the server's own pages.
+// Any web page the user visits can post a form to localhost, so a write
+// whose Origin (or, without one, Referer) names another host is refused.
+// Requests with neither, as sent by curl and scripts, are not browser
+// requests and are let through.
+func sameOrigin(r *http.Request) bool {
+	source := r.Header.Get("Origin")
+	if source == "" {
+		source = r.Referer()
+	}
+	if source == "" {
+		return true
+	}
+	u, err := url.Parse(source)
+	return err == nil && u.Host != "" && u.Host == r.Host
+}
+
+// sectionVersion identifies the content of a section. Edits carry the
+// version they were made on and are refused if the section changed in
+// the meantime (optimistic locking).
+func sectionVersion(sec *Section) string {
+	sum := sha256.Sum256([]byte(sec.Title + "\x00" + sec.Content()))
+	return hex.EncodeToString(sum[:6])
+}
+
+// errConflict is returned for an edit made on an outdated section.
+var errConflict = errors.New("section was changed elsewhere; reload and try again")
+
+// sectionFromPath parses "/s/<n>[/<action>]" into a 0-based section index
+// and the action.
+func (s *Server) sectionFromPath(path string) (int, string, bool) {
+	rest := strings.TrimPrefix(path, "/s/")
+	num, action, _ := strings.Cut(rest, "/")
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 1 || n > len(s.app.Sections) {
+		return 0, "", false
+	}
+	return n - 1, action, true
+}
+
+// toggleLine toggles the checkbox on a content line of a section.
+func (a *App) toggleLine(idx, line int) error {
+	a.CurrentIdx = idx
+	if !a.ToggleCheckbox(line) {
+		return fmt.Errorf("line %d has no checkbox", line)
+	}
+	a.UpdateFileSection(idx)
+	return a.SaveFile()
+}
+
+// addNote appends a note to a section and saves the file.
+func (a *App) addNote(idx int, note string) error {
+	if strings.TrimSpace(note) == "" {
+		return fmt.Errorf("empty note")
+	}
+	a.CurrentIdx = idx
+	a.AddNote(strings.TrimSpace(note))
+	a.UpdateFileSection(idx)
+	return a.SaveFile()
+}
+
+// editSection applies an edit to a section after checking the version
+// the client saw, and records it in the state.
+func (s *Server) editSection(idx int, version string, edit func() error) error {
+	if version != sectionVersion(&s.app.Sections[idx]) {
+		return errConflict
+	}
+	pageSize, _ := s.app.LoadState()
+
+	// Edits select the section; the reader's position is not theirs to move
+	current := s.app.CurrentIdx
+	err := edit()
+	s.app.CurrentIdx = current
+	if errors.Is(err, errModified) {
+		s.app.Reload()
+		return errConflict
+	}
+	if err != nil {
+		return err
+	}
+	return s.app.SaveState(pageSize)
+}
+
+// editStatus maps an edit error to an HTTP status.
+func editStatus(err error) int {
+	switch {
+	case errors.Is(err, errConflict):
+		return http.StatusConflict
+	case errors.Is(err, errReadOnly):
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
+
+// handleSection shows a section (GET) or edits it (POST .../toggle with
+// line and version, POST .../note with text and version).
+func (s *Server) handleSection(w http.ResponseWriter, r *http.Request) {
+	idx, action, ok := s.sectionFromPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method == http.MethodGet && action == "" {
+		s.renderSection(w, idx, "")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var edit func() error
+	switch action {
+	case "toggle":
+		line, err := strconv.Atoi(r.FormValue("line"))
+		if err != nil {
+			http.Error(w, "invalid line", http.StatusBadRequest)
+			return
+		}
+		edit = func() error { return s.app.toggleLine(idx, line) }
+	case "note":
+		text := r.FormValue("text")
+		edit = func() error { return s.app.addNote(idx, text) }
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := s.editSection(idx, r.FormValue("version"), edit); err != nil {
+		w.WriteHeader(editStatus(err))
+		s.renderSection(w, idx, err.Error())
+		return
+	}
+	target := fmt.Sprintf("/s/%d", idx+1)
+	if action == "toggle" {
+		target += "#l" + r.FormValue("line")
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+// webLine is one content line prepared for the section page.
+type webLine struct {
+	Index   int
+	Kind    string // "heading", "checkbox", "note", "code", "text" or "blank"
+	Text    string
+	Checked bool
+	// State names the checkbox state (see checkboxStateNames)
+	State string
+}
+
+// webLines classifies the content lines of a section for display.
+func webLines(content string) []webLine {
+	var lines []webLine
+	inCode := false
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		l := webLine{Index: i, Kind: "text", Text: line}
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			inCode = !inCode
+			l.Kind = "code"
+		case inCode:
+			l.Kind = "code"
+		case trimmed == "":
+			l.Kind = "blank"
+		case isCheckbox(line):
+			l.Kind, l.Checked, l.Text = "checkbox", isChecked(line), checkboxText(line)
+			l.State = checkboxStateNames[checkboxState(line)]
+		case strings.HasPrefix(trimmed, ">"):
+			l.Kind, l.Text = "note", strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+		case strings.HasPrefix(trimmed, "#"):
+			l.Kind, l.Text = "heading", strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// webTOCEntry is one row of the index page.
+type webTOCEntry struct {
+	Number  int
+	Indent  int
+	Title   string
+	Checked int
+	Total   int
+	Current bool
+	Skipped bool
+}
+
+// handleIndex lists the sections with their progress.
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	s.app.LoadState()
+
+	entries := make([]webTOCEntry, len(s.app.Sections))
+	for i, sec := range s.app.Sections {
+		checked, total := s.app.GetProgress(i)
+		entries[i] = webTOCEntry{
+			Number:  i + 1,
+			Indent:  sec.Level - 1,
+			Title:   sec.Title,
+			Checked: checked,
+			Total:   total,
+			Current: i == s.app.CurrentIdx,
+			Skipped: s.app.IsSkipped(i),
+		}
+	}
+	checked, total := s.app.GetTotalProgress()
+	webTemplates.ExecuteTemplate(w, "index", map[string]any{
+		"File":    s.app.FilePath,
+		"Entries": entries,
+		"Checked": checked,
+		"Total":   total,
+	})
+}
+
+// renderSection writes the page of a section, with an error banner if
+// an edit failed.
+func (s *Server) renderSection(w io.Writer, idx int, errMsg string) {
+	sec := &s.app.Sections[idx]
+	data := map[string]any{
+		"Number":  idx + 1,
+		"Count":   len(s.app.Sections),
+		"Title":   sec.Title,
+		"Version": sectionVersion(sec),
+		"Lines":   webLines(sec.Content()),
+		"Error":   errMsg,
+	}
+	checked, total := s.app.GetProgress(idx)
+	data["Checked"], data["Total"] = checked, total
+	webTemplates.ExecuteTemplate(w, "section", data)
+}
+
+// webTemplates are the pages of serve mode. They are small and mobile
+// friendly so boxes can be ticked from a phone.
+var webTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+}).Parse(`
+{{define "head"}}<!doctype html>
+<html lang="vi"><head><meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.}} · sre-learn</title>
+<style>
+body{font-family:system-ui,sans-serif;max-width:46rem;margin:0 auto;padding:1rem;line-height:1.5;color:#222}
+a{color:#0969da;text-decoration:none} .dim{color:#888} .err{background:#fde;padding:.5rem;border-radius:4px}
+ul.toc{list-style:none;padding:0} ul.toc li{padding:.25rem 0}
+form.cb{margin:.2rem 0} form.cb button{font-size:1rem;background:none;border:0;text-align:left;padding:.35rem 0;cursor:pointer;width:100%}
+.done{color:#2a7} blockquote{border-left:3px solid #c8c;margin:.5rem 0;padding-left:.75rem;color:#555}
+pre{background:#f4f4f4;margin:0;padding:0 .5rem;overflow-x:auto} textarea{width:100%;min-height:4rem}
+nav{display:flex;justify-content:space-between;margin:1rem 0}
+</style></head><body>{{end}}
+
+{{define "index"}}{{template "head" "Mục lục"}}
+<h1>📖 SRE Learning Path</h1>
+<p class="dim">{{.File}} · {{.Checked}}/{{.Total}} mục</p>
+<ul class="toc">{{range .Entries}}
+<li style="padding-left:{{.Indent}}rem"><a href="/s/{{.Number}}">{{.Title}}</a>
+{{if .Skipped}}<span class="dim">⊘</span>{{else if .Total}}<span class="{{if eq .Checked .Total}}done{{else}}dim{{end}}">{{.Checked}}/{{.Total}}</span>{{end}}
+{{if .Current}}<span class="dim">(hiện tại)</span>{{end}}</li>{{end}}
+</ul></body></html>{{end}}
+
+{{define "section"}}{{template "head" .Title}}
+<nav><a href="/">☰ Mục lục</a><span class="dim">{{.Number}}/{{.Count}}{{if .Total}} · {{.Checked}}/{{.Total}}{{end}}</span></nav>
+<h2>{{.Title}}</h2>
+{{if .Error}}<p class="err">⚠ {{.Error}}</p>{{end}}
+{{$v := .Version}}{{$n := .Number}}
+{{range .Lines}}{{if eq .Kind "checkbox"}}<form class="cb" method="post" action="/s/{{$n}}/toggle" id="l{{.Index}}">
+<input type="hidden" name="line" value="{{.Index}}"><input type="hidden" name="version" value="{{$v}}">
+<button type="submit" class="{{if .Checked}}done{{end}}">{{if .Checked}}☑{{else if eq .State "in_progress"}}◐{{else if eq .State "wont_do"}}⊟{{else}}☐{{end}} {{.Text}}</button></form>
+{{else if eq .Kind "heading"}}<h3>{{.Text}}</h3>
+{{else if eq .Kind "note"}}<blockquote>{{.Text}}</blockquote>
+{{else if eq .Kind "code"}}<pre>{{.Text}}</pre>
+{{else if eq .Kind "text"}}<p>{{.Text}}</p>{{end}}{{end}}
+<form method="post" action="/s/{{.Number}}/note">
+<input type="hidden" name="version" value="{{.Version}}">
+<textarea name="text" placeholder="Ghi chú…"></textarea><button type="submit">📝 Thêm ghi chú</button></form>
+<nav>{{if gt .Number 1}}<a href="/s/{{add .Number -1}}">← Trước</a>{{else}}<span></span>{{end}}
+{{if lt .Number .Count}}<a href="/s/{{add .Number 1}}">Tiếp →</a>{{end}}</nav>
+</body></html>{{end}}
+`))
+
+// runServe implements "serve [--addr host:port]".
+func runServe(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on (:8080 for all interfaces)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	server := NewServer(app)
+	server.host, _, _ = net.SplitHostPort(*addr)
+	fmt.Fprintf(out, "Serving %s on http://%s (no authentication: use on a trusted network only)\n", app.FilePath, listener.Addr())
+	return http.Serve(listener, server)
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestServer serves a copy of the sample document from a temp dir.
+func newTestServer(t *testing.T) (*Server, *App) {
+	t.Helper()
+	dir := t.TempDir()
+	a := NewApp()
+	a.FilePath = filepath.Join(dir, "path.md")
+	a.StateFile = filepath.Join(dir, "state")
+	os.WriteFile(a.FilePath, []byte(sampleMarkdown), 0o644)
+	if err := a.LoadFile(); err != nil {
+		t.Fatal(err)
+	}
+	a.ParseSections()
+	return NewServer(a), a
+}
+
+// newLocalRequest returns a request for target as a browser on the same
+// machine sends it.
+func newLocalRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Host = "localhost:8080"
+	return req
+}
+
+// serve performs a request against s and returns the recorder.
+func serve(s *Server, method, target string, form url.Values) *httptest.ResponseRecorder {
+	var req *http.Request
+	if form != nil {
+		req = newLocalRequest(method, target, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req = newLocalRequest(method, target, nil)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+// ============================================================================
+// Serve Mode Tests
+// ============================================================================
+
+func TestServeIndexAndSection(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	index := serve(s, http.MethodGet, "/", nil)
+	if index.Code != http.StatusOK || !strings.Contains(index.Body.String(), `<a href="/s/3">Chapter 1: Basics</a>`) {
+		t.Errorf("Unexpected index page (%d):\n%s", index.Code, index.Body.String())
+	}
+
+	page := serve(s, http.MethodGet, "/s/3", nil)
+	for _, want := range []string{"☐ Task one", "☑ Task two completed", `action="/s/3/toggle"`} {
+		if !strings.Contains(page.Body.String(), want) {
+			t.Errorf("Expected section page to contain %q", want)
+		}
+	}
+
+	if rec := serve(s, http.MethodGet, "/s/99", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown section, got %d", rec.Code)
+	}
+}
+
+func TestServeToggleSavesFile(t *testing.T) {
+	s, a := newTestServer(t)
+	a.CurrentIdx = 1
+	lines := webLines(a.Sections[2].Content())
+	var taskOne int
+	for _, l := range lines {
+		if l.Text == "Task one" {
+			taskOne = l.Index
+		}
+	}
+
+	form := url.Values{"line": {strconv.Itoa(taskOne)}, "version": {sectionVersion(&a.Sections[2])}}
+	rec := serve(s, http.MethodPost, "/s/3/toggle", form)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("Expected redirect, got %d:\n%s", rec.Code, rec.Body.String())
+	}
+
+	data, _ := os.ReadFile(a.FilePath)
+	if !strings.Contains(string(data), "- [x] Task one") {
+		t.Error("Expected the toggle to be saved to the file")
+	}
+	if a.CurrentIdx != 1 {
+		t.Errorf("Expected the reader's position kept, got %d", a.CurrentIdx)
+	}
+	if len(a.History) != 1 {
+		t.Errorf("Expected the toggle in the history, got %d events", len(a.History))
+	}
+}
+
+func TestServeRejectsStaleEdits(t *testing.T) {
+	s, a := newTestServer(t)
+	stale := sectionVersion(&a.Sections[2])
+
+	// The TUI (another program) checks a box in the meantime
+	edited := strings.Replace(sampleMarkdown, "- [ ] Task three", "- [x] Task three", 1)
+	os.WriteFile(a.FilePath, []byte(edited), 0o644)
+
+	rec := serve(s, http.MethodPost, "/s/3/note", url.Values{"text": {"từ điện thoại"}, "version": {stale}})
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("Expected 409 for a stale version, got %d", rec.Code)
+	}
+	data, _ := os.ReadFile(a.FilePath)
+	if string(data) != edited {
+		t.Error("Expected the file untouched by a rejected edit")
+	}
+
+	// Retrying on the current version succeeds
+	rec = serve(s, http.MethodPost, "/s/3/note", url.Values{"text": {"từ điện thoại"}, "version": {sectionVersion(&a.Sections[2])}})
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("Expected the retry to succeed, got %d", rec.Code)
+	}
+	data, _ = os.ReadFile(a.FilePath)
+	if !strings.Contains(string(data), "từ điện thoại") || !strings.Contains(string(data), "- [x] Task three") {
+		t.Error("Expected both edits in the file")
+	}
+}
+
+func TestSaveFileDetectsExternalChange(t *testing.T) {
+	_, a := newTestServer(t)
+	os.WriteFile(a.FilePath, []byte("# Changed\n"), 0o644)
+
+	if err := a.SaveFile(); err != errModified {
+		t.Fatalf("Expected errModified, got %v", err)
+	}
+	if err := a.Reload(); err != nil || a.Sections[0].Title != "Changed" {
+		t.Fatalf("Expected reload to pick up the change, got %v", err)
+	}
+	if err := a.SaveFile(); err != nil {
+		t.Errorf("Expected save after reload to succeed, got %v", err)
+	}
+}
+
+func TestServeAndViewerShareState(t *testing.T) {
+	s, a := newTestServer(t)
+	viewer := NewApp()
+	viewer.FilePath, viewer.StateFile = a.FilePath, a.StateFile
+	if err := viewer.LoadFile(); err != nil {
+		t.Fatal(err)
+	}
+	viewer.ParseSections()
+	viewer.CurrentIdx = 4
+	viewer.SaveState(20)
+
+	// A box is ticked from the phone while the viewer stays open
+	form := url.Values{"line": {"1"}, "version": {sectionVersion(&a.Sections[2])}}
+	if rec := serve(s, http.MethodPost, "/s/3/toggle", form); rec.Code != http.StatusSeeOther {
+		t.Fatalf("Expected redirect, got %d:\n%s", rec.Code, rec.Body.String())
+	}
+
+	// The viewer's periodic save must not drop the served toggle
+	viewer.AddStudyTime("Chapter 2: Advanced", time.Minute)
+	if err := viewer.SaveState(20); err != nil {
+		t.Fatal(err)
+	}
+	if len(viewer.History) != 1 {
+		t.Errorf("Expected the viewer to pick up the served toggle, got %d events", len(viewer.History))
+	}
+
+	// Nor does the next served edit drop the viewer's study time
+	form = url.Values{"line": {"3"}, "version": {sectionVersion(&a.Sections[2])}}
+	if rec := serve(s, http.MethodPost, "/s/3/toggle", form); rec.Code != http.StatusSeeOther {
+		t.Fatalf("Expected redirect, got %d:\n%s", rec.Code, rec.Body.String())
+	}
+	viewer.SaveState(20)
+
+	disk := NewApp()
+	disk.StateFile = a.StateFile
+	if _, err := disk.LoadState(); err != nil {
+		t.Fatal(err)
+	}
+	if len(disk.History) != 2 {
+		t.Errorf("Expected both served toggles in the history, got %+v", disk.History)
+	}
+	if disk.StudyTime["Chapter 2: Advanced"] != 60 {
+		t.Errorf("Expected the viewer's study time kept, got %v", disk.StudyTime)
+	}
+	if disk.CurrentIdx != 4 {
+		t.Errorf("Expected the viewer's position kept, got %d", disk.CurrentIdx)
+	}
+}
+
+func TestMergeState(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	ev := func(d int, item string) CheckboxEvent {
+		return CheckboxEvent{Time: day(d), Section: "S", Item: item, Checked: true}
+	}
+	base := State{Version: stateVersion, CurrentSection: 1, History: []CheckboxEvent{ev(1, "a")},
+		StudyTime: map[string]float64{"S": 10}, Bookmarks: []string{"S"}}
+	ours := base
+	ours.CurrentSection = 2
+	ours.History = []CheckboxEvent{ev(1, "a"), ev(3, "c")}
+	ours.StudyTime = map[string]float64{"S": 20}
+	disk := base
+	disk.CurrentSection = 5
+	disk.History = []CheckboxEvent{ev(1, "a"), ev(2, "b")}
+	disk.StudyTime = map[string]float64{"S": 10, "T": 5}
+	disk.Bookmarks = nil
+
+	merged, err := mergeState(base, ours, disk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.CurrentSection != 2 {
+		t.Errorf("Expected our position, got %d", merged.CurrentSection)
+	}
+	if len(merged.History) != 3 || merged.History[1].Item != "b" {
+		t.Errorf("Expected the union of the histories in order, got %+v", merged.History)
+	}
+	if merged.StudyTime["S"] != 20 || merged.StudyTime["T"] != 5 {
+		t.Errorf("Expected study times merged by section, got %v", merged.StudyTime)
+	}
+	if len(merged.Bookmarks) != 0 {
+		t.Errorf("Expected the bookmark removed elsewhere to stay removed, got %v", merged.Bookmarks)
+	}
+}
+
+func TestServeRefusesCrossSitePosts(t *testing.T) {
+	s, a := newTestServer(t)
+	form := url.Values{"line": {"1"}, "version": {sectionVersion(&a.Sections[2])}}
+
+	for name, header := range map[string][2]string{
+		"foreign origin":  {"Origin", "https://evil.example"},
+		"null origin":     {"Origin", "null"},
+		"foreign referer": {"Referer", "https://evil.example/page"},
+	} {
+		req := newLocalRequest(http.MethodPost, "/s/3/toggle", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set(header[0], header[1])
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403, got %d", name, rec.Code)
+		}
+	}
+	if data, _ := os.ReadFile(a.FilePath); string(data) != sampleMarkdown {
+		t.Error("Expected a refused request to leave the file unchanged")
+	}
+
+	req := newLocalRequest(http.MethodPost, "/api/sections/3/toggle", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "https://evil.example")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected the API to refuse a foreign origin, got %d", rec.Code)
+	}
+
+	// The server's own pages post with their origin
+	req = newLocalRequest(http.MethodPost, "/s/3/toggle", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "http://"+req.Host)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code == http.StatusForbidden {
+		t.Error("Expected a same-origin post to be accepted")
+	}
+}
+
+func TestServeRefusesForeignHosts(t *testing.T) {
+	s, a := newTestServer(t)
+	form := url.Values{"line": {"1"}, "version": {sectionVersion(&a.Sections[2])}}
+
+	// A rebound name reaches the server with its own Host and Origin
+	for _, host := range []string{"evil.example", "evil.example:8080", "localhost.evil.example"} {
+		req := newLocalRequest(http.MethodGet, "/api/progress", nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != http.StatusMisdirectedRequest {
+			t.Errorf("GET for host %s: expected 421, got %d", host, rec.Code)
+		}
+
+		req = newLocalRequest(http.MethodPost, "/s/3/toggle", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Origin", "http://"+host)
+		req.Host = host
+		rec = httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != http.StatusMisdirectedRequest {
+			t.Errorf("POST for host %s: expected 421, got %d", host, rec.Code)
+		}
+	}
+	if data, _ := os.ReadFile(a.FilePath); string(data) != sampleMarkdown {
+		t.Error("Expected a refused request to leave the file unchanged")
+	}
+
+	for _, host := range []string{"localhost", "LOCALHOST:8080", "127.0.0.1:8080", "[::1]:8080"} {
+		req := newLocalRequest(http.MethodGet, "/api/progress", nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET for host %s: expected 200, got %d", host, rec.Code)
+		}
+	}
+
+	s.host = "sre.lan"
+	if !s.allowedHost("sre.lan:8080") || s.allowedHost("evil.example") {
+		t.Error("Expected only the listen host to be allowed besides localhost")
+	}
+}
+
+func TestServePostWithoutOrigin(t *testing.T) {
+	s, a := newTestServer(t)
+	form := url.Values{"line": {"1"}, "version": {sectionVersion(&a.Sections[2])}}
+
+	// Scripts send neither Origin nor Referer: accepted for this host only
+	req := newLocalRequest(http.MethodPost, "/s/3/toggle", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Host = "evil.example"
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMisdirectedRequest {
+		t.Errorf("Expected a post without origin for another host to be refused, got %d", rec.Code)
+	}
+
+	rec = serve(s, http.MethodPost, "/s/3/toggle", form)
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("Expected a post without origin to localhost to be accepted, got %d", rec.Code)
+	}
+	if data, _ := os.ReadFile(a.FilePath); string(data) == sampleMarkdown {
+		t.Error("Expected the accepted post to toggle the checkbox")
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Session is a named snapshot of the viewer: which document is open, where
+// the reader is and how the panes are laid out. Sessions let one user keep
+// several documents (or several places in one document) side by side.
+type Session struct {
+	Name string `json:"name"`
+	// FilePath and StateFile are absolute, so a session can be resumed
+	// from any directory
+	FilePath  string `json:"file_path"`
+	StateFile string `json:"state_file"`
+	Profile   string `json:"profile,omitempty"`
+	// Section is the current section's title; SectionIdx is the fallback
+	// when the title no longer exists
+	Section       string    `json:"section"`
+	SectionIdx    int       `json:"section_idx"`
+	Scroll        int       `json:"scroll"`
+	PageSize      int       `json:"page_size"`
+	BookMode      bool      `json:"book_mode"`
+	SplitView     bool      `json:"split_view"`
+	LineNumbers   bool      `json:"line_numbers,omitempty"`
+	SidebarOffset int       `json:"sidebar_offset"`
+	Updated       time.Time `json:"updated"`
+}
+
+// sessionPath returns the file a named session is stored in.
+func sessionPath(name string) (string, error) {
+	if !profileNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid session name %q", name)
+	}
+	base, err := dataHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "sessions", name+".json"), nil
+}
+
+// LoadSession reads a named session. The error wraps os.ErrNotExist if
+// the session has never been saved.
+func LoadSession(name string) (Session, error) {
+	path, err := sessionPath(name)
+	if err != nil {
+		return Session{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Session{}, fmt.Errorf("cannot read session %s: %w", name, err)
+	}
+
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Session{}, fmt.Errorf("session %s is corrupt: %w", name, err)
+	}
+	s.Name = name
+	return s, nil
+}
+
+// Save writes the session, stamping it with the current time.
+func (s *Session) Save() error {
+	path, err := sessionPath(s.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cannot create session directory: %w", err)
+	}
+
+	s.Updated = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ListSessions returns all saved sessions, most recently used first.
+func ListSessions() ([]Session, error) {
+	base, err := dataHome()
+	if err != nil {
+		return nil, err
+	}
+	paths, _ := filepath.Glob(filepath.Join(base, "sessions", "*.json"))
+
+	var sessions []Session
+	for _, path := range paths {
+		if s, err := LoadSession(strings.TrimSuffix(filepath.Base(path), ".json")); err == nil {
+			sessions = append(sessions, s)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Updated.After(sessions[j].Updated)
+	})
+	return sessions, nil
+}
+
+// absPath returns path made absolute, or path itself if that fails.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// CaptureSession snapshots the current document, position and layout.
+func CaptureSession(name string, a *App, r *Renderer) Session {
+	s := Session{
+		Name:          name,
+		FilePath:      absPath(a.FilePath),
+		StateFile:     absPath(a.StateFile),
+		Profile:       a.Profile,
+		SectionIdx:    a.CurrentIdx,
+		Scroll:        r.ScrollOffset,
+		PageSize:      r.PageSize,
+		BookMode:      r.BookMode,
+		SplitView:     r.SplitView,
+		LineNumbers:   r.LineNumbers,
+		SidebarOffset: r.SidebarOffset,
+	}
+	if sec := a.GetCurrentSection(); sec != nil {
+		s.Section = sec.Title
+	}
+	return s
+}
+
+// ApplySession restores the position and layout of s on the loaded
+// document. The section is found by title first, so edits that shift
+// sections around do not lose the place.
+func ApplySession(s Session, a *App, r *Renderer) {
+	a.CurrentIdx = max(0, min(s.SectionIdx, len(a.Sections)-1))
+	for i, sec := range a.Sections {
+		if sec.Title == s.Section {
+			a.CurrentIdx = i
+			break
+		}
+	}
+	if s.PageSize > 0 {
+		r.PageSize = s.PageSize
+	}
+	r.BookMode = s.BookMode
+	r.SplitView = s.SplitView
+	r.LineNumbers = s.LineNumbers
+	r.SidebarOffset = s.SidebarOffset
+	r.ScrollOffset = max(0, min(s.Scroll, r.maxScrollOffset()))
+	a.Session = s.Name
+}
+
+// saveSession saves the active session, if any.
+func saveSession() error {
+	if app.Session == "" {
+		return nil
+	}
+	s := CaptureSession(app.Session, app, renderer)
+	return s.Save()
+}
+
+// switchSession saves the current session and state, then opens the
+// document of s and restores its position and layout.
+func switchSession(s Session) error {
+	if err := saveSession(); err != nil {
+		return err
+	}
+	app.SaveState(renderer.PageSize)
+
+	app.FilePath, app.StateFile, app.Profile = s.FilePath, s.StateFile, s.Profile
+	app.Skipped, app.Macros, app.Bookmarks, app.SectionScroll = nil, nil, nil, nil
+	app.History, app.GitHubIssues, app.Exported, app.StudyTime = nil, nil, nil, nil
+	if err := openDocument(""); err != nil {
+		return err
+	}
+	if err := lockDocument(bufio.NewReader(keyboard)); err != nil {
+		return err
+	}
+	app.LoadState()
+	ApplySession(s, app, renderer)
+	rememberDocument()
+	return nil
+}
+
+// handleSessions lists the saved sessions; a number switches to one and
+// "n" saves the current view as a new session.
+func handleSessions() {
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	inputReader := bufio.NewReader(keyboard)
+
+	ClearScreen()
+	fmt.Printf("%s🗂  PHIÊN LÀM VIỆC%s\n", Bold+Cyan, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+
+	sessions, err := ListSessions()
+	if err != nil {
+		notify(SeverityError, "%v", err)
+		return
+	}
+	printSessions(os.Stdout, sessions, app.Session)
+
+	fmt.Printf("\n%sSố để chuyển phiên, n để lưu phiên mới, Enter để quay lại:%s ", Bold, Reset)
+	input, _ := inputReader.ReadString('\n')
+	input = strings.TrimSpace(input)
+
+	switch {
+	case input == "":
+		return
+	case input == "n":
+		fmt.Printf("%sTên phiên:%s ", Bold, Reset)
+		name, _ := inputReader.ReadString('\n')
+		s := CaptureSession(strings.TrimSpace(name), app, renderer)
+		if err := s.Save(); err != nil {
+			notify(SeverityError, "%v", err)
+			return
+		}
+		app.Session = s.Name
+		notify(SeveritySuccess, "Đã lưu phiên %s", s.Name)
+	default:
+		n, err := strconv.Atoi(input)
+		if err != nil || n < 1 || n > len(sessions) {
+			notify(SeverityWarning, "Lựa chọn không hợp lệ: %s", input)
+			return
+		}
+		if err := switchSession(sessions[n-1]); err != nil {
+			notify(SeverityError, "Không mở được phiên %s: %v", sessions[n-1].Name, err)
+			return
+		}
+		notify(SeveritySuccess, "Đã chuyển sang phiên %s", app.Session)
+	}
+}
+
+// printSessions writes a numbered session list, marking the active one.
+func printSessions(out io.Writer, sessions []Session, active string) {
+	if len(sessions) == 0 {
+		fmt.Fprintf(out, "\n%sChưa có phiên nào (dùng --session <tên> hoặc n).%s\n", Dim, Reset)
+		return
+	}
+	fmt.Fprintln(out)
+	for i, s := range sessions {
+		marker := ""
+		if s.Name == active {
+			marker = Green + " ◀" + Reset
+		}
+		fmt.Fprintf(out, "  %s%2d.%s %s%s%s%s\n", Cyan, i+1, Reset, Bold, s.Name, Reset, marker)
+		fmt.Fprintf(out, "      %s%s %s %s (%s)%s\n", Dim, filepath.Base(s.FilePath), glyphs.Crumb, s.Section, s.Updated.Format("2006-01-02 15:04"), Reset)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================================
+// Session Tests
+// ============================================================================
+
+func sessionTestApp() *App {
+	app := NewApp()
+	app.FileLines = strings.Split("# A\n\n## B\n\ntext\n\n## C\n\nmore", "\n")
+	app.ParseSections()
+	return app
+}
+
+func TestSessionSaveLoad(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	a := sessionTestApp()
+	a.CurrentIdx = 2
+	r := NewRenderer(a)
+	r.SplitView = true
+	r.PageSize = 30
+
+	s := CaptureSession("interview-prep", a, r)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadSession("interview-prep")
+	if err != nil {
+		t.Fatalf("LoadSession failed: %v", err)
+	}
+	if loaded.Section != "C" || !loaded.SplitView || loaded.PageSize != 30 || !filepath.IsAbs(loaded.FilePath) {
+		t.Errorf("Unexpected session: %+v", loaded)
+	}
+
+	if _, err := LoadSession("missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected ErrNotExist for a missing session, got %v", err)
+	}
+	if _, err := LoadSession("../evil"); err == nil {
+		t.Error("Expected invalid session name to be rejected")
+	}
+}
+
+func TestApplySessionFindsSectionByTitle(t *testing.T) {
+	a := sessionTestApp()
+	r := NewRenderer(a)
+
+	// The section moved since the session was saved
+	ApplySession(Session{Name: "work", Section: "C", SectionIdx: 0, BookMode: true, PageSize: 12}, a, r)
+	if a.CurrentIdx != 2 || a.Session != "work" {
+		t.Errorf("Expected section C and session work, got %d %q", a.CurrentIdx, a.Session)
+	}
+	if !r.BookMode || r.PageSize != 12 {
+		t.Errorf("Expected layout restored, got book=%v page=%d", r.BookMode, r.PageSize)
+	}
+
+	// Unknown title falls back to the (clamped) index
+	ApplySession(Session{Section: "gone", SectionIdx: 99}, a, r)
+	if a.CurrentIdx != 2 {
+		t.Errorf("Expected clamped index 2, got %d", a.CurrentIdx)
+	}
+}
+
+func TestListSessionsMostRecentFirst(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	for _, name := range []string{"old", "new"} {
+		s := Session{Name: name}
+		if err := s.Save(); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sessions, err := ListSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].Name != "new" || sessions[1].Name != "old" {
+		t.Errorf("Expected [new old], got %+v", sessions)
+	}
+}
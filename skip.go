@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IsSkipped reports whether a section was marked as skipped, either
+// directly or through one of its ancestors.
+func (a *App) IsSkipped(idx int) bool {
+	if idx < 0 || idx >= len(a.Sections) || len(a.Skipped) == 0 {
+		return false
+	}
+	if a.Skipped[a.Sections[idx].Title] {
+		return true
+	}
+	for _, anc := range a.Ancestors(idx) {
+		if a.Skipped[a.Sections[anc].Title] {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleSkip marks or unmarks a section as skipped.
+// Returns the new skipped state of the section itself.
+func (a *App) ToggleSkip(idx int) bool {
+	if idx < 0 || idx >= len(a.Sections) {
+		return false
+	}
+	if a.Skipped == nil {
+		a.Skipped = make(map[string]bool)
+	}
+
+	title := a.Sections[idx].Title
+	if a.Skipped[title] {
+		delete(a.Skipped, title)
+		return false
+	}
+	a.Skipped[title] = true
+	return true
+}
+
+// SkippedSections returns the indexes of sections marked as skipped
+// directly, in document order.
+func (a *App) SkippedSections() []int {
+	var result []int
+	for i, sec := range a.Sections {
+		if a.Skipped[sec.Title] {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// handleSkip toggles the skipped mark of the current section.
+func handleSkip() {
+	sec := app.GetCurrentSection()
+	if sec == nil {
+		return
+	}
+
+	if app.ToggleSkip(app.CurrentIdx) {
+		notify(SeverityInfo, "Đã bỏ qua: %s (không tính tiến độ)", sec.Title)
+	} else {
+		notify(SeveritySuccess, "Đã học lại: %s", sec.Title)
+	}
+	app.SaveState(renderer.PageSize)
+}
+
+// handleSkippedList shows the skipped sections and unskips the chosen one.
+func handleSkippedList() {
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	ClearScreen()
+
+	skipped := app.SkippedSections()
+	fmt.Printf("%s%s SECTION ĐÃ BỎ QUA (%d)%s\n", Bold+Cyan, glyphs.Skipped, len(skipped), Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+
+	if len(skipped) == 0 {
+		fmt.Printf("\n%sChưa bỏ qua section nào. Nhấn zz để bỏ qua section hiện tại.%s\n", Dim, Reset)
+		fmt.Printf("\n%s[Enter để quay lại]%s", Dim, Reset)
+		bufio.NewReader(keyboard).ReadString('\n')
+		return
+	}
+
+	for i, idx := range skipped {
+		fmt.Printf("%s%2d.%s %s\n", Cyan, i+1, Reset, app.Sections[idx].Title)
+	}
+
+	fmt.Printf("\n%sNhập số để học lại section hoặc Enter để hủy:%s ", Bold, Reset)
+	input, _ := bufio.NewReader(keyboard).ReadString('\n')
+	if num, err := strconv.Atoi(strings.TrimSpace(input)); err == nil && num >= 1 && num <= len(skipped) {
+		app.ToggleSkip(skipped[num-1])
+		app.SaveState(renderer.PageSize)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// ============================================================================
+// Skipped Section Tests
+// ============================================================================
+
+func TestToggleSkip(t *testing.T) {
+	app := createTestApp()
+
+	if !app.ToggleSkip(2) || !app.IsSkipped(2) {
+		t.Error("Expected section 2 to be skipped")
+	}
+
+	if app.ToggleSkip(2) || app.IsSkipped(2) {
+		t.Error("Expected section 2 to be unskipped")
+	}
+
+	if app.ToggleSkip(-1) || app.ToggleSkip(len(app.Sections)) {
+		t.Error("Expected out-of-range toggle to fail")
+	}
+}
+
+func TestIsSkippedInheritsFromAncestors(t *testing.T) {
+	app := createTestApp()
+	app.ToggleSkip(1) // Giai đoạn 1: Learning
+
+	for _, idx := range []int{1, 2, 3} {
+		if !app.IsSkipped(idx) {
+			t.Errorf("Expected section %d to be skipped", idx)
+		}
+	}
+
+	if app.IsSkipped(0) || app.IsSkipped(4) {
+		t.Error("Expected parent and sibling phase not to be skipped")
+	}
+
+	if got := app.SkippedSections(); len(got) != 1 || got[0] != 1 {
+		t.Errorf("Expected only section 1 listed as skipped, got %v", got)
+	}
+}
+
+func TestTotalProgressExcludesSkipped(t *testing.T) {
+	app := createTestApp()
+	app.ToggleSkip(1)
+
+	checked, total := app.GetTotalProgress()
+	if checked != 2 || total != 2 {
+		t.Errorf("Expected 2/2 without skipped phase, got %d/%d", checked, total)
+	}
+}
+
+func TestSkippedPersistedInState(t *testing.T) {
+	app := createTestApp()
+	app.StateFile = filepath.Join(t.TempDir(), "state")
+	app.ToggleSkip(2)
+	app.ToggleSkip(5)
+
+	if err := app.SaveState(30); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	app2 := createTestApp()
+	app2.StateFile = app.StateFile
+	if _, err := app2.LoadState(); err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+
+	if !app2.IsSkipped(2) || !app2.IsSkipped(5) || app2.IsSkipped(3) {
+		t.Errorf("Expected skipped sections restored, got %v", app2.Skipped)
+	}
+}
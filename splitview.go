@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// minSplitWidth is the narrowest terminal that still fits both panes.
+// Below it the split view falls back to the single-pane layout.
+const minSplitWidth = 80
+
+// ToggleSplitView switches between the single-pane and split layouts.
+func (r *Renderer) ToggleSplitView() {
+	r.SplitView = !r.SplitView
+	r.SidebarOffset = -1 // re-center on the current section
+}
+
+// ScrollSidebar scrolls the TOC sidebar independently of the content.
+func (r *Renderer) ScrollSidebar(delta int) {
+	r.SidebarOffset = max(0, min(r.SidebarOffset+delta, len(r.App.Sections)-1))
+}
+
+// splitActive reports whether the split layout should be drawn.
+// The split view reflows into a single pane on narrow terminals.
+func (r *Renderer) splitActive() bool {
+	return r.SplitView && r.TermWidth >= minSplitWidth
+}
+
+// sidebarWidth returns the width of the TOC pane: a third of the
+// terminal, clamped to a readable range.
+func (r *Renderer) sidebarWidth() int {
+	return max(24, min(r.TermWidth/3, 40))
+}
+
+// sidebarLines renders rows of the TOC sidebar, keeping the current
+// section visible unless the sidebar was scrolled manually.
+func (r *Renderer) sidebarLines(width, rows int) []string {
+	sections := r.App.Sections
+
+	if r.SidebarOffset < 0 || r.sidebarFollowIdx != r.App.CurrentIdx {
+		r.SidebarOffset = max(0, r.App.CurrentIdx-rows/2)
+		r.sidebarFollowIdx = r.App.CurrentIdx
+	}
+
+	lines := []string{}
+	for i := r.SidebarOffset; i < len(sections) && len(lines) < rows; i++ {
+		sec := sections[i]
+		indent := strings.Repeat(" ", sec.Level-1)
+		if i == r.App.CurrentIdx {
+			lines = append(lines, Green+glyphs.Selector+Reset+indent+Bold+sec.Title+Reset)
+		} else {
+			lines = append(lines, " "+indent+Dim+sec.Title+Reset)
+		}
+	}
+	return lines
+}
+
+// printSplitContent renders the TOC sidebar and the content side by side.
+func (r *Renderer) printSplitContent(content string) {
+	sideWidth := r.sidebarWidth()
+	mainWidth := r.TermWidth - sideWidth - 3
+
+	rendered, startIdx, endIdx := r.visibleContent(content, mainWidth)
+	sidebar := r.sidebarLines(sideWidth, r.PageSize)
+
+	rows := max(endIdx-startIdx, len(sidebar))
+	for i := 0; i < rows; i++ {
+		left := ""
+		if i < len(sidebar) {
+			left = sidebar[i]
+		}
+		right := ""
+		if startIdx+i < endIdx {
+			right = truncateVisible(rendered[startIdx+i], mainWidth)
+		}
+		fmt.Printf("%s %s%s%s %s\n", padVisible(left, sideWidth), Dim, glyphs.Vertical, Reset, right)
+	}
+
+	r.printPosition(len(rendered), startIdx, endIdx)
+}
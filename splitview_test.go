@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Split View Tests
+// ============================================================================
+
+func TestSplitActiveNarrowTerminal(t *testing.T) {
+	app := createTestApp()
+	renderer := NewRenderer(app)
+	renderer.ToggleSplitView()
+
+	renderer.TermWidth = 120
+	if !renderer.splitActive() {
+		t.Error("Expected split view on wide terminal")
+	}
+
+	renderer.TermWidth = 60
+	if renderer.splitActive() {
+		t.Error("Expected split view to fall back on narrow terminal")
+	}
+}
+
+func TestSidebarFollowsCurrentSection(t *testing.T) {
+	app := createTestApp()
+	renderer := NewRenderer(app)
+	app.CurrentIdx = len(app.Sections) - 1
+
+	lines := renderer.sidebarLines(30, 2)
+
+	found := false
+	for _, line := range lines {
+		if strings.Contains(line, "▶") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected current section to be visible in sidebar")
+	}
+}
+
+func TestScrollSidebarBounds(t *testing.T) {
+	app := createTestApp()
+	renderer := NewRenderer(app)
+
+	renderer.SidebarOffset = 0
+	renderer.ScrollSidebar(-5)
+	if renderer.SidebarOffset != 0 {
+		t.Errorf("Expected sidebar offset 0, got %d", renderer.SidebarOffset)
+	}
+
+	renderer.ScrollSidebar(1000)
+	if renderer.SidebarOffset != len(app.Sections)-1 {
+		t.Errorf("Expected sidebar offset clamped to %d, got %d", len(app.Sections)-1, renderer.SidebarOffset)
+	}
+}
@@ -0,0 +1,550 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// legacyStateFile is where state was kept before it moved to the state
+// directory: the working directory the viewer was started from.
+const legacyStateFile = ".sre-learn-state"
+
+// stateVersion is the version of the state file schema written by
+// SaveState. Version 1 is the legacy key=value format.
+const stateVersion = 2
+
+// State is the on-disk form of the reader's progress in a document.
+type State struct {
+	Version  int    `json:"version"`
+	FilePath string `json:"file_path,omitempty"`
+	// CurrentSection is the index of the current section; CurrentTitle
+	// finds it again when sections were added or removed before it
+	CurrentSection int    `json:"current_section"`
+	CurrentTitle   string `json:"current_title,omitempty"`
+	PageSize       int    `json:"page_size,omitempty"`
+	// Scroll maps section titles to their last scroll offset
+	Scroll    map[string]int    `json:"scroll,omitempty"`
+	Bookmarks []string          `json:"bookmarks,omitempty"`
+	Skipped   []string          `json:"skipped,omitempty"`
+	Macros    map[string]string `json:"macros,omitempty"`
+	// History is the append-only checkbox log, oldest first
+	History []CheckboxEvent `json:"history,omitempty"`
+	// GitHubIssues maps checklist item keys to GitHub issue numbers
+	GitHubIssues map[string]int `json:"github_issues,omitempty"`
+	// Exported maps tracker-qualified item keys to ticket IDs
+	Exported map[string]string `json:"exported,omitempty"`
+	// StudyTime maps section titles to seconds spent reading them
+	StudyTime map[string]float64 `json:"seconds_spent,omitempty"`
+	// Repetition is the spaced-repetition queue by section title
+	Repetition map[string]Repetition `json:"repetition,omitempty"`
+	// Cycles are the archived results of earlier study cycles
+	Cycles []Cycle `json:"cycles,omitempty"`
+	// Recurring maps checked recurring items to when they reopen
+	Recurring map[string]time.Time `json:"recurring,omitempty"`
+	// Highlights are the highlighted line ranges with their comments
+	Highlights []Highlight `json:"highlights,omitempty"`
+	// KnownSummaries are the sections whose summary card is known
+	KnownSummaries []string `json:"known_summaries,omitempty"`
+}
+
+// Validate rejects values no version of the viewer writes, so a damaged
+// file is reported instead of silently restoring zeroes.
+func (s State) Validate() error {
+	switch {
+	case s.Version < 1:
+		return errors.New("missing version")
+	case s.Version > stateVersion:
+		return fmt.Errorf("version %d is newer than supported version %d", s.Version, stateVersion)
+	case s.CurrentSection < 0:
+		return fmt.Errorf("invalid current_section %d", s.CurrentSection)
+	case s.PageSize < 0:
+		return fmt.Errorf("invalid page_size %d", s.PageSize)
+	}
+	for title, offset := range s.Scroll {
+		if offset < 0 {
+			return fmt.Errorf("invalid scroll %d for %q", offset, title)
+		}
+	}
+	for register := range s.Macros {
+		if !validRegister(register) {
+			return fmt.Errorf("invalid macro register %q", register)
+		}
+	}
+	return nil
+}
+
+// parseLegacyState parses the version 1 key=value state file.
+func parseLegacyState(data []byte) (State, error) {
+	s := State{Version: 1}
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return State{}, fmt.Errorf("line %d: expected key=value", i+1)
+		}
+
+		var err error
+		switch key {
+		case "current_section":
+			s.CurrentSection, err = strconv.Atoi(value)
+		case "page_size":
+			s.PageSize, err = strconv.Atoi(value)
+		case "file_path":
+			s.FilePath = value
+		case "skipped":
+			s.Skipped = append(s.Skipped, value)
+		default:
+			register, isMacro := strings.CutPrefix(key, "macro.")
+			if !isMacro {
+				return State{}, fmt.Errorf("line %d: unknown key %q", i+1, key)
+			}
+			var keys string
+			if keys, err = strconv.Unquote(value); err == nil {
+				if s.Macros == nil {
+					s.Macros = make(map[string]string)
+				}
+				s.Macros[register] = keys
+			}
+		}
+		if err != nil {
+			return State{}, fmt.Errorf("line %d: invalid %s: %w", i+1, key, err)
+		}
+	}
+	return s, nil
+}
+
+// DecodeState parses a state file of any supported version and
+// validates it.
+func DecodeState(data []byte) (State, error) {
+	var s State
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(trimmed, &s)
+	} else {
+		s, err = parseLegacyState(data)
+	}
+	if err != nil {
+		return State{}, err
+	}
+	if err := s.Validate(); err != nil {
+		return State{}, err
+	}
+	return s, nil
+}
+
+// sortedKeys returns the keys of a set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// titleSet turns a list of titles back into a set, or nil if empty.
+func titleSet(titles []string) map[string]bool {
+	if len(titles) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(titles))
+	for _, title := range titles {
+		set[title] = true
+	}
+	return set
+}
+
+// sectionIndex returns the index of the section titled exactly title,
+// or -1.
+func (a *App) sectionIndex(title string) int {
+	for i, sec := range a.Sections {
+		if sec.Title == title {
+			return i
+		}
+	}
+	return -1
+}
+
+// CaptureState snapshots the reading position and settings of the app.
+// Scroll offsets of sections no longer in the document are dropped.
+func (a *App) CaptureState(pageSize int) State {
+	s := State{
+		Version:        stateVersion,
+		FilePath:       a.FilePath,
+		CurrentSection: a.CurrentIdx,
+		PageSize:       pageSize,
+		Bookmarks:      sortedKeys(a.Bookmarks),
+		Skipped:        sortedKeys(a.Skipped),
+		Macros:         a.Macros,
+		History:        a.History,
+		GitHubIssues:   a.GitHubIssues,
+		Exported:       a.Exported,
+		StudyTime:      a.StudyTime,
+		Repetition:     a.Repetition,
+		Cycles:         a.Cycles,
+		Recurring:      a.Recurring,
+		Highlights:     a.Highlights,
+		KnownSummaries: sortedKeys(a.KnownSummaries),
+	}
+	if sec := a.GetCurrentSection(); sec != nil {
+		s.CurrentTitle = sec.Title
+	}
+	for title, offset := range a.SectionScroll {
+		if len(a.Sections) > 0 && a.sectionIndex(title) < 0 {
+			continue
+		}
+		if s.Scroll == nil {
+			s.Scroll = make(map[string]int)
+		}
+		s.Scroll[title] = offset
+	}
+	return s
+}
+
+// ApplyState restores the reading position and settings from s.
+func (a *App) ApplyState(s State) {
+	a.CurrentIdx = s.CurrentSection
+	if idx := a.sectionIndex(s.CurrentTitle); idx >= 0 {
+		a.CurrentIdx = idx
+	}
+	// Only use saved file_path if current one is default
+	if a.FilePath == "learning-path-full.md" && s.FilePath != "" {
+		a.FilePath = s.FilePath
+	}
+	a.SectionScroll = s.Scroll
+	a.Bookmarks = titleSet(s.Bookmarks)
+	a.Skipped = titleSet(s.Skipped)
+	a.Macros = s.Macros
+	a.History = s.History
+	a.GitHubIssues = s.GitHubIssues
+	a.Exported = s.Exported
+	a.StudyTime = s.StudyTime
+	a.Repetition = s.Repetition
+	a.Cycles = s.Cycles
+	a.Recurring = s.Recurring
+	a.Highlights = s.Highlights
+	a.KnownSummaries = titleSet(s.KnownSummaries)
+}
+
+// documentStateFile returns the state file of the document at path. It
+// is keyed by a hash of the absolute path, so the same document shares
+// its state wherever the viewer is started from.
+func documentStateFile(path string) (string, error) {
+	base, err := stateHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "documents", documentKey(path)+".json"), nil
+}
+
+// documentKey names the files kept for the document at path: its base
+// name without extension and a hash of its absolute path, so documents
+// of the same name in different directories get files of their own.
+func documentKey(path string) string {
+	abs := absPath(path)
+	sum := sha256.Sum256([]byte(abs))
+	return strings.TrimSuffix(filepath.Base(abs), filepath.Ext(abs)) + "-" + hex.EncodeToString(sum[:8])
+}
+
+// UseDocumentState points StateFile at the document's file in the state
+// directory, moving a legacy state file of the same document from the
+// working directory there. Without a home directory the legacy location
+// is kept.
+func (a *App) UseDocumentState() {
+	path, err := documentStateFile(a.FilePath)
+	if err != nil {
+		a.StateFile = legacyStateFile
+		return
+	}
+	a.StateFile = path
+	if !fileExists(path) {
+		migrateLegacyState(legacyStateFile, path, a.FilePath)
+	}
+}
+
+// migrateLegacyState moves the legacy state file to path if it belongs
+// to the document at filePath. Older files without file_path are
+// assumed to belong to it. The moved file is encrypted when encryption
+// is on.
+func migrateLegacyState(legacy, path, filePath string) error {
+	data, err := readSecure(legacy)
+	if err != nil {
+		return err
+	}
+	if s, err := DecodeState(data); err != nil || (s.FilePath != "" && absPath(s.FilePath) != absPath(filePath)) {
+		return fmt.Errorf("%s does not belong to %s", legacy, filePath)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := writeSecure(path, data, 0o644); err != nil {
+		return err
+	}
+	return os.Remove(legacy)
+}
+
+// stateLockTimeout is how long SaveState waits for another process
+// saving the same state file.
+const stateLockTimeout = 2 * time.Second
+
+// lockStateFile serializes saving a state file between the processes
+// sharing it (the viewer, serve mode, subcommands). Returns the function
+// releasing the lock. A lock file older than lockGrace was left by a
+// crashed process and is taken over.
+func lockStateFile(path string) (func(), error) {
+	lock := path + ".lock"
+	deadline := time.Now().Add(stateLockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if stat, err := os.Stat(lock); err == nil && time.Since(stat.ModTime()) > lockGrace {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("state file %s is locked", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// ownStateFields are the fields of the reading position, which belong to
+// the process saving: another one's position never moves the reader.
+var ownStateFields = map[string]bool{
+	"version": true, "file_path": true, "current_section": true, "current_title": true, "page_size": true,
+}
+
+// mergeState merges the state ours saves with disk, which another process
+// saved since both started from base. Fields only one side changed take
+// its value; fields both changed take ours, except that the history log
+// is the union of both and maps are merged key by key the same way.
+func mergeState(base, ours, disk State) (State, error) {
+	var b, o, d map[string]json.RawMessage
+	for _, side := range []struct {
+		state State
+		raw   *map[string]json.RawMessage
+	}{{base, &b}, {ours, &o}, {disk, &d}} {
+		data, err := json.Marshal(side.state)
+		if err != nil {
+			return State{}, err
+		}
+		if err := json.Unmarshal(data, side.raw); err != nil {
+			return State{}, err
+		}
+	}
+
+	merged, err := mergeRaw(b, o, d, func(key string, bv, ov, dv json.RawMessage) (json.RawMessage, error) {
+		switch {
+		case ownStateFields[key]:
+			return ov, nil
+		case key == "history":
+			return mergeHistory(ov, dv)
+		case len(ov) > 0 && ov[0] == '{' && len(dv) > 0 && dv[0] == '{':
+			var bm, om, dm map[string]json.RawMessage
+			json.Unmarshal(bv, &bm)
+			if err := json.Unmarshal(ov, &om); err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(dv, &dm); err != nil {
+				return nil, err
+			}
+			m, err := mergeRaw(bm, om, dm, func(_ string, _, ov, _ json.RawMessage) (json.RawMessage, error) {
+				return ov, nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(m)
+		}
+		return ov, nil
+	})
+	if err != nil {
+		return State{}, err
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return State{}, err
+	}
+	var s State
+	return s, json.Unmarshal(data, &s)
+}
+
+// sameState reports whether two states are saved the same.
+func sameState(s1, s2 State) bool {
+	d1, err1 := json.Marshal(s1)
+	d2, err2 := json.Marshal(s2)
+	return err1 == nil && err2 == nil && bytes.Equal(d1, d2)
+}
+
+// mergeRaw merges the keys of ours and disk against base: a key only one
+// side changed (or added, or removed) takes that side's value, and
+// conflict decides for keys both changed.
+func mergeRaw(base, ours, disk map[string]json.RawMessage, conflict func(key string, b, o, d json.RawMessage) (json.RawMessage, error)) (map[string]json.RawMessage, error) {
+	merged := make(map[string]json.RawMessage)
+	keys := make(map[string]bool)
+	for _, m := range []map[string]json.RawMessage{base, ours, disk} {
+		for key := range m {
+			keys[key] = true
+		}
+	}
+	for key := range keys {
+		bv, ov, dv := base[key], ours[key], disk[key]
+		var value json.RawMessage
+		switch {
+		case bytes.Equal(ov, bv):
+			value = dv
+		case bytes.Equal(dv, bv) || bytes.Equal(dv, ov):
+			value = ov
+		default:
+			var err error
+			if value, err = conflict(key, bv, ov, dv); err != nil {
+				return nil, err
+			}
+		}
+		if value != nil {
+			merged[key] = value
+		}
+	}
+	return merged, nil
+}
+
+// mergeHistory returns the union of two checkbox logs, oldest first.
+func mergeHistory(ours, disk json.RawMessage) (json.RawMessage, error) {
+	var o, d []CheckboxEvent
+	if err := json.Unmarshal(ours, &o); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(disk, &d); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(o))
+	key := func(ev CheckboxEvent) string {
+		return fmt.Sprintf("%d\x1f%s\x1f%s\x1f%t", ev.Time.UnixNano(), ev.Section, ev.Item, ev.Checked)
+	}
+	for _, ev := range o {
+		seen[key(ev)] = true
+	}
+	for _, ev := range d {
+		if !seen[key(ev)] {
+			o = append(o, ev)
+		}
+	}
+	sort.SliceStable(o, func(i, j int) bool { return o[i].Time.Before(o[j].Time) })
+	return json.Marshal(o)
+}
+
+// SaveState saves current reading position and settings to state file.
+// The file is replaced atomically, so a crash never leaves it half
+// written. Changes another process (serve mode, another viewer) saved
+// since this one last loaded or saved the state are merged in, and the
+// app takes the merged state. In review mode the mentee's state is left
+// untouched.
+func (a *App) SaveState(pageSize int) (err error) {
+	if a.ReviewMode || a.Piped {
+		return nil
+	}
+	defer func() {
+		if err != nil {
+			logger.Error("save state", "path", a.StateFile, "err", err)
+		} else {
+			logger.Debug("saved state", "path", a.StateFile)
+		}
+	}()
+	if err := os.MkdirAll(filepath.Dir(a.StateFile), 0o755); err != nil {
+		return err
+	}
+	unlock, err := lockStateFile(a.StateFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state := a.CaptureState(pageSize)
+	if data, err := readSecure(a.StateFile); err == nil {
+		base := State{}
+		if a.stateBase != nil {
+			base = *a.stateBase
+		}
+		if disk, err := DecodeState(data); err == nil && !sameState(disk, base) {
+			if state, err = mergeState(base, state, disk); err != nil {
+				return err
+			}
+			a.ApplyState(state)
+		}
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := a.StateFile + ".tmp"
+	if err := writeSecure(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, a.StateFile); err != nil {
+		return err
+	}
+	a.stateBase = &state
+	return nil
+}
+
+// LoadState restores reading position and settings from state file.
+// Returns (pageSize, error). If the file doesn't exist the error wraps
+// os.ErrNotExist. A legacy file is upgraded in place, keeping a backup
+// in StateFile.v1; a corrupt one is moved aside to StateFile.corrupt and
+// reported, leaving the defaults untouched.
+func (a *App) LoadState() (int, error) {
+	if a.Piped {
+		return 0, fmt.Errorf("piped document has no state: %w", os.ErrNotExist)
+	}
+	data, err := readSecure(a.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, err // File doesn't exist, use defaults
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot read state file %s: %w", a.StateFile, err)
+	}
+
+	s, err := DecodeState(data)
+	if err != nil {
+		logger.Warn("corrupt state file", "path", a.StateFile, "err", err)
+		corrupt := a.StateFile + ".corrupt"
+		if renameErr := os.Rename(a.StateFile, corrupt); renameErr != nil {
+			return 0, fmt.Errorf("state file %s is corrupt: %w", a.StateFile, err)
+		}
+		return 0, fmt.Errorf("state file %s is corrupt (moved to %s): %w", a.StateFile, corrupt, err)
+	}
+	a.ApplyState(s)
+	a.stateBase = &s
+
+	if s.Version < stateVersion && !a.ReviewMode {
+		backup := fmt.Sprintf("%s.v%d", a.StateFile, s.Version)
+		// data is decrypted already: seal the backup again
+		if err := writeSecure(backup, data, 0o600); err != nil {
+			return s.PageSize, fmt.Errorf("cannot back up state file before upgrading: %w", err)
+		}
+		if err := a.SaveState(s.PageSize); err != nil {
+			return s.PageSize, fmt.Errorf("cannot upgrade state file: %w", err)
+		}
+	}
+	return s.PageSize, nil
+}
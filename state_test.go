@@ -0,0 +1,236 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// State File Tests
+// ============================================================================
+
+func TestStateRoundTrip(t *testing.T) {
+	app := createTestApp()
+	app.StateFile = filepath.Join(t.TempDir(), "state")
+	app.CurrentIdx = 3
+	app.ToggleBookmark(2)
+	app.ToggleSkip(5)
+	app.SectionScroll = map[string]int{app.Sections[3].Title: 7, "Đã xóa": 4}
+
+	if err := app.SaveState(25); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+	data, _ := os.ReadFile(app.StateFile)
+	if !strings.Contains(string(data), `"version": 2`) {
+		t.Errorf("Expected a versioned JSON state file, got:\n%s", data)
+	}
+
+	loaded := createTestApp()
+	loaded.StateFile = app.StateFile
+	pageSize, err := loaded.LoadState()
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if pageSize != 25 || loaded.CurrentIdx != 3 {
+		t.Errorf("Expected page size 25 at section 3, got %d at %d", pageSize, loaded.CurrentIdx)
+	}
+	if !loaded.Bookmarks[app.Sections[2].Title] || !loaded.IsSkipped(5) {
+		t.Errorf("Expected bookmark and skip restored, got %v %v", loaded.Bookmarks, loaded.Skipped)
+	}
+	want := map[string]int{app.Sections[3].Title: 7}
+	if !reflect.DeepEqual(loaded.SectionScroll, want) {
+		t.Errorf("Expected scroll of existing sections only %v, got %v", want, loaded.SectionScroll)
+	}
+}
+
+func TestLoadStateFollowsCurrentTitle(t *testing.T) {
+	app := createTestApp()
+	app.StateFile = filepath.Join(t.TempDir(), "state")
+	app.CurrentIdx = 3
+	title := app.Sections[3].Title
+	app.SaveState(20)
+
+	// A section inserted before the current one shifts its index
+	loaded := createTestApp()
+	loaded.StateFile = app.StateFile
+	loaded.Sections = append([]Section{{Title: "Mới", Level: 2}}, loaded.Sections...)
+	if _, err := loaded.LoadState(); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Sections[loaded.CurrentIdx].Title != title {
+		t.Errorf("Expected current section %q, got %q", title, loaded.Sections[loaded.CurrentIdx].Title)
+	}
+}
+
+func TestLoadStateMigratesLegacyFile(t *testing.T) {
+	app := NewApp()
+	app.StateFile = filepath.Join(t.TempDir(), "state")
+	legacy := "current_section=4\npage_size=18\nfile_path=learning-path-full.md\nskipped=Tuần 1\nmacro.a=\"jj\"\n"
+	os.WriteFile(app.StateFile, []byte(legacy), 0o644)
+
+	pageSize, err := app.LoadState()
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if pageSize != 18 || app.CurrentIdx != 4 || !app.Skipped["Tuần 1"] || app.Macros["a"] != "jj" {
+		t.Errorf("Legacy state not applied: page=%d idx=%d skipped=%v macros=%v", pageSize, app.CurrentIdx, app.Skipped, app.Macros)
+	}
+
+	if backup, err := os.ReadFile(app.StateFile + ".v1"); err != nil || string(backup) != legacy {
+		t.Errorf("Expected legacy backup, got %q (%v)", backup, err)
+	}
+	data, _ := os.ReadFile(app.StateFile)
+	s, err := DecodeState(data)
+	if err != nil || s.Version != stateVersion || s.PageSize != 18 {
+		t.Errorf("Expected upgraded state file, got %+v (%v)", s, err)
+	}
+}
+
+func TestLoadStateEncryptsUpgradeBackup(t *testing.T) {
+	withVault(t, "secret")
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	chdir(t, t.TempDir())
+	legacy := "current_section=2\npage_size=18\nfile_path=path.md\n"
+	os.WriteFile(legacyStateFile, []byte(legacy), 0o644)
+
+	app := NewApp()
+	app.FilePath = "path.md"
+	app.UseDocumentState()
+	if data, err := os.ReadFile(app.StateFile); err != nil || !isEncrypted(data) {
+		t.Fatalf("Expected the migrated state file to be encrypted (%v)", err)
+	}
+	if _, err := app.LoadState(); err != nil || app.CurrentIdx != 2 {
+		t.Fatalf("Expected migrated state at section 2, got %d (%v)", app.CurrentIdx, err)
+	}
+
+	backup := app.StateFile + ".v1"
+	data, err := os.ReadFile(backup)
+	if err != nil || !isEncrypted(data) {
+		t.Fatalf("Expected an encrypted upgrade backup (%v)", err)
+	}
+	if plain, err := readSecure(backup); err != nil || string(plain) != legacy {
+		t.Errorf("Expected the backup to decrypt to the old state, got %q (%v)", plain, err)
+	}
+	if info, err := os.Stat(backup); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected a private backup, got %v (%v)", info.Mode(), err)
+	}
+}
+
+func TestDecodeStateRejectsCorruptState(t *testing.T) {
+	tests := map[string]string{
+		"bad json":        `{"version": 2, "current_section": `,
+		"no version":      `{"current_section": 3}`,
+		"future version":  `{"version": 99}`,
+		"negative index":  `{"version": 2, "current_section": -1}`,
+		"negative scroll": `{"version": 2, "scroll": {"A": -2}}`,
+		"bad register":    `{"version": 2, "macros": {"1": "j"}}`,
+		"legacy garbage":  "current_section=abc\n",
+		"legacy unknown":  "\x00\x01binary",
+	}
+	for name, data := range tests {
+		if _, err := DecodeState([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadStateMovesCorruptFileAside(t *testing.T) {
+	app := NewApp()
+	app.StateFile = filepath.Join(t.TempDir(), "state")
+	os.WriteFile(app.StateFile, []byte("current_section=abc\n"), 0o644)
+
+	if _, err := app.LoadState(); err == nil || errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected a corruption error, got %v", err)
+	}
+	if app.CurrentIdx != 0 {
+		t.Errorf("Expected defaults kept, got section %d", app.CurrentIdx)
+	}
+	if fileExists(app.StateFile) || !fileExists(app.StateFile+".corrupt") {
+		t.Error("Expected corrupt state moved to .corrupt")
+	}
+}
+
+// ============================================================================
+// Bookmark Tests
+// ============================================================================
+
+func TestToggleBookmark(t *testing.T) {
+	app := createTestApp()
+
+	if !app.ToggleBookmark(2) || !app.Bookmarks[app.Sections[2].Title] {
+		t.Error("Expected section 2 to be bookmarked")
+	}
+	if app.ToggleBookmark(2) || app.Bookmarks[app.Sections[2].Title] {
+		t.Error("Expected section 2 to be unbookmarked")
+	}
+	if app.ToggleBookmark(-1) || app.ToggleBookmark(len(app.Sections)) {
+		t.Error("Expected out-of-range toggle to fail")
+	}
+}
+
+// ============================================================================
+// State Location Tests
+// ============================================================================
+
+// chdir changes the working directory for the duration of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestDocumentStateFileKeyedByAbsolutePath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	rel, err := documentStateFile("path.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	abs, _ := documentStateFile(filepath.Join(dir, "path.md"))
+	other, _ := documentStateFile(filepath.Join(dir, "sub", "path.md"))
+
+	if rel != abs {
+		t.Errorf("Expected relative and absolute paths to share state, got %s and %s", rel, abs)
+	}
+	if rel == other {
+		t.Error("Expected documents with the same name in different directories to have separate state")
+	}
+	if !strings.HasPrefix(filepath.Base(rel), "path-") || !strings.HasSuffix(rel, ".json") {
+		t.Errorf("Expected a readable state file name, got %s", rel)
+	}
+}
+
+func TestUseDocumentStateMigratesLegacyFile(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	chdir(t, t.TempDir())
+	os.WriteFile(legacyStateFile, []byte("current_section=2\nfile_path=path.md\n"), 0o644)
+
+	app := NewApp()
+	app.FilePath = "other.md"
+	app.UseDocumentState()
+	if !fileExists(legacyStateFile) || fileExists(app.StateFile) {
+		t.Fatal("Expected state of another document to stay in place")
+	}
+
+	app = NewApp()
+	app.FilePath = "path.md"
+	app.UseDocumentState()
+	if fileExists(legacyStateFile) {
+		t.Error("Expected legacy state file to be moved")
+	}
+	if _, err := app.LoadState(); err != nil || app.CurrentIdx != 2 {
+		t.Errorf("Expected migrated state at section 2, got %d (%v)", app.CurrentIdx, err)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultReadingWPM is the reading speed used for reading time estimates
+// unless reading_wpm is configured.
+const defaultReadingWPM = 200
+
+// ContentStats describes how long a section is to read.
+type ContentStats struct {
+	Words      int
+	CodeBlocks int
+	// ReadingTime is the estimated time to read the words
+	ReadingTime time.Duration
+}
+
+// readingWPM returns the configured reading speed in words per minute.
+func readingWPM() int {
+	if config.ReadingWPM > 0 {
+		return config.ReadingWPM
+	}
+	return defaultReadingWPM
+}
+
+// SectionStats counts the words (code included, fences and markup left
+// out) and code blocks of a section and estimates its reading time at wpm
+// words per minute.
+func (a *App) SectionStats(idx, wpm int) ContentStats {
+	var stats ContentStats
+	if idx < 0 || idx >= len(a.Sections) {
+		return stats
+	}
+	lines := strings.Split(a.Sections[idx].Content(), "\n")
+	for i, ml := range a.scanLines(lines) {
+		switch ml.Kind {
+		case LineFenceOpen:
+			stats.CodeBlocks++
+		case LineText, LineCode, LineHeader, LineMath:
+			stats.Words += wordCount(lines[i])
+		}
+	}
+	if wpm <= 0 {
+		wpm = defaultReadingWPM
+	}
+	stats.ReadingTime = time.Duration(stats.Words) * time.Minute / time.Duration(wpm)
+	return stats
+}
+
+// wordCount counts the words of a line, leaving out the markdown
+// punctuation that is not read: list bullets, quote bars, header marks,
+// table pipes and checkboxes.
+func wordCount(line string) int {
+	n := 0
+	for _, w := range strings.Fields(line) {
+		if w != "[x]" && w != "[X]" && strings.Trim(w, "#>-*+|[]~`=") != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// readingTimeLabel renders a reading time as "~8 phút" or "~2h05",
+// rounded up to whole minutes.
+func readingTimeLabel(d time.Duration) string {
+	minutes := int((d + time.Minute - 1) / time.Minute)
+	if minutes >= 60 {
+		return fmt.Sprintf("~%dh%02d", minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("~%d phút", max(1, minutes))
+}
+
+// statsLabel describes a section for its header, e.g. "~8 phút đọc ·
+// 1520 từ · 3 code block".
+func statsLabel(stats ContentStats) string {
+	label := fmt.Sprintf("%s đọc · %d từ", readingTimeLabel(stats.ReadingTime), stats.Words)
+	if stats.CodeBlocks > 0 {
+		label += fmt.Sprintf(" · %d code block", stats.CodeBlocks)
+	}
+	return label
+}
+
+// RemainingReadingTime sums the reading time of the sections not read
+// yet (no study time recorded) and not skipped, returning it with the
+// number of such sections that have content.
+func (a *App) RemainingReadingTime(wpm int) (time.Duration, int) {
+	var total time.Duration
+	unread := 0
+	for i, sec := range a.Sections {
+		if a.IsSkipped(i) || a.StudyTime[sec.Title] > 0 {
+			continue
+		}
+		if stats := a.SectionStats(i, wpm); stats.Words > 0 {
+			total += stats.ReadingTime
+			unread++
+		}
+	}
+	return total, unread
+}
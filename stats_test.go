@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSectionStats(t *testing.T) {
+	app := createTestApp()
+	stats := app.SectionStats(2, 6)
+	if stats.Words != 12 || stats.CodeBlocks != 0 || stats.ReadingTime != 2*time.Minute {
+		t.Errorf("SectionStats = %+v", stats)
+	}
+
+	app.FileLines = strings.Split("# Ops\n\n## Restart\n\nRun this:\n\n```bash\nsystemctl restart nginx\n```\n\n> quoted words here\n", "\n")
+	app.ParseSections()
+	if stats := app.SectionStats(1, 200); stats.Words != 8 || stats.CodeBlocks != 1 {
+		t.Errorf("Expected code words counted and fences left out, got %+v", stats)
+	}
+	if stats := app.SectionStats(9, 200); stats.Words != 0 {
+		t.Errorf("Expected no stats out of range, got %+v", stats)
+	}
+}
+
+func TestReadingTimeLabel(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		10 * time.Second:                "~1 phút",
+		8*time.Minute + time.Second:     "~9 phút",
+		2*time.Hour + 5*time.Minute:     "~2h05",
+		59*time.Minute + 30*time.Second: "~1h00",
+	} {
+		if got := readingTimeLabel(d); got != want {
+			t.Errorf("readingTimeLabel(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestRemainingReadingTime(t *testing.T) {
+	app := createTestApp()
+	all, unread := app.RemainingReadingTime(200)
+	if unread == 0 {
+		t.Fatal("Expected unread sections")
+	}
+
+	app.StudyTime = map[string]float64{app.Sections[2].Title: 30}
+	app.Skipped = map[string]bool{app.Sections[3].Title: true}
+	rest, left := app.RemainingReadingTime(200)
+	want := all - app.SectionStats(2, 200).ReadingTime - app.SectionStats(3, 200).ReadingTime
+	if left != unread-2 || rest != want {
+		t.Errorf("RemainingReadingTime = %s, %d; want %s, %d", rest, left, want, unread-2)
+	}
+}
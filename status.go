@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Severity classifies a status message.
+type Severity int
+
+const (
+	// SeverityInfo is a neutral message
+	SeverityInfo Severity = iota
+	// SeveritySuccess confirms a completed action ("Saved")
+	SeveritySuccess
+	// SeverityWarning reports something that did not happen ("No more matches")
+	SeverityWarning
+	// SeverityError reports a failed action
+	SeverityError
+)
+
+// statusTTL is how long a message stays on the status line.
+const statusTTL = 5 * time.Second
+
+// maxStatusHistory bounds the message history.
+const maxStatusHistory = 200
+
+// StatusMessage is one notification shown on the status line.
+type StatusMessage struct {
+	Time     time.Time
+	Severity Severity
+	Text     string
+}
+
+// StatusLine holds the notifications of the session, newest last.
+type StatusLine struct {
+	History []StatusMessage
+}
+
+// Push records a message at the current time.
+func (s *StatusLine) Push(sev Severity, text string) {
+	s.History = append(s.History, StatusMessage{Time: time.Now(), Severity: sev, Text: text})
+	if len(s.History) > maxStatusHistory {
+		s.History = s.History[len(s.History)-maxStatusHistory:]
+	}
+}
+
+// Current returns the latest message if it is still fresh at now.
+func (s *StatusLine) Current(now time.Time) (StatusMessage, bool) {
+	if len(s.History) == 0 {
+		return StatusMessage{}, false
+	}
+	msg := s.History[len(s.History)-1]
+	return msg, now.Sub(msg.Time) < statusTTL
+}
+
+// String formats the message with its time, icon and severity color.
+func (m StatusMessage) String() string {
+	color, icon := Cyan, "ℹ"
+	switch m.Severity {
+	case SeveritySuccess:
+		color, icon = Green, "✓"
+	case SeverityWarning:
+		color, icon = Yellow, "⚠"
+	case SeverityError:
+		color, icon = Red, "✗"
+	}
+	return fmt.Sprintf("%s%s%s %s%s %s%s", Dim, m.Time.Format("15:04:05"), Reset, color, icon, m.Text, Reset)
+}
+
+// notify shows a message on the status line without blocking the UI.
+func notify(sev Severity, format string, args ...any) {
+	app.Status.Push(sev, fmt.Sprintf(format, args...))
+}
+
+// printStatusLine prints the current message, or an empty line so the
+// layout does not jump when messages come and go.
+func printStatusLine() {
+	if msg, ok := app.Status.Current(time.Now()); ok {
+		fmt.Println(truncateVisible(msg.String(), app.TermWidth))
+	} else {
+		fmt.Println()
+	}
+}
+
+// handleMessages shows the message history, newest first.
+func handleMessages() {
+	history := app.Status.History
+	lines := make([]string, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		lines = append(lines, history[i].String())
+	}
+	if len(lines) == 0 {
+		lines = append(lines, Dim+"Chưa có thông báo nào."+Reset)
+	}
+	showOverlay(fmt.Sprintf("📨 LỊCH SỬ THÔNG BÁO (%d)", len(history)), lines)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================================================
+// Status Line Tests
+// ============================================================================
+
+func TestStatusLineCurrentExpires(t *testing.T) {
+	var s StatusLine
+	if _, ok := s.Current(time.Now()); ok {
+		t.Error("Expected no current message on an empty status line")
+	}
+
+	s.Push(SeveritySuccess, "Đã lưu")
+	msg, ok := s.Current(time.Now())
+	if !ok || msg.Text != "Đã lưu" || msg.Severity != SeveritySuccess {
+		t.Errorf("Expected fresh success message, got %+v (ok=%v)", msg, ok)
+	}
+
+	if _, ok := s.Current(time.Now().Add(statusTTL)); ok {
+		t.Error("Expected message to expire after the TTL")
+	}
+}
+
+func TestStatusLineHistoryBounded(t *testing.T) {
+	var s StatusLine
+	for i := 0; i < maxStatusHistory+10; i++ {
+		s.Push(SeverityInfo, "msg")
+	}
+	if len(s.History) != maxStatusHistory {
+		t.Errorf("Expected %d messages, got %d", maxStatusHistory, len(s.History))
+	}
+}
+
+func TestStatusMessageString(t *testing.T) {
+	msg := StatusMessage{Time: time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC), Severity: SeverityError, Text: "Lỗi lưu"}
+
+	s := msg.String()
+	if !strings.Contains(s, Red) {
+		t.Error("Expected error messages in red")
+	}
+	if plain := stripANSI(s); plain != "15:04:05 ✗ Lỗi lưu" {
+		t.Errorf("Unexpected format: %q", plain)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	app = NewApp()
+	notify(SeverityWarning, "Không tìm thấy: %s", "k8s")
+
+	if msg, ok := app.Status.Current(time.Now()); !ok || msg.Text != "Không tìm thấy: k8s" {
+		t.Errorf("Expected formatted warning, got %+v", msg)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinPath is the document argument that reads the document from stdin,
+// as in "cat runbook.md | sre-learn -".
+const stdinPath = "-"
+
+// readStdinDocument reads the piped document and reattaches standard
+// input to the terminal, so keys, editors and prompts still reach the
+// user at the end of a pipeline.
+func readStdinDocument(in *os.File) (string, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", fmt.Errorf("cannot read document from stdin: %w", err)
+	}
+	if !isTerminal(in) {
+		tty, err := os.Open("/dev/tty")
+		if err != nil {
+			return "", fmt.Errorf("no terminal for keyboard input: %w", err)
+		}
+		os.Stdin = tty
+		keyboard.src = tty
+	}
+	return string(data), nil
+}
+
+// LoadPiped loads a document read from stdin. It has no file on disk, so
+// it is read-only and keeps no state, lock or review comments.
+func (a *App) LoadPiped(content string) error {
+	content, err := decodeDocument(content)
+	if err != nil {
+		return fmt.Errorf("cannot decrypt notes of the piped document: %w", err)
+	}
+	a.FilePath, a.StateFile, a.Profile = stdinPath, "", ""
+	a.FileContent = content
+	a.FileLines = strings.Split(content, "\n")
+	a.Piped = true
+	a.SetReadOnly(readOnlyPiped)
+	a.ParseSections()
+	return nil
+}
+
+// writePiped saves a piped document to path, where it is opened for
+// editing. An existing file is not overwritten.
+func writePiped(path, content string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPiped(t *testing.T) {
+	chdir(t, t.TempDir())
+	a := NewApp()
+	if err := a.LoadPiped(sampleMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Piped || !a.ReadOnly || a.FilePath != stdinPath || len(a.Sections) == 0 {
+		t.Fatalf("Expected a read-only piped document, got %+v", a)
+	}
+
+	if err := a.SaveFile(); !errors.Is(err, errReadOnly) {
+		t.Errorf("Expected a read-only error, got %v", err)
+	}
+	if err := a.SaveState(20); err != nil {
+		t.Errorf("SaveState = %v", err)
+	}
+	if _, err := a.LoadState(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected no state, got %v", err)
+	}
+	if entries, _ := os.ReadDir("."); len(entries) != 0 {
+		t.Errorf("Expected nothing to be written, got %v", entries)
+	}
+}
+
+func TestWritePiped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runbook.md")
+	if err := writePiped(path, sampleMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != sampleMarkdown {
+		t.Errorf("Expected the piped document to be written, got %q", data)
+	}
+	if err := writePiped(path, "# Other\n"); err == nil {
+		t.Error("Expected an existing file not to be overwritten")
+	}
+}
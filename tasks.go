@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// personalTaskMarker distinguishes personal TODOs from curriculum checkboxes.
+// Personal tasks look like "- [ ] 🧑 my task" and are excluded from progress.
+const personalTaskMarker = "🧑"
+
+// myTasksHeader introduces the personal task area of a section.
+const myTasksHeader = "**🧑 Việc của tôi:**"
+
+// PersonalTask is a personal TODO found in the document.
+type PersonalTask struct {
+	// SectionIdx is the index of the owning section
+	SectionIdx int
+	// Line is the content line index within the section
+	Line int
+	// Text is the task description without checkbox and marker
+	Text string
+	// Done reports whether the task is checked
+	Done bool
+}
+
+// isPersonalTask reports whether a line is a personal TODO checkbox.
+func isPersonalTask(line string) bool {
+	return isCheckbox(line) && strings.HasPrefix(checkboxText(line), personalTaskMarker)
+}
+
+// AddPersonalTask adds a personal TODO to the "My Tasks" area of the
+// current section, creating the area at the end of the section if needed.
+func (a *App) AddPersonalTask(text string) {
+	text = strings.TrimSpace(text)
+	sec := a.GetCurrentSection()
+	if text == "" || sec == nil {
+		return
+	}
+
+	task := "- [ ] " + personalTaskMarker + " " + text
+	lines := strings.Split(sec.Content(), "\n")
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) != myTasksHeader {
+			continue
+		}
+		// Insert after the last task of the area
+		insertAt := i + 1
+		for insertAt < len(lines) && isPersonalTask(lines[insertAt]) {
+			insertAt++
+		}
+		lines = append(lines[:insertAt], append([]string{task}, lines[insertAt:]...)...)
+		a.Sections[a.CurrentIdx].SetContent(strings.Join(lines, "\n"))
+		return
+	}
+
+	content := strings.TrimRight(sec.Content(), "\n")
+	a.Sections[a.CurrentIdx].SetContent(content + "\n\n" + myTasksHeader + "\n" + task + "\n")
+}
+
+// PersonalTasks returns all personal TODOs across the document.
+func (a *App) PersonalTasks() []PersonalTask {
+	var tasks []PersonalTask
+	for i, sec := range a.Sections {
+		for j, line := range strings.Split(sec.Content(), "\n") {
+			if !isPersonalTask(line) {
+				continue
+			}
+			tasks = append(tasks, PersonalTask{
+				SectionIdx: i,
+				Line:       j,
+				Text:       strings.TrimSpace(strings.TrimPrefix(checkboxText(line), personalTaskMarker)),
+				Done:       isChecked(line),
+			})
+		}
+	}
+	return tasks
+}
+
+// handleAddTask prompts for a personal TODO and saves it in the current section.
+func handleAddTask() {
+	sec := app.GetCurrentSection()
+	if sec == nil {
+		return
+	}
+
+	terminal.SetRawMode(false)
+	ClearScreen()
+	fmt.Printf("%s🧑 VIỆC CỦA TÔI - %s%s\n", Bold+Cyan, sec.Title, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	fmt.Printf("%s(Không tính vào tiến độ học)%s\n", Dim, Reset)
+	fmt.Printf("\n%sViệc cần làm (Enter để hủy):%s ", Bold, Reset)
+
+	text, _ := bufio.NewReader(keyboard).ReadString('\n')
+	if strings.TrimSpace(text) != "" {
+		app.AddPersonalTask(text)
+		app.UpdateFileSection(app.CurrentIdx)
+		saveDocument()
+	}
+
+	terminal.SetRawMode(true)
+}
+
+// handleTaskList shows all personal TODOs and jumps to the chosen one's section.
+func handleTaskList() {
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+	ClearScreen()
+
+	tasks := app.PersonalTasks()
+	fmt.Printf("%s🧑 TẤT CẢ VIỆC CỦA TÔI (%d)%s\n", Bold+Cyan, len(tasks), Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+
+	if len(tasks) == 0 {
+		fmt.Printf("\n%sChưa có việc nào. Nhấn m trong một section để thêm.%s\n", Dim, Reset)
+		fmt.Printf("\n%s[Enter để quay lại]%s", Dim, Reset)
+		bufio.NewReader(keyboard).ReadString('\n')
+		return
+	}
+
+	for i, task := range tasks {
+		status := Red + glyphs.Unchecked + Reset
+		if task.Done {
+			status = Green + glyphs.Checked + Reset
+		}
+		fmt.Printf("%s%2d.%s %s %s %s(%s)%s\n", Cyan, i+1, Reset, status, task.Text, Dim, app.Sections[task.SectionIdx].Title, Reset)
+	}
+
+	fmt.Printf("\n%sNhập số để đến section hoặc Enter để hủy:%s ", Bold, Reset)
+	input, _ := bufio.NewReader(keyboard).ReadString('\n')
+	if num, err := strconv.Atoi(strings.TrimSpace(input)); err == nil && num >= 1 && num <= len(tasks) {
+		app.GotoSection(tasks[num-1].SectionIdx)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Personal Task Tests
+// ============================================================================
+
+func TestIsPersonalTask(t *testing.T) {
+	if !isPersonalTask("- [ ] 🧑 Review notes") || !isPersonalTask("  - [x] 🧑 Done") {
+		t.Error("Expected personal task lines to be detected")
+	}
+
+	if isPersonalTask("- [ ] Curriculum item") {
+		t.Error("Expected curriculum checkbox not to be a personal task")
+	}
+}
+
+func TestAddPersonalTaskCreatesArea(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 2
+
+	app.AddPersonalTask("Re-read chapter")
+	app.AddPersonalTask("Ask mentor")
+	app.AddPersonalTask("   ")
+
+	content := app.GetCurrentSection().Content()
+	if strings.Count(content, myTasksHeader) != 1 {
+		t.Errorf("Expected exactly one task area, got content %q", content)
+	}
+
+	first := strings.Index(content, "🧑 Re-read chapter")
+	second := strings.Index(content, "🧑 Ask mentor")
+	if first < 0 || second < first {
+		t.Errorf("Expected tasks in insertion order, got content %q", content)
+	}
+
+	if len(app.PersonalTasks()) != 2 {
+		t.Errorf("Expected 2 personal tasks, got %d", len(app.PersonalTasks()))
+	}
+}
+
+func TestPersonalTasksExcludedFromProgress(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 2
+	checked, total := app.GetProgress(2)
+
+	app.AddPersonalTask("My own follow-up")
+
+	newChecked, newTotal := app.GetProgress(2)
+	if newChecked != checked || newTotal != total {
+		t.Errorf("Expected progress %d/%d unchanged, got %d/%d", checked, total, newChecked, newTotal)
+	}
+}
+
+func TestPersonalTasksFields(t *testing.T) {
+	app := createTestApp()
+	app.CurrentIdx = 5
+	app.AddPersonalTask("Practice drill")
+	app.ToggleCheckbox(app.PersonalTasks()[0].Line)
+
+	tasks := app.PersonalTasks()
+	if len(tasks) != 1 {
+		t.Fatalf("Expected 1 task, got %d", len(tasks))
+	}
+
+	if tasks[0].Text != "Practice drill" || !tasks[0].Done || tasks[0].SectionIdx != 5 {
+		t.Errorf("Unexpected task: %+v", tasks[0])
+	}
+}
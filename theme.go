@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ColorDepth is the number of colors the terminal can show.
+type ColorDepth int
+
+const (
+	// Color8 is the 8 basic ANSI colors
+	Color8 ColorDepth = 8
+	// Color256 is the xterm 256-color palette
+	Color256 ColorDepth = 256
+	// ColorTrue is 24-bit RGB
+	ColorTrue ColorDepth = 1 << 24
+)
+
+// detectColorDepth returns the configured depth (color_depth=8|256|
+// truecolor), or for "auto" the one announced by COLORTERM and TERM.
+func detectColorDepth(setting string, getenv func(string) string) ColorDepth {
+	switch setting {
+	case "8":
+		return Color8
+	case "256":
+		return Color256
+	case "truecolor":
+		return ColorTrue
+	}
+	if ct := getenv("COLORTERM"); ct == "truecolor" || ct == "24bit" {
+		return ColorTrue
+	}
+	if strings.Contains(getenv("TERM"), "256color") {
+		return Color256
+	}
+	return Color8
+}
+
+// colorNames are the palette entries a theme can change, in ANSI order.
+var colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// basicRGB approximates the basic colors (xterm defaults), to downgrade
+// RGB values to the nearest one.
+var basicRGB = [8][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+}
+
+// cubeLevels are the component values of the 6x6x6 cube of the 256-color
+// palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// paletteRGB returns the RGB value of a 256-color palette index.
+func paletteRGB(n int) [3]int {
+	switch {
+	case n < 8:
+		return basicRGB[n]
+	case n < 16:
+		c := basicRGB[n-8]
+		return [3]int{min(c[0]+50, 255), min(c[1]+50, 255), min(c[2]+50, 255)}
+	case n < 232:
+		n -= 16
+		return [3]int{cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]}
+	default:
+		g := 8 + 10*(n-232)
+		return [3]int{g, g, g}
+	}
+}
+
+// colorDistance is the squared distance between two RGB values.
+func colorDistance(a, b [3]int) int {
+	d := 0
+	for i := range a {
+		d += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return d
+}
+
+// nearestBasic returns the basic color (0-7) closest to rgb.
+func nearestBasic(rgb [3]int) int {
+	best := 0
+	for i, c := range basicRGB {
+		if colorDistance(rgb, c) < colorDistance(rgb, basicRGB[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// nearest256 returns the 256-color palette index closest to rgb, from
+// the color cube or the gray ramp.
+func nearest256(rgb [3]int) int {
+	cube := 16
+	for i, mult := range []int{36, 6, 1} {
+		level := 0
+		for l, v := range cubeLevels {
+			if abs(rgb[i]-v) < abs(rgb[i]-cubeLevels[level]) {
+				level = l
+			}
+		}
+		cube += level * mult
+	}
+	gray := 232 + max(0, min(23, ((rgb[0]+rgb[1]+rgb[2])/3-8+5)/10))
+	if colorDistance(rgb, paletteRGB(gray)) < colorDistance(rgb, paletteRGB(cube)) {
+		return gray
+	}
+	return cube
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// themeColor is a color of a theme: a basic color, a 256-color index or
+// an RGB value.
+type themeColor struct {
+	basic int // 0-7, or -1
+	index int // 0-255, or -1
+	rgb   [3]int
+}
+
+// parseColor parses a color: a basic name ("cyan"), a 256-color index
+// ("208") or an RGB value ("#ff8700").
+func parseColor(spec string) (themeColor, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	for i, name := range colorNames {
+		if spec == name {
+			return themeColor{basic: i, index: -1, rgb: basicRGB[i]}, nil
+		}
+	}
+	if n, err := strconv.Atoi(spec); err == nil {
+		if n < 0 || n > 255 {
+			return themeColor{}, fmt.Errorf("color index %d out of range 0-255", n)
+		}
+		return themeColor{basic: -1, index: n, rgb: paletteRGB(n)}, nil
+	}
+	if hex, ok := strings.CutPrefix(spec, "#"); ok && len(hex) == 6 {
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return themeColor{basic: -1, index: -1, rgb: [3]int{int(v >> 16), int(v >> 8 & 0xff), int(v & 0xff)}}, nil
+		}
+	}
+	return themeColor{}, fmt.Errorf("invalid color %q (use a name, 0-255 or #rrggbb)", spec)
+}
+
+// sgr returns the escape sequence selecting c as the foreground (or
+// background) color, downgraded to what depth can show.
+func (c themeColor) sgr(depth ColorDepth, background bool) string {
+	base, ext := 30, "38"
+	if background {
+		base, ext = 40, "48"
+	}
+	switch {
+	case c.basic >= 0:
+		return fmt.Sprintf("\033[%dm", base+c.basic)
+	case c.index >= 0 && depth >= Color256:
+		return fmt.Sprintf("\033[%s;5;%dm", ext, c.index)
+	case c.index < 0 && depth >= ColorTrue:
+		return fmt.Sprintf("\033[%s;2;%d;%d;%dm", ext, c.rgb[0], c.rgb[1], c.rgb[2])
+	case depth >= Color256:
+		return fmt.Sprintf("\033[%s;5;%dm", ext, nearest256(c.rgb))
+	default:
+		return fmt.Sprintf("\033[%dm", base+nearestBasic(c.rgb))
+	}
+}
+
+// Theme maps palette names to colors. Names it leaves out keep the basic
+// ANSI color.
+type Theme map[string]string
+
+// themes are the built-in themes, selected with theme=<name>.
+var themes = map[string]Theme{
+	"default": {},
+	"solarized": {
+		"black": "#073642", "red": "#dc322f", "green": "#859900", "yellow": "#b58900",
+		"blue": "#268bd2", "magenta": "#d33682", "cyan": "#2aa198", "white": "#eee8d5",
+	},
+	"gruvbox": {
+		"black": "#282828", "red": "#fb4934", "green": "#b8bb26", "yellow": "#fabd2f",
+		"blue": "#458588", "magenta": "#d3869b", "cyan": "#8ec07c", "white": "#ebdbb2",
+	},
+	"nord": {
+		"black": "#3b4252", "red": "#bf616a", "green": "#a3be8c", "yellow": "#ebcb8b",
+		"blue": "#5e81ac", "magenta": "#b48ead", "cyan": "#88c0d0", "white": "#e5e9f0",
+	},
+}
+
+// themeNames returns the names of the built-in themes, sorted.
+func themeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// paletteVars are the foreground and background variables of each
+// palette name.
+var paletteVars = map[string][2]*string{
+	"black": {&Black, &BgBlack}, "red": {&Red, &BgRed}, "green": {&Green, &BgGreen}, "yellow": {&Yellow, &BgYellow},
+	"blue": {&Blue, &BgBlue}, "magenta": {&Magenta, &BgMagenta}, "cyan": {&Cyan, &BgCyan}, "white": {&White, &BgWhite},
+}
+
+// applyTheme sets the palette from a theme and the color.<name>
+// overrides, downgraded to depth.
+func applyTheme(theme Theme, overrides map[string]string, depth ColorDepth) error {
+	for _, name := range colorNames {
+		spec, ok := overrides[name]
+		if !ok {
+			spec, ok = theme[name]
+		}
+		if !ok {
+			spec = name
+		}
+		c, err := parseColor(spec)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		vars := paletteVars[name]
+		*vars[0], *vars[1] = c.sgr(depth, false), c.sgr(depth, true)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+func TestDetectColorDepth(t *testing.T) {
+	tests := []struct {
+		setting string
+		env     map[string]string
+		want    ColorDepth
+	}{
+		{"auto", map[string]string{"COLORTERM": "truecolor", "TERM": "xterm"}, ColorTrue},
+		{"auto", map[string]string{"TERM": "screen-256color"}, Color256},
+		{"auto", map[string]string{"TERM": "xterm"}, Color8},
+		{"8", map[string]string{"COLORTERM": "24bit"}, Color8},
+		{"truecolor", map[string]string{}, ColorTrue},
+	}
+	for _, tt := range tests {
+		if got := detectColorDepth(tt.setting, func(name string) string { return tt.env[name] }); got != tt.want {
+			t.Errorf("detectColorDepth(%q, %v) = %d, want %d", tt.setting, tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestColorDowngrade(t *testing.T) {
+	tests := []struct {
+		spec  string
+		depth ColorDepth
+		want  string
+	}{
+		{"cyan", ColorTrue, "\033[36m"},
+		{"#ff8700", ColorTrue, "\033[38;2;255;135;0m"},
+		{"#ff8700", Color256, "\033[38;5;208m"},
+		{"#ff8700", Color8, "\033[33m"},
+		{"#808080", Color256, "\033[38;5;244m"},
+		{"208", Color256, "\033[38;5;208m"},
+		{"208", Color8, "\033[33m"},
+		{"21", Color8, "\033[34m"},
+	}
+	for _, tt := range tests {
+		c, err := parseColor(tt.spec)
+		if err != nil {
+			t.Fatalf("parseColor(%q): %v", tt.spec, err)
+		}
+		if got := c.sgr(tt.depth, false); got != tt.want {
+			t.Errorf("%s at depth %d = %q, want %q", tt.spec, tt.depth, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"256", "#12345", "teal"} {
+		if _, err := parseColor(bad); err == nil {
+			t.Errorf("parseColor(%q) should fail", bad)
+		}
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	saved, savedBg, savedRed := Cyan, BgCyan, Red
+	defer func() { Cyan, BgCyan, Red = saved, savedBg, savedRed }()
+
+	if err := applyTheme(themes["nord"], map[string]string{"red": "196"}, Color256); err != nil {
+		t.Fatal(err)
+	}
+	if Cyan != "\033[38;5;110m" || BgCyan != "\033[48;5;110m" {
+		t.Errorf("Cyan = %q, BgCyan = %q", Cyan, BgCyan)
+	}
+	if Red != "\033[38;5;196m" {
+		t.Errorf("override not applied: Red = %q", Red)
+	}
+
+	if err := applyTheme(themes["default"], nil, ColorTrue); err != nil {
+		t.Fatal(err)
+	}
+	if Cyan != "\033[36m" {
+		t.Errorf("default theme should keep basic colors, Cyan = %q", Cyan)
+	}
+}
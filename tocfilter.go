@@ -0,0 +1,98 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// TOCFilter limits the sections listed in the TOC.
+type TOCFilter int
+
+const (
+	tocAll TOCFilter = iota
+	// tocIncomplete lists sections with unfinished checkboxes
+	tocIncomplete
+	tocBookmarked
+	tocWithNotes
+)
+
+// tocFilterNames label the filters in the TOC header, in cycling order.
+var tocFilterNames = []string{"tất cả", "chưa xong", "bookmark", "có ghi chú"}
+
+// TOCOrder sorts the sections listed in the TOC.
+type TOCOrder int
+
+const (
+	tocByPosition TOCOrder = iota
+	// tocByProgress lists the least completed sections first
+	tocByProgress
+	// tocByActivity lists the most recently worked on sections first
+	tocByActivity
+)
+
+// tocOrderNames label the orders in the TOC header, in cycling order.
+var tocOrderNames = []string{"thứ tự", "tiến độ", "hoạt động gần nhất"}
+
+// MatchesTOCFilter reports whether the section is listed under filter.
+// Skipped sections never count as incomplete.
+func (a *App) MatchesTOCFilter(idx int, filter TOCFilter) bool {
+	switch filter {
+	case tocIncomplete:
+		checked, total := a.GetProgress(idx)
+		return checked < total && !a.IsSkipped(idx)
+	case tocBookmarked:
+		return a.Bookmarks[a.Sections[idx].Title]
+	case tocWithNotes:
+		return len(extractNotes(a.Sections[idx].Content())) > 0
+	}
+	return true
+}
+
+// LastActivity returns when the section was last worked on: the later of
+// its last checked item and its newest note.
+func (a *App) LastActivity(idx int) (time.Time, bool) {
+	last, ok := a.LastCompleted(idx)
+	for _, line := range strings.Split(a.Sections[idx].Content(), "\n") {
+		m := noteTimestampRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if ts, err := time.ParseInLocation("2006-01-02 15:04", m[1], time.Local); err == nil && ts.After(last) {
+			last, ok = ts, true
+		}
+	}
+	return last, ok
+}
+
+// TOCSections returns the indexes of the sections listed under filter,
+// in the given order. Sections without checkboxes sort after the others
+// by progress, and sections never worked on after the others by
+// activity; ties keep the document order.
+func (a *App) TOCSections(filter TOCFilter, order TOCOrder) []int {
+	var idxs []int
+	for i := range a.Sections {
+		if a.MatchesTOCFilter(i, filter) {
+			idxs = append(idxs, i)
+		}
+	}
+
+	switch order {
+	case tocByProgress:
+		ratio := func(idx int) float64 {
+			checked, total := a.GetProgress(idx)
+			if total == 0 {
+				return 2
+			}
+			return float64(checked) / float64(total)
+		}
+		sort.SliceStable(idxs, func(i, j int) bool { return ratio(idxs[i]) < ratio(idxs[j]) })
+	case tocByActivity:
+		activity := make(map[int]time.Time, len(idxs))
+		for _, idx := range idxs {
+			activity[idx], _ = a.LastActivity(idx)
+		}
+		sort.SliceStable(idxs, func(i, j int) bool { return activity[idxs[i]].After(activity[idxs[j]]) })
+	}
+	return idxs
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTOCSectionsFilter(t *testing.T) {
+	app := createTestApp()
+	app.Bookmarks = map[string]bool{"Exercise 1": true}
+	app.Sections[3].SetContent(app.Sections[3].Content() + "\n\n> **Ghi chú [2025-01-31 10:00]:** hard")
+
+	if got := app.TOCSections(tocAll, tocByPosition); len(got) != len(app.Sections) {
+		t.Errorf("Expected every section, got %v", got)
+	}
+	if got := app.TOCSections(tocIncomplete, tocByPosition); !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Errorf("incomplete = %v", got)
+	}
+	if got := app.TOCSections(tocBookmarked, tocByPosition); !reflect.DeepEqual(got, []int{5}) {
+		t.Errorf("bookmarked = %v", got)
+	}
+	if got := app.TOCSections(tocWithNotes, tocByPosition); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("with notes = %v", got)
+	}
+
+	app.Skipped = map[string]bool{"Chapter 2: Advanced": true}
+	if got := app.TOCSections(tocIncomplete, tocByPosition); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("Expected skipped sections not to be incomplete, got %v", got)
+	}
+}
+
+func TestTOCSectionsOrder(t *testing.T) {
+	app := createTestApp()
+	if got := app.TOCSections(tocAll, tocByProgress); !reflect.DeepEqual(got, []int{3, 2, 5, 0, 1, 4}) {
+		t.Errorf("by progress = %v", got)
+	}
+
+	checked := time.Date(2025, 1, 30, 9, 0, 0, 0, time.Local)
+	app.History = []CheckboxEvent{{Time: checked, Section: "Exercise 1", Item: "Done", Checked: true}}
+	app.Sections[3].SetContent(app.Sections[3].Content() + "\n\n> **Ghi chú [2025-01-31 10:00]:** hard")
+	if got := app.TOCSections(tocAll, tocByActivity); !reflect.DeepEqual(got, []int{3, 5, 0, 1, 2, 4}) {
+		t.Errorf("by activity = %v", got)
+	}
+	if last, ok := app.LastActivity(5); !ok || !last.Equal(checked) {
+		t.Errorf("LastActivity = %v, %v", last, ok)
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxUndo is how many confirmed changes can be undone.
+const maxUndo = 20
+
+// undoContext is the number of unchanged lines around a change kept to
+// find it again when it is undone after other edits moved it.
+const undoContext = 2
+
+// UndoEntry is a destructive change. While the change is confirmed it
+// holds the document and progress as they were before, to roll back;
+// once confirmed it keeps only the inverse of the change, so undoing it
+// later keeps the edits made since.
+type UndoEntry struct {
+	// Title describes the change, e.g. "Xóa ghi chú #2"
+	Title string
+	Time  time.Time
+	// Lines are the file lines before the change
+	Lines   []string
+	Section int
+	Cycles  []Cycle
+	Skipped map[string]bool
+
+	// Patches revert the change in the file lines, first to last, and
+	// Length is the number of lines right after it
+	Patches []linePatch
+	Length  int
+	// The skip marks and cycles the change added and removed
+	SkipAdded, SkipRemoved     []string
+	CyclesAdded, CyclesRemoved []Cycle
+}
+
+// linePatch reverts one change of the file lines: the New lines the
+// change wrote at line At go back to Old. Before and After are the
+// unchanged lines around them, which find them again once other edits
+// moved them.
+type linePatch struct {
+	At            int
+	Old, New      []string
+	Before, After []string
+}
+
+// Snapshot records the document and progress before the change title.
+func (a *App) Snapshot(title string) UndoEntry {
+	skipped := make(map[string]bool, len(a.Skipped))
+	for t := range a.Skipped {
+		skipped[t] = true
+	}
+	return UndoEntry{
+		Title:   title,
+		Time:    time.Now(),
+		Lines:   append([]string(nil), a.FileLines...),
+		Section: a.CurrentIdx,
+		Cycles:  append([]Cycle(nil), a.Cycles...),
+		Skipped: skipped,
+	}
+}
+
+// Restore puts the document and progress back as recorded in e.
+func (a *App) Restore(e UndoEntry) {
+	a.FileLines = append([]string(nil), e.Lines...)
+	a.FileContent = strings.Join(a.FileLines, "\n")
+	a.ParseSections()
+	a.CurrentIdx = max(0, min(e.Section, len(a.Sections)-1))
+	a.Cycles = append([]Cycle(nil), e.Cycles...)
+	a.Skipped = make(map[string]bool, len(e.Skipped))
+	for t := range e.Skipped {
+		a.Skipped[t] = true
+	}
+}
+
+// Inverse turns a snapshot taken before a change into the entry undoing
+// it, from the difference with the app now.
+func (a *App) Inverse(e UndoEntry) UndoEntry {
+	inv := UndoEntry{Title: e.Title, Time: e.Time, Section: e.Section}
+	inv.Patches, inv.Length = undoPatches(e.Lines, a.FileLines), len(a.FileLines)
+	for t := range a.Skipped {
+		if !e.Skipped[t] {
+			inv.SkipAdded = append(inv.SkipAdded, t)
+		}
+	}
+	for t := range e.Skipped {
+		if !a.Skipped[t] {
+			inv.SkipRemoved = append(inv.SkipRemoved, t)
+		}
+	}
+	inv.CyclesAdded = cyclesMissing(a.Cycles, e.Cycles)
+	inv.CyclesRemoved = cyclesMissing(e.Cycles, a.Cycles)
+	return inv
+}
+
+// cyclesMissing returns the cycles of from that are not in other.
+func cyclesMissing(from, other []Cycle) []Cycle {
+	var missing []Cycle
+	for _, c := range from {
+		found := false
+		for _, o := range other {
+			if o.Number == c.Number && o.Ended.Equal(c.Ended) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// undoPatches returns the patches turning after back into before.
+func undoPatches(before, after []string) []linePatch {
+	ops := diffLines(before, after)
+	var patches []linePatch
+	line, kept := 0, 0 // line of after, unchanged lines before it
+	for i := 0; i < len(ops); {
+		if ops[i].Kind == ' ' {
+			line, kept, i = line+1, kept+1, i+1
+			continue
+		}
+		p := linePatch{At: line}
+		p.Before = append([]string(nil), after[line-min(kept, undoContext):line]...)
+		for ; i < len(ops) && ops[i].Kind != ' '; i++ {
+			if ops[i].Kind == '-' {
+				p.Old = append(p.Old, ops[i].Text)
+			} else {
+				p.New = append(p.New, ops[i].Text)
+				line++
+			}
+		}
+		for k := i; k < len(ops) && k < i+undoContext && ops[k].Kind == ' '; k++ {
+			p.After = append(p.After, ops[k].Text)
+		}
+		patches = append(patches, p)
+		kept = 0
+	}
+	return patches
+}
+
+// locate returns where the lines p reverts are in lines[:limit], the
+// nearest to at, or -1 if they were changed since.
+func (p linePatch) locate(lines []string, at, limit int) int {
+	matches := func(pos int) bool {
+		start, end := pos-len(p.Before), pos+len(p.New)+len(p.After)
+		return start >= 0 && end <= limit &&
+			slices.Equal(lines[start:pos], p.Before) &&
+			slices.Equal(lines[pos:pos+len(p.New)], p.New) &&
+			slices.Equal(lines[pos+len(p.New):end], p.After)
+	}
+	for d := 0; at-d >= 0 || at+d <= limit; d++ {
+		if matches(at - d) {
+			return at - d
+		}
+		if d > 0 && matches(at+d) {
+			return at + d
+		}
+	}
+	return -1
+}
+
+// errUndoConflict is returned when a change cannot be undone because the
+// lines it changed were edited since.
+var errUndoConflict = errors.New("the lines this change made were edited since; undo it by hand")
+
+// Revert undoes the change of e on the current document and progress,
+// keeping the edits made since elsewhere. The patches are applied from
+// the last, so the earlier ones are found where other edits left them.
+func (a *App) Revert(e UndoEntry) error {
+	lines := append([]string(nil), a.FileLines...)
+	limit, shift := len(lines), len(lines)-e.Length
+	for n := len(e.Patches) - 1; n >= 0; n-- {
+		p := e.Patches[n]
+		pos := p.locate(lines, min(max(0, p.At+shift), limit), limit)
+		if pos < 0 {
+			return errUndoConflict
+		}
+		lines = slices.Replace(lines, pos, pos+len(p.New), p.Old...)
+		limit = pos
+	}
+	a.FileLines = lines
+	a.FileContent = strings.Join(a.FileLines, "\n")
+	a.ParseSections()
+	a.CurrentIdx = max(0, min(e.Section, len(a.Sections)-1))
+
+	for _, t := range e.SkipAdded {
+		delete(a.Skipped, t)
+	}
+	for _, t := range e.SkipRemoved {
+		if a.Skipped == nil {
+			a.Skipped = make(map[string]bool)
+		}
+		a.Skipped[t] = true
+	}
+	var cycles []Cycle
+	for _, c := range a.Cycles {
+		if len(cyclesMissing([]Cycle{c}, e.CyclesAdded)) > 0 {
+			cycles = append(cycles, c)
+		}
+	}
+	cycles = append(cycles, e.CyclesRemoved...)
+	sort.SliceStable(cycles, func(i, j int) bool { return cycles[i].Number < cycles[j].Number })
+	a.Cycles = cycles
+	return nil
+}
+
+// PushUndo adds a confirmed change to the undo stack, dropping the
+// oldest entries beyond maxUndo.
+func (a *App) PushUndo(e UndoEntry) {
+	a.Undo = append(a.Undo, e)
+	if len(a.Undo) > maxUndo {
+		a.Undo = a.Undo[len(a.Undo)-maxUndo:]
+	}
+}
+
+// PopUndo removes and returns the most recent undo entry.
+func (a *App) PopUndo() (UndoEntry, bool) {
+	if len(a.Undo) == 0 {
+		return UndoEntry{}, false
+	}
+	e := a.Undo[len(a.Undo)-1]
+	a.Undo = a.Undo[:len(a.Undo)-1]
+	return e, true
+}
+
+// showChangePreview prints the effect of a change on the file as a diff,
+// cut to what fits the screen.
+func showChangePreview(title string, before, after []string) {
+	ClearScreen()
+	fmt.Printf("%s⚠️  %s%s\n", Bold+Red, title, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+
+	ops := diffLines(before, after)
+	hunks := diffHunks(ops, diffContext)
+	if len(hunks) == 0 {
+		fmt.Printf("%sNội dung file không đổi.%s\n", Dim, Reset)
+		return
+	}
+	var b strings.Builder
+	writeDiff(&b, ops, hunks, nil, true)
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	rows := max(5, app.TermHeight-6)
+	if len(lines) > rows {
+		hidden := len(lines) - rows + 1
+		lines = append(lines[:rows-1], fmt.Sprintf("%s… còn %d dòng%s", Dim, hidden, Reset))
+	}
+	for _, line := range lines {
+		fmt.Println(truncateVisible(line, app.TermWidth))
+	}
+}
+
+// confirmChange finishes a destructive change already made in memory
+// after entry was taken: it previews the change, asks for confirmation
+// (typing word, or y/N when word is empty) and then saves it and
+// registers the undo entry, or restores entry when declined. Returns
+// whether the change was kept.
+func confirmChange(reader *bufio.Reader, entry UndoEntry, word string) bool {
+	showChangePreview(entry.Title, entry.Lines, app.FileLines)
+	if word == "" {
+		fmt.Printf("\n%sXác nhận? (y/N): %s", Yellow, Reset)
+	} else {
+		fmt.Printf("\n%sGõ \"%s\" để xác nhận: %s", Yellow, word, Reset)
+	}
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	confirmed := answer == word
+	if word == "" {
+		answer = strings.ToLower(answer)
+		confirmed = answer == "y" || answer == "yes"
+	}
+	if !confirmed {
+		app.Restore(entry)
+		notify(SeverityInfo, "Đã hủy, không có thay đổi")
+		return false
+	}
+
+	app.PushUndo(app.Inverse(entry))
+	app.SaveState(renderer.PageSize)
+	return saveReviewed(reader)
+}
+
+// runChange makes a destructive change as a transaction: apply changes
+// the document in memory, then confirmChange previews it and keeps or
+// rolls it back. A failing apply is rolled back and reported.
+func runChange(reader *bufio.Reader, title, word string, apply func() error) bool {
+	entry := app.Snapshot(title)
+	if err := apply(); err != nil {
+		app.Restore(entry)
+		notify(SeverityError, "%v", err)
+		return false
+	}
+	return confirmChange(reader, entry, word)
+}
+
+// handleUndo undoes the most recent confirmed change, after previewing
+// it; edits made since elsewhere in the document are kept. The undo is
+// itself a change, so undoing again redoes it.
+func handleUndo() {
+	entry, ok := app.PopUndo()
+	if !ok {
+		notify(SeverityInfo, "Không có thay đổi nào để hoàn tác")
+		return
+	}
+	terminal.SetRawMode(false)
+	defer terminal.SetRawMode(true)
+
+	title := "Hoàn tác: " + entry.Title
+	if strings.HasPrefix(entry.Title, "Hoàn tác: ") {
+		title = strings.TrimPrefix(entry.Title, "Hoàn tác: ")
+	}
+	pending := len(app.Undo)
+	if !runChange(bufio.NewReader(keyboard), title, "", func() error {
+		return app.Revert(entry)
+	}) {
+		// A declined undo stays available
+		if len(app.Undo) == pending {
+			app.PushUndo(entry)
+		}
+		return
+	}
+	renderer.ResetScroll()
+	notify(SeveritySuccess, "Đã hoàn tác \"%s\" (z u để làm lại)", entry.Title)
+}
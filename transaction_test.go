@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withTransactionApp sets up a test app saving to a temporary document.
+func withTransactionApp(t *testing.T) *App {
+	t.Helper()
+	a := withTestApp(t)
+	dir := t.TempDir()
+	a.FilePath = filepath.Join(dir, "doc.md")
+	a.StateFile = filepath.Join(dir, "state.json")
+	if err := os.WriteFile(a.FilePath, []byte(a.FileContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	saved := renderer
+	renderer = NewRenderer(a)
+	t.Cleanup(func() { renderer = saved })
+	return a
+}
+
+func deleteExercise() error {
+	app.DeleteSection(5)
+	return nil
+}
+
+func TestRunChangeConfirmed(t *testing.T) {
+	a := withTransactionApp(t)
+	before := len(a.Sections)
+
+	if !runChange(bufio.NewReader(strings.NewReader("y\n")), "Xóa Exercise 1", "", deleteExercise) {
+		t.Fatal("Expected the confirmed change to be kept")
+	}
+	if len(a.Sections) != before-1 {
+		t.Errorf("Expected %d sections, got %d", before-1, len(a.Sections))
+	}
+	data, _ := os.ReadFile(a.FilePath)
+	if strings.Contains(string(data), "Exercise 1") {
+		t.Error("Expected the change to be saved")
+	}
+	if len(a.Undo) != 1 || a.Undo[0].Title != "Xóa Exercise 1" {
+		t.Errorf("Undo = %+v", a.Undo)
+	}
+}
+
+func TestRunChangeDeclined(t *testing.T) {
+	a := withTransactionApp(t)
+	lines := strings.Join(a.FileLines, "\n")
+
+	for _, answer := range []string{"\n", "n\n", "yes please\n"} {
+		if runChange(bufio.NewReader(strings.NewReader(answer)), "Xóa Exercise 1", "", deleteExercise) {
+			t.Errorf("%q: expected the change to be rolled back", answer)
+		}
+		if strings.Join(a.FileLines, "\n") != lines || a.sectionIndex("Exercise 1") < 0 {
+			t.Errorf("%q: expected the document to be restored", answer)
+		}
+	}
+	if len(a.Undo) != 0 {
+		t.Errorf("Expected no undo entry, got %d", len(a.Undo))
+	}
+}
+
+func TestRunChangeConfirmWord(t *testing.T) {
+	a := withTransactionApp(t)
+	if runChange(bufio.NewReader(strings.NewReader("y\n")), "Vòng mới", "reset", deleteExercise) {
+		t.Error("Expected y not to confirm a change needing a word")
+	}
+	if !runChange(bufio.NewReader(strings.NewReader("reset\n")), "Vòng mới", "reset", deleteExercise) {
+		t.Error("Expected the word to confirm the change")
+	}
+	if a.sectionIndex("Exercise 1") >= 0 {
+		t.Error("Expected the section to be deleted")
+	}
+}
+
+func TestUndoRestores(t *testing.T) {
+	a := withTransactionApp(t)
+	a.Skipped = map[string]bool{"Exercise 1": true}
+	a.Cycles = []Cycle{{Number: 1}}
+	lines := strings.Join(a.FileLines, "\n")
+
+	entry := a.Snapshot("Xóa Exercise 1")
+	a.DeleteSection(5)
+	a.Cycles = append(a.Cycles, Cycle{Number: 2})
+	if a.Skipped["Exercise 1"] {
+		t.Fatal("Expected DeleteSection to drop the skip mark")
+	}
+
+	a.Restore(entry)
+	if strings.Join(a.FileLines, "\n") != lines || a.sectionIndex("Exercise 1") < 0 {
+		t.Error("Expected the document to be restored")
+	}
+	if !a.Skipped["Exercise 1"] || len(a.Cycles) != 1 {
+		t.Errorf("Expected the progress to be restored, got %v %+v", a.Skipped, a.Cycles)
+	}
+}
+
+func TestUndoStackLimit(t *testing.T) {
+	a := withTestApp(t)
+	for i := 0; i < maxUndo+5; i++ {
+		a.PushUndo(UndoEntry{Section: i})
+	}
+	if len(a.Undo) != maxUndo {
+		t.Fatalf("Expected %d entries, got %d", maxUndo, len(a.Undo))
+	}
+	e, ok := a.PopUndo()
+	if !ok || e.Section != maxUndo+4 {
+		t.Errorf("Expected the most recent entry, got %+v", e)
+	}
+	a.Undo = nil
+	if _, ok := a.PopUndo(); ok {
+		t.Error("Expected an empty stack")
+	}
+}
+
+func TestUndoKeepsLaterEdits(t *testing.T) {
+	a := withTransactionApp(t)
+	a.Skipped = map[string]bool{"Exercise 1": true}
+
+	if !runChange(bufio.NewReader(strings.NewReader("y\n")), "Xóa Exercise 1", "", deleteExercise) {
+		t.Fatal("Expected the change to be kept")
+	}
+	// Later edits, before and after the deleted section
+	a.CurrentIdx = 2
+	a.ToggleCheckbox(a.GetCheckboxLines()[0])
+	a.UpdateFileSection(2)
+	a.CurrentIdx = 4
+	a.AddNote("Ghi chú sau khi xóa")
+	a.UpdateFileSection(4)
+	a.Skipped["Chapter 2: Advanced"] = true
+
+	entry, _ := a.PopUndo()
+	if err := a.Revert(entry); err != nil {
+		t.Fatalf("Revert failed: %v", err)
+	}
+	if a.sectionIndex("Exercise 1") < 0 {
+		t.Error("Expected the deleted section back")
+	}
+	content := strings.Join(a.FileLines, "\n")
+	if !strings.Contains(content, "- [x] Task one") || !strings.Contains(content, "Ghi chú sau khi xóa") {
+		t.Errorf("Expected the later edits to be kept:\n%s", content)
+	}
+	if !a.Skipped["Exercise 1"] || !a.Skipped["Chapter 2: Advanced"] {
+		t.Errorf("Expected both skip marks, got %v", a.Skipped)
+	}
+}
+
+func TestUndoOlderChange(t *testing.T) {
+	a := withTransactionApp(t)
+	removeBold := func() error {
+		a.Sections[2].SetContent(strings.Replace(a.Sections[2].Content(), "**Bold text** and *italic text*.", "", 1))
+		a.UpdateFileSection(2)
+		return nil
+	}
+	reader := func() *bufio.Reader { return bufio.NewReader(strings.NewReader("y\n")) }
+	if !runChange(reader(), "Xóa dòng", "", removeBold) || !runChange(reader(), "Xóa Exercise 1", "", deleteExercise) {
+		t.Fatal("Expected both changes to be kept")
+	}
+	a.CurrentIdx = 3
+	a.ToggleCheckbox(a.GetCheckboxLines()[0])
+	a.UpdateFileSection(3)
+
+	// The older change, with a newer one and an edit made after it
+	if err := a.Revert(a.Undo[0]); err != nil {
+		t.Fatalf("Revert failed: %v", err)
+	}
+	content := strings.Join(a.FileLines, "\n")
+	if !strings.Contains(content, "**Bold text**") {
+		t.Error("Expected the older change undone")
+	}
+	if strings.Contains(content, "Exercise 1") || !strings.Contains(content, "- [x] Advanced task") {
+		t.Errorf("Expected the newer change and the edit kept:\n%s", content)
+	}
+}
+
+func TestUndoConflict(t *testing.T) {
+	a := withTransactionApp(t)
+	if !runChange(bufio.NewReader(strings.NewReader("y\n")), "Sửa dòng", "", func() error {
+		a.Sections[3].SetContent(strings.Replace(a.Sections[3].Content(), "More content.", "Less content.", 1))
+		a.UpdateFileSection(3)
+		return nil
+	}) {
+		t.Fatal("Expected the change to be kept")
+	}
+	a.Sections[3].SetContent(strings.Replace(a.Sections[3].Content(), "Less content.", "Other content.", 1))
+	a.UpdateFileSection(3)
+	before := strings.Join(a.FileLines, "\n")
+
+	if err := a.Revert(a.Undo[0]); !errors.Is(err, errUndoConflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+	if strings.Join(a.FileLines, "\n") != before {
+		t.Error("Expected a conflicting undo to leave the document alone")
+	}
+}
+
+func TestUndoPatches(t *testing.T) {
+	before := []string{"a", "b", "c", "d", "e", "f", "g"}
+	after := []string{"a", "B", "c", "d", "f", "g", "h"}
+	patches := undoPatches(before, after)
+	if len(patches) != 3 {
+		t.Fatalf("Expected 3 patches, got %+v", patches)
+	}
+	// Moved by two lines inserted at the top
+	lines := append([]string{"x", "y"}, after...)
+	a := &App{FileLines: lines}
+	if err := a.Revert(UndoEntry{Patches: patches, Length: len(after)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(a.FileLines, ","), "x,y,"+strings.Join(before, ","); got != want {
+		t.Errorf("Revert = %s, want %s", got, want)
+	}
+}
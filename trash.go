@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrashedNote is a deleted note kept for restoring.
+type TrashedNote struct {
+	// Section is the title path of the section the note was in
+	Section []string  `json:"section"`
+	Note    string    `json:"note"`
+	Deleted time.Time `json:"deleted"`
+}
+
+// Trash holds the deleted notes of a document. It is stored next to the
+// document's state (see trashPath), so it never ends up in the markdown.
+type Trash struct {
+	Notes []TrashedNote `json:"notes"`
+}
+
+// trashPath returns the trash file of a document.
+func trashPath(docPath string) (string, error) {
+	path, err := documentStateFile(docPath)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(path, ".json") + ".trash.json", nil
+}
+
+// LoadTrash reads the trash from path. A missing file yields an empty
+// trash.
+func LoadTrash(path string) (*Trash, error) {
+	t := &Trash{}
+	data, err := readSecure(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read trash %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("cannot parse trash %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Save writes the trash to path.
+func (t *Trash) Save(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeSecure(path, data, 0o600)
+}
+
+// Purge drops notes deleted more than retention ago; zero keeps them
+// forever. Returns the number of notes dropped.
+func (t *Trash) Purge(now time.Time, retention time.Duration) int {
+	if retention <= 0 {
+		return 0
+	}
+	kept := t.Notes[:0]
+	for _, n := range t.Notes {
+		if now.Sub(n.Deleted) <= retention {
+			kept = append(kept, n)
+		}
+	}
+	purged := len(t.Notes) - len(kept)
+	t.Notes = kept
+	return purged
+}
+
+// sectionPath returns the titles from the top-level ancestor down to
+// section idx.
+func (a *App) sectionPath(idx int) []string {
+	var path []string
+	for _, i := range a.Ancestors(idx) {
+		path = append(path, a.Sections[i].Title)
+	}
+	return append(path, a.Sections[idx].Title)
+}
+
+// loadTrash reads the document's trash, purging notes older than the
+// configured retention.
+func (a *App) loadTrash() (*Trash, string, error) {
+	path, err := trashPath(a.FilePath)
+	if err != nil {
+		return nil, "", err
+	}
+	t, err := LoadTrash(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if t.Purge(time.Now(), config.TrashRetention) > 0 {
+		if err := t.Save(path); err != nil {
+			return nil, "", err
+		}
+	}
+	return t, path, nil
+}
+
+// TrashNotes moves deleted notes of section idx to the trash.
+func (a *App) TrashNotes(idx int, notes []string) error {
+	t, path, err := a.loadTrash()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, note := range notes {
+		t.Notes = append(t.Notes, TrashedNote{Section: a.sectionPath(idx), Note: note, Deleted: now})
+	}
+	logger.Info("trashed notes", "section", a.Sections[idx].Title, "notes", len(notes))
+	return t.Save(path)
+}
+
+// RestoreNote puts trashed note n back at the end of its section, or of
+// the current section if its section no longer exists, and removes it
+// from the trash. The caller saves the file.
+func (a *App) RestoreNote(t *Trash, n int) (section int) {
+	note := t.Notes[n]
+	section = a.sectionIndex(note.Section[len(note.Section)-1])
+	if section < 0 {
+		section = a.CurrentIdx
+	}
+	sec := &a.Sections[section]
+	logger.Info("restored note", "section", sec.Title, "deleted", note.Deleted)
+	sec.SetContent(strings.TrimRight(sec.Content(), "\n") + "\n\n" + note.Note)
+	a.UpdateFileSection(section)
+	t.Notes = append(t.Notes[:n], t.Notes[n+1:]...)
+	return section
+}
+
+// restoreNote lists the trashed notes, newest first, and restores the
+// chosen one.
+func restoreNote(reader *bufio.Reader) bool {
+	t, path, err := app.loadTrash()
+	if err != nil {
+		notify(SeverityError, "Lỗi đọc thùng rác: %v", err)
+		return false
+	}
+	if len(t.Notes) == 0 {
+		notify(SeverityInfo, "Thùng rác trống")
+		return false
+	}
+
+	ClearScreen()
+	fmt.Printf("%s♻️ KHÔI PHỤC GHI CHÚ ĐÃ XÓA%s\n", Bold+Cyan, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	fmt.Println()
+	for i := len(t.Notes) - 1; i >= 0; i-- {
+		n := t.Notes[i]
+		text := strings.ReplaceAll(noteBody(n.Note), "\n", " ")
+		fmt.Printf("  %s%d%s. %s%s · xóa %s%s\n     %s\n", Cyan, len(t.Notes)-i, Reset,
+			Dim, strings.Join(n.Section, " "+glyphs.Crumb+" "), n.Deleted.Format("2006-01-02 15:04"), Reset,
+			truncateEllipsis(text, 100))
+	}
+	if config.TrashRetention > 0 {
+		fmt.Printf("\n%sGhi chú trong thùng rác tự xóa sau %d ngày.%s\n", Dim, int(config.TrashRetention.Hours()/24), Reset)
+	}
+
+	fmt.Printf("\nNhập số để khôi phục (1-%d) hoặc Enter để hủy: ", len(t.Notes))
+	input, _ := reader.ReadString('\n')
+	num, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || num < 1 || num > len(t.Notes) {
+		return false
+	}
+
+	section := app.RestoreNote(t, len(t.Notes)-num)
+	if !saveReviewed(reader) {
+		return false
+	}
+	if err := t.Save(path); err != nil {
+		notify(SeverityError, "Lỗi lưu thùng rác: %v", err)
+	}
+	notify(SeveritySuccess, "Đã khôi phục ghi chú vào %s", app.Sections[section].Title)
+	return true
+}
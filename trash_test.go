@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrashRestoreNote(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	a := createTestApp()
+	a.FilePath = t.TempDir() + "/path.md"
+	a.CurrentIdx = 3
+	a.AddNote("nhớ ôn lại")
+	a.UpdateFileSection(3)
+	a.ParseSections()
+
+	note := extractNotes(a.Sections[3].Content())[0]
+	a.Sections[3].SetContent(removeNoteFromContent(a.Sections[3].Content(), note))
+	a.UpdateFileSection(3)
+	a.ParseSections()
+	if err := a.TrashNotes(3, []string{note}); err != nil {
+		t.Fatal(err)
+	}
+
+	trash, _, err := a.loadTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash.Notes) != 1 || strings.Join(trash.Notes[0].Section, "/") != "Main Title/Giai đoạn 1: Learning/Chapter 2: Advanced" {
+		t.Fatalf("trash = %+v", trash.Notes)
+	}
+
+	// Restored into its section even when another one is open
+	a.CurrentIdx = 0
+	if idx := a.RestoreNote(trash, 0); idx != 3 {
+		t.Errorf("restored into section %d, want 3", idx)
+	}
+	if notes := extractNotes(a.Sections[3].Content()); len(notes) != 1 || notes[0] != note {
+		t.Errorf("notes after restore = %q", notes)
+	}
+	if len(trash.Notes) != 0 {
+		t.Errorf("restored note should leave the trash")
+	}
+}
+
+func TestTrashPurge(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	trash := &Trash{Notes: []TrashedNote{
+		{Note: "old", Deleted: now.AddDate(0, 0, -40)},
+		{Note: "new", Deleted: now.AddDate(0, 0, -2)},
+	}}
+
+	if n := trash.Purge(now, 0); n != 0 {
+		t.Errorf("retention 0 should keep everything, purged %d", n)
+	}
+	if n := trash.Purge(now, 30*24*time.Hour); n != 1 || trash.Notes[0].Note != "new" {
+		t.Errorf("purged %d, left %+v", n, trash.Notes)
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// ttsBackend is a local speech synthesizer reading the text on stdin.
+type ttsBackend struct {
+	name string
+	args []string
+}
+
+// ttsBackends lists the local synthesizers in order of preference.
+var ttsBackends = []ttsBackend{
+	{"say", []string{"-f", "-"}},
+	{"espeak-ng", []string{"-v", "vi", "--stdin"}},
+	{"espeak", []string{"-v", "vi", "--stdin"}},
+	{"festival", []string{"--tts"}},
+}
+
+// audioPlayers play the audio returned by an HTTP TTS API from stdin.
+var audioPlayers = []ttsBackend{
+	{"mpv", []string{"--no-video", "--really-quiet", "-"}},
+	{"ffplay", []string{"-nodisp", "-autoexit", "-loglevel", "quiet", "-"}},
+	{"aplay", []string{"-q", "-"}},
+}
+
+// Speaker speaks text and returns when it is done or ctx is canceled.
+type Speaker func(ctx context.Context, text string) error
+
+// commandSpeaker speaks with a synthesizer reading stdin.
+func commandSpeaker(b ttsBackend) Speaker {
+	return func(ctx context.Context, text string) error {
+		cmd := exec.CommandContext(ctx, b.name, b.args...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+}
+
+// httpSpeaker posts the text to a TTS API and pipes the audio it returns
+// to a player.
+func httpSpeaker(url string, player ttsBackend) Speaker {
+	return func(ctx context.Context, text string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(text))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+			return fmt.Errorf("POST %s: %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+		}
+
+		cmd := exec.CommandContext(ctx, player.name, player.args...)
+		cmd.Stdin = resp.Body
+		return cmd.Run()
+	}
+}
+
+// firstInstalled returns the first tool found in PATH.
+func firstInstalled(tools []ttsBackend) (ttsBackend, bool) {
+	for _, t := range tools {
+		if _, err := exec.LookPath(t.name); err == nil {
+			return t, true
+		}
+	}
+	return ttsBackend{}, false
+}
+
+// NewSpeaker picks the TTS backend from the config: the HTTP API if
+// tts_url is set, otherwise tts_engine or the first installed synthesizer.
+// Returns the backend name.
+func NewSpeaker(cfg Config) (string, Speaker, error) {
+	if cfg.TTSURL != "" {
+		player, ok := firstInstalled(audioPlayers)
+		if !ok {
+			return "", nil, errors.New("no audio player found (install mpv, ffplay or aplay)")
+		}
+		return "http+" + player.name, httpSpeaker(cfg.TTSURL, player), nil
+	}
+
+	candidates := ttsBackends
+	if cfg.TTSEngine != "" && cfg.TTSEngine != "auto" {
+		candidates = nil
+		for _, b := range ttsBackends {
+			if b.name == cfg.TTSEngine {
+				candidates = []ttsBackend{b}
+			}
+		}
+	}
+	b, ok := firstInstalled(candidates)
+	if !ok {
+		return "", nil, errors.New("no speech synthesizer found (install espeak-ng, or set tts_url)")
+	}
+	return b.name, commandSpeaker(b), nil
+}
+
+// ttsEngineNames returns the accepted values of tts_engine.
+func ttsEngineNames() []string {
+	names := []string{"auto"}
+	for _, b := range ttsBackends {
+		names = append(names, b.name)
+	}
+	return names
+}
+
+// speechMarkup matches inline markdown that should not be read aloud.
+var speechMarkup = strings.NewReplacer("**", "", "__", "", "`", "", "*", "")
+
+// speechLinkRegex matches markdown links, read as their text.
+var speechLinkRegex = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+
+// speechText strips inline markdown from a line.
+func speechText(line string) string {
+	line = speechLinkRegex.ReplaceAllString(line, "$1")
+	return strings.TrimSpace(speechMarkup.Replace(line))
+}
+
+// SpeechParagraphs splits a section into the paragraphs read aloud: the
+// title, then text paragraphs, list items and notes. Code blocks and
+// tables are skipped.
+func SpeechParagraphs(sec *Section) []string {
+	paragraphs := []string{speechText(sec.Title)}
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = nil
+		}
+	}
+
+	inCode := false
+	for _, line := range strings.Split(sec.Content(), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			inCode = !inCode
+		case inCode || strings.HasPrefix(trimmed, "|") || trimmed == "---":
+		case trimmed == "":
+			flush()
+		case isCheckbox(line):
+			flush()
+			current = append(current, speechText(checkboxText(line)))
+			flush()
+		case strings.HasPrefix(trimmed, "#"), strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+			flush()
+			current = append(current, speechText(strings.TrimLeft(trimmed, "#-* ")))
+			flush()
+		default:
+			if text := speechText(strings.TrimLeft(trimmed, "> ")); text != "" {
+				current = append(current, text)
+			}
+		}
+	}
+	flush()
+	return paragraphs
+}
+
+// readAloud is the state of the read-aloud mode.
+type readAloud struct {
+	speak      Speaker
+	paragraphs []string
+	para       int
+	paused     bool
+	// cancel stops the paragraph being spoken; done receives its result
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// start speaks the current paragraph in the background.
+func (r *readAloud) start() {
+	r.stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	text := r.paragraphs[r.para]
+	go func() { done <- r.speak(ctx, text) }()
+	r.cancel, r.done = cancel, done
+}
+
+// stop interrupts the paragraph being spoken, if any.
+func (r *readAloud) stop() {
+	if r.cancel != nil {
+		r.cancel()
+		<-r.done
+	}
+	r.cancel, r.done = nil, nil
+}
+
+// loadSection reads the current section from its first paragraph.
+func (r *readAloud) loadSection() {
+	r.paragraphs = SpeechParagraphs(app.GetCurrentSection())
+	r.para = 0
+}
+
+// advance moves to the next paragraph, continuing with the next section
+// at the end of one. Returns false at the end of the document.
+func (r *readAloud) advance() bool {
+	if r.para+1 < len(r.paragraphs) {
+		r.para++
+		return true
+	}
+	if !app.NextSection() {
+		return false
+	}
+	r.loadSection()
+	return true
+}
+
+// render draws the read-aloud screen.
+func (r *readAloud) render(backend string) {
+	ClearScreen()
+	sec := app.GetCurrentSection()
+	fmt.Printf("%s%s🔊 Đọc to%s %s(%s)%s\n\n", Bold, Cyan, Reset, Dim, backend, Reset)
+	fmt.Printf("%s%s%s\n", Bold, sec.Title, Reset)
+	state := "▶ đang đọc"
+	if r.paused {
+		state = "⏸ tạm dừng"
+	}
+	fmt.Printf("%sĐoạn %d/%d · Section %d/%d · %s%s\n\n", Dim, r.para+1, len(r.paragraphs), app.CurrentIdx+1, len(app.Sections), state, Reset)
+	for _, line := range wrapVisible(r.paragraphs[r.para], min(app.TermWidth, 100)) {
+		fmt.Println(line)
+	}
+	fmt.Printf("\n%sSpace: dừng/tiếp · n/→: đoạn sau · p/←: đoạn trước · N/P: section sau/trước · q: thoát%s\n", Dim, Reset)
+	printStatusLine()
+}
+
+// handleReadAloud runs the read-aloud mode: the current section is
+// spoken paragraph by paragraph, moving on to the following sections.
+func handleReadAloud() {
+	backend, speak, err := NewSpeaker(config)
+	if err != nil {
+		notify(SeverityError, "Không đọc to được: %v", err)
+		return
+	}
+	if app.GetCurrentSection() == nil {
+		return
+	}
+
+	r := &readAloud{speak: speak}
+	r.loadSection()
+	r.start()
+	defer r.stop()
+
+	// Poll the keyboard so the next paragraph starts without a key press
+	terminal.SetReadTimeout(true)
+	defer terminal.SetReadTimeout(false)
+
+	for {
+		r.render(backend)
+
+		switch key := readKey(); key {
+		case "":
+			if r.done == nil {
+				continue
+			}
+			select {
+			case err := <-r.done:
+				r.cancel, r.done = nil, nil
+				if err != nil {
+					notify(SeverityError, "Lỗi TTS (%s): %v", backend, err)
+					r.paused = true
+				} else if r.advance() {
+					r.start()
+				} else {
+					notify(SeverityInfo, "Đã đọc hết tài liệu")
+					r.paused = true
+				}
+			default:
+			}
+		case "Space":
+			r.paused = !r.paused
+			if r.paused {
+				r.stop()
+			} else {
+				r.start()
+			}
+		case "n", "Right":
+			if r.advance() && !r.paused {
+				r.start()
+			}
+		case "p", "Left":
+			if r.para > 0 {
+				r.para--
+			}
+			if !r.paused {
+				r.start()
+			}
+		case "N", "P":
+			moved := app.NextSection()
+			if key == "P" {
+				moved = app.PrevSection()
+			}
+			if moved {
+				r.stop()
+				r.loadSection()
+				if !r.paused {
+					r.start()
+				}
+			}
+		case "q", "Q", "Esc":
+			renderer.ResetScroll()
+			return
+		}
+	}
+}
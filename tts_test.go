@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSpeechParagraphs(t *testing.T) {
+	sec := &Section{
+		Title:   "Chapter 1: **Basics**",
+		content: "Read the [docs](https://k8s.io) first\nand take `notes`.\n\n- [x] Task one\n- Plain item\n\n```bash\nkubectl get pods\n```\n| a | b |\n> **Ghi chú [2024-01-02 15:04]:** ok\n",
+	}
+	want := []string{
+		"Chapter 1: Basics",
+		"Read the docs first and take notes.",
+		"Task one",
+		"Plain item",
+		"Ghi chú [2024-01-02 15:04]: ok",
+	}
+	if got := SpeechParagraphs(sec); !reflect.DeepEqual(got, want) {
+		t.Errorf("SpeechParagraphs =\n%q\nwant\n%q", got, want)
+	}
+}
+
+// fakeTool installs an executable script in a temporary PATH.
+func fakeTool(t *testing.T, name, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	return dir
+}
+
+func TestNewSpeakerCommand(t *testing.T) {
+	dir := fakeTool(t, "espeak-ng", "IFS= read -r l; echo \"$l\" > \"${0%/*}/spoken\"\n")
+
+	name, speak, err := NewSpeaker(DefaultConfig())
+	if err != nil || name != "espeak-ng" {
+		t.Fatalf("NewSpeaker = %q, %v", name, err)
+	}
+	if err := speak(context.Background(), "xin chào"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "spoken")); string(data) != "xin chào\n" {
+		t.Errorf("synthesizer got %q", data)
+	}
+
+	cfg := DefaultConfig()
+	cfg.TTSEngine = "festival"
+	if _, _, err := NewSpeaker(cfg); err == nil {
+		t.Error("expected error for an engine that is not installed")
+	}
+}
+
+func TestHTTPSpeaker(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Write([]byte("RIFF"))
+	}))
+	defer srv.Close()
+	dir := fakeTool(t, "mpv", "IFS= read -r l; echo \"$l\" > \"${0%/*}/played\"\n")
+
+	cfg := DefaultConfig()
+	cfg.TTSURL = srv.URL
+	name, speak, err := NewSpeaker(cfg)
+	if err != nil || name != "http+mpv" {
+		t.Fatalf("NewSpeaker = %q, %v", name, err)
+	}
+	if err := speak(context.Background(), "đoạn một"); err != nil {
+		t.Fatal(err)
+	}
+	if body != "đoạn một" {
+		t.Errorf("API got %q", body)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "played")); string(data) != "RIFF\n" {
+		t.Errorf("player got %q", data)
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// MergeReport summarizes an upstream merge.
+type MergeReport struct {
+	NewSections int
+	NewItems    int
+	// Checked is the number of check states carried over
+	Checked int
+	Notes   int
+	// Moved and Removed describe the items and sections that needed a
+	// decision, with what was done
+	Moved   []string
+	Removed []string
+}
+
+// Resolver asks the user about a moved or removed item; true keeps the
+// local state (the check, or the item itself).
+type Resolver func(question string) bool
+
+// localItem is a checklist item of the annotated copy.
+type localItem struct {
+	Section string
+	Line    string
+	Checked bool
+}
+
+// appendBlock adds a block (a note, kept items) at the end of section
+// content, separated by a blank line.
+func appendBlock(content []string, block string) []string {
+	for len(content) > 0 && strings.TrimSpace(content[len(content)-1]) == "" {
+		content = content[:len(content)-1]
+	}
+	return append(content, "", block, "")
+}
+
+// MergeCurriculum merges the structure of upstream (new sections, new and
+// reworded checklists) into the annotated local copy and returns the new
+// file lines. Sections are matched by title; check states, personal
+// tasks and notes of the local copy are carried over. Items checked in
+// a section but found in another one upstream, items removed upstream
+// and removed sections holding progress or notes are put to resolve.
+func MergeCurriculum(local, upstream *App, resolve Resolver) ([]string, MergeReport) {
+	var report MergeReport
+
+	localSections := make(map[string]int)
+	items := make(map[string][]localItem)
+	for i, sec := range local.Sections {
+		localSections[sec.Title] = i
+		for _, line := range strings.Split(sec.Content(), "\n") {
+			if isCheckbox(line) && !isPersonalTask(line) {
+				text := checkboxText(line)
+				items[text] = append(items[text], localItem{sec.Title, line, isChecked(line)})
+			}
+		}
+	}
+	upstreamItems := make(map[string]bool)
+	for _, sec := range upstream.Sections {
+		for _, line := range strings.Split(sec.Content(), "\n") {
+			if isCheckbox(line) {
+				upstreamItems[checkboxText(line)] = true
+			}
+		}
+	}
+
+	var out []string
+	if len(upstream.Sections) > 0 {
+		out = append(out, upstream.FileLines[:upstream.Sections[0].Line]...)
+	} else {
+		out = append(out, upstream.FileLines...)
+	}
+
+	for _, up := range upstream.Sections {
+		li, exists := localSections[up.Title]
+		if !exists {
+			report.NewSections++
+		}
+
+		var content []string
+		for _, line := range strings.Split(up.Content(), "\n") {
+			if !isCheckbox(line) {
+				content = append(content, line)
+				continue
+			}
+			text := checkboxText(line)
+			known := items[text]
+			checked, moved := false, ""
+			for _, it := range known {
+				if it.Section == up.Title {
+					checked, moved = it.Checked, ""
+					break
+				}
+				if it.Checked {
+					moved = it.Section
+				}
+			}
+			if len(known) == 0 {
+				report.NewItems++
+			}
+			if moved != "" {
+				keep := resolve(fmt.Sprintf("%q đã chuyển từ %q sang %q. Giữ trạng thái đã xong?", text, moved, up.Title))
+				report.Moved = append(report.Moved, fmt.Sprintf("%s: %s → %s (%s)", text, moved, up.Title, keptLabel(keep)))
+				checked = keep
+			}
+			if checked {
+				line = setCheckbox(line, true)
+				report.Checked++
+			}
+			content = append(content, line)
+		}
+
+		if exists {
+			sec := local.Sections[li]
+			var extra []string
+			for _, line := range strings.Split(sec.Content(), "\n") {
+				if isPersonalTask(line) {
+					extra = append(extra, line)
+					continue
+				}
+				if !isCheckbox(line) || upstreamItems[checkboxText(line)] {
+					continue
+				}
+				text := checkboxText(line)
+				keep := resolve(fmt.Sprintf("%q (trong %q) đã bị xóa ở bản mới. Giữ lại trong bản của bạn?", text, sec.Title))
+				report.Removed = append(report.Removed, fmt.Sprintf("%s: %s (%s)", sec.Title, text, keptLabel(keep)))
+				if keep {
+					extra = append(extra, line)
+				}
+			}
+			if len(extra) > 0 {
+				content = appendBlock(content, strings.Join(extra, "\n"))
+			}
+			for _, note := range extractNotes(sec.Content()) {
+				content = appendBlock(content, note)
+				report.Notes++
+			}
+		}
+
+		out = append(out, up.HeaderLines()...)
+		out = append(out, content...)
+	}
+
+	// Sections removed upstream are kept at the end if the user wants
+	// their progress or notes
+	upstreamSections := make(map[string]bool)
+	for _, sec := range upstream.Sections {
+		upstreamSections[sec.Title] = true
+	}
+	for i, sec := range local.Sections {
+		if upstreamSections[sec.Title] {
+			continue
+		}
+		checked, _ := local.GetProgress(i)
+		notes := len(extractNotes(sec.Content()))
+		if checked == 0 && notes == 0 {
+			report.Removed = append(report.Removed, sec.Title+" (section, không có tiến độ)")
+			continue
+		}
+		keep := resolve(fmt.Sprintf("Section %q (%d mục đã xong, %d ghi chú) đã bị xóa ở bản mới. Giữ lại ở cuối file?", sec.Title, checked, notes))
+		report.Removed = append(report.Removed, fmt.Sprintf("%s (section, %s)", sec.Title, keptLabel(keep)))
+		if keep {
+			if n := len(out); n > 0 && strings.TrimSpace(out[n-1]) != "" {
+				out = append(out, "")
+			}
+			out = append(out, sec.HeaderLines()...)
+			out = append(out, strings.Split(sec.Content(), "\n")...)
+			report.Notes += notes
+		}
+	}
+	return out, report
+}
+
+// keptLabel describes a resolution in the report.
+func keptLabel(keep bool) string {
+	if keep {
+		return "giữ"
+	}
+	return "bỏ"
+}
+
+// readCurriculum reads a curriculum from a URL or a file.
+func readCurriculum(from string) ([]byte, error) {
+	if strings.HasPrefix(from, "https://") || strings.HasPrefix(from, "http://") {
+		return fetch(from)
+	}
+	return os.ReadFile(from)
+}
+
+// stdinResolver asks on out and reads y/n answers from in. Anything but
+// "n" keeps the local state, so Enter is the safe choice.
+func stdinResolver(in io.Reader, out io.Writer) Resolver {
+	reader := bufio.NewReader(in)
+	return func(question string) bool {
+		fmt.Fprintf(out, "%s (Y/n): ", question)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer != "n" && answer != "no"
+	}
+}
+
+// runUpdate implements "update --from <url|path> [--dry-run] [--yes]".
+// The previous file is kept as <file>.bak. The document is locked while
+// it is rewritten, and the update refused if a viewer has it open.
+func runUpdate(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	from := fs.String("from", "", "URL or path of the new curriculum version")
+	dryRun := fs.Bool("dry-run", false, "print the merged document instead of writing it")
+	yes := fs.Bool("yes", false, "keep the local state of moved and removed items without asking")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" {
+		return fmt.Errorf("usage: sre-learn update --from <url|path> [--dry-run] [--yes]")
+	}
+
+	data, err := readCurriculum(*from)
+	if err != nil {
+		return err
+	}
+	upstream := NewApp()
+	upstream.FileLines = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	upstream.ParseSections()
+	if len(upstream.Sections) == 0 {
+		return fmt.Errorf("%s has no sections", *from)
+	}
+
+	// Merging into a copy another instance keeps saving would lose its
+	// edits or ours, so the update waits until it is closed
+	if !*dryRun {
+		lock, holder, err := AcquireLock(app.FilePath, time.Now())
+		if err != nil {
+			return err
+		}
+		if holder != nil {
+			return fmt.Errorf("%s is open in another instance (%s); close it before updating", app.FilePath, holder)
+		}
+		defer lock.Release()
+		if app.ChangedOnDisk() {
+			if err := app.Reload(); err != nil {
+				return err
+			}
+		}
+	}
+
+	resolve := stdinResolver(os.Stdin, out)
+	if *yes {
+		resolve = func(string) bool { return true }
+	}
+	lines, report := MergeCurriculum(app, upstream, resolve)
+
+	if *dryRun {
+		fmt.Fprintln(out, strings.Join(lines, "\n"))
+		return nil
+	}
+	backup, err := encodeDocument(app.FileLines)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(app.FilePath+".bak", []byte(backup), 0o644); err != nil {
+		return fmt.Errorf("cannot write backup: %w", err)
+	}
+	app.FileLines = lines
+	if err := app.SaveFile(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Đã cập nhật %s (bản cũ: %s.bak)\n", app.FilePath, app.FilePath)
+	fmt.Fprintf(out, "  %d section mới, %d mục mới, giữ %d mục đã xong và %d ghi chú\n", report.NewSections, report.NewItems, report.Checked, report.Notes)
+	for _, m := range report.Moved {
+		fmt.Fprintf(out, "  chuyển: %s\n", m)
+	}
+	for _, r := range report.Removed {
+		fmt.Fprintf(out, "  xóa: %s\n", r)
+	}
+	return nil
+}
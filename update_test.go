@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseMarkdown returns an app holding content.
+func parseMarkdown(content string) *App {
+	a := NewApp()
+	a.FileContent = content
+	a.FileLines = strings.Split(content, "\n")
+	a.ParseSections()
+	return a
+}
+
+const localCurriculum = `# Path
+
+## Basics
+
+- [x] Install kubectl
+- [ ] Read the docs
+- [x] Learn YAML
+- [ ] 🧑 My own task
+
+> **Ghi chú [2025-01-01 10:00]:** kubectl ok
+
+## Old
+
+- [x] Legacy tool
+`
+
+const upstreamCurriculum = `<!-- v2 -->
+# Path
+
+## Basics
+
+- [ ] Install kubectl
+- [ ] Read the docs
+- [ ] Write a Pod
+
+## Advanced
+
+- [ ] Learn YAML
+- [ ] Helm charts
+`
+
+func TestMergeCurriculum(t *testing.T) {
+	local, upstream := parseMarkdown(localCurriculum), parseMarkdown(upstreamCurriculum)
+	var questions []string
+	lines, report := MergeCurriculum(local, upstream, func(q string) bool {
+		questions = append(questions, q)
+		return !strings.Contains(q, "Old")
+	})
+	merged := parseMarkdown(strings.Join(lines, "\n"))
+
+	if merged.FileLines[0] != "<!-- v2 -->" {
+		t.Errorf("Expected the upstream preamble, got %q", merged.FileLines[0])
+	}
+	basics := merged.Sections[merged.sectionIndex("Basics")].Content()
+	for _, want := range []string{"- [x] Install kubectl", "- [ ] Read the docs", "- [ ] Write a Pod", "🧑 My own task", "kubectl ok"} {
+		if !strings.Contains(basics, want) {
+			t.Errorf("Basics lacks %q:\n%s", want, basics)
+		}
+	}
+	// Learn YAML moved to Advanced and the resolver kept it checked
+	if adv := merged.Sections[merged.sectionIndex("Advanced")].Content(); !strings.Contains(adv, "- [x] Learn YAML") {
+		t.Errorf("Advanced = %q", adv)
+	}
+	// Old was removed upstream and the resolver dropped it
+	if merged.sectionIndex("Old") >= 0 {
+		t.Error("Expected the removed section to be dropped")
+	}
+	if len(questions) != 2 || len(report.Moved) != 1 || len(report.Removed) != 1 {
+		t.Errorf("questions = %q, report = %+v", questions, report)
+	}
+	if report.NewSections != 1 || report.NewItems != 2 || report.Checked != 2 || report.Notes != 1 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestRunUpdate(t *testing.T) {
+	app := withTestApp(t)
+	dir := t.TempDir()
+	app.FilePath = filepath.Join(dir, "path.md")
+	app.FileContent = localCurriculum
+	app.FileLines = strings.Split(localCurriculum, "\n")
+	app.ParseSections()
+	if err := os.WriteFile(app.FilePath, []byte(localCurriculum), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	upstream := filepath.Join(dir, "upstream.md")
+	if err := os.WriteFile(upstream, []byte(upstreamCurriculum), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runUpdate([]string{"--from", upstream, "--yes"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(app.FilePath + ".bak")
+	if err != nil || string(backup) != localCurriculum {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+	data, _ := os.ReadFile(app.FilePath)
+	// --yes keeps the removed section with its progress
+	if !strings.Contains(string(data), "- [ ] Helm charts") || !strings.Contains(string(data), "- [x] Legacy tool") {
+		t.Errorf("file = %q", data)
+	}
+	if !strings.Contains(out.String(), "1 section mới") {
+		t.Errorf("out = %q", out.String())
+	}
+
+	if err := runUpdate(nil, &out); err == nil {
+		t.Error("Expected an error without --from")
+	}
+}
+
+func TestRunUpdateRefusesLockedDocument(t *testing.T) {
+	app := withTestApp(t)
+	dir := t.TempDir()
+	app.FilePath = filepath.Join(dir, "path.md")
+	app.FileContent = localCurriculum
+	app.FileLines = strings.Split(localCurriculum, "\n")
+	app.ParseSections()
+	os.WriteFile(app.FilePath, []byte(localCurriculum), 0o644)
+	upstream := filepath.Join(dir, "upstream.md")
+	os.WriteFile(upstream, []byte(upstreamCurriculum), 0o644)
+
+	// A viewer (this process, so alive) has the document open
+	lock, _, err := AcquireLock(app.FilePath, time.Now())
+	if err != nil || lock == nil {
+		t.Fatalf("AcquireLock = %v, %v", lock, err)
+	}
+	defer lock.Release()
+
+	var out bytes.Buffer
+	if err := runUpdate([]string{"--from", upstream, "--yes"}, &out); err == nil {
+		t.Error("Expected the update to be refused while the document is locked")
+	}
+	if data, _ := os.ReadFile(app.FilePath); string(data) != localCurriculum {
+		t.Errorf("Expected the locked document to be left alone, got %q", data)
+	}
+
+	// Dry runs do not write and are allowed
+	if err := runUpdate([]string{"--from", upstream, "--yes", "--dry-run"}, &out); err != nil {
+		t.Errorf("Expected a dry run despite the lock, got %v", err)
+	}
+}
+
+func TestStdinResolver(t *testing.T) {
+	var out bytes.Buffer
+	resolve := stdinResolver(strings.NewReader("n\n\n"), &out)
+	if resolve("a?") || !resolve("b?") || !resolve("c?") {
+		t.Error("Expected n to drop and Enter or EOF to keep")
+	}
+	if !strings.Contains(out.String(), "a? (Y/n)") {
+		t.Errorf("out = %q", out.String())
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// audioRecorder is a command that records the default microphone into a
+// file until it is interrupted.
+type audioRecorder struct {
+	name string
+	args func(out string) []string
+}
+
+// audioRecorders lists the recorders in order of preference.
+var audioRecorders = []audioRecorder{
+	{"arecord", func(out string) []string { return []string{"-q", "-f", "cd", "-t", "wav", out} }},
+	{"sox", func(out string) []string { return []string{"-q", "-d", out} }},
+	{"ffmpeg", func(out string) []string {
+		input := []string{"-f", "alsa", "-i", "default"}
+		if runtime.GOOS == "darwin" {
+			input = []string{"-f", "avfoundation", "-i", ":0"}
+		}
+		return append(append([]string{"-nostdin", "-loglevel", "error"}, input...), "-y", out)
+	}},
+}
+
+// findRecorder returns the first installed audio recorder.
+func findRecorder() (audioRecorder, bool) {
+	for _, r := range audioRecorders {
+		if _, err := exec.LookPath(r.name); err == nil {
+			return r, true
+		}
+	}
+	return audioRecorder{}, false
+}
+
+// RecordAudio records into path until stop is closed. The recorder is
+// interrupted rather than killed, so it finishes writing the file.
+// Returns how long it recorded.
+func RecordAudio(rec audioRecorder, path string, stop <-chan struct{}) (time.Duration, error) {
+	cmd := exec.Command(rec.name, rec.args(path)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		// The recorder gave up on its own, e.g. no microphone
+		if err == nil {
+			err = fmt.Errorf("%s stopped", rec.name)
+		}
+		return 0, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	case <-stop:
+	}
+	elapsed := time.Since(start)
+	cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		cmd.Process.Kill()
+		<-done
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		return 0, fmt.Errorf("%s did not write %s", rec.name, path)
+	}
+	return elapsed, nil
+}
+
+// voiceMemoNote returns the text of the note linking a voice memo.
+func voiceMemoNote(link string, length time.Duration) string {
+	return fmt.Sprintf("🎙️ Ghi âm (%s)\n\n%s", length.Round(time.Second), attachmentLink(link))
+}
+
+// recordVoiceMemo records a voice memo into the attachments directory
+// until Enter is pressed and adds a note linking it.
+func recordVoiceMemo(reader *bufio.Reader) bool {
+	rec, ok := findRecorder()
+	if !ok {
+		notify(SeverityError, "Không tìm thấy công cụ ghi âm (arecord, sox hoặc ffmpeg)")
+		return false
+	}
+	path, link, err := app.newAttachment("memo-" + time.Now().Format("20060102-150405") + ".wav")
+	if err != nil {
+		notify(SeverityError, "Lỗi tạo file ghi âm: %v", err)
+		return false
+	}
+
+	ClearScreen()
+	fmt.Printf("%s🎙️ GHI ÂM%s\n", Bold+Cyan, Reset)
+	fmt.Println(Dim + strings.Repeat(glyphs.Rule, 60) + Reset)
+	fmt.Printf("\n%s● Đang ghi âm bằng %s...%s\n", Red, rec.name, Reset)
+	fmt.Printf("%s[Enter để dừng]%s", Dim, Reset)
+
+	stop := make(chan struct{})
+	go func() {
+		reader.ReadString('\n')
+		close(stop)
+	}()
+	length, err := RecordAudio(rec, path, stop)
+	if err != nil {
+		os.Remove(path)
+		fmt.Printf("\n%s❌ Lỗi ghi âm: %v%s\n", Red, err, Reset)
+		fmt.Printf("\n%s[Enter để quay lại]%s", Dim, Reset)
+		// The Enter is read by the goroutine above
+		<-stop
+		return false
+	}
+
+	if err := sealFile(path); err != nil {
+		notify(SeverityError, "Lỗi mã hóa file ghi âm: %v", err)
+	}
+	saveNote(voiceMemoNote(link, length))
+	return true
+}
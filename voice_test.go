@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAudio(t *testing.T) {
+	// Writes the file, then waits to be interrupted like arecord does
+	fakeTool(t, "arecord", "trap 'echo RIFF > \"$6\"; exit 0' INT\nwhile :; do :; done\n")
+	rec, ok := findRecorder()
+	if !ok || rec.name != "arecord" {
+		t.Fatalf("findRecorder = %q, %v", rec.name, ok)
+	}
+
+	out := filepath.Join(t.TempDir(), "memo.wav")
+	stop := make(chan struct{})
+	time.AfterFunc(100*time.Millisecond, func() { close(stop) })
+	length, err := RecordAudio(rec, out, stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if length < 100*time.Millisecond {
+		t.Errorf("length = %v", length)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "RIFF\n" {
+		t.Errorf("recording = %q", data)
+	}
+}
+
+func TestRecordAudioFailure(t *testing.T) {
+	fakeTool(t, "sox", "echo 'no default audio device' >&2\nexit 2\n")
+	rec, _ := findRecorder()
+
+	_, err := RecordAudio(rec, filepath.Join(t.TempDir(), "memo.wav"), make(chan struct{}))
+	if err == nil || !strings.Contains(err.Error(), "no default audio device") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestVoiceMemoNote(t *testing.T) {
+	app := createTestApp()
+	app.AddNote(voiceMemoNote("attachments/memo-1.wav", 83*time.Second+300*time.Millisecond))
+
+	notes := extractNotes(app.GetCurrentSection().Content())
+	if len(notes) != 1 {
+		t.Fatalf("Expected 1 note, got %q", notes)
+	}
+	if !strings.Contains(notes[0], "Ghi âm (1m23s)") {
+		t.Errorf("note = %q", notes[0])
+	}
+	if got := noteAttachments(notes[0]); len(got) != 1 || got[0] != "attachments/memo-1.wav" {
+		t.Errorf("noteAttachments = %q", got)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// whichKeyColumn is the width of one entry of the which-key popup.
+const whichKeyColumn = 32
+
+// whichKeyLines lays out the keys that can follow a prefix in columns
+// that fit width, filled row by row.
+func whichKeyLines(entries []chordEntry, width int) []string {
+	cols := max(1, width/whichKeyColumn)
+	var lines []string
+	for i := 0; i < len(entries); i += cols {
+		var row strings.Builder
+		for _, e := range entries[i:min(i+cols, len(entries))] {
+			cell := " " + Bold + Cyan + e.Key + Reset + BgBlack + White + " " + glyphs.Crumb + " " + e.Desc
+			row.WriteString(padVisible(cell, whichKeyColumn))
+		}
+		lines = append(lines, row.String())
+	}
+	return lines
+}
+
+// showWhichKey draws a popup over the bottom of the screen listing the
+// keys that can follow prefix and what they do. The last line is left
+// without a newline so the screen does not scroll.
+func showWhichKey(prefix string, entries []chordEntry) {
+	lines := whichKeyLines(entries, app.TermWidth)
+	row := max(1, app.TermHeight-len(lines))
+	fmt.Printf("\033[%d;1H", row)
+	fmt.Println(barLine(BgMagenta+White+Bold, fmt.Sprintf(" ⌨  %s …  (Esc: hủy)", prefix), app.TermWidth))
+	for i, line := range lines {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(barLine(BgBlack+White, line, app.TermWidth))
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChordContinuations(t *testing.T) {
+	bindings := []KeyBinding{
+		{Action: "first", Keys: []string{"g g"}, Desc: "First"},
+		{Action: "last", Keys: []string{"G", "g e"}, Desc: "Last"},
+		{Action: "deep", Keys: []string{"g x y"}, Desc: "Deep"},
+		{Action: "deeper", Keys: []string{"g x z"}, Desc: "Deeper"},
+		{Action: "skip", Keys: []string{"z z"}, Desc: "Skip"},
+	}
+	want := []chordEntry{{"g", "First"}, {"e", "Last"}, {"x", "+y/z …"}}
+	if got := chordContinuations(bindings, "g"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	want = []chordEntry{{"y", "Deep"}, {"z", "Deeper"}}
+	if got := chordContinuations(bindings, "g x"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := chordContinuations(bindings, "G"); got != nil {
+		t.Errorf("Expected no continuations of a complete key, got %v", got)
+	}
+}
+
+func TestDefaultChords(t *testing.T) {
+	if kb := findBinding(defaultKeyBindings(), "g g"); kb == nil || kb.Action != "goto_first" {
+		t.Errorf("Expected g g bound to goto_first, got %+v", kb)
+	}
+	if kb := findBinding(defaultKeyBindings(), "g f"); kb == nil || kb.Action != "links" {
+		t.Errorf("Expected g f bound to links, got %+v", kb)
+	}
+	if kb := findBinding(defaultKeyBindings(), "g"); kb != nil {
+		t.Errorf("Expected g to be a prefix only, got %s", kb.Action)
+	}
+	if len(chordContinuations(defaultKeyBindings(), "z")) == 0 {
+		t.Error("Expected z to start chords")
+	}
+}
+
+func TestChordPrefixConflict(t *testing.T) {
+	bindings, err := applyKeyOverrides(defaultKeyBindings(), map[string][]string{"quit": {"g"}})
+	if err != nil {
+		t.Fatalf("Expected a chord prefix to be bindable on its own, got %v", err)
+	}
+	if node := buildKeyTrie(bindings).find("g"); node == nil || node.binding == nil || node.binding.Action != "quit" || len(node.next) == 0 {
+		t.Errorf("Expected g to run quit and start chords, got %+v", node)
+	}
+	bindings, err = applyKeyOverrides(defaultKeyBindings(), map[string][]string{"goto": {parseKeySpec("gs")}})
+	if err != nil {
+		t.Fatalf("applyKeyOverrides failed: %v", err)
+	}
+	if kb := findBinding(bindings, "g s"); kb == nil || kb.Action != "goto" {
+		t.Errorf("Expected g s bound to goto, got %+v", kb)
+	}
+	if !keyTaken(bindings, "g") || !keyTaken(bindings, "g g x") || keyTaken(bindings, "w") {
+		t.Error("Unexpected keyTaken result")
+	}
+}
+
+func TestParseKeySpec(t *testing.T) {
+	for spec, want := range map[string]string{"j": "j", "Ctrl+p": "Ctrl+p", "Up": "Up", "gg": "g g", "zl": "z l", "PageUp": "PageUp", "F13": "F13", "Alt+x": "Alt+x"} {
+		if got := parseKeySpec(spec); got != want {
+			t.Errorf("parseKeySpec(%q) = %q, expected %q", spec, got, want)
+		}
+	}
+}
+
+func TestWhichKeyLines(t *testing.T) {
+	entries := []chordEntry{{"g", "First"}, {"e", "Last"}, {"j", "Jump"}}
+	lines := whichKeyLines(entries, 2*whichKeyColumn+5)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 rows of 2 columns, got %d", len(lines))
+	}
+	if first := stripANSI(lines[0]); !strings.Contains(first, "g "+glyphs.Crumb+" First") || !strings.Contains(first, "e "+glyphs.Crumb+" Last") {
+		t.Errorf("Unexpected first row %q", first)
+	}
+	if lines := whichKeyLines(entries, 10); len(lines) != 3 {
+		t.Errorf("Expected one column on a narrow screen, got %d rows", len(lines))
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ansiRegex matches ANSI escape sequences (colors and cursor control).
+var ansiRegex = regexp.MustCompile("\033\\[[0-9;]*[A-Za-z]")
+
+// ansiPrefixRegex matches an escape sequence at the start of a string.
+var ansiPrefixRegex = regexp.MustCompile("^\033\\[[0-9;]*[A-Za-z]")
+
+// ansiPrefixLen returns the length of the escape sequence s starts with,
+// 0 if none. It only runs the regex on an escape byte, so scanning a line
+// for sequences stays linear.
+func ansiPrefixLen(s string) int {
+	if s == "" || s[0] != '\033' {
+		return 0
+	}
+	if loc := ansiPrefixRegex.FindStringIndex(s); loc != nil {
+		return loc[1]
+	}
+	return 0
+}
+
+// stripANSI removes ANSI escape sequences from s.
+func stripANSI(s string) string {
+	return ansiRegex.ReplaceAllString(s, "")
+}
+
+// wideRanges lists the code points terminals draw two columns wide:
+// East Asian wide/fullwidth characters and emoji with default emoji
+// presentation. Sorted and non-overlapping.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC},
+	{0x23F0, 0x23F0}, {0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+	{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE},
+	{0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+	{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E},
+	{0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x1F004, 0x1F004},
+	{0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F251},
+	{0x1F300, 0x1F3FA}, {0x1F400, 0x1F64F}, {0x1F680, 0x1F6FF}, {0x1F7E0, 0x1F7EB},
+	{0x1F90C, 0x1F9FF}, {0x1FA70, 0x1FAFF}, {0x20000, 0x3FFFD},
+}
+
+// runeWidth returns the number of terminal columns r occupies: 0 for
+// control characters, combining marks (e.g. decomposed Vietnamese tone
+// marks), zero-width characters and emoji modifiers, 2 for wide
+// characters and emoji, 1 otherwise.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf), r >= 0x1F3FB && r <= 0x1F3FF:
+		return 0
+	}
+
+	i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i][1] >= r })
+	if i < len(wideRanges) && r >= wideRanges[i][0] {
+		return 2
+	}
+	return 1
+}
+
+// visibleWidth returns the number of terminal columns s occupies,
+// ignoring ANSI escape sequences.
+func visibleWidth(s string) int {
+	width := 0
+	for _, r := range stripANSI(s) {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// truncateVisible cuts s to at most width columns, keeping escape
+// sequences intact and resetting styles if anything was cut. A wide
+// character that would straddle the limit is dropped, and combining marks
+// stay with their base character.
+func truncateVisible(s string, width int) string {
+	if visibleWidth(s) <= width {
+		return s
+	}
+
+	var b strings.Builder
+	cols := 0
+	for i := 0; i < len(s); {
+		if n := ansiPrefixLen(s[i:]); n > 0 {
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := runeWidth(r)
+		if cols+w > width {
+			break
+		}
+		b.WriteString(s[i : i+size])
+		cols += w
+		i += size
+	}
+	b.WriteString(Reset)
+	return b.String()
+}
+
+// truncateEllipsis cuts s to at most width columns like truncateVisible,
+// ending with "…" when anything was cut.
+func truncateEllipsis(s string, width int) string {
+	if visibleWidth(s) <= width {
+		return s
+	}
+	return truncateVisible(s, max(0, width-1)) + "…"
+}
+
+// padVisible pads s with spaces to exactly width columns, truncating it
+// first if it is too long.
+func padVisible(s string, width int) string {
+	s = truncateVisible(s, width)
+	if w := visibleWidth(s); w < width {
+		s += strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// barLine renders text as a full-width bar in the given style, e.g. the
+// header and footer bars.
+func barLine(style, text string, width int) string {
+	return style + padVisible(text, width) + Reset
+}
+
+// wrapVisible word-wraps s into lines of at most width columns, breaking
+// at spaces where possible and inside words otherwise. Escape sequences
+// are kept where they occur, so styles carry over to the next line.
+func wrapVisible(s string, width int) []string {
+	if width <= 0 || visibleWidth(s) <= width {
+		return []string{s}
+	}
+
+	var lines []string
+	var line strings.Builder
+	cols := 0
+	breakAt, breakCols := -1, 0 // position after the last space in line
+
+	for i := 0; i < len(s); {
+		if n := ansiPrefixLen(s[i:]); n > 0 {
+			line.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := runeWidth(r)
+
+		if cols+w > width && cols > 0 {
+			current := line.String()
+			line.Reset()
+			if r == ' ' {
+				// Break at the space itself
+				lines = append(lines, current)
+				cols, breakAt = 0, -1
+				i += size
+				continue
+			}
+			if breakAt > 0 && strings.TrimSpace(stripANSI(current[:breakAt])) != "" {
+				lines = append(lines, strings.TrimRight(current[:breakAt], " "))
+				line.WriteString(current[breakAt:])
+				cols -= breakCols
+			} else {
+				lines = append(lines, current)
+				cols = 0
+			}
+			breakAt = -1
+		}
+
+		line.WriteString(s[i : i+size])
+		cols += w
+		i += size
+		if r == ' ' {
+			breakAt, breakCols = line.Len(), cols
+		}
+	}
+	return append(lines, line.String())
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ============================================================================
+// Width Helper Tests
+// ============================================================================
+
+func TestVisibleWidthIgnoresANSI(t *testing.T) {
+	s := Bold + Cyan + "Việt" + Reset
+
+	if w := visibleWidth(s); w != 4 {
+		t.Errorf("Expected width 4, got %d", w)
+	}
+}
+
+func TestTruncateVisible(t *testing.T) {
+	s := Green + "abcdef" + Reset
+
+	result := truncateVisible(s, 3)
+
+	if stripANSI(result) != "abc" {
+		t.Errorf("Expected 'abc', got '%s'", stripANSI(result))
+	}
+
+	if !strings.HasPrefix(result, Green) {
+		t.Error("Expected leading escape sequence to be preserved")
+	}
+}
+
+func TestPadVisible(t *testing.T) {
+	result := padVisible(Bold+"ab"+Reset, 5)
+
+	if visibleWidth(result) != 5 {
+		t.Errorf("Expected padded width 5, got %d", visibleWidth(result))
+	}
+}
+
+func TestRuneWidth(t *testing.T) {
+	tests := []struct {
+		r        rune
+		expected int
+	}{
+		{'a', 1},
+		{'ệ', 1},
+		{'́', 0}, // combining acute accent
+		{'漢', 2},
+		{'📖', 2},
+		{'✅', 2},
+		{'─', 1},
+		{'‍', 0}, // zero-width joiner
+		{'\t', 0},
+	}
+
+	for _, tt := range tests {
+		if got := runeWidth(tt.r); got != tt.expected {
+			t.Errorf("runeWidth(%q) = %d, expected %d", tt.r, got, tt.expected)
+		}
+	}
+}
+
+func TestVisibleWidthWide(t *testing.T) {
+	if w := visibleWidth(Bold + "📖 Kubernetes" + Reset); w != 13 {
+		t.Errorf("Expected width 13, got %d", w)
+	}
+
+	// Decomposed "ế" (e + circumflex + acute) is one column
+	if w := visibleWidth("Tiếng"); w != 5 {
+		t.Errorf("Expected width 5, got %d", w)
+	}
+}
+
+func TestTruncateVisibleMultibyte(t *testing.T) {
+	title := "Giám sát hệ thống phân tán với Prometheus"
+
+	result := stripANSI(truncateVisible(title, 10))
+	if result != "Giám sát h" {
+		t.Errorf("Expected 'Giám sát h', got %q", result)
+	}
+
+	// A wide character never straddles the limit
+	if w := visibleWidth(truncateVisible("ab漢字", 3)); w != 2 {
+		t.Errorf("Expected width 2, got %d", w)
+	}
+
+	// Combining marks stay with their base character
+	if result := stripANSI(truncateVisible("Tiếng", 3)); result != "Tiế" {
+		t.Errorf("Expected combining marks kept, got %q", result)
+	}
+}
+
+func TestTruncateEllipsis(t *testing.T) {
+	if got := truncateEllipsis("Ngắn", 10); got != "Ngắn" {
+		t.Errorf("Expected short title unchanged, got %q", got)
+	}
+
+	got := truncateEllipsis("Triển khai Kubernetes", 10)
+	if stripANSI(got) != "Triển kha…" || visibleWidth(got) != 10 {
+		t.Errorf("Expected 'Triển kha…', got %q", stripANSI(got))
+	}
+}
+
+func TestBarLine(t *testing.T) {
+	for _, text := range []string{" 📚 MỤC LỤC", strings.Repeat("✅", 50)} {
+		if w := visibleWidth(barLine(BgBlue, text, 40)); w != 40 {
+			t.Errorf("barLine(%q) width = %d, expected 40", text, w)
+		}
+	}
+}
+
+func TestWrapVisible(t *testing.T) {
+	lines := wrapVisible("một hai ba bốn năm sáu", 10)
+	expected := []string{"một hai ba", "bốn năm", "sáu"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, lines)
+	}
+
+	for _, line := range wrapVisible(Cyan+strings.Repeat("漢", 15)+Reset, 10) {
+		if w := visibleWidth(line); w > 10 {
+			t.Errorf("Line %q exceeds width: %d", line, w)
+		}
+	}
+
+	if lines := wrapVisible("short", 10); len(lines) != 1 || lines[0] != "short" {
+		t.Errorf("Expected short line unchanged, got %q", lines)
+	}
+}
+
+func TestAnsiPrefixLen(t *testing.T) {
+	tests := map[string]int{
+		Green + "abc": len(Green),
+		"abc" + Green: 0,
+		"\033abc":     0,
+		"":            0,
+	}
+	for s, want := range tests {
+		if got := ansiPrefixLen(s); got != want {
+			t.Errorf("ansiPrefixLen(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestTruncateVisibleLongLine(t *testing.T) {
+	// A long line with escapes far apart must not rescan the rest of the
+	// line at every rune
+	s := strings.Repeat(strings.Repeat("x", 1000)+Green, 200)
+
+	result := truncateVisible(s, 150000)
+
+	if visibleWidth(result) != 150000 {
+		t.Errorf("Expected width 150000, got %d", visibleWidth(result))
+	}
+	if lines := wrapVisible(s, 80); len(lines) != 2500 {
+		t.Errorf("Expected 2500 wrapped lines, got %d", len(lines))
+	}
+}